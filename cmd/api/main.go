@@ -61,7 +61,10 @@ func main() {
 	}
 
 	defer pool.Close()
-	router := srv.SetupRoutes()
+	router, err := srv.SetupRoutes()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to set up routes")
+	}
 
 	httpServer := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -79,6 +82,32 @@ func main() {
 		}
 	}()
 
+	// Release expired checkout reservations in the background
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go srv.RunReservationSweeper(sweeperCtx)
+
+	// Keep product embeddings for semantic search up to date
+	go srv.RunEmbeddingIndexer(sweeperCtx)
+
+	// Recompute "customers also bought" recommendations from order history
+	go srv.RunRecommendationRefresher(sweeperCtx)
+
+	// Retrain this replica's product category classifier as the catalog changes
+	go srv.RunCategoryClassifierTrainer(sweeperCtx)
+
+	// Reread the words "did you mean" corrects search terms to
+	go srv.RunSearchTermRefresher(sweeperCtx)
+
+	// Forecast product demand and reorder quantities from sales
+	go srv.RunDemandForecaster(sweeperCtx)
+
+	// Expire direct image uploads that were never completed
+	go srv.RunImageUploadSweeper(sweeperCtx)
+
+	// Delete stored files that no product image uses any more
+	go srv.RunStorageGC(sweeperCtx)
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -3,15 +3,20 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
 	"github.com/trenchesdeveloper/go-ai-store/internal/config"
+	"github.com/trenchesdeveloper/go-ai-store/internal/database"
 	"github.com/trenchesdeveloper/go-ai-store/internal/events"
 	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
 )
 
 func main() {
@@ -41,6 +46,22 @@ func main() {
 		Str("smtp_from", cfg.SMTP.From).
 		Msg("Email service configured")
 
+	// Back-in-stock subscriptions, queued stock alerts and review summaries
+	// live in the database
+	pool, err := database.InitDB(&cfg.Database)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	store := db.NewStore(pool)
+	backInStock := services.NewBackInStockService(
+		store,
+		cfg.Inventory.BackInStockBatchSize,
+		cfg.Inventory.BackInStockRetryDelay,
+		cfg.Inventory.BackInStockMaxAttempts,
+	)
+	// The notifier only refreshes summaries, so it has no events to publish
+	reviews := services.NewReviewService(store, nil, providers.NewLexiconSummarizer())
+
 	// Create context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -48,6 +69,7 @@ func main() {
 	subscriber, err := events.NewEventSubscriber(ctx, cfg)
 	if err != nil {
 		cancel()
+		pool.Close()
 		log.Fatal().Err(err).Msg("Failed to create event subscriber")
 	}
 
@@ -56,10 +78,12 @@ func main() {
 	if err != nil {
 		cancel()
 		_ = subscriber.Close()
+		pool.Close()
 		log.Fatal().Err(err).Msg("Failed to subscribe to events")
 	}
 
 	// Setup defer for cleanup (after all potential Fatal exits)
+	defer pool.Close()
 	defer cancel()
 	defer func() {
 		if err := subscriber.Close(); err != nil {
@@ -75,6 +99,77 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	// Stock alerts are queued in the database and mailed to catalog admins
+	// as a digest on a timer
+	stockAlerts := services.NewStockAlertDigest(store)
+	go func() {
+		interval := cfg.Inventory.AlertDigestInterval
+		if interval <= 0 {
+			interval = 15 * time.Minute
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sent, err := stockAlerts.Flush(ctx, func(alerts []notifications.Notification) error {
+					if len(cfg.Inventory.AlertEmails) == 0 {
+						log.Warn().
+							Int("alerts", len(alerts)).
+							Msg("No stock alert recipients configured, dropping digest")
+						return nil
+					}
+					return emailService.SendStockAlertDigest(cfg.Inventory.AlertEmails, alerts)
+				})
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to send stock alert digest")
+					continue
+				}
+				if sent > 0 && len(cfg.Inventory.AlertEmails) > 0 {
+					log.Info().Int("alerts", sent).Msg("Stock alert digest sent successfully")
+				}
+			}
+		}
+	}()
+
+	// Back-in-stock emails that failed are retried once their backoff is up
+	sendBackInStock := func(n notifications.Notification) error {
+		return emailService.SendBackInStockEmail(n.Email, n.Username, n.ProductName, n.SKU)
+	}
+	go func() {
+		interval := cfg.Inventory.BackInStockRetryDelay
+		if interval <= 0 {
+			interval = time.Minute
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sent, err := backInStock.RetryFailed(ctx, sendBackInStock)
+				if err != nil && !errors.Is(err, services.ErrBackInStockRetrying) {
+					log.Error().Err(err).Msg("Failed to retry back in stock emails")
+					continue
+				}
+				if err != nil {
+					log.Warn().Err(err).Int("sent", sent).Msg("Some back in stock email retries failed")
+					continue
+				}
+				if sent > 0 {
+					log.Info().Int("sent", sent).Msg("Back in stock email retries sent successfully")
+				}
+			}
+		}
+	}()
+
 	// Process messages
 	go func() {
 		for msg := range messages {
@@ -122,7 +217,7 @@ func main() {
 					Str("email", notification.Email).
 					Str("order_id", notification.OrderID).
 					Msg("Sending order confirmation email")
-				sendErr = emailService.SendOrderConfirmationEmail(notification.Email, notification.OrderID, notification.Total)
+				sendErr = emailService.SendOrderConfirmationEmail(notification.Email, notification.OrderID, notification.Total, notification.Currency)
 
 			case notifications.NotificationTypeLoginNotification:
 				log.Info().
@@ -152,6 +247,77 @@ func main() {
 					time.Now().Format(time.RFC3339),
 				)
 
+			case notifications.NotificationTypeStockLow, notifications.NotificationTypeStockOut:
+				log.Info().
+					Str("type", string(eventType)).
+					Str("sku", notification.SKU).
+					Int("stock", notification.Stock).
+					Msg("Queueing stock alert for digest")
+				notification.Type = eventType
+				if err := stockAlerts.Queue(ctx, notification); err != nil {
+					log.Error().
+						Err(err).
+						Str("message_id", msg.UUID).
+						Str("sku", notification.SKU).
+						Msg("Failed to queue stock alert")
+					msg.Nack()
+					continue
+				}
+				msg.Ack()
+				continue
+
+			case notifications.NotificationTypeBackInStock:
+				notification.Type = eventType
+				sent, err := backInStock.NotifySubscribers(ctx, notification, sendBackInStock)
+				if errors.Is(err, services.ErrBackInStockRetrying) {
+					// The failed emails are retried with backoff, so redelivering the event would only spin
+					log.Warn().
+						Err(err).
+						Str("message_id", msg.UUID).
+						Str("sku", notification.SKU).
+						Int("sent", sent).
+						Msg("Some back in stock emails failed and will be retried")
+					msg.Ack()
+					continue
+				}
+				if err != nil {
+					// Subscribers that were emailed are already fulfilled, so a redelivery only retries the rest
+					log.Error().
+						Err(err).
+						Str("message_id", msg.UUID).
+						Str("sku", notification.SKU).
+						Int("sent", sent).
+						Msg("Failed to send back in stock emails")
+					msg.Nack()
+					continue
+				}
+
+				log.Info().
+					Str("message_id", msg.UUID).
+					Str("sku", notification.SKU).
+					Int("sent", sent).
+					Msg("Back in stock emails sent successfully")
+				msg.Ack()
+				continue
+
+			case notifications.NotificationTypeReviewCreated:
+				if err := reviews.RefreshSummary(ctx, int32(notification.ProductID)); err != nil { //#nosec G115 -- product ID came from an int32 DB ID
+					log.Error().
+						Err(err).
+						Str("message_id", msg.UUID).
+						Int64("product_id", notification.ProductID).
+						Msg("Failed to refresh review summary")
+					msg.Nack()
+					continue
+				}
+
+				log.Info().
+					Str("message_id", msg.UUID).
+					Int64("product_id", notification.ProductID).
+					Msg("Review summary refreshed")
+				msg.Ack()
+				continue
+
 			default:
 				log.Warn().
 					Str("type", string(eventType)).
@@ -0,0 +1,7 @@
+-- Drop inventory movement ledger
+DROP TRIGGER IF EXISTS trg_inventory_movements_append_only ON inventory_movements;
+DROP FUNCTION IF EXISTS prevent_inventory_movement_mutation();
+DROP INDEX IF EXISTS idx_inventory_movements_reason;
+DROP INDEX IF EXISTS idx_inventory_movements_product_id;
+DROP TABLE IF EXISTS inventory_movements;
+DROP TYPE IF EXISTS inventory_movement_reason;
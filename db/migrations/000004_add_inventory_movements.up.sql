@@ -0,0 +1,35 @@
+-- Reasons a product's on-hand stock can change
+CREATE TYPE inventory_movement_reason AS ENUM ('order', 'cancellation', 'manual_adjustment', 'import', 'return');
+
+-- Append-only ledger of every stock change
+CREATE TABLE inventory_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    reason inventory_movement_reason NOT NULL,
+    reference_id VARCHAR(100),
+    actor_id INTEGER REFERENCES users(id),
+    quantity_change INTEGER NOT NULL CHECK (quantity_change <> 0),
+    stock_after INTEGER NOT NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_inventory_movements_product_id ON inventory_movements(product_id, created_at DESC);
+CREATE INDEX idx_inventory_movements_reason ON inventory_movements(reason);
+
+-- Reject updates and deletes so the ledger stays append-only
+CREATE FUNCTION prevent_inventory_movement_mutation() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'inventory_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_inventory_movements_append_only
+    BEFORE UPDATE OR DELETE ON inventory_movements
+    FOR EACH ROW EXECUTE FUNCTION prevent_inventory_movement_mutation();
+
+-- Seed the ledger with an opening balance for existing stock so it reconciles
+INSERT INTO inventory_movements (product_id, reason, quantity_change, stock_after, note)
+SELECT id, 'import', stock, stock, 'opening balance'
+FROM products
+WHERE stock IS NOT NULL AND stock <> 0;
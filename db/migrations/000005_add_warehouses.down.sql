@@ -0,0 +1,9 @@
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS warehouse_id;
+
+DROP INDEX IF EXISTS idx_order_item_allocations_order_item_id;
+DROP TABLE IF EXISTS order_item_allocations;
+
+DROP INDEX IF EXISTS idx_warehouse_stock_product_id;
+DROP TABLE IF EXISTS warehouse_stock;
+
+DROP TABLE IF EXISTS warehouses;
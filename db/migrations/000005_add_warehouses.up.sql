@@ -0,0 +1,52 @@
+-- Fulfilment locations; lower priority values are preferred
+CREATE TABLE warehouses (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    country VARCHAR(2) NOT NULL,
+    postal_code VARCHAR(20),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- On-hand stock per warehouse; products.stock is kept as the total across warehouses
+CREATE TABLE warehouse_stock (
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (warehouse_id, product_id)
+);
+
+CREATE INDEX idx_warehouse_stock_product_id ON warehouse_stock(product_id);
+
+-- Where each order line is fulfilled from; a line may be split across warehouses
+CREATE TABLE order_item_allocations (
+    id SERIAL PRIMARY KEY,
+    order_item_id INTEGER NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_item_allocations_order_item_id ON order_item_allocations(order_item_id);
+
+-- Ledger entries record which warehouse they touched (NULL for pre-warehouse history)
+ALTER TABLE inventory_movements ADD COLUMN warehouse_id INTEGER REFERENCES warehouses(id);
+
+-- Move existing stock and open orders into a default warehouse
+INSERT INTO warehouses (code, name, country, priority) VALUES ('MAIN', 'Main warehouse', 'US', 0);
+
+INSERT INTO warehouse_stock (warehouse_id, product_id, quantity)
+SELECT w.id, p.id, p.stock
+FROM products p CROSS JOIN warehouses w
+WHERE w.code = 'MAIN' AND p.stock IS NOT NULL AND p.stock > 0;
+
+INSERT INTO order_item_allocations (order_item_id, warehouse_id, quantity)
+SELECT oi.id, w.id, oi.quantity
+FROM order_items oi CROSS JOIN warehouses w
+WHERE w.code = 'MAIN' AND oi.deleted_at IS NULL;
@@ -0,0 +1,14 @@
+-- Short-lived holds on stock while a customer checks out.
+-- Available stock is products.stock minus the unexpired reservations for the product.
+CREATE TABLE stock_reservations (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, product_id)
+);
+
+CREATE INDEX idx_stock_reservations_product_id_expires_at ON stock_reservations(product_id, expires_at);
+CREATE INDEX idx_stock_reservations_expires_at ON stock_reservations(expires_at);
@@ -0,0 +1,10 @@
+-- Reorder thresholds set on a product or on a whole category.
+-- A product's own threshold wins over its category's.
+CREATE TABLE reorder_thresholds (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER UNIQUE REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER UNIQUE REFERENCES categories(id) ON DELETE CASCADE,
+    threshold INTEGER NOT NULL CHECK (threshold >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((product_id IS NULL) <> (category_id IS NULL))
+);
@@ -0,0 +1,18 @@
+-- Customers waiting to hear when an out-of-stock product returns.
+-- fulfilled_at is set once the customer has been emailed.
+CREATE TABLE back_in_stock_subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    fulfilled_at TIMESTAMPTZ
+);
+
+-- One open subscription per customer and product
+CREATE UNIQUE INDEX idx_back_in_stock_subscriptions_pending
+    ON back_in_stock_subscriptions (user_id, product_id)
+    WHERE fulfilled_at IS NULL;
+
+CREATE INDEX idx_back_in_stock_subscriptions_product
+    ON back_in_stock_subscriptions (product_id, id)
+    WHERE fulfilled_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_prices;
+DROP TYPE IF EXISTS product_price_kind;
@@ -0,0 +1,23 @@
+-- 'list' rows record every change to a product's regular price;
+-- 'sale' rows override it between starts_at and ends_at
+CREATE TYPE product_price_kind AS ENUM ('list', 'sale');
+
+-- Append-only price history. products.price stays the current list price.
+CREATE TABLE product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    kind product_price_kind NOT NULL,
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMPTZ,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at IS NULL OR ends_at >= starts_at), -- equal when a scheduled sale was cancelled
+    CHECK (kind = 'sale' OR ends_at IS NULL)
+);
+
+CREATE INDEX idx_product_prices_product_kind_starts_at ON product_prices(product_id, kind, starts_at DESC);
+
+-- Open the history with each product's current price
+INSERT INTO product_prices (product_id, kind, price, starts_at)
+SELECT id, 'list', price, COALESCE(created_at, CURRENT_TIMESTAMP) FROM products;
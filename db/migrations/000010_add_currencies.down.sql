@@ -0,0 +1,7 @@
+ALTER TABLE orders DROP COLUMN exchange_rate;
+ALTER TABLE orders DROP COLUMN currency;
+
+DROP INDEX IF EXISTS idx_product_prices_product_currency_kind_starts_at;
+DELETE FROM product_prices WHERE currency <> 'USD';
+ALTER TABLE product_prices DROP COLUMN currency;
+CREATE INDEX idx_product_prices_product_kind_starts_at ON product_prices(product_id, kind, starts_at DESC);
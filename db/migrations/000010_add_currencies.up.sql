@@ -0,0 +1,14 @@
+-- Prices so far were all implicitly USD. Rows in another currency form that
+-- currency's price list; products.price stays the base currency list price.
+ALTER TABLE product_prices ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE product_prices ALTER COLUMN currency DROP DEFAULT;
+
+DROP INDEX idx_product_prices_product_kind_starts_at;
+CREATE INDEX idx_product_prices_product_currency_kind_starts_at ON product_prices(product_id, currency, kind, starts_at DESC);
+
+-- The currency an order was placed in and the base-to-order exchange rate
+-- locked at checkout. total_amount and order_items.price are in this currency.
+ALTER TABLE orders ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE orders ADD COLUMN exchange_rate DECIMAL(18,8) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+ALTER TABLE orders ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE orders ALTER COLUMN exchange_rate DROP DEFAULT;
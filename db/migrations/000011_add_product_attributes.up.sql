@@ -0,0 +1,10 @@
+-- Free-form attributes such as color or size that search can facet and
+-- filter on. A product may have several values for one attribute.
+CREATE TABLE product_attributes (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (product_id, name, value)
+);
+
+CREATE INDEX idx_product_attributes_name_value ON product_attributes(name, value);
@@ -0,0 +1,3 @@
+-- Remove trigram index and extension
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
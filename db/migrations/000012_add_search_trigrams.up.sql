@@ -0,0 +1,5 @@
+-- Trigram matching for autocomplete suggestions and typo-tolerant search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Create GIN trigram index for substring and similarity matches on product names
+CREATE INDEX idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
@@ -0,0 +1,13 @@
+-- Vector embeddings of product text for semantic search. Vectors are
+-- normalized to unit length, so their dot product is their cosine similarity.
+-- content_hash fingerprints the text that was embedded, so a product is only
+-- embedded again when its text changes or the embedding model does.
+CREATE TABLE product_embeddings (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    model VARCHAR(100) NOT NULL,
+    embedding REAL[] NOT NULL,
+    content_hash CHAR(32) NOT NULL,
+    embedded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_embeddings_model ON product_embeddings(model);
@@ -0,0 +1,13 @@
+-- "Customers also bought" neighbours of each product, computed periodically
+-- from order history. score is the cosine similarity of the two products'
+-- sets of orders; co_purchases is how many orders contained both.
+CREATE TABLE product_recommendations (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    recommended_product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    score REAL NOT NULL,
+    co_purchases INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, recommended_product_id)
+);
+
+CREATE INDEX idx_product_recommendations_score ON product_recommendations(product_id, score DESC);
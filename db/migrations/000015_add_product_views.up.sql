@@ -0,0 +1,11 @@
+-- Products a signed-in customer looked at, one row per view. Views feed the
+-- category affinity of the personalized product feed.
+CREATE TABLE product_views (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_views_user_id ON product_views(user_id, viewed_at DESC);
+CREATE INDEX idx_product_views_product_id ON product_views(product_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS product_content_drafts;
+DROP TYPE IF EXISTS content_draft_status;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS seo_title;
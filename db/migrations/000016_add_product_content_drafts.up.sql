@@ -0,0 +1,23 @@
+ALTER TABLE products
+    ADD COLUMN seo_title VARCHAR(255),
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TYPE content_draft_status AS ENUM ('pending', 'accepted', 'rejected');
+
+-- Generated descriptions, SEO titles and tags waiting for an admin to review.
+-- A draft changes the product only once it is accepted.
+CREATE TABLE product_content_drafts (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    seo_title VARCHAR(255) NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    generator VARCHAR(50) NOT NULL,
+    status content_draft_status NOT NULL DEFAULT 'pending',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_content_drafts_product_id ON product_content_drafts(product_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_review_summaries;
+DROP TABLE IF EXISTS product_reviews;
@@ -0,0 +1,26 @@
+-- One review per user per product
+CREATE TABLE product_reviews (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, user_id)
+);
+
+CREATE INDEX idx_product_reviews_product_id ON product_reviews(product_id, created_at DESC);
+
+-- What a product's reviews add up to, rebuilt by the notifier whenever a
+-- review is written. Sentiment runs from -1 (negative) to 1 (positive).
+CREATE TABLE product_review_summaries (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    review_count INTEGER NOT NULL,
+    average_rating DOUBLE PRECISION NOT NULL,
+    sentiment DOUBLE PRECISION NOT NULL,
+    pros TEXT[] NOT NULL DEFAULT '{}',
+    cons TEXT[] NOT NULL DEFAULT '{}',
+    quotes TEXT[] NOT NULL DEFAULT '{}',
+    summarizer VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
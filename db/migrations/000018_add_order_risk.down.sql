@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_orders_user_id_created_at;
+DROP TABLE IF EXISTS failed_logins;
+DROP TABLE IF EXISTS order_risk_signals;
+DROP TABLE IF EXISTS order_risk_assessments;
+DROP TYPE IF EXISTS order_risk_decision;
+
+-- Enum values can't be dropped, so held orders go back to pending and the
+-- type is rebuilt without on_hold
+UPDATE orders SET status = 'pending' WHERE status = 'on_hold';
+ALTER TYPE order_status RENAME TO order_status_old;
+CREATE TYPE order_status AS ENUM ('pending', 'confirmed', 'shipped', 'delivered', 'cancelled');
+ALTER TABLE orders
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE order_status USING status::text::order_status,
+    ALTER COLUMN status SET DEFAULT 'pending';
+DROP TYPE order_status_old;
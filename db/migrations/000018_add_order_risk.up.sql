@@ -0,0 +1,37 @@
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'on_hold';
+
+CREATE TYPE order_risk_decision AS ENUM ('approved', 'rejected');
+
+-- The fraud risk score each order was placed with. Orders scoring at or above
+-- the hold score wait in on_hold until an admin approves or rejects them.
+CREATE TABLE order_risk_assessments (
+    order_id INTEGER PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    held BOOLEAN NOT NULL DEFAULT FALSE,
+    ip_address VARCHAR(45),
+    assessed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    decision order_risk_decision
+);
+
+CREATE INDEX idx_order_risk_assessments_ip_address ON order_risk_assessments(ip_address, assessed_at);
+
+-- The rules that fired for an order and what each added to its score
+CREATE TABLE order_risk_signals (
+    order_id INTEGER NOT NULL REFERENCES order_risk_assessments(order_id) ON DELETE CASCADE,
+    rule VARCHAR(50) NOT NULL,
+    weight DOUBLE PRECISION NOT NULL,
+    detail TEXT NOT NULL,
+    PRIMARY KEY (order_id, rule)
+);
+
+-- Wrong passwords given for an account, counted by the failed_logins rule
+CREATE TABLE failed_logins (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_failed_logins_user_id ON failed_logins(user_id, attempted_at);
+CREATE INDEX idx_orders_user_id_created_at ON orders(user_id, created_at);
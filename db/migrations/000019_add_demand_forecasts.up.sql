@@ -0,0 +1,20 @@
+-- Daily demand forecast of each product, recomputed periodically from the
+-- sales in order_items. days_of_cover is how long stock lasts at the
+-- forecast demand, null when nothing is expected to sell; a product whose
+-- stock falls to reorder_point should be restocked by reorder_quantity.
+CREATE TABLE product_demand_forecasts (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    stock INTEGER NOT NULL,
+    daily_demand DOUBLE PRECISION NOT NULL,
+    moving_average DOUBLE PRECISION NOT NULL,
+    forecast_error DOUBLE PRECISION NOT NULL,
+    days_of_cover DOUBLE PRECISION,
+    reorder_point INTEGER NOT NULL,
+    reorder_quantity INTEGER NOT NULL,
+    method VARCHAR(30) NOT NULL,
+    history_days INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_demand_forecasts_days_of_cover ON product_demand_forecasts(days_of_cover);
+CREATE INDEX idx_orders_created_at ON orders(created_at);
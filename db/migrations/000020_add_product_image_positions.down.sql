@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_product_images_product_position;
+DROP INDEX IF EXISTS idx_product_images_one_primary;
+
+ALTER TABLE product_images
+    DROP COLUMN IF EXISTS storage_path,
+    DROP COLUMN IF EXISTS position;
@@ -0,0 +1,36 @@
+-- Images are shown in position order, and storage_path is the key the file
+-- was uploaded under, so it can be deleted with the image. Images added by
+-- URL rather than uploaded have no storage_path.
+ALTER TABLE product_images
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN storage_path VARCHAR(500);
+
+-- Keep the order images were listed in before
+UPDATE product_images i
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY is_primary DESC, created_at, id) - 1 AS position
+    FROM product_images
+    WHERE deleted_at IS NULL
+) ordered
+WHERE i.id = ordered.id;
+
+-- Every upload used to be made primary; keep the latest
+UPDATE product_images i
+SET is_primary = false
+WHERE i.is_primary AND i.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM product_images newer
+    WHERE newer.product_id = i.product_id
+      AND newer.is_primary AND newer.deleted_at IS NULL
+      AND (newer.created_at, newer.id) > (i.created_at, i.id)
+  );
+
+-- Uploads were stored under products/<product id>/<file>, which ends both
+-- local and S3 URLs
+UPDATE product_images
+SET storage_path = substring(url from '(products/[0-9]+/[^/?#]+)$')
+WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX idx_product_images_one_primary ON product_images(product_id) WHERE is_primary AND deleted_at IS NULL;
+CREATE INDEX idx_product_images_product_position ON product_images(product_id, position) WHERE deleted_at IS NULL;
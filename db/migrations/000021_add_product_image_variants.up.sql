@@ -0,0 +1,13 @@
+-- Copies of an uploaded image: the original with its metadata stripped,
+-- and resized versions of it in the original format and as WebP. Each is
+-- stored under its own storage_path, which is deleted with the image.
+CREATE TABLE product_image_variants (
+    image_id INTEGER NOT NULL REFERENCES product_images(id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,   -- original, thumbnail, medium or large
+    format VARCHAR(10) NOT NULL, -- jpeg, png, gif or webp
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    storage_path VARCHAR(500) NOT NULL,
+    PRIMARY KEY (image_id, name, format)
+);
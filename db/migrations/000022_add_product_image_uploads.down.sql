@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_image_uploads;
+DROP TYPE IF EXISTS image_upload_status;
@@ -0,0 +1,23 @@
+CREATE TYPE image_upload_status AS ENUM ('pending', 'completed', 'failed', 'expired');
+
+-- Direct-to-storage image uploads. A session presigns a request for the
+-- client to store the raw file under storage_path; completing it checks
+-- and processes the file into a product image, then deletes the raw file.
+-- Sessions still pending well after expires_at are expired and their raw
+-- file deleted.
+CREATE TABLE product_image_uploads (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    storage_path VARCHAR(500) NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    max_bytes BIGINT NOT NULL,
+    alt_text VARCHAR(255),
+    status image_upload_status NOT NULL DEFAULT 'pending',
+    image_id INTEGER REFERENCES product_images(id) ON DELETE SET NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_image_uploads_pending ON product_image_uploads(expires_at) WHERE status = 'pending';
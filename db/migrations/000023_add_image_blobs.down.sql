@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_product_images_content_hash;
+ALTER TABLE product_images DROP COLUMN IF EXISTS content_hash;
+DROP TABLE IF EXISTS image_blobs;
@@ -0,0 +1,25 @@
+-- Uploaded images are stored under keys made from the SHA-256 of the file,
+-- so the same file uploaded twice is stored once and its images share the
+-- stored files. ref_count counts the images, not deleted, that use a
+-- content hash. Files no image uses are deleted by the storage garbage
+-- collector once they have been unused for its grace period.
+CREATE TABLE image_blobs (
+    content_hash VARCHAR(64) PRIMARY KEY, -- hex SHA-256 of the uploaded file
+    ref_count INTEGER NOT NULL DEFAULT 0 CHECK (ref_count >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_image_blobs_released ON image_blobs(updated_at) WHERE ref_count = 0;
+
+-- Images added by URL, or uploaded before content hashes, have none
+ALTER TABLE product_images
+    ADD COLUMN content_hash VARCHAR(64) REFERENCES image_blobs(content_hash) ON DELETE SET NULL;
+
+CREATE INDEX idx_product_images_content_hash ON product_images(content_hash) WHERE content_hash IS NOT NULL;
+
+-- Deleting a product used to leave its images, and so their files
+UPDATE product_images i
+SET deleted_at = p.deleted_at
+FROM products p
+WHERE p.id = i.product_id AND p.deleted_at IS NOT NULL AND i.deleted_at IS NULL;
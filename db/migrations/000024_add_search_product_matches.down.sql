@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS search_product_matches(TEXT, BOOLEAN, INTEGER[], NUMERIC, NUMERIC, BOOLEAN, TEXT[], TEXT[], TEXT, TEXT, NUMERIC);
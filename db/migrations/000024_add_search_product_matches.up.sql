@@ -0,0 +1,94 @@
+-- The products a catalog search matches, with what the search, count and
+-- facet queries need of each: its rank, what it sells for now in the quoted
+-- currency and its stock not held by reservations. It is the only place the
+-- search filters are written; a NULL or empty filter matches everything,
+-- and an empty query with fuzzy false matches every active product.
+--
+-- Prices follow the service's pricing: the newest running sale, else the
+-- newest list price, from the quoted currency's own price list where it has
+-- one and otherwise the base currency's converted at quote_rate and rounded
+-- to the cent. A product with no base list price history is at its
+-- products.price.
+CREATE FUNCTION search_product_matches(
+    search_query TEXT,
+    search_fuzzy BOOLEAN,
+    search_category_ids INTEGER[],
+    search_min_price NUMERIC,
+    search_max_price NUMERIC,
+    search_in_stock BOOLEAN,
+    search_attribute_names TEXT[],
+    search_attribute_values TEXT[],
+    base_currency TEXT,
+    quote_currency TEXT,
+    quote_rate NUMERIC
+) RETURNS TABLE (product_id INTEGER, rank REAL, price NUMERIC, available INTEGER)
+LANGUAGE sql STABLE AS $$
+SELECT m.id, m.rank, m.price, m.available
+FROM (
+  SELECT p.id,
+    CASE
+      WHEN search_fuzzy THEN word_similarity(search_query, p.name)
+      WHEN search_query = '' THEN 0
+      ELSE ts_rank(p.search_vector, plainto_tsquery('english', search_query))
+    END::real AS rank,
+    COALESCE(
+      quote_sale.price,
+      round(base_sale.price * quote_rate, 2),
+      quote_list.price,
+      round(COALESCE(base_list.price, p.price) * quote_rate, 2)
+    ) AS price,
+    (COALESCE(p.stock, 0) - COALESCE(held.quantity, 0))::int AS available
+  FROM products p
+  LEFT JOIN LATERAL (
+    SELECT pp.price FROM product_prices pp
+    WHERE pp.product_id = p.id AND pp.currency = base_currency AND pp.kind = 'list'
+      AND pp.starts_at <= CURRENT_TIMESTAMP
+    ORDER BY pp.starts_at DESC, pp.id DESC
+    LIMIT 1
+  ) base_list ON true
+  LEFT JOIN LATERAL (
+    SELECT pp.price FROM product_prices pp
+    WHERE pp.product_id = p.id AND pp.currency = base_currency AND pp.kind = 'sale'
+      AND pp.starts_at <= CURRENT_TIMESTAMP AND (pp.ends_at IS NULL OR pp.ends_at > CURRENT_TIMESTAMP)
+    ORDER BY pp.starts_at DESC, pp.id DESC
+    LIMIT 1
+  ) base_sale ON true
+  LEFT JOIN LATERAL (
+    SELECT pp.price FROM product_prices pp
+    WHERE pp.product_id = p.id AND pp.currency = quote_currency AND pp.kind = 'list'
+      AND pp.starts_at <= CURRENT_TIMESTAMP
+    ORDER BY pp.starts_at DESC, pp.id DESC
+    LIMIT 1
+  ) quote_list ON true
+  LEFT JOIN LATERAL (
+    SELECT pp.price FROM product_prices pp
+    WHERE pp.product_id = p.id AND pp.currency = quote_currency AND pp.kind = 'sale'
+      AND pp.starts_at <= CURRENT_TIMESTAMP AND (pp.ends_at IS NULL OR pp.ends_at > CURRENT_TIMESTAMP)
+    ORDER BY pp.starts_at DESC, pp.id DESC
+    LIMIT 1
+  ) quote_sale ON true
+  LEFT JOIN LATERAL (
+    SELECT SUM(r.quantity) AS quantity FROM stock_reservations r
+    WHERE r.product_id = p.id AND r.expires_at > CURRENT_TIMESTAMP
+  ) held ON true
+  WHERE CASE WHEN search_fuzzy
+      THEN word_similarity(search_query, p.name) >= 0.3
+      ELSE search_query = '' OR p.search_vector @@ plainto_tsquery('english', search_query)
+    END
+    AND p.is_active = true
+    AND p.deleted_at IS NULL
+    AND (cardinality(search_category_ids) = 0 OR p.category_id = ANY(search_category_ids))
+    AND NOT EXISTS (
+      SELECT 1 FROM unnest(search_attribute_names) AS f(name)
+      WHERE NOT EXISTS (
+        SELECT 1 FROM product_attributes pa
+        JOIN unnest(search_attribute_names, search_attribute_values) AS s(name, value)
+          ON s.name = pa.name AND s.value = pa.value
+        WHERE pa.product_id = p.id AND pa.name = f.name
+      )
+    )
+) m
+WHERE (search_min_price IS NULL OR m.price >= search_min_price)
+  AND (search_max_price IS NULL OR m.price <= search_max_price)
+  AND (search_in_stock IS NULL OR (m.available > 0) = search_in_stock)
+$$;
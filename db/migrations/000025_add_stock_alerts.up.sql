@@ -0,0 +1,12 @@
+-- Stock alerts waiting for the next digest to catalog admins. A newer alert
+-- for a product replaces the older one, so the digest shows the latest
+-- state, and the row is deleted once a digest including it has been sent.
+CREATE TABLE stock_alerts (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('stock_low', 'stock_out')),
+    sku VARCHAR(100) NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    stock INTEGER NOT NULL,
+    threshold INTEGER NOT NULL,
+    queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp()
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_back_in_stock_subscriptions_retry;
+ALTER TABLE back_in_stock_subscriptions
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts;
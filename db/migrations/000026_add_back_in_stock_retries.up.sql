@@ -0,0 +1,10 @@
+-- A back-in-stock email that fails is retried on its own, with backoff,
+-- instead of redelivering the whole event. attempts counts the failed
+-- sends and next_attempt_at is when the next one is due.
+ALTER TABLE back_in_stock_subscriptions
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMPTZ;
+
+CREATE INDEX idx_back_in_stock_subscriptions_retry
+    ON back_in_stock_subscriptions (next_attempt_at)
+    WHERE fulfilled_at IS NULL AND next_attempt_at IS NOT NULL;
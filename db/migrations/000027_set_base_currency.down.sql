@@ -0,0 +1,3 @@
+-- Rows moved to the base currency can't be told from those recorded in it
+-- since, so they stay in it
+SELECT 1;
@@ -0,0 +1,28 @@
+-- Migration 000010 recorded the prices and orders from before currencies in
+-- USD. A store with another base currency (CURRENCY_BASE) passes it as the
+-- app.currency_base setting when migrating, and those rows move to it:
+--   ALTER DATABASE ecommerce SET app.currency_base = 'EUR';
+-- Without the setting the base currency is USD and nothing changes.
+DO $$
+DECLARE
+    base TEXT := upper(COALESCE(NULLIF(current_setting('app.currency_base', true), ''), 'USD'));
+BEGIN
+    IF base !~ '^[A-Z]{3}$' THEN
+        RAISE EXCEPTION 'app.currency_base must be a three-letter currency code, not %', base;
+    END IF;
+    IF base = 'USD' THEN
+        RETURN;
+    END IF;
+
+    -- An order in USD placed since has a USD exchange rate, never exactly 1
+    UPDATE orders SET currency = base WHERE currency = 'USD' AND exchange_rate = 1;
+
+    -- Prices from before currencies were recorded before any in another one
+    UPDATE product_prices SET currency = base
+    WHERE currency = 'USD'
+      AND created_at < COALESCE(
+          (SELECT MIN(created_at) FROM product_prices WHERE currency <> 'USD'),
+          'infinity'
+      );
+END
+$$;
@@ -0,0 +1,4 @@
+-- Postgres can't drop an enum value, so uploads being processed go back to
+-- pending and 'processing' is left unused
+UPDATE product_image_uploads SET status = 'pending' WHERE status = 'processing';
+ALTER TABLE product_image_uploads DROP COLUMN claimed_at;
@@ -0,0 +1,5 @@
+-- Completing an upload claims it as processing, so only one request
+-- processes it, and marks it completed once its image is added. A claim left
+-- by a request that crashed can be taken again after a timeout.
+ALTER TYPE image_upload_status ADD VALUE IF NOT EXISTS 'processing' AFTER 'pending';
+ALTER TABLE product_image_uploads ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE;
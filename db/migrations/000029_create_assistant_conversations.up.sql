@@ -0,0 +1,12 @@
+-- Each user's conversation with the shopping assistant, so any replica can
+-- answer its next message. busy_until is set while a message is being
+-- answered and keeps a second one out until then, so a replica that dies
+-- mid-answer doesn't hold the conversation for good.
+CREATE TABLE assistant_conversations (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    messages JSONB NOT NULL DEFAULT '[]',
+    busy_until TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_assistant_conversations_updated_at ON assistant_conversations(updated_at);
@@ -16,10 +16,14 @@ type MockStore struct {
 // Ensure MockStore implements db.Store
 var _ db.Store = (*MockStore)(nil)
 
-// ExecTx mocks the transaction execution
-func (m *MockStore) ExecTx(ctx context.Context, fn func(*db.Queries) error) error {
+// ExecTx records the transaction and, unless it is mocked to fail, runs fn
+// against the mock, so the queries made inside it are checked too
+func (m *MockStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
 	args := m.Called(ctx, fn)
-	return args.Error(0)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
 }
 
 // User methods
@@ -354,19 +358,19 @@ func (m *MockStore) UpdateProductImage(ctx context.Context, arg db.UpdateProduct
 	return args.Get(0).(db.ProductImage), args.Error(1)
 }
 
-func (m *MockStore) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) error {
+func (m *MockStore) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) (int64, error) {
 	args := m.Called(ctx, arg)
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockStore) SoftDeleteProductImage(ctx context.Context, id int32) error {
+func (m *MockStore) SoftDeleteProductImage(ctx context.Context, id int32) ([]string, error) {
 	args := m.Called(ctx, id)
-	return args.Error(0)
+	return args.Get(0).([]string), args.Error(1)
 }
 
-func (m *MockStore) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) error {
+func (m *MockStore) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) ([]string, error) {
 	args := m.Called(ctx, productID)
-	return args.Error(0)
+	return args.Get(0).([]string), args.Error(1)
 }
 
 // Order methods
@@ -380,6 +384,16 @@ func (m *MockStore) GetOrderByID(ctx context.Context, id int32) (db.Order, error
 	return args.Get(0).(db.Order), args.Error(1)
 }
 
+func (m *MockStore) GetOrderByIDForUpdate(ctx context.Context, id int32) (db.Order, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Order), args.Error(1)
+}
+
+func (m *MockStore) GetOrdersByIDs(ctx context.Context, ids []int32) ([]db.Order, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]db.Order), args.Error(1)
+}
+
 func (m *MockStore) ListOrders(ctx context.Context, arg db.ListOrdersParams) ([]db.Order, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).([]db.Order), args.Error(1)
@@ -446,6 +460,11 @@ func (m *MockStore) ListOrderItems(ctx context.Context, orderID int32) ([]db.Ord
 	return args.Get(0).([]db.OrderItem), args.Error(1)
 }
 
+func (m *MockStore) ListOrderItemsByOrderIDs(ctx context.Context, orderIds []int32) ([]db.OrderItem, error) {
+	args := m.Called(ctx, orderIds)
+	return args.Get(0).([]db.OrderItem), args.Error(1)
+}
+
 func (m *MockStore) SoftDeleteOrderItem(ctx context.Context, id int32) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -476,3 +495,591 @@ func (m *MockStore) UpdateIdempotencyKeyOrderID(ctx context.Context, arg db.Upda
 	args := m.Called(ctx, arg)
 	return args.Error(0)
 }
+
+// Inventory ledger methods
+func (m *MockStore) AdjustProductStock(ctx context.Context, arg db.AdjustProductStockParams) (db.Product, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Product), args.Error(1)
+}
+
+func (m *MockStore) CreateInventoryMovement(ctx context.Context, arg db.CreateInventoryMovementParams) (db.InventoryMovement, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.InventoryMovement), args.Error(1)
+}
+
+func (m *MockStore) ListInventoryMovementsByProduct(ctx context.Context, arg db.ListInventoryMovementsByProductParams) ([]db.InventoryMovement, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.InventoryMovement), args.Error(1)
+}
+
+func (m *MockStore) CountInventoryMovementsByProduct(ctx context.Context, productID int32) (int64, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) ListStockDiscrepancies(ctx context.Context) ([]db.ListStockDiscrepanciesRow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.ListStockDiscrepanciesRow), args.Error(1)
+}
+
+// Warehouse methods
+func (m *MockStore) AdjustWarehouseStock(ctx context.Context, arg db.AdjustWarehouseStockParams) (db.WarehouseStock, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.WarehouseStock), args.Error(1)
+}
+
+func (m *MockStore) CreateOrderItemAllocation(ctx context.Context, arg db.CreateOrderItemAllocationParams) (db.OrderItemAllocation, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OrderItemAllocation), args.Error(1)
+}
+
+func (m *MockStore) CreateWarehouse(ctx context.Context, arg db.CreateWarehouseParams) (db.Warehouse, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Warehouse), args.Error(1)
+}
+
+func (m *MockStore) GetDefaultWarehouse(ctx context.Context) (db.Warehouse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(db.Warehouse), args.Error(1)
+}
+
+func (m *MockStore) GetWarehouseByCode(ctx context.Context, code string) (db.Warehouse, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(db.Warehouse), args.Error(1)
+}
+
+func (m *MockStore) GetWarehouseStockForUpdate(ctx context.Context, arg db.GetWarehouseStockForUpdateParams) (db.WarehouseStock, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.WarehouseStock), args.Error(1)
+}
+
+func (m *MockStore) ListAllocatableStockForUpdate(ctx context.Context, dollar_1 []int32) ([]db.ListAllocatableStockForUpdateRow, error) {
+	args := m.Called(ctx, dollar_1)
+	return args.Get(0).([]db.ListAllocatableStockForUpdateRow), args.Error(1)
+}
+
+func (m *MockStore) ListOrderItemAllocationsByOrderID(ctx context.Context, orderID int32) ([]db.ListOrderItemAllocationsByOrderIDRow, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).([]db.ListOrderItemAllocationsByOrderIDRow), args.Error(1)
+}
+
+func (m *MockStore) ListWarehouseStockByProduct(ctx context.Context, productID int32) ([]db.ListWarehouseStockByProductRow, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]db.ListWarehouseStockByProductRow), args.Error(1)
+}
+
+func (m *MockStore) ListWarehouses(ctx context.Context) ([]db.Warehouse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.Warehouse), args.Error(1)
+}
+
+// Stock reservation methods
+func (m *MockStore) DeleteExpiredStockReservations(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) DeleteStockReservationsByUser(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetReservedStockByProductIDs(ctx context.Context, arg db.GetReservedStockByProductIDsParams) ([]db.GetReservedStockByProductIDsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.GetReservedStockByProductIDsRow), args.Error(1)
+}
+
+func (m *MockStore) ListActiveStockReservationsByUser(ctx context.Context, userID int32) ([]db.StockReservation, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.StockReservation), args.Error(1)
+}
+
+func (m *MockStore) UpsertStockReservation(ctx context.Context, arg db.UpsertStockReservationParams) (db.StockReservation, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.StockReservation), args.Error(1)
+}
+
+// Reorder threshold methods
+func (m *MockStore) DeleteCategoryReorderThreshold(ctx context.Context, categoryID pgtype.Int4) error {
+	args := m.Called(ctx, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteProductReorderThreshold(ctx context.Context, productID pgtype.Int4) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetReorderThresholdsByProductIDs(ctx context.Context, arg db.GetReorderThresholdsByProductIDsParams) ([]db.GetReorderThresholdsByProductIDsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.GetReorderThresholdsByProductIDsRow), args.Error(1)
+}
+
+func (m *MockStore) ListProductsBelowThreshold(ctx context.Context, defaultThreshold int32) ([]db.ListProductsBelowThresholdRow, error) {
+	args := m.Called(ctx, defaultThreshold)
+	return args.Get(0).([]db.ListProductsBelowThresholdRow), args.Error(1)
+}
+
+func (m *MockStore) UpsertCategoryReorderThreshold(ctx context.Context, arg db.UpsertCategoryReorderThresholdParams) (db.ReorderThreshold, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ReorderThreshold), args.Error(1)
+}
+
+func (m *MockStore) UpsertProductReorderThreshold(ctx context.Context, arg db.UpsertProductReorderThresholdParams) (db.ReorderThreshold, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ReorderThreshold), args.Error(1)
+}
+
+// Back-in-stock subscription methods
+func (m *MockStore) ClaimBackInStockSubscribers(ctx context.Context, arg db.ClaimBackInStockSubscribersParams) ([]db.ClaimBackInStockSubscribersRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ClaimBackInStockSubscribersRow), args.Error(1)
+}
+
+func (m *MockStore) CreateBackInStockSubscription(ctx context.Context, arg db.CreateBackInStockSubscriptionParams) (db.BackInStockSubscription, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.BackInStockSubscription), args.Error(1)
+}
+
+func (m *MockStore) DeletePendingBackInStockSubscription(ctx context.Context, arg db.DeletePendingBackInStockSubscriptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) GetPendingBackInStockSubscription(ctx context.Context, arg db.GetPendingBackInStockSubscriptionParams) (db.BackInStockSubscription, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.BackInStockSubscription), args.Error(1)
+}
+
+func (m *MockStore) ListBackInStockRetryProducts(ctx context.Context, maxAttempts int32) ([]db.ListBackInStockRetryProductsRow, error) {
+	args := m.Called(ctx, maxAttempts)
+	return args.Get(0).([]db.ListBackInStockRetryProductsRow), args.Error(1)
+}
+
+func (m *MockStore) MarkBackInStockSubscriptionsFulfilled(ctx context.Context, ids []int32) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockStore) RecordBackInStockFailures(ctx context.Context, arg db.RecordBackInStockFailuresParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// Stock alert digest methods
+func (m *MockStore) DeleteSentStockAlerts(ctx context.Context, arg db.DeleteSentStockAlertsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ListStockAlerts(ctx context.Context) ([]db.StockAlert, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.StockAlert), args.Error(1)
+}
+
+func (m *MockStore) QueueStockAlert(ctx context.Context, arg db.QueueStockAlertParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// Product price methods
+func (m *MockStore) CreateProductPrice(ctx context.Context, arg db.CreateProductPriceParams) (db.ProductPrice, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductPrice), args.Error(1)
+}
+
+func (m *MockStore) EndProductSalePrice(ctx context.Context, arg db.EndProductSalePriceParams) (db.ProductPrice, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductPrice), args.Error(1)
+}
+
+func (m *MockStore) GetEffectivePrices(ctx context.Context, arg db.GetEffectivePricesParams) ([]db.GetEffectivePricesRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.GetEffectivePricesRow), args.Error(1)
+}
+
+func (m *MockStore) ListProductPricesByProduct(ctx context.Context, productID int32) ([]db.ProductPrice, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]db.ProductPrice), args.Error(1)
+}
+
+// Product attribute and search facet methods
+func (m *MockStore) AddProductAttributes(ctx context.Context, arg db.AddProductAttributesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteProductAttributes(ctx context.Context, productID int32) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func (m *MockStore) ListProductAttributesByProductIDs(ctx context.Context, productIds []int32) ([]db.ProductAttribute, error) {
+	args := m.Called(ctx, productIds)
+	return args.Get(0).([]db.ProductAttribute), args.Error(1)
+}
+
+func (m *MockStore) SearchAttributeFacets(ctx context.Context, arg db.SearchAttributeFacetsParams) ([]db.SearchAttributeFacetsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.SearchAttributeFacetsRow), args.Error(1)
+}
+
+func (m *MockStore) SearchCategoryFacets(ctx context.Context, arg db.SearchCategoryFacetsParams) ([]db.SearchCategoryFacetsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.SearchCategoryFacetsRow), args.Error(1)
+}
+
+func (m *MockStore) SearchPriceFacets(ctx context.Context, arg db.SearchPriceFacetsParams) ([]db.SearchPriceFacetsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.SearchPriceFacetsRow), args.Error(1)
+}
+
+func (m *MockStore) SearchStockFacets(ctx context.Context, arg db.SearchStockFacetsParams) (db.SearchStockFacetsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.SearchStockFacetsRow), args.Error(1)
+}
+
+// Search suggestion methods
+func (m *MockStore) ListSearchTermWords(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStore) SuggestProducts(ctx context.Context, arg db.SuggestProductsParams) ([]db.SuggestProductsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.SuggestProductsRow), args.Error(1)
+}
+
+// Product embedding methods
+func (m *MockStore) CountSemanticSearchProducts(ctx context.Context, arg db.CountSemanticSearchProductsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) ListProductsNeedingEmbedding(ctx context.Context, arg db.ListProductsNeedingEmbeddingParams) ([]db.ListProductsNeedingEmbeddingRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListProductsNeedingEmbeddingRow), args.Error(1)
+}
+
+func (m *MockStore) SemanticSearchProducts(ctx context.Context, arg db.SemanticSearchProductsParams) ([]db.SemanticSearchProductsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.SemanticSearchProductsRow), args.Error(1)
+}
+
+func (m *MockStore) UpsertProductEmbedding(ctx context.Context, arg db.UpsertProductEmbeddingParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// Search vocabulary methods
+func (m *MockStore) ListAttributeValues(ctx context.Context) ([]db.ListAttributeValuesRow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.ListAttributeValuesRow), args.Error(1)
+}
+
+func (m *MockStore) ListCategoryNames(ctx context.Context) ([]db.ListCategoryNamesRow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.ListCategoryNamesRow), args.Error(1)
+}
+
+// Product recommendation methods
+func (m *MockStore) DeleteProductRecommendations(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockStore) InsertProductRecommendations(ctx context.Context, arg db.InsertProductRecommendationsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) ListCartRecommendations(ctx context.Context, arg db.ListCartRecommendationsParams) ([]db.ListCartRecommendationsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListCartRecommendationsRow), args.Error(1)
+}
+
+func (m *MockStore) ListProductRecommendations(ctx context.Context, arg db.ListProductRecommendationsParams) ([]db.ListProductRecommendationsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListProductRecommendationsRow), args.Error(1)
+}
+
+// Product views
+
+func (m *MockStore) CreateProductView(ctx context.Context, arg db.CreateProductViewParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ListFeedCandidates(ctx context.Context, arg db.ListFeedCandidatesParams) ([]db.ListFeedCandidatesRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListFeedCandidatesRow), args.Error(1)
+}
+
+func (m *MockStore) ListUserCategoryActivity(ctx context.Context, arg db.ListUserCategoryActivityParams) ([]db.ListUserCategoryActivityRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListUserCategoryActivityRow), args.Error(1)
+}
+
+func (m *MockStore) ListUserOwnedProductIDs(ctx context.Context, userID int32) ([]int32, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]int32), args.Error(1)
+}
+
+// Product content drafts
+
+func (m *MockStore) CreateProductContentDraft(ctx context.Context, arg db.CreateProductContentDraftParams) (db.ProductContentDraft, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductContentDraft), args.Error(1)
+}
+
+func (m *MockStore) GetProductContentDraft(ctx context.Context, id int32) (db.ProductContentDraft, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.ProductContentDraft), args.Error(1)
+}
+
+func (m *MockStore) ListProductContentDrafts(ctx context.Context, productID int32) ([]db.ProductContentDraft, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]db.ProductContentDraft), args.Error(1)
+}
+
+func (m *MockStore) ReviewProductContentDraft(ctx context.Context, arg db.ReviewProductContentDraftParams) (db.ProductContentDraft, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductContentDraft), args.Error(1)
+}
+
+func (m *MockStore) UpdateProductContent(ctx context.Context, arg db.UpdateProductContentParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// Categorization
+
+func (m *MockStore) ListCategoryTrainingProducts(ctx context.Context) ([]db.ListCategoryTrainingProductsRow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.ListCategoryTrainingProductsRow), args.Error(1)
+}
+
+// Product reviews
+
+func (m *MockStore) CountProductReviews(ctx context.Context, productID int32) (int64, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) CreateProductReview(ctx context.Context, arg db.CreateProductReviewParams) (db.ProductReview, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductReview), args.Error(1)
+}
+
+func (m *MockStore) GetProductReviewStats(ctx context.Context, productID int32) (db.GetProductReviewStatsRow, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(db.GetProductReviewStatsRow), args.Error(1)
+}
+
+func (m *MockStore) GetProductReviewSummary(ctx context.Context, productID int32) (db.ProductReviewSummary, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(db.ProductReviewSummary), args.Error(1)
+}
+
+func (m *MockStore) ListProductReviewSummariesByProductIDs(ctx context.Context, productIds []int32) ([]db.ProductReviewSummary, error) {
+	args := m.Called(ctx, productIds)
+	return args.Get(0).([]db.ProductReviewSummary), args.Error(1)
+}
+
+func (m *MockStore) ListProductReviews(ctx context.Context, arg db.ListProductReviewsParams) ([]db.ProductReview, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ProductReview), args.Error(1)
+}
+
+func (m *MockStore) UpsertProductReviewSummary(ctx context.Context, arg db.UpsertProductReviewSummaryParams) (db.ProductReviewSummary, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductReviewSummary), args.Error(1)
+}
+
+// Order risk
+
+func (m *MockStore) AddOrderRiskSignals(ctx context.Context, arg db.AddOrderRiskSignalsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) CountRecentFailedLogins(ctx context.Context, arg db.CountRecentFailedLoginsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) CountRecentOrdersByIP(ctx context.Context, arg db.CountRecentOrdersByIPParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) CountRecentOrdersByUser(ctx context.Context, arg db.CountRecentOrdersByUserParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) CreateOrderRiskAssessment(ctx context.Context, arg db.CreateOrderRiskAssessmentParams) (db.OrderRiskAssessment, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OrderRiskAssessment), args.Error(1)
+}
+
+func (m *MockStore) GetOrderRiskAssessment(ctx context.Context, orderID int32) (db.OrderRiskAssessment, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).(db.OrderRiskAssessment), args.Error(1)
+}
+
+func (m *MockStore) ListHeldOrderRiskAssessments(ctx context.Context, arg db.ListHeldOrderRiskAssessmentsParams) ([]db.OrderRiskAssessment, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.OrderRiskAssessment), args.Error(1)
+}
+
+func (m *MockStore) ListOrderRiskSignalsByOrderIDs(ctx context.Context, orderIds []int32) ([]db.OrderRiskSignal, error) {
+	args := m.Called(ctx, orderIds)
+	return args.Get(0).([]db.OrderRiskSignal), args.Error(1)
+}
+
+func (m *MockStore) RecordFailedLogin(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockStore) RecordOrderRiskReview(ctx context.Context, arg db.RecordOrderRiskReviewParams) (db.OrderRiskAssessment, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OrderRiskAssessment), args.Error(1)
+}
+
+// Demand forecasts
+
+func (m *MockStore) CountDemandForecasts(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) DeleteDemandForecasts(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockStore) InsertDemandForecasts(ctx context.Context, arg db.InsertDemandForecastsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) ListDailyProductSales(ctx context.Context, arg db.ListDailyProductSalesParams) ([]db.ListDailyProductSalesRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListDailyProductSalesRow), args.Error(1)
+}
+
+func (m *MockStore) ListDemandForecasts(ctx context.Context, arg db.ListDemandForecastsParams) ([]db.ListDemandForecastsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListDemandForecastsRow), args.Error(1)
+}
+
+func (m *MockStore) ListForecastProducts(ctx context.Context) ([]db.ListForecastProductsRow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.ListForecastProductsRow), args.Error(1)
+}
+
+// Product images
+
+func (m *MockStore) ClearPrimaryProductImage(ctx context.Context, productID int32) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func (m *MockStore) SetProductImagePositions(ctx context.Context, arg db.SetProductImagePositionsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) UpdateProductImageAltText(ctx context.Context, arg db.UpdateProductImageAltTextParams) (db.ProductImage, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductImage), args.Error(1)
+}
+
+func (m *MockStore) ListProductImageVariantsByImageIDs(ctx context.Context, imageIDs []int32) ([]db.ProductImageVariant, error) {
+	args := m.Called(ctx, imageIDs)
+	return args.Get(0).([]db.ProductImageVariant), args.Error(1)
+}
+
+func (m *MockStore) ClaimProductImageUpload(ctx context.Context, arg db.ClaimProductImageUploadParams) (db.ProductImageUpload, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductImageUpload), args.Error(1)
+}
+
+func (m *MockStore) CompleteProductImageUpload(ctx context.Context, arg db.CompleteProductImageUploadParams) (db.ProductImageUpload, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductImageUpload), args.Error(1)
+}
+
+func (m *MockStore) CreateProductImageUpload(ctx context.Context, arg db.CreateProductImageUploadParams) (db.ProductImageUpload, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ProductImageUpload), args.Error(1)
+}
+
+func (m *MockStore) ExpireProductImageUploads(ctx context.Context, arg db.ExpireProductImageUploadsParams) ([]db.ProductImageUpload, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ProductImageUpload), args.Error(1)
+}
+
+func (m *MockStore) FailProductImageUpload(ctx context.Context, arg db.FailProductImageUploadParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetProductImageUpload(ctx context.Context, id int32) (db.ProductImageUpload, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.ProductImageUpload), args.Error(1)
+}
+
+func (m *MockStore) ReleaseProductImageUpload(ctx context.Context, arg db.ReleaseProductImageUploadParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteReleasedImageBlobs(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) GetImageBlobForUpdate(ctx context.Context, contentHash string) (db.ImageBlob, error) {
+	args := m.Called(ctx, contentHash)
+	return args.Get(0).(db.ImageBlob), args.Error(1)
+}
+
+func (m *MockStore) ListImageBlobVariants(ctx context.Context, contentHash string) ([]db.ProductImageVariant, error) {
+	args := m.Called(ctx, contentHash)
+	return args.Get(0).([]db.ProductImageVariant), args.Error(1)
+}
+
+func (m *MockStore) ListReferencedStoragePaths(ctx context.Context, cutoff pgtype.Timestamptz) ([]string, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// Assistant conversation methods
+func (m *MockStore) DeleteQuietAssistantConversations(ctx context.Context, arg db.DeleteQuietAssistantConversationsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ClaimAssistantConversation(ctx context.Context, arg db.ClaimAssistantConversationParams) (db.AssistantConversation, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.AssistantConversation), args.Error(1)
+}
+
+func (m *MockStore) SaveAssistantConversation(ctx context.Context, arg db.SaveAssistantConversationParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ReleaseAssistantConversation(ctx context.Context, arg db.ReleaseAssistantConversationParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// Job lock methods
+func (m *MockStore) TryJobLock(ctx context.Context, name string) (bool, error) {
+	args := m.Called(ctx, name)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,29 @@
+-- name: DeleteQuietAssistantConversations :exec
+-- Deletes conversations not written to since quiet_before, unless a message
+-- of theirs is being answered
+DELETE FROM assistant_conversations
+WHERE updated_at < sqlc.arg('quiet_before')
+  AND (busy_until IS NULL OR busy_until < sqlc.arg('now'));
+
+-- name: ClaimAssistantConversation :one
+-- Starts answering a message of the user's conversation, creating it if
+-- needed. Nothing is returned while another message of it is being answered
+-- and that claim hasn't run out.
+INSERT INTO assistant_conversations (user_id, busy_until, updated_at)
+VALUES ($1, $2, sqlc.arg('now'))
+ON CONFLICT (user_id) DO UPDATE
+SET busy_until = EXCLUDED.busy_until
+WHERE assistant_conversations.busy_until IS NULL OR assistant_conversations.busy_until < sqlc.arg('now')
+RETURNING *;
+
+-- name: SaveAssistantConversation :exec
+-- Stores a claimed conversation's messages and ends the claim
+UPDATE assistant_conversations
+SET messages = $2, updated_at = $3, busy_until = NULL
+WHERE user_id = $1 AND busy_until = sqlc.arg('claimed_until');
+
+-- name: ReleaseAssistantConversation :exec
+-- Ends a claim without changing the conversation
+UPDATE assistant_conversations
+SET busy_until = NULL
+WHERE user_id = $1 AND busy_until = sqlc.arg('claimed_until');
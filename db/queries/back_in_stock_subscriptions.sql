@@ -0,0 +1,66 @@
+-- name: ClaimBackInStockSubscribers :many
+-- Claims a batch of the product's pending subscriptions by putting off their
+-- next attempt by the lease, so no other notifier emails them meanwhile. A
+-- claim that is never fulfilled or failed lapses when the lease runs out.
+WITH claimed AS (
+    UPDATE back_in_stock_subscriptions
+    SET next_attempt_at = CURRENT_TIMESTAMP + sqlc.arg('lease')::interval
+    WHERE id IN (
+        SELECT s.id
+        FROM back_in_stock_subscriptions s
+        JOIN users u ON u.id = s.user_id
+        WHERE s.product_id = sqlc.arg('product_id')
+          AND s.fulfilled_at IS NULL
+          AND s.attempts < sqlc.arg('max_attempts')
+          AND (s.next_attempt_at IS NULL OR s.next_attempt_at <= CURRENT_TIMESTAMP)
+          AND u.deleted_at IS NULL
+        ORDER BY s.id
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE OF s SKIP LOCKED
+    )
+    RETURNING id, user_id
+)
+SELECT c.id, c.user_id, u.email, u.first_name
+FROM claimed c
+JOIN users u ON u.id = c.user_id
+ORDER BY c.id;
+
+-- name: CreateBackInStockSubscription :one
+INSERT INTO back_in_stock_subscriptions (user_id, product_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, product_id) WHERE fulfilled_at IS NULL
+DO UPDATE SET attempts = 0, next_attempt_at = NULL
+RETURNING *;
+
+-- name: GetPendingBackInStockSubscription :one
+SELECT * FROM back_in_stock_subscriptions
+WHERE user_id = $1 AND product_id = $2 AND fulfilled_at IS NULL;
+
+-- name: DeletePendingBackInStockSubscription :execrows
+DELETE FROM back_in_stock_subscriptions
+WHERE user_id = $1 AND product_id = $2 AND fulfilled_at IS NULL;
+
+-- name: MarkBackInStockSubscriptionsFulfilled :exec
+UPDATE back_in_stock_subscriptions
+SET fulfilled_at = CURRENT_TIMESTAMP
+WHERE id = ANY(sqlc.arg('ids')::int[]) AND fulfilled_at IS NULL;
+
+-- name: RecordBackInStockFailures :exec
+-- Puts off the next attempt of each failed subscription by the retry delay,
+-- doubled for every earlier failure
+UPDATE back_in_stock_subscriptions
+SET attempts = attempts + 1,
+    next_attempt_at = CURRENT_TIMESTAMP + sqlc.arg('retry_delay')::interval * power(2, attempts)
+WHERE id = ANY(sqlc.arg('ids')::int[]) AND fulfilled_at IS NULL;
+
+-- name: ListBackInStockRetryProducts :many
+-- Products in stock with failed subscriptions due another attempt
+SELECT DISTINCT p.id, p.sku, p.name
+FROM back_in_stock_subscriptions s
+JOIN products p ON p.id = s.product_id
+WHERE s.fulfilled_at IS NULL
+  AND s.attempts < sqlc.arg('max_attempts')
+  AND s.next_attempt_at <= CURRENT_TIMESTAMP
+  AND p.stock > 0
+  AND p.deleted_at IS NULL
+ORDER BY p.id;
@@ -41,3 +41,8 @@ SELECT COUNT(*) FROM categories WHERE deleted_at IS NULL;
 -- name: GetCategoriesByIDs :many
 SELECT * FROM categories
 WHERE id = ANY($1::int[]) AND deleted_at IS NULL;
+
+-- name: ListCategoryNames :many
+SELECT id, name FROM categories
+WHERE deleted_at IS NULL
+ORDER BY name ASC;
@@ -0,0 +1,54 @@
+-- name: ListDailyProductSales :many
+-- Units of each product sold per UTC day. Cancelled orders don't count.
+SELECT oi.product_id, (o.created_at AT TIME ZONE 'UTC')::date AS day, SUM(oi.quantity)::bigint AS quantity
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.created_at >= sqlc.arg('since')
+  AND o.created_at < sqlc.arg('until')
+  AND o.status <> 'cancelled'
+  AND o.deleted_at IS NULL
+  AND oi.deleted_at IS NULL
+GROUP BY oi.product_id, day
+ORDER BY oi.product_id, day;
+
+-- name: ListForecastProducts :many
+SELECT id, stock FROM products
+WHERE is_active = true AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: DeleteDemandForecasts :exec
+DELETE FROM product_demand_forecasts;
+
+-- name: InsertDemandForecasts :execrows
+INSERT INTO product_demand_forecasts (
+    product_id, stock, daily_demand, moving_average, forecast_error, days_of_cover,
+    reorder_point, reorder_quantity, method, history_days
+)
+SELECT f.product_id, f.stock, f.daily_demand, f.moving_average, f.forecast_error,
+    CASE WHEN f.daily_demand > 0 THEN f.stock / f.daily_demand END,
+    f.reorder_point, f.reorder_quantity, f.method, f.history_days
+FROM unnest(
+    sqlc.arg('product_ids')::int[],
+    sqlc.arg('stocks')::int[],
+    sqlc.arg('daily_demands')::float8[],
+    sqlc.arg('moving_averages')::float8[],
+    sqlc.arg('forecast_errors')::float8[],
+    sqlc.arg('reorder_points')::int[],
+    sqlc.arg('reorder_quantities')::int[],
+    sqlc.arg('methods')::text[],
+    sqlc.arg('history_days')::int[]
+) AS f(product_id, stock, daily_demand, moving_average, forecast_error, reorder_point, reorder_quantity, method, history_days);
+
+-- name: CountDemandForecasts :one
+SELECT COUNT(*) FROM product_demand_forecasts f
+JOIN products p ON p.id = f.product_id
+WHERE p.deleted_at IS NULL;
+
+-- name: ListDemandForecasts :many
+-- Products that run out soonest first; products not expected to sell last
+SELECT f.*, p.sku, p.name
+FROM product_demand_forecasts f
+JOIN products p ON p.id = f.product_id
+WHERE p.deleted_at IS NULL
+ORDER BY f.days_of_cover NULLS LAST, f.reorder_quantity DESC, f.product_id
+LIMIT $1 OFFSET $2;
@@ -0,0 +1,38 @@
+-- name: GetImageBlobForUpdate :one
+SELECT * FROM image_blobs
+WHERE content_hash = $1
+FOR UPDATE;
+
+-- name: ListImageBlobVariants :many
+-- The files stored for a content hash, taken from an image that uses it.
+-- There are none once no image uses it, since its files may be collected.
+SELECT v.* FROM product_image_variants v
+WHERE v.image_id = (
+    SELECT i.id FROM product_images i
+    JOIN image_blobs b ON b.content_hash = i.content_hash
+    WHERE b.content_hash = $1 AND b.ref_count > 0 AND i.deleted_at IS NULL
+    ORDER BY i.id
+    LIMIT 1
+)
+ORDER BY v.width, v.format;
+
+-- name: ListReferencedStoragePaths :many
+-- Storage paths whose files must be kept: those of images not deleted
+-- before cutoff, and of uploads still pending
+SELECT i.storage_path::text AS storage_path
+FROM product_images i
+WHERE i.storage_path IS NOT NULL AND (i.deleted_at IS NULL OR i.deleted_at >= sqlc.arg('cutoff'))
+UNION
+SELECT v.storage_path::text
+FROM product_image_variants v
+JOIN product_images i ON i.id = v.image_id
+WHERE i.deleted_at IS NULL OR i.deleted_at >= sqlc.arg('cutoff')
+UNION
+SELECT u.storage_path::text
+FROM product_image_uploads u
+WHERE u.status = 'pending';
+
+-- name: DeleteReleasedImageBlobs :execrows
+-- Forgets content hashes no image has used since cutoff
+DELETE FROM image_blobs
+WHERE ref_count = 0 AND updated_at < sqlc.arg('cutoff');
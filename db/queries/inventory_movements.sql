@@ -0,0 +1,28 @@
+-- name: CreateInventoryMovement :one
+INSERT INTO inventory_movements (product_id, reason, reference_id, actor_id, quantity_change, stock_after, note, warehouse_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListInventoryMovementsByProduct :many
+SELECT * FROM inventory_movements
+WHERE product_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountInventoryMovementsByProduct :one
+SELECT COUNT(*) FROM inventory_movements WHERE product_id = $1;
+
+-- name: ListStockDiscrepancies :many
+SELECT
+  p.id,
+  p.sku,
+  COALESCE(p.stock, 0)::int AS stock,
+  COALESCE((SELECT SUM(m.quantity_change) FROM inventory_movements m WHERE m.product_id = p.id), 0)::int AS ledger_stock,
+  COALESCE((SELECT SUM(ws.quantity) FROM warehouse_stock ws WHERE ws.product_id = p.id), 0)::int AS warehouse_stock
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND (
+    COALESCE(p.stock, 0) <> COALESCE((SELECT SUM(m.quantity_change) FROM inventory_movements m WHERE m.product_id = p.id), 0)
+    OR COALESCE(p.stock, 0) <> COALESCE((SELECT SUM(ws.quantity) FROM warehouse_stock ws WHERE ws.product_id = p.id), 0)
+  )
+ORDER BY p.id;
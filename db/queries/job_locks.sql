@@ -0,0 +1,4 @@
+-- name: TryJobLock :one
+-- Locks a background job by name until the transaction ends, unless another
+-- session holds the lock, so one replica runs the job at a time
+SELECT pg_try_advisory_xact_lock(hashtext(sqlc.arg('name')::text)) AS locked;
@@ -0,0 +1,11 @@
+-- name: CreateOrderItemAllocation :one
+INSERT INTO order_item_allocations (order_item_id, warehouse_id, quantity)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListOrderItemAllocationsByOrderID :many
+SELECT a.id, a.order_item_id, a.warehouse_id, a.quantity, oi.product_id
+FROM order_item_allocations a
+JOIN order_items oi ON oi.id = a.order_item_id
+WHERE oi.order_id = $1
+ORDER BY a.id ASC;
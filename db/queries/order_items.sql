@@ -12,6 +12,11 @@ SELECT * FROM order_items
 WHERE order_id = $1 AND deleted_at IS NULL
 ORDER BY created_at ASC;
 
+-- name: ListOrderItemsByOrderIDs :many
+SELECT * FROM order_items
+WHERE order_id = ANY(sqlc.arg('order_ids')::int[]) AND deleted_at IS NULL
+ORDER BY order_id, created_at ASC;
+
 -- name: SoftDeleteOrderItem :exec
 UPDATE order_items
 SET deleted_at = CURRENT_TIMESTAMP
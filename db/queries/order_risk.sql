@@ -0,0 +1,42 @@
+-- name: CreateOrderRiskAssessment :one
+INSERT INTO order_risk_assessments (order_id, score, held, ip_address)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: AddOrderRiskSignals :exec
+INSERT INTO order_risk_signals (order_id, rule, weight, detail)
+SELECT sqlc.arg('order_id'), unnest(sqlc.arg('rules')::text[]), unnest(sqlc.arg('weights')::float8[]), unnest(sqlc.arg('details')::text[]);
+
+-- name: GetOrderRiskAssessment :one
+SELECT * FROM order_risk_assessments
+WHERE order_id = $1;
+
+-- name: ListOrderRiskSignalsByOrderIDs :many
+SELECT * FROM order_risk_signals
+WHERE order_id = ANY(sqlc.arg('order_ids')::int[])
+ORDER BY order_id, weight DESC, rule;
+
+-- name: ListHeldOrderRiskAssessments :many
+SELECT a.* FROM order_risk_assessments a
+JOIN orders o ON o.id = a.order_id
+WHERE o.status = 'on_hold' AND o.deleted_at IS NULL
+ORDER BY a.assessed_at, a.order_id
+LIMIT $1 OFFSET $2;
+
+-- name: RecordOrderRiskReview :one
+UPDATE order_risk_assessments
+SET reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP, decision = $3
+WHERE order_id = $1
+RETURNING *;
+
+-- name: CountRecentOrdersByIP :one
+SELECT COUNT(*) FROM order_risk_assessments
+WHERE ip_address = $1 AND assessed_at >= sqlc.arg('since');
+
+-- name: RecordFailedLogin :exec
+INSERT INTO failed_logins (user_id)
+VALUES ($1);
+
+-- name: CountRecentFailedLogins :one
+SELECT COUNT(*) FROM failed_logins
+WHERE user_id = $1 AND attempted_at >= sqlc.arg('since');
@@ -1,12 +1,21 @@
 -- name: CreateOrder :one
-INSERT INTO orders (user_id, total_amount)
-VALUES ($1, $2)
+INSERT INTO orders (user_id, total_amount, currency, exchange_rate)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetOrderByID :one
 SELECT * FROM orders
 WHERE id = $1 AND deleted_at IS NULL;
 
+-- name: GetOrderByIDForUpdate :one
+SELECT * FROM orders
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetOrdersByIDs :many
+SELECT * FROM orders
+WHERE id = ANY(sqlc.arg('ids')::int[]) AND deleted_at IS NULL;
+
 -- name: ListOrdersByUserID :many
 SELECT * FROM orders
 WHERE user_id = $1 AND deleted_at IS NULL
@@ -50,3 +59,7 @@ SELECT COUNT(*) FROM orders WHERE user_id = $1 AND deleted_at IS NULL;
 
 -- name: CountOrdersByStatus :one
 SELECT COUNT(*) FROM orders WHERE status = $1 AND deleted_at IS NULL;
+
+-- name: CountRecentOrdersByUser :one
+SELECT COUNT(*) FROM orders
+WHERE user_id = $1 AND created_at >= sqlc.arg('since') AND deleted_at IS NULL;
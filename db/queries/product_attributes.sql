@@ -0,0 +1,22 @@
+-- name: ListProductAttributesByProductIDs :many
+SELECT * FROM product_attributes
+WHERE product_id = ANY(sqlc.arg('product_ids')::int[])
+ORDER BY product_id, name, value;
+
+-- name: AddProductAttributes :exec
+INSERT INTO product_attributes (product_id, name, value)
+SELECT sqlc.arg('product_id'), unnest(sqlc.arg('names')::text[]), unnest(sqlc.arg('values')::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteProductAttributes :exec
+DELETE FROM product_attributes
+WHERE product_id = $1;
+
+-- name: ListAttributeValues :many
+-- Every attribute value set on an active product
+SELECT DISTINCT pa.name, pa.value
+FROM product_attributes pa
+JOIN products p ON p.id = pa.product_id
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+ORDER BY pa.name, pa.value;
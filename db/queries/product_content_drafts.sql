@@ -0,0 +1,20 @@
+-- name: CreateProductContentDraft :one
+INSERT INTO product_content_drafts (product_id, description, seo_title, tags, generator, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetProductContentDraft :one
+SELECT * FROM product_content_drafts
+WHERE id = $1;
+
+-- name: ListProductContentDrafts :many
+SELECT * FROM product_content_drafts
+WHERE product_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ReviewProductContentDraft :one
+-- Only a pending draft can be reviewed, so two admins can't both act on it
+UPDATE product_content_drafts
+SET status = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
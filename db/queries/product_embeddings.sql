@@ -0,0 +1,79 @@
+-- name: ListProductsNeedingEmbedding :many
+-- Products with no embedding from the model, or whose text changed since
+-- theirs was made
+SELECT c.id, c.name, c.description, c.category_name, c.attributes, c.content_hash
+FROM (
+  SELECT p.id, p.name, COALESCE(p.description, '')::text AS description, cat.name AS category_name,
+    COALESCE((
+      SELECT string_agg(a.name || ': ' || a.value, '; ' ORDER BY a.name, a.value)
+      FROM product_attributes a
+      WHERE a.product_id = p.id
+    ), '')::text AS attributes,
+    md5(concat_ws(E'\n', p.name, p.description, cat.name, (
+      SELECT string_agg(a.name || ': ' || a.value, '; ' ORDER BY a.name, a.value)
+      FROM product_attributes a
+      WHERE a.product_id = p.id
+    )))::text AS content_hash
+  FROM products p
+  JOIN categories cat ON cat.id = p.category_id
+  WHERE p.deleted_at IS NULL
+) c
+LEFT JOIN product_embeddings e ON e.product_id = c.id
+WHERE e.product_id IS NULL
+  OR e.model <> sqlc.arg('model')
+  OR e.content_hash <> c.content_hash
+ORDER BY c.id
+LIMIT sqlc.arg('limit');
+
+-- name: UpsertProductEmbedding :exec
+INSERT INTO product_embeddings (product_id, model, embedding, content_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (product_id) DO UPDATE
+SET model = EXCLUDED.model,
+    embedding = EXCLUDED.embedding,
+    content_hash = EXCLUDED.content_hash,
+    embedded_at = CURRENT_TIMESTAMP;
+
+-- name: SemanticSearchProducts :many
+-- Ranks active products by a blend of cosine similarity to the query
+-- embedding and full-text rank. With text_weight 0 this is pure semantic
+-- search; otherwise products matching either way are included.
+SELECT sqlc.embed(p),
+  ((1 - sqlc.arg('text_weight')::real) * v.similarity + sqlc.arg('text_weight')::real * t.text_rank)::real AS score
+FROM products p
+LEFT JOIN product_embeddings e ON e.product_id = p.id AND e.model = sqlc.arg('model')
+CROSS JOIN LATERAL (
+  SELECT COALESCE(SUM(x.a * x.b), 0)::real AS similarity
+  FROM unnest(e.embedding, sqlc.arg('embedding')::real[]) AS x(a, b)
+) v
+CROSS JOIN LATERAL (
+  SELECT CASE
+    WHEN sqlc.arg('text_weight')::real > 0 AND p.search_vector @@ plainto_tsquery('english', sqlc.arg('query'))
+    THEN ts_rank(p.search_vector, plainto_tsquery('english', sqlc.arg('query')), 32)
+    ELSE 0
+  END::real AS text_rank
+) t
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND (v.similarity >= sqlc.arg('min_similarity')::real OR t.text_rank > 0)
+ORDER BY score DESC, p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSemanticSearchProducts :one
+SELECT COUNT(*)
+FROM products p
+LEFT JOIN product_embeddings e ON e.product_id = p.id AND e.model = sqlc.arg('model')
+CROSS JOIN LATERAL (
+  SELECT COALESCE(SUM(x.a * x.b), 0)::real AS similarity
+  FROM unnest(e.embedding, sqlc.arg('embedding')::real[]) AS x(a, b)
+) v
+CROSS JOIN LATERAL (
+  SELECT CASE
+    WHEN sqlc.arg('text_weight')::real > 0 AND p.search_vector @@ plainto_tsquery('english', sqlc.arg('query'))
+    THEN ts_rank(p.search_vector, plainto_tsquery('english', sqlc.arg('query')), 32)
+    ELSE 0
+  END::real AS text_rank
+) t
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND (v.similarity >= sqlc.arg('min_similarity')::real OR t.text_rank > 0);
@@ -0,0 +1,47 @@
+-- name: CreateProductImageUpload :one
+INSERT INTO product_image_uploads (product_id, storage_path, content_type, max_bytes, alt_text, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetProductImageUpload :one
+SELECT * FROM product_image_uploads
+WHERE id = $1;
+
+-- name: ClaimProductImageUpload :one
+-- Marks a pending upload processing, so only one request processes it. An
+-- upload claimed before reclaim_before was abandoned by a request that
+-- crashed, and may be claimed again.
+UPDATE product_image_uploads
+SET status = 'processing', claimed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND product_id = $2
+  AND (status = 'pending' OR (status = 'processing' AND claimed_at < sqlc.arg('reclaim_before')))
+RETURNING *;
+
+-- name: CompleteProductImageUpload :one
+-- Links a processed upload to its image and marks it completed, unless it
+-- has been claimed again since
+UPDATE product_image_uploads
+SET status = 'completed', image_id = $2, completed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'processing' AND claimed_at = $3
+RETURNING *;
+
+-- name: FailProductImageUpload :exec
+-- Fails a claimed upload whose file isn't an acceptable image
+UPDATE product_image_uploads
+SET status = 'failed'
+WHERE id = $1 AND status = 'processing' AND claimed_at = $2;
+
+-- name: ReleaseProductImageUpload :exec
+-- Returns a claimed upload to pending after an error it can be retried past
+UPDATE product_image_uploads
+SET status = 'pending', claimed_at = NULL
+WHERE id = $1 AND status = 'processing' AND claimed_at = $2;
+
+-- name: ExpireProductImageUploads :many
+-- Expires uploads still pending whose URL expired before the cutoff, and
+-- ones whose processing was abandoned before abandoned_before
+UPDATE product_image_uploads
+SET status = 'expired'
+WHERE (status = 'pending' AND expires_at < sqlc.arg('cutoff'))
+   OR (status = 'processing' AND claimed_at < sqlc.arg('abandoned_before'))
+RETURNING *;
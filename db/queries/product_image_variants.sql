@@ -0,0 +1,4 @@
+-- name: ListProductImageVariantsByImageIDs :many
+SELECT * FROM product_image_variants
+WHERE image_id = ANY(sqlc.arg('image_ids')::int[])
+ORDER BY image_id, width, format;
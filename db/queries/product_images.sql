@@ -1,7 +1,31 @@
 -- name: CreateProductImage :one
-INSERT INTO product_images (product_id, url, alt_text, is_primary)
-VALUES ($1, $2, $3, $4)
-RETURNING *;
+-- Appends an image to the product's images, along with its variants, and
+-- adds a reference to its content hash. It becomes the primary image only
+-- when the product has none. Callers lock the product row first, so images
+-- added at the same time take distinct positions and only one is primary.
+WITH image AS (
+    INSERT INTO product_images (product_id, url, alt_text, storage_path, content_hash, position, is_primary)
+    SELECT sqlc.arg('product_id'), sqlc.arg('url'), sqlc.arg('alt_text'), sqlc.arg('storage_path'), sqlc.arg('content_hash'),
+           COALESCE(MAX(position) + 1, 0), COUNT(*) FILTER (WHERE is_primary) = 0
+    FROM product_images
+    WHERE product_id = sqlc.arg('product_id') AND deleted_at IS NULL
+    RETURNING *
+), variants AS (
+    INSERT INTO product_image_variants (image_id, name, format, width, height, url, storage_path)
+    SELECT image.id, v.name, v.format, v.width, v.height, v.url, v.storage_path
+    FROM image, unnest(
+        sqlc.arg('variant_names')::text[], sqlc.arg('variant_formats')::text[],
+        sqlc.arg('variant_widths')::int[], sqlc.arg('variant_heights')::int[],
+        sqlc.arg('variant_urls')::text[], sqlc.arg('variant_storage_paths')::text[]
+    ) AS v(name, format, width, height, url, storage_path)
+), blob AS (
+    INSERT INTO image_blobs (content_hash, ref_count)
+    SELECT content_hash, 1 FROM image
+    WHERE content_hash IS NOT NULL
+    ON CONFLICT (content_hash) DO UPDATE
+    SET ref_count = image_blobs.ref_count + 1, updated_at = CURRENT_TIMESTAMP
+)
+SELECT * FROM image;
 
 -- name: GetProductImageByID :one
 SELECT * FROM product_images
@@ -10,16 +34,22 @@ WHERE id = $1 AND deleted_at IS NULL;
 -- name: ListProductImages :many
 SELECT * FROM product_images
 WHERE product_id = $1 AND deleted_at IS NULL
-ORDER BY is_primary DESC, created_at ASC;
+ORDER BY position, id;
 
 -- name: GetPrimaryProductImage :one
 SELECT * FROM product_images
 WHERE product_id = $1 AND is_primary = true AND deleted_at IS NULL;
 
--- name: SetPrimaryProductImage :exec
+-- name: ClearPrimaryProductImage :exec
 UPDATE product_images
-SET is_primary = CASE WHEN id = $2 THEN true ELSE false END
-WHERE product_id = $1 AND deleted_at IS NULL;
+SET is_primary = false
+WHERE product_id = $1 AND is_primary = true AND deleted_at IS NULL;
+
+-- name: SetPrimaryProductImage :execrows
+-- Clear the current primary image first; a product can only have one.
+UPDATE product_images
+SET is_primary = true
+WHERE product_id = $1 AND id = $2 AND deleted_at IS NULL;
 
 -- name: UpdateProductImage :one
 UPDATE product_images
@@ -27,17 +57,61 @@ SET url = $2, alt_text = $3, is_primary = $4
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
--- name: SoftDeleteProductImage :exec
+-- name: UpdateProductImageAltText :one
 UPDATE product_images
-SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL;
+SET alt_text = $3
+WHERE product_id = $1 AND id = $2 AND deleted_at IS NULL
+RETURNING *;
 
--- name: SoftDeleteProductImagesByProductID :exec
-UPDATE product_images
-SET deleted_at = CURRENT_TIMESTAMP
-WHERE product_id = $1 AND deleted_at IS NULL;
+-- name: SetProductImagePositions :execrows
+-- Puts the product's images in the order of image_ids
+UPDATE product_images i
+SET position = ordered.ord - 1
+FROM unnest(sqlc.arg('image_ids')::int[]) WITH ORDINALITY AS ordered(id, ord)
+WHERE i.id = ordered.id AND i.product_id = sqlc.arg('product_id') AND i.deleted_at IS NULL;
+
+-- name: SoftDeleteProductImage :many
+-- Also drops the image's reference to its content hash, returning the hash
+-- when no image uses it any more
+WITH deleted AS (
+    UPDATE product_images
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE id = $1 AND deleted_at IS NULL
+    RETURNING content_hash
+), released AS (
+    UPDATE image_blobs b
+    SET ref_count = b.ref_count - 1, updated_at = CURRENT_TIMESTAMP
+    FROM deleted
+    WHERE b.content_hash = deleted.content_hash
+    RETURNING b.content_hash, b.ref_count
+)
+SELECT content_hash FROM released
+WHERE ref_count = 0;
+
+-- name: SoftDeleteProductImagesByProductID :many
+-- Also drops the images' references to their content hashes, returning the
+-- hashes no image uses any more
+WITH deleted AS (
+    UPDATE product_images
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE product_id = $1 AND deleted_at IS NULL
+    RETURNING content_hash
+), released AS (
+    UPDATE image_blobs b
+    SET ref_count = b.ref_count - counted.refs, updated_at = CURRENT_TIMESTAMP
+    FROM (
+        SELECT content_hash, COUNT(*) AS refs
+        FROM deleted
+        WHERE content_hash IS NOT NULL
+        GROUP BY content_hash
+    ) counted
+    WHERE b.content_hash = counted.content_hash
+    RETURNING b.content_hash, b.ref_count
+)
+SELECT content_hash FROM released
+WHERE ref_count = 0;
 
 -- name: ListProductImagesByProductIDs :many
 SELECT * FROM product_images
 WHERE product_id = ANY($1::int[]) AND deleted_at IS NULL
-ORDER BY product_id, is_primary DESC, created_at ASC;
+ORDER BY product_id, position, id;
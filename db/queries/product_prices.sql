@@ -0,0 +1,37 @@
+-- name: CreateProductPrice :one
+INSERT INTO product_prices (product_id, currency, kind, price, starts_at, ends_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListProductPricesByProduct :many
+SELECT * FROM product_prices
+WHERE product_id = $1
+ORDER BY starts_at DESC, id DESC;
+
+-- name: EndProductSalePrice :one
+UPDATE product_prices
+SET ends_at = GREATEST(starts_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND product_id = $2 AND kind = 'sale'
+  AND (ends_at IS NULL OR ends_at > CURRENT_TIMESTAMP)
+RETURNING *;
+
+-- name: GetEffectivePrices :many
+SELECT p.id AS product_id,
+       p.price AS current_price,
+       lp.price::numeric AS list_price,
+       sp.price::numeric AS sale_price
+FROM products p
+LEFT JOIN LATERAL (
+    SELECT price FROM product_prices
+    WHERE product_id = p.id AND currency = sqlc.arg('currency') AND kind = 'list' AND starts_at <= sqlc.arg('at')
+    ORDER BY starts_at DESC, id DESC
+    LIMIT 1
+) lp ON true
+LEFT JOIN LATERAL (
+    SELECT price FROM product_prices
+    WHERE product_id = p.id AND currency = sqlc.arg('currency') AND kind = 'sale' AND starts_at <= sqlc.arg('at')
+      AND (ends_at IS NULL OR ends_at > sqlc.arg('at'))
+    ORDER BY starts_at DESC, id DESC
+    LIMIT 1
+) sp ON true
+WHERE p.id = ANY(sqlc.arg('product_ids')::int[]);
@@ -0,0 +1,77 @@
+-- name: DeleteProductRecommendations :exec
+DELETE FROM product_recommendations;
+
+-- name: InsertProductRecommendations :execrows
+-- Scores every pair of products bought in the same order by the cosine
+-- similarity of their sets of orders, co-purchases / sqrt(orders of a *
+-- orders of b), and keeps each product's top_n neighbours. Cancelled orders
+-- don't count.
+WITH purchases AS (
+  SELECT DISTINCT oi.order_id, oi.product_id
+  FROM order_items oi
+  JOIN orders o ON o.id = oi.order_id
+  WHERE o.status <> 'cancelled'
+    AND o.deleted_at IS NULL
+    AND oi.deleted_at IS NULL
+),
+product_orders AS (
+  SELECT product_id, COUNT(*) AS orders
+  FROM purchases
+  GROUP BY product_id
+),
+pairs AS (
+  SELECT a.product_id, b.product_id AS recommended_product_id, COUNT(*) AS co_purchases
+  FROM purchases a
+  JOIN purchases b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+  GROUP BY a.product_id, b.product_id
+  HAVING COUNT(*) >= sqlc.arg('min_co_purchases')::int
+),
+ranked AS (
+  SELECT pr.product_id, pr.recommended_product_id, pr.co_purchases,
+    pr.co_purchases / sqrt(pa.orders * pb.orders) AS score,
+    row_number() OVER (
+      PARTITION BY pr.product_id
+      ORDER BY pr.co_purchases / sqrt(pa.orders * pb.orders) DESC, pr.co_purchases DESC, pr.recommended_product_id
+    ) AS position
+  FROM pairs pr
+  JOIN product_orders pa ON pa.product_id = pr.product_id
+  JOIN product_orders pb ON pb.product_id = pr.recommended_product_id
+)
+INSERT INTO product_recommendations (product_id, recommended_product_id, score, co_purchases)
+SELECT product_id, recommended_product_id, score::real, co_purchases::int
+FROM ranked
+WHERE position <= sqlc.arg('top_n')::int;
+
+-- name: ListProductRecommendations :many
+SELECT p.id, p.category_id, p.name, p.description, p.price, p.stock, p.sku, p.is_active, p.created_at, p.updated_at,
+  r.score
+FROM product_recommendations r
+JOIN products p ON p.id = r.recommended_product_id
+WHERE r.product_id = sqlc.arg('product_id')
+  AND p.is_active = true
+  AND p.deleted_at IS NULL
+ORDER BY r.score DESC, p.id
+LIMIT sqlc.arg('limit');
+
+-- name: ListCartRecommendations :many
+-- Neighbours of everything in the user's cart, scored by the sum of their
+-- scores against each cart product. Products already in the cart are left out.
+WITH cart_products AS (
+  SELECT ci.product_id
+  FROM cart_items ci
+  JOIN carts c ON c.id = ci.cart_id
+  WHERE c.user_id = sqlc.arg('user_id')
+    AND c.deleted_at IS NULL
+    AND ci.deleted_at IS NULL
+)
+SELECT p.id, p.category_id, p.name, p.description, p.price, p.stock, p.sku, p.is_active, p.created_at, p.updated_at,
+  SUM(r.score)::real AS score
+FROM product_recommendations r
+JOIN products p ON p.id = r.recommended_product_id
+WHERE r.product_id IN (SELECT product_id FROM cart_products)
+  AND r.recommended_product_id NOT IN (SELECT product_id FROM cart_products)
+  AND p.is_active = true
+  AND p.deleted_at IS NULL
+GROUP BY p.id
+ORDER BY score DESC, p.id
+LIMIT sqlc.arg('limit');
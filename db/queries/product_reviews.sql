@@ -0,0 +1,42 @@
+-- name: CreateProductReview :one
+INSERT INTO product_reviews (product_id, user_id, rating, body)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (product_id, user_id) DO NOTHING
+RETURNING *;
+
+-- name: ListProductReviews :many
+SELECT * FROM product_reviews
+WHERE product_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountProductReviews :one
+SELECT COUNT(*) FROM product_reviews
+WHERE product_id = $1;
+
+-- name: GetProductReviewStats :one
+SELECT COUNT(*)::int AS review_count, COALESCE(AVG(rating), 0)::float8 AS average_rating
+FROM product_reviews
+WHERE product_id = $1;
+
+-- name: UpsertProductReviewSummary :one
+INSERT INTO product_review_summaries (product_id, review_count, average_rating, sentiment, pros, cons, quotes, summarizer)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (product_id) DO UPDATE
+SET review_count = EXCLUDED.review_count,
+    average_rating = EXCLUDED.average_rating,
+    sentiment = EXCLUDED.sentiment,
+    pros = EXCLUDED.pros,
+    cons = EXCLUDED.cons,
+    quotes = EXCLUDED.quotes,
+    summarizer = EXCLUDED.summarizer,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetProductReviewSummary :one
+SELECT * FROM product_review_summaries
+WHERE product_id = $1;
+
+-- name: ListProductReviewSummariesByProductIDs :many
+SELECT * FROM product_review_summaries
+WHERE product_id = ANY(sqlc.arg('product_ids')::int[]);
@@ -0,0 +1,74 @@
+-- name: CreateProductView :exec
+INSERT INTO product_views (user_id, product_id)
+VALUES ($1, $2);
+
+-- name: ListFeedCandidates :many
+-- Active products with how many orders they were in since a time, the
+-- best-selling first. An empty category list means every category.
+SELECT p.id, p.category_id, p.created_at,
+  COALESCE(s.orders, 0)::int AS orders
+FROM products p
+LEFT JOIN (
+  SELECT oi.product_id, COUNT(DISTINCT oi.order_id) AS orders
+  FROM order_items oi
+  JOIN orders o ON o.id = oi.order_id
+  WHERE o.created_at >= sqlc.arg('since')
+    AND o.status <> 'cancelled'
+    AND o.deleted_at IS NULL
+    AND oi.deleted_at IS NULL
+  GROUP BY oi.product_id
+) s ON s.product_id = p.id
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND (cardinality(sqlc.arg('category_ids')::int[]) = 0 OR p.category_id = ANY(sqlc.arg('category_ids')::int[]))
+ORDER BY orders DESC, p.created_at DESC, p.id
+LIMIT sqlc.arg('limit');
+
+-- name: ListUserCategoryActivity :many
+-- How often a user ordered, carted and viewed products of each category.
+-- Views count since a time; orders and the cart count whenever they were.
+SELECT p.category_id,
+  COUNT(*) FILTER (WHERE a.kind = 'order')::int AS orders,
+  COUNT(*) FILTER (WHERE a.kind = 'cart')::int AS cart_items,
+  COUNT(*) FILTER (WHERE a.kind = 'view')::int AS views
+FROM (
+  SELECT oi.product_id, 'order' AS kind
+  FROM order_items oi
+  JOIN orders o ON o.id = oi.order_id
+  WHERE o.user_id = sqlc.arg('user_id')
+    AND o.status <> 'cancelled'
+    AND o.deleted_at IS NULL
+    AND oi.deleted_at IS NULL
+  UNION ALL
+  SELECT ci.product_id, 'cart' AS kind
+  FROM cart_items ci
+  JOIN carts c ON c.id = ci.cart_id
+  WHERE c.user_id = sqlc.arg('user_id')
+    AND c.deleted_at IS NULL
+    AND ci.deleted_at IS NULL
+  UNION ALL
+  SELECT v.product_id, 'view' AS kind
+  FROM product_views v
+  WHERE v.user_id = sqlc.arg('user_id')
+    AND v.viewed_at >= sqlc.arg('views_since')
+) a
+JOIN products p ON p.id = a.product_id
+GROUP BY p.category_id
+ORDER BY p.category_id;
+
+-- name: ListUserOwnedProductIDs :many
+-- Products a user has bought or has in their cart
+SELECT oi.product_id
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.user_id = sqlc.arg('user_id')
+  AND o.status <> 'cancelled'
+  AND o.deleted_at IS NULL
+  AND oi.deleted_at IS NULL
+UNION
+SELECT ci.product_id
+FROM cart_items ci
+JOIN carts c ON c.id = ci.cart_id
+WHERE c.user_id = sqlc.arg('user_id')
+  AND c.deleted_at IS NULL
+  AND ci.deleted_at IS NULL;
@@ -30,8 +30,9 @@ ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: UpdateProduct :one
+-- Stock is left alone; it only changes through inventory movements
 UPDATE products
-SET category_id = $2, name = $3, description = $4, price = $5, stock = $6, sku = $7, updated_at = CURRENT_TIMESTAMP
+SET category_id = $2, name = $3, description = $4, price = $5, sku = $6, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
@@ -41,12 +42,23 @@ SET stock = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: AdjustProductStock :one
+UPDATE products
+SET stock = COALESCE(stock, 0) + sqlc.arg('delta')::int, updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
 -- name: UpdateProductStatus :one
 UPDATE products
 SET is_active = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: UpdateProductContent :exec
+UPDATE products
+SET seo_title = $2, tags = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
 -- name: SoftDeleteProduct :exec
 UPDATE products
 SET deleted_at = CURRENT_TIMESTAMP
@@ -76,24 +88,160 @@ WHERE id = ANY($1::int[]) AND deleted_at IS NULL
 FOR UPDATE;
 
 -- name: SearchProducts :many
-SELECT
-  *,
-  ts_rank(search_vector, plainto_tsquery('english', $1)) as rank
-FROM products
-WHERE search_vector @@ plainto_tsquery('english', $1)
-  AND is_active = true
-  AND deleted_at IS NULL
-  AND (sqlc.narg('category_id')::int IS NULL OR category_id = sqlc.narg('category_id')::int)
-  AND (sqlc.narg('min_price')::numeric IS NULL OR price >= sqlc.narg('min_price')::numeric)
-  AND (sqlc.narg('max_price')::numeric IS NULL OR price <= sqlc.narg('max_price')::numeric)
-ORDER BY rank DESC
-LIMIT $2 OFFSET $3;
+-- The search filters live in search_product_matches, which every search and
+-- facet query reads; prices are in the quoted currency
+SELECT sqlc.embed(p), m.rank
+FROM search_product_matches(
+  sqlc.arg('query')::text,
+  sqlc.arg('fuzzy')::bool,
+  sqlc.arg('category_ids')::int[],
+  sqlc.narg('min_price')::numeric,
+  sqlc.narg('max_price')::numeric,
+  sqlc.narg('in_stock')::bool,
+  sqlc.arg('attribute_names')::text[],
+  sqlc.arg('attribute_values')::text[],
+  sqlc.arg('base_currency')::text,
+  sqlc.arg('currency')::text,
+  sqlc.arg('rate')::numeric
+) m
+JOIN products p ON p.id = m.product_id
+ORDER BY
+  CASE WHEN sqlc.arg('sort')::text = 'price_asc' THEN m.price END ASC,
+  CASE WHEN sqlc.arg('sort')::text = 'price_desc' THEN m.price END DESC,
+  CASE WHEN sqlc.arg('sort')::text = 'newest' THEN p.created_at END DESC,
+  m.rank DESC,
+  p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountSearchProducts :one
-SELECT COUNT(*) FROM products
-WHERE search_vector @@ plainto_tsquery('english', $1)
-  AND is_active = true
-  AND deleted_at IS NULL
-  AND (sqlc.narg('category_id')::int IS NULL OR category_id = sqlc.narg('category_id')::int)
-  AND (sqlc.narg('min_price')::numeric IS NULL OR price >= sqlc.narg('min_price')::numeric)
-  AND (sqlc.narg('max_price')::numeric IS NULL OR price <= sqlc.narg('max_price')::numeric);
+SELECT COUNT(*)
+FROM search_product_matches(
+  sqlc.arg('query')::text,
+  sqlc.arg('fuzzy')::bool,
+  sqlc.arg('category_ids')::int[],
+  sqlc.narg('min_price')::numeric,
+  sqlc.narg('max_price')::numeric,
+  sqlc.narg('in_stock')::bool,
+  sqlc.arg('attribute_names')::text[],
+  sqlc.arg('attribute_values')::text[],
+  sqlc.arg('base_currency')::text,
+  sqlc.arg('currency')::text,
+  sqlc.arg('rate')::numeric
+) m;
+
+-- name: SearchCategoryFacets :many
+SELECT p.category_id, COUNT(*) AS count
+FROM search_product_matches(
+  sqlc.arg('query')::text,
+  sqlc.arg('fuzzy')::bool,
+  sqlc.arg('category_ids')::int[],
+  sqlc.narg('min_price')::numeric,
+  sqlc.narg('max_price')::numeric,
+  sqlc.narg('in_stock')::bool,
+  sqlc.arg('attribute_names')::text[],
+  sqlc.arg('attribute_values')::text[],
+  sqlc.arg('base_currency')::text,
+  sqlc.arg('currency')::text,
+  sqlc.arg('rate')::numeric
+) m
+JOIN products p ON p.id = m.product_id
+GROUP BY p.category_id
+ORDER BY count DESC, p.category_id;
+
+-- name: SearchPriceFacets :many
+SELECT width_bucket(m.price, sqlc.arg('bounds')::numeric[]) AS bucket, COUNT(*) AS count
+FROM search_product_matches(
+  sqlc.arg('query')::text,
+  sqlc.arg('fuzzy')::bool,
+  sqlc.arg('category_ids')::int[],
+  sqlc.narg('min_price')::numeric,
+  sqlc.narg('max_price')::numeric,
+  sqlc.narg('in_stock')::bool,
+  sqlc.arg('attribute_names')::text[],
+  sqlc.arg('attribute_values')::text[],
+  sqlc.arg('base_currency')::text,
+  sqlc.arg('currency')::text,
+  sqlc.arg('rate')::numeric
+) m
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: SearchStockFacets :one
+SELECT COUNT(*) FILTER (WHERE m.available > 0) AS in_stock,
+       COUNT(*) FILTER (WHERE m.available <= 0) AS out_of_stock
+FROM search_product_matches(
+  sqlc.arg('query')::text,
+  sqlc.arg('fuzzy')::bool,
+  sqlc.arg('category_ids')::int[],
+  sqlc.narg('min_price')::numeric,
+  sqlc.narg('max_price')::numeric,
+  sqlc.narg('in_stock')::bool,
+  sqlc.arg('attribute_names')::text[],
+  sqlc.arg('attribute_values')::text[],
+  sqlc.arg('base_currency')::text,
+  sqlc.arg('currency')::text,
+  sqlc.arg('rate')::numeric
+) m;
+
+-- name: SearchAttributeFacets :many
+SELECT a.name, a.value, COUNT(*) AS count
+FROM search_product_matches(
+  sqlc.arg('query')::text,
+  sqlc.arg('fuzzy')::bool,
+  sqlc.arg('category_ids')::int[],
+  sqlc.narg('min_price')::numeric,
+  sqlc.narg('max_price')::numeric,
+  sqlc.narg('in_stock')::bool,
+  sqlc.arg('attribute_names')::text[],
+  sqlc.arg('attribute_values')::text[],
+  sqlc.arg('base_currency')::text,
+  sqlc.arg('currency')::text,
+  sqlc.arg('rate')::numeric
+) m
+JOIN product_attributes a ON a.product_id = m.product_id
+WHERE (sqlc.narg('facet_name')::text IS NULL OR a.name = sqlc.narg('facet_name')::text)
+  AND NOT (a.name = ANY(sqlc.arg('exclude_names')::text[]))
+GROUP BY a.name, a.value
+ORDER BY a.name, count DESC, a.value;
+
+-- name: SuggestProducts :many
+-- Names starting with the prefix, or with a word starting with it, come
+-- first; names only similar to it follow
+SELECT p.id, p.name, p.sku
+FROM products p
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND (
+    p.name ILIKE sqlc.arg('pattern')::text || '%'
+    OR p.name ILIKE '% ' || sqlc.arg('pattern')::text || '%'
+    OR word_similarity(sqlc.arg('prefix')::text, p.name) >= 0.3
+  )
+ORDER BY
+  p.name ILIKE sqlc.arg('pattern')::text || '%' DESC,
+  p.name ILIKE '% ' || sqlc.arg('pattern')::text || '%' DESC,
+  word_similarity(sqlc.arg('prefix')::text, p.name) DESC,
+  p.name
+LIMIT sqlc.arg('limit');
+
+-- name: ListSearchTermWords :many
+-- The distinct words in active product names, which misspelt search terms
+-- are corrected to
+SELECT DISTINCT w.word::text AS word
+FROM products p
+CROSS JOIN LATERAL regexp_split_to_table(lower(p.name), '[^[:alnum:]]+') AS w(word)
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND w.word <> ''
+ORDER BY word;
+
+-- name: ListCategoryTrainingProducts :many
+-- Products with the category they are filed under, for learning what the
+-- products of each category are called. Products in deleted or inactive
+-- categories are left out.
+SELECT p.id, p.name, p.description, p.sku, p.category_id, c.name AS category_name
+FROM products p
+JOIN categories c ON c.id = p.category_id
+WHERE p.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND c.is_active = true
+ORDER BY p.id;
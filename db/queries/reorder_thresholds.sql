@@ -0,0 +1,38 @@
+-- name: UpsertProductReorderThreshold :one
+INSERT INTO reorder_thresholds (product_id, threshold)
+VALUES ($1, $2)
+ON CONFLICT (product_id)
+DO UPDATE SET threshold = EXCLUDED.threshold, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: UpsertCategoryReorderThreshold :one
+INSERT INTO reorder_thresholds (category_id, threshold)
+VALUES ($1, $2)
+ON CONFLICT (category_id)
+DO UPDATE SET threshold = EXCLUDED.threshold, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteProductReorderThreshold :exec
+DELETE FROM reorder_thresholds WHERE product_id = $1;
+
+-- name: DeleteCategoryReorderThreshold :exec
+DELETE FROM reorder_thresholds WHERE category_id = $1;
+
+-- name: GetReorderThresholdsByProductIDs :many
+SELECT p.id, p.sku, p.name,
+       COALESCE(pt.threshold, ct.threshold, sqlc.arg('default_threshold')::int)::int AS threshold
+FROM products p
+LEFT JOIN reorder_thresholds pt ON pt.product_id = p.id
+LEFT JOIN reorder_thresholds ct ON ct.category_id = p.category_id
+WHERE p.id = ANY(sqlc.arg('product_ids')::int[]);
+
+-- name: ListProductsBelowThreshold :many
+SELECT p.id, p.sku, p.name, COALESCE(p.stock, 0)::int AS stock,
+       COALESCE(pt.threshold, ct.threshold, sqlc.arg('default_threshold')::int)::int AS threshold
+FROM products p
+LEFT JOIN reorder_thresholds pt ON pt.product_id = p.id
+LEFT JOIN reorder_thresholds ct ON ct.category_id = p.category_id
+WHERE p.deleted_at IS NULL
+  AND (COALESCE(p.stock, 0) <= 0
+       OR COALESCE(p.stock, 0) < COALESCE(pt.threshold, ct.threshold, sqlc.arg('default_threshold')::int))
+ORDER BY COALESCE(p.stock, 0) ASC, p.id ASC;
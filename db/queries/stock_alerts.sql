@@ -0,0 +1,21 @@
+-- name: QueueStockAlert :exec
+INSERT INTO stock_alerts (product_id, type, sku, product_name, stock, threshold)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (product_id) DO UPDATE SET
+    type = EXCLUDED.type,
+    sku = EXCLUDED.sku,
+    product_name = EXCLUDED.product_name,
+    stock = EXCLUDED.stock,
+    threshold = EXCLUDED.threshold,
+    queued_at = clock_timestamp();
+
+-- name: ListStockAlerts :many
+SELECT * FROM stock_alerts
+ORDER BY queued_at, product_id;
+
+-- name: DeleteSentStockAlerts :exec
+-- Deletes the alerts a digest listed, unless a newer alert has replaced one
+-- since, in which case the newer alert is kept for the next digest
+DELETE FROM stock_alerts a
+USING unnest(sqlc.arg('product_ids')::int[], sqlc.arg('queued_ats')::timestamptz[]) AS s(product_id, queued_at)
+WHERE a.product_id = s.product_id AND a.queued_at = s.queued_at;
@@ -0,0 +1,25 @@
+-- name: UpsertStockReservation :one
+INSERT INTO stock_reservations (user_id, product_id, quantity, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, product_id)
+DO UPDATE SET quantity = EXCLUDED.quantity, expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: ListActiveStockReservationsByUser :many
+SELECT * FROM stock_reservations
+WHERE user_id = $1 AND expires_at > CURRENT_TIMESTAMP
+ORDER BY product_id ASC;
+
+-- name: GetReservedStockByProductIDs :many
+SELECT product_id, SUM(quantity)::int AS reserved
+FROM stock_reservations
+WHERE product_id = ANY(sqlc.arg('product_ids')::int[])
+  AND expires_at > CURRENT_TIMESTAMP
+  AND user_id <> sqlc.arg('exclude_user_id')
+GROUP BY product_id;
+
+-- name: DeleteStockReservationsByUser :exec
+DELETE FROM stock_reservations WHERE user_id = $1;
+
+-- name: DeleteExpiredStockReservations :execrows
+DELETE FROM stock_reservations WHERE expires_at <= CURRENT_TIMESTAMP;
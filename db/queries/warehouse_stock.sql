@@ -0,0 +1,26 @@
+-- name: AdjustWarehouseStock :one
+INSERT INTO warehouse_stock (warehouse_id, product_id, quantity)
+VALUES (sqlc.arg('warehouse_id'), sqlc.arg('product_id'), sqlc.arg('delta')::int)
+ON CONFLICT (warehouse_id, product_id)
+DO UPDATE SET quantity = warehouse_stock.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetWarehouseStockForUpdate :one
+SELECT * FROM warehouse_stock
+WHERE warehouse_id = $1 AND product_id = $2
+FOR UPDATE;
+
+-- name: ListWarehouseStockByProduct :many
+SELECT ws.warehouse_id, w.code, w.name, ws.quantity, ws.updated_at
+FROM warehouse_stock ws
+JOIN warehouses w ON w.id = ws.warehouse_id
+WHERE ws.product_id = $1
+ORDER BY w.priority ASC, w.id ASC;
+
+-- name: ListAllocatableStockForUpdate :many
+SELECT ws.warehouse_id, ws.product_id, ws.quantity, w.code, w.country, w.postal_code, w.latitude, w.longitude, w.priority
+FROM warehouse_stock ws
+JOIN warehouses w ON w.id = ws.warehouse_id
+WHERE ws.product_id = ANY($1::int[]) AND w.is_active = true AND ws.quantity > 0
+ORDER BY w.priority ASC, w.id ASC
+FOR UPDATE OF ws;
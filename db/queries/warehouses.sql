@@ -0,0 +1,18 @@
+-- name: CreateWarehouse :one
+INSERT INTO warehouses (code, name, country, postal_code, latitude, longitude, priority)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetWarehouseByCode :one
+SELECT * FROM warehouses
+WHERE code = $1;
+
+-- name: GetDefaultWarehouse :one
+SELECT * FROM warehouses
+WHERE is_active = true
+ORDER BY priority ASC, id ASC
+LIMIT 1;
+
+-- name: ListWarehouses :many
+SELECT * FROM warehouses
+ORDER BY priority ASC, id ASC;
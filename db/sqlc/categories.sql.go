@@ -175,6 +175,37 @@ func (q *Queries) ListCategories(ctx context.Context, arg ListCategoriesParams)
 	return items, nil
 }
 
+const listCategoryNames = `-- name: ListCategoryNames :many
+SELECT id, name FROM categories
+WHERE deleted_at IS NULL
+ORDER BY name ASC
+`
+
+type ListCategoryNamesRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) ListCategoryNames(ctx context.Context) ([]ListCategoryNamesRow, error) {
+	rows, err := q.db.Query(ctx, listCategoryNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCategoryNamesRow{}
+	for rows.Next() {
+		var i ListCategoryNamesRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const softDeleteCategory = `-- name: SoftDeleteCategory :exec
 UPDATE categories
 SET deleted_at = CURRENT_TIMESTAMP
@@ -11,6 +11,181 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ContentDraftStatus string
+
+const (
+	ContentDraftStatusPending  ContentDraftStatus = "pending"
+	ContentDraftStatusAccepted ContentDraftStatus = "accepted"
+	ContentDraftStatusRejected ContentDraftStatus = "rejected"
+)
+
+func (e *ContentDraftStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ContentDraftStatus(s)
+	case string:
+		*e = ContentDraftStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ContentDraftStatus: %T", src)
+	}
+	return nil
+}
+
+type NullContentDraftStatus struct {
+	ContentDraftStatus ContentDraftStatus `json:"content_draft_status"`
+	Valid              bool               `json:"valid"` // Valid is true if ContentDraftStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullContentDraftStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ContentDraftStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ContentDraftStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullContentDraftStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ContentDraftStatus), nil
+}
+
+type ImageUploadStatus string
+
+const (
+	ImageUploadStatusPending    ImageUploadStatus = "pending"
+	ImageUploadStatusProcessing ImageUploadStatus = "processing"
+	ImageUploadStatusCompleted  ImageUploadStatus = "completed"
+	ImageUploadStatusFailed     ImageUploadStatus = "failed"
+	ImageUploadStatusExpired    ImageUploadStatus = "expired"
+)
+
+func (e *ImageUploadStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImageUploadStatus(s)
+	case string:
+		*e = ImageUploadStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImageUploadStatus: %T", src)
+	}
+	return nil
+}
+
+type NullImageUploadStatus struct {
+	ImageUploadStatus ImageUploadStatus `json:"image_upload_status"`
+	Valid             bool              `json:"valid"` // Valid is true if ImageUploadStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImageUploadStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImageUploadStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImageUploadStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImageUploadStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImageUploadStatus), nil
+}
+
+type InventoryMovementReason string
+
+const (
+	InventoryMovementReasonOrder            InventoryMovementReason = "order"
+	InventoryMovementReasonCancellation     InventoryMovementReason = "cancellation"
+	InventoryMovementReasonManualAdjustment InventoryMovementReason = "manual_adjustment"
+	InventoryMovementReasonImport           InventoryMovementReason = "import"
+	InventoryMovementReasonReturn           InventoryMovementReason = "return"
+)
+
+func (e *InventoryMovementReason) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InventoryMovementReason(s)
+	case string:
+		*e = InventoryMovementReason(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InventoryMovementReason: %T", src)
+	}
+	return nil
+}
+
+type NullInventoryMovementReason struct {
+	InventoryMovementReason InventoryMovementReason `json:"inventory_movement_reason"`
+	Valid                   bool                    `json:"valid"` // Valid is true if InventoryMovementReason is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInventoryMovementReason) Scan(value interface{}) error {
+	if value == nil {
+		ns.InventoryMovementReason, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InventoryMovementReason.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInventoryMovementReason) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InventoryMovementReason), nil
+}
+
+type OrderRiskDecision string
+
+const (
+	OrderRiskDecisionApproved OrderRiskDecision = "approved"
+	OrderRiskDecisionRejected OrderRiskDecision = "rejected"
+)
+
+func (e *OrderRiskDecision) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderRiskDecision(s)
+	case string:
+		*e = OrderRiskDecision(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderRiskDecision: %T", src)
+	}
+	return nil
+}
+
+type NullOrderRiskDecision struct {
+	OrderRiskDecision OrderRiskDecision `json:"order_risk_decision"`
+	Valid             bool              `json:"valid"` // Valid is true if OrderRiskDecision is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrderRiskDecision) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrderRiskDecision, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrderRiskDecision.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrderRiskDecision) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrderRiskDecision), nil
+}
+
 type OrderStatus string
 
 const (
@@ -19,6 +194,7 @@ const (
 	OrderStatusShipped   OrderStatus = "shipped"
 	OrderStatusDelivered OrderStatus = "delivered"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusOnHold    OrderStatus = "on_hold"
 )
 
 func (e *OrderStatus) Scan(src interface{}) error {
@@ -56,6 +232,48 @@ func (ns NullOrderStatus) Value() (driver.Value, error) {
 	return string(ns.OrderStatus), nil
 }
 
+type ProductPriceKind string
+
+const (
+	ProductPriceKindList ProductPriceKind = "list"
+	ProductPriceKindSale ProductPriceKind = "sale"
+)
+
+func (e *ProductPriceKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProductPriceKind(s)
+	case string:
+		*e = ProductPriceKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProductPriceKind: %T", src)
+	}
+	return nil
+}
+
+type NullProductPriceKind struct {
+	ProductPriceKind ProductPriceKind `json:"product_price_kind"`
+	Valid            bool             `json:"valid"` // Valid is true if ProductPriceKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProductPriceKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProductPriceKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProductPriceKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProductPriceKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProductPriceKind), nil
+}
+
 type UserRole string
 
 const (
@@ -98,6 +316,23 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type AssistantConversation struct {
+	UserID    int32              `json:"user_id"`
+	Messages  []byte             `json:"messages"`
+	BusyUntil pgtype.Timestamptz `json:"busy_until"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type BackInStockSubscription struct {
+	ID            int32              `json:"id"`
+	UserID        int32              `json:"user_id"`
+	ProductID     int32              `json:"product_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	FulfilledAt   pgtype.Timestamptz `json:"fulfilled_at"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+}
+
 type Cart struct {
 	ID        int32              `json:"id"`
 	UserID    int32              `json:"user_id"`
@@ -126,14 +361,42 @@ type Category struct {
 	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
 }
 
-type Order struct {
-	ID          int32              `json:"id"`
+type FailedLogin struct {
+	ID          int64              `json:"id"`
 	UserID      int32              `json:"user_id"`
-	Status      NullOrderStatus    `json:"status"`
-	TotalAmount pgtype.Numeric     `json:"total_amount"`
+	AttemptedAt pgtype.Timestamptz `json:"attempted_at"`
+}
+
+type ImageBlob struct {
+	ContentHash string             `json:"content_hash"`
+	RefCount    int32              `json:"ref_count"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type InventoryMovement struct {
+	ID             int32                   `json:"id"`
+	ProductID      int32                   `json:"product_id"`
+	Reason         InventoryMovementReason `json:"reason"`
+	ReferenceID    pgtype.Text             `json:"reference_id"`
+	ActorID        pgtype.Int4             `json:"actor_id"`
+	QuantityChange int32                   `json:"quantity_change"`
+	StockAfter     int32                   `json:"stock_after"`
+	Note           pgtype.Text             `json:"note"`
+	CreatedAt      pgtype.Timestamptz      `json:"created_at"`
+	WarehouseID    pgtype.Int4             `json:"warehouse_id"`
+}
+
+type Order struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	Status       NullOrderStatus    `json:"status"`
+	TotalAmount  pgtype.Numeric     `json:"total_amount"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+	Currency     string             `json:"currency"`
+	ExchangeRate pgtype.Numeric     `json:"exchange_rate"`
 }
 
 type OrderIdempotencyKey struct {
@@ -154,6 +417,32 @@ type OrderItem struct {
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type OrderItemAllocation struct {
+	ID          int32              `json:"id"`
+	OrderItemID int32              `json:"order_item_id"`
+	WarehouseID int32              `json:"warehouse_id"`
+	Quantity    int32              `json:"quantity"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrderRiskAssessment struct {
+	OrderID    int32                 `json:"order_id"`
+	Score      float64               `json:"score"`
+	Held       bool                  `json:"held"`
+	IpAddress  pgtype.Text           `json:"ip_address"`
+	AssessedAt pgtype.Timestamptz    `json:"assessed_at"`
+	ReviewedBy pgtype.Int4           `json:"reviewed_by"`
+	ReviewedAt pgtype.Timestamptz    `json:"reviewed_at"`
+	Decision   NullOrderRiskDecision `json:"decision"`
+}
+
+type OrderRiskSignal struct {
+	OrderID int32   `json:"order_id"`
+	Rule    string  `json:"rule"`
+	Weight  float64 `json:"weight"`
+	Detail  string  `json:"detail"`
+}
+
 type Product struct {
 	ID          int32              `json:"id"`
 	CategoryID  int32              `json:"category_id"`
@@ -168,16 +457,130 @@ type Product struct {
 	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
 	// Full-text search vector combining name (weight A), sku (weight A), and description (weight B)
 	SearchVector interface{} `json:"search_vector"`
+	SeoTitle     pgtype.Text `json:"seo_title"`
+	Tags         []string    `json:"tags"`
+}
+
+type ProductAttribute struct {
+	ProductID int32  `json:"product_id"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+}
+
+type ProductContentDraft struct {
+	ID          int32              `json:"id"`
+	ProductID   int32              `json:"product_id"`
+	Description string             `json:"description"`
+	SeoTitle    string             `json:"seo_title"`
+	Tags        []string           `json:"tags"`
+	Generator   string             `json:"generator"`
+	Status      ContentDraftStatus `json:"status"`
+	CreatedBy   pgtype.Int4        `json:"created_by"`
+	ReviewedBy  pgtype.Int4        `json:"reviewed_by"`
+	ReviewedAt  pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type ProductDemandForecast struct {
+	ProductID       int32              `json:"product_id"`
+	Stock           int32              `json:"stock"`
+	DailyDemand     float64            `json:"daily_demand"`
+	MovingAverage   float64            `json:"moving_average"`
+	ForecastError   float64            `json:"forecast_error"`
+	DaysOfCover     pgtype.Float8      `json:"days_of_cover"`
+	ReorderPoint    int32              `json:"reorder_point"`
+	ReorderQuantity int32              `json:"reorder_quantity"`
+	Method          string             `json:"method"`
+	HistoryDays     int32              `json:"history_days"`
+	ComputedAt      pgtype.Timestamptz `json:"computed_at"`
+}
+
+type ProductEmbedding struct {
+	ProductID   int32              `json:"product_id"`
+	Model       string             `json:"model"`
+	Embedding   []float32          `json:"embedding"`
+	ContentHash string             `json:"content_hash"`
+	EmbeddedAt  pgtype.Timestamptz `json:"embedded_at"`
 }
 
 type ProductImage struct {
+	ID          int32              `json:"id"`
+	ProductID   int32              `json:"product_id"`
+	Url         string             `json:"url"`
+	AltText     pgtype.Text        `json:"alt_text"`
+	IsPrimary   pgtype.Bool        `json:"is_primary"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	Position    int32              `json:"position"`
+	StoragePath pgtype.Text        `json:"storage_path"`
+	ContentHash pgtype.Text        `json:"content_hash"`
+}
+
+type ProductImageUpload struct {
+	ID          int32              `json:"id"`
+	ProductID   int32              `json:"product_id"`
+	StoragePath string             `json:"storage_path"`
+	ContentType string             `json:"content_type"`
+	MaxBytes    int64              `json:"max_bytes"`
+	AltText     pgtype.Text        `json:"alt_text"`
+	Status      ImageUploadStatus  `json:"status"`
+	ImageID     pgtype.Int4        `json:"image_id"`
+	CreatedBy   pgtype.Int4        `json:"created_by"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ClaimedAt   pgtype.Timestamptz `json:"claimed_at"`
+}
+
+type ProductImageVariant struct {
+	ImageID     int32  `json:"image_id"`
+	Name        string `json:"name"`
+	Format      string `json:"format"`
+	Width       int32  `json:"width"`
+	Height      int32  `json:"height"`
+	Url         string `json:"url"`
+	StoragePath string `json:"storage_path"`
+}
+
+type ProductPrice struct {
 	ID        int32              `json:"id"`
 	ProductID int32              `json:"product_id"`
-	Url       string             `json:"url"`
-	AltText   pgtype.Text        `json:"alt_text"`
-	IsPrimary pgtype.Bool        `json:"is_primary"`
+	Kind      ProductPriceKind   `json:"kind"`
+	Price     pgtype.Numeric     `json:"price"`
+	StartsAt  pgtype.Timestamptz `json:"starts_at"`
+	EndsAt    pgtype.Timestamptz `json:"ends_at"`
+	CreatedBy pgtype.Int4        `json:"created_by"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Currency  string             `json:"currency"`
+}
+
+type ProductRecommendation struct {
+	ProductID            int32              `json:"product_id"`
+	RecommendedProductID int32              `json:"recommended_product_id"`
+	Score                float32            `json:"score"`
+	CoPurchases          int32              `json:"co_purchases"`
+	ComputedAt           pgtype.Timestamptz `json:"computed_at"`
+}
+
+type ProductReview struct {
+	ID        int32              `json:"id"`
+	ProductID int32              `json:"product_id"`
+	UserID    int32              `json:"user_id"`
+	Rating    int32              `json:"rating"`
+	Body      string             `json:"body"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ProductReviewSummary struct {
+	ProductID     int32              `json:"product_id"`
+	ReviewCount   int32              `json:"review_count"`
+	AverageRating float64            `json:"average_rating"`
+	Sentiment     float64            `json:"sentiment"`
+	Pros          []string           `json:"pros"`
+	Cons          []string           `json:"cons"`
+	Quotes        []string           `json:"quotes"`
+	Summarizer    string             `json:"summarizer"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
 
 type RefreshToken struct {
@@ -189,6 +592,33 @@ type RefreshToken struct {
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type ReorderThreshold struct {
+	ID         int32              `json:"id"`
+	ProductID  pgtype.Int4        `json:"product_id"`
+	CategoryID pgtype.Int4        `json:"category_id"`
+	Threshold  int32              `json:"threshold"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StockAlert struct {
+	ProductID   int32              `json:"product_id"`
+	Type        string             `json:"type"`
+	Sku         string             `json:"sku"`
+	ProductName string             `json:"product_name"`
+	Stock       int32              `json:"stock"`
+	Threshold   int32              `json:"threshold"`
+	QueuedAt    pgtype.Timestamptz `json:"queued_at"`
+}
+
+type StockReservation struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	ProductID int32              `json:"product_id"`
+	Quantity  int32              `json:"quantity"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type User struct {
 	ID        int32              `json:"id"`
 	Email     string             `json:"email"`
@@ -202,3 +632,24 @@ type User struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
+
+type Warehouse struct {
+	ID         int32              `json:"id"`
+	Code       string             `json:"code"`
+	Name       string             `json:"name"`
+	Country    string             `json:"country"`
+	PostalCode pgtype.Text        `json:"postal_code"`
+	Latitude   pgtype.Float8      `json:"latitude"`
+	Longitude  pgtype.Float8      `json:"longitude"`
+	Priority   int32              `json:"priority"`
+	IsActive   bool               `json:"is_active"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WarehouseStock struct {
+	WarehouseID int32              `json:"warehouse_id"`
+	ProductID   int32              `json:"product_id"`
+	Quantity    int32              `json:"quantity"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
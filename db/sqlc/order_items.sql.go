@@ -122,6 +122,40 @@ func (q *Queries) ListOrderItems(ctx context.Context, orderID int32) ([]OrderIte
 	return items, nil
 }
 
+const listOrderItemsByOrderIDs = `-- name: ListOrderItemsByOrderIDs :many
+SELECT id, order_id, product_id, quantity, price, created_at, deleted_at FROM order_items
+WHERE order_id = ANY($1::int[]) AND deleted_at IS NULL
+ORDER BY order_id, created_at ASC
+`
+
+func (q *Queries) ListOrderItemsByOrderIDs(ctx context.Context, orderIds []int32) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, listOrderItemsByOrderIDs, orderIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderItem{}
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.Price,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const softDeleteOrderItem = `-- name: SoftDeleteOrderItem :exec
 UPDATE order_items
 SET deleted_at = CURRENT_TIMESTAMP
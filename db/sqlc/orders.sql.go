@@ -44,19 +44,43 @@ func (q *Queries) CountOrdersByUserID(ctx context.Context, userID int32) (int64,
 	return count, err
 }
 
+const countRecentOrdersByUser = `-- name: CountRecentOrdersByUser :one
+SELECT COUNT(*) FROM orders
+WHERE user_id = $1 AND created_at >= $2 AND deleted_at IS NULL
+`
+
+type CountRecentOrdersByUserParams struct {
+	UserID int32              `json:"user_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountRecentOrdersByUser(ctx context.Context, arg CountRecentOrdersByUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecentOrdersByUser, arg.UserID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createOrder = `-- name: CreateOrder :one
-INSERT INTO orders (user_id, total_amount)
-VALUES ($1, $2)
-RETURNING id, user_id, status, total_amount, created_at, updated_at, deleted_at
+INSERT INTO orders (user_id, total_amount, currency, exchange_rate)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate
 `
 
 type CreateOrderParams struct {
-	UserID      int32          `json:"user_id"`
-	TotalAmount pgtype.Numeric `json:"total_amount"`
+	UserID       int32          `json:"user_id"`
+	TotalAmount  pgtype.Numeric `json:"total_amount"`
+	Currency     string         `json:"currency"`
+	ExchangeRate pgtype.Numeric `json:"exchange_rate"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
-	row := q.db.QueryRow(ctx, createOrder, arg.UserID, arg.TotalAmount)
+	row := q.db.QueryRow(ctx, createOrder,
+		arg.UserID,
+		arg.TotalAmount,
+		arg.Currency,
+		arg.ExchangeRate,
+	)
 	var i Order
 	err := row.Scan(
 		&i.ID,
@@ -66,12 +90,14 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
 
 const getOrderByID = `-- name: GetOrderByID :one
-SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at FROM orders
+SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate FROM orders
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -86,12 +112,72 @@ func (q *Queries) GetOrderByID(ctx context.Context, id int32) (Order, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+	)
+	return i, err
+}
+
+const getOrderByIDForUpdate = `-- name: GetOrderByIDForUpdate :one
+SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate FROM orders
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderByIDForUpdate(ctx context.Context, id int32) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderByIDForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Currency,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
 
+const getOrdersByIDs = `-- name: GetOrdersByIDs :many
+SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate FROM orders
+WHERE id = ANY($1::int[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetOrdersByIDs(ctx context.Context, ids []int32) ([]Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.TotalAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOrders = `-- name: ListOrders :many
-SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at FROM orders
+SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate FROM orders
 WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -119,6 +205,8 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Currency,
+			&i.ExchangeRate,
 		); err != nil {
 			return nil, err
 		}
@@ -131,7 +219,7 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order
 }
 
 const listOrdersByStatus = `-- name: ListOrdersByStatus :many
-SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at FROM orders
+SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate FROM orders
 WHERE status = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -160,6 +248,8 @@ func (q *Queries) ListOrdersByStatus(ctx context.Context, arg ListOrdersByStatus
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Currency,
+			&i.ExchangeRate,
 		); err != nil {
 			return nil, err
 		}
@@ -172,7 +262,7 @@ func (q *Queries) ListOrdersByStatus(ctx context.Context, arg ListOrdersByStatus
 }
 
 const listOrdersByUserID = `-- name: ListOrdersByUserID :many
-SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at FROM orders
+SELECT id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate FROM orders
 WHERE user_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -201,6 +291,8 @@ func (q *Queries) ListOrdersByUserID(ctx context.Context, arg ListOrdersByUserID
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Currency,
+			&i.ExchangeRate,
 		); err != nil {
 			return nil, err
 		}
@@ -227,7 +319,7 @@ const updateOrderStatus = `-- name: UpdateOrderStatus :one
 UPDATE orders
 SET status = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, user_id, status, total_amount, created_at, updated_at, deleted_at
+RETURNING id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate
 `
 
 type UpdateOrderStatusParams struct {
@@ -246,6 +338,8 @@ func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusPa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
@@ -254,7 +348,7 @@ const updateOrderTotal = `-- name: UpdateOrderTotal :one
 UPDATE orders
 SET total_amount = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, user_id, status, total_amount, created_at, updated_at, deleted_at
+RETURNING id, user_id, status, total_amount, created_at, updated_at, deleted_at, currency, exchange_rate
 `
 
 type UpdateOrderTotalParams struct {
@@ -273,6 +367,8 @@ func (q *Queries) UpdateOrderTotal(ctx context.Context, arg UpdateOrderTotalPara
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
@@ -11,17 +11,59 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const clearPrimaryProductImage = `-- name: ClearPrimaryProductImage :exec
+UPDATE product_images
+SET is_primary = false
+WHERE product_id = $1 AND is_primary = true AND deleted_at IS NULL
+`
+
+func (q *Queries) ClearPrimaryProductImage(ctx context.Context, productID int32) error {
+	_, err := q.db.Exec(ctx, clearPrimaryProductImage, productID)
+	return err
+}
+
 const createProductImage = `-- name: CreateProductImage :one
-INSERT INTO product_images (product_id, url, alt_text, is_primary)
-VALUES ($1, $2, $3, $4)
-RETURNING id, product_id, url, alt_text, is_primary, created_at, deleted_at
+-- Appends an image to the product's images, along with its variants, and
+-- adds a reference to its content hash. It becomes the primary image only
+-- when the product has none. Callers lock the product row first, so images
+-- added at the same time take distinct positions and only one is primary.
+WITH image AS (
+    INSERT INTO product_images (product_id, url, alt_text, storage_path, content_hash, position, is_primary)
+    SELECT $1, $2, $3, $4, $5,
+           COALESCE(MAX(position) + 1, 0), COUNT(*) FILTER (WHERE is_primary) = 0
+    FROM product_images
+    WHERE product_id = $1 AND deleted_at IS NULL
+    RETURNING id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash
+), variants AS (
+    INSERT INTO product_image_variants (image_id, name, format, width, height, url, storage_path)
+    SELECT image.id, v.name, v.format, v.width, v.height, v.url, v.storage_path
+    FROM image, unnest(
+        $6::text[], $7::text[],
+        $8::int[], $9::int[],
+        $10::text[], $11::text[]
+    ) AS v(name, format, width, height, url, storage_path)
+), blob AS (
+    INSERT INTO image_blobs (content_hash, ref_count)
+    SELECT content_hash, 1 FROM image
+    WHERE content_hash IS NOT NULL
+    ON CONFLICT (content_hash) DO UPDATE
+    SET ref_count = image_blobs.ref_count + 1, updated_at = CURRENT_TIMESTAMP
+)
+SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash FROM image
 `
 
 type CreateProductImageParams struct {
-	ProductID int32       `json:"product_id"`
-	Url       string      `json:"url"`
-	AltText   pgtype.Text `json:"alt_text"`
-	IsPrimary pgtype.Bool `json:"is_primary"`
+	ProductID           int32       `json:"product_id"`
+	Url                 string      `json:"url"`
+	AltText             pgtype.Text `json:"alt_text"`
+	StoragePath         pgtype.Text `json:"storage_path"`
+	ContentHash         pgtype.Text `json:"content_hash"`
+	VariantNames        []string    `json:"variant_names"`
+	VariantFormats      []string    `json:"variant_formats"`
+	VariantWidths       []int32     `json:"variant_widths"`
+	VariantHeights      []int32     `json:"variant_heights"`
+	VariantUrls         []string    `json:"variant_urls"`
+	VariantStoragePaths []string    `json:"variant_storage_paths"`
 }
 
 func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error) {
@@ -29,7 +71,14 @@ func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImage
 		arg.ProductID,
 		arg.Url,
 		arg.AltText,
-		arg.IsPrimary,
+		arg.StoragePath,
+		arg.ContentHash,
+		arg.VariantNames,
+		arg.VariantFormats,
+		arg.VariantWidths,
+		arg.VariantHeights,
+		arg.VariantUrls,
+		arg.VariantStoragePaths,
 	)
 	var i ProductImage
 	err := row.Scan(
@@ -40,12 +89,15 @@ func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImage
 		&i.IsPrimary,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.Position,
+		&i.StoragePath,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const getPrimaryProductImage = `-- name: GetPrimaryProductImage :one
-SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at FROM product_images
+SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash FROM product_images
 WHERE product_id = $1 AND is_primary = true AND deleted_at IS NULL
 `
 
@@ -60,12 +112,15 @@ func (q *Queries) GetPrimaryProductImage(ctx context.Context, productID int32) (
 		&i.IsPrimary,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.Position,
+		&i.StoragePath,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const getProductImageByID = `-- name: GetProductImageByID :one
-SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at FROM product_images
+SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash FROM product_images
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -80,14 +135,17 @@ func (q *Queries) GetProductImageByID(ctx context.Context, id int32) (ProductIma
 		&i.IsPrimary,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.Position,
+		&i.StoragePath,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const listProductImages = `-- name: ListProductImages :many
-SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at FROM product_images
+SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash FROM product_images
 WHERE product_id = $1 AND deleted_at IS NULL
-ORDER BY is_primary DESC, created_at ASC
+ORDER BY position, id
 `
 
 func (q *Queries) ListProductImages(ctx context.Context, productID int32) ([]ProductImage, error) {
@@ -107,6 +165,9 @@ func (q *Queries) ListProductImages(ctx context.Context, productID int32) ([]Pro
 			&i.IsPrimary,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.Position,
+			&i.StoragePath,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -119,9 +180,9 @@ func (q *Queries) ListProductImages(ctx context.Context, productID int32) ([]Pro
 }
 
 const listProductImagesByProductIDs = `-- name: ListProductImagesByProductIDs :many
-SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at FROM product_images
+SELECT id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash FROM product_images
 WHERE product_id = ANY($1::int[]) AND deleted_at IS NULL
-ORDER BY product_id, is_primary DESC, created_at ASC
+ORDER BY product_id, position, id
 `
 
 func (q *Queries) ListProductImagesByProductIDs(ctx context.Context, dollar_1 []int32) ([]ProductImage, error) {
@@ -141,6 +202,9 @@ func (q *Queries) ListProductImagesByProductIDs(ctx context.Context, dollar_1 []
 			&i.IsPrimary,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.Position,
+			&i.StoragePath,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -152,10 +216,11 @@ func (q *Queries) ListProductImagesByProductIDs(ctx context.Context, dollar_1 []
 	return items, nil
 }
 
-const setPrimaryProductImage = `-- name: SetPrimaryProductImage :exec
+const setPrimaryProductImage = `-- name: SetPrimaryProductImage :execrows
+-- Clear the current primary image first; a product can only have one.
 UPDATE product_images
-SET is_primary = CASE WHEN id = $2 THEN true ELSE false END
-WHERE product_id = $1 AND deleted_at IS NULL
+SET is_primary = true
+WHERE product_id = $1 AND id = $2 AND deleted_at IS NULL
 `
 
 type SetPrimaryProductImageParams struct {
@@ -163,38 +228,123 @@ type SetPrimaryProductImageParams struct {
 	ID        int32 `json:"id"`
 }
 
-func (q *Queries) SetPrimaryProductImage(ctx context.Context, arg SetPrimaryProductImageParams) error {
-	_, err := q.db.Exec(ctx, setPrimaryProductImage, arg.ProductID, arg.ID)
-	return err
+func (q *Queries) SetPrimaryProductImage(ctx context.Context, arg SetPrimaryProductImageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPrimaryProductImage, arg.ProductID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const softDeleteProductImage = `-- name: SoftDeleteProductImage :exec
-UPDATE product_images
-SET deleted_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND deleted_at IS NULL
+const setProductImagePositions = `-- name: SetProductImagePositions :execrows
+-- Puts the product's images in the order of image_ids
+UPDATE product_images i
+SET position = ordered.ord - 1
+FROM unnest($1::int[]) WITH ORDINALITY AS ordered(id, ord)
+WHERE i.id = ordered.id AND i.product_id = $2 AND i.deleted_at IS NULL
 `
 
-func (q *Queries) SoftDeleteProductImage(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, softDeleteProductImage, id)
-	return err
+type SetProductImagePositionsParams struct {
+	ImageIds  []int32 `json:"image_ids"`
+	ProductID int32   `json:"product_id"`
 }
 
-const softDeleteProductImagesByProductID = `-- name: SoftDeleteProductImagesByProductID :exec
-UPDATE product_images
-SET deleted_at = CURRENT_TIMESTAMP
-WHERE product_id = $1 AND deleted_at IS NULL
+func (q *Queries) SetProductImagePositions(ctx context.Context, arg SetProductImagePositionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setProductImagePositions, arg.ImageIds, arg.ProductID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteProductImage = `-- name: SoftDeleteProductImage :many
+-- Also drops the image's reference to its content hash, returning the hash
+-- when no image uses it any more
+WITH deleted AS (
+    UPDATE product_images
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE id = $1 AND deleted_at IS NULL
+    RETURNING content_hash
+), released AS (
+    UPDATE image_blobs b
+    SET ref_count = b.ref_count - 1, updated_at = CURRENT_TIMESTAMP
+    FROM deleted
+    WHERE b.content_hash = deleted.content_hash
+    RETURNING b.content_hash, b.ref_count
+)
+SELECT content_hash FROM released
+WHERE ref_count = 0
 `
 
-func (q *Queries) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) error {
-	_, err := q.db.Exec(ctx, softDeleteProductImagesByProductID, productID)
-	return err
+func (q *Queries) SoftDeleteProductImage(ctx context.Context, id int32) ([]string, error) {
+	rows, err := q.db.Query(ctx, softDeleteProductImage, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var content_hash string
+		if err := rows.Scan(&content_hash); err != nil {
+			return nil, err
+		}
+		items = append(items, content_hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteProductImagesByProductID = `-- name: SoftDeleteProductImagesByProductID :many
+-- Also drops the images' references to their content hashes, returning the
+-- hashes no image uses any more
+WITH deleted AS (
+    UPDATE product_images
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE product_id = $1 AND deleted_at IS NULL
+    RETURNING content_hash
+), released AS (
+    UPDATE image_blobs b
+    SET ref_count = b.ref_count - counted.refs, updated_at = CURRENT_TIMESTAMP
+    FROM (
+        SELECT content_hash, COUNT(*) AS refs
+        FROM deleted
+        WHERE content_hash IS NOT NULL
+        GROUP BY content_hash
+    ) counted
+    WHERE b.content_hash = counted.content_hash
+    RETURNING b.content_hash, b.ref_count
+)
+SELECT content_hash FROM released
+WHERE ref_count = 0
+`
+
+func (q *Queries) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) ([]string, error) {
+	rows, err := q.db.Query(ctx, softDeleteProductImagesByProductID, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var content_hash string
+		if err := rows.Scan(&content_hash); err != nil {
+			return nil, err
+		}
+		items = append(items, content_hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const updateProductImage = `-- name: UpdateProductImage :one
 UPDATE product_images
 SET url = $2, alt_text = $3, is_primary = $4
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, product_id, url, alt_text, is_primary, created_at, deleted_at
+RETURNING id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash
 `
 
 type UpdateProductImageParams struct {
@@ -220,6 +370,40 @@ func (q *Queries) UpdateProductImage(ctx context.Context, arg UpdateProductImage
 		&i.IsPrimary,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.Position,
+		&i.StoragePath,
+		&i.ContentHash,
+	)
+	return i, err
+}
+
+const updateProductImageAltText = `-- name: UpdateProductImageAltText :one
+UPDATE product_images
+SET alt_text = $3
+WHERE product_id = $1 AND id = $2 AND deleted_at IS NULL
+RETURNING id, product_id, url, alt_text, is_primary, created_at, deleted_at, position, storage_path, content_hash
+`
+
+type UpdateProductImageAltTextParams struct {
+	ProductID int32       `json:"product_id"`
+	ID        int32       `json:"id"`
+	AltText   pgtype.Text `json:"alt_text"`
+}
+
+func (q *Queries) UpdateProductImageAltText(ctx context.Context, arg UpdateProductImageAltTextParams) (ProductImage, error) {
+	row := q.db.QueryRow(ctx, updateProductImageAltText, arg.ProductID, arg.ID, arg.AltText)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Url,
+		&i.AltText,
+		&i.IsPrimary,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.Position,
+		&i.StoragePath,
+		&i.ContentHash,
 	)
 	return i, err
 }
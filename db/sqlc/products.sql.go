@@ -11,6 +11,40 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const adjustProductStock = `-- name: AdjustProductStock :one
+UPDATE products
+SET stock = COALESCE(stock, 0) + $1::int, updated_at = CURRENT_TIMESTAMP
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags
+`
+
+type AdjustProductStockParams struct {
+	Delta int32 `json:"delta"`
+	ID    int32 `json:"id"`
+}
+
+func (q *Queries) AdjustProductStock(ctx context.Context, arg AdjustProductStockParams) (Product, error) {
+	row := q.db.QueryRow(ctx, adjustProductStock, arg.Delta, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.CategoryID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.Stock,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
+	)
+	return i, err
+}
+
 const countActiveProducts = `-- name: CountActiveProducts :one
 SELECT COUNT(*) FROM products WHERE is_active = true AND deleted_at IS NULL
 `
@@ -45,28 +79,49 @@ func (q *Queries) CountProductsByCategory(ctx context.Context, categoryID int32)
 }
 
 const countSearchProducts = `-- name: CountSearchProducts :one
-SELECT COUNT(*) FROM products
-WHERE search_vector @@ plainto_tsquery('english', $1)
-  AND is_active = true
-  AND deleted_at IS NULL
-  AND ($2::int IS NULL OR category_id = $2::int)
-  AND ($3::numeric IS NULL OR price >= $3::numeric)
-  AND ($4::numeric IS NULL OR price <= $4::numeric)
+SELECT COUNT(*)
+FROM search_product_matches(
+  $1::text,
+  $2::bool,
+  $3::int[],
+  $4::numeric,
+  $5::numeric,
+  $6::bool,
+  $7::text[],
+  $8::text[],
+  $9::text,
+  $10::text,
+  $11::numeric
+) m
 `
 
 type CountSearchProductsParams struct {
-	PlaintoTsquery string         `json:"plainto_tsquery"`
-	CategoryID     pgtype.Int4    `json:"category_id"`
-	MinPrice       pgtype.Numeric `json:"min_price"`
-	MaxPrice       pgtype.Numeric `json:"max_price"`
+	Query           string         `json:"query"`
+	Fuzzy           bool           `json:"fuzzy"`
+	CategoryIds     []int32        `json:"category_ids"`
+	MinPrice        pgtype.Numeric `json:"min_price"`
+	MaxPrice        pgtype.Numeric `json:"max_price"`
+	InStock         pgtype.Bool    `json:"in_stock"`
+	AttributeNames  []string       `json:"attribute_names"`
+	AttributeValues []string       `json:"attribute_values"`
+	BaseCurrency    string         `json:"base_currency"`
+	Currency        string         `json:"currency"`
+	Rate            pgtype.Numeric `json:"rate"`
 }
 
 func (q *Queries) CountSearchProducts(ctx context.Context, arg CountSearchProductsParams) (int64, error) {
 	row := q.db.QueryRow(ctx, countSearchProducts,
-		arg.PlaintoTsquery,
-		arg.CategoryID,
+		arg.Query,
+		arg.Fuzzy,
+		arg.CategoryIds,
 		arg.MinPrice,
 		arg.MaxPrice,
+		arg.InStock,
+		arg.AttributeNames,
+		arg.AttributeValues,
+		arg.BaseCurrency,
+		arg.Currency,
+		arg.Rate,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -76,7 +131,7 @@ func (q *Queries) CountSearchProducts(ctx context.Context, arg CountSearchProduc
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (category_id, name, description, price, stock, sku)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector
+RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags
 `
 
 type CreateProductParams struct {
@@ -94,7 +149,6 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		arg.Name,
 		arg.Description,
 		arg.Price,
-		arg.Stock,
 		arg.Sku,
 	)
 	var i Product
@@ -111,12 +165,14 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -136,12 +192,14 @@ func (q *Queries) GetProductByID(ctx context.Context, id int32) (Product, error)
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProductByIDForUpdate = `-- name: GetProductByIDForUpdate :one
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE id = $1 AND deleted_at IS NULL
 FOR UPDATE
 `
@@ -162,12 +220,14 @@ func (q *Queries) GetProductByIDForUpdate(ctx context.Context, id int32) (Produc
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProductBySKU = `-- name: GetProductBySKU :one
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE sku = $1 AND deleted_at IS NULL
 `
 
@@ -187,12 +247,14 @@ func (q *Queries) GetProductBySKU(ctx context.Context, sku string) (Product, err
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const getProductsByIDs = `-- name: GetProductsByIDs :many
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE id = ANY($1::int[]) AND deleted_at IS NULL
 `
 
@@ -218,6 +280,8 @@ func (q *Queries) GetProductsByIDs(ctx context.Context, dollar_1 []int32) ([]Pro
 			&i.UpdatedAt,
 			&i.DeletedAt,
 			&i.SearchVector,
+			&i.SeoTitle,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -230,7 +294,7 @@ func (q *Queries) GetProductsByIDs(ctx context.Context, dollar_1 []int32) ([]Pro
 }
 
 const getProductsByIDsForUpdate = `-- name: GetProductsByIDsForUpdate :many
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE id = ANY($1::int[]) AND deleted_at IS NULL
 FOR UPDATE
 `
@@ -257,6 +321,8 @@ func (q *Queries) GetProductsByIDsForUpdate(ctx context.Context, dollar_1 []int3
 			&i.UpdatedAt,
 			&i.DeletedAt,
 			&i.SearchVector,
+			&i.SeoTitle,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -269,7 +335,7 @@ func (q *Queries) GetProductsByIDsForUpdate(ctx context.Context, dollar_1 []int3
 }
 
 const listActiveProducts = `-- name: ListActiveProducts :many
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE is_active = true AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -302,6 +368,57 @@ func (q *Queries) ListActiveProducts(ctx context.Context, arg ListActiveProducts
 			&i.UpdatedAt,
 			&i.DeletedAt,
 			&i.SearchVector,
+			&i.SeoTitle,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoryTrainingProducts = `-- name: ListCategoryTrainingProducts :many
+-- Products with the category they are filed under, for learning what the
+-- products of each category are called. Products in deleted or inactive
+-- categories are left out.
+SELECT p.id, p.name, p.description, p.sku, p.category_id, c.name AS category_name
+FROM products p
+JOIN categories c ON c.id = p.category_id
+WHERE p.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND c.is_active = true
+ORDER BY p.id
+`
+
+type ListCategoryTrainingProductsRow struct {
+	ID           int32       `json:"id"`
+	Name         string      `json:"name"`
+	Description  pgtype.Text `json:"description"`
+	Sku          string      `json:"sku"`
+	CategoryID   int32       `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+}
+
+func (q *Queries) ListCategoryTrainingProducts(ctx context.Context) ([]ListCategoryTrainingProductsRow, error) {
+	rows, err := q.db.Query(ctx, listCategoryTrainingProducts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCategoryTrainingProductsRow{}
+	for rows.Next() {
+		var i ListCategoryTrainingProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Sku,
+			&i.CategoryID,
+			&i.CategoryName,
 		); err != nil {
 			return nil, err
 		}
@@ -314,7 +431,7 @@ func (q *Queries) ListActiveProducts(ctx context.Context, arg ListActiveProducts
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -347,6 +464,8 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 			&i.UpdatedAt,
 			&i.DeletedAt,
 			&i.SearchVector,
+			&i.SeoTitle,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -359,7 +478,7 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 }
 
 const listProductsByCategory = `-- name: ListProductsByCategory :many
-SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector FROM products
+SELECT id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags FROM products
 WHERE category_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -393,6 +512,8 @@ func (q *Queries) ListProductsByCategory(ctx context.Context, arg ListProductsBy
 			&i.UpdatedAt,
 			&i.DeletedAt,
 			&i.SearchVector,
+			&i.SeoTitle,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -404,54 +525,324 @@ func (q *Queries) ListProductsByCategory(ctx context.Context, arg ListProductsBy
 	return items, nil
 }
 
+const listSearchTermWords = `-- name: ListSearchTermWords :many
+-- The distinct words in active product names, which misspelt search terms
+-- are corrected to
+SELECT DISTINCT w.word::text AS word
+FROM products p
+CROSS JOIN LATERAL regexp_split_to_table(lower(p.name), '[^[:alnum:]]+') AS w(word)
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND w.word <> ''
+ORDER BY word
+`
+
+func (q *Queries) ListSearchTermWords(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listSearchTermWords)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		items = append(items, word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAttributeFacets = `-- name: SearchAttributeFacets :many
+SELECT a.name, a.value, COUNT(*) AS count
+FROM search_product_matches(
+  $1::text,
+  $2::bool,
+  $3::int[],
+  $4::numeric,
+  $5::numeric,
+  $6::bool,
+  $7::text[],
+  $8::text[],
+  $9::text,
+  $10::text,
+  $11::numeric
+) m
+JOIN product_attributes a ON a.product_id = m.product_id
+WHERE ($12::text IS NULL OR a.name = $12::text)
+  AND NOT (a.name = ANY($13::text[]))
+GROUP BY a.name, a.value
+ORDER BY a.name, count DESC, a.value
+`
+
+type SearchAttributeFacetsParams struct {
+	Query           string         `json:"query"`
+	Fuzzy           bool           `json:"fuzzy"`
+	CategoryIds     []int32        `json:"category_ids"`
+	MinPrice        pgtype.Numeric `json:"min_price"`
+	MaxPrice        pgtype.Numeric `json:"max_price"`
+	InStock         pgtype.Bool    `json:"in_stock"`
+	AttributeNames  []string       `json:"attribute_names"`
+	AttributeValues []string       `json:"attribute_values"`
+	BaseCurrency    string         `json:"base_currency"`
+	Currency        string         `json:"currency"`
+	Rate            pgtype.Numeric `json:"rate"`
+	FacetName       pgtype.Text    `json:"facet_name"`
+	ExcludeNames    []string       `json:"exclude_names"`
+}
+
+type SearchAttributeFacetsRow struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) SearchAttributeFacets(ctx context.Context, arg SearchAttributeFacetsParams) ([]SearchAttributeFacetsRow, error) {
+	rows, err := q.db.Query(ctx, searchAttributeFacets,
+		arg.Query,
+		arg.Fuzzy,
+		arg.CategoryIds,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.AttributeNames,
+		arg.AttributeValues,
+		arg.BaseCurrency,
+		arg.Currency,
+		arg.Rate,
+		arg.FacetName,
+		arg.ExcludeNames,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchAttributeFacetsRow{}
+	for rows.Next() {
+		var i SearchAttributeFacetsRow
+		if err := rows.Scan(&i.Name, &i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCategoryFacets = `-- name: SearchCategoryFacets :many
+SELECT p.category_id, COUNT(*) AS count
+FROM search_product_matches(
+  $1::text,
+  $2::bool,
+  $3::int[],
+  $4::numeric,
+  $5::numeric,
+  $6::bool,
+  $7::text[],
+  $8::text[],
+  $9::text,
+  $10::text,
+  $11::numeric
+) m
+JOIN products p ON p.id = m.product_id
+GROUP BY p.category_id
+ORDER BY count DESC, p.category_id
+`
+
+type SearchCategoryFacetsParams struct {
+	Query           string         `json:"query"`
+	Fuzzy           bool           `json:"fuzzy"`
+	CategoryIds     []int32        `json:"category_ids"`
+	MinPrice        pgtype.Numeric `json:"min_price"`
+	MaxPrice        pgtype.Numeric `json:"max_price"`
+	InStock         pgtype.Bool    `json:"in_stock"`
+	AttributeNames  []string       `json:"attribute_names"`
+	AttributeValues []string       `json:"attribute_values"`
+	BaseCurrency    string         `json:"base_currency"`
+	Currency        string         `json:"currency"`
+	Rate            pgtype.Numeric `json:"rate"`
+}
+
+type SearchCategoryFacetsRow struct {
+	CategoryID int32 `json:"category_id"`
+	Count      int64 `json:"count"`
+}
+
+func (q *Queries) SearchCategoryFacets(ctx context.Context, arg SearchCategoryFacetsParams) ([]SearchCategoryFacetsRow, error) {
+	rows, err := q.db.Query(ctx, searchCategoryFacets,
+		arg.Query,
+		arg.Fuzzy,
+		arg.CategoryIds,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.AttributeNames,
+		arg.AttributeValues,
+		arg.BaseCurrency,
+		arg.Currency,
+		arg.Rate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchCategoryFacetsRow{}
+	for rows.Next() {
+		var i SearchCategoryFacetsRow
+		if err := rows.Scan(&i.CategoryID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPriceFacets = `-- name: SearchPriceFacets :many
+SELECT width_bucket(m.price, $1::numeric[]) AS bucket, COUNT(*) AS count
+FROM search_product_matches(
+  $2::text,
+  $3::bool,
+  $4::int[],
+  $5::numeric,
+  $6::numeric,
+  $7::bool,
+  $8::text[],
+  $9::text[],
+  $10::text,
+  $11::text,
+  $12::numeric
+) m
+GROUP BY bucket
+ORDER BY bucket
+`
+
+type SearchPriceFacetsParams struct {
+	Bounds          []pgtype.Numeric `json:"bounds"`
+	Query           string           `json:"query"`
+	Fuzzy           bool             `json:"fuzzy"`
+	CategoryIds     []int32          `json:"category_ids"`
+	MinPrice        pgtype.Numeric   `json:"min_price"`
+	MaxPrice        pgtype.Numeric   `json:"max_price"`
+	InStock         pgtype.Bool      `json:"in_stock"`
+	AttributeNames  []string         `json:"attribute_names"`
+	AttributeValues []string         `json:"attribute_values"`
+	BaseCurrency    string           `json:"base_currency"`
+	Currency        string           `json:"currency"`
+	Rate            pgtype.Numeric   `json:"rate"`
+}
+
+type SearchPriceFacetsRow struct {
+	Bucket int32 `json:"bucket"`
+	Count  int64 `json:"count"`
+}
+
+func (q *Queries) SearchPriceFacets(ctx context.Context, arg SearchPriceFacetsParams) ([]SearchPriceFacetsRow, error) {
+	rows, err := q.db.Query(ctx, searchPriceFacets,
+		arg.Bounds,
+		arg.Query,
+		arg.Fuzzy,
+		arg.CategoryIds,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.AttributeNames,
+		arg.AttributeValues,
+		arg.BaseCurrency,
+		arg.Currency,
+		arg.Rate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchPriceFacetsRow{}
+	for rows.Next() {
+		var i SearchPriceFacetsRow
+		if err := rows.Scan(&i.Bucket, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchProducts = `-- name: SearchProducts :many
-SELECT
-  id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector,
-  ts_rank(search_vector, plainto_tsquery('english', $1)) as rank
-FROM products
-WHERE search_vector @@ plainto_tsquery('english', $1)
-  AND is_active = true
-  AND deleted_at IS NULL
-  AND ($4::int IS NULL OR category_id = $4::int)
-  AND ($5::numeric IS NULL OR price >= $5::numeric)
-  AND ($6::numeric IS NULL OR price <= $6::numeric)
-ORDER BY rank DESC
-LIMIT $2 OFFSET $3
+-- The search filters live in search_product_matches, which every search and
+-- facet query reads; prices are in the quoted currency
+SELECT p.id, p.category_id, p.name, p.description, p.price, p.stock, p.sku, p.is_active, p.created_at, p.updated_at, p.deleted_at, p.search_vector, p.seo_title, p.tags, m.rank
+FROM search_product_matches(
+  $1::text,
+  $2::bool,
+  $3::int[],
+  $4::numeric,
+  $5::numeric,
+  $6::bool,
+  $7::text[],
+  $8::text[],
+  $9::text,
+  $10::text,
+  $11::numeric
+) m
+JOIN products p ON p.id = m.product_id
+ORDER BY
+  CASE WHEN $12::text = 'price_asc' THEN m.price END ASC,
+  CASE WHEN $12::text = 'price_desc' THEN m.price END DESC,
+  CASE WHEN $12::text = 'newest' THEN p.created_at END DESC,
+  m.rank DESC,
+  p.id DESC
+LIMIT $13 OFFSET $14
 `
 
 type SearchProductsParams struct {
-	PlaintoTsquery string         `json:"plainto_tsquery"`
-	Limit          int32          `json:"limit"`
-	Offset         int32          `json:"offset"`
-	CategoryID     pgtype.Int4    `json:"category_id"`
-	MinPrice       pgtype.Numeric `json:"min_price"`
-	MaxPrice       pgtype.Numeric `json:"max_price"`
+	Query           string         `json:"query"`
+	Fuzzy           bool           `json:"fuzzy"`
+	CategoryIds     []int32        `json:"category_ids"`
+	MinPrice        pgtype.Numeric `json:"min_price"`
+	MaxPrice        pgtype.Numeric `json:"max_price"`
+	InStock         pgtype.Bool    `json:"in_stock"`
+	AttributeNames  []string       `json:"attribute_names"`
+	AttributeValues []string       `json:"attribute_values"`
+	BaseCurrency    string         `json:"base_currency"`
+	Currency        string         `json:"currency"`
+	Rate            pgtype.Numeric `json:"rate"`
+	Sort            string         `json:"sort"`
+	Limit           int32          `json:"limit"`
+	Offset          int32          `json:"offset"`
 }
 
 type SearchProductsRow struct {
-	ID           int32              `json:"id"`
-	CategoryID   int32              `json:"category_id"`
-	Name         string             `json:"name"`
-	Description  pgtype.Text        `json:"description"`
-	Price        pgtype.Numeric     `json:"price"`
-	Stock        pgtype.Int4        `json:"stock"`
-	Sku          string             `json:"sku"`
-	IsActive     pgtype.Bool        `json:"is_active"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
-	SearchVector interface{}        `json:"search_vector"`
-	Rank         float32            `json:"rank"`
+	Product Product `json:"product"`
+	Rank    float32 `json:"rank"`
 }
 
 func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
 	rows, err := q.db.Query(ctx, searchProducts,
-		arg.PlaintoTsquery,
-		arg.Limit,
-		arg.Offset,
-		arg.CategoryID,
+		arg.Query,
+		arg.Fuzzy,
+		arg.CategoryIds,
 		arg.MinPrice,
 		arg.MaxPrice,
+		arg.InStock,
+		arg.AttributeNames,
+		arg.AttributeValues,
+		arg.BaseCurrency,
+		arg.Currency,
+		arg.Rate,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
 	)
 	if err != nil {
 		return nil, err
@@ -461,18 +852,20 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 	for rows.Next() {
 		var i SearchProductsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CategoryID,
-			&i.Name,
-			&i.Description,
-			&i.Price,
-			&i.Stock,
-			&i.Sku,
-			&i.IsActive,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.DeletedAt,
-			&i.SearchVector,
+			&i.Product.ID,
+			&i.Product.CategoryID,
+			&i.Product.Name,
+			&i.Product.Description,
+			&i.Product.Price,
+			&i.Product.Stock,
+			&i.Product.Sku,
+			&i.Product.IsActive,
+			&i.Product.CreatedAt,
+			&i.Product.UpdatedAt,
+			&i.Product.DeletedAt,
+			&i.Product.SearchVector,
+			&i.Product.SeoTitle,
+			&i.Product.Tags,
 			&i.Rank,
 		); err != nil {
 			return nil, err
@@ -485,6 +878,62 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 	return items, nil
 }
 
+const searchStockFacets = `-- name: SearchStockFacets :one
+SELECT COUNT(*) FILTER (WHERE m.available > 0) AS in_stock,
+       COUNT(*) FILTER (WHERE m.available <= 0) AS out_of_stock
+FROM search_product_matches(
+  $1::text,
+  $2::bool,
+  $3::int[],
+  $4::numeric,
+  $5::numeric,
+  $6::bool,
+  $7::text[],
+  $8::text[],
+  $9::text,
+  $10::text,
+  $11::numeric
+) m
+`
+
+type SearchStockFacetsParams struct {
+	Query           string         `json:"query"`
+	Fuzzy           bool           `json:"fuzzy"`
+	CategoryIds     []int32        `json:"category_ids"`
+	MinPrice        pgtype.Numeric `json:"min_price"`
+	MaxPrice        pgtype.Numeric `json:"max_price"`
+	InStock         pgtype.Bool    `json:"in_stock"`
+	AttributeNames  []string       `json:"attribute_names"`
+	AttributeValues []string       `json:"attribute_values"`
+	BaseCurrency    string         `json:"base_currency"`
+	Currency        string         `json:"currency"`
+	Rate            pgtype.Numeric `json:"rate"`
+}
+
+type SearchStockFacetsRow struct {
+	InStock    int64 `json:"in_stock"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
+
+func (q *Queries) SearchStockFacets(ctx context.Context, arg SearchStockFacetsParams) (SearchStockFacetsRow, error) {
+	row := q.db.QueryRow(ctx, searchStockFacets,
+		arg.Query,
+		arg.Fuzzy,
+		arg.CategoryIds,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.AttributeNames,
+		arg.AttributeValues,
+		arg.BaseCurrency,
+		arg.Currency,
+		arg.Rate,
+	)
+	var i SearchStockFacetsRow
+	err := row.Scan(&i.InStock, &i.OutOfStock)
+	return i, err
+}
+
 const softDeleteProduct = `-- name: SoftDeleteProduct :exec
 UPDATE products
 SET deleted_at = CURRENT_TIMESTAMP
@@ -496,11 +945,64 @@ func (q *Queries) SoftDeleteProduct(ctx context.Context, id int32) error {
 	return err
 }
 
+const suggestProducts = `-- name: SuggestProducts :many
+-- Names starting with the prefix, or with a word starting with it, come
+-- first; names only similar to it follow
+SELECT p.id, p.name, p.sku
+FROM products p
+WHERE p.is_active = true
+  AND p.deleted_at IS NULL
+  AND (
+    p.name ILIKE $1::text || '%'
+    OR p.name ILIKE '% ' || $1::text || '%'
+    OR word_similarity($2::text, p.name) >= 0.3
+  )
+ORDER BY
+  p.name ILIKE $1::text || '%' DESC,
+  p.name ILIKE '% ' || $1::text || '%' DESC,
+  word_similarity($2::text, p.name) DESC,
+  p.name
+LIMIT $3
+`
+
+type SuggestProductsParams struct {
+	Pattern string `json:"pattern"`
+	Prefix  string `json:"prefix"`
+	Limit   int32  `json:"limit"`
+}
+
+type SuggestProductsRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Sku  string `json:"sku"`
+}
+
+func (q *Queries) SuggestProducts(ctx context.Context, arg SuggestProductsParams) ([]SuggestProductsRow, error) {
+	rows, err := q.db.Query(ctx, suggestProducts, arg.Pattern, arg.Prefix, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SuggestProductsRow{}
+	for rows.Next() {
+		var i SuggestProductsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Sku); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateProduct = `-- name: UpdateProduct :one
+-- Stock is left alone; it only changes through inventory movements
 UPDATE products
-SET category_id = $2, name = $3, description = $4, price = $5, stock = $6, sku = $7, updated_at = CURRENT_TIMESTAMP
+SET category_id = $2, name = $3, description = $4, price = $5, sku = $6, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector
+RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags
 `
 
 type UpdateProductParams struct {
@@ -509,7 +1011,6 @@ type UpdateProductParams struct {
 	Name        string         `json:"name"`
 	Description pgtype.Text    `json:"description"`
 	Price       pgtype.Numeric `json:"price"`
-	Stock       pgtype.Int4    `json:"stock"`
 	Sku         string         `json:"sku"`
 }
 
@@ -520,7 +1021,6 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		arg.Name,
 		arg.Description,
 		arg.Price,
-		arg.Stock,
 		arg.Sku,
 	)
 	var i Product
@@ -537,15 +1037,34 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
 
+const updateProductContent = `-- name: UpdateProductContent :exec
+UPDATE products
+SET seo_title = $2, tags = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type UpdateProductContentParams struct {
+	ID       int32       `json:"id"`
+	SeoTitle pgtype.Text `json:"seo_title"`
+	Tags     []string    `json:"tags"`
+}
+
+func (q *Queries) UpdateProductContent(ctx context.Context, arg UpdateProductContentParams) error {
+	_, err := q.db.Exec(ctx, updateProductContent, arg.ID, arg.SeoTitle, arg.Tags)
+	return err
+}
+
 const updateProductStatus = `-- name: UpdateProductStatus :one
 UPDATE products
 SET is_active = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector
+RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags
 `
 
 type UpdateProductStatusParams struct {
@@ -569,6 +1088,8 @@ func (q *Queries) UpdateProductStatus(ctx context.Context, arg UpdateProductStat
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
@@ -577,7 +1098,7 @@ const updateProductStock = `-- name: UpdateProductStock :one
 UPDATE products
 SET stock = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector
+RETURNING id, category_id, name, description, price, stock, sku, is_active, created_at, updated_at, deleted_at, search_vector, seo_title, tags
 `
 
 type UpdateProductStockParams struct {
@@ -601,6 +1122,8 @@ func (q *Queries) UpdateProductStock(ctx context.Context, arg UpdateProductStock
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SearchVector,
+		&i.SeoTitle,
+		&i.Tags,
 	)
 	return i, err
 }
@@ -11,67 +11,173 @@ import (
 )
 
 type Querier interface {
+	AddOrderRiskSignals(ctx context.Context, arg AddOrderRiskSignalsParams) error
+	AddProductAttributes(ctx context.Context, arg AddProductAttributesParams) error
+	AdjustProductStock(ctx context.Context, arg AdjustProductStockParams) (Product, error)
+	AdjustWarehouseStock(ctx context.Context, arg AdjustWarehouseStockParams) (WarehouseStock, error)
+	ClaimAssistantConversation(ctx context.Context, arg ClaimAssistantConversationParams) (AssistantConversation, error)
+	ClaimBackInStockSubscribers(ctx context.Context, arg ClaimBackInStockSubscribersParams) ([]ClaimBackInStockSubscribersRow, error)
+	ClaimProductImageUpload(ctx context.Context, arg ClaimProductImageUploadParams) (ProductImageUpload, error)
+	ClearPrimaryProductImage(ctx context.Context, productID int32) error
+	CompleteProductImageUpload(ctx context.Context, arg CompleteProductImageUploadParams) (ProductImageUpload, error)
 	CountActiveProducts(ctx context.Context) (int64, error)
 	CountCartItems(ctx context.Context, cartID int32) (int64, error)
 	CountCategories(ctx context.Context) (int64, error)
+	CountDemandForecasts(ctx context.Context) (int64, error)
+	CountInventoryMovementsByProduct(ctx context.Context, productID int32) (int64, error)
 	CountOrderItems(ctx context.Context, orderID int32) (int64, error)
 	CountOrders(ctx context.Context) (int64, error)
 	CountOrdersByStatus(ctx context.Context, status NullOrderStatus) (int64, error)
 	CountOrdersByUserID(ctx context.Context, userID int32) (int64, error)
+	CountProductReviews(ctx context.Context, productID int32) (int64, error)
 	CountProducts(ctx context.Context) (int64, error)
 	CountProductsByCategory(ctx context.Context, categoryID int32) (int64, error)
+	CountRecentFailedLogins(ctx context.Context, arg CountRecentFailedLoginsParams) (int64, error)
+	CountRecentOrdersByIP(ctx context.Context, arg CountRecentOrdersByIPParams) (int64, error)
+	CountRecentOrdersByUser(ctx context.Context, arg CountRecentOrdersByUserParams) (int64, error)
 	CountSearchProducts(ctx context.Context, arg CountSearchProductsParams) (int64, error)
+	CountSemanticSearchProducts(ctx context.Context, arg CountSemanticSearchProductsParams) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
+	CreateBackInStockSubscription(ctx context.Context, arg CreateBackInStockSubscriptionParams) (BackInStockSubscription, error)
 	CreateCart(ctx context.Context, userID int32) (Cart, error)
 	CreateCartItem(ctx context.Context, arg CreateCartItemParams) (CartItem, error)
 	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
 	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (OrderIdempotencyKey, error)
+	CreateInventoryMovement(ctx context.Context, arg CreateInventoryMovementParams) (InventoryMovement, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
+	CreateOrderItemAllocation(ctx context.Context, arg CreateOrderItemAllocationParams) (OrderItemAllocation, error)
+	CreateOrderRiskAssessment(ctx context.Context, arg CreateOrderRiskAssessmentParams) (OrderRiskAssessment, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreateProductContentDraft(ctx context.Context, arg CreateProductContentDraftParams) (ProductContentDraft, error)
 	CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error)
+	CreateProductImageUpload(ctx context.Context, arg CreateProductImageUploadParams) (ProductImageUpload, error)
+	CreateProductPrice(ctx context.Context, arg CreateProductPriceParams) (ProductPrice, error)
+	CreateProductReview(ctx context.Context, arg CreateProductReviewParams) (ProductReview, error)
+	CreateProductView(ctx context.Context, arg CreateProductViewParams) error
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWarehouse(ctx context.Context, arg CreateWarehouseParams) (Warehouse, error)
+	DeleteCategoryReorderThreshold(ctx context.Context, categoryID pgtype.Int4) error
+	DeleteDemandForecasts(ctx context.Context) error
 	DeleteExpiredRefreshTokens(ctx context.Context) error
+	DeleteExpiredStockReservations(ctx context.Context) (int64, error)
+	DeletePendingBackInStockSubscription(ctx context.Context, arg DeletePendingBackInStockSubscriptionParams) (int64, error)
+	DeleteProductAttributes(ctx context.Context, productID int32) error
+	DeleteProductRecommendations(ctx context.Context) error
+	DeleteProductReorderThreshold(ctx context.Context, productID pgtype.Int4) error
+	DeleteQuietAssistantConversations(ctx context.Context, arg DeleteQuietAssistantConversationsParams) error
 	DeleteRefreshToken(ctx context.Context, token string) error
 	DeleteRefreshTokensByUserID(ctx context.Context, userID int32) error
+	DeleteReleasedImageBlobs(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error)
+	DeleteSentStockAlerts(ctx context.Context, arg DeleteSentStockAlertsParams) error
+	DeleteStockReservationsByUser(ctx context.Context, userID int32) error
+	EndProductSalePrice(ctx context.Context, arg EndProductSalePriceParams) (ProductPrice, error)
+	ExpireProductImageUploads(ctx context.Context, arg ExpireProductImageUploadsParams) ([]ProductImageUpload, error)
+	FailProductImageUpload(ctx context.Context, arg FailProductImageUploadParams) error
 	GetCartByID(ctx context.Context, id int32) (Cart, error)
 	GetCartByUserID(ctx context.Context, userID int32) (Cart, error)
 	GetCartItem(ctx context.Context, arg GetCartItemParams) (CartItem, error)
 	GetCartItemByID(ctx context.Context, id int32) (CartItem, error)
 	GetCategoriesByIDs(ctx context.Context, dollar_1 []int32) ([]Category, error)
 	GetCategoryByID(ctx context.Context, id int32) (Category, error)
+	GetDefaultWarehouse(ctx context.Context) (Warehouse, error)
+	GetEffectivePrices(ctx context.Context, arg GetEffectivePricesParams) ([]GetEffectivePricesRow, error)
 	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (OrderIdempotencyKey, error)
+	GetImageBlobForUpdate(ctx context.Context, contentHash string) (ImageBlob, error)
 	GetOrderByID(ctx context.Context, id int32) (Order, error)
+	GetOrderByIDForUpdate(ctx context.Context, id int32) (Order, error)
 	GetOrderItemByID(ctx context.Context, id int32) (OrderItem, error)
+	GetOrderRiskAssessment(ctx context.Context, orderID int32) (OrderRiskAssessment, error)
 	GetOrderTotal(ctx context.Context, orderID int32) (pgtype.Numeric, error)
+	GetOrdersByIDs(ctx context.Context, ids []int32) ([]Order, error)
+	GetPendingBackInStockSubscription(ctx context.Context, arg GetPendingBackInStockSubscriptionParams) (BackInStockSubscription, error)
 	GetPrimaryProductImage(ctx context.Context, productID int32) (ProductImage, error)
 	GetProductByID(ctx context.Context, id int32) (Product, error)
 	GetProductByIDForUpdate(ctx context.Context, id int32) (Product, error)
 	GetProductBySKU(ctx context.Context, sku string) (Product, error)
+	GetProductContentDraft(ctx context.Context, id int32) (ProductContentDraft, error)
 	GetProductImageByID(ctx context.Context, id int32) (ProductImage, error)
+	GetProductImageUpload(ctx context.Context, id int32) (ProductImageUpload, error)
+	GetProductReviewStats(ctx context.Context, productID int32) (GetProductReviewStatsRow, error)
+	GetProductReviewSummary(ctx context.Context, productID int32) (ProductReviewSummary, error)
 	GetProductsByIDs(ctx context.Context, dollar_1 []int32) ([]Product, error)
 	GetProductsByIDsForUpdate(ctx context.Context, dollar_1 []int32) ([]Product, error)
 	GetRefreshToken(ctx context.Context, token string) (RefreshToken, error)
 	GetRefreshTokensByUserID(ctx context.Context, userID int32) ([]RefreshToken, error)
+	GetReorderThresholdsByProductIDs(ctx context.Context, arg GetReorderThresholdsByProductIDsParams) ([]GetReorderThresholdsByProductIDsRow, error)
+	GetReservedStockByProductIDs(ctx context.Context, arg GetReservedStockByProductIDsParams) ([]GetReservedStockByProductIDsRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
+	GetWarehouseByCode(ctx context.Context, code string) (Warehouse, error)
+	GetWarehouseStockForUpdate(ctx context.Context, arg GetWarehouseStockForUpdateParams) (WarehouseStock, error)
+	InsertDemandForecasts(ctx context.Context, arg InsertDemandForecastsParams) (int64, error)
+	InsertProductRecommendations(ctx context.Context, arg InsertProductRecommendationsParams) (int64, error)
 	ListActiveCategories(ctx context.Context) ([]Category, error)
 	ListActiveProducts(ctx context.Context, arg ListActiveProductsParams) ([]Product, error)
+	ListActiveStockReservationsByUser(ctx context.Context, userID int32) ([]StockReservation, error)
+	ListAllocatableStockForUpdate(ctx context.Context, dollar_1 []int32) ([]ListAllocatableStockForUpdateRow, error)
+	ListAttributeValues(ctx context.Context) ([]ListAttributeValuesRow, error)
+	ListBackInStockRetryProducts(ctx context.Context, maxAttempts int32) ([]ListBackInStockRetryProductsRow, error)
 	ListCartItems(ctx context.Context, cartID int32) ([]CartItem, error)
+	ListCartRecommendations(ctx context.Context, arg ListCartRecommendationsParams) ([]ListCartRecommendationsRow, error)
 	ListCategories(ctx context.Context, arg ListCategoriesParams) ([]Category, error)
+	ListCategoryNames(ctx context.Context) ([]ListCategoryNamesRow, error)
+	ListCategoryTrainingProducts(ctx context.Context) ([]ListCategoryTrainingProductsRow, error)
+	ListDailyProductSales(ctx context.Context, arg ListDailyProductSalesParams) ([]ListDailyProductSalesRow, error)
+	ListDemandForecasts(ctx context.Context, arg ListDemandForecastsParams) ([]ListDemandForecastsRow, error)
+	ListFeedCandidates(ctx context.Context, arg ListFeedCandidatesParams) ([]ListFeedCandidatesRow, error)
+	ListForecastProducts(ctx context.Context) ([]ListForecastProductsRow, error)
+	ListHeldOrderRiskAssessments(ctx context.Context, arg ListHeldOrderRiskAssessmentsParams) ([]OrderRiskAssessment, error)
+	ListImageBlobVariants(ctx context.Context, contentHash string) ([]ProductImageVariant, error)
+	ListInventoryMovementsByProduct(ctx context.Context, arg ListInventoryMovementsByProductParams) ([]InventoryMovement, error)
+	ListOrderItemAllocationsByOrderID(ctx context.Context, orderID int32) ([]ListOrderItemAllocationsByOrderIDRow, error)
 	ListOrderItems(ctx context.Context, orderID int32) ([]OrderItem, error)
+	ListOrderItemsByOrderIDs(ctx context.Context, orderIds []int32) ([]OrderItem, error)
+	ListOrderRiskSignalsByOrderIDs(ctx context.Context, orderIds []int32) ([]OrderRiskSignal, error)
 	ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error)
 	ListOrdersByStatus(ctx context.Context, arg ListOrdersByStatusParams) ([]Order, error)
 	ListOrdersByUserID(ctx context.Context, arg ListOrdersByUserIDParams) ([]Order, error)
+	ListProductAttributesByProductIDs(ctx context.Context, productIds []int32) ([]ProductAttribute, error)
+	ListProductContentDrafts(ctx context.Context, productID int32) ([]ProductContentDraft, error)
+	ListProductImageVariantsByImageIDs(ctx context.Context, imageIds []int32) ([]ProductImageVariant, error)
 	ListProductImages(ctx context.Context, productID int32) ([]ProductImage, error)
 	ListProductImagesByProductIDs(ctx context.Context, dollar_1 []int32) ([]ProductImage, error)
+	ListProductPricesByProduct(ctx context.Context, productID int32) ([]ProductPrice, error)
+	ListProductRecommendations(ctx context.Context, arg ListProductRecommendationsParams) ([]ListProductRecommendationsRow, error)
+	ListProductReviewSummariesByProductIDs(ctx context.Context, productIds []int32) ([]ProductReviewSummary, error)
+	ListProductReviews(ctx context.Context, arg ListProductReviewsParams) ([]ProductReview, error)
 	ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error)
+	ListProductsBelowThreshold(ctx context.Context, defaultThreshold int32) ([]ListProductsBelowThresholdRow, error)
 	ListProductsByCategory(ctx context.Context, arg ListProductsByCategoryParams) ([]Product, error)
+	ListProductsNeedingEmbedding(ctx context.Context, arg ListProductsNeedingEmbeddingParams) ([]ListProductsNeedingEmbeddingRow, error)
+	ListReferencedStoragePaths(ctx context.Context, cutoff pgtype.Timestamptz) ([]string, error)
+	ListSearchTermWords(ctx context.Context) ([]string, error)
+	ListStockAlerts(ctx context.Context) ([]StockAlert, error)
+	ListStockDiscrepancies(ctx context.Context) ([]ListStockDiscrepanciesRow, error)
+	ListUserCategoryActivity(ctx context.Context, arg ListUserCategoryActivityParams) ([]ListUserCategoryActivityRow, error)
+	ListUserOwnedProductIDs(ctx context.Context, userID int32) ([]int32, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListWarehouseStockByProduct(ctx context.Context, productID int32) ([]ListWarehouseStockByProductRow, error)
+	ListWarehouses(ctx context.Context) ([]Warehouse, error)
+	MarkBackInStockSubscriptionsFulfilled(ctx context.Context, ids []int32) error
+	QueueStockAlert(ctx context.Context, arg QueueStockAlertParams) error
+	RecordBackInStockFailures(ctx context.Context, arg RecordBackInStockFailuresParams) error
+	RecordFailedLogin(ctx context.Context, userID int32) error
+	RecordOrderRiskReview(ctx context.Context, arg RecordOrderRiskReviewParams) (OrderRiskAssessment, error)
+	ReleaseAssistantConversation(ctx context.Context, arg ReleaseAssistantConversationParams) error
+	ReleaseProductImageUpload(ctx context.Context, arg ReleaseProductImageUploadParams) error
 	RestoreCartItem(ctx context.Context, arg RestoreCartItemParams) (CartItem, error)
+	ReviewProductContentDraft(ctx context.Context, arg ReviewProductContentDraftParams) (ProductContentDraft, error)
+	SaveAssistantConversation(ctx context.Context, arg SaveAssistantConversationParams) error
+	SearchAttributeFacets(ctx context.Context, arg SearchAttributeFacetsParams) ([]SearchAttributeFacetsRow, error)
+	SearchCategoryFacets(ctx context.Context, arg SearchCategoryFacetsParams) ([]SearchCategoryFacetsRow, error)
+	SearchPriceFacets(ctx context.Context, arg SearchPriceFacetsParams) ([]SearchPriceFacetsRow, error)
 	SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error)
-	SetPrimaryProductImage(ctx context.Context, arg SetPrimaryProductImageParams) error
+	SearchStockFacets(ctx context.Context, arg SearchStockFacetsParams) (SearchStockFacetsRow, error)
+	SemanticSearchProducts(ctx context.Context, arg SemanticSearchProductsParams) ([]SemanticSearchProductsRow, error)
+	SetPrimaryProductImage(ctx context.Context, arg SetPrimaryProductImageParams) (int64, error)
+	SetProductImagePositions(ctx context.Context, arg SetProductImagePositionsParams) (int64, error)
 	SoftDeleteCart(ctx context.Context, id int32) error
 	SoftDeleteCartByUserID(ctx context.Context, userID int32) error
 	SoftDeleteCartItem(ctx context.Context, id int32) error
@@ -81,9 +187,11 @@ type Querier interface {
 	SoftDeleteOrderItem(ctx context.Context, id int32) error
 	SoftDeleteOrderItemsByOrderID(ctx context.Context, orderID int32) error
 	SoftDeleteProduct(ctx context.Context, id int32) error
-	SoftDeleteProductImage(ctx context.Context, id int32) error
-	SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) error
+	SoftDeleteProductImage(ctx context.Context, id int32) ([]string, error)
+	SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) ([]string, error)
 	SoftDeleteUser(ctx context.Context, id int32) error
+	SuggestProducts(ctx context.Context, arg SuggestProductsParams) ([]SuggestProductsRow, error)
+	TryJobLock(ctx context.Context, name string) (bool, error)
 	UpdateCartItemQuantity(ctx context.Context, arg UpdateCartItemQuantityParams) (CartItem, error)
 	UpdateCartTimestamp(ctx context.Context, id int32) (Cart, error)
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
@@ -92,7 +200,9 @@ type Querier interface {
 	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
 	UpdateOrderTotal(ctx context.Context, arg UpdateOrderTotalParams) (Order, error)
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
+	UpdateProductContent(ctx context.Context, arg UpdateProductContentParams) error
 	UpdateProductImage(ctx context.Context, arg UpdateProductImageParams) (ProductImage, error)
+	UpdateProductImageAltText(ctx context.Context, arg UpdateProductImageAltTextParams) (ProductImage, error)
 	UpdateProductStatus(ctx context.Context, arg UpdateProductStatusParams) (Product, error)
 	UpdateProductStock(ctx context.Context, arg UpdateProductStockParams) (Product, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
@@ -100,6 +210,11 @@ type Querier interface {
 	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
 	UpsertCartItem(ctx context.Context, arg UpsertCartItemParams) (CartItem, error)
+	UpsertCategoryReorderThreshold(ctx context.Context, arg UpsertCategoryReorderThresholdParams) (ReorderThreshold, error)
+	UpsertProductEmbedding(ctx context.Context, arg UpsertProductEmbeddingParams) error
+	UpsertProductReorderThreshold(ctx context.Context, arg UpsertProductReorderThresholdParams) (ReorderThreshold, error)
+	UpsertProductReviewSummary(ctx context.Context, arg UpsertProductReviewSummaryParams) (ProductReviewSummary, error)
+	UpsertStockReservation(ctx context.Context, arg UpsertStockReservationParams) (StockReservation, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -9,7 +9,7 @@ import (
 
 type Store interface {
 	Querier
-	ExecTx(ctx context.Context, fn func(*Queries) error) error
+	ExecTx(ctx context.Context, fn func(Querier) error) error
 }
 
 type SQLStore struct {
@@ -27,7 +27,7 @@ func NewStore(db *pgxpool.Pool) Store {
 // ExecTx executes a function within a database transaction.
 // If the function returns an error, the transaction is rolled back.
 // Otherwise, the transaction is committed.
-func (s *SQLStore) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+func (s *SQLStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
 	tx, err := s.connPool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -3,6 +3,16 @@
 # Create Bucket
 awslocal s3 mb s3://ecommerce-uploads
 
+# Let browsers upload straight to the bucket with presigned requests
+awslocal s3api put-bucket-cors --bucket ecommerce-uploads --cors-configuration '{
+  "CORSRules": [{
+    "AllowedOrigins": ["*"],
+    "AllowedMethods": ["GET", "PUT", "POST"],
+    "AllowedHeaders": ["*"],
+    "ExposeHeaders": ["ETag"]
+  }]
+}'
+
 echo "LocalStack S3 bucket created successfully."
 
 # Create Queue
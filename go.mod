@@ -23,6 +23,7 @@ require (
 	github.com/swaggo/swag v1.16.6
 	github.com/vektah/gqlparser/v2 v2.5.31
 	golang.org/x/crypto v0.47.0
+	golang.org/x/image v0.25.0
 )
 
 require (
@@ -16,8 +16,14 @@ const (
 	userIDKey    contextKey = "user_id"
 	userEmailKey contextKey = "user_email"
 	userRoleKey  contextKey = "user_role"
+	currencyKey  contextKey = "currency"
+	clientIPKey  contextKey = "client_ip"
 )
 
+// CurrencyHeader lets clients pick the currency prices are shown in; the
+// currency query parameter does the same
+const CurrencyHeader = "X-Currency"
+
 // User represents the authenticated user from context
 type User struct {
 	ID    uint
@@ -83,6 +89,42 @@ func RequireAdmin(ctx context.Context) (*User, error) {
 	return user, nil
 }
 
+// CurrencyFromContext returns the display currency the client asked for, or
+// an empty string for the base currency
+func CurrencyFromContext(ctx context.Context) string {
+	currency, _ := ctx.Value(currencyKey).(string)
+	return currency
+}
+
+// CurrencyMiddleware is an HTTP middleware that adds the requested display
+// currency to context
+func CurrencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		currency := r.URL.Query().Get("currency")
+		if currency == "" {
+			currency = r.Header.Get(CurrencyHeader)
+		}
+		if currency == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), currencyKey, currency)))
+	})
+}
+
+// WithClientIP adds the client IP address to context
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// ClientIPFromContext returns the client IP address, or an empty string
+// when it isn't known
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
 // AuthMiddleware is an HTTP middleware that validates JWT and adds user to context
 func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
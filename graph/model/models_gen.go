@@ -3,13 +3,22 @@
 package model
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
 	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 )
 
 type CreateOrderInput struct {
-	ShippingAddress string  `json:"shippingAddress"`
-	IdempotencyKey  *string `json:"idempotencyKey,omitempty"`
+	ShippingAddress string               `json:"shippingAddress"`
+	IdempotencyKey  *string              `json:"idempotencyKey,omitempty"`
+	ShipTo          *dto.ShippingAddress `json:"shipTo,omitempty"`
+	BillTo          *dto.ShippingAddress `json:"billTo,omitempty"`
+	Currency        *string              `json:"currency,omitempty"`
 }
 
 type Mutation struct {
@@ -40,6 +49,20 @@ type ProductEdge struct {
 	Node *dto.ProductResponse `json:"node"`
 }
 
+type ProductSearchConnection struct {
+	Edges      []*ProductSearchEdge `json:"edges"`
+	Facets     *dto.SearchFacets    `json:"facets"`
+	DidYouMean *string              `json:"didYouMean,omitempty"`
+	Fuzzy      bool                 `json:"fuzzy"`
+	Parsed     *dto.ParsedQuery     `json:"parsed,omitempty"`
+	PageInfo   *PageInfo            `json:"pageInfo"`
+}
+
+type ProductSearchEdge struct {
+	Node *dto.ProductResponse `json:"node"`
+	Rank float64              `json:"rank"`
+}
+
 type Query struct {
 }
 
@@ -47,6 +70,78 @@ type RefreshTokenInput struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
+type SearchProductsInput struct {
+	Query       string       `json:"query"`
+	Page        *int32       `json:"page,omitempty"`
+	Limit       *int32       `json:"limit,omitempty"`
+	CategoryIds []uint       `json:"categoryIds,omitempty"`
+	MinPrice    *money.Money `json:"minPrice,omitempty"`
+	MaxPrice    *money.Money `json:"maxPrice,omitempty"`
+	InStock     *bool        `json:"inStock,omitempty"`
+	Attributes  []string     `json:"attributes,omitempty"`
+	Sort        *SearchSort  `json:"sort,omitempty"`
+	Parse       *bool        `json:"parse,omitempty"`
+}
+
 type UpdateOrderStatusInput struct {
 	Status db.OrderStatus `json:"status"`
 }
+
+type SearchSort string
+
+const (
+	SearchSortRank      SearchSort = "RANK"
+	SearchSortPriceAsc  SearchSort = "PRICE_ASC"
+	SearchSortPriceDesc SearchSort = "PRICE_DESC"
+	SearchSortNewest    SearchSort = "NEWEST"
+)
+
+var AllSearchSort = []SearchSort{
+	SearchSortRank,
+	SearchSortPriceAsc,
+	SearchSortPriceDesc,
+	SearchSortNewest,
+}
+
+func (e SearchSort) IsValid() bool {
+	switch e {
+	case SearchSortRank, SearchSortPriceAsc, SearchSortPriceDesc, SearchSortNewest:
+		return true
+	}
+	return false
+}
+
+func (e SearchSort) String() string {
+	return string(e)
+}
+
+func (e *SearchSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchSort", str)
+	}
+	return nil
+}
+
+func (e SearchSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SearchSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SearchSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
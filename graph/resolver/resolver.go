@@ -15,6 +15,7 @@ type Resolver struct {
 	ProductService interfaces.ProductServicer
 	CartService    interfaces.CartServicer
 	OrderService   interfaces.OrderServicer
+	ImageService   interfaces.ProductImageServicer
 }
 
 // NewResolver creates a new resolver with all service dependencies
@@ -24,6 +25,7 @@ func NewResolver(
 	productService interfaces.ProductServicer,
 	cartService interfaces.CartServicer,
 	orderService interfaces.OrderServicer,
+	imageService interfaces.ProductImageServicer,
 ) *Resolver {
 	return &Resolver{
 		AuthService:    authService,
@@ -31,5 +33,6 @@ func NewResolver(
 		ProductService: productService,
 		CartService:    cartService,
 		OrderService:   orderService,
+		ImageService:   imageService,
 	}
 }
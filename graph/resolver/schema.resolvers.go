@@ -8,6 +8,7 @@ package graph
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/trenchesdeveloper/go-ai-store/graph"
 	"github.com/trenchesdeveloper/go-ai-store/graph/model"
@@ -68,20 +69,20 @@ func (r *mutationResolver) UpdateProfile(ctx context.Context, input dto.UpdatePr
 
 // CreateProduct is the resolver for the createProduct field.
 func (r *mutationResolver) CreateProduct(ctx context.Context, input dto.CreateProductRequest) (*dto.ProductResponse, error) {
-	_, err := graph.RequireAdmin(ctx)
+	user, err := graph.RequireAdmin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
-	return r.ProductService.CreateProduct(ctx, input)
+	return r.ProductService.CreateProduct(ctx, int32(user.ID), input)
 }
 
 // UpdateProduct is the resolver for the updateProduct field.
 func (r *mutationResolver) UpdateProduct(ctx context.Context, id uint, input dto.UpdateProductRequest) (*dto.ProductResponse, error) {
-	_, err := graph.RequireAdmin(ctx)
+	user, err := graph.RequireAdmin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
-	return r.ProductService.UpdateProductByID(ctx, id, &input)
+	return r.ProductService.UpdateProductByID(ctx, int32(user.ID), id, &input)
 }
 
 // DeleteProduct is the resolver for the deleteProduct field.
@@ -97,6 +98,64 @@ func (r *mutationResolver) DeleteProduct(ctx context.Context, id uint) (bool, er
 	return true, nil
 }
 
+// ReorderProductImages is the resolver for the reorderProductImages field.
+func (r *mutationResolver) ReorderProductImages(ctx context.Context, productID uint, imageIds []uint) ([]*dto.ProductImageResponse, error) {
+	_, err := graph.RequireAdmin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder product images: %w", err)
+	}
+	images, err := r.ImageService.ReorderImages(ctx, productID, imageIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder product images: %w", err)
+	}
+
+	result := make([]*dto.ProductImageResponse, len(images))
+	for i := range images {
+		result[i] = &images[i]
+	}
+	return result, nil
+}
+
+// SetPrimaryProductImage is the resolver for the setPrimaryProductImage field.
+func (r *mutationResolver) SetPrimaryProductImage(ctx context.Context, productID uint, imageID uint) ([]*dto.ProductImageResponse, error) {
+	_, err := graph.RequireAdmin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set primary product image: %w", err)
+	}
+	images, err := r.ImageService.SetPrimaryImage(ctx, productID, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set primary product image: %w", err)
+	}
+
+	result := make([]*dto.ProductImageResponse, len(images))
+	for i := range images {
+		result[i] = &images[i]
+	}
+	return result, nil
+}
+
+// UpdateProductImage is the resolver for the updateProductImage field.
+func (r *mutationResolver) UpdateProductImage(ctx context.Context, productID uint, imageID uint, input dto.UpdateProductImageRequest) (*dto.ProductImageResponse, error) {
+	_, err := graph.RequireAdmin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product image: %w", err)
+	}
+	return r.ImageService.UpdateImage(ctx, productID, imageID, input)
+}
+
+// DeleteProductImage is the resolver for the deleteProductImage field.
+func (r *mutationResolver) DeleteProductImage(ctx context.Context, productID uint, imageID uint) (bool, error) {
+	_, err := graph.RequireAdmin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete product image: %w", err)
+	}
+	err = r.ImageService.DeleteImage(ctx, productID, imageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete product image: %w", err)
+	}
+	return true, nil
+}
+
 // CreateCategory is the resolver for the createCategory field.
 func (r *mutationResolver) CreateCategory(ctx context.Context, input dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
 	_, err := graph.RequireAdmin(ctx)
@@ -139,7 +198,7 @@ func (r *mutationResolver) AddToCart(ctx context.Context, input dto.AddToCartReq
 	if err != nil {
 		return nil, fmt.Errorf("failed to add to cart: %w", err)
 	}
-	return r.CartService.AddToCart(ctx, int32(user.ID), input)
+	return r.CartService.AddToCart(ctx, int32(user.ID), input, graph.CurrencyFromContext(ctx))
 }
 
 // UpdateCartItem is the resolver for the updateCartItem field.
@@ -148,7 +207,7 @@ func (r *mutationResolver) UpdateCartItem(ctx context.Context, itemID uint, inpu
 	if err != nil {
 		return nil, fmt.Errorf("failed to update cart item: %w", err)
 	}
-	return r.CartService.UpdateCartItem(ctx, int32(user.ID), int32(itemID), input)
+	return r.CartService.UpdateCartItem(ctx, int32(user.ID), int32(itemID), input, graph.CurrencyFromContext(ctx))
 }
 
 // RemoveCartItem is the resolver for the removeCartItem field.
@@ -157,7 +216,7 @@ func (r *mutationResolver) RemoveCartItem(ctx context.Context, itemID uint) (*dt
 	if err != nil {
 		return nil, fmt.Errorf("failed to remove cart item: %w", err)
 	}
-	return r.CartService.RemoveCartItem(ctx, int32(user.ID), int32(itemID))
+	return r.CartService.RemoveCartItem(ctx, int32(user.ID), int32(itemID), graph.CurrencyFromContext(ctx))
 }
 
 // ClearCart is the resolver for the clearCart field.
@@ -171,7 +230,7 @@ func (r *mutationResolver) ClearCart(ctx context.Context) (*dto.CartResponse, er
 		return nil, fmt.Errorf("failed to clear cart: %w", err)
 	}
 	// Return empty cart
-	return r.CartService.GetCart(ctx, int32(user.ID))
+	return r.CartService.GetCart(ctx, int32(user.ID), graph.CurrencyFromContext(ctx))
 }
 
 // CreateOrder is the resolver for the createOrder field.
@@ -180,11 +239,20 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
+	req := dto.CreateOrderRequest{
+		ShippingAddress: input.ShipTo,
+		BillingAddress:  input.BillTo,
+		Currency:        graph.CurrencyFromContext(ctx),
+		IPAddress:       graph.ClientIPFromContext(ctx),
+	}
+	if input.Currency != nil {
+		req.Currency = *input.Currency
+	}
 	// Use idempotency key if provided
 	if input.IdempotencyKey != nil && *input.IdempotencyKey != "" {
-		return r.OrderService.CreateOrderWithIdempotency(ctx, int32(user.ID), *input.IdempotencyKey)
+		return r.OrderService.CreateOrderWithIdempotency(ctx, int32(user.ID), *input.IdempotencyKey, req)
 	}
-	return r.OrderService.CreateOrderFromCart(ctx, int32(user.ID))
+	return r.OrderService.CreateOrderFromCart(ctx, int32(user.ID), req)
 }
 
 // CancelOrder is the resolver for the cancelOrder field.
@@ -198,11 +266,11 @@ func (r *mutationResolver) CancelOrder(ctx context.Context, id uint) (*dto.Order
 
 // UpdateOrderStatus is the resolver for the updateOrderStatus field.
 func (r *mutationResolver) UpdateOrderStatus(ctx context.Context, id uint, input model.UpdateOrderStatusInput) (*dto.OrderResponse, error) {
-	_, err := graph.RequireAdmin(ctx)
+	admin, err := graph.RequireAdmin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update order status: %w", err)
 	}
-	return r.OrderService.UpdateOrderStatus(ctx, int32(id), string(input.Status))
+	return r.OrderService.UpdateOrderStatus(ctx, int32(admin.ID), int32(id), string(input.Status))
 }
 
 // Me is the resolver for the me field.
@@ -230,7 +298,7 @@ func (r *queryResolver) Products(ctx context.Context, page *int32, limit *int32)
 		limitNum = int(*limit)
 	}
 
-	products, meta, err := r.ProductService.GetProducts(ctx, pageNum, limitNum)
+	products, meta, err := r.ProductService.GetProducts(ctx, pageNum, limitNum, graph.CurrencyFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
@@ -254,9 +322,87 @@ func (r *queryResolver) Products(ctx context.Context, page *int32, limit *int32)
 	}, nil
 }
 
+// SearchProducts is the resolver for the searchProducts field.
+func (r *queryResolver) SearchProducts(ctx context.Context, input model.SearchProductsInput) (*model.ProductSearchConnection, error) {
+	req := dto.SearchProductsRequest{
+		Query:       input.Query,
+		Page:        1,
+		Limit:       10,
+		CategoryIDs: input.CategoryIds,
+		MinPrice:    input.MinPrice,
+		MaxPrice:    input.MaxPrice,
+		InStock:     input.InStock,
+		Attributes:  input.Attributes,
+	}
+	if input.Page != nil {
+		req.Page = int(*input.Page)
+	}
+	if input.Limit != nil {
+		req.Limit = int(*input.Limit)
+	}
+	if input.Sort != nil {
+		req.Sort = strings.ToLower(input.Sort.String())
+	}
+	if input.Parse != nil {
+		req.Parse = *input.Parse
+	}
+
+	result, meta, err := r.ProductService.SearchProducts(ctx, req, graph.CurrencyFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	// Convert to edges
+	edges := make([]*model.ProductSearchEdge, len(result.Results))
+	for i := range result.Results {
+		edges[i] = &model.ProductSearchEdge{
+			Node: &result.Results[i].ProductResponse,
+			Rank: float64(result.Results[i].Rank),
+		}
+	}
+
+	var didYouMean *string
+	if result.DidYouMean != "" {
+		didYouMean = &result.DidYouMean
+	}
+
+	return &model.ProductSearchConnection{
+		Edges:      edges,
+		Facets:     &result.Facets,
+		DidYouMean: didYouMean,
+		Fuzzy:      result.Fuzzy,
+		Parsed:     result.Parsed,
+		PageInfo: &model.PageInfo{
+			Page:       int32(meta.Page),
+			Limit:      int32(meta.Limit),
+			Total:      int32(meta.TotalCount),
+			TotalPages: int32(meta.TotalPages),
+		},
+	}, nil
+}
+
+// SuggestProducts is the resolver for the suggestProducts field.
+func (r *queryResolver) SuggestProducts(ctx context.Context, query string, limit *int32) ([]*dto.ProductSuggestion, error) {
+	req := dto.SuggestProductsRequest{Query: query}
+	if limit != nil {
+		req.Limit = int(*limit)
+	}
+
+	suggestions, err := r.ProductService.SuggestProducts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest products: %w", err)
+	}
+
+	result := make([]*dto.ProductSuggestion, len(suggestions))
+	for i := range suggestions {
+		result[i] = &suggestions[i]
+	}
+	return result, nil
+}
+
 // Product is the resolver for the product field.
 func (r *queryResolver) Product(ctx context.Context, id uint) (*dto.ProductResponse, error) {
-	return r.ProductService.GetProductByID(ctx, id)
+	return r.ProductService.GetProductByID(ctx, id, graph.CurrencyFromContext(ctx))
 }
 
 // Categories is the resolver for the categories field.
@@ -288,7 +434,7 @@ func (r *queryResolver) Cart(ctx context.Context) (*dto.CartResponse, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cart: %w", err)
 	}
-	return r.CartService.GetCart(ctx, int32(user.ID))
+	return r.CartService.GetCart(ctx, int32(user.ID), graph.CurrencyFromContext(ctx))
 }
 
 // Orders is the resolver for the orders field.
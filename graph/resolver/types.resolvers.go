@@ -8,12 +8,19 @@ package graph
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/trenchesdeveloper/go-ai-store/graph"
+	"github.com/trenchesdeveloper/go-ai-store/graph/model"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
 )
 
+// Count is the resolver for the count field.
+func (r *attributeValueFacetResolver) Count(ctx context.Context, obj *dto.AttributeValueFacet) (int32, error) {
+	return int32(obj.Count), nil
+}
+
 // Quantity is the resolver for the quantity field.
 func (r *cartItemResolver) Quantity(ctx context.Context, obj *dto.CartItemResponse) (int32, error) {
 	return int32(obj.Quantity), nil
@@ -24,37 +31,121 @@ func (r *cartItemResolver) UpdatedAt(ctx context.Context, obj *dto.CartItemRespo
 	panic(fmt.Errorf("not implemented: UpdatedAt - updatedAt"))
 }
 
+// Count is the resolver for the count field.
+func (r *categoryFacetResolver) Count(ctx context.Context, obj *dto.CategoryFacet) (int32, error) {
+	return int32(obj.Count), nil
+}
+
 // Quantity is the resolver for the quantity field.
 func (r *orderItemResolver) Quantity(ctx context.Context, obj *dto.OrderItemResponse) (int32, error) {
 	return int32(obj.Quantity), nil
 }
 
+// Sort is the resolver for the sort field.
+func (r *parsedQueryResolver) Sort(ctx context.Context, obj *dto.ParsedQuery) (*model.SearchSort, error) {
+	if obj.Sort == "" {
+		return nil, nil
+	}
+	sort := model.SearchSort(strings.ToUpper(obj.Sort))
+	return &sort, nil
+}
+
+// Count is the resolver for the count field.
+func (r *priceFacetResolver) Count(ctx context.Context, obj *dto.PriceFacet) (int32, error) {
+	return int32(obj.Count), nil
+}
+
 // Stock is the resolver for the stock field.
 func (r *productResolver) Stock(ctx context.Context, obj *dto.ProductResponse) (int32, error) {
 	return int32(obj.Stock), nil
 }
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *productImageResolver) CreatedAt(ctx context.Context, obj *dto.ProductImageResponse) (*time.Time, error) {
-	panic(fmt.Errorf("not implemented: CreatedAt - createdAt"))
+// AvailableStock is the resolver for the availableStock field.
+func (r *productResolver) AvailableStock(ctx context.Context, obj *dto.ProductResponse) (int32, error) {
+	return int32(obj.AvailableStock), nil
+}
+
+// Recommendations is the resolver for the recommendations field.
+func (r *productResolver) Recommendations(ctx context.Context, obj *dto.ProductResponse, limit *int32) ([]*dto.ProductResponse, error) {
+	req := dto.RecommendationsRequest{}
+	if limit != nil {
+		req.Limit = int(*limit)
+	}
+
+	recommendations, err := r.ProductService.GetProductRecommendations(ctx, obj.ID, req, graph.CurrencyFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	products := make([]*dto.ProductResponse, len(recommendations))
+	for i := range recommendations {
+		products[i] = &recommendations[i].ProductResponse
+	}
+	return products, nil
+}
+
+// Position is the resolver for the position field.
+func (r *productImageResolver) Position(ctx context.Context, obj *dto.ProductImageResponse) (int32, error) {
+	return int32(obj.Position), nil
+}
+
+// ReviewCount is the resolver for the reviewCount field.
+func (r *reviewSummaryResolver) ReviewCount(ctx context.Context, obj *dto.ReviewSummary) (int32, error) {
+	return int32(obj.ReviewCount), nil
+}
+
+// InStock is the resolver for the inStock field.
+func (r *stockFacetResolver) InStock(ctx context.Context, obj *dto.StockFacet) (int32, error) {
+	return int32(obj.InStock), nil
+}
+
+// OutOfStock is the resolver for the outOfStock field.
+func (r *stockFacetResolver) OutOfStock(ctx context.Context, obj *dto.StockFacet) (int32, error) {
+	return int32(obj.OutOfStock), nil
+}
+
+// AttributeValueFacet returns graph.AttributeValueFacetResolver implementation.
+func (r *Resolver) AttributeValueFacet() graph.AttributeValueFacetResolver {
+	return &attributeValueFacetResolver{r}
 }
 
 // CartItem returns graph.CartItemResolver implementation.
 func (r *Resolver) CartItem() graph.CartItemResolver { return &cartItemResolver{r} }
 
+// CategoryFacet returns graph.CategoryFacetResolver implementation.
+func (r *Resolver) CategoryFacet() graph.CategoryFacetResolver { return &categoryFacetResolver{r} }
+
 // OrderItem returns graph.OrderItemResolver implementation.
 func (r *Resolver) OrderItem() graph.OrderItemResolver { return &orderItemResolver{r} }
 
+// ParsedQuery returns graph.ParsedQueryResolver implementation.
+func (r *Resolver) ParsedQuery() graph.ParsedQueryResolver { return &parsedQueryResolver{r} }
+
+// PriceFacet returns graph.PriceFacetResolver implementation.
+func (r *Resolver) PriceFacet() graph.PriceFacetResolver { return &priceFacetResolver{r} }
+
 // Product returns graph.ProductResolver implementation.
 func (r *Resolver) Product() graph.ProductResolver { return &productResolver{r} }
 
 // ProductImage returns graph.ProductImageResolver implementation.
 func (r *Resolver) ProductImage() graph.ProductImageResolver { return &productImageResolver{r} }
 
+// ReviewSummary returns graph.ReviewSummaryResolver implementation.
+func (r *Resolver) ReviewSummary() graph.ReviewSummaryResolver { return &reviewSummaryResolver{r} }
+
+// StockFacet returns graph.StockFacetResolver implementation.
+func (r *Resolver) StockFacet() graph.StockFacetResolver { return &stockFacetResolver{r} }
+
+type attributeValueFacetResolver struct{ *Resolver }
 type cartItemResolver struct{ *Resolver }
+type categoryFacetResolver struct{ *Resolver }
 type orderItemResolver struct{ *Resolver }
+type parsedQueryResolver struct{ *Resolver }
+type priceFacetResolver struct{ *Resolver }
 type productResolver struct{ *Resolver }
 type productImageResolver struct{ *Resolver }
+type reviewSummaryResolver struct{ *Resolver }
+type stockFacetResolver struct{ *Resolver }
 
 // !!! WARNING !!!
 // The code below was going to be deleted when updating resolvers. It has been copied here so you have
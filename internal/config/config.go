@@ -3,23 +3,37 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	AWS      AWSConfig
-	Upload   UploadConfig
-	SMTP     SMTPConfig
+	Server          ServerConfig
+	Database        DatabaseConfig
+	JWT             JWTConfig
+	AWS             AWSConfig
+	Upload          UploadConfig
+	SMTP            SMTPConfig
+	Inventory       InventoryConfig
+	Currency        CurrencyConfig
+	Embedding       EmbeddingConfig
+	LLM             LLMConfig
+	Search          SearchConfig
+	Recommendations RecommendationConfig
+	Feed            FeedConfig
+	Assistant       AssistantConfig
+	Categorizer     CategorizerConfig
+	Fraud           FraudConfig
+	Forecast        ForecastConfig
 }
 
 type ServerConfig struct {
-	Port    string
-	GinMode string
+	Port            string
+	GinMode         string
+	TrustedProxies  []string // proxies (IPs or CIDRs) whose X-Forwarded-For is believed
+	TrustedPlatform string   // header a hosting platform sets to the client IP, e.g. CF-Connecting-IP
 }
 
 type DatabaseConfig struct {
@@ -38,12 +52,13 @@ type JWTConfig struct {
 }
 
 type AWSConfig struct {
-	S3Endpoint      string
-	Region          string
-	AccessKeyID     string
-	SecretAccessKey string
-	S3Bucket        string
-	EventQueueName  string
+	S3Endpoint       string
+	S3PublicEndpoint string // endpoint presigned upload URLs point clients at
+	Region           string
+	AccessKeyID      string
+	SecretAccessKey  string
+	S3Bucket         string
+	EventQueueName   string
 }
 
 type SMTPConfig struct {
@@ -54,10 +69,110 @@ type SMTPConfig struct {
 	From     string
 }
 
+type InventoryConfig struct {
+	AllocationStrategy       string // "single_warehouse_first" or "nearest_warehouse"
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
+	DefaultReorderThreshold  int32    // used when neither the product nor its category sets one
+	AlertEmails              []string // catalog admins who receive stock alert digests
+	AlertDigestInterval      time.Duration
+	BackInStockBatchSize     int           // subscribers emailed per batch when a product returns
+	BackInStockRetryDelay    time.Duration // before a failed back-in-stock email is retried, doubled for each retry
+	BackInStockMaxAttempts   int           // failed sends after which a back-in-stock subscription is given up on
+}
+
+type CurrencyConfig struct {
+	Base          string   // currency catalog prices are kept in
+	Supported     []string // currencies shoppers may browse and check out in
+	RatesProvider string   // "static" or "file"
+	Rates         string   // static rates from the base, e.g. "EUR=0.92,GBP=0.79"
+	RatesFile     string   // JSON rates file for the file provider
+}
+
+type EmbeddingConfig struct {
+	Provider      string        // "hash" or "openai"
+	Model         string        // model the openai provider asks for
+	APIURL        string        // base URL of an OpenAI-compatible API
+	APIKey        string        // API key for the openai provider
+	Dimensions    int           // vector size of the hash provider
+	IndexInterval time.Duration // how often stale product embeddings are refreshed
+	BatchSize     int           // products embedded per request to the provider
+}
+
+type LLMConfig struct {
+	APIURL string // base URL of an OpenAI-compatible chat API
+	APIKey string
+	Model  string
+}
+
+type SearchConfig struct {
+	QueryParser          string        // "rules" or "llm"
+	TermsRefreshInterval time.Duration // how often the words search terms are corrected to are reread
+}
+
+type RecommendationConfig struct {
+	RefreshInterval time.Duration // how often recommendations are recomputed from order history
+	TopN            int           // recommendations kept per product
+	MinCoPurchases  int           // orders two products must share to be recommended together
+}
+
+type FeedConfig struct {
+	PopularityWeight float64       // weight of how well a product sells
+	AffinityWeight   float64       // weight of how much the user shops the product's category
+	RecencyWeight    float64       // weight of how new the product is
+	Window           time.Duration // how far back orders and views count
+	RecencyHalfLife  time.Duration // product age at which its recency score halves
+}
+
+type AssistantConfig struct {
+	ConversationTTL time.Duration // how long a quiet conversation is kept
+	MaxHistory      int           // messages of a conversation sent back to the model
+	MaxToolRounds   int           // tool call rounds allowed before the model must reply
+}
+
+type CategorizerConfig struct {
+	TrainInterval      time.Duration // how often the category classifier is retrained from the catalog
+	MismatchConfidence float64       // confidence a different category needs before a product is flagged
+}
+
+type FraudConfig struct {
+	HoldScore           float64       // risk score at which an order is held for review; 0 never holds
+	RuleWeights         string        // risk rule weights, e.g. "user_velocity=30,address_mismatch=20"
+	VelocityWindow      time.Duration // how far back orders count towards user and IP velocity
+	MaxOrdersPerUser    int           // orders a user may place within the window before velocity fires
+	MaxOrdersPerIP      int           // orders an IP may place within the window before velocity fires
+	FirstOrderHighValue string        // base currency total at which a first order counts as high value
+	FailedLoginWindow   time.Duration // how far back failed logins count
+	MaxFailedLogins     int           // failed logins within the window before the rule fires
+	UnusualQuantity     int32         // quantity of one product above which an order line is unusual
+}
+
+type ForecastConfig struct {
+	RefreshInterval   time.Duration // how often demand is forecast again from sales
+	HistoryDays       int           // days of sales forecasts are based on
+	LevelSmoothing    float64       // how fast the forecast level follows new sales, 0-1
+	TrendSmoothing    float64       // how fast the forecast trend follows changes in level, 0-1
+	SeasonalSmoothing float64       // how fast the weekly pattern follows new sales, 0-1
+	SeasonLength      int           // days before sales repeat their pattern
+	MovingAverageDays int           // days the reported moving average covers
+	LeadTimeDays      int           // days a restock takes to arrive
+	CoverDays         int           // days a restock should last once it arrives
+	SafetyFactor      float64       // forecast errors of safety stock kept over the lead time
+}
+
 type UploadConfig struct {
-	Provider      string // "local" or "s3"
-	UploadPath    string
-	MaxUploadSize int64
+	Provider          string // "local" or "s3"
+	UploadPath        string
+	MaxUploadSize     int64
+	MinImageDimension int           // shortest side an uploaded image may have, in pixels
+	MaxImageDimension int           // longest side an uploaded image may have, in pixels
+	PublicURL         string        // base URL of the API, for signed local upload URLs
+	SigningSecret     string        // signs local upload URLs
+	PresignTTL        time.Duration // how long a presigned upload URL works
+	SweepInterval     time.Duration // how often abandoned direct uploads are expired
+	GCInterval        time.Duration // how often stored files no image uses are collected
+	GCGracePeriod     time.Duration // how long unused files are kept before they are collected
+	GCDryRun          bool          // only report the files the collector would delete
 }
 
 func LoadConfig() (*Config, error) {
@@ -66,12 +181,62 @@ func LoadConfig() (*Config, error) {
 	jwtExpiresIn, _ := time.ParseDuration(getEnv("JWT_EXPIRES_IN", "24h"))
 	refreshTokenExpiresIn, _ := time.ParseDuration(getEnv("REFRESH_TOKEN_EXPIRES_IN", "72h"))
 	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "10485760"), 10, 64)
+	minImageDimension, _ := strconv.Atoi(getEnv("UPLOAD_MIN_IMAGE_DIMENSION", "50"))
+	maxImageDimension, _ := strconv.Atoi(getEnv("UPLOAD_MAX_IMAGE_DIMENSION", "8000"))
+	uploadPresignTTL, _ := time.ParseDuration(getEnv("UPLOAD_PRESIGN_TTL", "15m"))
+	uploadSweepInterval, _ := time.ParseDuration(getEnv("UPLOAD_SESSION_SWEEP_INTERVAL", "10m"))
+	uploadGCInterval, _ := time.ParseDuration(getEnv("UPLOAD_GC_INTERVAL", "24h"))
+	uploadGCGracePeriod, _ := time.ParseDuration(getEnv("UPLOAD_GC_GRACE_PERIOD", "72h"))
+	uploadGCDryRun, _ := strconv.ParseBool(getEnv("UPLOAD_GC_DRY_RUN", "false"))
 	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "1025"))
+	reservationTTL, _ := time.ParseDuration(getEnv("INVENTORY_RESERVATION_TTL", "15m"))
+	reservationSweepInterval, _ := time.ParseDuration(getEnv("INVENTORY_RESERVATION_SWEEP_INTERVAL", "1m"))
+	defaultReorderThreshold, _ := strconv.ParseInt(getEnv("INVENTORY_DEFAULT_REORDER_THRESHOLD", "0"), 10, 32)
+	alertDigestInterval, _ := time.ParseDuration(getEnv("INVENTORY_ALERT_DIGEST_INTERVAL", "15m"))
+	backInStockBatchSize, _ := strconv.Atoi(getEnv("INVENTORY_BACK_IN_STOCK_BATCH_SIZE", "100"))
+	backInStockRetryDelay, _ := time.ParseDuration(getEnv("INVENTORY_BACK_IN_STOCK_RETRY_DELAY", "1m"))
+	backInStockMaxAttempts, _ := strconv.Atoi(getEnv("INVENTORY_BACK_IN_STOCK_MAX_ATTEMPTS", "5"))
+	embeddingDimensions, _ := strconv.Atoi(getEnv("EMBEDDING_DIMENSIONS", "256"))
+	embeddingIndexInterval, _ := time.ParseDuration(getEnv("EMBEDDING_INDEX_INTERVAL", "5m"))
+	embeddingBatchSize, _ := strconv.Atoi(getEnv("EMBEDDING_BATCH_SIZE", "64"))
+	searchTermsRefreshInterval, _ := time.ParseDuration(getEnv("SEARCH_TERMS_REFRESH_INTERVAL", "10m"))
+	recommendRefreshInterval, _ := time.ParseDuration(getEnv("RECOMMENDATIONS_REFRESH_INTERVAL", "1h"))
+	recommendTopN, _ := strconv.Atoi(getEnv("RECOMMENDATIONS_TOP_N", "20"))
+	recommendMinCoPurchases, _ := strconv.Atoi(getEnv("RECOMMENDATIONS_MIN_CO_PURCHASES", "1"))
+	feedPopularityWeight, _ := strconv.ParseFloat(getEnv("FEED_POPULARITY_WEIGHT", "0.3"), 64)
+	feedAffinityWeight, _ := strconv.ParseFloat(getEnv("FEED_AFFINITY_WEIGHT", "0.5"), 64)
+	feedRecencyWeight, _ := strconv.ParseFloat(getEnv("FEED_RECENCY_WEIGHT", "0.2"), 64)
+	feedWindow, _ := time.ParseDuration(getEnv("FEED_WINDOW", "720h"))
+	feedRecencyHalfLife, _ := time.ParseDuration(getEnv("FEED_RECENCY_HALF_LIFE", "720h"))
+	assistantConversationTTL, _ := time.ParseDuration(getEnv("ASSISTANT_CONVERSATION_TTL", "30m"))
+	assistantMaxHistory, _ := strconv.Atoi(getEnv("ASSISTANT_MAX_HISTORY", "20"))
+	assistantMaxToolRounds, _ := strconv.Atoi(getEnv("ASSISTANT_MAX_TOOL_ROUNDS", "5"))
+	categorizerTrainInterval, _ := time.ParseDuration(getEnv("CATEGORIZER_TRAIN_INTERVAL", "1h"))
+	categorizerMismatchConfidence, _ := strconv.ParseFloat(getEnv("CATEGORIZER_MISMATCH_CONFIDENCE", "0.7"), 64)
+	fraudHoldScore, _ := strconv.ParseFloat(getEnv("FRAUD_HOLD_SCORE", "50"), 64)
+	fraudVelocityWindow, _ := time.ParseDuration(getEnv("FRAUD_VELOCITY_WINDOW", "1h"))
+	fraudMaxOrdersPerUser, _ := strconv.Atoi(getEnv("FRAUD_MAX_ORDERS_PER_USER", "3"))
+	fraudMaxOrdersPerIP, _ := strconv.Atoi(getEnv("FRAUD_MAX_ORDERS_PER_IP", "5"))
+	fraudFailedLoginWindow, _ := time.ParseDuration(getEnv("FRAUD_FAILED_LOGIN_WINDOW", "24h"))
+	fraudMaxFailedLogins, _ := strconv.Atoi(getEnv("FRAUD_MAX_FAILED_LOGINS", "5"))
+	fraudUnusualQuantity, _ := strconv.ParseInt(getEnv("FRAUD_UNUSUAL_QUANTITY", "10"), 10, 32)
+	forecastRefreshInterval, _ := time.ParseDuration(getEnv("FORECAST_REFRESH_INTERVAL", "24h"))
+	forecastHistoryDays, _ := strconv.Atoi(getEnv("FORECAST_HISTORY_DAYS", "180"))
+	forecastLevelSmoothing, _ := strconv.ParseFloat(getEnv("FORECAST_LEVEL_SMOOTHING", "0.3"), 64)
+	forecastTrendSmoothing, _ := strconv.ParseFloat(getEnv("FORECAST_TREND_SMOOTHING", "0.05"), 64)
+	forecastSeasonalSmoothing, _ := strconv.ParseFloat(getEnv("FORECAST_SEASONAL_SMOOTHING", "0.2"), 64)
+	forecastSeasonLength, _ := strconv.Atoi(getEnv("FORECAST_SEASON_LENGTH", "7"))
+	forecastMovingAverageDays, _ := strconv.Atoi(getEnv("FORECAST_MOVING_AVERAGE_DAYS", "28"))
+	forecastLeadTimeDays, _ := strconv.Atoi(getEnv("FORECAST_LEAD_TIME_DAYS", "7"))
+	forecastCoverDays, _ := strconv.Atoi(getEnv("FORECAST_COVER_DAYS", "30"))
+	forecastSafetyFactor, _ := strconv.ParseFloat(getEnv("FORECAST_SAFETY_FACTOR", "1.65"), 64)
 
 	return &Config{
 		Server: ServerConfig{
-			Port:    getEnv("PORT", "8000"),
-			GinMode: getEnv("GIN_MODE", "debug"),
+			Port:            getEnv("PORT", "8000"),
+			GinMode:         getEnv("GIN_MODE", "debug"),
+			TrustedProxies:  splitList(getEnv("TRUSTED_PROXIES", "")),
+			TrustedPlatform: getEnv("TRUSTED_PLATFORM", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -87,17 +252,27 @@ func LoadConfig() (*Config, error) {
 			RefreshTokenExpiresIn: refreshTokenExpiresIn,
 		},
 		AWS: AWSConfig{
-			S3Endpoint:      getEnv("AWS_S3_ENDPOINT", "http://localhost:4566"),
-			Region:          getEnv("AWS_S3_REGION", "us-east-1"),
-			AccessKeyID:     getEnv("AWS_S3_ACCESS_KEY", "localstack"),
-			SecretAccessKey: getEnv("AWS_S3_SECRET_KEY", "localstack"),
-			S3Bucket:        getEnv("AWS_S3_BUCKET", "ecommerce-uploads"),
-			EventQueueName:  getEnv("AWS_EVENT_QUEUE_NAME", "ecommerce-events"),
+			S3Endpoint:       getEnv("AWS_S3_ENDPOINT", "http://localhost:4566"),
+			S3PublicEndpoint: getEnv("AWS_S3_PUBLIC_ENDPOINT", getEnv("AWS_S3_ENDPOINT", "http://localhost:4566")),
+			Region:           getEnv("AWS_S3_REGION", "us-east-1"),
+			AccessKeyID:      getEnv("AWS_S3_ACCESS_KEY", "localstack"),
+			SecretAccessKey:  getEnv("AWS_S3_SECRET_KEY", "localstack"),
+			S3Bucket:         getEnv("AWS_S3_BUCKET", "ecommerce-uploads"),
+			EventQueueName:   getEnv("AWS_EVENT_QUEUE_NAME", "ecommerce-events"),
 		},
 		Upload: UploadConfig{
-			Provider:      getEnv("UPLOAD_PROVIDER", "local"),
-			UploadPath:    getEnv("UPLOAD_PATH", "uploads"),
-			MaxUploadSize: maxUploadSize,
+			Provider:          getEnv("UPLOAD_PROVIDER", "local"),
+			UploadPath:        getEnv("UPLOAD_PATH", "uploads"),
+			MaxUploadSize:     maxUploadSize,
+			MinImageDimension: minImageDimension,
+			MaxImageDimension: maxImageDimension,
+			PublicURL:         getEnv("UPLOAD_PUBLIC_URL", "http://localhost:8000"),
+			SigningSecret:     getEnv("UPLOAD_SIGNING_SECRET", ""),
+			PresignTTL:        uploadPresignTTL,
+			SweepInterval:     uploadSweepInterval,
+			GCInterval:        uploadGCInterval,
+			GCGracePeriod:     uploadGCGracePeriod,
+			GCDryRun:          uploadGCDryRun,
 		},
 		SMTP: SMTPConfig{
 			Host:     getEnv("SMTP_HOST", "localhost"),
@@ -106,6 +281,86 @@ func LoadConfig() (*Config, error) {
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("SMTP_FROM", "noreply@example.com"),
 		},
+		Inventory: InventoryConfig{
+			AllocationStrategy:       getEnv("INVENTORY_ALLOCATION_STRATEGY", "single_warehouse_first"),
+			ReservationTTL:           reservationTTL,
+			ReservationSweepInterval: reservationSweepInterval,
+			DefaultReorderThreshold:  int32(defaultReorderThreshold), //#nosec G115 -- parsed with a 32-bit limit
+			AlertEmails:              splitList(getEnv("INVENTORY_ALERT_EMAILS", "")),
+			AlertDigestInterval:      alertDigestInterval,
+			BackInStockBatchSize:     backInStockBatchSize,
+			BackInStockRetryDelay:    backInStockRetryDelay,
+			BackInStockMaxAttempts:   backInStockMaxAttempts,
+		},
+		Currency: CurrencyConfig{
+			Base:          getEnv("CURRENCY_BASE", "USD"),
+			Supported:     splitList(getEnv("CURRENCY_SUPPORTED", "USD")),
+			RatesProvider: getEnv("CURRENCY_RATES_PROVIDER", "static"),
+			Rates:         getEnv("CURRENCY_RATES", ""),
+			RatesFile:     getEnv("CURRENCY_RATES_FILE", "exchange_rates.json"),
+		},
+		Embedding: EmbeddingConfig{
+			Provider:      getEnv("EMBEDDING_PROVIDER", "hash"),
+			Model:         getEnv("EMBEDDING_MODEL", "text-embedding-3-small"),
+			APIURL:        getEnv("EMBEDDING_API_URL", "https://api.openai.com/v1"),
+			APIKey:        getEnv("EMBEDDING_API_KEY", ""),
+			Dimensions:    embeddingDimensions,
+			IndexInterval: embeddingIndexInterval,
+			BatchSize:     embeddingBatchSize,
+		},
+		LLM: LLMConfig{
+			APIURL: getEnv("LLM_API_URL", "https://api.openai.com/v1"),
+			APIKey: getEnv("LLM_API_KEY", ""),
+			Model:  getEnv("LLM_MODEL", "gpt-4o-mini"),
+		},
+		Search: SearchConfig{
+			QueryParser:          getEnv("SEARCH_QUERY_PARSER", "rules"),
+			TermsRefreshInterval: searchTermsRefreshInterval,
+		},
+		Recommendations: RecommendationConfig{
+			RefreshInterval: recommendRefreshInterval,
+			TopN:            recommendTopN,
+			MinCoPurchases:  recommendMinCoPurchases,
+		},
+		Feed: FeedConfig{
+			PopularityWeight: feedPopularityWeight,
+			AffinityWeight:   feedAffinityWeight,
+			RecencyWeight:    feedRecencyWeight,
+			Window:           feedWindow,
+			RecencyHalfLife:  feedRecencyHalfLife,
+		},
+		Assistant: AssistantConfig{
+			ConversationTTL: assistantConversationTTL,
+			MaxHistory:      assistantMaxHistory,
+			MaxToolRounds:   assistantMaxToolRounds,
+		},
+		Categorizer: CategorizerConfig{
+			TrainInterval:      categorizerTrainInterval,
+			MismatchConfidence: categorizerMismatchConfidence,
+		},
+		Fraud: FraudConfig{
+			HoldScore:           fraudHoldScore,
+			RuleWeights:         getEnv("FRAUD_RULE_WEIGHTS", "user_velocity=30,ip_velocity=30,first_order_high_value=35,address_mismatch=20,failed_logins=25,unusual_quantity=20"),
+			VelocityWindow:      fraudVelocityWindow,
+			MaxOrdersPerUser:    fraudMaxOrdersPerUser,
+			MaxOrdersPerIP:      fraudMaxOrdersPerIP,
+			FirstOrderHighValue: getEnv("FRAUD_FIRST_ORDER_HIGH_VALUE", "500.00"),
+			FailedLoginWindow:   fraudFailedLoginWindow,
+			MaxFailedLogins:     fraudMaxFailedLogins,
+			UnusualQuantity:     int32(fraudUnusualQuantity), //#nosec G115 -- parsed with a 32-bit limit
+		},
+		Forecast: ForecastConfig{
+			RefreshInterval:   forecastRefreshInterval,
+			HistoryDays:       forecastHistoryDays,
+			LevelSmoothing:    forecastLevelSmoothing,
+			TrendSmoothing:    forecastTrendSmoothing,
+			SeasonalSmoothing: forecastSeasonalSmoothing,
+			SeasonLength:      forecastSeasonLength,
+			MovingAverageDays: forecastMovingAverageDays,
+			LeadTimeDays:      forecastLeadTimeDays,
+			CoverDays:         forecastCoverDays,
+			SafetyFactor:      forecastSafetyFactor,
+		},
 	}, nil
 }
 
@@ -116,3 +371,14 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// splitList parses a comma-separated env value, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,63 @@
+package dto
+
+import "encoding/json"
+
+// AssistantChatRequest is a shopper's message to the shopping assistant
+type AssistantChatRequest struct {
+	Message string `json:"message" binding:"required,max=2000"`
+}
+
+// Roles of the messages in a conversation with a language model
+const (
+	ChatRoleSystem    = "system"
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+	ChatRoleTool      = "tool"
+)
+
+// ChatMessage is one message of a conversation with a language model. An
+// assistant message either has Content for the shopper or ToolCalls to run
+// first; a tool message carries the result of the call named by ToolCallID.
+type ChatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// ToolCall is the model asking to run a tool with JSON arguments
+type ToolCall struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ToolDefinition describes a tool to the model. Parameters is a JSON Schema
+// of the arguments.
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage
+}
+
+// Types of the server-sent events an assistant chat streams
+const (
+	AssistantEventDelta      = "delta"
+	AssistantEventToolCall   = "tool_call"
+	AssistantEventToolResult = "tool_result"
+	AssistantEventMessage    = "message"
+	AssistantEventError      = "error"
+	AssistantEventDone       = "done"
+)
+
+// AssistantEvent is a step of an assistant chat as it happens: a piece of
+// the text the model is writing, a tool being called, its result, the
+// assistant's whole reply, or an error
+type AssistantEvent struct {
+	Type      string          `json:"type"`
+	Content   string          `json:"content,omitempty"`
+	Tool      string          `json:"tool,omitempty"`
+	Arguments json.RawMessage `json:"arguments,omitempty" swaggertype:"object"`
+	Result    json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	Error     string          `json:"error,omitempty"`
+}
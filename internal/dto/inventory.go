@@ -0,0 +1,117 @@
+package dto
+
+import "time"
+
+// StockAdjustmentRequest applies a signed change to a product's on-hand stock
+type StockAdjustmentRequest struct {
+	QuantityChange int32  `json:"quantity_change" binding:"required"`
+	Reason         string `json:"reason" binding:"omitempty,oneof=manual_adjustment import return"`
+	ReferenceID    string `json:"reference_id" binding:"max=100"`
+	Note           string `json:"note"`
+	WarehouseCode  string `json:"warehouse_code"` // defaults to the highest-priority warehouse
+}
+
+// InventoryMovementResponse is a single entry in a product's stock ledger
+type InventoryMovementResponse struct {
+	ID             uint      `json:"id"`
+	ProductID      uint      `json:"product_id"`
+	SKU            string    `json:"sku"`
+	WarehouseID    *uint     `json:"warehouse_id,omitempty"`
+	Reason         string    `json:"reason"`
+	ReferenceID    string    `json:"reference_id,omitempty"`
+	ActorID        *uint     `json:"actor_id,omitempty"`
+	QuantityChange int       `json:"quantity_change"`
+	StockAfter     int       `json:"stock_after"`
+	Note           string    `json:"note,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// StockDiscrepancyResponse reports a product whose stock column disagrees with its ledger
+type StockDiscrepancyResponse struct {
+	ProductID      uint   `json:"product_id"`
+	SKU            string `json:"sku"`
+	Stock          int    `json:"stock"`
+	LedgerStock    int    `json:"ledger_stock"`
+	WarehouseStock int    `json:"warehouse_stock"`
+	Difference     int    `json:"difference"`
+}
+
+type CreateWarehouseRequest struct {
+	Code       string   `json:"code" binding:"required,max=50"`
+	Name       string   `json:"name" binding:"required"`
+	Country    string   `json:"country" binding:"required,len=2"`
+	PostalCode string   `json:"postal_code" binding:"max=20"`
+	Latitude   *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude  *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Priority   int32    `json:"priority"`
+}
+
+type WarehouseResponse struct {
+	ID         uint      `json:"id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Country    string    `json:"country"`
+	PostalCode string    `json:"postal_code,omitempty"`
+	Latitude   *float64  `json:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty"`
+	Priority   int       `json:"priority"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WarehouseStockResponse is a product's on-hand quantity at one warehouse
+type WarehouseStockResponse struct {
+	WarehouseID   uint      `json:"warehouse_id"`
+	WarehouseCode string    `json:"warehouse_code"`
+	WarehouseName string    `json:"warehouse_name"`
+	Quantity      int       `json:"quantity"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ReorderThresholdRequest sets the stock level below which a product is
+// reported as low. A null threshold removes the override.
+type ReorderThresholdRequest struct {
+	Threshold *int32 `json:"threshold" binding:"omitempty,min=0"`
+}
+
+// ReorderThresholdResponse is the threshold override now set on a product or category
+type ReorderThresholdResponse struct {
+	ProductID  *uint `json:"product_id,omitempty"`
+	CategoryID *uint `json:"category_id,omitempty"`
+	Threshold  *int  `json:"threshold"` // null when the override was removed
+}
+
+// LowStockResponse is a product whose stock is below its reorder threshold
+type LowStockResponse struct {
+	ProductID uint   `json:"product_id"`
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Stock     int    `json:"stock"`
+	Threshold int    `json:"threshold"`
+	Status    string `json:"status"` // stock_low or stock_out
+}
+
+// DemandForecastResponse is a product's forecast daily demand and how to
+// restock it
+type DemandForecastResponse struct {
+	ProductID       uint      `json:"product_id"`
+	SKU             string    `json:"sku"`
+	Name            string    `json:"name"`
+	Stock           int       `json:"stock"`
+	DailyDemand     float64   `json:"daily_demand"`   // forecast units per day over the lead time and cover period
+	MovingAverage   float64   `json:"moving_average"` // units sold per day recently
+	ForecastError   float64   `json:"forecast_error"` // typical units per day the forecast was off by
+	DaysOfCover     *float64  `json:"days_of_cover"`  // null when nothing is expected to sell
+	ReorderPoint    int       `json:"reorder_point"`
+	ReorderQuantity int       `json:"reorder_quantity"` // 0 while stock is above the reorder point
+	Method          string    `json:"method"`           // holt_winters, exponential_smoothing or no_sales
+	HistoryDays     int       `json:"history_days"`     // days of sales the forecast is based on
+	ComputedAt      time.Time `json:"computed_at"`
+}
+
+// BackInStockSubscriptionResponse is a customer's pending request to hear when a product returns
+type BackInStockSubscriptionResponse struct {
+	ID        uint      `json:"id"`
+	ProductID uint      `json:"product_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
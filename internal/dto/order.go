@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
 
 type AddToCartRequest struct {
 	ProductID uint `json:"product_id" binding:"required"`
@@ -15,7 +19,8 @@ type CartResponse struct {
 	ID        uint               `json:"id"`
 	UserID    uint               `json:"user_id"`
 	CartItems []CartItemResponse `json:"cart_items"`
-	Total     float64            `json:"total"`
+	Total     money.Money        `json:"total" swaggertype:"number"`
+	Currency  string             `json:"currency"`
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
 }
@@ -24,24 +29,76 @@ type CartItemResponse struct {
 	ID        uint            `json:"id"`
 	Product   ProductResponse `json:"product"`
 	Quantity  int             `json:"quantity"`
-	Subtotal  float64         `json:"subtotal"`
+	Subtotal  money.Money     `json:"subtotal" swaggertype:"number"`
 	CreatedAt time.Time       `json:"created_at"`
 }
 
 type OrderResponse struct {
-	ID          uint                `json:"id"`
-	UserID      uint                `json:"user_id"`
-	Status      string              `json:"status"`
-	TotalAmount float64             `json:"total_amount"`
-	OrderItems  []OrderItemResponse `json:"order_items"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	ID           uint                `json:"id"`
+	UserID       uint                `json:"user_id"`
+	Status       string              `json:"status"`
+	TotalAmount  money.Money         `json:"total_amount" swaggertype:"number"`
+	Currency     string              `json:"currency"`
+	ExchangeRate string              `json:"exchange_rate"` // base-to-order currency rate locked at checkout
+	OrderItems   []OrderItemResponse `json:"order_items"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
 }
 
 type OrderItemResponse struct {
 	ID        uint            `json:"id"`
 	Product   ProductResponse `json:"product"`
 	Quantity  int             `json:"quantity"`
-	Price     float64         `json:"price"`
+	Price     money.Money     `json:"price" swaggertype:"number"`
 	CreatedAt time.Time       `json:"created_at"`
 }
+
+type CreateOrderRequest struct {
+	ShippingAddress *ShippingAddress `json:"shipping_address"`
+	BillingAddress  *ShippingAddress `json:"billing_address"`                    // compared with the shipping address by fraud checks
+	Currency        string           `json:"currency" binding:"omitempty,len=3"` // defaults to the shopper's display currency
+	IPAddress       string           `json:"-"`                                  // the shopper's IP, set by the API for fraud checks
+}
+
+// ShippingAddress is where an order is delivered; it steers warehouse selection
+type ShippingAddress struct {
+	Line1      string   `json:"line1"`
+	City       string   `json:"city"`
+	PostalCode string   `json:"postal_code"`
+	Country    string   `json:"country" binding:"required,len=2"`
+	Latitude   *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude  *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// ReservationResponse is the stock held for a customer during checkout
+type ReservationResponse struct {
+	ExpiresAt time.Time                 `json:"expires_at"`
+	Items     []ReservationItemResponse `json:"items"`
+}
+
+type ReservationItemResponse struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// RiskSignal is a fraud rule that fired for an order and the weight it
+// added to the order's risk score
+type RiskSignal struct {
+	Rule   string  `json:"rule"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail"`
+}
+
+// HeldOrderResponse is an order held for fraud review and why
+type HeldOrderResponse struct {
+	Order      OrderResponse `json:"order"`
+	RiskScore  float64       `json:"risk_score"`
+	Signals    []RiskSignal  `json:"signals"`
+	IPAddress  string        `json:"ip_address,omitempty"`
+	AssessedAt time.Time     `json:"assessed_at"`
+}
+
+// ReviewHeldOrderRequest releases a held order or cancels it
+type ReviewHeldOrderRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
 
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
@@ -24,54 +28,231 @@ type CategoryResponse struct {
 }
 
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"min=0"`
-	CategoryID  int64   `json:"category_id" binding:"required"`
-	SKU         string  `json:"sku" binding:"required"`
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description"`
+	Price       money.Money        `json:"price" binding:"required,gt=0" swaggertype:"number"`
+	Stock       int                `json:"stock" binding:"min=0"`
+	CategoryID  int64              `json:"category_id" binding:"required"`
+	SKU         string             `json:"sku" binding:"required"`
+	Attributes  []ProductAttribute `json:"attributes" binding:"omitempty,dive"`
 }
 
 type UpdateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"min=0"`
-	CategoryID  int64   `json:"category_id" binding:"required"`
-	IsActive    *bool   `json:"is_active"`
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description"`
+	Price       money.Money        `json:"price" binding:"required,gt=0" swaggertype:"number"`
+	Stock       *int               `json:"stock" binding:"omitempty,min=0"` // moves the stock to this level when set
+	CategoryID  int64              `json:"category_id" binding:"required"`
+	IsActive    *bool              `json:"is_active"`
+	Attributes  []ProductAttribute `json:"attributes" binding:"omitempty,dive"`                  // replaces the product's attributes when set
+	SEOTitle    *string            `json:"seo_title" binding:"omitempty,max=255"`                // replaces the SEO title when set; "" clears it
+	Tags        []string           `json:"tags" binding:"omitempty,max=20,dive,required,max=50"` // replaces the product's tags when set
+}
+
+// ProductAttribute is a searchable property of a product, such as color: red.
+// A product may have several values for one attribute.
+type ProductAttribute struct {
+	Name  string `json:"name" binding:"required,max=100"`
+	Value string `json:"value" binding:"required,max=255"`
 }
 
 type ProductResponse struct {
-	ID          uint                   `json:"id"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Price       float64                `json:"price"`
-	Stock       int                    `json:"stock"`
-	CategoryID  uint                   `json:"category_id"`
-	SKU         string                 `json:"sku"`
-	IsActive    bool                   `json:"is_active"`
-	Category    CategoryResponse       `json:"category"`
-	Images      []ProductImageResponse `json:"images"`
-	CreatedAt   time.Time              `json:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at"`
+	ID             uint                   `json:"id"`
+	Name           string                 `json:"name"`
+	Description    string                 `json:"description"`
+	Price          money.Money            `json:"price" swaggertype:"number"`                      // the sale price while a sale runs
+	CompareAtPrice *money.Money           `json:"compare_at_price,omitempty" swaggertype:"number"` // the list price, only while a sale runs
+	Currency       string                 `json:"currency"`
+	Stock          int                    `json:"stock"`
+	AvailableStock int                    `json:"available_stock"` // stock minus active checkout reservations
+	CategoryID     uint                   `json:"category_id"`
+	SKU            string                 `json:"sku"`
+	IsActive       bool                   `json:"is_active"`
+	Category       CategoryResponse       `json:"category"`
+	Images         []ProductImageResponse `json:"images"`
+	Attributes     []ProductAttribute     `json:"attributes,omitempty"`
+	SEOTitle       string                 `json:"seo_title,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`
+	ReviewSummary  *ReviewSummary         `json:"review_summary,omitempty"` // once the product has reviews
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// CreateSalePriceRequest schedules a sale price. A sale without ends_at runs
+// until it is ended; without starts_at it starts immediately.
+type CreateSalePriceRequest struct {
+	Price    money.Money `json:"price" binding:"required,gt=0" swaggertype:"number"`
+	Currency string      `json:"currency" binding:"omitempty,len=3"` // defaults to the base currency
+	StartsAt *time.Time  `json:"starts_at"`
+	EndsAt   *time.Time  `json:"ends_at"`
+}
+
+// SetListPriceRequest sets a product's list price in a currency's price list
+type SetListPriceRequest struct {
+	Price money.Money `json:"price" binding:"required,gt=0" swaggertype:"number"`
+}
+
+// ProductPriceResponse is one entry in a product's price history
+type ProductPriceResponse struct {
+	ID        uint        `json:"id"`
+	Kind      string      `json:"kind"` // list or sale
+	Currency  string      `json:"currency"`
+	Price     money.Money `json:"price" swaggertype:"number"`
+	StartsAt  time.Time   `json:"starts_at"`
+	EndsAt    *time.Time  `json:"ends_at,omitempty"`
+	CreatedBy *uint       `json:"created_by,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// PriceHistoryResponse is a product's price history and what it sold for at a point in time
+type PriceHistoryResponse struct {
+	ProductID      uint                   `json:"product_id"`
+	At             time.Time              `json:"at"`
+	Currency       string                 `json:"currency"`
+	Price          money.Money            `json:"price" swaggertype:"number"`
+	CompareAtPrice *money.Money           `json:"compare_at_price,omitempty" swaggertype:"number"`
+	History        []ProductPriceResponse `json:"history"`
 }
 
 type ProductImageResponse struct {
-	ID        uint      `json:"id"`
-	URL       string    `json:"url"`
-	AltText   string    `json:"alt_text"`
-	IsPrimary bool      `json:"is_primary"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint                  `json:"id"`
+	URL       string                `json:"url"`
+	AltText   string                `json:"alt_text"`
+	IsPrimary bool                  `json:"is_primary"`
+	Position  int                   `json:"position"`
+	Variants  []ProductImageVariant `json:"variants"` // empty for images hosted elsewhere
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// ProductImageVariant is a stored copy of an uploaded image: the original
+// without its metadata, or a resized version of it
+type ProductImageVariant struct {
+	Name   string `json:"name"`   // original, thumbnail, medium or large
+	Format string `json:"format"` // jpeg, png, gif or webp
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// UploadedImage is an image the upload service stored, along with where
+// each of its variants was stored. Images with the same content hash share
+// the stored files.
+type UploadedImage struct {
+	URL         string
+	StoragePath string
+	ContentHash string // hex SHA-256 of the uploaded file
+	Variants    []UploadedImageVariant
+}
+
+type UploadedImageVariant struct {
+	ProductImageVariant
+	StoragePath string
+}
+
+// ReorderProductImagesRequest lists every image of a product in the order
+// to show them
+type ReorderProductImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1"`
+}
+
+type UpdateProductImageRequest struct {
+	AltText string `json:"alt_text" binding:"max=255"`
+}
+
+// CreateImageUploadRequest describes an image the client is about to upload
+// directly to storage
+type CreateImageUploadRequest struct {
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/gif image/webp"`
+	Size        int64  `json:"size" binding:"required,gt=0"`              // in bytes; a PUT must be exactly this size, a POST no larger
+	Method      string `json:"method" binding:"omitempty,oneof=PUT POST"` // defaults to PUT
+	AltText     string `json:"alt_text" binding:"max=255"`
+}
+
+type ImageUploadResponse struct {
+	ID        uint                  `json:"id"`
+	ProductID uint                  `json:"product_id"`
+	Status    string                `json:"status"`           // pending, processing, completed, failed or expired
+	Upload    *PresignedUpload      `json:"upload,omitempty"` // only when the upload is created
+	Image     *ProductImageResponse `json:"image,omitempty"`  // once the upload is completed
+	ExpiresAt time.Time             `json:"expires_at"`
+	CreatedAt time.Time             `json:"created_at"`
 }
 
-// SearchProductsRequest defines the request for full-text product search
+// Search sort orders
+const (
+	SearchSortRank      = "rank"
+	SearchSortPriceAsc  = "price_asc"
+	SearchSortPriceDesc = "price_desc"
+	SearchSortNewest    = "newest"
+)
+
+// SearchProductsRequest defines the request for full-text product search.
+// Repeating a multi-select filter matches any of its values; different
+// filters must all match. Prices are in the display currency.
 type SearchProductsRequest struct {
-	Query      string   `form:"q" binding:"required,min=1"`
-	Page       int      `form:"page"`
-	Limit      int      `form:"limit"`
-	CategoryID *uint    `form:"category_id"`
-	MinPrice   *float64 `form:"min_price"`
-	MaxPrice   *float64 `form:"max_price"`
+	Query       string       `form:"q"` // empty to browse by filters alone
+	Page        int          `form:"page"`
+	Limit       int          `form:"limit"`
+	CategoryIDs []uint       `form:"category_id"`
+	MinPrice    *money.Money `form:"min_price" swaggertype:"number"`
+	MaxPrice    *money.Money `form:"max_price" swaggertype:"number"`
+	InStock     *bool        `form:"in_stock"`
+	Attributes  []string     `form:"attr"` // name:value, e.g. color:red
+	Sort        string       `form:"sort" binding:"omitempty,oneof=rank price_asc price_desc newest"`
+	Parse       bool         `form:"parse"` // read price, category, attribute and sort filters out of the query
+}
+
+// SearchVocabulary is what a query parser can recognise in a search: the
+// catalog's categories and the attribute values set on its products
+type SearchVocabulary struct {
+	Categories []ParsedCategory
+	Attributes []ProductAttribute
+}
+
+type ParsedCategory struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// ParsedQuery is the filters a query parser read out of a free-text search,
+// and the keywords left over for full-text search. Prices are in the display
+// currency.
+type ParsedQuery struct {
+	Keywords   string             `json:"keywords"`
+	Categories []ParsedCategory   `json:"categories"`
+	MinPrice   *money.Money       `json:"min_price,omitempty" swaggertype:"number"`
+	MaxPrice   *money.Money       `json:"max_price,omitempty" swaggertype:"number"`
+	Attributes []ProductAttribute `json:"attributes"`
+	Sort       string             `json:"sort,omitempty"`
+}
+
+// Semantic search modes
+const (
+	SemanticSearchSemantic = "semantic"
+	SemanticSearchHybrid   = "hybrid"
+)
+
+// SemanticSearchRequest defines the request for semantic product search.
+// Hybrid mode blends semantic similarity with the full-text rank.
+type SemanticSearchRequest struct {
+	Query string `form:"q" binding:"required,min=1,max=500"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+	Mode  string `form:"mode" binding:"omitempty,oneof=semantic hybrid"`
+}
+
+// SuggestProductsRequest defines the request for autocomplete suggestions
+type SuggestProductsRequest struct {
+	Query string `form:"q" binding:"required,min=1,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// ProductSuggestion is a product offered as a shopper types a query
+type ProductSuggestion struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	SKU  string `json:"sku"`
 }
 
 // ProductSearchResult includes product data with search relevance rank
@@ -79,3 +260,192 @@ type ProductSearchResult struct {
 	ProductResponse
 	Rank float32 `json:"rank"`
 }
+
+// RecommendationsRequest defines the request for product recommendations
+type RecommendationsRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// ProductRecommendation is a product customers bought together with the
+// ones recommended from. Score is the cosine similarity of their orders,
+// summed over every cart product for cart recommendations.
+type ProductRecommendation struct {
+	ProductResponse
+	Score float32 `json:"score"`
+}
+
+// ProductSearchResponse is a page of search results with facet counts over
+// every product the query matches
+type ProductSearchResponse struct {
+	Results    []ProductSearchResult `json:"results"`
+	Facets     SearchFacets          `json:"facets"`
+	DidYouMean string                `json:"did_you_mean,omitempty"` // a corrected query, when the original matched nothing
+	Fuzzy      bool                  `json:"fuzzy"`                  // results are approximate name matches
+	Parsed     *ParsedQuery          `json:"parsed,omitempty"`       // filters read out of the query, when asked to parse it
+}
+
+// SearchFacets counts matching products by category, price, stock and
+// attribute. Each facet is counted with every filter applied except its own,
+// so shoppers can see what selecting another value would add.
+type SearchFacets struct {
+	Categories []CategoryFacet  `json:"categories"`
+	Prices     []PriceFacet     `json:"prices"`
+	Stock      StockFacet       `json:"stock"`
+	Attributes []AttributeFacet `json:"attributes"`
+}
+
+type CategoryFacet struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	Selected bool   `json:"selected"`
+}
+
+// PriceFacet counts products priced from Min up to but not including Max.
+// The lowest bucket has no Min and the highest no Max.
+type PriceFacet struct {
+	Min   *money.Money `json:"min,omitempty" swaggertype:"number"`
+	Max   *money.Money `json:"max,omitempty" swaggertype:"number"`
+	Count int          `json:"count"`
+}
+
+type StockFacet struct {
+	InStock    int `json:"in_stock"`
+	OutOfStock int `json:"out_of_stock"`
+}
+
+type AttributeFacet struct {
+	Name   string                `json:"name"`
+	Values []AttributeValueFacet `json:"values"`
+}
+
+type AttributeValueFacet struct {
+	Value    string `json:"value"`
+	Count    int    `json:"count"`
+	Selected bool   `json:"selected"`
+}
+
+// FeedRequest defines the request for the personalized product feed
+type FeedRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// FeedItem is a product in a user's feed. Score blends how well the product
+// sells, how much the user shops its category and how new it is.
+type FeedItem struct {
+	ProductResponse
+	Score float64 `json:"score"`
+}
+
+// FeedResponse is a user's feed, best first. Personalized is false when the
+// user has no order, cart or view history yet and the feed is best-sellers.
+type FeedResponse struct {
+	Personalized bool       `json:"personalized"`
+	Items        []FeedItem `json:"items"`
+}
+
+// ContentInput is what a content generator knows about a product
+type ContentInput struct {
+	Name          string
+	Category      string
+	Attributes    []ProductAttribute
+	ImageAltTexts []string
+}
+
+// GeneratedContent is product copy written by a content generator
+type GeneratedContent struct {
+	Description string
+	SEOTitle    string
+	Tags        []string
+}
+
+// ContentDraftResponse is generated product copy waiting for review. Status
+// is pending, accepted or rejected.
+type ContentDraftResponse struct {
+	ID          uint       `json:"id"`
+	ProductID   uint       `json:"product_id"`
+	Description string     `json:"description"`
+	SEOTitle    string     `json:"seo_title"`
+	Tags        []string   `json:"tags"`
+	Generator   string     `json:"generator"`
+	Status      string     `json:"status"`
+	CreatedBy   *uint      `json:"created_by,omitempty"`
+	ReviewedBy  *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SuggestCategoryRequest is a product an admin is about to create, for
+// suggesting which category it belongs in
+type SuggestCategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=255"`
+	Description string `json:"description"`
+	Limit       int    `json:"limit" binding:"omitempty,min=1,max=10"`
+}
+
+// CategorySuggestion is a category and the classifier's confidence, from 0
+// to 1, that a product belongs in it
+type CategorySuggestion struct {
+	CategoryID   uint    `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// CategoryMismatchRequest defines the filters for listing products whose
+// category looks wrong
+type CategoryMismatchRequest struct {
+	MinConfidence float64 `form:"min_confidence" binding:"omitempty,gt=0,lte=1"`
+	Limit         int     `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// CategoryMismatch is a product the classifier would file under a different
+// category than the one it is in
+type CategoryMismatch struct {
+	ProductID   uint               `json:"product_id"`
+	ProductName string             `json:"product_name"`
+	SKU         string             `json:"sku"`
+	Current     CategorySuggestion `json:"current"`
+	Suggested   CategorySuggestion `json:"suggested"`
+}
+
+// CreateReviewRequest is a user's review of a product
+type CreateReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Body   string `json:"body" binding:"required,min=1,max=5000"`
+}
+
+type ReviewResponse struct {
+	ID        uint      `json:"id"`
+	ProductID uint      `json:"product_id"`
+	UserID    uint      `json:"user_id"`
+	Rating    int       `json:"rating"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewSummary is what a product's reviews add up to. Sentiment runs from
+// -1 (negative) to 1 (positive); pros and cons are what reviewers praise and
+// complain about, most mentioned first.
+type ReviewSummary struct {
+	ReviewCount   int       `json:"review_count"`
+	AverageRating float64   `json:"average_rating"`
+	Sentiment     float64   `json:"sentiment"`
+	Pros          []string  `json:"pros"`
+	Cons          []string  `json:"cons"`
+	Quotes        []string  `json:"quotes"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ReviewText is a review as a Summarizer reads it
+type ReviewText struct {
+	Rating int
+	Body   string
+}
+
+// GeneratedReviewSummary is what a Summarizer makes of a product's reviews
+type GeneratedReviewSummary struct {
+	Sentiment float64
+	Pros      []string
+	Cons      []string
+	Quotes    []string
+}
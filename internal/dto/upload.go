@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+// FileMetadata describes a file being stored
+type FileMetadata struct {
+	ContentType string
+	Size        int64 // in bytes, or -1 when unknown
+}
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	Path        string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+const (
+	UploadMethodPut  = "PUT"
+	UploadMethodPost = "POST"
+)
+
+// PresignedUpload is a request the client sends to store a file directly,
+// without it passing through the API. A PUT sends the file as the body
+// with the headers; a POST sends the fields, then the file as a "file"
+// field, in a multipart form.
+type PresignedUpload struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// StorageGCRequest defines how to run the storage garbage collector
+type StorageGCRequest struct {
+	// Report the orphaned files without deleting them
+	DryRun bool `form:"dry_run"`
+}
+
+// StorageGCReport describes a run of the storage garbage collector. Stored
+// files no image or pending upload uses are orphans once they and the
+// images that used them are older than the grace period.
+type StorageGCReport struct {
+	DryRun         bool           `json:"dry_run"`
+	Cutoff         time.Time      `json:"cutoff"`     // start of the grace period
+	Scanned        int            `json:"scanned"`    // files in storage
+	Referenced     int            `json:"referenced"` // files an image or pending upload uses
+	InGrace        int            `json:"in_grace"`   // unused files kept until the grace period ends
+	Orphans        []OrphanedFile `json:"orphans"`
+	OrphanBytes    int64          `json:"orphan_bytes"`
+	Deleted        int            `json:"deleted"`         // orphans deleted; none in a dry run
+	ForgottenBlobs int64          `json:"forgotten_blobs"` // unused content hashes forgotten; none in a dry run
+}
+
+// OrphanedFile is a stored file no image uses
+type OrphanedFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
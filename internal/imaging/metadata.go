@@ -0,0 +1,205 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrMalformedImage = errors.New("malformed image")
+
+// JPEG markers
+const (
+	markerSOI  = 0xd8
+	markerEOI  = 0xd9
+	markerSOS  = 0xda
+	markerAPP1 = 0xe1 // Exif and XMP
+	markerIPTC = 0xed // APP13, Photoshop and IPTC
+	markerCOM  = 0xfe
+)
+
+// strippedPNGChunks hold metadata rather than pixels or color information
+var strippedPNGChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// StripMetadata removes EXIF, XMP, IPTC and text metadata from an encoded
+// image without re-encoding it. Color profiles are kept. format is the name
+// image.DecodeConfig reports; GIFs carry no such metadata and are returned
+// as they are.
+func StripMetadata(data []byte, format string) ([]byte, error) {
+	switch format {
+	case "jpeg":
+		return stripJPEG(data)
+	case "png":
+		return stripPNG(data)
+	case "webp":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != markerSOI {
+		return nil, ErrMalformedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+	for {
+		segment, marker, err := nextJPEGSegment(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		// Entropy-coded data follows the scan header; keep it all
+		if marker == markerSOS || marker == markerEOI {
+			return append(out, data[pos:]...), nil
+		}
+		if marker != markerAPP1 && marker != markerIPTC && marker != markerCOM {
+			out = append(out, segment...)
+		}
+		pos += len(segment)
+	}
+}
+
+// nextJPEGSegment returns the marker segment at pos, including its marker
+// and length
+func nextJPEGSegment(data []byte, pos int) ([]byte, byte, error) {
+	if pos+4 > len(data) || data[pos] != 0xff {
+		return nil, 0, ErrMalformedImage
+	}
+	marker := data[pos+1]
+	if marker == markerEOI {
+		return data[pos : pos+2], marker, nil
+	}
+	length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+	if length < 2 || pos+2+length > len(data) {
+		return nil, 0, ErrMalformedImage
+	}
+	return data[pos : pos+2+length], marker, nil
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	const signatureLen = 8
+	if len(data) < signatureLen {
+		return nil, ErrMalformedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:signatureLen]...)
+	for pos := signatureLen; pos < len(data); {
+		if pos+8 > len(data) {
+			return nil, ErrMalformedImage
+		}
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		end := pos + 12 + length // length, type, data and CRC
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformedImage
+		}
+		if !strippedPNGChunks[string(data[pos+4:pos+8])] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	return out, nil
+}
+
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || !bytes.Equal(data[0:4], []byte("RIFF")) || !bytes.Equal(data[8:12], []byte("WEBP")) {
+		return nil, ErrMalformedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	for pos := 12; pos < len(data); {
+		if pos+8 > len(data) {
+			return nil, ErrMalformedImage
+		}
+		fourCC := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + size + size%2
+		if size < 0 || end > len(data) {
+			return nil, ErrMalformedImage
+		}
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[pos:end]...)
+			if size > 0 {
+				// Clear the flags saying EXIF and XMP chunks follow
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8)) //#nosec G115 -- no larger than the input
+	return out, nil
+}
+
+// JPEGOrientation returns the EXIF orientation of a JPEG, from 1 to 8; 1,
+// the default, needs no transform
+func JPEGOrientation(data []byte) int {
+	if len(data) < 2 || data[0] != 0xff || data[1] != markerSOI {
+		return 1
+	}
+
+	for pos := 2; ; {
+		segment, marker, err := nextJPEGSegment(data, pos)
+		if err != nil || marker == markerSOS || marker == markerEOI {
+			return 1
+		}
+		if marker == markerAPP1 && bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[10:])
+		}
+		pos += len(segment)
+	}
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF
+// structure
+func exifOrientation(tiff []byte) int {
+	const orientationTag = 0x0112
+
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) != orientationTag {
+			continue
+		}
+		orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+		return orientation
+	}
+	return 1
+}
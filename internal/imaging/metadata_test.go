@@ -0,0 +1,143 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exifSegment builds an APP1 segment holding a little-endian TIFF with one
+// orientation entry
+func exifSegment(orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	copy(tiff, "II")
+	binary.LittleEndian.PutUint16(tiff[2:], 42)
+	binary.LittleEndian.PutUint32(tiff[4:], 8)
+	binary.LittleEndian.PutUint16(tiff[8:], 1)
+	binary.LittleEndian.PutUint16(tiff[10:], 0x0112)
+	binary.LittleEndian.PutUint16(tiff[12:], 3) // SHORT
+	binary.LittleEndian.PutUint32(tiff[14:], 1)
+	binary.LittleEndian.PutUint16(tiff[18:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2)) //#nosec G115 -- small test fixture
+	return append(segment, payload...)
+}
+
+// jpegWithExif encodes a small JPEG and inserts an EXIF segment and a
+// comment after its SOI marker
+func jpegWithExif(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 8)), nil))
+	encoded := buf.Bytes()
+
+	comment := []byte{0xff, markerCOM, 0, 7, 'h', 'e', 'l', 'l', 'o'}
+	data := append([]byte{}, encoded[:2]...)
+	data = append(data, exifSegment(orientation)...)
+	data = append(data, comment...)
+	return append(data, encoded[2:]...)
+}
+
+func pngChunk(kind string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data))) //#nosec G115 -- small test fixture
+	copy(chunk[4:], kind)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestStripMetadata_JPEG(t *testing.T) {
+	t.Parallel()
+
+	data := jpegWithExif(t, 6)
+
+	stripped, err := StripMetadata(data, "jpeg")
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(stripped), "Exif")
+	assert.NotContains(t, string(stripped), "hello")
+	assert.Equal(t, 1, JPEGOrientation(stripped))
+
+	img, err := jpeg.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	assert.Equal(t, image.Pt(16, 8), img.Bounds().Size())
+}
+
+func TestStripMetadata_PNG(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	encoded := buf.Bytes()
+
+	// Metadata goes between IHDR, which ends 33 bytes in, and the pixels
+	data := append([]byte{}, encoded[:33]...)
+	data = append(data, pngChunk("tEXt", []byte("Author\x00someone"))...)
+	data = append(data, pngChunk("eXIf", []byte("MM\x00\x2a"))...)
+	data = append(data, encoded[33:]...)
+
+	stripped, err := StripMetadata(data, "png")
+	require.NoError(t, err)
+
+	assert.Equal(t, encoded, stripped)
+}
+
+func TestStripMetadata_WebP(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeWebP(&buf, image.NewNRGBA(image.Rect(0, 0, 3, 3))))
+	bitstream := buf.Bytes()[12:]
+
+	vp8x := []byte("VP8X\x0a\x00\x00\x00")
+	vp8x = append(vp8x, 0x08|0x04|0x10, 0, 0, 0, 2, 0, 0, 2, 0, 0)
+	exif := append([]byte("EXIF\x03\x00\x00\x00"), 'a', 'b', 'c', 0)
+
+	data := []byte("RIFF\x00\x00\x00\x00WEBP")
+	data = append(data, vp8x...)
+	data = append(data, exif...)
+	data = append(data, bitstream...)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8)) //#nosec G115 -- small test fixture
+
+	stripped, err := StripMetadata(data, "webp")
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(stripped), "EXIF")
+	assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:])) //#nosec G115 -- small test fixture
+	// Only the alpha flag is left
+	assert.Equal(t, byte(0x10), stripped[20])
+	assert.Equal(t, bitstream, stripped[12+len(vp8x):])
+}
+
+func TestStripMetadata_Malformed(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"jpeg", "png", "webp"} {
+		_, err := StripMetadata([]byte("not an image at all"), format)
+		assert.ErrorIs(t, err, ErrMalformedImage, format)
+	}
+
+	truncated := jpegWithExif(t, 1)[:30]
+	_, err := StripMetadata(truncated, "jpeg")
+	assert.ErrorIs(t, err, ErrMalformedImage)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	t.Parallel()
+
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		assert.Equal(t, int(orientation), JPEGOrientation(jpegWithExif(t, orientation)))
+	}
+	assert.Equal(t, 1, JPEGOrientation(jpegWithExif(t, 12)), "out of range")
+	assert.Equal(t, 1, JPEGOrientation([]byte("not a jpeg")))
+}
@@ -0,0 +1,59 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrient(t *testing.T) {
+	t.Parallel()
+
+	// 3x2, each pixel's red is its index:
+	//   0 1 2
+	//   3 4 5
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i := 0; i < 6; i++ {
+		src.SetNRGBA(i%3, i/3, color.NRGBA{R: uint8(i), A: 0xff}) //#nosec G115 -- small test fixture
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{orientation: 1, want: [][]uint8{{0, 1, 2}, {3, 4, 5}}},
+		{orientation: 2, want: [][]uint8{{2, 1, 0}, {5, 4, 3}}},
+		{orientation: 3, want: [][]uint8{{5, 4, 3}, {2, 1, 0}}},
+		{orientation: 4, want: [][]uint8{{3, 4, 5}, {0, 1, 2}}},
+		{orientation: 5, want: [][]uint8{{0, 3}, {1, 4}, {2, 5}}},
+		{orientation: 6, want: [][]uint8{{3, 0}, {4, 1}, {5, 2}}},
+		{orientation: 7, want: [][]uint8{{5, 2}, {4, 1}, {3, 0}}},
+		{orientation: 8, want: [][]uint8{{2, 5}, {1, 4}, {0, 3}}},
+	}
+
+	for _, tt := range tests {
+		got := Orient(src, tt.orientation)
+		rows := make([][]uint8, got.Bounds().Dy())
+		for y := range rows {
+			for x := 0; x < got.Bounds().Dx(); x++ {
+				rows[y] = append(rows[y], color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA).R)
+			}
+		}
+		assert.Equal(t, tt.want, rows, "orientation %d", tt.orientation)
+	}
+}
+
+func TestResize(t *testing.T) {
+	t.Parallel()
+
+	src := image.NewRGBA(image.Rect(10, 10, 1010, 760))
+
+	assert.Equal(t, image.Pt(150, 113), Resize(src, 150).Bounds().Size())
+	assert.Equal(t, image.Pt(600, 450), Resize(src, 600).Bounds().Size())
+	// Never scaled up
+	assert.Equal(t, image.Pt(1000, 750), Resize(src, 1200).Bounds().Size())
+	// Always at least a pixel high
+	assert.Equal(t, image.Pt(10, 1), Resize(image.NewRGBA(image.Rect(0, 0, 1000, 2)), 10).Bounds().Size())
+}
@@ -0,0 +1,330 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+	"sort"
+)
+
+// VP8L limits
+const (
+	maxWebPDimension  = 1 << 14
+	maxCodeLength     = 15 // longest prefix code for image data
+	maxCodeLengthCode = 7  // longest prefix code for code lengths
+	subtractGreen     = 2  // transform type
+)
+
+// codeLengthCodeOrder is the order code length code lengths are written in
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+var ErrImageTooLargeForWebP = errors.New("image is too large to encode as WebP")
+
+// EncodeWebP writes img as a lossless WebP. It only applies the subtract
+// green transform and a prefix code per channel, so the output is larger
+// than libwebp's, but it needs nothing beyond the standard library.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > maxWebPDimension || height > maxWebPDimension {
+		return ErrImageTooLargeForWebP
+	}
+
+	pixels := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(pixels, pixels.Bounds(), img, b.Min, draw.Src)
+
+	// Subtract green leaves red and blue as their difference from green,
+	// which is close to zero for most colors and so codes shorter
+	var green, red, blue, alpha [256]int
+	opaque := true
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		p := pixels.Pix[i : i+4 : i+4]
+		p[0] -= p[1]
+		p[2] -= p[1]
+		red[p[0]]++
+		green[p[1]]++
+		blue[p[2]]++
+		alpha[p[3]]++
+		if p[3] != 0xff {
+			opaque = false
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)  //#nosec G115 -- bounded by maxWebPDimension
+	bw.write(uint32(height-1), 14) //#nosec G115 -- bounded by maxWebPDimension
+	if opaque {
+		bw.write(0, 1)
+	} else {
+		bw.write(1, 1)
+	}
+	bw.write(0, 3) // version
+
+	bw.write(1, 1) // a transform follows
+	bw.write(subtractGreen, 2)
+	bw.write(0, 1) // no more transforms
+	bw.write(0, 1) // no color cache
+	bw.write(0, 1) // one prefix code group for the whole image
+
+	// Green's alphabet also covers backward reference lengths, which are
+	// never used, as is the distance code
+	greenCode := writePrefixCode(bw, green[:], 256+24)
+	redCode := writePrefixCode(bw, red[:], 256)
+	blueCode := writePrefixCode(bw, blue[:], 256)
+	alphaCode := writePrefixCode(bw, alpha[:], 256)
+	writePrefixCode(bw, nil, 40)
+
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		greenCode.write(bw, pixels.Pix[i+1])
+		redCode.write(bw, pixels.Pix[i])
+		blueCode.write(bw, pixels.Pix[i+2])
+		alphaCode.write(bw, pixels.Pix[i+3])
+	}
+
+	data := bw.bytes()
+	chunkSize := len(data)
+	padded := chunkSize + chunkSize%2
+
+	header := make([]byte, 20)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(4+8+padded)) //#nosec G115 -- bounded by maxWebPDimension
+	copy(header[8:12], "WEBP")
+	copy(header[12:16], "VP8L")
+	binary.LittleEndian.PutUint32(header[16:20], uint32(chunkSize)) //#nosec G115 -- bounded by maxWebPDimension
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padded != chunkSize {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prefixCode holds the bit-reversed canonical code of each symbol, ready to
+// be written least significant bit first
+type prefixCode struct {
+	codes   []uint32
+	lengths []uint8
+}
+
+func (c prefixCode) write(bw *bitWriter, symbol uint8) {
+	bw.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes the prefix code for a histogram of symbols and
+// returns it. Codes of one or two small symbols use VP8L's simple form.
+func writePrefixCode(bw *bitWriter, histogram []int, alphabetSize int) prefixCode {
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	code := prefixCode{
+		codes:   make([]uint32, alphabetSize),
+		lengths: make([]uint8, alphabetSize),
+	}
+
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < 256) {
+		if len(used) == 0 {
+			used = []int{0}
+		}
+		bw.write(1, 1) // simple code
+		bw.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(used[0]), 8)
+		}
+		// A lone symbol takes no bits; of two, the first written is 0
+		if len(used) == 2 {
+			bw.write(uint32(used[1]), 8)
+			code.codes[used[1]] = 1
+			code.lengths[used[0]] = 1
+			code.lengths[used[1]] = 1
+		}
+		return code
+	}
+
+	lengths := codeLengths(histogram, alphabetSize, maxCodeLength)
+	code.codes = canonicalCodes(lengths)
+	code.lengths = lengths
+
+	// The code lengths are written with a prefix code of their own
+	var lengthHistogram [19]int
+	for _, l := range lengths {
+		lengthHistogram[l]++
+	}
+	lengthLengths := codeLengths(lengthHistogram[:], len(lengthHistogram), maxCodeLengthCode)
+	lengthCodes := canonicalCodes(lengthLengths)
+
+	written := len(codeLengthCodeOrder)
+	for written > 4 && lengthLengths[codeLengthCodeOrder[written-1]] == 0 {
+		written--
+	}
+	bw.write(0, 1) // normal code
+	bw.write(uint32(written-4), 4)
+	for _, symbol := range codeLengthCodeOrder[:written] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+	bw.write(0, 1) // every symbol's length follows
+
+	// A code with a single length is read without any bits
+	if countNonZero(lengthLengths) > 1 {
+		for _, l := range lengths {
+			bw.write(lengthCodes[l], uint(lengthLengths[l]))
+		}
+	}
+	return code
+}
+
+func countNonZero(lengths []uint8) int {
+	n := 0
+	for _, l := range lengths {
+		if l != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// codeLengths builds Huffman code lengths for a histogram, no longer than
+// limit. When the tree comes out too deep the counts are flattened and it
+// is built again.
+func codeLengths(histogram []int, alphabetSize int, limit uint8) []uint8 {
+	counts := make([]int, len(histogram))
+	copy(counts, histogram)
+
+	for {
+		lengths, ok := huffmanLengths(counts, alphabetSize, limit)
+		if ok {
+			return lengths
+		}
+		for i, c := range counts {
+			if c > 0 {
+				counts[i] = c/2 + 1
+			}
+		}
+	}
+}
+
+func huffmanLengths(counts []int, alphabetSize int, limit uint8) ([]uint8, bool) {
+	type node struct {
+		count  int
+		parent int
+	}
+
+	nodes := make([]node, 0, 2*len(counts))
+	var live []int
+	leaves := make(map[int]int)
+	for symbol, c := range counts {
+		if c > 0 {
+			leaves[symbol] = len(nodes)
+			live = append(live, len(nodes))
+			nodes = append(nodes, node{count: c, parent: -1})
+		}
+	}
+
+	lengths := make([]uint8, alphabetSize)
+	if len(live) == 1 {
+		for symbol := range leaves {
+			lengths[symbol] = 1
+		}
+		return lengths, true
+	}
+
+	for len(live) > 1 {
+		sort.SliceStable(live, func(i, j int) bool { return nodes[live[i]].count < nodes[live[j]].count })
+		a, b := live[0], live[1]
+		parent := len(nodes)
+		nodes = append(nodes, node{count: nodes[a].count + nodes[b].count, parent: -1})
+		nodes[a].parent = parent
+		nodes[b].parent = parent
+		live = append(live[2:], parent)
+	}
+
+	for symbol, leaf := range leaves {
+		depth := 0
+		for n := leaf; nodes[n].parent != -1; n = nodes[n].parent {
+			depth++
+		}
+		if depth > int(limit) {
+			return nil, false
+		}
+		lengths[symbol] = uint8(depth) //#nosec G115 -- bounded by limit
+	}
+	return lengths, true
+}
+
+// canonicalCodes assigns canonical codes to code lengths, bit-reversed so
+// they can be written least significant bit first
+func canonicalCodes(lengths []uint8) []uint32 {
+	var count [maxCodeLength + 1]uint32
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+
+	var next [maxCodeLength + 1]uint32
+	code := uint32(0)
+	for l := 1; l <= maxCodeLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		codes[symbol] = reverseBits(next[l], l)
+		next[l]++
+	}
+	return codes
+}
+
+func reverseBits(code uint32, length uint8) uint32 {
+	var reversed uint32
+	for i := uint8(0); i < length; i++ {
+		reversed = reversed<<1 | code&1
+		code >>= 1
+	}
+	return reversed
+}
+
+// bitWriter packs bits least significant first, as VP8L reads them
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(bits uint32, n uint) {
+	w.acc |= uint64(bits) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc = 0
+		w.nbits = 0
+	}
+	return w.buf
+}
@@ -0,0 +1,86 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	t.Parallel()
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 67, 41))
+	for y := 0; y < 41; y++ {
+		for x := 0; x < 67; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 3), G: uint8(y * 5), B: uint8(x ^ y), A: 0xff}) //#nosec G115 -- small test fixture
+		}
+	}
+
+	translucent := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	for i := range translucent.Pix {
+		translucent.Pix[i] = uint8(i * 7) //#nosec G115 -- wraps on purpose
+	}
+
+	solid := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(solid.Pix); i += 4 {
+		copy(solid.Pix[i:], []byte{200, 30, 30, 0xff})
+	}
+
+	twoColors := image.NewNRGBA(image.Rect(0, 0, 9, 3))
+	for i := 0; i < len(twoColors.Pix); i += 4 {
+		if i%8 == 0 {
+			copy(twoColors.Pix[i:], []byte{10, 10, 10, 0xff})
+		} else {
+			copy(twoColors.Pix[i:], []byte{250, 250, 250, 0xff})
+		}
+	}
+
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{name: "gradient", img: gradient},
+		{name: "translucent", img: translucent},
+		{name: "single color", img: solid},
+		{name: "two colors", img: twoColors},
+		{name: "one pixel", img: image.NewNRGBA(image.Rect(0, 0, 1, 1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, EncodeWebP(&buf, tt.img))
+
+			decoded, err := webp.Decode(&buf)
+			require.NoError(t, err)
+			require.Equal(t, tt.img.Bounds().Size(), decoded.Bounds().Size())
+
+			for y := 0; y < tt.img.Bounds().Dy(); y++ {
+				for x := 0; x < tt.img.Bounds().Dx(); x++ {
+					want := tt.img.NRGBAAt(x, y)
+					got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+					// Fully transparent pixels have no color to keep
+					if want.A == 0 {
+						assert.Zero(t, got.A)
+						continue
+					}
+					require.Equal(t, want, got, "pixel %d,%d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeWebP_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, maxWebPDimension+1, 1))
+	assert.ErrorIs(t, EncodeWebP(&bytes.Buffer{}, img), ErrImageTooLargeForWebP)
+}
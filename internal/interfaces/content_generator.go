@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// ContentGenerator writes product copy: a description, an SEO title and
+// tags. What it writes is a draft for an admin to review.
+type ContentGenerator interface {
+	Generate(ctx context.Context, input dto.ContentInput) (*dto.GeneratedContent, error)
+	// Name identifies the generator on the drafts it writes
+	Name() string
+}
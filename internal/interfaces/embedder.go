@@ -0,0 +1,13 @@
+package interfaces
+
+import "context"
+
+// Embedder turns texts into vectors that point in similar directions when
+// the texts mean similar things
+type Embedder interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model names the model behind the vectors. Vectors from different models
+	// are never compared.
+	Model() string
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"math/big"
+)
+
+// ExchangeRates quotes how many units of one currency a unit of another buys
+type ExchangeRates interface {
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
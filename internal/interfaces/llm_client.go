@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// LLMClient talks to a chat language model that can call tools
+type LLMClient interface {
+	// Complete returns the model's next message in the conversation: either
+	// a reply, or tool calls whose results it needs before replying. The
+	// message's content is passed to onContent piece by piece as the model
+	// writes it.
+	Complete(ctx context.Context, messages []dto.ChatMessage, tools []dto.ToolDefinition, onContent func(string)) (*dto.ChatMessage, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// QueryParser reads structured filters out of a free-text search such as
+// "red running shoes under $80 in size 10"
+type QueryParser interface {
+	// Parse returns the filters found in query, limited to the categories and
+	// attribute values in vocab, and the words it did not use as keywords
+	Parse(ctx context.Context, query string, vocab dto.SearchVocabulary) (*dto.ParsedQuery, error)
+}
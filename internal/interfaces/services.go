@@ -2,6 +2,8 @@ package interfaces
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
 	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
@@ -28,30 +30,135 @@ type ProductServicer interface {
 	GetCategoryByID(ctx context.Context, id uint) (*dto.CategoryResponse, error)
 	UpdateCategory(ctx context.Context, req dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
 	DeleteCategory(ctx context.Context, id uint) error
-	CreateProduct(ctx context.Context, req dto.CreateProductRequest) (*dto.ProductResponse, error)
-	GetProducts(ctx context.Context, page, limit int) ([]dto.ProductResponse, *utils.PaginationMeta, error)
-	SearchProducts(ctx context.Context, req dto.SearchProductsRequest) ([]dto.ProductSearchResult, *utils.PaginationMeta, error)
-	GetProductByID(ctx context.Context, id uint) (*dto.ProductResponse, error)
-	UpdateProductByID(ctx context.Context, id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
+	CreateProduct(ctx context.Context, actorID int32, req dto.CreateProductRequest) (*dto.ProductResponse, error)
+	GetProducts(ctx context.Context, page, limit int, currency string) ([]dto.ProductResponse, *utils.PaginationMeta, error)
+	SearchProducts(ctx context.Context, req dto.SearchProductsRequest, currency string) (*dto.ProductSearchResponse, *utils.PaginationMeta, error)
+	SuggestProducts(ctx context.Context, req dto.SuggestProductsRequest) ([]dto.ProductSuggestion, error)
+	SemanticSearch(ctx context.Context, req dto.SemanticSearchRequest, currency string) ([]dto.ProductSearchResult, *utils.PaginationMeta, error)
+	GetProductRecommendations(ctx context.Context, productID uint, req dto.RecommendationsRequest, currency string) ([]dto.ProductRecommendation, error)
+	GetCartRecommendations(ctx context.Context, userID int32, req dto.RecommendationsRequest, currency string) ([]dto.ProductRecommendation, error)
+	GetProductByID(ctx context.Context, id uint, currency string) (*dto.ProductResponse, error)
+	UpdateProductByID(ctx context.Context, actorID int32, id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
 	DeleteProductByID(ctx context.Context, id uint) error
-	UpdateProductImage(ctx context.Context, productID uint, imageURL string, altText string) error
+	GetPriceHistory(ctx context.Context, productID uint, at time.Time, currency string) (*dto.PriceHistoryResponse, error)
+	SetListPrice(ctx context.Context, actorID int32, productID uint, currency string, req dto.SetListPriceRequest) (*dto.ProductPriceResponse, error)
+	ScheduleSalePrice(ctx context.Context, actorID int32, productID uint, req dto.CreateSalePriceRequest) (*dto.ProductPriceResponse, error)
+	EndSalePrice(ctx context.Context, productID, priceID uint) (*dto.ProductPriceResponse, error)
+}
+
+// ProductImageServicer defines product image management methods
+type ProductImageServicer interface {
+	ListImages(ctx context.Context, productID uint) ([]dto.ProductImageResponse, error)
+	AddImage(ctx context.Context, productID uint, image dto.UploadedImage, altText string) (*dto.ProductImageResponse, error)
+	ReorderImages(ctx context.Context, productID uint, imageIDs []uint) ([]dto.ProductImageResponse, error)
+	SetPrimaryImage(ctx context.Context, productID, imageID uint) ([]dto.ProductImageResponse, error)
+	UpdateImage(ctx context.Context, productID, imageID uint, req dto.UpdateProductImageRequest) (*dto.ProductImageResponse, error)
+	DeleteImage(ctx context.Context, productID, imageID uint) error
+}
+
+// ImageUploadServicer defines direct-to-storage product image upload methods
+type ImageUploadServicer interface {
+	CreateUpload(ctx context.Context, actorID int32, productID uint, req dto.CreateImageUploadRequest) (*dto.ImageUploadResponse, error)
+	CompleteUpload(ctx context.Context, productID, uploadID uint) (*dto.ImageUploadResponse, error)
+	ExpireUploads(ctx context.Context) (int, error)
+}
+
+// StorageGCServicer defines garbage collection of stored files no image uses
+type StorageGCServicer interface {
+	CollectGarbage(ctx context.Context, dryRun bool) (*dto.StorageGCReport, error)
 }
 
 // CartServicer defines cart management methods
 type CartServicer interface {
-	GetCart(ctx context.Context, userID int32) (*dto.CartResponse, error)
-	AddToCart(ctx context.Context, userID int32, req dto.AddToCartRequest) (*dto.CartResponse, error)
-	UpdateCartItem(ctx context.Context, userID int32, itemID int32, req dto.UpdateCartItemRequest) (*dto.CartResponse, error)
-	RemoveCartItem(ctx context.Context, userID int32, itemID int32) (*dto.CartResponse, error)
+	GetCart(ctx context.Context, userID int32, currency string) (*dto.CartResponse, error)
+	AddToCart(ctx context.Context, userID int32, req dto.AddToCartRequest, currency string) (*dto.CartResponse, error)
+	UpdateCartItem(ctx context.Context, userID int32, itemID int32, req dto.UpdateCartItemRequest, currency string) (*dto.CartResponse, error)
+	RemoveCartItem(ctx context.Context, userID int32, itemID int32, currency string) (*dto.CartResponse, error)
 	ClearCart(ctx context.Context, userID int32) error
 }
 
 // OrderServicer defines order management methods
 type OrderServicer interface {
-	CreateOrderFromCart(ctx context.Context, userID int32) (*dto.OrderResponse, error)
-	CreateOrderWithIdempotency(ctx context.Context, userID int32, idempotencyKey string) (*dto.OrderResponse, error)
+	CreateOrderFromCart(ctx context.Context, userID int32, req dto.CreateOrderRequest) (*dto.OrderResponse, error)
+	CreateOrderWithIdempotency(ctx context.Context, userID int32, idempotencyKey string, req dto.CreateOrderRequest) (*dto.OrderResponse, error)
 	GetOrderByID(ctx context.Context, userID int32, orderID int32, isAdmin bool) (*dto.OrderResponse, error)
 	GetUserOrders(ctx context.Context, userID int32, page, limit int) ([]dto.OrderResponse, *utils.PaginationMeta, error)
-	UpdateOrderStatus(ctx context.Context, orderID int32, status string) (*dto.OrderResponse, error)
+	UpdateOrderStatus(ctx context.Context, adminID, orderID int32, status string) (*dto.OrderResponse, error)
 	CancelOrder(ctx context.Context, userID int32, orderID int32) (*dto.OrderResponse, error)
+	ListHeldOrders(ctx context.Context, page, limit int) ([]dto.HeldOrderResponse, *utils.PaginationMeta, error)
+	ReviewHeldOrder(ctx context.Context, adminID, orderID int32, decision string) (*dto.OrderResponse, error)
+}
+
+// InventoryServicer defines stock ledger, warehouse and reorder threshold methods
+type InventoryServicer interface {
+	GetMovementsBySKU(ctx context.Context, sku string, page, limit int) ([]dto.InventoryMovementResponse, *utils.PaginationMeta, error)
+	AdjustStock(ctx context.Context, actorID int32, sku string, req dto.StockAdjustmentRequest) (*dto.InventoryMovementResponse, error)
+	Reconcile(ctx context.Context) ([]dto.StockDiscrepancyResponse, error)
+	ListWarehouses(ctx context.Context) ([]dto.WarehouseResponse, error)
+	CreateWarehouse(ctx context.Context, req dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error)
+	GetWarehouseStockBySKU(ctx context.Context, sku string) ([]dto.WarehouseStockResponse, error)
+	ListLowStock(ctx context.Context) ([]dto.LowStockResponse, error)
+	SetProductReorderThreshold(ctx context.Context, sku string, req dto.ReorderThresholdRequest) (*dto.ReorderThresholdResponse, error)
+	SetCategoryReorderThreshold(ctx context.Context, categoryID int32, req dto.ReorderThresholdRequest) (*dto.ReorderThresholdResponse, error)
+}
+
+// BackInStockServicer defines back-in-stock subscription methods
+type BackInStockServicer interface {
+	Subscribe(ctx context.Context, userID, productID int32) (*dto.BackInStockSubscriptionResponse, error)
+	GetSubscription(ctx context.Context, userID, productID int32) (*dto.BackInStockSubscriptionResponse, error)
+	Unsubscribe(ctx context.Context, userID, productID int32) error
+}
+
+// ReviewServicer defines product review methods
+type ReviewServicer interface {
+	CreateReview(ctx context.Context, userID, productID int32, req dto.CreateReviewRequest) (*dto.ReviewResponse, error)
+	ListReviews(ctx context.Context, productID int32, page, limit int) ([]dto.ReviewResponse, *utils.PaginationMeta, error)
+	GetSummary(ctx context.Context, productID int32) (*dto.ReviewSummary, error)
+}
+
+// ReservationServicer defines checkout stock hold methods
+type ReservationServicer interface {
+	ReserveCart(ctx context.Context, userID int32) (*dto.ReservationResponse, error)
+	GetReservation(ctx context.Context, userID int32) (*dto.ReservationResponse, error)
+	ReleaseReservation(ctx context.Context, userID int32) error
+	ReleaseExpired(ctx context.Context) (int64, error)
+}
+
+// RecommendationServicer defines co-purchase recommendation methods
+type RecommendationServicer interface {
+	Refresh(ctx context.Context) (int64, error)
+}
+
+// ForecastServicer defines demand forecasting and reorder report methods
+type ForecastServicer interface {
+	Refresh(ctx context.Context) (int, error)
+	ListForecasts(ctx context.Context, page, limit int) ([]dto.DemandForecastResponse, *utils.PaginationMeta, error)
+	ExportCSV(ctx context.Context, w io.Writer) error
+}
+
+// FeedServicer defines personalized product feed methods
+type FeedServicer interface {
+	GetFeed(ctx context.Context, userID int32, req dto.FeedRequest, currency string) (*dto.FeedResponse, error)
+	RecordView(ctx context.Context, userID, productID int32) error
+}
+
+// ContentServicer defines generated product content methods
+type ContentServicer interface {
+	GenerateDraft(ctx context.Context, actorID int32, productID uint) (*dto.ContentDraftResponse, error)
+	ListDrafts(ctx context.Context, productID uint) ([]dto.ContentDraftResponse, error)
+	AcceptDraft(ctx context.Context, actorID int32, productID, draftID uint) (*dto.ProductResponse, error)
+	RejectDraft(ctx context.Context, actorID int32, productID, draftID uint) (*dto.ContentDraftResponse, error)
+}
+
+// AssistantServicer defines conversational shopping assistant methods
+type AssistantServicer interface {
+	Chat(ctx context.Context, userID int32, message, currency string, emit func(dto.AssistantEvent)) error
+	Reset(ctx context.Context, userID int32) error
+}
+
+// CategorizationServicer defines product category suggestion methods
+type CategorizationServicer interface {
+	Train(ctx context.Context) (int, error)
+	SuggestCategory(ctx context.Context, req dto.SuggestCategoryRequest) ([]dto.CategorySuggestion, error)
+	FindCategoryMismatches(ctx context.Context, req dto.CategoryMismatchRequest) ([]dto.CategoryMismatch, error)
 }
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// Summarizer reads a product's reviews and works out their overall
+// sentiment, the pros and cons reviewers mention and quotes that show both
+type Summarizer interface {
+	Summarize(ctx context.Context, reviews []dto.ReviewText) (*dto.GeneratedReviewSummary, error)
+	// Name identifies the summarizer on the summaries it writes
+	Name() string
+}
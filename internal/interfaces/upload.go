@@ -1,8 +1,30 @@
 package interfaces
 
-import "mime/multipart"
+import (
+	"context"
+	"io"
+	"time"
 
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// Upload stores files, such as product images, under slash-separated paths
 type Upload interface {
-	UploadFile(file *multipart.FileHeader, path string) (string, error)
-	DeleteFile(path string) error
+	// Put stores what r reads under path, replacing any file there, and
+	// returns its URL
+	Put(ctx context.Context, path string, r io.Reader, meta dto.FileMetadata) (string, error)
+	// Get opens the file stored under path. Get and Stat return an error
+	// wrapping fs.ErrNotExist when there is none.
+	Get(ctx context.Context, path string) (io.ReadCloser, error)
+	Stat(ctx context.Context, path string) (*dto.FileInfo, error)
+	// List returns the files whose paths start with prefix, in path order
+	List(ctx context.Context, prefix string) ([]dto.FileInfo, error)
+	// Delete removes the file stored under path. A file that is already
+	// gone may or may not be an error wrapping fs.ErrNotExist.
+	Delete(ctx context.Context, path string) error
+	// PresignUpload returns a request, sent with method (dto.UploadMethodPut
+	// or dto.UploadMethodPost), that lets a client store a file of
+	// contentType under path until it expires. PUT uploads must be
+	// exactly maxBytes long, and POST uploads at most that.
+	PresignUpload(ctx context.Context, method, path, contentType string, maxBytes int64, expires time.Duration) (*dto.PresignedUpload, error)
 }
@@ -0,0 +1,111 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultCurrency is the currency prices were kept in before the catalog
+// became multi-currency
+const DefaultCurrency = "USD"
+
+// RateScale is the number of decimal places kept on exchange rates
+const RateScale = 8
+
+var (
+	ErrInvalidCurrency = errors.New("invalid currency code")
+	ErrInvalidRate     = errors.New("invalid exchange rate")
+)
+
+// NormalizeCurrency upper-cases an ISO 4217 code such as "eur" and checks its shape
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+		}
+	}
+	return code, nil
+}
+
+// ParseRate reads a positive exchange rate such as "0.92", rounded half away
+// from zero to RateScale places so it can be stored and replayed exactly
+func ParseRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	// A rate too small for RateScale rounds to zero, which can't be inverted
+	rate := RoundRate(r)
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// RoundRate rounds an exchange rate half away from zero to RateScale places
+func RoundRate(r *big.Rat) *big.Rat {
+	return roundRat(r, RateScale)
+}
+
+// RateFromNumeric converts a stored exchange rate
+func RateFromNumeric(n pgtype.Numeric) (*big.Rat, error) {
+	if !n.Valid || n.NaN || n.InfinityModifier != pgtype.Finite || n.Int == nil {
+		return nil, ErrInvalidRate
+	}
+
+	r := new(big.Rat).SetInt(n.Int)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(n.Exp))), nil)
+	if n.Exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(scale))
+	} else {
+		r.Mul(r, new(big.Rat).SetInt(scale))
+	}
+	if r.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return r, nil
+}
+
+// RateNumeric converts an exchange rate for the database, at RateScale places
+func RateNumeric(r *big.Rat) pgtype.Numeric {
+	scaled := new(big.Rat).Mul(roundRat(r, RateScale), new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(RateScale), nil)))
+	return pgtype.Numeric{Int: new(big.Int).Set(scaled.Num()), Exp: -RateScale, Valid: true}
+}
+
+// Convert returns the amount at an exchange rate, rounded half away from zero
+// to the cent, or ErrOutOfRange if that doesn't fit
+func (m Money) Convert(rate *big.Rat) (Money, error) {
+	converted := new(big.Rat).Mul(big.NewRat(int64(m), 1), rate)
+	return fromBigInt(roundRat(converted, 0).Num())
+}
+
+// roundRat rounds r half away from zero to the given number of decimal places
+func roundRat(r *big.Rat, places int64) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(places), nil)
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+
+	num := new(big.Int).Abs(scaled.Num())
+	quo, rem := new(big.Int).QuoRem(num, scaled.Denom(), new(big.Int))
+	if new(big.Int).Mul(rem, big.NewInt(2)).Cmp(scaled.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if scaled.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return new(big.Rat).SetFrac(quo, scale)
+}
+
+func abs32(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
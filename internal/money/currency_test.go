@@ -0,0 +1,109 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	t.Parallel()
+
+	code, err := NormalizeCurrency(" eur ")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", code)
+
+	for _, input := range []string{"", "EU", "EURO", "E1R"} {
+		_, err := NormalizeCurrency(input)
+		assert.ErrorIs(t, err, ErrInvalidCurrency, input)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	rate, err := ParseRate("0.92")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(92, 100), rate)
+
+	rate, err = ParseRate("1.234567895")
+	require.NoError(t, err)
+	assert.Equal(t, "1.23456790", rate.FloatString(RateScale))
+
+	// 0.000000001 rounds to zero at RateScale
+	for _, input := range []string{"", "abc", "0", "-1.5", "0.000000001"} {
+		_, err := ParseRate(input)
+		assert.ErrorIs(t, err, ErrInvalidRate, input)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		amount Money
+		rate   *big.Rat
+		want   Money
+	}{
+		{name: "exact", amount: 1000, rate: big.NewRat(92, 100), want: 920},
+		{name: "rounds half up", amount: 150, rate: big.NewRat(1, 4), want: 38},
+		{name: "rounds down below half", amount: 999, rate: big.NewRat(92, 100), want: 919},
+		{name: "rounds half away from zero", amount: -150, rate: big.NewRat(1, 4), want: -38},
+		{name: "identity", amount: 1999, rate: big.NewRat(1, 1), want: 1999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.amount.Convert(tt.rate)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Money(math.MaxInt64).Convert(big.NewRat(2, 1))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+// Converting either gives the amount at the rate rounded to the cent or
+// reports that it doesn't fit, never a wrapped one
+func TestConvertOverflowProperty(t *testing.T) {
+	t.Parallel()
+
+	converts := func(cents int64, num, denom uint32) bool {
+		rate := big.NewRat(int64(num)+1, int64(denom)+1)
+		got, err := FromCents(cents).Convert(rate)
+
+		want := roundRat(new(big.Rat).Mul(new(big.Rat).SetInt64(cents), rate), 0).Num()
+		if !want.IsInt64() {
+			return errors.Is(err, ErrOutOfRange)
+		}
+		return err == nil && got.Cents() == want.Int64()
+	}
+
+	require.NoError(t, quick.Check(converts, nil))
+	assert.True(t, converts(math.MaxInt64, 0, 0))
+	assert.True(t, converts(math.MaxInt64, 1, 0))
+	assert.True(t, converts(math.MinInt64, 1, 0))
+}
+
+// Rates an order stores come back exactly, so its prices can be replayed
+func TestRateRoundTripProperty(t *testing.T) {
+	t.Parallel()
+
+	roundTrips := func(num, denom uint32) bool {
+		rate := RoundRate(big.NewRat(int64(num)+1, int64(denom)+1))
+
+		stored, err := RateFromNumeric(RateNumeric(rate))
+		return err == nil && stored.Cmp(rate) == 0
+	}
+
+	require.NoError(t, quick.Check(roundTrips, nil))
+}
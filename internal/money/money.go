@@ -0,0 +1,192 @@
+// Package money holds prices and totals exactly, in integer minor units.
+//
+// Rounding rules:
+//   - Amounts a client sends may not carry fractions of a cent; Parse rejects
+//     them rather than guess which way the client meant to round.
+//   - Amounts that come out with more precision than a cent, such as a
+//     database numeric with a wider scale or a price converted at an
+//     exchange rate, round half away from zero.
+//   - A line total is the unit price times the quantity, which is exact, and an
+//     order or cart total is the sum of its line totals, so totals always
+//     match their lines to the cent.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scale is the number of decimal places kept
+const Scale = 2
+
+var (
+	ErrInvalidAmount = errors.New("invalid money amount")
+	ErrTooPrecise    = errors.New("money amount has fractions of a cent")
+	ErrOutOfRange    = errors.New("money amount out of range")
+)
+
+var (
+	centsPerUnit = big.NewInt(100)
+	minInt64     = big.NewInt(-1 << 63)
+	maxInt64     = big.NewInt(1<<63 - 1)
+)
+
+// Money is an exact amount in cents. It marshals to JSON and GraphQL as a
+// decimal number with two places, e.g. 19.90.
+type Money int64
+
+// FromCents returns the amount for a number of cents
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// Parse reads a decimal amount such as "19.99", "-0.5" or "1e2" exactly
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || s == "" || strings.ContainsAny(s, "/") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(centsPerUnit))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("%w: %q", ErrTooPrecise, s)
+	}
+	return fromBigInt(r.Num())
+}
+
+// FromNumeric converts a database numeric, rounding half away from zero to the cent
+func FromNumeric(n pgtype.Numeric) (Money, error) {
+	if !n.Valid || n.NaN || n.InfinityModifier != pgtype.Finite || n.Int == nil {
+		return 0, ErrInvalidAmount
+	}
+
+	cents := new(big.Int).Set(n.Int)
+	shift := int64(n.Exp) + Scale
+	if shift >= 0 {
+		cents.Mul(cents, new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil))
+		return fromBigInt(cents)
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(-shift), nil)
+	quo, rem := new(big.Int).QuoRem(cents, divisor, new(big.Int))
+	if new(big.Int).Mul(rem.Abs(rem), big.NewInt(2)).Cmp(divisor) >= 0 {
+		if cents.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return fromBigInt(quo)
+}
+
+func fromBigInt(cents *big.Int) (Money, error) {
+	if cents.Cmp(minInt64) < 0 || cents.Cmp(maxInt64) > 0 {
+		return 0, ErrOutOfRange
+	}
+	return Money(cents.Int64()), nil
+}
+
+// Cents returns the amount in cents
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Add returns the sum of two amounts, or ErrOutOfRange if it overflows
+func (m Money) Add(other Money) (Money, error) {
+	return fromBigInt(new(big.Int).Add(big.NewInt(int64(m)), big.NewInt(int64(other))))
+}
+
+// Mul returns the amount times a quantity, or ErrOutOfRange if it overflows
+func (m Money) Mul(quantity int64) (Money, error) {
+	return fromBigInt(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(quantity)))
+}
+
+// Numeric converts the amount for the database
+func (m Money) Numeric() pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(int64(m)), Exp: -Scale, Valid: true}
+}
+
+// String formats the amount with two decimal places, e.g. "-0.50"
+func (m Money) String() string {
+	cents := int64(m)
+	sign := ""
+	// Negate as uint64 so the smallest int64 does not overflow
+	abs := uint64(cents)
+	if cents < 0 {
+		sign = "-"
+		abs = -abs
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam lets gin bind an amount from a query or form parameter
+func (m *Money) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalGQL writes the amount as a GraphQL Money scalar
+func (m Money) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, m.String())
+}
+
+// UnmarshalGQL reads a GraphQL Money scalar given as a number or a string
+func (m *Money) UnmarshalGQL(v any) error {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		// The shortest form that round-trips is what the client wrote
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case interface{ String() string }: // json.Number
+		s = v.String()
+	default:
+		return fmt.Errorf("%w: %T", ErrInvalidAmount, v)
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
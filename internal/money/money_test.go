@@ -0,0 +1,221 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Money
+		wantErr error
+	}{
+		{name: "whole units", input: "19", want: 1900},
+		{name: "two places", input: "19.99", want: 1999},
+		{name: "one place", input: "0.5", want: 50},
+		{name: "trailing zeros", input: "10.500", want: 1050},
+		{name: "negative", input: "-3.05", want: -305},
+		{name: "exponent", input: "1.5e2", want: 15000},
+		{name: "surrounding space", input: " 7.25 ", want: 725},
+		{name: "fraction of a cent", input: "19.999", wantErr: ErrTooPrecise},
+		{name: "empty", input: "", wantErr: ErrInvalidAmount},
+		{name: "not a number", input: "ten", wantErr: ErrInvalidAmount},
+		{name: "ratio", input: "1/4", wantErr: ErrInvalidAmount},
+		{name: "too large", input: "1e30", wantErr: ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0.00", Money(0).String())
+	assert.Equal(t, "0.05", Money(5).String())
+	assert.Equal(t, "19.90", Money(1990).String())
+	assert.Equal(t, "-0.50", Money(-50).String())
+	assert.Equal(t, "-92233720368547758.08", Money(-1<<63).String())
+}
+
+func TestFromNumeric(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   pgtype.Numeric
+		want    Money
+		wantErr bool
+	}{
+		{name: "two places", input: pgtype.Numeric{Int: big.NewInt(1999), Exp: -2, Valid: true}, want: 1999},
+		{name: "whole units", input: pgtype.Numeric{Int: big.NewInt(12), Exp: 0, Valid: true}, want: 1200},
+		{name: "positive exponent", input: pgtype.Numeric{Int: big.NewInt(3), Exp: 2, Valid: true}, want: 30000},
+		{name: "rounds half up", input: pgtype.Numeric{Int: big.NewInt(10005), Exp: -3, Valid: true}, want: 1001},
+		{name: "rounds down below half", input: pgtype.Numeric{Int: big.NewInt(10004), Exp: -3, Valid: true}, want: 1000},
+		{name: "rounds half away from zero", input: pgtype.Numeric{Int: big.NewInt(-10005), Exp: -3, Valid: true}, want: -1001},
+		{name: "null", input: pgtype.Numeric{}, wantErr: true},
+		{name: "NaN", input: pgtype.Numeric{NaN: true, Valid: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FromNumeric(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	var body struct {
+		Price Money  `json:"price"`
+		Sale  *Money `json:"sale,omitempty"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 0.1, "sale": "0.20"}`), &body))
+	assert.Equal(t, Money(10), body.Price)
+	require.NotNil(t, body.Sale)
+	assert.Equal(t, Money(20), *body.Sale)
+
+	out, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 0.10, "sale": 0.20}`, string(out))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"price": 0.001}`), &body), ErrTooPrecise)
+}
+
+func TestGQL(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []any{"19.99", 19.99, json.Number("19.99")} {
+		var m Money
+		require.NoError(t, m.UnmarshalGQL(input))
+		assert.Equal(t, Money(1999), m)
+	}
+
+	var m Money
+	require.NoError(t, m.UnmarshalGQL(int64(5)))
+	assert.Equal(t, Money(500), m)
+	assert.Error(t, m.UnmarshalGQL(true))
+
+	var buf bytes.Buffer
+	Money(1990).MarshalGQL(&buf)
+	assert.Equal(t, "19.90", buf.String())
+}
+
+// Amounts survive every representation they pass through unchanged
+func TestRoundTripProperty(t *testing.T) {
+	t.Parallel()
+
+	roundTrips := func(cents int64) bool {
+		m := FromCents(cents)
+
+		parsed, err := Parse(m.String())
+		if err != nil || parsed != m {
+			return false
+		}
+
+		fromDB, err := FromNumeric(m.Numeric())
+		if err != nil || fromDB != m {
+			return false
+		}
+
+		data, err := json.Marshal(m)
+		if err != nil {
+			return false
+		}
+		var decoded Money
+		return json.Unmarshal(data, &decoded) == nil && decoded == m
+	}
+
+	require.NoError(t, quick.Check(roundTrips, nil))
+}
+
+// Adding prices in cents never drifts the way float64 sums of the same prices do
+func TestSumProperty(t *testing.T) {
+	t.Parallel()
+
+	exact := func(cents []uint16) bool {
+		var sum Money
+		want := new(big.Rat)
+		for _, c := range cents {
+			sum += FromCents(int64(c))
+			want.Add(want, big.NewRat(int64(c), 100))
+		}
+
+		got, ok := new(big.Rat).SetString(sum.String())
+		return ok && got.Cmp(want) == 0
+	}
+
+	require.NoError(t, quick.Check(exact, nil))
+}
+
+// Multiplying or adding amounts either gives the exact result or reports
+// that it doesn't fit, never a wrapped one
+func TestOverflowProperty(t *testing.T) {
+	t.Parallel()
+
+	mul := func(cents, quantity int64) bool {
+		want := new(big.Int).Mul(big.NewInt(cents), big.NewInt(quantity))
+		got, err := FromCents(cents).Mul(quantity)
+		if !want.IsInt64() {
+			return errors.Is(err, ErrOutOfRange)
+		}
+		return err == nil && got.Cents() == want.Int64()
+	}
+	add := func(a, b int64) bool {
+		want := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+		got, err := FromCents(a).Add(FromCents(b))
+		if !want.IsInt64() {
+			return errors.Is(err, ErrOutOfRange)
+		}
+		return err == nil && got.Cents() == want.Int64()
+	}
+
+	require.NoError(t, quick.Check(mul, nil))
+	require.NoError(t, quick.Check(add, nil))
+
+	// The bounds themselves
+	assert.True(t, mul(math.MaxInt64, 1))
+	assert.True(t, mul(math.MaxInt64, 2))
+	assert.True(t, mul(math.MinInt64, -1))
+	assert.True(t, mul(math.MinInt64/2, 2))
+	assert.True(t, add(math.MaxInt64, 1))
+	assert.True(t, add(math.MinInt64, -1))
+	assert.True(t, add(math.MaxInt64, math.MinInt64))
+}
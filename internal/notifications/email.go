@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"html/template"
 	"net/smtp"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 )
 
 // SMTPConfig holds the SMTP server configuration
@@ -180,15 +182,18 @@ func (s *EmailService) SendPasswordResetEmail(to string, resetToken string) erro
 }
 
 // SendOrderConfirmationEmail sends an order confirmation email
-func (s *EmailService) SendOrderConfirmationEmail(to string, orderID string, total float64) error {
+func (s *EmailService) SendOrderConfirmationEmail(to string, orderID string, total money.Money, currency string) error {
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
 	body := fmt.Sprintf(`
 		<h1>Order Confirmation</h1>
 		<p>Thank you for your order!</p>
 		<p><strong>Order ID:</strong> %s</p>
-		<p><strong>Total:</strong> $%.2f</p>
+		<p><strong>Total:</strong> %s %s</p>
 		<p>We will notify you when your order ships.</p>
 		<p>Best regards,<br>The Go AI Store Team</p>
-	`, orderID, total)
+	`, orderID, total, currency)
 
 	return s.Send(Email{
 		To:      []string{to},
@@ -230,3 +235,53 @@ func (s *EmailService) SendLoginNotificationEmail(to string, username string, ip
 		IsHTML:  true,
 	})
 }
+
+// SendStockAlertDigest sends catalog admins a summary of products that ran low or out of stock
+func (s *EmailService) SendStockAlertDigest(to []string, alerts []Notification) error {
+	var rows bytes.Buffer
+	for _, a := range alerts {
+		status := "Low stock"
+		if a.Type == NotificationTypeStockOut {
+			status = "Out of stock"
+		}
+		rows.WriteString(fmt.Sprintf(
+			"<tr><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%d</td></tr>",
+			template.HTMLEscapeString(a.SKU), template.HTMLEscapeString(a.ProductName), status, a.Stock, a.Threshold,
+		))
+	}
+
+	body := fmt.Sprintf(`
+		<h1>Stock Alert Digest</h1>
+		<p>The following products need restocking:</p>
+		<table border="1" cellpadding="4" cellspacing="0">
+			<tr><th>SKU</th><th>Product</th><th>Status</th><th>Stock</th><th>Threshold</th></tr>
+			%s
+		</table>
+		<p>Best regards,<br>The Go AI Store Team</p>
+	`, rows.String())
+
+	return s.Send(Email{
+		To:      to,
+		Subject: fmt.Sprintf("Stock Alert - %d product(s) need restocking", len(alerts)),
+		Body:    body,
+		IsHTML:  true,
+	})
+}
+
+// SendBackInStockEmail tells a subscribed customer that a product they asked about is available again
+func (s *EmailService) SendBackInStockEmail(to string, username string, productName string, sku string) error {
+	body := fmt.Sprintf(`
+		<h1>Back in Stock</h1>
+		<p>Hello %s,</p>
+		<p>Good news! <strong>%s</strong> (SKU %s) is back in stock.</p>
+		<p>Stock may be limited, so order soon if you don't want to miss out.</p>
+		<p>Best regards,<br>The Go AI Store Team</p>
+	`, template.HTMLEscapeString(username), template.HTMLEscapeString(productName), template.HTMLEscapeString(sku))
+
+	return s.Send(Email{
+		To:      []string{to},
+		Subject: fmt.Sprintf("%s is back in stock", productName),
+		Body:    body,
+		IsHTML:  true,
+	})
+}
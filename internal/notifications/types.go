@@ -1,5 +1,7 @@
 package notifications
 
+import "github.com/trenchesdeveloper/go-ai-store/internal/money"
+
 // NotificationType represents the type of notification to send
 type NotificationType string
 
@@ -9,6 +11,10 @@ const (
 	NotificationTypeOrderConfirmation NotificationType = "order_confirmation"
 	NotificationTypeLoginNotification NotificationType = "login_notification"
 	NotificationTypeUserLoggedIn      NotificationType = "user_logged_in"
+	NotificationTypeStockLow          NotificationType = "stock_low"
+	NotificationTypeStockOut          NotificationType = "stock_out"
+	NotificationTypeBackInStock       NotificationType = "back_in_stock"
+	NotificationTypeReviewCreated     NotificationType = "review_created"
 )
 
 // Notification represents a notification message from the queue
@@ -24,11 +30,19 @@ type Notification struct {
 	ResetToken string `json:"reset_token,omitempty"`
 
 	// Order confirmation fields
-	OrderID string  `json:"order_id,omitempty"`
-	Total   float64 `json:"total,omitempty"`
+	OrderID  string      `json:"order_id,omitempty"`
+	Total    money.Money `json:"total,omitempty"`
+	Currency string      `json:"currency,omitempty"` // the order's currency; USD when unset
 
 	// Login notification fields
 	IPAddress string `json:"ip_address,omitempty"`
 	UserAgent string `json:"user_agent,omitempty"`
 	LoginTime string `json:"login_time,omitempty"`
+
+	// Stock alert, back-in-stock and review_created fields
+	ProductID   int64  `json:"product_id,omitempty"`
+	SKU         string `json:"sku,omitempty"`
+	ProductName string `json:"product_name,omitempty"`
+	Stock       int    `json:"stock,omitempty"`
+	Threshold   int    `json:"threshold,omitempty"`
 }
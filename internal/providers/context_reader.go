@@ -0,0 +1,20 @@
+package providers
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader reads from r until ctx is done, so copying a large upload
+// stops when its request is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// StaticExchangeRates serves fixed rates against a base currency, for local
+// development and tests. Rates between two non-base currencies are crossed
+// through the base.
+type StaticExchangeRates struct {
+	base  string
+	rates map[string]*big.Rat // units of the currency one unit of base buys
+}
+
+// NewStaticExchangeRates builds rates from base-to-currency quotes such as
+// {"EUR": "0.92", "GBP": "0.79"}
+func NewStaticExchangeRates(base string, rates map[string]string) (*StaticExchangeRates, error) {
+	base, err := money.NormalizeCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := map[string]*big.Rat{base: big.NewRat(1, 1)}
+	for code, rate := range rates {
+		code, err := money.NormalizeCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		r, err := money.ParseRate(rate)
+		if err != nil {
+			return nil, fmt.Errorf("rate for %s: %w", code, err)
+		}
+		parsed[code] = r
+	}
+
+	return &StaticExchangeRates{base: base, rates: parsed}, nil
+}
+
+// ParseStaticRates reads rates written as "EUR=0.92,GBP=0.79"
+func ParseStaticRates(s string) (map[string]string, error) {
+	rates := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		code, rate, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid exchange rate %q, expected CODE=rate", pair)
+		}
+		rates[strings.TrimSpace(code)] = strings.TrimSpace(rate)
+	}
+	return rates, nil
+}
+
+// exchangeRatesFile is the layout of a rates file:
+// {"base": "USD", "rates": {"EUR": "0.92", "GBP": "0.79"}}
+type exchangeRatesFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+// NewFileExchangeRates loads static rates from a JSON file
+func NewFileExchangeRates(path string) (*StaticExchangeRates, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	var file exchangeRatesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+
+	return NewStaticExchangeRates(file.Base, file.Rates)
+}
+
+func (s *StaticExchangeRates) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	fromRate, ok := s.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRateNotFound, from)
+	}
+	toRate, ok := s.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRateNotFound, to)
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const defaultHashDimensions = 256
+
+// HashEmbedder embeds text without a model by hashing its words and their
+// character trigrams into a fixed number of dimensions. Texts that share
+// words or word fragments get similar vectors, which makes it deterministic
+// and offline for tests and local development, but it knows nothing of
+// synonyms or meaning. Vectors are not normalized.
+type HashEmbedder struct {
+	dims int
+}
+
+func NewHashEmbedder(dims int) *HashEmbedder {
+	if dims <= 0 {
+		dims = defaultHashDimensions
+	}
+	return &HashEmbedder{dims: dims}
+}
+
+func (e *HashEmbedder) Model() string {
+	return fmt.Sprintf("hash-ngram-%d", e.dims)
+}
+
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, e.dims)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		e.add(vector, "w:"+word, 1)
+
+		// Trigrams of the padded word let "headphone" and "headphones" share
+		// most of their features
+		padded := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			e.add(vector, "g:"+string(padded[i:i+3]), 0.5)
+		}
+	}
+	return vector
+}
+
+// add hashes a feature to a dimension and a sign, so features that collide
+// tend to cancel out rather than pile up
+func (e *HashEmbedder) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[sum%uint64(e.dims)] += weight //#nosec G115 -- dims is positive
+}
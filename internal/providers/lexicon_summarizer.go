@@ -0,0 +1,291 @@
+package providers
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const (
+	maxSummaryAspects = 5
+	maxSummaryQuotes  = 3
+	maxQuoteLength    = 200
+	minQuoteWords     = 3
+	// sentimentAlpha flattens a sentence's summed word scores into -1..1
+	sentimentAlpha = 15
+	// negationWindow is how many words back a "not" still flips a word
+	negationWindow = 3
+	negationFactor = -0.75
+	intensifyBoost = 1.5
+)
+
+// sentimentLexicon scores words that carry sentiment in product reviews
+var sentimentLexicon = map[string]float64{
+	"amazing": 3, "awesome": 3, "excellent": 3, "fantastic": 3, "love": 3, "loved": 3, "loves": 3,
+	"perfect": 3, "perfectly": 3, "outstanding": 3, "superb": 3,
+	"great": 2, "beautiful": 2, "comfortable": 2, "comfy": 2, "durable": 2, "happy": 2, "impressed": 2,
+	"recommend": 2, "reliable": 2, "sturdy": 2, "wonderful": 2, "favorite": 2, "pleased": 2,
+	"good": 1, "nice": 1, "easy": 1, "fast": 1, "solid": 1, "soft": 1, "worth": 1, "fine": 1,
+	"quick": 1, "works": 1, "lightweight": 1, "cute": 1, "warm": 1, "quiet": 1,
+	"terrible": -3, "awful": -3, "horrible": -3, "worst": -3, "hate": -3, "useless": -3, "defective": -3,
+	"junk": -3, "garbage": -3,
+	"bad": -2, "broke": -2, "broken": -2, "poor": -2, "poorly": -2, "disappointed": -2,
+	"disappointing": -2, "flimsy": -2, "uncomfortable": -2, "overpriced": -2, "returned": -2, "waste": -2,
+	"ripped": -2, "torn": -2, "leaks": -2, "leaked": -2, "faulty": -2, "painful": -2,
+	"cheap": -1, "slow": -1, "hard": -1, "difficult": -1, "tight": -1, "loose": -1, "noisy": -1,
+	"expensive": -1, "smell": -1, "smells": -1, "scratched": -1, "stiff": -1, "annoying": -1, "flaky": -1,
+}
+
+var negators = map[string]bool{
+	"not": true, "no": true, "never": true, "nor": true, "hardly": true, "without": true,
+	"isn't": true, "wasn't": true, "aren't": true, "weren't": true, "don't": true, "doesn't": true,
+	"didn't": true, "can't": true, "cannot": true, "won't": true, "wouldn't": true, "couldn't": true,
+}
+
+var intensifiers = map[string]bool{
+	"very": true, "really": true, "extremely": true, "super": true, "so": true, "incredibly": true,
+	"absolutely": true, "totally": true,
+}
+
+// aspectStopWords are words that say nothing about the product itself, so
+// they are never pros or cons
+var aspectStopWords = map[string]bool{
+	"the": true, "and": true, "but": true, "for": true, "with": true, "this": true, "that": true,
+	"these": true, "those": true, "they": true, "them": true, "their": true, "its": true, "it's": true,
+	"was": true, "were": true, "are": true, "is": true, "been": true, "have": true, "has": true,
+	"had": true, "would": true, "will": true, "after": true, "before": true, "just": true, "also": true,
+	"all": true, "one": true, "two": true, "than": true, "then": true, "too": true, "out": true,
+	"from": true, "about": true, "what": true, "when": true, "which": true, "while": true, "because": true,
+	"product": true, "item": true, "thing": true, "bought": true, "buy": true, "purchase": true,
+	"purchased": true, "ordered": true, "order": true, "got": true, "get": true, "use": true, "used": true,
+	"using": true, "day": true, "days": true, "week": true, "weeks": true, "month": true, "months": true,
+	"time": true, "first": true, "again": true, "still": true, "much": true, "more": true, "some": true,
+	"very": true, "really": true, "even": true, "only": true, "overall": true, "would've": true,
+	"i'm": true, "i've": true, "you": true, "your": true, "our": true, "my": true, "me": true,
+	"pair": true, "pairs": true, "lot": true, "bit": true, "ever": true, "well": true, "like": true,
+}
+
+// LexiconSummarizer summarizes reviews with a fixed word list. A sentence's
+// sentiment is the sum of its words' scores, with "not" and the like
+// flipping the words after them and "very" and the like strengthening them.
+// Pros and cons are the other words of positive and negative sentences,
+// counted once per review. It needs nothing outside the process, so it is
+// deterministic for tests and local development.
+type LexiconSummarizer struct{}
+
+func NewLexiconSummarizer() *LexiconSummarizer {
+	return &LexiconSummarizer{}
+}
+
+func (s *LexiconSummarizer) Name() string {
+	return "lexicon"
+}
+
+// reviewSentence is a sentence of a review with its sentiment
+type reviewSentence struct {
+	text   string
+	review int
+	score  float64 // -1..1, 0 when it has no sentiment words
+	words  []string
+}
+
+func (s *LexiconSummarizer) Summarize(ctx context.Context, reviews []dto.ReviewText) (*dto.GeneratedReviewSummary, error) {
+	summary := &dto.GeneratedReviewSummary{Pros: []string{}, Cons: []string{}, Quotes: []string{}}
+	if len(reviews) == 0 {
+		return summary, nil
+	}
+
+	var sentences []reviewSentence
+	var total float64
+	for i, review := range reviews {
+		var reviewTotal float64
+		var scored int
+		for _, text := range splitSentences(review.Body) {
+			words := sentimentWords(text)
+			score, ok := sentenceSentiment(words)
+			sentences = append(sentences, reviewSentence{text: text, review: i, score: score, words: words})
+			if ok {
+				reviewTotal += score
+				scored++
+			}
+		}
+
+		// A review with no sentiment words falls back on its star rating
+		if scored > 0 {
+			total += reviewTotal / float64(scored)
+		} else {
+			total += float64(review.Rating-3) / 2
+		}
+	}
+
+	summary.Sentiment = math.Round(total/float64(len(reviews))*100) / 100
+	summary.Pros, summary.Cons = reviewAspects(sentences)
+	summary.Quotes = reviewQuotes(sentences, summary.Sentiment)
+	return summary, nil
+}
+
+// sentenceSentiment scores a sentence from -1 to 1, reporting false when no
+// word in it carries sentiment
+func sentenceSentiment(words []string) (float64, bool) {
+	var sum float64
+	found := false
+	for i, word := range words {
+		score, ok := sentimentLexicon[word]
+		if !ok {
+			continue
+		}
+		found = true
+
+		if i > 0 && intensifiers[words[i-1]] {
+			score *= intensifyBoost
+		}
+		for j := max(0, i-negationWindow); j < i; j++ {
+			if negators[words[j]] || strings.HasSuffix(words[j], "n't") {
+				score *= negationFactor
+				break
+			}
+		}
+		sum += score
+	}
+	if !found {
+		return 0, false
+	}
+	return sum / math.Sqrt(sum*sum+sentimentAlpha), true
+}
+
+// reviewAspects returns the words most often found in positive sentences
+// and in negative ones, counting each review once per word. A word goes to
+// whichever side more reviews put it on.
+func reviewAspects(sentences []reviewSentence) ([]string, []string) {
+	positive := map[string]map[int]bool{}
+	negative := map[string]map[int]bool{}
+	for _, sentence := range sentences {
+		if sentence.score == 0 {
+			continue
+		}
+		side := positive
+		if sentence.score < 0 {
+			side = negative
+		}
+		for _, word := range sentence.words {
+			if !isAspectWord(word) {
+				continue
+			}
+			word = singularWord(word)
+			if side[word] == nil {
+				side[word] = map[int]bool{}
+			}
+			side[word][sentence.review] = true
+		}
+	}
+
+	pick := func(side, other map[string]map[int]bool) []string {
+		words := make([]string, 0, len(side))
+		for word, reviews := range side {
+			if len(reviews) > len(other[word]) {
+				words = append(words, word)
+			}
+		}
+		sort.Slice(words, func(i, j int) bool {
+			if len(side[words[i]]) != len(side[words[j]]) {
+				return len(side[words[i]]) > len(side[words[j]])
+			}
+			return words[i] < words[j]
+		})
+		return words[:min(maxSummaryAspects, len(words))]
+	}
+	return pick(positive, negative), pick(negative, positive)
+}
+
+// reviewQuotes picks the most positive sentence, the most negative one and
+// the one closest to the overall sentiment, leaving out sentences too short
+// or too long to quote. Ties go to the earlier review.
+func reviewQuotes(sentences []reviewSentence, overall float64) []string {
+	var candidates []reviewSentence
+	for _, sentence := range sentences {
+		if sentence.score != 0 && len(sentence.words) >= minQuoteWords && len(sentence.text) <= maxQuoteLength {
+			candidates = append(candidates, sentence)
+		}
+	}
+	if len(candidates) == 0 {
+		return []string{}
+	}
+
+	picked := map[int]bool{}
+	quotes := []string{}
+	pick := func(better func(a, b reviewSentence) bool, accept func(reviewSentence) bool) {
+		best := -1
+		for i, candidate := range candidates {
+			if picked[i] || !accept(candidate) {
+				continue
+			}
+			if best < 0 || better(candidate, candidates[best]) {
+				best = i
+			}
+		}
+		if best >= 0 && len(quotes) < maxSummaryQuotes {
+			picked[best] = true
+			quotes = append(quotes, candidates[best].text)
+		}
+	}
+
+	pick(func(a, b reviewSentence) bool { return a.score > b.score },
+		func(s reviewSentence) bool { return s.score > 0 })
+	pick(func(a, b reviewSentence) bool { return a.score < b.score },
+		func(s reviewSentence) bool { return s.score < 0 })
+	pick(func(a, b reviewSentence) bool { return math.Abs(a.score-overall) < math.Abs(b.score-overall) },
+		func(reviewSentence) bool { return true })
+	return quotes
+}
+
+// splitSentences splits text after ".", "!" and "?" and at line breaks
+func splitSentences(text string) []string {
+	var sentences []string
+	var current strings.Builder
+	flush := func() {
+		if sentence := strings.TrimSpace(current.String()); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == '\n' {
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+			flush()
+		}
+	}
+	flush()
+	return sentences
+}
+
+// sentimentWords lowercases a sentence and splits it into words, keeping
+// apostrophes so "didn't" stays one word
+func sentimentWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+}
+
+func isAspectWord(word string) bool {
+	_, sentiment := sentimentLexicon[word]
+	return len(word) >= 3 && !sentiment && !negators[word] && !intensifiers[word] &&
+		!aspectStopWords[word] && !strings.ContainsAny(word, "'’")
+}
+
+// singularWord strips a plural "s", leaving words like "glass" alone
+func singularWord(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return word[:len(word)-1]
+	}
+	return word
+}
@@ -1,53 +1,264 @@
 package providers
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"io/fs"
+	"mime"
+	"net/url"
 	"os"
+	pathpkg "path"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+var (
+	ErrInvalidUploadSignature = errors.New("upload signature is invalid")
+	ErrUploadURLExpired       = errors.New("upload URL has expired")
+	ErrUploadTooLarge         = errors.New("upload is larger than allowed")
+	ErrUploadTooSmall         = errors.New("upload is smaller than its signed size")
+	ErrUploadContentType      = errors.New("upload content type does not match its URL")
 )
 
+// LocalUploadProvider stores files on disk. Direct uploads go to the API's
+// signed upload endpoint at uploadURL, which passes them to ReceiveUpload.
 type LocalUploadProvider struct {
-	basePath string
+	basePath  string
+	uploadURL string
+	secret    []byte
 }
 
-func NewLocalUploadProvider(basePath string) *LocalUploadProvider {
+// localTempPrefix starts the names of files being written by Put
+const localTempPrefix = ".upload-"
+
+func NewLocalUploadProvider(basePath, uploadURL, secret string) *LocalUploadProvider {
 	return &LocalUploadProvider{
-		basePath: basePath,
+		basePath:  basePath,
+		uploadURL: uploadURL,
+		secret:    []byte(secret),
 	}
 }
 
-func (l *LocalUploadProvider) UploadFile(file *multipart.FileHeader, path string) (string, error) {
+func (l *LocalUploadProvider) Put(ctx context.Context, path string, r io.Reader, _ dto.FileMetadata) (string, error) {
 	fullPath := filepath.Join(l.basePath, path)
 
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0750); err != nil {
 		return "", err
 	}
 
-	// Open source
-	source, err := file.Open()
+	// Write to a temporary file first, so a cancelled or failed upload
+	// never leaves a partial file under path
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), localTempPrefix+"*")
 	if err != nil {
 		return "", err
 	}
-	defer func() { _ = source.Close() }()
+	defer func() { _ = os.Remove(tmp.Name()) }()
 
-	// create destination
-	destination, err := os.Create(fullPath) //#nosec G304 -- path is sanitized via filepath.Join
-	if err != nil {
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: r}); err != nil {
+		_ = tmp.Close()
 		return "", err
 	}
-	defer func() { _ = destination.Close() }()
-
-	// Copy file
-	if _, err := io.Copy(destination, source); err != nil {
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
 		return "", err
 	}
 
 	return fmt.Sprintf("/uploads/%s", path), nil
 }
 
-func (l *LocalUploadProvider) DeleteFile(path string) error {
+func (l *LocalUploadProvider) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fullPath := filepath.Join(l.basePath, path)
+	return os.Open(fullPath) //#nosec G304 -- path is sanitized via filepath.Join
+}
+
+func (l *LocalUploadProvider) Stat(ctx context.Context, path string) (*dto.FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filepath.Join(l.basePath, path))
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory: %w", path, fs.ErrNotExist)
+	}
+	return localFileInfo(filepath.ToSlash(path), info), nil
+}
+
+func (l *LocalUploadProvider) List(ctx context.Context, prefix string) ([]dto.FileInfo, error) {
+	// Walk the deepest directory every match is in
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = pathpkg.Dir(dir)
+	}
+	root := filepath.Join(l.basePath, filepath.FromSlash(dir))
+
+	var files []dto.FileInfo
+	err := filepath.WalkDir(root, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && fullPath == root {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), localTempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.basePath, fullPath)
+		if err != nil {
+			return err
+		}
+		path := filepath.ToSlash(rel)
+		if !strings.HasPrefix(path, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, *localFileInfo(path, info))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(files, func(a, b dto.FileInfo) int { return strings.Compare(a.Path, b.Path) })
+	return files, nil
+}
+
+func (l *LocalUploadProvider) Delete(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	fullPath := filepath.Join(l.basePath, path)
 	return os.Remove(fullPath)
 }
+
+// PresignUpload signs the upload's method, path, content type, size limit
+// and expiry with the provider's secret. A PUT carries them in the URL's
+// query, a POST in its form fields.
+func (l *LocalUploadProvider) PresignUpload(_ context.Context, method, path, contentType string, maxBytes int64, expires time.Duration) (*dto.PresignedUpload, error) {
+	expiresAt := time.Now().Add(expires)
+	params := url.Values{
+		"path":         {path},
+		"content_type": {contentType},
+		"max_bytes":    {strconv.FormatInt(maxBytes, 10)},
+		"expires":      {strconv.FormatInt(expiresAt.Unix(), 10)},
+	}
+	params.Set("signature", l.sign(method, params))
+
+	if method == dto.UploadMethodPost {
+		fields := make(map[string]string, len(params))
+		for name := range params {
+			fields[name] = params.Get(name)
+		}
+		return &dto.PresignedUpload{
+			Method:    dto.UploadMethodPost,
+			URL:       l.uploadURL,
+			Fields:    fields,
+			ExpiresAt: expiresAt,
+		}, nil
+	}
+
+	return &dto.PresignedUpload{
+		Method:    dto.UploadMethodPut,
+		URL:       l.uploadURL + "?" + params.Encode(),
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ReceiveUpload checks the signed params of an upload sent with method and
+// stores body, which must be of contentType and within the signed size
+// limit, under the signed path. Like an S3 PUT, a PUT must be exactly the
+// signed size.
+func (l *LocalUploadProvider) ReceiveUpload(ctx context.Context, method string, params url.Values, contentType string, body io.Reader) error {
+	want := l.sign(method, params)
+	if !hmac.Equal([]byte(want), []byte(params.Get("signature"))) {
+		return ErrInvalidUploadSignature
+	}
+
+	expires, err := strconv.ParseInt(params.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return ErrUploadURLExpired
+	}
+	if mediaType, _, _ := strings.Cut(contentType, ";"); strings.TrimSpace(mediaType) != params.Get("content_type") {
+		return fmt.Errorf("%w: must be %s", ErrUploadContentType, params.Get("content_type"))
+	}
+	maxBytes, err := strconv.ParseInt(params.Get("max_bytes"), 10, 64)
+	if err != nil {
+		return ErrInvalidUploadSignature
+	}
+
+	_, err = l.Put(ctx, params.Get("path"), &maxBytesReader{
+		r:         body,
+		remaining: maxBytes,
+		exact:     method == dto.UploadMethodPut,
+	}, dto.FileMetadata{
+		ContentType: params.Get("content_type"),
+		Size:        -1,
+	})
+	return err
+}
+
+func (l *LocalUploadProvider) sign(method string, params url.Values) string {
+	mac := hmac.New(sha256.New, l.secret)
+	for _, part := range []string{method, params.Get("path"), params.Get("content_type"), params.Get("max_bytes"), params.Get("expires")} {
+		mac.Write([]byte(part))
+		mac.Write([]byte{0})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func localFileInfo(path string, info fs.FileInfo) *dto.FileInfo {
+	return &dto.FileInfo{
+		Path:        path,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(pathpkg.Ext(path)),
+		ModTime:     info.ModTime(),
+	}
+}
+
+// maxBytesReader reads from r until more than remaining bytes have been
+// read, then fails with ErrUploadTooLarge. When exact, r ending before all
+// remaining bytes were read fails with ErrUploadTooSmall.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+	exact     bool
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n, ErrUploadTooLarge
+	}
+	if errors.Is(err, io.EOF) && m.exact && m.remaining > 0 {
+		return n, ErrUploadTooSmall
+	}
+	return n, err
+}
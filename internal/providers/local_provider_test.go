@@ -0,0 +1,217 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const uploadPath = "incoming/products/1/upload"
+
+func newLocalProvider(t *testing.T) *LocalUploadProvider {
+	t.Helper()
+	return NewLocalUploadProvider(t.TempDir(), "http://localhost:8000/api/v1/uploads/local", "upload-secret")
+}
+
+// presignedParams presigns an upload and returns the params its request
+// would carry
+func presignedParams(t *testing.T, provider *LocalUploadProvider, method string, maxBytes int64, expires time.Duration) url.Values {
+	t.Helper()
+
+	presigned, err := provider.PresignUpload(context.Background(), method, uploadPath, "image/png", maxBytes, expires)
+	require.NoError(t, err)
+
+	if method == dto.UploadMethodPost {
+		params := url.Values{}
+		for name, value := range presigned.Fields {
+			params.Set(name, value)
+		}
+		return params
+	}
+	u, err := url.Parse(presigned.URL)
+	require.NoError(t, err)
+	return u.Query()
+}
+
+func assertNotStored(t *testing.T, provider *LocalUploadProvider) {
+	t.Helper()
+
+	_, err := provider.Stat(context.Background(), uploadPath)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLocalUploadProvider_ReceiveUpload(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("0123456789")
+
+	t.Run("stores a PUT", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPut, 10, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, "image/png", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		info, err := provider.Stat(context.Background(), uploadPath)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), info.Size)
+	})
+
+	t.Run("stores a smaller POST", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPost, 100, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPost, params, "image/png", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		info, err := provider.Stat(context.Background(), uploadPath)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), info.Size)
+	})
+
+	t.Run("accepts content type parameters", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPut, 10, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, "image/png; name=front.png", bytes.NewReader(data))
+		require.NoError(t, err)
+	})
+
+	tampered := []struct {
+		name  string
+		param string
+		value string
+	}{
+		{"path", "path", "images/ab/abc.png"},
+		{"content type", "content_type", "image/jpeg"},
+		{"size", "max_bytes", "1000000"},
+		{"expiry", "expires", "9999999999"},
+		{"signature", "signature", strings.Repeat("0", 64)},
+	}
+	for _, tc := range tampered {
+		t.Run("rejects a tampered "+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newLocalProvider(t)
+			params := presignedParams(t, provider, dto.UploadMethodPut, 10, time.Minute)
+			params.Set(tc.param, tc.value)
+
+			err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, params.Get("content_type"), bytes.NewReader(data))
+			assert.ErrorIs(t, err, ErrInvalidUploadSignature)
+			assertNotStored(t, provider)
+		})
+	}
+
+	t.Run("rejects another method", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPut, 100, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPost, params, "image/png", bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidUploadSignature)
+		assertNotStored(t, provider)
+	})
+
+	t.Run("rejects another secret's signature", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		other := NewLocalUploadProvider(t.TempDir(), "http://localhost:8000/api/v1/uploads/local", "other-secret")
+		params := presignedParams(t, other, dto.UploadMethodPut, 10, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, "image/png", bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidUploadSignature)
+		assertNotStored(t, provider)
+	})
+
+	t.Run("rejects an expired signature", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPut, 10, -time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, "image/png", bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrUploadURLExpired)
+		assertNotStored(t, provider)
+	})
+
+	t.Run("rejects another content type", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPut, 10, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, "text/html", bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrUploadContentType)
+		assertNotStored(t, provider)
+	})
+
+	t.Run("rejects a larger body", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPost, 9, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPost, params, "image/png", bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrUploadTooLarge)
+		assertNotStored(t, provider)
+	})
+
+	t.Run("rejects a smaller PUT", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newLocalProvider(t)
+		params := presignedParams(t, provider, dto.UploadMethodPut, 11, time.Minute)
+
+		err := provider.ReceiveUpload(context.Background(), dto.UploadMethodPut, params, "image/png", bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrUploadTooSmall)
+		assertNotStored(t, provider)
+	})
+}
+
+func TestMaxBytesReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		remaining int64
+		exact     bool
+		wantErr   error
+	}{
+		{"within the limit", 20, false, nil},
+		{"at the limit", 10, false, nil},
+		{"one byte over", 9, false, ErrUploadTooLarge},
+		{"exactly the size", 10, true, nil},
+		{"short of the size", 11, true, ErrUploadTooSmall},
+		{"over the size", 9, true, ErrUploadTooLarge},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			_, err := out.ReadFrom(&maxBytesReader{r: strings.NewReader("0123456789"), remaining: tc.remaining, exact: tc.exact})
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, "0123456789", out.String())
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
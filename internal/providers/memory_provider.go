@@ -0,0 +1,137 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// MemoryUploadProvider keeps files in memory. It lets services that store
+// files run in tests without a disk or a bucket, and lets tests read back
+// what was stored.
+type MemoryUploadProvider struct {
+	mu    sync.Mutex
+	files map[string]memoryFile
+}
+
+type memoryFile struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func NewMemoryUploadProvider() *MemoryUploadProvider {
+	return &MemoryUploadProvider{files: make(map[string]memoryFile)}
+}
+
+func (m *MemoryUploadProvider) Put(ctx context.Context, path string, r io.Reader, meta dto.FileMetadata) (string, error) {
+	data, err := io.ReadAll(contextReader{ctx: ctx, r: r})
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.files[path] = memoryFile{data: data, contentType: meta.ContentType, modTime: time.Now()}
+	return "memory://" + path, nil
+}
+
+func (m *MemoryUploadProvider) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	file, err := m.file(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(file.data)), nil
+}
+
+func (m *MemoryUploadProvider) Stat(ctx context.Context, path string) (*dto.FileInfo, error) {
+	file, err := m.file(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info := file.info(path)
+	return &info, nil
+}
+
+func (m *MemoryUploadProvider) List(ctx context.Context, prefix string) ([]dto.FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var files []dto.FileInfo
+	for path, file := range m.files {
+		if strings.HasPrefix(path, prefix) {
+			files = append(files, file.info(path))
+		}
+	}
+	slices.SortFunc(files, func(a, b dto.FileInfo) int { return strings.Compare(a.Path, b.Path) })
+	return files, nil
+}
+
+func (m *MemoryUploadProvider) Delete(ctx context.Context, path string) error {
+	if _, err := m.file(ctx, path); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.files, path)
+	return nil
+}
+
+// PresignUpload returns a request to a memory:// URL, which nothing serves.
+// Tests store the file with Put instead.
+func (m *MemoryUploadProvider) PresignUpload(ctx context.Context, method, path, contentType string, maxBytes int64, expires time.Duration) (*dto.PresignedUpload, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	upload := &dto.PresignedUpload{
+		Method:    method,
+		URL:       "memory://" + path,
+		ExpiresAt: time.Now().Add(expires),
+	}
+	if method == dto.UploadMethodPost {
+		upload.Fields = map[string]string{"Content-Type": contentType}
+	} else {
+		upload.Headers = map[string]string{"Content-Type": contentType}
+	}
+	return upload, nil
+}
+
+func (m *MemoryUploadProvider) file(ctx context.Context, path string) (memoryFile, error) {
+	if err := ctx.Err(); err != nil {
+		return memoryFile{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, ok := m.files[path]
+	if !ok {
+		return memoryFile{}, fmt.Errorf("file %s: %w", path, fs.ErrNotExist)
+	}
+	return file, nil
+}
+
+func (f memoryFile) info(path string) dto.FileInfo {
+	return dto.FileInfo{
+		Path:        path,
+		Size:        int64(len(f.data)),
+		ContentType: f.contentType,
+		ModTime:     f.modTime,
+	}
+}
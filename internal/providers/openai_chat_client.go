@@ -0,0 +1,231 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// OpenAIChatClient is an LLMClient for a chat model behind an
+// OpenAI-compatible API with function calling. Replies are streamed.
+type OpenAIChatClient struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewOpenAIChatClient(baseURL, apiKey, model string) *OpenAIChatClient {
+	return &OpenAIChatClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type toolChatMessage struct {
+	Role       string         `json:"role"`
+	Content    *string        `json:"content"`
+	ToolCalls  []toolCallWire `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type toolCallWire struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON encoded as a string
+	} `json:"function"`
+}
+
+type toolWire struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters"`
+	} `json:"function"`
+}
+
+type toolChatRequest struct {
+	Model    string            `json:"model"`
+	Messages []toolChatMessage `json:"messages"`
+	Tools    []toolWire        `json:"tools,omitempty"`
+	Stream   bool              `json:"stream"`
+}
+
+// toolChatChunk is a piece of a streamed reply. Tool calls arrive in pieces
+// too: the first piece of each has its ID and name, and its arguments are
+// split over all of them.
+type toolChatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (c *OpenAIChatClient) Complete(ctx context.Context, messages []dto.ChatMessage, tools []dto.ToolDefinition, onContent func(string)) (*dto.ChatMessage, error) {
+	request := toolChatRequest{
+		Model:    c.model,
+		Messages: make([]toolChatMessage, len(messages)),
+		Stream:   true,
+	}
+	for i, message := range messages {
+		request.Messages[i] = toToolChatMessage(message)
+	}
+	for _, tool := range tools {
+		var wire toolWire
+		wire.Type = "function"
+		wire.Function.Name = tool.Name
+		wire.Function.Description = tool.Description
+		wire.Function.Parameters = tool.Parameters
+		request.Tools = append(request.Tools, wire)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call chat API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("chat API returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	return readChatStream(resp.Body, onContent)
+}
+
+// readChatStream puts together the message streamed as server-sent events,
+// passing its content to onContent as it arrives
+func readChatStream(body io.Reader, onContent func(string)) (*dto.ChatMessage, error) {
+	var (
+		content strings.Builder
+		calls   []toolCallWire
+		done    bool
+	)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+
+		var chunk toolChatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode chat response: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		delta := chunk.Choices[0].Delta
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			onContent(delta.Content)
+		}
+		for _, piece := range delta.ToolCalls {
+			if piece.Index < 0 || piece.Index > len(calls) {
+				return nil, fmt.Errorf("chat API streamed tool call %d out of order", piece.Index)
+			}
+			if piece.Index == len(calls) {
+				calls = append(calls, toolCallWire{Type: "function"})
+			}
+			call := &calls[piece.Index]
+			if piece.ID != "" {
+				call.ID = piece.ID
+			}
+			if piece.Function.Name != "" {
+				call.Function.Name = piece.Function.Name
+			}
+			call.Function.Arguments += piece.Function.Arguments
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat response: %w", err)
+	}
+	if !done {
+		return nil, fmt.Errorf("chat API response ended early")
+	}
+
+	text := content.String()
+	return fromToolChatMessage(toolChatMessage{Content: &text, ToolCalls: calls}), nil
+}
+
+func toToolChatMessage(message dto.ChatMessage) toolChatMessage {
+	wire := toolChatMessage{
+		Role:       message.Role,
+		ToolCallID: message.ToolCallID,
+	}
+	// Assistant messages that only call tools are sent with a null content
+	if message.Content != "" || len(message.ToolCalls) == 0 {
+		content := message.Content
+		wire.Content = &content
+	}
+	for _, call := range message.ToolCalls {
+		var w toolCallWire
+		w.ID = call.ID
+		w.Type = "function"
+		w.Function.Name = call.Name
+		w.Function.Arguments = string(call.Arguments)
+		wire.ToolCalls = append(wire.ToolCalls, w)
+	}
+	return wire
+}
+
+func fromToolChatMessage(wire toolChatMessage) *dto.ChatMessage {
+	message := &dto.ChatMessage{Role: dto.ChatRoleAssistant}
+	if wire.Content != nil {
+		message.Content = *wire.Content
+	}
+	for _, call := range wire.ToolCalls {
+		arguments := json.RawMessage(call.Function.Arguments)
+		if len(arguments) == 0 {
+			arguments = json.RawMessage("{}")
+		}
+		message.ToolCalls = append(message.ToolCalls, dto.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: arguments,
+		})
+	}
+	return message
+}
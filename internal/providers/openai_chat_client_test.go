@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// chatStreamServer answers chat requests by streaming chunks as server-sent
+// events, ending with [DONE] unless cut is set
+func chatStreamServer(t *testing.T, chunks []string, cut bool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request toolChatRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.True(t, request.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		if !cut {
+			_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAIChatClient_Complete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reply streams as it arrives", func(t *testing.T) {
+		t.Parallel()
+
+		server := chatStreamServer(t, []string{
+			`{"choices":[{"delta":{"role":"assistant","content":"Hello"}}]}`,
+			`{"choices":[{"delta":{"content":" there."}}]}`,
+		}, false)
+
+		var pieces []string
+		client := NewOpenAIChatClient(server.URL, "", "test-model")
+		reply, err := client.Complete(context.Background(), []dto.ChatMessage{{Role: dto.ChatRoleUser, Content: "hi"}}, nil, func(piece string) {
+			pieces = append(pieces, piece)
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Hello", " there."}, pieces)
+		assert.Equal(t, &dto.ChatMessage{Role: dto.ChatRoleAssistant, Content: "Hello there."}, reply)
+	})
+
+	t.Run("tool calls are put together from their pieces", func(t *testing.T) {
+		t.Parallel()
+
+		server := chatStreamServer(t, []string{
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"search_products","arguments":""}}]}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"query\":"}}]}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"socks\"}"}}]}}]}`,
+			`{"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_orders","arguments":""}}]}}]}`,
+		}, false)
+
+		client := NewOpenAIChatClient(server.URL, "", "test-model")
+		reply, err := client.Complete(context.Background(), nil, nil, func(string) {
+			t.Error("tool calls have no content")
+		})
+		require.NoError(t, err)
+
+		require.Len(t, reply.ToolCalls, 2)
+		assert.Equal(t, "call_1", reply.ToolCalls[0].ID)
+		assert.Equal(t, "search_products", reply.ToolCalls[0].Name)
+		assert.JSONEq(t, `{"query":"socks"}`, string(reply.ToolCalls[0].Arguments))
+		assert.Equal(t, "get_orders", reply.ToolCalls[1].Name)
+		assert.JSONEq(t, `{}`, string(reply.ToolCalls[1].Arguments))
+	})
+
+	t.Run("stream cut short", func(t *testing.T) {
+		t.Parallel()
+
+		server := chatStreamServer(t, []string{`{"choices":[{"delta":{"content":"Hel"}}]}`}, true)
+
+		client := NewOpenAIChatClient(server.URL, "", "test-model")
+		_, err := client.Complete(context.Background(), nil, nil, func(string) {})
+		assert.ErrorContains(t, err, "ended early")
+	})
+}
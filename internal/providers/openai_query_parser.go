@@ -0,0 +1,199 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+// maxPromptAttributeValues caps how many values of one attribute the prompt
+// lists, to bound its size on large catalogs
+const maxPromptAttributeValues = 50
+
+const queryParserPrompt = `You turn shopping search queries into filters. Reply with a JSON object with these keys:
+"keywords": the words of the query that are not filters,
+"categories": names of matching categories from the list below,
+"min_price" and "max_price": numbers, or null,
+"attributes": [{"name": ..., "value": ...}] using only the attribute values listed below,
+"sort": one of "price_asc", "price_desc", "newest", or "" when the query asks for no order.
+Leave out anything the query does not ask for.
+
+Categories: %s
+Attributes:
+%s`
+
+// OpenAIQueryParser asks a chat model behind an OpenAI-compatible API to
+// read filters out of a search. It handles phrasing the rule-based parser
+// has no pattern for, such as "something warm for winter hikes".
+type OpenAIQueryParser struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewOpenAIQueryParser(baseURL, apiKey, model string) *OpenAIQueryParser {
+	return &OpenAIQueryParser{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// parsedQueryReply is the JSON the model is asked to reply with
+type parsedQueryReply struct {
+	Keywords   string                 `json:"keywords"`
+	Categories []string               `json:"categories"`
+	MinPrice   *json.Number           `json:"min_price"`
+	MaxPrice   *json.Number           `json:"max_price"`
+	Attributes []dto.ProductAttribute `json:"attributes"`
+	Sort       string                 `json:"sort"`
+}
+
+func (p *OpenAIQueryParser) Parse(ctx context.Context, query string, vocab dto.SearchVocabulary) (*dto.ParsedQuery, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: p.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: queryParserSystemPrompt(vocab)},
+			{Role: "user", Content: query},
+		},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call chat API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("chat API returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	var result chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode chat response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("chat API returned no choices")
+	}
+
+	var reply parsedQueryReply
+	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &reply); err != nil {
+		return nil, fmt.Errorf("failed to decode parsed query: %w", err)
+	}
+	return reply.toParsedQuery(vocab), nil
+}
+
+func queryParserSystemPrompt(vocab dto.SearchVocabulary) string {
+	categories := make([]string, len(vocab.Categories))
+	for i, category := range vocab.Categories {
+		categories[i] = category.Name
+	}
+
+	var names []string
+	values := map[string][]string{}
+	for _, attr := range vocab.Attributes {
+		if _, ok := values[attr.Name]; !ok {
+			names = append(names, attr.Name)
+		}
+		if len(values[attr.Name]) < maxPromptAttributeValues {
+			values[attr.Name] = append(values[attr.Name], attr.Value)
+		}
+	}
+	var attributes strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&attributes, "- %s: %s\n", name, strings.Join(values[name], ", "))
+	}
+
+	return fmt.Sprintf(queryParserPrompt, strings.Join(categories, ", "), attributes.String())
+}
+
+// toParsedQuery keeps only what the catalog has: categories and attribute
+// values the model made up, and sort orders search doesn't offer, are
+// dropped
+func (r parsedQueryReply) toParsedQuery(vocab dto.SearchVocabulary) *dto.ParsedQuery {
+	parsed := &dto.ParsedQuery{
+		Keywords:   strings.TrimSpace(r.Keywords),
+		Categories: []dto.ParsedCategory{},
+		MinPrice:   replyAmount(r.MinPrice),
+		MaxPrice:   replyAmount(r.MaxPrice),
+		Attributes: []dto.ProductAttribute{},
+	}
+
+	for _, name := range r.Categories {
+		for _, category := range vocab.Categories {
+			if strings.EqualFold(name, category.Name) {
+				parsed.Categories = append(parsed.Categories, category)
+				break
+			}
+		}
+	}
+	for _, attr := range r.Attributes {
+		for _, known := range vocab.Attributes {
+			if strings.EqualFold(attr.Name, known.Name) && strings.EqualFold(attr.Value, known.Value) {
+				parsed.Attributes = append(parsed.Attributes, known)
+				break
+			}
+		}
+	}
+
+	switch r.Sort {
+	case dto.SearchSortPriceAsc, dto.SearchSortPriceDesc, dto.SearchSortNewest:
+		parsed.Sort = r.Sort
+	}
+	return parsed
+}
+
+// replyAmount reads a price from the model, dropping ones that aren't a
+// positive amount of money
+func replyAmount(n *json.Number) *money.Money {
+	if n == nil {
+		return nil
+	}
+	amount, err := money.Parse(n.String())
+	if err != nil || amount <= 0 {
+		return nil
+	}
+	return &amount
+}
@@ -2,22 +2,28 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"mime/multipart"
+	"io"
+	"io/fs"
 	"path/filepath"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
 )
 
 type S3UploadProvider struct {
-	client   *s3.Client
-	uploader *manager.Uploader
-	bucket   string
-	endpoint string
+	client    *s3.Client
+	uploader  *manager.Uploader
+	presigner *s3.PresignClient
+	bucket    string
+	endpoint  string
 }
 
 // S3Config holds the configuration for S3 uploads
@@ -27,6 +33,10 @@ type S3Config struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	Bucket          string
+	// PublicEndpoint is the endpoint clients reach S3 at, when it differs
+	// from Endpoint (e.g. LocalStack inside Docker). Presigned URLs are
+	// signed for its host.
+	PublicEndpoint string
 }
 
 func NewS3UploadProvider(cfg S3Config) (*S3UploadProvider, error) {
@@ -56,31 +66,32 @@ func NewS3UploadProvider(cfg S3Config) (*S3UploadProvider, error) {
 	// Create uploader using s3 manager
 	uploader := manager.NewUploader(client)
 
+	presignClient := client
+	if cfg.PublicEndpoint != "" && cfg.PublicEndpoint != cfg.Endpoint {
+		presignClient = s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(cfg.PublicEndpoint)
+			o.UsePathStyle = true
+		})
+	}
+
 	return &S3UploadProvider{
-		client:   client,
-		uploader: uploader,
-		bucket:   cfg.Bucket,
-		endpoint: cfg.Endpoint,
+		client:    client,
+		uploader:  uploader,
+		presigner: s3.NewPresignClient(presignClient),
+		bucket:    cfg.Bucket,
+		endpoint:  cfg.Endpoint,
 	}, nil
 }
 
-func (s *S3UploadProvider) UploadFile(file *multipart.FileHeader, path string) (string, error) {
-	// Open source file
-	source, err := file.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer func() { _ = source.Close() }()
-
-	// Clean the path
+func (s *S3UploadProvider) Put(ctx context.Context, path string, r io.Reader, meta dto.FileMetadata) (string, error) {
 	key := filepath.ToSlash(path)
 
-	// Upload to S3 using the manager
-	result, err := s.uploader.Upload(context.TODO(), &s3.PutObjectInput{
+	// Upload to S3 using the manager, which streams large bodies in parts
+	result, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(key),
-		Body:        source,
-		ContentType: aws.String(file.Header.Get("Content-Type")),
+		Body:        r,
+		ContentType: aws.String(meta.ContentType),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file to S3: %w", err)
@@ -90,10 +101,78 @@ func (s *S3UploadProvider) UploadFile(file *multipart.FileHeader, path string) (
 	return result.Location, nil
 }
 
-func (s *S3UploadProvider) DeleteFile(path string) error {
+func (s *S3UploadProvider) Get(ctx context.Context, path string) (io.ReadCloser, error) {
 	key := filepath.ToSlash(path)
 
-	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("file %s: %w", key, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to get file from S3: %w", err)
+	}
+
+	return result.Body, nil
+}
+
+func (s *S3UploadProvider) Stat(ctx context.Context, path string) (*dto.FileInfo, error) {
+	key := filepath.ToSlash(path)
+
+	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		// HEAD responses have no body, so a missing key is a plain NotFound
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("file %s: %w", key, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to stat file in S3: %w", err)
+	}
+
+	return &dto.FileInfo{
+		Path:        key,
+		Size:        aws.ToInt64(result.ContentLength),
+		ContentType: aws.ToString(result.ContentType),
+		ModTime:     aws.ToTime(result.LastModified),
+	}, nil
+}
+
+// List pages through the bucket's keys under prefix. S3 lists keys in
+// UTF-8 binary order, which is path order.
+func (s *S3UploadProvider) List(ctx context.Context, prefix string) ([]dto.FileInfo, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(filepath.ToSlash(prefix)),
+	})
+
+	var files []dto.FileInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files in S3: %w", err)
+		}
+		for _, object := range page.Contents {
+			files = append(files, dto.FileInfo{
+				Path:    aws.ToString(object.Key),
+				Size:    aws.ToInt64(object.Size),
+				ModTime: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+func (s *S3UploadProvider) Delete(ctx context.Context, path string) error {
+	key := filepath.ToSlash(path)
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
@@ -103,3 +182,59 @@ func (s *S3UploadProvider) DeleteFile(path string) error {
 
 	return nil
 }
+
+// PresignUpload signs a PutObject request, or a POST policy that also
+// limits the size and content type of the upload
+func (s *S3UploadProvider) PresignUpload(ctx context.Context, method, path, contentType string, maxBytes int64, expires time.Duration) (*dto.PresignedUpload, error) {
+	key := filepath.ToSlash(path)
+	expiresAt := time.Now().Add(expires)
+
+	if method == dto.UploadMethodPost {
+		request, err := s.presigner.PresignPostObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		}, func(o *s3.PresignPostOptions) {
+			o.Expires = expires
+			o.Conditions = []interface{}{
+				map[string]string{"Content-Type": contentType},
+				[]interface{}{"content-length-range", 1, maxBytes},
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to presign S3 upload: %w", err)
+		}
+
+		request.Values["Content-Type"] = contentType
+		return &dto.PresignedUpload{
+			Method:    dto.UploadMethodPost,
+			URL:       request.URL,
+			Fields:    request.Values,
+			ExpiresAt: expiresAt,
+		}, nil
+	}
+
+	request, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		// Signed, so S3 rejects a body of any other size
+		ContentLength: aws.Int64(maxBytes),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign S3 upload: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": contentType}
+	for name := range request.SignedHeader {
+		// Clients set the host from the URL
+		if name != "Host" {
+			headers[name] = request.SignedHeader.Get(name)
+		}
+	}
+	return &dto.PresignedUpload{
+		Method:    dto.UploadMethodPut,
+		URL:       request.URL,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// ScriptedLLMClient is an LLMClient that plays back replies written in
+// advance, one per call, and records the conversations it was sent. It
+// streams each reply a word at a time. It lets the assistant's tool loop run
+// in tests and local development without a model.
+type ScriptedLLMClient struct {
+	mu      sync.Mutex
+	replies []dto.ChatMessage
+	calls   [][]dto.ChatMessage
+}
+
+func NewScriptedLLMClient(replies ...dto.ChatMessage) *ScriptedLLMClient {
+	return &ScriptedLLMClient{replies: replies}
+}
+
+func (c *ScriptedLLMClient) Complete(ctx context.Context, messages []dto.ChatMessage, tools []dto.ToolDefinition, onContent func(string)) (*dto.ChatMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, append([]dto.ChatMessage(nil), messages...))
+	if len(c.replies) == 0 {
+		return nil, fmt.Errorf("scripted LLM client has no reply left")
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	if reply.Role == "" {
+		reply.Role = dto.ChatRoleAssistant
+	}
+	for _, word := range strings.SplitAfter(reply.Content, " ") {
+		if word != "" {
+			onContent(word)
+		}
+	}
+	return &reply, nil
+}
+
+// Calls returns the messages of each Complete call so far, in order
+func (c *ScriptedLLMClient) Calls() [][]dto.ChatMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]dto.ChatMessage(nil), c.calls...)
+}
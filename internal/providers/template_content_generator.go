@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const (
+	// maxSEOTitleLength is about what search results show of a title
+	maxSEOTitleLength = 60
+	maxGeneratedTags  = 10
+	maxAltTextsShown  = 3
+)
+
+// tagStopWords are name words too common to be useful tags
+var tagStopWords = map[string]bool{
+	"and": true, "for": true, "the": true, "with": true, "from": true, "new": true,
+}
+
+// TemplateContentGenerator writes product copy by filling fixed templates
+// with the product's name, category, attributes and image alt text. The copy
+// is plain but accurate, and it needs nothing outside the process, which
+// makes it deterministic for tests and local development.
+type TemplateContentGenerator struct{}
+
+func NewTemplateContentGenerator() *TemplateContentGenerator {
+	return &TemplateContentGenerator{}
+}
+
+func (g *TemplateContentGenerator) Name() string {
+	return "template"
+}
+
+func (g *TemplateContentGenerator) Generate(ctx context.Context, input dto.ContentInput) (*dto.GeneratedContent, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("product name is required")
+	}
+	category := strings.TrimSpace(input.Category)
+
+	return &dto.GeneratedContent{
+		Description: templateDescription(name, category, input.Attributes, input.ImageAltTexts),
+		SEOTitle:    templateSEOTitle(name, category),
+		Tags:        templateTags(name, category, input.Attributes),
+	}, nil
+}
+
+// templateDescription reads like "The Trail Runner is part of our Shoes
+// range. Available in color red or blue and size 10. Pictured: side view."
+func templateDescription(name, category string, attributes []dto.ProductAttribute, altTexts []string) string {
+	var sentences []string
+	if category != "" {
+		sentences = append(sentences, fmt.Sprintf("The %s is part of our %s range.", name, category))
+	} else {
+		sentences = append(sentences, fmt.Sprintf("Introducing the %s.", name))
+	}
+
+	var names []string
+	values := map[string][]string{}
+	for _, attr := range attributes {
+		if _, ok := values[attr.Name]; !ok {
+			names = append(names, attr.Name)
+		}
+		values[attr.Name] = append(values[attr.Name], attr.Value)
+	}
+	if len(names) > 0 {
+		options := make([]string, len(names))
+		for i, n := range names {
+			options[i] = n + " " + strings.Join(values[n], " or ")
+		}
+		sentences = append(sentences, fmt.Sprintf("Available in %s.", joinAnd(options)))
+	}
+
+	var shown []string
+	for _, alt := range altTexts {
+		if alt = strings.TrimRight(strings.TrimSpace(alt), "."); alt != "" && len(shown) < maxAltTextsShown {
+			shown = append(shown, alt)
+		}
+	}
+	if len(shown) > 0 {
+		sentences = append(sentences, fmt.Sprintf("Pictured: %s.", strings.Join(shown, "; ")))
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// templateSEOTitle is "Name | Category", shortening the name at a word
+// boundary to fit maxSEOTitleLength
+func templateSEOTitle(name, category string) string {
+	suffix := ""
+	if category != "" {
+		suffix = " | " + category
+	}
+	if len(name)+len(suffix) <= maxSEOTitleLength {
+		return name + suffix
+	}
+
+	room := max(maxSEOTitleLength-len(suffix), 0)
+	short := name[:min(room, len(name))]
+	if i := strings.LastIndexByte(short, ' '); i > 0 {
+		short = short[:i]
+	}
+	return strings.TrimSpace(short) + suffix
+}
+
+// templateTags are the category, the attribute values and the distinctive
+// words of the name, lowercased and without repeats
+func templateTags(name, category string, attributes []dto.ProductAttribute) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	add := func(tag string) {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] && len(tags) < maxGeneratedTags {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	add(category)
+	for _, attr := range attributes {
+		add(attr.Value)
+	}
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len(word) >= 3 && !tagStopWords[word] {
+			add(word)
+		}
+	}
+	return tags
+}
+
+// joinAnd joins items as "a", "a and b" or "a, b and c"
+func joinAnd(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// AssistantChat godoc
+// @Summary      Chat with the shopping assistant
+// @Description  Send a message to the shopping assistant, which can search products, add to the cart and look up
+// @Description  orders for the user. The answer streams as server-sent events named after their type: delta
+// @Description  for each piece of text the model writes, tool_call, tool_result, message with the whole reply,
+// @Description  error and finally done. The conversation is kept until it is reset or goes quiet. Errors before
+// @Description  the stream starts are returned as JSON; closing the connection stops the answer.
+// @Tags         assistant
+// @Accept       json
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        request body dto.AssistantChatRequest true "Message"
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
+// @Success      200  {object}  dto.AssistantEvent
+// @Failure      400  {object}  utils.Response
+// @Failure      429  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /assistant/chat [post]
+func (s *Server) AssistantChat(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	var req dto.AssistantChatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request data", err)
+		return
+	}
+
+	// A reply with tool calls can take longer than the server's write
+	// timeout, which would cut the stream off
+	_ = http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+
+	// The stream starts with the first event, so errors before it can still
+	// be answered with a status code
+	streaming := false
+	emit := func(event dto.AssistantEvent) {
+		if !streaming {
+			ctx.Header("Content-Type", "text/event-stream")
+			ctx.Header("Cache-Control", "no-cache")
+			ctx.Header("Connection", "keep-alive")
+			ctx.Header("X-Accel-Buffering", "no")
+			streaming = true
+		}
+		ctx.SSEvent(event.Type, event)
+		ctx.Writer.Flush()
+	}
+
+	// The request's context ends when the client goes away, which stops
+	// the model and tool calls still to come
+	err := s.assistantService.Chat(ctx.Request.Context(), int32(userID), req.Message, ctx.GetString("currency"), emit) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		if !streaming {
+			if errors.Is(err, services.ErrAssistantBusy) {
+				utils.ErrorResponse(ctx, "Assistant is busy", http.StatusTooManyRequests, err)
+				return
+			}
+			utils.InternalErrorResponse(ctx, "Failed to get assistant reply", err)
+			return
+		}
+
+		message := "Failed to get assistant reply"
+		if errors.Is(err, services.ErrAssistantToolLimit) {
+			message = err.Error()
+		}
+		emit(dto.AssistantEvent{Type: dto.AssistantEventError, Error: message})
+	}
+	emit(dto.AssistantEvent{Type: dto.AssistantEventDone})
+}
+
+// ResetAssistantChat godoc
+// @Summary      Reset the shopping assistant conversation
+// @Description  Forget the user's conversation so the next message starts a new one
+// @Tags         assistant
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response
+// @Failure      429  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /assistant/chat [delete]
+func (s *Server) ResetAssistantChat(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	if err := s.assistantService.Reset(ctx.Request.Context(), int32(userID)); err != nil { //#nosec G115 -- user ID from auth middleware
+		if errors.Is(err, services.ErrAssistantBusy) {
+			utils.ErrorResponse(ctx, "Assistant is busy", http.StatusTooManyRequests, err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to reset conversation", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Conversation reset successfully", nil)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// GetBackInStockSubscription godoc
+// @Summary      Get back-in-stock subscription
+// @Description  Check whether the user is waiting to hear when a product is back in stock
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      200  {object}  utils.Response{data=dto.BackInStockSubscriptionResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/back-in-stock [get]
+func (s *Server) GetBackInStockSubscription(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	subscription, err := s.backInStockService.GetSubscription(ctx, int32(userID), int32(productID)) //#nosec G115 -- user ID from auth middleware, product ID parsed with a 32-bit limit
+	if err != nil {
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			utils.NotFoundResponse(ctx, "Not subscribed", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get subscription", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Subscription retrieved successfully", subscription)
+}
+
+// SubscribeBackInStock godoc
+// @Summary      Subscribe to back-in-stock email
+// @Description  Email the user once when an out-of-stock product is back in stock
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      201  {object}  utils.Response{data=dto.BackInStockSubscriptionResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/back-in-stock [post]
+func (s *Server) SubscribeBackInStock(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	subscription, err := s.backInStockService.Subscribe(ctx, int32(userID), int32(productID)) //#nosec G115 -- user ID from auth middleware, product ID parsed with a 32-bit limit
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrProductInStock):
+			utils.BadRequestResponse(ctx, "Product is in stock", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to subscribe", err)
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Subscribed successfully", subscription)
+}
+
+// UnsubscribeBackInStock godoc
+// @Summary      Unsubscribe from back-in-stock email
+// @Description  Stop waiting to hear when a product is back in stock
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/back-in-stock [delete]
+func (s *Server) UnsubscribeBackInStock(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	err = s.backInStockService.Unsubscribe(ctx, int32(userID), int32(productID)) //#nosec G115 -- user ID from auth middleware, product ID parsed with a 32-bit limit
+	if err != nil {
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			utils.NotFoundResponse(ctx, "Not subscribed", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to unsubscribe", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Unsubscribed successfully", nil)
+}
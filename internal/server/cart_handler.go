@@ -17,14 +17,20 @@ import (
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      200  {object}  utils.Response{data=dto.CartResponse}
+// @Failure      400  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
 // @Router       /cart [get]
 func (s *Server) GetCart(ctx *gin.Context) {
 	userID := ctx.GetUint("user_id")
 
-	cart, err := s.cartService.GetCart(ctx, int32(userID)) //#nosec G115 -- user ID from auth middleware
+	cart, err := s.cartService.GetCart(ctx, int32(userID), ctx.GetString("currency")) //#nosec G115 -- user ID from auth middleware
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
 		utils.InternalErrorResponse(ctx, "Failed to get cart", err)
 		return
 	}
@@ -40,6 +46,7 @@ func (s *Server) GetCart(ctx *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        request body dto.AddToCartRequest true "Product and quantity"
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      200  {object}  utils.Response{data=dto.CartResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
@@ -54,13 +61,15 @@ func (s *Server) AddToCart(ctx *gin.Context) {
 		return
 	}
 
-	cart, err := s.cartService.AddToCart(ctx, int32(userID), req) //#nosec G115 -- user ID from auth middleware
+	cart, err := s.cartService.AddToCart(ctx, int32(userID), req, ctx.GetString("currency")) //#nosec G115 -- user ID from auth middleware
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrProductNotFound):
 			utils.NotFoundResponse(ctx, "Product not found", err)
 		case errors.Is(err, services.ErrInsufficientStock):
 			utils.BadRequestResponse(ctx, "Insufficient stock", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
 		default:
 			utils.InternalErrorResponse(ctx, "Failed to add to cart", err)
 		}
@@ -79,6 +88,7 @@ func (s *Server) AddToCart(ctx *gin.Context) {
 // @Security     BearerAuth
 // @Param        itemId path int true "Cart Item ID"
 // @Param        request body dto.UpdateCartItemRequest true "New quantity"
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      200  {object}  utils.Response{data=dto.CartResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
@@ -100,7 +110,7 @@ func (s *Server) UpdateCartItem(ctx *gin.Context) {
 		return
 	}
 
-	cart, err := s.cartService.UpdateCartItem(ctx, int32(userID), int32(itemID), req) //#nosec G115 -- IDs from validated request
+	cart, err := s.cartService.UpdateCartItem(ctx, int32(userID), int32(itemID), req, ctx.GetString("currency")) //#nosec G115 -- IDs from validated request
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrCartNotFound):
@@ -109,6 +119,8 @@ func (s *Server) UpdateCartItem(ctx *gin.Context) {
 			utils.NotFoundResponse(ctx, "Cart item not found", err)
 		case errors.Is(err, services.ErrInsufficientStock):
 			utils.BadRequestResponse(ctx, "Insufficient stock", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
 		default:
 			utils.InternalErrorResponse(ctx, "Failed to update cart item", err)
 		}
@@ -126,6 +138,7 @@ func (s *Server) UpdateCartItem(ctx *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        itemId path int true "Cart Item ID"
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      200  {object}  utils.Response{data=dto.CartResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
@@ -141,13 +154,15 @@ func (s *Server) RemoveCartItem(ctx *gin.Context) {
 		return
 	}
 
-	cart, err := s.cartService.RemoveCartItem(ctx, int32(userID), int32(itemID)) //#nosec G115 -- IDs from validated request
+	cart, err := s.cartService.RemoveCartItem(ctx, int32(userID), int32(itemID), ctx.GetString("currency")) //#nosec G115 -- IDs from validated request
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrCartNotFound):
 			utils.NotFoundResponse(ctx, "Cart not found", err)
 		case errors.Is(err, services.ErrCartItemNotFound):
 			utils.NotFoundResponse(ctx, "Cart item not found", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
 		default:
 			utils.InternalErrorResponse(ctx, "Failed to remove cart item", err)
 		}
@@ -178,3 +193,121 @@ func (s *Server) ClearCart(ctx *gin.Context) {
 
 	utils.SuccessResponse(ctx, "Cart cleared", nil)
 }
+
+// GetCartReservation godoc
+// @Summary      Get checkout reservation
+// @Description  Get the stock currently held for the user's checkout and when the hold expires
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=dto.ReservationResponse}
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /cart/reservation [get]
+func (s *Server) GetCartReservation(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	reservation, err := s.reservationService.GetReservation(ctx, int32(userID)) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		if errors.Is(err, services.ErrReservationNotFound) {
+			utils.NotFoundResponse(ctx, "No active reservation", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get reservation", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Reservation retrieved successfully", reservation)
+}
+
+// GetCartRecommendations godoc
+// @Summary      Get cart recommendations
+// @Description  Products customers also bought with the items in the user's cart, scored against
+// @Description  the whole cart. Products already in the cart are left out.
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Maximum recommendations" default(10)
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
+// @Success      200  {object}  utils.Response{data=[]dto.ProductRecommendation}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /cart/recommendations [get]
+func (s *Server) GetCartRecommendations(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	var req dto.RecommendationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid recommendation parameters", err)
+		return
+	}
+
+	recommendations, err := s.productService.GetCartRecommendations(ctx, int32(userID), req, ctx.GetString("currency")) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get recommendations", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Recommendations retrieved successfully", recommendations)
+}
+
+// ReserveCart godoc
+// @Summary      Reserve cart stock
+// @Description  Hold stock for every cart item while the user checks out. Replaces any earlier hold and expires after the configured TTL.
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201  {object}  utils.Response{data=dto.ReservationResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /cart/reservation [post]
+func (s *Server) ReserveCart(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	reservation, err := s.reservationService.ReserveCart(ctx, int32(userID)) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmptyCart):
+			utils.BadRequestResponse(ctx, "Cart is empty", err)
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrInsufficientStock):
+			utils.BadRequestResponse(ctx, "Insufficient stock", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to reserve stock", err)
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Stock reserved successfully", reservation)
+}
+
+// ReleaseCartReservation godoc
+// @Summary      Release cart reservation
+// @Description  Give back the stock held for the user's checkout
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /cart/reservation [delete]
+func (s *Server) ReleaseCartReservation(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	err := s.reservationService.ReleaseReservation(ctx, int32(userID)) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to release reservation", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Reservation released", nil)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// SuggestProductCategory godoc
+// @Summary      Suggest a product category (Admin)
+// @Description  Rank the categories a product could go in from its name and description, using a classifier
+// @Description  trained on the products already in each category. Confidence is from 0 to 1.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.SuggestCategoryRequest true "Product name and description"
+// @Success      200  {object}  utils.Response{data=[]dto.CategorySuggestion}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/products/suggest-category [post]
+func (s *Server) SuggestProductCategory(ctx *gin.Context) {
+	var req dto.SuggestCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request data", err)
+		return
+	}
+
+	suggestions, err := s.categorization.SuggestCategory(ctx, req)
+	if err != nil {
+		if errors.Is(err, services.ErrCategorizerUntrained) {
+			utils.BadRequestResponse(ctx, "Not enough categorized products to suggest a category", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to suggest category", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Category suggestions retrieved successfully", suggestions)
+}
+
+// ListCategoryMismatches godoc
+// @Summary      List products in the wrong category (Admin)
+// @Description  Products the classifier would file under another category with at least min_confidence,
+// @Description  most confident first. Each product is judged without its own words in the training data.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        min_confidence query number false "Confidence a different category needs, from 0 to 1" default(0.7)
+// @Param        limit query int false "Maximum products" default(50)
+// @Success      200  {object}  utils.Response{data=[]dto.CategoryMismatch}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/products/category-mismatches [get]
+func (s *Server) ListCategoryMismatches(ctx *gin.Context) {
+	var req dto.CategoryMismatchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid mismatch parameters", err)
+		return
+	}
+
+	mismatches, err := s.categorization.FindCategoryMismatches(ctx, req)
+	if err != nil {
+		if errors.Is(err, services.ErrCategorizerUntrained) {
+			utils.BadRequestResponse(ctx, "Not enough categorized products to check categories", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to find category mismatches", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Category mismatches retrieved successfully", mismatches)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// GenerateContentDraft godoc
+// @Summary      Draft product content (Admin)
+// @Description  Generate a description, SEO title and tags from the product's name, category, attributes
+// @Description  and image alt text. The draft is stored for review; the product is not changed.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      201  {object}  utils.Response{data=dto.ContentDraftResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/content-drafts [post]
+func (s *Server) GenerateContentDraft(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	draft, err := s.contentService.GenerateDraft(ctx, int32(userID), uint(id)) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to generate content draft", err)
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Content draft created successfully", draft)
+}
+
+// ListContentDrafts godoc
+// @Summary      List product content drafts (Admin)
+// @Description  Generated content drafts for a product, newest first
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      200  {object}  utils.Response{data=[]dto.ContentDraftResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/content-drafts [get]
+func (s *Server) ListContentDrafts(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	drafts, err := s.contentService.ListDrafts(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to list content drafts", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Content drafts retrieved successfully", drafts)
+}
+
+// AcceptContentDraft godoc
+// @Summary      Accept a product content draft (Admin)
+// @Description  Apply a pending draft's description, SEO title and tags to the product
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        draftId path int true "Content draft ID"
+// @Success      200  {object}  utils.Response{data=dto.ProductResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/content-drafts/{draftId}/accept [post]
+func (s *Server) AcceptContentDraft(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	id, draftID, ok := contentDraftParams(ctx)
+	if !ok {
+		return
+	}
+
+	product, err := s.contentService.AcceptDraft(ctx, int32(userID), id, draftID) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		handleContentDraftError(ctx, err, "Failed to accept content draft")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Content draft applied successfully", product)
+}
+
+// RejectContentDraft godoc
+// @Summary      Reject a product content draft (Admin)
+// @Description  Mark a pending draft rejected without changing the product
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        draftId path int true "Content draft ID"
+// @Success      200  {object}  utils.Response{data=dto.ContentDraftResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/content-drafts/{draftId}/reject [post]
+func (s *Server) RejectContentDraft(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	id, draftID, ok := contentDraftParams(ctx)
+	if !ok {
+		return
+	}
+
+	draft, err := s.contentService.RejectDraft(ctx, int32(userID), id, draftID) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		handleContentDraftError(ctx, err, "Failed to reject content draft")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Content draft rejected successfully", draft)
+}
+
+// contentDraftParams parses the product and draft IDs from the path,
+// responding with a bad request when either is invalid
+func contentDraftParams(ctx *gin.Context) (uint, uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return 0, 0, false
+	}
+
+	draftID, err := strconv.ParseUint(ctx.Param("draftId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid content draft ID", err)
+		return 0, 0, false
+	}
+	return uint(id), uint(draftID), true
+}
+
+func handleContentDraftError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrContentDraftNotFound):
+		utils.NotFoundResponse(ctx, "Content draft not found", err)
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.NotFoundResponse(ctx, "Product not found", err)
+	case errors.Is(err, services.ErrContentDraftReviewed):
+		utils.BadRequestResponse(ctx, "Content draft has already been reviewed", err)
+	default:
+		utils.InternalErrorResponse(ctx, message, err)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// GetFeed godoc
+// @Summary      Get personalized feed
+// @Description  Products picked for the user from their orders, cart and product views, ranked by a
+// @Description  weighted blend of popularity, category affinity and recency. Products the user has
+// @Description  bought or has in their cart are left out. Users with no history get the best-sellers.
+// @Tags         feed
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Maximum products" default(20)
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
+// @Success      200  {object}  utils.Response{data=dto.FeedResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /feed [get]
+func (s *Server) GetFeed(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	var req dto.FeedRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid feed parameters", err)
+		return
+	}
+
+	feed, err := s.feedService.GetFeed(ctx, int32(userID), req, ctx.GetString("currency")) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get feed", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Feed retrieved successfully", feed)
+}
+
+// RecordProductView godoc
+// @Summary      Record product view
+// @Description  Note that the user looked at a product, so their feed leans towards its category
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      201  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/views [post]
+func (s *Server) RecordProductView(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	if err := s.feedService.RecordView(ctx, int32(userID), int32(productID)); err != nil { //#nosec G115 -- user ID from auth middleware, product ID parsed with a 32-bit limit
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to record view", err)
+		return
+	}
+
+	utils.CreatedResponse(ctx, "View recorded", nil)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// ListDemandForecasts godoc
+// @Summary      Demand forecast and reorder report (Admin)
+// @Description  Each product's forecast daily demand, days of stock cover and recommended reorder quantity,
+// @Description  products that run out soonest first. Forecasts are refreshed from order history periodically.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(10)
+// @Success      200  {object}  utils.PaginatedResponse{data=[]dto.DemandForecastResponse}
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/forecasts [get]
+func (s *Server) ListDemandForecasts(ctx *gin.Context) {
+	// Parse pagination parameters
+	page := 1
+	limit := 10
+
+	if pageStr := ctx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	forecasts, pagination, err := s.forecasts.ListForecasts(ctx, page, limit)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to get demand forecasts", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(ctx, "Demand forecasts retrieved successfully", forecasts, *pagination)
+}
+
+// ExportDemandForecasts godoc
+// @Summary      Export demand forecasts as CSV (Admin)
+// @Description  The whole demand forecast and reorder report as a CSV file, in the same order as the report
+// @Tags         inventory
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Success      200  {file}    file
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/forecasts/export [get]
+func (s *Server) ExportDemandForecasts(ctx *gin.Context) {
+	// Build the file first so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	if err := s.forecasts.ExportCSV(ctx, &buf); err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to export demand forecasts", err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="demand_forecasts.csv"`)
+	ctx.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
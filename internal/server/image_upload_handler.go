@@ -0,0 +1,165 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// CreateProductImageUpload godoc
+// @Summary      Start a direct product image upload (Admin)
+// @Description  Returns a presigned request that uploads an image straight to storage, without it passing
+// @Description  through the API. A PUT sends the file as the body with the given headers; a POST sends the
+// @Description  given fields, then the file as a "file" field, in a multipart form, and is also limited to
+// @Description  the declared size. Complete the upload once the file is uploaded.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        request body dto.CreateImageUploadRequest true "Image to upload"
+// @Success      201  {object}  utils.Response{data=dto.ImageUploadResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images/uploads [post]
+func (s *Server) CreateProductImageUpload(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.CreateImageUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	upload, err := s.imageUploadService.CreateUpload(ctx, int32(userID), uint(id), req) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		handleImageUploadError(ctx, err, "Failed to start image upload")
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Image upload started successfully", upload)
+}
+
+// CompleteProductImageUpload godoc
+// @Summary      Complete a direct product image upload (Admin)
+// @Description  Processes an image uploaded with a presigned request like an image uploaded through the API,
+// @Description  and adds it to the product's images. An upload that is not a valid image fails, and has to be
+// @Description  started again.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        uploadId path int true "Upload ID"
+// @Success      201  {object}  utils.Response{data=dto.ImageUploadResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images/uploads/{uploadId}/complete [post]
+func (s *Server) CompleteProductImageUpload(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+	uploadID, err := strconv.ParseUint(ctx.Param("uploadId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid upload ID", err)
+		return
+	}
+
+	upload, err := s.imageUploadService.CompleteUpload(ctx, uint(id), uint(uploadID))
+	if err != nil {
+		handleImageUploadError(ctx, err, "Failed to complete image upload")
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Product image uploaded successfully", upload)
+}
+
+// ReceiveLocalUpload stores a file sent with a request presigned by the
+// local upload provider. It stands in for S3 when files are stored on disk,
+// so it needs no authentication beyond the request's signature.
+func (s *Server) ReceiveLocalUpload(ctx *gin.Context) {
+	var (
+		params      url.Values
+		contentType string
+		body        io.Reader
+	)
+	if ctx.Request.Method == http.MethodPost {
+		form, err := ctx.MultipartForm()
+		if err != nil {
+			utils.BadRequestResponse(ctx, "Invalid upload form", err)
+			return
+		}
+		files := form.File["file"]
+		if len(files) == 0 {
+			utils.BadRequestResponse(ctx, "Invalid upload form", errors.New("file is required"))
+			return
+		}
+		file, err := files[0].Open()
+		if err != nil {
+			utils.InternalErrorResponse(ctx, "Failed to read upload", err)
+			return
+		}
+		defer func() { _ = file.Close() }()
+
+		params = url.Values(form.Value)
+		contentType = params.Get("content_type")
+		body = file
+	} else {
+		params = ctx.Request.URL.Query()
+		contentType = ctx.GetHeader("Content-Type")
+		body = ctx.Request.Body
+	}
+
+	if err := s.localUploads.ReceiveUpload(ctx.Request.Context(), ctx.Request.Method, params, contentType, body); err != nil {
+		switch {
+		case errors.Is(err, providers.ErrInvalidUploadSignature),
+			errors.Is(err, providers.ErrUploadURLExpired),
+			errors.Is(err, providers.ErrUploadContentType):
+			utils.ForbiddenResponse(ctx, "Upload not allowed", err)
+		case errors.Is(err, providers.ErrUploadTooLarge):
+			utils.ErrorResponse(ctx, "Upload too large", http.StatusRequestEntityTooLarge, err)
+		case errors.Is(err, providers.ErrUploadTooSmall):
+			utils.BadRequestResponse(ctx, "Upload incomplete", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to store upload", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func handleImageUploadError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrImageUploadNotFound):
+		utils.NotFoundResponse(ctx, "Image upload not found", err)
+	case errors.Is(err, services.ErrImageUploadNotPending):
+		utils.ErrorResponse(ctx, "Image upload is no longer pending", http.StatusConflict, err)
+	case errors.Is(err, services.ErrImageNotUploaded):
+		utils.BadRequestResponse(ctx, "Image has not been uploaded yet", err)
+	case errors.Is(err, services.ErrUnsupportedImage),
+		errors.Is(err, services.ErrImageTooLarge),
+		errors.Is(err, services.ErrImageDimensions):
+		utils.BadRequestResponse(ctx, "Invalid image", err)
+	default:
+		handleProductImageError(ctx, err, message)
+	}
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// GetInventoryMovements godoc
+// @Summary      List stock movements (Admin)
+// @Description  Get the stock ledger for a product SKU, newest first
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sku path string true "Product SKU"
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(20)
+// @Success      200  {object}  utils.PaginatedResponse{data=[]dto.InventoryMovementResponse}
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/{sku}/movements [get]
+func (s *Server) GetInventoryMovements(ctx *gin.Context) {
+	sku := ctx.Param("sku")
+
+	// Parse pagination parameters
+	page := 1
+	limit := 20
+
+	if pageStr := ctx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	movements, pagination, err := s.inventoryService.GetMovementsBySKU(ctx, sku, page, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get stock movements", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(ctx, "Stock movements retrieved successfully", movements, *pagination)
+}
+
+// AdjustInventory godoc
+// @Summary      Adjust stock (Admin)
+// @Description  Apply a signed stock correction to a product and record it in the ledger
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sku path string true "Product SKU"
+// @Param        request body dto.StockAdjustmentRequest true "Adjustment data"
+// @Success      201  {object}  utils.Response{data=dto.InventoryMovementResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/{sku}/adjustments [post]
+func (s *Server) AdjustInventory(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+	sku := ctx.Param("sku")
+
+	var req dto.StockAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	movement, err := s.inventoryService.AdjustStock(ctx, int32(userID), sku, req) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrWarehouseNotFound):
+			utils.NotFoundResponse(ctx, "Warehouse not found", err)
+		case errors.Is(err, services.ErrInvalidStockAdjustment),
+			errors.Is(err, services.ErrInvalidMovementReason):
+			utils.BadRequestResponse(ctx, "Invalid stock adjustment", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to adjust stock", err)
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Stock adjusted successfully", movement)
+}
+
+// ReconcileInventory godoc
+// @Summary      Reconcile stock (Admin)
+// @Description  List products whose stock does not match their ledger movements or warehouse stock
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=[]dto.StockDiscrepancyResponse}
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/reconciliation [get]
+func (s *Server) ReconcileInventory(ctx *gin.Context) {
+	discrepancies, err := s.inventoryService.Reconcile(ctx)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to reconcile inventory", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Inventory reconciled successfully", discrepancies)
+}
+
+// GetInventoryByWarehouse godoc
+// @Summary      Stock by warehouse (Admin)
+// @Description  Get how a product's stock is split across warehouses
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sku path string true "Product SKU"
+// @Success      200  {object}  utils.Response{data=[]dto.WarehouseStockResponse}
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/{sku}/warehouses [get]
+func (s *Server) GetInventoryByWarehouse(ctx *gin.Context) {
+	stock, err := s.inventoryService.GetWarehouseStockBySKU(ctx, ctx.Param("sku"))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get warehouse stock", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Warehouse stock retrieved successfully", stock)
+}
+
+// ListWarehouses godoc
+// @Summary      List warehouses (Admin)
+// @Description  Get all warehouses in fulfilment priority order
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=[]dto.WarehouseResponse}
+// @Failure      500  {object}  utils.Response
+// @Router       /warehouses [get]
+func (s *Server) ListWarehouses(ctx *gin.Context) {
+	warehouses, err := s.inventoryService.ListWarehouses(ctx)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to list warehouses", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Warehouses retrieved successfully", warehouses)
+}
+
+// CreateWarehouse godoc
+// @Summary      Create warehouse (Admin)
+// @Description  Register a new fulfilment location
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.CreateWarehouseRequest true "Warehouse data"
+// @Success      201  {object}  utils.Response{data=dto.WarehouseResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /warehouses [post]
+func (s *Server) CreateWarehouse(ctx *gin.Context) {
+	var req dto.CreateWarehouseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	warehouse, err := s.inventoryService.CreateWarehouse(ctx, req)
+	if err != nil {
+		if errors.Is(err, services.ErrWarehouseExists) {
+			utils.BadRequestResponse(ctx, "Warehouse code already exists", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to create warehouse", err)
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Warehouse created successfully", warehouse)
+}
+
+// ListLowStock godoc
+// @Summary      List low stock (Admin)
+// @Description  Get every product below its reorder threshold or out of stock, emptiest first
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.Response{data=[]dto.LowStockResponse}
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/low-stock [get]
+func (s *Server) ListLowStock(ctx *gin.Context) {
+	products, err := s.inventoryService.ListLowStock(ctx)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to list low stock products", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Low stock products retrieved successfully", products)
+}
+
+// SetProductReorderThreshold godoc
+// @Summary      Set product reorder threshold (Admin)
+// @Description  Override the reorder threshold for a product; a null threshold falls back to its category's
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sku path string true "Product SKU"
+// @Param        request body dto.ReorderThresholdRequest true "Threshold data"
+// @Success      200  {object}  utils.Response{data=dto.ReorderThresholdResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /inventory/{sku}/reorder-threshold [put]
+func (s *Server) SetProductReorderThreshold(ctx *gin.Context) {
+	var req dto.ReorderThresholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	threshold, err := s.inventoryService.SetProductReorderThreshold(ctx, ctx.Param("sku"), req)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to set reorder threshold", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Reorder threshold updated successfully", threshold)
+}
+
+// SetCategoryReorderThreshold godoc
+// @Summary      Set category reorder threshold (Admin)
+// @Description  Set the reorder threshold for products in a category without their own; a null threshold falls back to the default
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Category ID"
+// @Param        request body dto.ReorderThresholdRequest true "Threshold data"
+// @Success      200  {object}  utils.Response{data=dto.ReorderThresholdResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /categories/{id}/reorder-threshold [put]
+func (s *Server) SetCategoryReorderThreshold(ctx *gin.Context) {
+	var req dto.ReorderThresholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid category ID", err)
+		return
+	}
+
+	threshold, err := s.inventoryService.SetCategoryReorderThreshold(ctx, int32(id), req) //#nosec G115 -- parsed with a 32-bit limit
+	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			utils.NotFoundResponse(ctx, "Category not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to set reorder threshold", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Reorder threshold updated successfully", threshold)
+}
@@ -0,0 +1,296 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+)
+
+// runExclusive runs job unless another replica is already running the job
+// of the same name, in which case it skips this run. The lock is a
+// transaction-level advisory lock held while job runs.
+func (s *Server) runExclusive(ctx context.Context, name string, job func(ctx context.Context)) error {
+	return s.store.ExecTx(ctx, func(q db.Querier) error {
+		locked, err := q.TryJobLock(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to lock job %s: %w", name, err)
+		}
+		if locked {
+			job(ctx)
+		}
+		return nil
+	})
+}
+
+// RunReservationSweeper releases expired stock reservations on every tick of
+// the configured interval until ctx is cancelled. Only one replica sweeps at
+// a time.
+func (s *Server) RunReservationSweeper(ctx context.Context) {
+	interval := s.cfg.Inventory.ReservationSweepInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.runExclusive(ctx, "reservation_sweeper", s.releaseExpiredReservations)
+			if err != nil {
+				s.logger.Error().Err(err).Msg("failed to run reservation sweeper")
+			}
+		}
+	}
+}
+
+func (s *Server) releaseExpiredReservations(ctx context.Context) {
+	released, err := s.reservationService.ReleaseExpired(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to release expired stock reservations")
+		return
+	}
+	if released > 0 {
+		s.logger.Info().Int64("released", released).Msg("Released expired stock reservations")
+	}
+}
+
+// RunEmbeddingIndexer refreshes stale product embeddings at startup, on every
+// tick of the configured interval, and whenever a product is created or
+// updated, until ctx is cancelled
+func (s *Server) RunEmbeddingIndexer(ctx context.Context) {
+	interval := s.cfg.Embedding.IndexInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		indexed, err := s.embeddings.IndexStale(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("failed to index product embeddings")
+		}
+		if indexed > 0 {
+			s.logger.Info().Int("indexed", indexed).Msg("Indexed product embeddings")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.embeddings.Notified():
+		}
+	}
+}
+
+// RunRecommendationRefresher recomputes co-purchase recommendations from
+// order history at startup and on every tick of the configured interval,
+// until ctx is cancelled. Only one replica refreshes them at a time.
+func (s *Server) RunRecommendationRefresher(ctx context.Context) {
+	interval := s.cfg.Recommendations.RefreshInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.runExclusive(ctx, "recommendation_refresher", s.refreshRecommendations); err != nil {
+			s.logger.Error().Err(err).Msg("failed to run recommendation refresher")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) refreshRecommendations(ctx context.Context) {
+	stored, err := s.recommendations.Refresh(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to refresh product recommendations")
+		return
+	}
+	s.logger.Info().Int64("recommendations", stored).Msg("Refreshed product recommendations")
+}
+
+// RunCategoryClassifierTrainer retrains the product category classifier
+// from the catalog at startup and on every tick of the configured interval,
+// until ctx is cancelled. Unlike the other jobs it runs on every replica
+// without a lock: each replica suggests categories from its own classifier
+// in memory, and training only reads the catalog.
+func (s *Server) RunCategoryClassifierTrainer(ctx context.Context) {
+	interval := s.cfg.Categorizer.TrainInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		trained, err := s.categorization.Train(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("failed to train category classifier")
+		} else {
+			s.logger.Info().Int("products", trained).Msg("Trained category classifier")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunSearchTermRefresher rereads the words misspelt search terms are
+// corrected to from the catalog at startup and on every tick of the
+// configured interval, until ctx is cancelled. Like the classifier trainer
+// it runs on every replica without a lock, since each keeps its own words in
+// memory.
+func (s *Server) RunSearchTermRefresher(ctx context.Context) {
+	interval := s.cfg.Search.TermsRefreshInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		words, err := s.searchTerms.Refresh(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("failed to refresh search terms")
+		} else {
+			s.logger.Info().Int("words", words).Msg("Refreshed search terms")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDemandForecaster forecasts product demand from sales at startup and on
+// every tick of the configured interval, until ctx is cancelled. Only one
+// replica forecasts at a time.
+func (s *Server) RunDemandForecaster(ctx context.Context) {
+	interval := s.cfg.Forecast.RefreshInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.runExclusive(ctx, "demand_forecaster", s.forecastDemand); err != nil {
+			s.logger.Error().Err(err).Msg("failed to run demand forecaster")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) forecastDemand(ctx context.Context) {
+	forecast, err := s.forecasts.Refresh(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to forecast product demand")
+		return
+	}
+	s.logger.Info().Int("products", forecast).Msg("Forecast product demand")
+}
+
+// RunImageUploadSweeper expires abandoned direct image uploads, deleting
+// their files, on every tick of the configured interval until ctx is
+// cancelled. Only one replica sweeps at a time.
+func (s *Server) RunImageUploadSweeper(ctx context.Context) {
+	interval := s.cfg.Upload.SweepInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.runExclusive(ctx, "image_upload_sweeper", s.expireImageUploads); err != nil {
+				s.logger.Error().Err(err).Msg("failed to run image upload sweeper")
+			}
+		}
+	}
+}
+
+func (s *Server) expireImageUploads(ctx context.Context) {
+	expired, err := s.imageUploadService.ExpireUploads(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to expire image uploads")
+		return
+	}
+	if expired > 0 {
+		s.logger.Info().Int("expired", expired).Msg("Expired abandoned image uploads")
+	}
+}
+
+// RunStorageGC deletes stored files no product image or pending upload uses
+// on every tick of the configured interval until ctx is cancelled. In dry
+// run mode it only logs the files it would delete. Only one replica collects
+// at a time.
+func (s *Server) RunStorageGC(ctx context.Context) {
+	interval := s.cfg.Upload.GCInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.runExclusive(ctx, "storage_gc", s.collectStorageGarbage); err != nil {
+				s.logger.Error().Err(err).Msg("failed to run storage collector")
+			}
+		}
+	}
+}
+
+func (s *Server) collectStorageGarbage(ctx context.Context) {
+	report, err := s.storageGC.CollectGarbage(ctx, s.cfg.Upload.GCDryRun)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to collect unused stored files")
+		return
+	}
+	if report.DryRun {
+		for _, orphan := range report.Orphans {
+			s.logger.Info().Str("path", orphan.Path).Int64("size", orphan.Size).Time("mod_time", orphan.ModTime).Msg("Would delete unused stored file")
+		}
+	}
+	if len(report.Orphans) > 0 || report.ForgottenBlobs > 0 {
+		s.logger.Info().Bool("dry_run", report.DryRun).Int("orphans", len(report.Orphans)).Int64("orphan_bytes", report.OrphanBytes).
+			Int("deleted", report.Deleted).Int64("forgotten_blobs", report.ForgottenBlobs).Msg("Collected unused stored files")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/graph"
 	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
 )
 
@@ -53,3 +54,18 @@ func (s *Server) AdminAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// CurrencyMiddleware picks up the display currency a shopper asked for from
+// the currency query parameter or the X-Currency header. Services check it is
+// one the store sells in.
+func (s *Server) CurrencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currency := c.Query("currency")
+		if currency == "" {
+			currency = c.GetHeader(graph.CurrencyHeader)
+		}
+		c.Set("currency", currency)
+
+		c.Next()
+	}
+}
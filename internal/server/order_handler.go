@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
 	"github.com/trenchesdeveloper/go-ai-store/internal/services"
 	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
 )
@@ -17,6 +18,8 @@ import (
 // @Produce      json
 // @Security     BearerAuth
 // @Param        X-Idempotency-Key header string false "Idempotency key to prevent duplicate orders"
+// @Param        request body dto.CreateOrderRequest false "Shipping address used to pick the fulfilment warehouse, and the currency to charge in"
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      201  {object}  utils.Response{data=dto.OrderResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
@@ -28,7 +31,22 @@ func (s *Server) CreateOrder(ctx *gin.Context) {
 	// Get idempotency key from header (optional)
 	idempotencyKey := ctx.GetHeader("X-Idempotency-Key")
 
-	order, err := s.orderService.CreateOrderWithIdempotency(ctx, int32(userID), idempotencyKey) //#nosec G115 -- user ID from auth middleware
+	// Body is optional; without a shipping address the default warehouse order applies
+	var req dto.CreateOrderRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(ctx, "Invalid request payload", err)
+			return
+		}
+	}
+	// Charge in the display currency unless the body picks one
+	if req.Currency == "" {
+		req.Currency = ctx.GetString("currency")
+	}
+	// Orders from the same address count towards IP velocity
+	req.IPAddress = ctx.ClientIP()
+
+	order, err := s.orderService.CreateOrderWithIdempotency(ctx, int32(userID), idempotencyKey, req) //#nosec G115 -- user ID from auth middleware
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrEmptyCart):
@@ -39,6 +57,8 @@ func (s *Server) CreateOrder(ctx *gin.Context) {
 			utils.BadRequestResponse(ctx, "Insufficient stock", err)
 		case errors.Is(err, services.ErrDuplicateOrder):
 			utils.BadRequestResponse(ctx, "Duplicate order submission - please wait", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
 		default:
 			utils.InternalErrorResponse(ctx, "Failed to create order", err)
 		}
@@ -135,7 +155,7 @@ func (s *Server) GetOrders(ctx *gin.Context) {
 
 // CancelOrder godoc
 // @Summary      Cancel order
-// @Description  Cancel a pending order. Only pending orders can be cancelled.
+// @Description  Cancel a pending order, or one held for fraud review. Confirmed orders can't be cancelled.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
@@ -176,18 +196,20 @@ func (s *Server) CancelOrder(ctx *gin.Context) {
 
 // UpdateOrderStatus godoc
 // @Summary      Update order status (Admin)
-// @Description  Update the status of an order. Admin only.
+// @Description  Update the status of an order. Admin only. Cancelling puts its stock back; orders on hold are settled through fraud review instead.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path int true "Order ID"
-// @Param        request body object{status=string} true "New status (pending, confirmed, shipped, delivered, cancelled)"
+// @Param        request body object{status=string} true "New status (pending, confirmed, shipped, delivered or cancelled)"
 // @Success      200  {object}  utils.Response{data=dto.OrderResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
 // @Router       /orders/{id}/status [put]
 func (s *Server) UpdateOrderStatus(ctx *gin.Context) {
+	adminID := ctx.GetUint("user_id")
+
 	orderIDStr := ctx.Param("id")
 	orderID, err := strconv.ParseUint(orderIDStr, 10, 64)
 	if err != nil {
@@ -203,11 +225,15 @@ func (s *Server) UpdateOrderStatus(ctx *gin.Context) {
 		return
 	}
 
-	order, err := s.orderService.UpdateOrderStatus(ctx, int32(orderID), req.Status) //#nosec G115 -- order ID from validated request
+	order, err := s.orderService.UpdateOrderStatus(ctx, int32(adminID), int32(orderID), req.Status) //#nosec G115 -- IDs from auth middleware and validated request
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrOrderNotFound):
 			utils.NotFoundResponse(ctx, "Order not found", err)
+		case errors.Is(err, services.ErrOrderHeldByReview):
+			utils.BadRequestResponse(ctx, "Orders on hold are settled through fraud review", err)
+		case errors.Is(err, services.ErrOrderNotCancellable):
+			utils.BadRequestResponse(ctx, "Order cannot be cancelled", err)
 		default:
 			utils.BadRequestResponse(ctx, err.Error(), err)
 		}
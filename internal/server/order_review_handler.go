@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// ListHeldOrders godoc
+// @Summary      List orders held for fraud review (Admin)
+// @Description  Orders whose risk score reached the hold score, oldest first, with the rules that fired
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(10)
+// @Success      200  {object}  utils.PaginatedResponse{data=[]dto.HeldOrderResponse}
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/orders/review-queue [get]
+func (s *Server) ListHeldOrders(ctx *gin.Context) {
+	// Parse pagination parameters
+	page := 1
+	limit := 10
+
+	if pageStr := ctx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	orders, pagination, err := s.orderService.ListHeldOrders(ctx, page, limit)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to get held orders", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(ctx, "Held orders retrieved successfully", orders, *pagination)
+}
+
+// ReviewHeldOrder godoc
+// @Summary      Review an order held for fraud (Admin)
+// @Description  Approve a held order to release it as pending, or reject it to cancel it and restock its items
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Order ID"
+// @Param        request body dto.ReviewHeldOrderRequest true "approve or reject"
+// @Success      200  {object}  utils.Response{data=dto.OrderResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/orders/{id}/review [post]
+func (s *Server) ReviewHeldOrder(ctx *gin.Context) {
+	adminID := ctx.GetUint("user_id")
+
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid order ID", err)
+		return
+	}
+
+	var req dto.ReviewHeldOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	order, err := s.orderService.ReviewHeldOrder(ctx, int32(adminID), int32(orderID), req.Decision) //#nosec G115 -- IDs from auth middleware and validated request
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			utils.NotFoundResponse(ctx, "Order not found", err)
+		case errors.Is(err, services.ErrOrderNotHeld):
+			utils.BadRequestResponse(ctx, "Order is not held for review", err)
+		case errors.Is(err, services.ErrInvalidReviewDecision):
+			utils.BadRequestResponse(ctx, "Decision must be approve or reject", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to review order", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Order reviewed successfully", order)
+}
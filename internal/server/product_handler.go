@@ -1,10 +1,13 @@
 package server
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
 	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
 )
 
@@ -141,7 +144,9 @@ func (s *Server) CreateProduct(ctx *gin.Context) {
 		return
 	}
 
-	product, err := s.productService.CreateProduct(ctx, req)
+	userID := ctx.GetUint("user_id")
+
+	product, err := s.productService.CreateProduct(ctx, int32(userID), req) //#nosec G115 -- user ID from auth middleware
 	if err != nil {
 		utils.InternalErrorResponse(ctx, "Failed to create product", err)
 		return
@@ -158,7 +163,9 @@ func (s *Server) CreateProduct(ctx *gin.Context) {
 // @Produce      json
 // @Param        page query int false "Page number" default(1)
 // @Param        limit query int false "Items per page" default(10)
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      200  {object}  utils.PaginatedResponse{data=[]dto.ProductResponse}
+// @Failure      400  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
 // @Router       /products [get]
 func (s *Server) GetProducts(ctx *gin.Context) {
@@ -177,8 +184,12 @@ func (s *Server) GetProducts(ctx *gin.Context) {
 		}
 	}
 
-	products, paginationMeta, err := s.productService.GetProducts(ctx, page, limit)
+	products, paginationMeta, err := s.productService.GetProducts(ctx, page, limit, ctx.GetString("currency"))
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
 		utils.InternalErrorResponse(ctx, "Failed to get products", err)
 		return
 	}
@@ -188,17 +199,30 @@ func (s *Server) GetProducts(ctx *gin.Context) {
 
 // SearchProducts godoc
 // @Summary      Search products
-// @Description  Full-text search products by name, SKU, and description with optional filters
+// @Description  Full-text search products by name, SKU, and description with optional filters.
+// @Description  Repeat category_id or attr to match any of several values. The response counts
+// @Description  matches by category, price, stock and attribute in facets. Prices are what products
+// @Description  sell for now, sales included, and stock held by reservations is not in stock. Leave
+// @Description  out q to browse by filters alone. When a query matches nothing,
+// @Description  did_you_mean suggests a corrected query and results fall back to products with
+// @Description  similar names, marked fuzzy. With parse=true, prices, categories, attributes and sort
+// @Description  order written in the query ("red running shoes under $80 in size 10") become filters,
+// @Description  returned in parsed; filters given as parameters win over parsed ones.
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param        q query string true "Search query"
+// @Param        q query string false "Search query"
 // @Param        page query int false "Page number" default(1)
 // @Param        limit query int false "Items per page" default(10)
-// @Param        category_id query int false "Filter by category ID"
+// @Param        category_id query []int false "Filter by category IDs" collectionFormat(multi)
 // @Param        min_price query number false "Minimum price filter"
 // @Param        max_price query number false "Maximum price filter"
-// @Success      200  {object}  utils.PaginatedResponse{data=[]dto.ProductSearchResult}
+// @Param        in_stock query bool false "Filter by whether products are in stock"
+// @Param        attr query []string false "Filter by attribute values, written name:value" collectionFormat(multi)
+// @Param        sort query string false "Sort order" Enums(rank, price_asc, price_desc, newest) default(rank)
+// @Param        parse query bool false "Read filters out of the query text" default(false)
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
+// @Success      200  {object}  utils.SearchResponse{data=[]dto.ProductSearchResult,facets=dto.SearchFacets,parsed=dto.ParsedQuery}
 // @Failure      400  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
 // @Router       /products/search [get]
@@ -209,13 +233,92 @@ func (s *Server) SearchProducts(ctx *gin.Context) {
 		return
 	}
 
-	products, paginationMeta, err := s.productService.SearchProducts(ctx, req)
+	result, paginationMeta, err := s.productService.SearchProducts(ctx, req, ctx.GetString("currency"))
+	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidAttributeFilter) {
+			utils.BadRequestResponse(ctx, "Invalid search parameters", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to search products", err)
+		return
+	}
+
+	utils.SearchSuccessResponse(ctx, "Products search completed", result.Results, *paginationMeta, result.Facets, result.DidYouMean, result.Fuzzy, result.Parsed)
+}
+
+// SuggestProducts godoc
+// @Summary      Suggest products
+// @Description  Autocomplete product suggestions for a partly typed query. Names starting with the
+// @Description  query come first, then names with a word starting with it, then similar names.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        q query string true "Partly typed query"
+// @Param        limit query int false "Maximum suggestions" default(5)
+// @Success      200  {object}  utils.Response{data=[]dto.ProductSuggestion}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/suggest [get]
+func (s *Server) SuggestProducts(ctx *gin.Context) {
+	var req dto.SuggestProductsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid suggestion parameters", err)
+		return
+	}
+
+	suggestions, err := s.productService.SuggestProducts(ctx, req)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to suggest products", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Product suggestions retrieved successfully", suggestions)
+}
+
+// SemanticSearchProducts godoc
+// @Summary      Semantic product search
+// @Description  Rank products by how close their meaning is to the query, by cosine similarity of
+// @Description  embeddings. Hybrid mode blends that with the full-text rank and also returns
+// @Description  products that only match on words.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        q query string true "Search query"
+// @Param        mode query string false "Ranking mode" Enums(semantic, hybrid) default(semantic)
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(10)
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
+// @Success      200  {object}  utils.PaginatedResponse{data=[]dto.ProductSearchResult}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Failure      503  {object}  utils.Response
+// @Router       /products/semantic-search [get]
+func (s *Server) SemanticSearchProducts(ctx *gin.Context) {
+	var req dto.SemanticSearchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid search parameters", err)
+		return
+	}
+
+	results, paginationMeta, err := s.productService.SemanticSearch(ctx, req, ctx.GetString("currency"))
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
+		if errors.Is(err, services.ErrSemanticSearchUnavailable) {
+			utils.ErrorResponse(ctx, "Semantic search unavailable", http.StatusServiceUnavailable, err)
+			return
+		}
 		utils.InternalErrorResponse(ctx, "Failed to search products", err)
 		return
 	}
 
-	utils.PaginatedSuccessResponse(ctx, "Products search completed", products, *paginationMeta)
+	utils.PaginatedSuccessResponse(ctx, "Products search completed", results, *paginationMeta)
 }
 
 // GetProductByID godoc
@@ -225,6 +328,7 @@ func (s *Server) SearchProducts(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "Product ID"
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
 // @Success      200  {object}  utils.Response{data=dto.ProductResponse}
 // @Failure      400  {object}  utils.Response
 // @Failure      404  {object}  utils.Response
@@ -237,8 +341,12 @@ func (s *Server) GetProductByID(ctx *gin.Context) {
 		return
 	}
 
-	product, err := s.productService.GetProductByID(ctx, uint(id))
+	product, err := s.productService.GetProductByID(ctx, uint(id), ctx.GetString("currency"))
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+			return
+		}
 		utils.NotFoundResponse(ctx, "Product not found", err)
 		return
 	}
@@ -246,6 +354,50 @@ func (s *Server) GetProductByID(ctx *gin.Context) {
 	utils.SuccessResponse(ctx, "Product retrieved successfully", product)
 }
 
+// GetProductRecommendations godoc
+// @Summary      Get product recommendations
+// @Description  Products customers also bought with this one, most similar first. Similarity is
+// @Description  the cosine similarity of the products' orders, recomputed periodically.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Product ID"
+// @Param        limit query int false "Maximum recommendations" default(10)
+// @Param        currency query string false "Display currency, e.g. EUR; the X-Currency header also works"
+// @Success      200  {object}  utils.Response{data=[]dto.ProductRecommendation}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/recommendations [get]
+func (s *Server) GetProductRecommendations(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.RecommendationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid recommendation parameters", err)
+		return
+	}
+
+	recommendations, err := s.productService.GetProductRecommendations(ctx, uint(id), req, ctx.GetString("currency"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to get recommendations", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Recommendations retrieved successfully", recommendations)
+}
+
 // UpdateProductByID godoc
 // @Summary      Update product (Admin)
 // @Description  Update an existing product
@@ -273,7 +425,9 @@ func (s *Server) UpdateProductByID(ctx *gin.Context) {
 		return
 	}
 
-	product, err := s.productService.UpdateProductByID(ctx, uint(id), &req)
+	userID := ctx.GetUint("user_id")
+
+	product, err := s.productService.UpdateProductByID(ctx, int32(userID), uint(id), &req) //#nosec G115 -- user ID from auth middleware
 	if err != nil {
 		utils.InternalErrorResponse(ctx, "Failed to update product", err)
 		return
@@ -313,15 +467,19 @@ func (s *Server) DeleteProductByID(ctx *gin.Context) {
 
 // UploadProductImage godoc
 // @Summary      Upload product image (Admin)
-// @Description  Upload an image for a product
+// @Description  Upload a JPEG, PNG, GIF or WebP image for a product. Its metadata is stripped and it is
+// @Description  stored along with thumbnail, medium and large variants, each also as WebP when smaller. It is added
+// @Description  after the product's other images, and becomes the primary image when the product has none.
 // @Tags         products
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path int true "Product ID"
 // @Param        image formData file true "Product image"
-// @Success      200  {object}  utils.Response{data=string}
+// @Param        alt_text formData string false "Alt text; defaults to the file name"
+// @Success      201  {object}  utils.Response{data=dto.ProductImageResponse}
 // @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
 // @Failure      500  {object}  utils.Response
 // @Router       /products/{id}/image [post]
 func (s *Server) UploadProductImage(ctx *gin.Context) {
@@ -338,21 +496,29 @@ func (s *Server) UploadProductImage(ctx *gin.Context) {
 		return
 	}
 
-	imagePath, err := s.uploadService.UploadProductImage(
-		uint(id),
-		file,
-	)
+	uploaded, err := s.uploadService.UploadProductImage(ctx, file)
 	if err != nil {
-		utils.InternalErrorResponse(ctx, "Failed to upload product image", err)
+		switch {
+		case errors.Is(err, services.ErrUnsupportedImage),
+			errors.Is(err, services.ErrImageTooLarge),
+			errors.Is(err, services.ErrImageDimensions):
+			utils.BadRequestResponse(ctx, "Invalid image", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to upload product image", err)
+		}
 		return
 	}
 
-	// Update product image path in database
-	err = s.productService.UpdateProductImage(ctx, uint(id), imagePath, file.Filename)
+	altText := ctx.PostForm("alt_text")
+	if altText == "" {
+		altText = file.Filename
+	}
+
+	image, err := s.productImageService.AddImage(ctx, uint(id), *uploaded, altText)
 	if err != nil {
-		utils.InternalErrorResponse(ctx, "Failed to update product image", err)
+		handleProductImageError(ctx, err, "Failed to update product image")
 		return
 	}
 
-	utils.SuccessResponse(ctx, "Product image uploaded successfully", imagePath)
+	utils.CreatedResponse(ctx, "Product image uploaded successfully", image)
 }
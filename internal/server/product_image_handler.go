@@ -0,0 +1,198 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// ListProductImages godoc
+// @Summary      List product images (Admin)
+// @Description  A product's images in display order
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Success      200  {object}  utils.Response{data=[]dto.ProductImageResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images [get]
+func (s *Server) ListProductImages(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	images, err := s.productImageService.ListImages(ctx, uint(id))
+	if err != nil {
+		handleProductImageError(ctx, err, "Failed to list product images")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Product images retrieved successfully", images)
+}
+
+// ReorderProductImages godoc
+// @Summary      Reorder product images (Admin)
+// @Description  Set the order a product's images are shown in. image_ids must list every image of the product once.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        request body dto.ReorderProductImagesRequest true "Image IDs in display order"
+// @Success      200  {object}  utils.Response{data=[]dto.ProductImageResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images/order [put]
+func (s *Server) ReorderProductImages(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.ReorderProductImagesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	images, err := s.productImageService.ReorderImages(ctx, uint(id), req.ImageIDs)
+	if err != nil {
+		handleProductImageError(ctx, err, "Failed to reorder product images")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Product images reordered successfully", images)
+}
+
+// SetPrimaryProductImage godoc
+// @Summary      Set the primary product image (Admin)
+// @Description  Make an image its product's primary image, in place of the current one
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        imageId path int true "Image ID"
+// @Success      200  {object}  utils.Response{data=[]dto.ProductImageResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images/{imageId}/primary [put]
+func (s *Server) SetPrimaryProductImage(ctx *gin.Context) {
+	id, imageID, ok := productImageParams(ctx)
+	if !ok {
+		return
+	}
+
+	images, err := s.productImageService.SetPrimaryImage(ctx, id, imageID)
+	if err != nil {
+		handleProductImageError(ctx, err, "Failed to set primary product image")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Primary product image set successfully", images)
+}
+
+// UpdateProductImage godoc
+// @Summary      Update a product image (Admin)
+// @Description  Change an image's alt text
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        imageId path int true "Image ID"
+// @Param        request body dto.UpdateProductImageRequest true "Image changes"
+// @Success      200  {object}  utils.Response{data=dto.ProductImageResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images/{imageId} [put]
+func (s *Server) UpdateProductImage(ctx *gin.Context) {
+	id, imageID, ok := productImageParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProductImageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	image, err := s.productImageService.UpdateImage(ctx, id, imageID, req)
+	if err != nil {
+		handleProductImageError(ctx, err, "Failed to update product image")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Product image updated successfully", image)
+}
+
+// DeleteProductImage godoc
+// @Summary      Delete a product image (Admin)
+// @Description  Remove an image. Its files are deleted along with it unless another image has the same content. When it was the primary image, the next image takes its place.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        imageId path int true "Image ID"
+// @Success      200  {object}  utils.Response
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/images/{imageId} [delete]
+func (s *Server) DeleteProductImage(ctx *gin.Context) {
+	id, imageID, ok := productImageParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := s.productImageService.DeleteImage(ctx, id, imageID); err != nil {
+		handleProductImageError(ctx, err, "Failed to delete product image")
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Product image deleted successfully", nil)
+}
+
+// productImageParams parses the product and image IDs from the path,
+// responding with a bad request when either is invalid
+func productImageParams(ctx *gin.Context) (uint, uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return 0, 0, false
+	}
+
+	imageID, err := strconv.ParseUint(ctx.Param("imageId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid image ID", err)
+		return 0, 0, false
+	}
+	return uint(id), uint(imageID), true
+}
+
+func handleProductImageError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrProductImageNotFound):
+		utils.NotFoundResponse(ctx, "Product image not found", err)
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.NotFoundResponse(ctx, "Product not found", err)
+	case errors.Is(err, services.ErrInvalidImageOrder):
+		utils.BadRequestResponse(ctx, "Image order must list each of the product's images once", err)
+	default:
+		utils.InternalErrorResponse(ctx, message, err)
+	}
+}
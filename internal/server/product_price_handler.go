@@ -0,0 +1,196 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// GetProductPrices godoc
+// @Summary      Get product price history (Admin)
+// @Description  Full list and sale price history of a product, newest first, with the price it sold for at the given time
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        at query string false "RFC 3339 time to resolve the effective price at (default now)"
+// @Param        currency query string false "Currency to resolve the effective price in (default base)"
+// @Success      200  {object}  utils.Response{data=dto.PriceHistoryResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/prices [get]
+func (s *Server) GetProductPrices(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	at := time.Now()
+	if raw := ctx.Query("at"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			utils.BadRequestResponse(ctx, "Invalid at time, expected RFC 3339", err)
+			return
+		}
+	}
+
+	history, err := s.productService.GetPriceHistory(ctx, uint(id), at, ctx.GetString("currency"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to get price history", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Price history retrieved successfully", history)
+}
+
+// SetProductListPrice godoc
+// @Summary      Set a list price in another currency (Admin)
+// @Description  Set a product's list price in a currency's own price list, instead of converting the base price. Base currency prices are set by updating the product.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        currency path string true "Currency code, e.g. EUR"
+// @Param        request body dto.SetListPriceRequest true "List price"
+// @Success      201  {object}  utils.Response{data=dto.ProductPriceResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/prices/{currency} [put]
+func (s *Server) SetProductListPrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.SetListPriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+
+	price, err := s.productService.SetListPrice(ctx, int32(userID), uint(id), ctx.Param("currency"), req) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+		case errors.Is(err, services.ErrBaseCurrencyPrice):
+			utils.BadRequestResponse(ctx, "Set the base currency price by updating the product", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to set list price", err)
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "List price set successfully", price)
+}
+
+// CreateSalePrice godoc
+// @Summary      Schedule a sale price (Admin)
+// @Description  Put a product on sale from starts_at (default now) until ends_at (default until ended). The sale price must be below the list price in its currency when it starts.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        request body dto.CreateSalePriceRequest true "Sale price"
+// @Success      201  {object}  utils.Response{data=dto.ProductPriceResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/sale-prices [post]
+func (s *Server) CreateSalePrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.CreateSalePriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+
+	sale, err := s.productService.ScheduleSalePrice(ctx, int32(userID), uint(id), req) //#nosec G115 -- user ID from auth middleware
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.NotFoundResponse(ctx, "Product not found", err)
+		case errors.Is(err, services.ErrInvalidSalePeriod):
+			utils.BadRequestResponse(ctx, "Sale must end after it starts", err)
+		case errors.Is(err, services.ErrSaleNotBelowList):
+			utils.BadRequestResponse(ctx, "Sale price must be below the list price", err)
+		case errors.Is(err, services.ErrUnsupportedCurrency):
+			utils.BadRequestResponse(ctx, "Unsupported currency", err)
+		default:
+			utils.InternalErrorResponse(ctx, "Failed to schedule sale price", err)
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Sale price scheduled successfully", sale)
+}
+
+// EndSalePrice godoc
+// @Summary      End a sale price (Admin)
+// @Description  End a running sale now, or cancel one that has not started. The price history keeps the sale.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        priceId path int true "Sale price ID"
+// @Success      200  {object}  utils.Response{data=dto.ProductPriceResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/sale-prices/{priceId} [delete]
+func (s *Server) EndSalePrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	priceID, err := strconv.ParseUint(ctx.Param("priceId"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid sale price ID", err)
+		return
+	}
+
+	sale, err := s.productService.EndSalePrice(ctx, uint(id), uint(priceID))
+	if err != nil {
+		if errors.Is(err, services.ErrSalePriceNotFound) {
+			utils.NotFoundResponse(ctx, "Sale price not found or already ended", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to end sale price", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Sale price ended successfully", sale)
+}
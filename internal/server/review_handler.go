@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/services"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// CreateProductReview godoc
+// @Summary      Review a product
+// @Description  Rate a product from 1 to 5 and say why. Each user reviews a product once. The product's
+// @Description  review summary is refreshed in the background, so it may take a moment to include the review.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Product ID"
+// @Param        request body dto.CreateReviewRequest true "Rating and review"
+// @Success      201  {object}  utils.Response{data=dto.ReviewResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      409  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/reviews [post]
+func (s *Server) CreateProductReview(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.CreateReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid request data", err)
+		return
+	}
+
+	review, err := s.reviewService.CreateReview(ctx, int32(userID), int32(productID), req) //#nosec G115 -- user ID from auth middleware, product ID parsed with a 32-bit limit
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		if errors.Is(err, services.ErrReviewExists) {
+			utils.ErrorResponse(ctx, "Product already reviewed", http.StatusConflict, err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to create review", err)
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Review created successfully", review)
+}
+
+// GetProductReviews godoc
+// @Summary      List product reviews
+// @Description  Get a product's reviews, newest first
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Product ID"
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(10)
+// @Success      200  {object}  utils.PaginatedResponse{data=[]dto.ReviewResponse}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/reviews [get]
+func (s *Server) GetProductReviews(ctx *gin.Context) {
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	// Parse pagination parameters
+	page := 1
+	limit := 10
+
+	if pageStr := ctx.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	reviews, pagination, err := s.reviewService.ListReviews(ctx, int32(productID), page, limit) //#nosec G115 -- product ID parsed with a 32-bit limit
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get reviews", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(ctx, "Reviews retrieved successfully", reviews, *pagination)
+}
+
+// GetProductReviewSummary godoc
+// @Summary      Get a product's review summary
+// @Description  Pros, cons, overall sentiment from -1 to 1 and representative quotes drawn from a
+// @Description  product's reviews, refreshed whenever a review is added
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Product ID"
+// @Success      200  {object}  utils.Response{data=dto.ReviewSummary}
+// @Failure      400  {object}  utils.Response
+// @Failure      404  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /products/{id}/reviews/summary [get]
+func (s *Server) GetProductReviewSummary(ctx *gin.Context) {
+	productID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid product ID", err)
+		return
+	}
+
+	summary, err := s.reviewService.GetSummary(ctx, int32(productID)) //#nosec G115 -- product ID parsed with a 32-bit limit
+	if err != nil {
+		if errors.Is(err, services.ErrReviewSummaryNotFound) {
+			utils.NotFoundResponse(ctx, "No review summary yet", err)
+			return
+		}
+		utils.InternalErrorResponse(ctx, "Failed to get review summary", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, "Review summary retrieved successfully", summary)
+}
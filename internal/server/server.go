@@ -2,7 +2,10 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -19,26 +22,44 @@ import (
 	"github.com/trenchesdeveloper/go-ai-store/internal/config"
 	"github.com/trenchesdeveloper/go-ai-store/internal/events"
 	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
 	"github.com/trenchesdeveloper/go-ai-store/internal/services"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
 type Server struct {
-	cfg            *config.Config
-	logger         *zerolog.Logger
-	store          db.Store
-	authService    interfaces.AuthServicer
-	userService    interfaces.UserServicer
-	productService interfaces.ProductServicer
-	uploadService  *services.UploadService
-	cartService    interfaces.CartServicer
-	orderService   interfaces.OrderServicer
+	cfg                 *config.Config
+	logger              *zerolog.Logger
+	store               db.Store
+	authService         interfaces.AuthServicer
+	userService         interfaces.UserServicer
+	productService      interfaces.ProductServicer
+	uploadService       *services.UploadService
+	productImageService interfaces.ProductImageServicer
+	imageUploadService  interfaces.ImageUploadServicer
+	localUploads        *providers.LocalUploadProvider // nil unless files are stored locally
+	storageGC           interfaces.StorageGCServicer
+	cartService         interfaces.CartServicer
+	orderService        interfaces.OrderServicer
+	inventoryService    interfaces.InventoryServicer
+	reservationService  interfaces.ReservationServicer
+	backInStockService  interfaces.BackInStockServicer
+	embeddings          *services.EmbeddingIndexer
+	searchTerms         *services.SearchTermIndex
+	recommendations     interfaces.RecommendationServicer
+	feedService         interfaces.FeedServicer
+	contentService      interfaces.ContentServicer
+	assistantService    interfaces.AssistantServicer
+	categorization      interfaces.CategorizationServicer
+	reviewService       interfaces.ReviewServicer
+	forecasts           interfaces.ForecastServicer
 }
 
 func NewServer(cfg *config.Config, logger *zerolog.Logger, store db.Store) (*Server, error) {
 	// Initialize upload provider based on config
 	var uploadProvider interfaces.Upload
+	var localUploads *providers.LocalUploadProvider
 	switch cfg.Upload.Provider {
 	case "s3":
 		s3Provider, err := providers.NewS3UploadProvider(providers.S3Config{
@@ -47,13 +68,21 @@ func NewServer(cfg *config.Config, logger *zerolog.Logger, store db.Store) (*Ser
 			AccessKeyID:     cfg.AWS.AccessKeyID,
 			SecretAccessKey: cfg.AWS.SecretAccessKey,
 			Bucket:          cfg.AWS.S3Bucket,
+			PublicEndpoint:  cfg.AWS.S3PublicEndpoint,
 		})
 		if err != nil {
 			return nil, err
 		}
 		uploadProvider = s3Provider
 	default:
-		uploadProvider = providers.NewLocalUploadProvider(cfg.Upload.UploadPath)
+		// Anyone with the secret can store files, so it isn't shared with
+		// or defaulted like the JWT secret
+		if cfg.Upload.SigningSecret == "" {
+			return nil, errors.New("UPLOAD_SIGNING_SECRET is required to store uploads locally")
+		}
+		localUploads = providers.NewLocalUploadProvider(cfg.Upload.UploadPath,
+			strings.TrimSuffix(cfg.Upload.PublicURL, "/")+"/api/v1/uploads/local", cfg.Upload.SigningSecret)
+		uploadProvider = localUploads
 	}
 
 	// Initialize event publisher
@@ -62,23 +91,151 @@ func NewServer(cfg *config.Config, logger *zerolog.Logger, store db.Store) (*Ser
 		return nil, err
 	}
 
-	cartService := services.NewCartService(store)
+	allocator, err := services.NewAllocationStrategy(cfg.Inventory.AllocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize exchange rates based on config
+	var rates interfaces.ExchangeRates
+	switch cfg.Currency.RatesProvider {
+	case "file":
+		fileRates, err := providers.NewFileExchangeRates(cfg.Currency.RatesFile)
+		if err != nil {
+			return nil, err
+		}
+		rates = fileRates
+	default:
+		staticRates, err := providers.ParseStaticRates(cfg.Currency.Rates)
+		if err != nil {
+			return nil, err
+		}
+		staticProvider, err := providers.NewStaticExchangeRates(cfg.Currency.Base, staticRates)
+		if err != nil {
+			return nil, err
+		}
+		rates = staticProvider
+	}
+
+	currencies, err := services.NewCurrencyConverter(cfg.Currency.Base, cfg.Currency.Supported, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize embedder based on config
+	var embedder interfaces.Embedder
+	switch cfg.Embedding.Provider {
+	case "openai":
+		embedder = providers.NewOpenAIEmbedder(cfg.Embedding.APIURL, cfg.Embedding.APIKey, cfg.Embedding.Model)
+	default:
+		embedder = providers.NewHashEmbedder(cfg.Embedding.Dimensions)
+	}
+	embeddings := services.NewEmbeddingIndexer(store, embedder, cfg.Embedding.BatchSize)
+
+	// Initialize search query parser based on config
+	var queryParser interfaces.QueryParser
+	switch cfg.Search.QueryParser {
+	case "llm":
+		queryParser = providers.NewOpenAIQueryParser(cfg.LLM.APIURL, cfg.LLM.APIKey, cfg.LLM.Model)
+	default:
+		queryParser = services.NewRuleQueryParser()
+	}
+
+	// Initialize fraud risk scoring from the configured rule weights
+	riskWeights, err := services.ParseRiskWeights(cfg.Fraud.RuleWeights)
+	if err != nil {
+		return nil, err
+	}
+	firstOrderHighValue, err := money.Parse(cfg.Fraud.FirstOrderHighValue)
+	if err != nil {
+		return nil, err
+	}
+	risk := services.NewRiskScorer(cfg.Fraud.HoldScore, riskWeights,
+		services.UserVelocityRule{Window: cfg.Fraud.VelocityWindow, MaxOrders: cfg.Fraud.MaxOrdersPerUser},
+		services.IPVelocityRule{Window: cfg.Fraud.VelocityWindow, MaxOrders: cfg.Fraud.MaxOrdersPerIP},
+		services.FirstOrderHighValueRule{Threshold: firstOrderHighValue},
+		services.AddressMismatchRule{},
+		services.FailedLoginsRule{Window: cfg.Fraud.FailedLoginWindow, MaxAttempts: cfg.Fraud.MaxFailedLogins},
+		services.UnusualQuantityRule{MaxQuantity: cfg.Fraud.UnusualQuantity},
+	)
+
+	alerter := services.NewStockAlerter(store, pub, cfg.Inventory.DefaultReorderThreshold)
+	cartService := services.NewCartService(store, currencies)
+	storageGC := services.NewStorageGCService(store, uploadProvider, cfg.Upload.GCGracePeriod)
+	searchTerms := services.NewSearchTermIndex(store)
+	productService := services.NewProductService(store, alerter, currencies, embeddings, queryParser, searchTerms, storageGC)
+	orderService := services.NewOrderService(store, cartService, allocator, alerter, currencies, risk)
+	assistantService := services.NewAssistantService(
+		store,
+		providers.NewOpenAIChatClient(cfg.LLM.APIURL, cfg.LLM.APIKey, cfg.LLM.Model),
+		services.NewShopTools(productService, cartService, orderService),
+		cfg.Assistant.ConversationTTL,
+		cfg.Assistant.MaxHistory,
+		cfg.Assistant.MaxToolRounds,
+	)
+	feedWeights := services.FeedWeights{
+		Popularity: cfg.Feed.PopularityWeight,
+		Affinity:   cfg.Feed.AffinityWeight,
+		Recency:    cfg.Feed.RecencyWeight,
+	}
+	forecaster := services.DemandForecaster{
+		LevelSmoothing:    cfg.Forecast.LevelSmoothing,
+		TrendSmoothing:    cfg.Forecast.TrendSmoothing,
+		SeasonalSmoothing: cfg.Forecast.SeasonalSmoothing,
+		SeasonLength:      cfg.Forecast.SeasonLength,
+		MovingAverageDays: cfg.Forecast.MovingAverageDays,
+	}
+	reorderPolicy := services.ReorderPolicy{
+		LeadTimeDays: cfg.Forecast.LeadTimeDays,
+		CoverDays:    cfg.Forecast.CoverDays,
+		SafetyFactor: cfg.Forecast.SafetyFactor,
+	}
+	imageLimits := services.ImageLimits{
+		MaxBytes:     cfg.Upload.MaxUploadSize,
+		MinDimension: cfg.Upload.MinImageDimension,
+		MaxDimension: cfg.Upload.MaxImageDimension,
+	}
+	uploadService := services.NewUploadService(store, uploadProvider, imageLimits)
+	productImageService := services.NewProductImageService(store, storageGC)
 	return &Server{
-		cfg:            cfg,
-		logger:         logger,
-		store:          store,
-		authService:    services.NewAuthService(store, cfg, pub),
-		userService:    services.NewUserService(store),
-		productService: services.NewProductService(store),
-		uploadService:  services.NewUploadService(uploadProvider),
-		cartService:    cartService,
-		orderService:   services.NewOrderService(store, cartService),
+		cfg:                 cfg,
+		logger:              logger,
+		store:               store,
+		authService:         services.NewAuthService(store, cfg, pub),
+		userService:         services.NewUserService(store),
+		productService:      productService,
+		uploadService:       uploadService,
+		productImageService: productImageService,
+		imageUploadService:  services.NewImageUploadService(store, uploadProvider, uploadService, productImageService, cfg.Upload.PresignTTL),
+		localUploads:        localUploads,
+		storageGC:           storageGC,
+		cartService:         cartService,
+		orderService:        orderService,
+		inventoryService:    services.NewInventoryService(store, alerter),
+		reservationService:  services.NewReservationService(store, cfg.Inventory.ReservationTTL),
+		backInStockService:  services.NewBackInStockService(store, cfg.Inventory.BackInStockBatchSize, cfg.Inventory.BackInStockRetryDelay, cfg.Inventory.BackInStockMaxAttempts),
+		embeddings:          embeddings,
+		searchTerms:         searchTerms,
+		recommendations:     services.NewRecommendationService(store, cfg.Recommendations.TopN, cfg.Recommendations.MinCoPurchases),
+		feedService:         services.NewFeedService(store, productService, feedWeights, cfg.Feed.Window, cfg.Feed.RecencyHalfLife),
+		contentService:      services.NewContentService(store, productService, providers.NewTemplateContentGenerator()),
+		assistantService:    assistantService,
+		categorization:      services.NewCategorizationService(store, cfg.Categorizer.MismatchConfidence),
+		reviewService:       services.NewReviewService(store, pub, providers.NewLexiconSummarizer()),
+		forecasts:           services.NewForecastService(store, forecaster, reorderPolicy, cfg.Forecast.HistoryDays),
 	}, nil
 }
 
-func (s *Server) SetupRoutes() *gin.Engine {
+func (s *Server) SetupRoutes() (*gin.Engine, error) {
 	router := gin.New()
 
+	// Only believe forwarded client IPs from our own proxies, so clients
+	// can't choose the IP their orders are scored under
+	if err := router.SetTrustedProxies(s.cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.TrustedPlatform = s.cfg.Server.TrustedPlatform
+
 	// Add middlewares
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
@@ -92,9 +249,11 @@ func (s *Server) SetupRoutes() *gin.Engine {
 
 	router.StaticFile("/api-docs", "./docs/rapidoc.html")
 
-	// GraphQL endpoint with auth middleware
+	// GraphQL endpoint with auth middleware; orders are scored for fraud
+	// with the IP gin resolves through trusted proxies
 	graphqlHandler := s.graphqlHandler()
 	router.POST("/graphql", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(graph.WithClientIP(c.Request.Context(), c.ClientIP()))
 		graphqlHandler.ServeHTTP(c.Writer, c.Request)
 	})
 	router.GET("/graphql", func(c *gin.Context) {
@@ -106,6 +265,7 @@ func (s *Server) SetupRoutes() *gin.Engine {
 	})
 
 	api := router.Group("/api/v1")
+	api.Use(s.CurrencyMiddleware())
 	{
 		auth := api.Group("/auth")
 		{
@@ -131,6 +291,7 @@ func (s *Server) SetupRoutes() *gin.Engine {
 				categories.POST("", s.AdminAuthMiddleware(), s.CreateCategory)
 				categories.PUT("/:id", s.AdminAuthMiddleware(), s.UpdateCategory)
 				categories.DELETE("/:id", s.AdminAuthMiddleware(), s.DeleteCategory)
+				categories.PUT("/:id/reorder-threshold", s.AdminAuthMiddleware(), s.SetCategoryReorderThreshold)
 			}
 
 			// product routes
@@ -140,6 +301,36 @@ func (s *Server) SetupRoutes() *gin.Engine {
 				products.PUT("/:id", s.AdminAuthMiddleware(), s.UpdateProductByID)
 				products.DELETE("/:id", s.AdminAuthMiddleware(), s.DeleteProductByID)
 				products.POST("/:id/image", s.AdminAuthMiddleware(), s.UploadProductImage)
+				products.POST("/:id/images/uploads", s.AdminAuthMiddleware(), s.CreateProductImageUpload)
+				products.POST("/:id/images/uploads/:uploadId/complete", s.AdminAuthMiddleware(), s.CompleteProductImageUpload)
+				products.GET("/:id/images", s.AdminAuthMiddleware(), s.ListProductImages)
+				products.PUT("/:id/images/order", s.AdminAuthMiddleware(), s.ReorderProductImages)
+				products.PUT("/:id/images/:imageId", s.AdminAuthMiddleware(), s.UpdateProductImage)
+				products.PUT("/:id/images/:imageId/primary", s.AdminAuthMiddleware(), s.SetPrimaryProductImage)
+				products.DELETE("/:id/images/:imageId", s.AdminAuthMiddleware(), s.DeleteProductImage)
+				products.GET("/:id/prices", s.AdminAuthMiddleware(), s.GetProductPrices)
+				products.PUT("/:id/prices/:currency", s.AdminAuthMiddleware(), s.SetProductListPrice)
+				products.POST("/:id/sale-prices", s.AdminAuthMiddleware(), s.CreateSalePrice)
+				products.DELETE("/:id/sale-prices/:priceId", s.AdminAuthMiddleware(), s.EndSalePrice)
+				products.GET("/:id/back-in-stock", s.GetBackInStockSubscription)
+				products.POST("/:id/back-in-stock", s.SubscribeBackInStock)
+				products.DELETE("/:id/back-in-stock", s.UnsubscribeBackInStock)
+				products.POST("/:id/views", s.RecordProductView)
+				products.POST("/:id/reviews", s.CreateProductReview)
+				products.POST("/:id/content-drafts", s.AdminAuthMiddleware(), s.GenerateContentDraft)
+				products.GET("/:id/content-drafts", s.AdminAuthMiddleware(), s.ListContentDrafts)
+				products.POST("/:id/content-drafts/:draftId/accept", s.AdminAuthMiddleware(), s.AcceptContentDraft)
+				products.POST("/:id/content-drafts/:draftId/reject", s.AdminAuthMiddleware(), s.RejectContentDraft)
+			}
+
+			// personalized feed
+			protected.GET("/feed", s.GetFeed)
+
+			// shopping assistant
+			assistant := protected.Group("/assistant")
+			{
+				assistant.POST("/chat", s.AssistantChat)
+				assistant.DELETE("/chat", s.ResetAssistantChat)
 			}
 
 			// cart routes
@@ -150,6 +341,10 @@ func (s *Server) SetupRoutes() *gin.Engine {
 				cart.PUT("/items/:itemId", s.UpdateCartItem)
 				cart.DELETE("/items/:itemId", s.RemoveCartItem)
 				cart.DELETE("", s.ClearCart)
+				cart.GET("/reservation", s.GetCartReservation)
+				cart.POST("/reservation", s.ReserveCart)
+				cart.DELETE("/reservation", s.ReleaseCartReservation)
+				cart.GET("/recommendations", s.GetCartRecommendations)
 			}
 
 			// order routes
@@ -161,6 +356,36 @@ func (s *Server) SetupRoutes() *gin.Engine {
 				orders.POST("/:id/cancel", s.CancelOrder)
 				orders.PUT("/:id/status", s.AdminAuthMiddleware(), s.UpdateOrderStatus)
 			}
+
+			// inventory routes (admin only)
+			inventory := protected.Group("/inventory")
+			{
+				inventory.GET("/reconciliation", s.AdminAuthMiddleware(), s.ReconcileInventory)
+				inventory.GET("/low-stock", s.AdminAuthMiddleware(), s.ListLowStock)
+				inventory.GET("/forecasts", s.AdminAuthMiddleware(), s.ListDemandForecasts)
+				inventory.GET("/forecasts/export", s.AdminAuthMiddleware(), s.ExportDemandForecasts)
+				inventory.GET("/:sku/movements", s.AdminAuthMiddleware(), s.GetInventoryMovements)
+				inventory.POST("/:sku/adjustments", s.AdminAuthMiddleware(), s.AdjustInventory)
+				inventory.GET("/:sku/warehouses", s.AdminAuthMiddleware(), s.GetInventoryByWarehouse)
+				inventory.PUT("/:sku/reorder-threshold", s.AdminAuthMiddleware(), s.SetProductReorderThreshold)
+			}
+
+			// catalog administration (admin only)
+			admin := protected.Group("/admin")
+			{
+				admin.POST("/products/suggest-category", s.AdminAuthMiddleware(), s.SuggestProductCategory)
+				admin.GET("/products/category-mismatches", s.AdminAuthMiddleware(), s.ListCategoryMismatches)
+				admin.GET("/orders/review-queue", s.AdminAuthMiddleware(), s.ListHeldOrders)
+				admin.POST("/orders/:id/review", s.AdminAuthMiddleware(), s.ReviewHeldOrder)
+				admin.POST("/storage/gc", s.AdminAuthMiddleware(), s.CollectStorageGarbage)
+			}
+
+			// warehouse routes (admin only)
+			warehouses := protected.Group("/warehouses")
+			{
+				warehouses.GET("", s.AdminAuthMiddleware(), s.ListWarehouses)
+				warehouses.POST("", s.AdminAuthMiddleware(), s.CreateWarehouse)
+			}
 		}
 
 		// public routes
@@ -168,19 +393,30 @@ func (s *Server) SetupRoutes() *gin.Engine {
 		{
 			public.GET("/categories", s.GetCategories)
 			public.GET("/products", s.GetProducts)
-			public.GET("/products/search", s.SearchProducts) // Must be before :id
+			public.GET("/products/search", s.SearchProducts)                  // Must be before :id
+			public.GET("/products/suggest", s.SuggestProducts)                // Must be before :id
+			public.GET("/products/semantic-search", s.SemanticSearchProducts) // Must be before :id
 			public.GET("/products/:id", s.GetProductByID)
+			public.GET("/products/:id/recommendations", s.GetProductRecommendations)
+			public.GET("/products/:id/reviews", s.GetProductReviews)
+			public.GET("/products/:id/reviews/summary", s.GetProductReviewSummary)
+		}
+
+		// presigned uploads to local storage, authorized by their signature
+		if s.localUploads != nil {
+			api.PUT("/uploads/local", s.ReceiveLocalUpload)
+			api.POST("/uploads/local", s.ReceiveLocalUpload)
 		}
 	}
 
-	return router
+	return router, nil
 }
 
 func (s *Server) corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, X-CSRF-Token, Authorization, X-Currency")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -205,6 +441,7 @@ func (s *Server) graphqlHandler() http.Handler {
 		s.productService,
 		s.cartService,
 		s.orderService,
+		s.productImageService,
 	)
 
 	// Create GraphQL server with explicit configuration (production-ready)
@@ -226,6 +463,6 @@ func (s *Server) graphqlHandler() http.Handler {
 		Cache: lru.New[string](100),
 	})
 
-	// Wrap with auth middleware
-	return graph.AuthMiddleware(s.cfg.JWT.Secret)(srv)
+	// Wrap with auth and display currency middleware
+	return graph.AuthMiddleware(s.cfg.JWT.Secret)(graph.CurrencyMiddleware(srv))
 }
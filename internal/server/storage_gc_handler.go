@@ -0,0 +1,41 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// CollectStorageGarbage godoc
+// @Summary      Collect unused stored files (Admin)
+// @Description  Compares the files in storage against the product images and pending uploads that use them.
+// @Description  Files no image has used for the grace period, such as those of deleted images and products,
+// @Description  are orphans and are deleted. With dry_run, the orphans are only reported.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        dry_run query bool false "Report the orphaned files without deleting them" default(false)
+// @Success      200  {object}  utils.Response{data=dto.StorageGCReport}
+// @Failure      400  {object}  utils.Response
+// @Failure      500  {object}  utils.Response
+// @Router       /admin/storage/gc [post]
+func (s *Server) CollectStorageGarbage(ctx *gin.Context) {
+	var req dto.StorageGCRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.BadRequestResponse(ctx, "Invalid garbage collection parameters", err)
+		return
+	}
+
+	report, err := s.storageGC.CollectGarbage(ctx, req.DryRun)
+	if err != nil {
+		utils.InternalErrorResponse(ctx, "Failed to collect unused files", err)
+		return
+	}
+
+	message := "Unused files collected successfully"
+	if req.DryRun {
+		message = "Unused files reported successfully"
+	}
+	utils.SuccessResponse(ctx, message, report)
+}
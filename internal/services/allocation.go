@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	AllocationSingleWarehouseFirst = "single_warehouse_first"
+	AllocationNearestWarehouse     = "nearest_warehouse"
+)
+
+// Address is the shipping destination used to pick a fulfilment warehouse
+type Address struct {
+	Country    string
+	PostalCode string
+	Latitude   *float64
+	Longitude  *float64
+}
+
+// AllocationLine is a quantity of a product that must be fulfilled
+type AllocationLine struct {
+	ProductID int32
+	Quantity  int32
+}
+
+// WarehouseCandidate is an active warehouse with the stock it holds for the requested products
+type WarehouseCandidate struct {
+	WarehouseID int32
+	Code        string
+	Priority    int32
+	Address     Address
+	Stock       map[int32]int32 // product ID -> on-hand quantity
+}
+
+// AllocationRequest is the input to an AllocationStrategy
+type AllocationRequest struct {
+	Lines       []AllocationLine
+	Warehouses  []WarehouseCandidate
+	Destination *Address
+}
+
+// Allocation assigns part (or all) of an order line to a warehouse
+type Allocation struct {
+	ProductID   int32
+	WarehouseID int32
+	Quantity    int32
+}
+
+// AllocationStrategy decides which warehouses fulfil an order.
+// It returns ErrInsufficientStock when the lines cannot be covered.
+type AllocationStrategy interface {
+	Allocate(req AllocationRequest) ([]Allocation, error)
+}
+
+// NewAllocationStrategy returns the strategy registered under name
+func NewAllocationStrategy(name string) (AllocationStrategy, error) {
+	switch name {
+	case "", AllocationSingleWarehouseFirst:
+		return SingleWarehouseFirstStrategy{}, nil
+	case AllocationNearestWarehouse:
+		return NearestWarehouseStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy: %s", name)
+	}
+}
+
+// SingleWarehouseFirstStrategy ships the whole order from the highest-priority
+// warehouse that can cover every line, and only splits across warehouses
+// (in priority order) when no single warehouse can.
+type SingleWarehouseFirstStrategy struct{}
+
+func (SingleWarehouseFirstStrategy) Allocate(req AllocationRequest) ([]Allocation, error) {
+	warehouses := sortedCandidates(req.Warehouses, lessByPriority)
+
+	for _, w := range warehouses {
+		if coversAll(w, req.Lines) {
+			allocations := make([]Allocation, len(req.Lines))
+			for i, line := range req.Lines {
+				allocations[i] = Allocation{ProductID: line.ProductID, WarehouseID: w.WarehouseID, Quantity: line.Quantity}
+			}
+			return allocations, nil
+		}
+	}
+
+	return allocateGreedy(req.Lines, warehouses)
+}
+
+// NearestWarehouseStrategy fills each line from the warehouses closest to the
+// destination. Distance uses coordinates when both sides have them, otherwise
+// warehouses in the destination country rank first. Ties and missing
+// destinations fall back to warehouse priority.
+type NearestWarehouseStrategy struct{}
+
+func (NearestWarehouseStrategy) Allocate(req AllocationRequest) ([]Allocation, error) {
+	if req.Destination == nil {
+		return SingleWarehouseFirstStrategy{}.Allocate(req)
+	}
+
+	dest := *req.Destination
+	warehouses := sortedCandidates(req.Warehouses, func(a, b WarehouseCandidate) bool {
+		distA, distB := distanceRank(a.Address, dest), distanceRank(b.Address, dest)
+		if distA != distB {
+			return distA < distB
+		}
+		return lessByPriority(a, b)
+	})
+
+	return allocateGreedy(req.Lines, warehouses)
+}
+
+// allocateGreedy walks the warehouses in order, taking as much of each line as they hold
+func allocateGreedy(lines []AllocationLine, warehouses []WarehouseCandidate) ([]Allocation, error) {
+	var allocations []Allocation
+
+	for _, line := range lines {
+		need := line.Quantity
+		for _, w := range warehouses {
+			if need == 0 {
+				break
+			}
+			available := w.Stock[line.ProductID]
+			if available <= 0 {
+				continue
+			}
+			take := min(available, need)
+			allocations = append(allocations, Allocation{ProductID: line.ProductID, WarehouseID: w.WarehouseID, Quantity: take})
+			need -= take
+		}
+		if need > 0 {
+			return nil, ErrInsufficientStock
+		}
+	}
+
+	return allocations, nil
+}
+
+func coversAll(w WarehouseCandidate, lines []AllocationLine) bool {
+	for _, line := range lines {
+		if w.Stock[line.ProductID] < line.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func lessByPriority(a, b WarehouseCandidate) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.WarehouseID < b.WarehouseID
+}
+
+func sortedCandidates(in []WarehouseCandidate, less func(a, b WarehouseCandidate) bool) []WarehouseCandidate {
+	out := make([]WarehouseCandidate, len(in))
+	copy(out, in)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// distanceRank orders warehouses by closeness to dest: great-circle kilometres
+// when coordinates are known, otherwise a coarse same-country/same-postcode rank
+// placed beyond any real distance.
+func distanceRank(from, dest Address) float64 {
+	if from.Latitude != nil && from.Longitude != nil && dest.Latitude != nil && dest.Longitude != nil {
+		return haversineKm(*from.Latitude, *from.Longitude, *dest.Latitude, *dest.Longitude)
+	}
+
+	const far = 1e6
+	switch {
+	case !strings.EqualFold(from.Country, dest.Country):
+		return far * 3
+	case from.PostalCode != "" && dest.PostalCode != "" && postalPrefix(from.PostalCode) == postalPrefix(dest.PostalCode):
+		return far
+	default:
+		return far * 2
+	}
+}
+
+func postalPrefix(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(code, " ", ""))
+	if len(code) > 2 {
+		return code[:2]
+	}
+	return code
+}
+
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestNewAllocationStrategy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		strategy string
+		want     AllocationStrategy
+		wantErr  bool
+	}{
+		{name: "default", strategy: "", want: SingleWarehouseFirstStrategy{}},
+		{name: "single warehouse first", strategy: AllocationSingleWarehouseFirst, want: SingleWarehouseFirstStrategy{}},
+		{name: "nearest warehouse", strategy: AllocationNearestWarehouse, want: NearestWarehouseStrategy{}},
+		{name: "unknown", strategy: "round_robin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewAllocationStrategy(tt.strategy)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSingleWarehouseFirstStrategy_Allocate(t *testing.T) {
+	t.Parallel()
+
+	east := WarehouseCandidate{WarehouseID: 1, Code: "EAST", Priority: 0, Stock: map[int32]int32{1: 5, 2: 1}}
+	west := WarehouseCandidate{WarehouseID: 2, Code: "WEST", Priority: 1, Stock: map[int32]int32{1: 5, 2: 5}}
+
+	tests := []struct {
+		name    string
+		lines   []AllocationLine
+		want    []Allocation
+		wantErr error
+	}{
+		{
+			name:  "success - highest priority covers the order",
+			lines: []AllocationLine{{ProductID: 1, Quantity: 3}},
+			want:  []Allocation{{ProductID: 1, WarehouseID: 1, Quantity: 3}},
+		},
+		{
+			name:  "success - prefers one warehouse over a split",
+			lines: []AllocationLine{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 2}},
+			want: []Allocation{
+				{ProductID: 1, WarehouseID: 2, Quantity: 3},
+				{ProductID: 2, WarehouseID: 2, Quantity: 2},
+			},
+		},
+		{
+			name:  "success - splits when no warehouse covers everything",
+			lines: []AllocationLine{{ProductID: 1, Quantity: 8}},
+			want: []Allocation{
+				{ProductID: 1, WarehouseID: 1, Quantity: 5},
+				{ProductID: 1, WarehouseID: 2, Quantity: 3},
+			},
+		},
+		{
+			name:    "error - not enough stock anywhere",
+			lines:   []AllocationLine{{ProductID: 2, Quantity: 7}},
+			wantErr: ErrInsufficientStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SingleWarehouseFirstStrategy{}.Allocate(AllocationRequest{
+				Lines:      tt.lines,
+				Warehouses: []WarehouseCandidate{west, east},
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNearestWarehouseStrategy_Allocate(t *testing.T) {
+	t.Parallel()
+
+	newYork := WarehouseCandidate{
+		WarehouseID: 1,
+		Priority:    0,
+		Address:     Address{Country: "US", PostalCode: "10001", Latitude: floatPtr(40.75), Longitude: floatPtr(-73.99)},
+		Stock:       map[int32]int32{1: 10},
+	}
+	losAngeles := WarehouseCandidate{
+		WarehouseID: 2,
+		Priority:    1,
+		Address:     Address{Country: "US", PostalCode: "90001", Latitude: floatPtr(33.97), Longitude: floatPtr(-118.24)},
+		Stock:       map[int32]int32{1: 2},
+	}
+	warehouses := []WarehouseCandidate{newYork, losAngeles}
+
+	tests := []struct {
+		name        string
+		destination *Address
+		quantity    int32
+		want        []Allocation
+	}{
+		{
+			name:        "success - closest by coordinates",
+			destination: &Address{Country: "US", Latitude: floatPtr(34.05), Longitude: floatPtr(-118.25)},
+			quantity:    2,
+			want:        []Allocation{{ProductID: 1, WarehouseID: 2, Quantity: 2}},
+		},
+		{
+			name:        "success - closest by postal code",
+			destination: &Address{Country: "US", PostalCode: "90210"},
+			quantity:    2,
+			want:        []Allocation{{ProductID: 1, WarehouseID: 2, Quantity: 2}},
+		},
+		{
+			name:        "success - tops up from the next nearest",
+			destination: &Address{Country: "US", PostalCode: "90210"},
+			quantity:    5,
+			want: []Allocation{
+				{ProductID: 1, WarehouseID: 2, Quantity: 2},
+				{ProductID: 1, WarehouseID: 1, Quantity: 3},
+			},
+		},
+		{
+			name:     "success - no destination falls back to priority",
+			quantity: 2,
+			want:     []Allocation{{ProductID: 1, WarehouseID: 1, Quantity: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NearestWarehouseStrategy{}.Allocate(AllocationRequest{
+				Lines:       []AllocationLine{{ProductID: 1, Quantity: tt.quantity}},
+				Warehouses:  warehouses,
+				Destination: tt.destination,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
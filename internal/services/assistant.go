@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+)
+
+var (
+	ErrAssistantBusy      = errors.New("assistant is still answering the previous message")
+	ErrAssistantToolLimit = errors.New("assistant made too many tool calls without replying")
+)
+
+const assistantSystemPrompt = `You are the shopping assistant of an online store. Help the shopper find products, add them to their cart and check on their orders, using the tools you are given.
+Only talk about products, prices and orders the tools returned; never make up product IDs, prices or stock.
+Ask before adding anything to the cart unless the shopper clearly asked for it. Keep replies short.`
+
+// shownToolErrors are the tool errors the model is told about, so it can
+// correct itself or explain to the shopper. Any other failure is reported to
+// it without detail, keeping internal errors out of the conversation.
+var shownToolErrors = []error{
+	ErrUnknownTool,
+	ErrInvalidToolArguments,
+	ErrProductNotFound,
+	ErrInsufficientStock,
+	ErrUnsupportedCurrency,
+}
+
+// assistantClaimTimeout is how long answering a message may keep other
+// messages of the conversation out. A replica that stops mid-answer frees
+// the conversation after it.
+const assistantClaimTimeout = 10 * time.Minute
+
+// AssistantService is a conversational shopping assistant. Each user has
+// one conversation, stored until it goes quiet for the TTL, so any replica
+// can answer its next message. A message is answered by asking the
+// LLMClient for a reply, running any tools it calls from the ToolRegistry,
+// and asking again with their results, for at most maxToolRounds rounds.
+type AssistantService struct {
+	store         db.Store
+	llm           interfaces.LLMClient
+	tools         *ToolRegistry
+	ttl           time.Duration
+	maxHistory    int
+	maxToolRounds int
+	now           func() time.Time
+}
+
+func NewAssistantService(store db.Store, llm interfaces.LLMClient, tools *ToolRegistry, ttl time.Duration, maxHistory, maxToolRounds int) *AssistantService {
+	return &AssistantService{
+		store:         store,
+		llm:           llm,
+		tools:         tools,
+		ttl:           ttl,
+		maxHistory:    maxHistory,
+		maxToolRounds: maxToolRounds,
+		now:           time.Now,
+	}
+}
+
+// Chat answers the user's message, passing the model's text as it streams
+// in, each tool call and tool result, and the final reply to emit as they
+// happen. Cancelling ctx stops it between steps. When a message fails, the rounds
+// of tool calls that completed are still added to the conversation, since
+// their tools already ran and the model must know, say, what is in the
+// cart. A round cut short is dropped, so no call is left without a result.
+func (s *AssistantService) Chat(ctx context.Context, userID int32, message, currency string, emit func(dto.AssistantEvent)) (err error) {
+	conversation, history, err := s.begin(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tc := ToolContext{UserID: userID, Currency: currency}
+	definitions := s.tools.Definitions()
+	onContent := func(delta string) {
+		emit(dto.AssistantEvent{Type: dto.AssistantEventDelta, Content: delta})
+	}
+	turn := []dto.ChatMessage{{Role: dto.ChatRoleUser, Content: message}}
+
+	// completed is how much of turn ended with every tool call answered
+	completed := 1
+	defer func() {
+		kept := turn
+		if err != nil {
+			kept = turn[:completed]
+			if completed == 1 {
+				kept = nil // nothing ran, so the message is dropped
+			}
+		}
+		if finishErr := s.finish(ctx, conversation, history, kept); finishErr != nil && err == nil {
+			err = finishErr
+		}
+	}()
+
+	for round := 0; ; round++ {
+		messages := make([]dto.ChatMessage, 0, len(history)+len(turn)+1)
+		messages = append(messages, dto.ChatMessage{Role: dto.ChatRoleSystem, Content: assistantSystemPrompt})
+		messages = append(messages, history...)
+		messages = append(messages, turn...)
+
+		reply, err := s.llm.Complete(ctx, messages, definitions, onContent)
+		if err != nil {
+			return fmt.Errorf("failed to get assistant reply: %w", err)
+		}
+
+		if len(reply.ToolCalls) == 0 {
+			turn = append(turn, dto.ChatMessage{Role: dto.ChatRoleAssistant, Content: reply.Content})
+			emit(dto.AssistantEvent{Type: dto.AssistantEventMessage, Content: reply.Content})
+			return nil
+		}
+		if round >= s.maxToolRounds {
+			return ErrAssistantToolLimit
+		}
+
+		turn = append(turn, dto.ChatMessage{Role: dto.ChatRoleAssistant, Content: reply.Content, ToolCalls: reply.ToolCalls})
+		for _, call := range reply.ToolCalls {
+			emit(dto.AssistantEvent{Type: dto.AssistantEventToolCall, Tool: call.Name, Arguments: call.Arguments})
+
+			result, err := s.tools.Call(ctx, tc, call.Name, call.Arguments)
+			event := dto.AssistantEvent{Type: dto.AssistantEventToolResult, Tool: call.Name}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				event.Error = toolErrorMessage(err)
+				result, _ = json.Marshal(map[string]string{"error": event.Error})
+			} else {
+				event.Result = result
+			}
+			emit(event)
+
+			turn = append(turn, dto.ChatMessage{Role: dto.ChatRoleTool, Content: string(result), ToolCallID: call.ID})
+		}
+		completed = len(turn)
+	}
+}
+
+// Reset forgets the user's conversation
+func (s *AssistantService) Reset(ctx context.Context, userID int32) error {
+	conversation, err := s.claim(ctx, userID, s.now())
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, conversation, []dto.ChatMessage{})
+}
+
+// begin claims the user's conversation and returns its messages. Quiet
+// conversations of every user are deleted on the way.
+func (s *AssistantService) begin(ctx context.Context, userID int32) (db.AssistantConversation, []dto.ChatMessage, error) {
+	now := s.now()
+	if err := s.store.DeleteQuietAssistantConversations(ctx, db.DeleteQuietAssistantConversationsParams{
+		QuietBefore: pgtype.Timestamptz{Time: now.Add(-s.ttl), Valid: true},
+		Now:         pgtype.Timestamptz{Time: now, Valid: true},
+	}); err != nil {
+		return db.AssistantConversation{}, nil, fmt.Errorf("failed to delete quiet conversations: %w", err)
+	}
+
+	conversation, err := s.claim(ctx, userID, now)
+	if err != nil {
+		return db.AssistantConversation{}, nil, err
+	}
+
+	var history []dto.ChatMessage
+	if err := json.Unmarshal(conversation.Messages, &history); err != nil {
+		_ = s.finish(ctx, conversation, nil, nil)
+		return db.AssistantConversation{}, nil, fmt.Errorf("failed to read conversation: %w", err)
+	}
+	return conversation, history, nil
+}
+
+// claim marks the user's conversation busy, or fails with ErrAssistantBusy
+// when a message of it is already being answered
+func (s *AssistantService) claim(ctx context.Context, userID int32, now time.Time) (db.AssistantConversation, error) {
+	conversation, err := s.store.ClaimAssistantConversation(ctx, db.ClaimAssistantConversationParams{
+		UserID:    userID,
+		BusyUntil: pgtype.Timestamptz{Time: now.Add(assistantClaimTimeout), Valid: true},
+		Now:       pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.AssistantConversation{}, ErrAssistantBusy
+		}
+		return db.AssistantConversation{}, fmt.Errorf("failed to claim conversation: %w", err)
+	}
+	return conversation, nil
+}
+
+// finish ends the claim on a conversation, adding turn to its history. It
+// runs even when the request was cancelled, so the conversation isn't left
+// busy and the tools that ran aren't forgotten.
+func (s *AssistantService) finish(ctx context.Context, conversation db.AssistantConversation, history, turn []dto.ChatMessage) error {
+	ctx = context.WithoutCancel(ctx)
+	if len(turn) == 0 {
+		if err := s.store.ReleaseAssistantConversation(ctx, db.ReleaseAssistantConversationParams{
+			UserID:       conversation.UserID,
+			ClaimedUntil: conversation.BusyUntil,
+		}); err != nil {
+			return fmt.Errorf("failed to release conversation: %w", err)
+		}
+		return nil
+	}
+	messages := append(append([]dto.ChatMessage(nil), history...), turn...)
+	return s.save(ctx, conversation, trimHistory(messages, s.maxHistory))
+}
+
+// save stores a claimed conversation's messages and ends the claim
+func (s *AssistantService) save(ctx context.Context, conversation db.AssistantConversation, messages []dto.ChatMessage) error {
+	if messages == nil {
+		messages = []dto.ChatMessage{}
+	}
+	encoded, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation: %w", err)
+	}
+	if err := s.store.SaveAssistantConversation(ctx, db.SaveAssistantConversationParams{
+		UserID:       conversation.UserID,
+		Messages:     encoded,
+		UpdatedAt:    pgtype.Timestamptz{Time: s.now(), Valid: true},
+		ClaimedUntil: conversation.BusyUntil,
+	}); err != nil {
+		return fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return nil
+}
+
+// trimHistory keeps at most limit of the latest messages, starting at a user
+// message so the model never sees tool results without the calls that
+// asked for them
+func trimHistory(messages []dto.ChatMessage, limit int) []dto.ChatMessage {
+	if len(messages) <= limit {
+		return messages
+	}
+	for start := len(messages) - limit; start < len(messages); start++ {
+		if messages[start].Role == dto.ChatRoleUser {
+			return append([]dto.ChatMessage(nil), messages[start:]...)
+		}
+	}
+	return nil
+}
+
+func toolErrorMessage(err error) string {
+	for _, shown := range shownToolErrors {
+		if errors.Is(err, shown) {
+			return err.Error()
+		}
+	}
+	return "the tool failed, try again later"
+}
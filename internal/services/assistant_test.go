@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// fakeShop stands in for the product, cart and order services the shop
+// tools call, recording what they were asked
+type fakeShop struct {
+	interfaces.ProductServicer
+	interfaces.CartServicer
+	interfaces.OrderServicer
+
+	searches  []dto.SearchProductsRequest
+	cartAdds  []dto.AddToCartRequest
+	cartUsers []int32
+	cartErr   error
+}
+
+func (f *fakeShop) SearchProducts(ctx context.Context, req dto.SearchProductsRequest, currency string) (*dto.ProductSearchResponse, *utils.PaginationMeta, error) {
+	f.searches = append(f.searches, req)
+	return &dto.ProductSearchResponse{Results: []dto.ProductSearchResult{{
+		ProductResponse: dto.ProductResponse{
+			ID:             7,
+			Name:           "Trail Runner",
+			Price:          money.FromCents(8999),
+			Currency:       currency,
+			AvailableStock: 3,
+			Category:       dto.CategoryResponse{Name: "Shoes"},
+		},
+	}}}, &utils.PaginationMeta{}, nil
+}
+
+func (f *fakeShop) AddToCart(ctx context.Context, userID int32, req dto.AddToCartRequest, currency string) (*dto.CartResponse, error) {
+	if f.cartErr != nil {
+		return nil, f.cartErr
+	}
+	f.cartUsers = append(f.cartUsers, userID)
+	f.cartAdds = append(f.cartAdds, req)
+	total, err := money.FromCents(8999).Mul(int64(req.Quantity))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CartResponse{
+		CartItems: []dto.CartItemResponse{{Product: dto.ProductResponse{ID: req.ProductID, Name: "Trail Runner"}, Quantity: req.Quantity}},
+		Total:     total,
+		Currency:  currency,
+	}, nil
+}
+
+func (f *fakeShop) GetUserOrders(ctx context.Context, userID int32, page, limit int) ([]dto.OrderResponse, *utils.PaginationMeta, error) {
+	return []dto.OrderResponse{{
+		ID:          3,
+		Status:      "shipped",
+		TotalAmount: money.FromCents(1999),
+		Currency:    "USD",
+		OrderItems:  []dto.OrderItemResponse{{Product: dto.ProductResponse{Name: "Wool Socks"}, Quantity: 2}},
+	}}, &utils.PaginationMeta{}, nil
+}
+
+func toolCallReply(id, name, arguments string) dto.ChatMessage {
+	return dto.ChatMessage{ToolCalls: []dto.ToolCall{{ID: id, Name: name, Arguments: json.RawMessage(arguments)}}}
+}
+
+// conversationStore keeps assistant conversations in memory the way the
+// queries keep them in the database
+type conversationStore struct {
+	noopStore
+
+	mu   sync.Mutex
+	rows map[int32]db.AssistantConversation
+}
+
+func (c *conversationStore) DeleteQuietAssistantConversations(ctx context.Context, arg db.DeleteQuietAssistantConversationsParams) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, row := range c.rows {
+		if row.UpdatedAt.Time.Before(arg.QuietBefore.Time) && (!row.BusyUntil.Valid || row.BusyUntil.Time.Before(arg.Now.Time)) {
+			delete(c.rows, id)
+		}
+	}
+	return nil
+}
+
+func (c *conversationStore) ClaimAssistantConversation(ctx context.Context, arg db.ClaimAssistantConversationParams) (db.AssistantConversation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	row, ok := c.rows[arg.UserID]
+	if !ok {
+		row = db.AssistantConversation{UserID: arg.UserID, Messages: []byte("[]"), UpdatedAt: arg.Now}
+	} else if row.BusyUntil.Valid && !row.BusyUntil.Time.Before(arg.Now.Time) {
+		return db.AssistantConversation{}, pgx.ErrNoRows
+	}
+	row.BusyUntil = arg.BusyUntil
+	c.rows[arg.UserID] = row
+	return row, nil
+}
+
+func (c *conversationStore) SaveAssistantConversation(ctx context.Context, arg db.SaveAssistantConversationParams) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if row, ok := c.rows[arg.UserID]; ok && row.BusyUntil == arg.ClaimedUntil {
+		row.Messages, row.UpdatedAt, row.BusyUntil = arg.Messages, arg.UpdatedAt, pgtype.Timestamptz{}
+		c.rows[arg.UserID] = row
+	}
+	return nil
+}
+
+func (c *conversationStore) ReleaseAssistantConversation(ctx context.Context, arg db.ReleaseAssistantConversationParams) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if row, ok := c.rows[arg.UserID]; ok && row.BusyUntil == arg.ClaimedUntil {
+		row.BusyUntil = pgtype.Timestamptz{}
+		c.rows[arg.UserID] = row
+	}
+	return nil
+}
+
+// ExecTx runs fn against the store
+func (c *conversationStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	return fn(c)
+}
+
+// messages returns the user's stored conversation
+func (c *conversationStore) messages(t *testing.T, userID int32) []dto.ChatMessage {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var messages []dto.ChatMessage
+	require.NoError(t, json.Unmarshal(c.rows[userID].Messages, &messages))
+	return messages
+}
+
+func newTestAssistant(shop *fakeShop, llm interfaces.LLMClient) (*AssistantService, *conversationStore) {
+	store := &conversationStore{rows: map[int32]db.AssistantConversation{}}
+	return NewAssistantService(store, llm, NewShopTools(shop, shop, shop), 30*time.Minute, 20, 3), store
+}
+
+func collectEvents(events *[]dto.AssistantEvent) func(dto.AssistantEvent) {
+	return func(event dto.AssistantEvent) {
+		*events = append(*events, event)
+	}
+}
+
+func TestAssistantService_Chat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tool results are sent back before the reply", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &fakeShop{}
+		llm := providers.NewScriptedLLMClient(
+			toolCallReply("call_1", "search_products", `{"query":"trail shoes","limit":50}`),
+			dto.ChatMessage{Content: "The Trail Runner is 89.99 EUR."},
+		)
+		service, _ := newTestAssistant(shop, llm)
+
+		var events []dto.AssistantEvent
+		err := service.Chat(context.Background(), 1, "trail shoes?", "EUR", collectEvents(&events))
+		require.NoError(t, err)
+
+		require.Len(t, shop.searches, 1)
+		assert.Equal(t, "trail shoes", shop.searches[0].Query)
+		assert.Equal(t, maxAssistantSearchResults, shop.searches[0].Limit)
+
+		require.Len(t, events, 9)
+		assert.Equal(t, dto.AssistantEventToolCall, events[0].Type)
+		assert.Equal(t, "search_products", events[0].Tool)
+		assert.Equal(t, dto.AssistantEventToolResult, events[1].Type)
+		assert.JSONEq(t, `[{"id":7,"name":"Trail Runner","category":"Shoes","price":89.99,"currency":"EUR","in_stock":true}]`, string(events[1].Result))
+		// The reply streams word by word before it is sent whole
+		var streamed string
+		for _, event := range events[2:8] {
+			assert.Equal(t, dto.AssistantEventDelta, event.Type)
+			streamed += event.Content
+		}
+		assert.Equal(t, "The Trail Runner is 89.99 EUR.", streamed)
+		assert.Equal(t, dto.AssistantEvent{Type: dto.AssistantEventMessage, Content: "The Trail Runner is 89.99 EUR."}, events[8])
+
+		calls := llm.Calls()
+		require.Len(t, calls, 2)
+		second := calls[1]
+		require.Len(t, second, 4) // system, user, tool call, tool result
+		assert.Equal(t, dto.ChatRoleSystem, second[0].Role)
+		assert.Equal(t, dto.ChatRoleTool, second[3].Role)
+		assert.Equal(t, "call_1", second[3].ToolCallID)
+		assert.JSONEq(t, string(events[1].Result), second[3].Content)
+	})
+
+	t.Run("conversation carries over to the next message", func(t *testing.T) {
+		t.Parallel()
+
+		llm := providers.NewScriptedLLMClient(
+			dto.ChatMessage{Content: "Hi! What are you looking for?"},
+			dto.ChatMessage{Content: "Sure."},
+		)
+		service, _ := newTestAssistant(&fakeShop{}, llm)
+
+		require.NoError(t, service.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {}))
+		require.NoError(t, service.Chat(context.Background(), 1, "socks", "USD", func(dto.AssistantEvent) {}))
+
+		calls := llm.Calls()
+		require.Len(t, calls, 2)
+		assert.Equal(t, []dto.ChatMessage{
+			{Role: dto.ChatRoleUser, Content: "hello"},
+			{Role: dto.ChatRoleAssistant, Content: "Hi! What are you looking for?"},
+			{Role: dto.ChatRoleUser, Content: "socks"},
+		}, calls[1][1:])
+	})
+
+	t.Run("cart tool acts for the signed in user", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &fakeShop{}
+		llm := providers.NewScriptedLLMClient(
+			toolCallReply("call_1", "add_to_cart", `{"product_id":7,"quantity":2}`),
+			dto.ChatMessage{Content: "Added."},
+		)
+		service, _ := newTestAssistant(shop, llm)
+
+		var events []dto.AssistantEvent
+		require.NoError(t, service.Chat(context.Background(), 42, "add two", "USD", collectEvents(&events)))
+
+		assert.Equal(t, []int32{42}, shop.cartUsers)
+		assert.Equal(t, []dto.AddToCartRequest{{ProductID: 7, Quantity: 2}}, shop.cartAdds)
+		assert.JSONEq(t, `{"items":[{"product_id":7,"name":"Trail Runner","quantity":2}],"total":179.98,"currency":"USD"}`, string(events[1].Result))
+	})
+
+	t.Run("bad calls are reported to the model", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &fakeShop{}
+		llm := providers.NewScriptedLLMClient(
+			dto.ChatMessage{ToolCalls: []dto.ToolCall{
+				{ID: "call_1", Name: "add_to_cart", Arguments: json.RawMessage(`{"product_id":7,"quantity":50}`)},
+				{ID: "call_2", Name: "checkout", Arguments: json.RawMessage(`{}`)},
+				{ID: "call_3", Name: "get_orders", Arguments: json.RawMessage(`{"user_id":9}`)},
+			}},
+			dto.ChatMessage{Content: "Sorry, I can add at most 10."},
+		)
+		service, _ := newTestAssistant(shop, llm)
+
+		var events []dto.AssistantEvent
+		require.NoError(t, service.Chat(context.Background(), 1, "add fifty", "USD", collectEvents(&events)))
+
+		assert.Empty(t, shop.cartAdds)
+		require.Len(t, events, 14) // three calls with their results, seven words and the reply
+		assert.Equal(t, "invalid tool arguments: quantity must be between 1 and 10", events[1].Error)
+		assert.Equal(t, "unknown tool: checkout", events[3].Error)
+		assert.Contains(t, events[5].Error, `unknown field "user_id"`)
+
+		toolMessages := llm.Calls()[1][3:]
+		require.Len(t, toolMessages, 3)
+		assert.JSONEq(t, `{"error":"invalid tool arguments: quantity must be between 1 and 10"}`, toolMessages[0].Content)
+		assert.Equal(t, "call_2", toolMessages[1].ToolCallID)
+	})
+
+	t.Run("internal tool errors are not shown to the model", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &fakeShop{cartErr: errors.New("failed to get cart: connection refused")}
+		llm := providers.NewScriptedLLMClient(
+			toolCallReply("call_1", "add_to_cart", `{"product_id":7}`),
+			dto.ChatMessage{Content: "Something went wrong."},
+		)
+		service, _ := newTestAssistant(shop, llm)
+
+		var events []dto.AssistantEvent
+		require.NoError(t, service.Chat(context.Background(), 1, "add it", "USD", collectEvents(&events)))
+
+		assert.Equal(t, "the tool failed, try again later", events[1].Error)
+		assert.NotContains(t, llm.Calls()[1][3].Content, "connection refused")
+	})
+
+	t.Run("too many tool rounds", func(t *testing.T) {
+		t.Parallel()
+
+		llm := providers.NewScriptedLLMClient(
+			toolCallReply("call_1", "get_orders", `{}`),
+			toolCallReply("call_2", "get_orders", `{}`),
+			toolCallReply("call_3", "get_orders", `{}`),
+			toolCallReply("call_4", "get_orders", `{}`),
+			dto.ChatMessage{Content: "Hello again."},
+		)
+		service, _ := newTestAssistant(&fakeShop{}, llm)
+
+		err := service.Chat(context.Background(), 1, "orders?", "USD", func(dto.AssistantEvent) {})
+		assert.True(t, errors.Is(err, ErrAssistantToolLimit))
+		assert.Len(t, llm.Calls(), 4)
+
+		// The rounds that ran are kept, up to the one over the limit
+		require.NoError(t, service.Chat(context.Background(), 1, "hi", "USD", func(dto.AssistantEvent) {}))
+		next := llm.Calls()[4][1:]
+		require.Len(t, next, 8) // user, three tool calls with their results, user
+		assert.Equal(t, dto.ChatMessage{Role: dto.ChatRoleUser, Content: "orders?"}, next[0])
+		assert.Equal(t, "call_3", next[6].ToolCallID)
+		assert.Equal(t, dto.ChatMessage{Role: dto.ChatRoleUser, Content: "hi"}, next[7])
+	})
+
+	t.Run("completed tool calls are kept when the reply fails", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &fakeShop{}
+		llm := providers.NewScriptedLLMClient(
+			toolCallReply("call_1", "add_to_cart", `{"product_id":7,"quantity":1}`),
+		)
+		service, store := newTestAssistant(shop, llm)
+
+		err := service.Chat(context.Background(), 1, "add it", "USD", func(dto.AssistantEvent) {})
+		assert.ErrorContains(t, err, "failed to get assistant reply")
+		require.Len(t, shop.cartAdds, 1)
+
+		// The cart was changed, so the next message must not ask to add it again
+		messages := store.messages(t, 1)
+		require.Len(t, messages, 3)
+		assert.Equal(t, dto.ChatRoleUser, messages[0].Role)
+		assert.Equal(t, "call_1", messages[1].ToolCalls[0].ID)
+		assert.Equal(t, "call_1", messages[2].ToolCallID)
+	})
+
+	t.Run("a client going away stops the answer", func(t *testing.T) {
+		t.Parallel()
+
+		shop := &fakeShop{}
+		llm := providers.NewScriptedLLMClient(
+			toolCallReply("call_1", "add_to_cart", `{"product_id":7,"quantity":1}`),
+			toolCallReply("call_2", "add_to_cart", `{"product_id":7,"quantity":1}`),
+			dto.ChatMessage{Content: "Added."},
+		)
+		service, store := newTestAssistant(shop, llm)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := service.Chat(ctx, 1, "add it", "USD", func(event dto.AssistantEvent) {
+			if event.Type == dto.AssistantEventToolResult {
+				cancel()
+			}
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, shop.cartAdds, 1)
+		assert.Len(t, llm.Calls(), 1)
+
+		// The round that ran is kept and the conversation is free again
+		assert.Len(t, store.messages(t, 1), 3)
+		assert.False(t, store.rows[1].BusyUntil.Valid)
+	})
+
+	t.Run("model errors fail the message", func(t *testing.T) {
+		t.Parallel()
+
+		service, store := newTestAssistant(&fakeShop{}, providers.NewScriptedLLMClient())
+
+		err := service.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {})
+		assert.ErrorContains(t, err, "failed to get assistant reply")
+		// Nothing ran, so nothing is kept, and the conversation is free again
+		assert.Empty(t, store.messages(t, 1))
+		assert.False(t, store.rows[1].BusyUntil.Valid)
+	})
+}
+
+func TestAssistantService_Conversations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("one message at a time per user", func(t *testing.T) {
+		t.Parallel()
+
+		service, _ := newTestAssistant(&fakeShop{}, providers.NewScriptedLLMClient(dto.ChatMessage{Content: "Hi."}))
+		_, _, err := service.begin(context.Background(), 1)
+		require.NoError(t, err)
+
+		err = service.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {})
+		assert.True(t, errors.Is(err, ErrAssistantBusy))
+		assert.True(t, errors.Is(service.Reset(context.Background(), 1), ErrAssistantBusy))
+
+		// Other users aren't held up
+		require.NoError(t, service.Chat(context.Background(), 2, "hello", "USD", func(dto.AssistantEvent) {}))
+	})
+
+	t.Run("conversations are shared between replicas", func(t *testing.T) {
+		t.Parallel()
+
+		llm := providers.NewScriptedLLMClient(dto.ChatMessage{Content: "Hi."}, dto.ChatMessage{Content: "Sure."})
+		first, store := newTestAssistant(&fakeShop{}, llm)
+		second := NewAssistantService(store, llm, first.tools, 30*time.Minute, 20, 3)
+
+		_, _, err := first.begin(context.Background(), 1)
+		require.NoError(t, err)
+		err = second.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {})
+		assert.True(t, errors.Is(err, ErrAssistantBusy))
+
+		// A replica that stopped mid-answer frees the conversation once its claim runs out
+		now := time.Now()
+		second.now = func() time.Time { return now.Add(assistantClaimTimeout + time.Second) }
+		require.NoError(t, second.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {}))
+
+		first.now = second.now
+		require.NoError(t, first.Chat(context.Background(), 1, "socks", "USD", func(dto.AssistantEvent) {}))
+		assert.Equal(t, []dto.ChatMessage{
+			{Role: dto.ChatRoleUser, Content: "hello"},
+			{Role: dto.ChatRoleAssistant, Content: "Hi."},
+			{Role: dto.ChatRoleUser, Content: "socks"},
+		}, llm.Calls()[1][1:])
+	})
+
+	t.Run("quiet conversations expire", func(t *testing.T) {
+		t.Parallel()
+
+		llm := providers.NewScriptedLLMClient(dto.ChatMessage{Content: "Hi."}, dto.ChatMessage{Content: "Hi again."})
+		service, _ := newTestAssistant(&fakeShop{}, llm)
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		require.NoError(t, service.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {}))
+		now = now.Add(31 * time.Minute)
+		require.NoError(t, service.Chat(context.Background(), 1, "hello?", "USD", func(dto.AssistantEvent) {}))
+
+		assert.Equal(t, []dto.ChatMessage{{Role: dto.ChatRoleUser, Content: "hello?"}}, llm.Calls()[1][1:])
+	})
+
+	t.Run("reset forgets the conversation", func(t *testing.T) {
+		t.Parallel()
+
+		llm := providers.NewScriptedLLMClient(dto.ChatMessage{Content: "Hi."}, dto.ChatMessage{Content: "Hi again."})
+		service, _ := newTestAssistant(&fakeShop{}, llm)
+
+		require.NoError(t, service.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {}))
+		require.NoError(t, service.Reset(context.Background(), 1))
+		require.NoError(t, service.Chat(context.Background(), 1, "hello", "USD", func(dto.AssistantEvent) {}))
+
+		assert.Len(t, llm.Calls()[1], 2)
+	})
+}
+
+func TestTrimHistory(t *testing.T) {
+	t.Parallel()
+
+	messages := []dto.ChatMessage{
+		{Role: dto.ChatRoleUser, Content: "1"},
+		{Role: dto.ChatRoleAssistant, ToolCalls: []dto.ToolCall{{ID: "a"}}},
+		{Role: dto.ChatRoleTool, ToolCallID: "a"},
+		{Role: dto.ChatRoleAssistant, Content: "2"},
+		{Role: dto.ChatRoleUser, Content: "3"},
+		{Role: dto.ChatRoleAssistant, Content: "4"},
+	}
+
+	assert.Equal(t, messages, trimHistory(messages, 6))
+	// Cutting at 4 would start on a tool result, so the history starts at the next user message
+	assert.Equal(t, messages[4:], trimHistory(messages, 4))
+	assert.Nil(t, trimHistory(messages, 1))
+}
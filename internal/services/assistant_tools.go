@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+const (
+	maxAssistantSearchResults = 10
+	maxAssistantCartQuantity  = 10
+	maxAssistantOrders        = 10
+)
+
+var (
+	ErrUnknownTool          = errors.New("unknown tool")
+	ErrInvalidToolArguments = errors.New("invalid tool arguments")
+)
+
+// ToolContext is who a tool runs for. It comes from the authenticated
+// request, never from the model, so a tool can't act for another user.
+type ToolContext struct {
+	UserID   int32
+	Currency string
+}
+
+// Tool is an action the assistant's model may take. Run gets the model's
+// JSON arguments and returns a JSON-encodable result. Argument errors should
+// wrap ErrInvalidToolArguments so the model is told what it got wrong.
+type Tool struct {
+	Definition dto.ToolDefinition
+	Run        func(ctx context.Context, tc ToolContext, arguments json.RawMessage) (any, error)
+}
+
+// ToolRegistry holds the tools the assistant offers its model, by name
+type ToolRegistry struct {
+	tools map[string]Tool
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: map[string]Tool{}}
+}
+
+// Register adds a tool, replacing any tool with the same name
+func (r *ToolRegistry) Register(tool Tool) {
+	r.tools[tool.Definition.Name] = tool
+}
+
+// Definitions describes the registered tools, sorted by name
+func (r *ToolRegistry) Definitions() []dto.ToolDefinition {
+	definitions := make([]dto.ToolDefinition, 0, len(r.tools))
+	for _, tool := range r.tools {
+		definitions = append(definitions, tool.Definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// Call runs the named tool and encodes its result as JSON
+func (r *ToolRegistry) Call(ctx context.Context, tc ToolContext, name string, arguments json.RawMessage) (json.RawMessage, error) {
+	tool, ok := r.tools[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+	result, err := tool.Run(ctx, tc, arguments)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s result: %w", name, err)
+	}
+	return encoded, nil
+}
+
+// assistantProduct is what the model is told about a product: enough to
+// describe and pick it, without image and timestamp noise
+type assistantProduct struct {
+	ID             uint         `json:"id"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description,omitempty"`
+	Category       string       `json:"category"`
+	Price          money.Money  `json:"price"`
+	CompareAtPrice *money.Money `json:"compare_at_price,omitempty"`
+	Currency       string       `json:"currency"`
+	InStock        bool         `json:"in_stock"`
+}
+
+type assistantCart struct {
+	Items    []assistantCartItem `json:"items"`
+	Total    money.Money         `json:"total"`
+	Currency string              `json:"currency"`
+}
+
+type assistantCartItem struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+}
+
+type assistantOrder struct {
+	ID        uint        `json:"id"`
+	Status    string      `json:"status"`
+	Total     money.Money `json:"total"`
+	Currency  string      `json:"currency"`
+	Items     []string    `json:"items"` // "2 x Trail Runner"
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// NewShopTools registers the tools that let the assistant search the
+// catalog, add to the user's cart and look up the user's orders
+func NewShopTools(products interfaces.ProductServicer, carts interfaces.CartServicer, orders interfaces.OrderServicer) *ToolRegistry {
+	registry := NewToolRegistry()
+
+	registry.Register(Tool{
+		Definition: dto.ToolDefinition{
+			Name:        "search_products",
+			Description: "Search the catalog. Returns matching products with their ID, price and whether they are in stock.",
+			Parameters: json.RawMessage(`{"type":"object","properties":{` +
+				`"query":{"type":"string","description":"What the shopper is looking for"},` +
+				`"max_price":{"type":"number","description":"Highest price in the shopper's currency"},` +
+				`"in_stock":{"type":"boolean","description":"Only products in stock"},` +
+				`"limit":{"type":"integer","minimum":1,"maximum":10}},"required":["query"]}`),
+		},
+		Run: func(ctx context.Context, tc ToolContext, arguments json.RawMessage) (any, error) {
+			var args struct {
+				Query    string       `json:"query"`
+				MaxPrice *money.Money `json:"max_price"`
+				InStock  *bool        `json:"in_stock"`
+				Limit    int          `json:"limit"`
+			}
+			if err := decodeToolArguments(arguments, &args); err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(args.Query) == "" {
+				return nil, fmt.Errorf("%w: query is required", ErrInvalidToolArguments)
+			}
+			if args.Limit <= 0 || args.Limit > maxAssistantSearchResults {
+				args.Limit = maxAssistantSearchResults
+			}
+
+			results, _, err := products.SearchProducts(ctx, dto.SearchProductsRequest{
+				Query:    args.Query,
+				Page:     1,
+				Limit:    args.Limit,
+				MaxPrice: args.MaxPrice,
+				InStock:  args.InStock,
+			}, tc.Currency)
+			if err != nil {
+				return nil, err
+			}
+
+			found := make([]assistantProduct, len(results.Results))
+			for i, result := range results.Results {
+				found[i] = toAssistantProduct(result.ProductResponse)
+			}
+			return found, nil
+		},
+	})
+
+	registry.Register(Tool{
+		Definition: dto.ToolDefinition{
+			Name:        "add_to_cart",
+			Description: "Add a product to the shopper's cart. Only use product IDs returned by search_products.",
+			Parameters: json.RawMessage(`{"type":"object","properties":{` +
+				`"product_id":{"type":"integer"},` +
+				`"quantity":{"type":"integer","minimum":1,"maximum":10}},"required":["product_id"]}`),
+		},
+		Run: func(ctx context.Context, tc ToolContext, arguments json.RawMessage) (any, error) {
+			var args struct {
+				ProductID uint `json:"product_id"`
+				Quantity  int  `json:"quantity"`
+			}
+			if err := decodeToolArguments(arguments, &args); err != nil {
+				return nil, err
+			}
+			if args.ProductID == 0 {
+				return nil, fmt.Errorf("%w: product_id is required", ErrInvalidToolArguments)
+			}
+			if args.Quantity == 0 {
+				args.Quantity = 1
+			}
+			if args.Quantity < 1 || args.Quantity > maxAssistantCartQuantity {
+				return nil, fmt.Errorf("%w: quantity must be between 1 and %d", ErrInvalidToolArguments, maxAssistantCartQuantity)
+			}
+
+			cart, err := carts.AddToCart(ctx, tc.UserID, dto.AddToCartRequest{
+				ProductID: args.ProductID,
+				Quantity:  args.Quantity,
+			}, tc.Currency)
+			if err != nil {
+				return nil, err
+			}
+
+			summary := assistantCart{
+				Items:    make([]assistantCartItem, len(cart.CartItems)),
+				Total:    cart.Total,
+				Currency: cart.Currency,
+			}
+			for i, item := range cart.CartItems {
+				summary.Items[i] = assistantCartItem{
+					ProductID: item.Product.ID,
+					Name:      item.Product.Name,
+					Quantity:  item.Quantity,
+				}
+			}
+			return summary, nil
+		},
+	})
+
+	registry.Register(Tool{
+		Definition: dto.ToolDefinition{
+			Name:        "get_orders",
+			Description: "List the shopper's most recent orders with their status and items.",
+			Parameters: json.RawMessage(`{"type":"object","properties":{` +
+				`"limit":{"type":"integer","minimum":1,"maximum":10}}}`),
+		},
+		Run: func(ctx context.Context, tc ToolContext, arguments json.RawMessage) (any, error) {
+			var args struct {
+				Limit int `json:"limit"`
+			}
+			if err := decodeToolArguments(arguments, &args); err != nil {
+				return nil, err
+			}
+			if args.Limit <= 0 || args.Limit > maxAssistantOrders {
+				args.Limit = maxAssistantOrders
+			}
+
+			userOrders, _, err := orders.GetUserOrders(ctx, tc.UserID, 1, args.Limit)
+			if err != nil {
+				return nil, err
+			}
+
+			summaries := make([]assistantOrder, len(userOrders))
+			for i, order := range userOrders {
+				items := make([]string, len(order.OrderItems))
+				for j, item := range order.OrderItems {
+					items[j] = fmt.Sprintf("%d x %s", item.Quantity, item.Product.Name)
+				}
+				summaries[i] = assistantOrder{
+					ID:        order.ID,
+					Status:    order.Status,
+					Total:     order.TotalAmount,
+					Currency:  order.Currency,
+					Items:     items,
+					CreatedAt: order.CreatedAt,
+				}
+			}
+			return summaries, nil
+		},
+	})
+
+	return registry
+}
+
+// decodeToolArguments reads the model's arguments strictly, so a misspelt
+// argument is reported back to the model rather than quietly ignored
+func decodeToolArguments(arguments json.RawMessage, v any) error {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(arguments)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolArguments, err)
+	}
+	return nil
+}
+
+func toAssistantProduct(product dto.ProductResponse) assistantProduct {
+	return assistantProduct{
+		ID:             product.ID,
+		Name:           product.Name,
+		Description:    product.Description,
+		Category:       product.Category.Name,
+		Price:          product.Price,
+		CompareAtPrice: product.CompareAtPrice,
+		Currency:       product.Currency,
+		InStock:        product.AvailableStock > 0,
+	}
+}
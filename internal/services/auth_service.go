@@ -83,6 +83,8 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (dto.Auth
 
 	// check password
 	if err := utils.VerifyPassword(user.Password, req.Password); err != nil {
+		// Failed logins count towards the fraud risk of the account's orders
+		_ = s.db.RecordFailedLogin(ctx, user.ID)
 		return dto.AuthResponse{}, errors.New("invalid email or password")
 	}
 
@@ -34,6 +34,7 @@ func (m *MockEventPublisher) Close() error {
 // MockAuthStore provides mocked store methods for AuthService
 type MockAuthStore struct {
 	mock.Mock
+	noopStore
 }
 
 func (m *MockAuthStore) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
@@ -71,11 +72,21 @@ func (m *MockAuthStore) GetUserByID(ctx context.Context, id int32) (db.User, err
 	return args.Get(0).(db.User), args.Error(1)
 }
 
-func (m *MockAuthStore) ExecTx(ctx context.Context, fn func(*db.Queries) error) error {
-	args := m.Called(ctx, fn)
+func (m *MockAuthStore) RecordFailedLogin(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
 
+// ExecTx records the transaction and, unless it is mocked to fail, runs fn
+// against the mock, so the queries made inside it are checked too
+func (m *MockAuthStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	args := m.Called(ctx, fn)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
 // Helper function to create a test config
 func newAuthTestConfig() *config.Config {
 	return &config.Config{
@@ -272,6 +283,7 @@ func TestAuthService_Login(t *testing.T) {
 			},
 			setupMock: func(m *MockAuthStore, pub *MockEventPublisher) {
 				m.On("GetUserByEmail", mock.Anything, "test@example.com").Return(testUser, nil)
+				m.On("RecordFailedLogin", mock.Anything, testUser.ID).Return(nil)
 			},
 			wantErr: true,
 			errMsg:  "invalid email or password",
@@ -511,7 +523,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 	}
 }
 
-// authStoreWrapper wraps MockAuthStore to implement db.Store interface
+// authStoreWrapper wraps MockAuthStore as a db.Store; queries it doesn't mock fall through to noopStore
 type authStoreWrapper struct {
 	*MockAuthStore
 }
@@ -519,219 +531,3 @@ type authStoreWrapper struct {
 func createAuthStoreWrapper(m *MockAuthStore) db.Store {
 	return &authStoreWrapper{MockAuthStore: m}
 }
-
-// Implement remaining db.Store methods as stubs
-func (s *authStoreWrapper) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *authStoreWrapper) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) error {
-	return nil
-}
-func (s *authStoreWrapper) UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *authStoreWrapper) UpdateUserStatus(ctx context.Context, arg db.UpdateUserStatusParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *authStoreWrapper) SoftDeleteUser(ctx context.Context, id int32) error { return nil }
-func (s *authStoreWrapper) ListUsers(ctx context.Context, arg db.ListUsersParams) ([]db.User, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) CountUsers(ctx context.Context) (int64, error) { return 0, nil }
-func (s *authStoreWrapper) DeleteRefreshTokensByUserID(ctx context.Context, u int32) error {
-	return nil
-}
-func (s *authStoreWrapper) DeleteExpiredRefreshTokens(ctx context.Context) error { return nil }
-func (s *authStoreWrapper) GetRefreshTokensByUserID(ctx context.Context, u int32) ([]db.RefreshToken, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) GetCartByUserID(ctx context.Context, u int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *authStoreWrapper) GetCartByID(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *authStoreWrapper) UpdateCartTimestamp(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *authStoreWrapper) SoftDeleteCart(ctx context.Context, id int32) error        { return nil }
-func (s *authStoreWrapper) SoftDeleteCartByUserID(ctx context.Context, u int32) error { return nil }
-func (s *authStoreWrapper) CreateCartItem(ctx context.Context, arg db.CreateCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *authStoreWrapper) GetCartItem(ctx context.Context, arg db.GetCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *authStoreWrapper) GetCartItemByID(ctx context.Context, id int32) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *authStoreWrapper) ListCartItems(ctx context.Context, c int32) ([]db.CartItem, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) UpdateCartItemQuantity(ctx context.Context, arg db.UpdateCartItemQuantityParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *authStoreWrapper) UpsertCartItem(ctx context.Context, arg db.UpsertCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *authStoreWrapper) RestoreCartItem(ctx context.Context, arg db.RestoreCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *authStoreWrapper) SoftDeleteCartItem(ctx context.Context, id int32) error { return nil }
-func (s *authStoreWrapper) SoftDeleteCartItemsByCartID(ctx context.Context, c int32) error {
-	return nil
-}
-func (s *authStoreWrapper) CountCartItems(ctx context.Context, c int32) (int64, error) { return 0, nil }
-func (s *authStoreWrapper) CreateCategory(ctx context.Context, arg db.CreateCategoryParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *authStoreWrapper) GetCategoryByID(ctx context.Context, id int32) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *authStoreWrapper) GetCategoriesByIDs(ctx context.Context, ids []int32) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListCategories(ctx context.Context, arg db.ListCategoriesParams) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListActiveCategories(ctx context.Context) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) UpdateCategory(ctx context.Context, arg db.UpdateCategoryParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *authStoreWrapper) UpdateCategoryStatus(ctx context.Context, arg db.UpdateCategoryStatusParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *authStoreWrapper) SoftDeleteCategory(ctx context.Context, id int32) error { return nil }
-func (s *authStoreWrapper) CountCategories(ctx context.Context) (int64, error)     { return 0, nil }
-func (s *authStoreWrapper) CreateProduct(ctx context.Context, arg db.CreateProductParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) GetProductByID(ctx context.Context, id int32) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) GetProductBySKU(ctx context.Context, sku string) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) GetProductsByIDs(ctx context.Context, ids []int32) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) GetProductsByIDsForUpdate(ctx context.Context, ids []int32) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListProducts(ctx context.Context, arg db.ListProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListActiveProducts(ctx context.Context, arg db.ListActiveProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListProductsByCategory(ctx context.Context, arg db.ListProductsByCategoryParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) UpdateProduct(ctx context.Context, arg db.UpdateProductParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) UpdateProductStatus(ctx context.Context, arg db.UpdateProductStatusParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) UpdateProductStock(ctx context.Context, arg db.UpdateProductStockParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *authStoreWrapper) SoftDeleteProduct(ctx context.Context, id int32) error  { return nil }
-func (s *authStoreWrapper) CountProducts(ctx context.Context) (int64, error)       { return 0, nil }
-func (s *authStoreWrapper) CountActiveProducts(ctx context.Context) (int64, error) { return 0, nil }
-func (s *authStoreWrapper) CountProductsByCategory(ctx context.Context, c int32) (int64, error) {
-	return 0, nil
-}
-func (s *authStoreWrapper) CreateProductImage(ctx context.Context, arg db.CreateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *authStoreWrapper) GetProductImageByID(ctx context.Context, id int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *authStoreWrapper) GetPrimaryProductImage(ctx context.Context, p int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *authStoreWrapper) ListProductImages(ctx context.Context, p int32) ([]db.ProductImage, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListProductImagesByProductIDs(ctx context.Context, ids []int32) ([]db.ProductImage, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) UpdateProductImage(ctx context.Context, arg db.UpdateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *authStoreWrapper) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) error {
-	return nil
-}
-func (s *authStoreWrapper) SoftDeleteProductImage(ctx context.Context, id int32) error { return nil }
-func (s *authStoreWrapper) SoftDeleteProductImagesByProductID(ctx context.Context, p int32) error {
-	return nil
-}
-func (s *authStoreWrapper) CreateOrder(ctx context.Context, arg db.CreateOrderParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *authStoreWrapper) GetOrderByID(ctx context.Context, id int32) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *authStoreWrapper) ListOrders(ctx context.Context, arg db.ListOrdersParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListOrdersByUserID(ctx context.Context, arg db.ListOrdersByUserIDParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) ListOrdersByStatus(ctx context.Context, arg db.ListOrdersByStatusParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) UpdateOrderStatus(ctx context.Context, arg db.UpdateOrderStatusParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *authStoreWrapper) UpdateOrderTotal(ctx context.Context, arg db.UpdateOrderTotalParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *authStoreWrapper) SoftDeleteOrder(ctx context.Context, id int32) error { return nil }
-func (s *authStoreWrapper) CountOrders(ctx context.Context) (int64, error)      { return 0, nil }
-func (s *authStoreWrapper) CountOrdersByUserID(ctx context.Context, u int32) (int64, error) {
-	return 0, nil
-}
-func (s *authStoreWrapper) CountOrdersByStatus(ctx context.Context, s2 db.NullOrderStatus) (int64, error) {
-	return 0, nil
-}
-func (s *authStoreWrapper) GetOrderTotal(ctx context.Context, o int32) (pgtype.Numeric, error) {
-	return pgtype.Numeric{}, nil
-}
-func (s *authStoreWrapper) CreateOrderItem(ctx context.Context, arg db.CreateOrderItemParams) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *authStoreWrapper) GetOrderItemByID(ctx context.Context, id int32) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *authStoreWrapper) ListOrderItems(ctx context.Context, o int32) ([]db.OrderItem, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) SoftDeleteOrderItem(ctx context.Context, id int32) error { return nil }
-func (s *authStoreWrapper) SoftDeleteOrderItemsByOrderID(ctx context.Context, o int32) error {
-	return nil
-}
-func (s *authStoreWrapper) CountOrderItems(ctx context.Context, o int32) (int64, error) {
-	return 0, nil
-}
-func (s *authStoreWrapper) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *authStoreWrapper) GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *authStoreWrapper) UpdateIdempotencyKeyOrderID(ctx context.Context, arg db.UpdateIdempotencyKeyOrderIDParams) error {
-	return nil
-}
-func (s *authStoreWrapper) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
-	return nil, nil
-}
-func (s *authStoreWrapper) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
-	return 0, nil
-}
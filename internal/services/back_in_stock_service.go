@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+)
+
+const (
+	defaultBackInStockBatchSize   = 100
+	defaultBackInStockRetryDelay  = time.Minute
+	defaultBackInStockMaxAttempts = 5
+
+	// backInStockClaimLease is how long a notifier has to email a claimed
+	// batch before another may claim it
+	backInStockClaimLease = 10 * time.Minute
+
+	// backInStockRetryJob locks the retries so only one notifier replica
+	// sends them
+	backInStockRetryJob = "back_in_stock_retry"
+)
+
+var (
+	ErrProductInStock       = errors.New("product is in stock")
+	ErrSubscriptionNotFound = errors.New("back in stock subscription not found")
+	ErrBackInStockRetrying  = errors.New("back in stock emails failed and will be retried")
+)
+
+// BackInStockService lets customers ask to be emailed when an out-of-stock
+// product returns, and works through those requests when it does
+type BackInStockService struct {
+	store       db.Store
+	batchSize   int
+	retryDelay  time.Duration // before the first retry of a failed email, doubled for each after
+	maxAttempts int32         // failed sends after which a subscription is given up on
+}
+
+func NewBackInStockService(store db.Store, batchSize int, retryDelay time.Duration, maxAttempts int) *BackInStockService {
+	if batchSize <= 0 {
+		batchSize = defaultBackInStockBatchSize
+	}
+	if retryDelay <= 0 {
+		retryDelay = defaultBackInStockRetryDelay
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultBackInStockMaxAttempts
+	}
+	return &BackInStockService{
+		store:       store,
+		batchSize:   batchSize,
+		retryDelay:  retryDelay,
+		maxAttempts: int32(maxAttempts), //#nosec G115 -- max attempts is a small config value
+	}
+}
+
+// Subscribe records that the user wants to hear when the product is back in
+// stock. Subscribing twice returns the existing subscription.
+func (s *BackInStockService) Subscribe(ctx context.Context, userID, productID int32) (*dto.BackInStockSubscriptionResponse, error) {
+	product, err := s.store.GetProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if product.Stock.Int32 > 0 {
+		return nil, ErrProductInStock
+	}
+
+	subscription, err := s.store.CreateBackInStockSubscription(ctx, db.CreateBackInStockSubscriptionParams{
+		UserID:    userID,
+		ProductID: productID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return toBackInStockSubscriptionResponse(subscription), nil
+}
+
+// GetSubscription returns the user's pending subscription for the product
+func (s *BackInStockService) GetSubscription(ctx context.Context, userID, productID int32) (*dto.BackInStockSubscriptionResponse, error) {
+	subscription, err := s.store.GetPendingBackInStockSubscription(ctx, db.GetPendingBackInStockSubscriptionParams{
+		UserID:    userID,
+		ProductID: productID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return toBackInStockSubscriptionResponse(subscription), nil
+}
+
+// Unsubscribe drops the user's pending subscription for the product
+func (s *BackInStockService) Unsubscribe(ctx context.Context, userID, productID int32) error {
+	deleted, err := s.store.DeletePendingBackInStockSubscription(ctx, db.DeletePendingBackInStockSubscriptionParams{
+		UserID:    userID,
+		ProductID: productID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	if deleted == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// NotifySubscribers calls send for every pending subscriber of the product in
+// the back_in_stock event, a batch at a time, and marks each subscription
+// fulfilled once its email has gone out. Each batch is claimed before it is
+// sent, so duplicate events and retries on other replicas skip it. A
+// subscription whose email fails is put off with backoff for RetryFailed to
+// retry, so calling it again never resends an email that went out. It returns
+// how many subscribers were notified, and ErrBackInStockRetrying if any email
+// failed.
+func (s *BackInStockService) NotifySubscribers(ctx context.Context, event notifications.Notification, send func(n notifications.Notification) error) (int, error) {
+	productID := int32(event.ProductID) //#nosec G115 -- product IDs come from an int32 column
+
+	var (
+		notified int
+		errs     []error
+	)
+	for {
+		subscribers, err := s.store.ClaimBackInStockSubscribers(ctx, db.ClaimBackInStockSubscribersParams{
+			Lease:       pgtype.Interval{Microseconds: backInStockClaimLease.Microseconds(), Valid: true},
+			ProductID:   productID,
+			MaxAttempts: s.maxAttempts,
+			Limit:       int32(s.batchSize), //#nosec G115 -- batch size is a small config value
+		})
+		if err != nil {
+			return notified, fmt.Errorf("failed to claim subscribers: %w", err)
+		}
+		if len(subscribers) == 0 {
+			break
+		}
+
+		var fulfilled, failed []int32
+		for _, sub := range subscribers {
+			n := event
+			n.Type = notifications.NotificationTypeBackInStock
+			n.UserID = int64(sub.UserID)
+			n.Email = sub.Email
+			n.Username = sub.FirstName
+
+			if err := send(n); err != nil {
+				errs = append(errs, fmt.Errorf("failed to notify subscription %d: %w", sub.ID, err))
+				failed = append(failed, sub.ID)
+				continue
+			}
+			fulfilled = append(fulfilled, sub.ID)
+		}
+
+		// the emails have gone out, so record them even if the caller gives up
+		finishCtx := context.WithoutCancel(ctx)
+		if len(fulfilled) > 0 {
+			if err := s.store.MarkBackInStockSubscriptionsFulfilled(finishCtx, fulfilled); err != nil {
+				return notified, fmt.Errorf("failed to mark subscriptions fulfilled: %w", err)
+			}
+			notified += len(fulfilled)
+		}
+		if len(failed) > 0 {
+			err := s.store.RecordBackInStockFailures(finishCtx, db.RecordBackInStockFailuresParams{
+				RetryDelay: pgtype.Interval{Microseconds: s.retryDelay.Microseconds(), Valid: true},
+				Ids:        failed,
+			})
+			if err != nil {
+				return notified, fmt.Errorf("failed to record failed subscriptions: %w", err)
+			}
+		}
+
+		if len(subscribers) < s.batchSize {
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return notified, fmt.Errorf("%w: %w", ErrBackInStockRetrying, errors.Join(errs...))
+	}
+	return notified, nil
+}
+
+// RetryFailed resends the emails whose retry is due, for products still in
+// stock. When another replica is retrying, it leaves them to it. It returns
+// how many subscribers were notified, and ErrBackInStockRetrying if any
+// email failed again.
+func (s *BackInStockService) RetryFailed(ctx context.Context, send func(n notifications.Notification) error) (int, error) {
+	var (
+		notified int
+		errs     []error
+	)
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		locked, err := q.TryJobLock(ctx, backInStockRetryJob)
+		if err != nil {
+			return fmt.Errorf("failed to lock back in stock retries: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		products, err := q.ListBackInStockRetryProducts(ctx, s.maxAttempts)
+		if err != nil {
+			return fmt.Errorf("failed to list products to retry: %w", err)
+		}
+
+		for _, p := range products {
+			sent, err := s.NotifySubscribers(ctx, notifications.Notification{
+				Type:        notifications.NotificationTypeBackInStock,
+				ProductID:   int64(p.ID),
+				SKU:         p.Sku,
+				ProductName: p.Name,
+			}, send)
+			notified += sent
+			if err != nil {
+				if !errors.Is(err, ErrBackInStockRetrying) {
+					return err
+				}
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return notified, err
+	}
+	return notified, errors.Join(errs...)
+}
+
+func toBackInStockSubscriptionResponse(s db.BackInStockSubscription) *dto.BackInStockSubscriptionResponse {
+	return &dto.BackInStockSubscriptionResponse{
+		ID:        uint(s.ID),        //#nosec G115 -- DB ID is always positive
+		ProductID: uint(s.ProductID), //#nosec G115 -- DB ID is always positive
+		CreatedAt: s.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+)
+
+func TestBackInStockService_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	soldOut := createTestProduct()
+	soldOut.Stock = pgtype.Int4{Int32: 0, Valid: true}
+
+	tests := []struct {
+		name      string
+		setupMock func(m *mocks.MockStore)
+		wantErr   error
+	}{
+		{
+			name: "success - subscribed",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(soldOut, nil)
+				m.On("CreateBackInStockSubscription", mock.Anything, db.CreateBackInStockSubscriptionParams{
+					UserID:    7,
+					ProductID: 1,
+				}).Return(db.BackInStockSubscription{ID: 3, UserID: 7, ProductID: 1}, nil)
+			},
+		},
+		{
+			name: "error - product in stock",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+			},
+			wantErr: ErrProductInStock,
+		},
+		{
+			name: "error - product not found",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(db.Product{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewBackInStockService(mockStore, 0, 0, 0)
+
+			resp, err := service.Subscribe(context.Background(), 7, 1)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertExpectations(t)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint(3), resp.ID)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackInStockService_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("DeletePendingBackInStockSubscription", mock.Anything, db.DeletePendingBackInStockSubscriptionParams{
+		UserID:    7,
+		ProductID: 1,
+	}).Return(int64(0), nil)
+
+	service := NewBackInStockService(mockStore, 0, 0, 0)
+
+	err := service.Unsubscribe(context.Background(), 7, 1)
+
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+}
+
+func TestBackInStockService_NotifySubscribers(t *testing.T) {
+	t.Parallel()
+
+	event := notifications.Notification{
+		Type:        notifications.NotificationTypeBackInStock,
+		ProductID:   1,
+		SKU:         "TEST-001",
+		ProductName: "Test Product",
+	}
+
+	t.Run("success - pages through subscribers", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		claim := db.ClaimBackInStockSubscribersParams{
+			Lease:     pgtype.Interval{Microseconds: backInStockClaimLease.Microseconds(), Valid: true},
+			ProductID: 1, MaxAttempts: 5, Limit: 2,
+		}
+		mockStore.On("ClaimBackInStockSubscribers", mock.Anything, claim).Return([]db.ClaimBackInStockSubscribersRow{
+			{ID: 1, UserID: 10, Email: "a@example.com", FirstName: "Ada"},
+			{ID: 2, UserID: 11, Email: "b@example.com", FirstName: "Bo"},
+		}, nil).Once()
+		mockStore.On("ClaimBackInStockSubscribers", mock.Anything, claim).Return([]db.ClaimBackInStockSubscribersRow{
+			{ID: 5, UserID: 12, Email: "c@example.com", FirstName: "Cy"},
+		}, nil).Once()
+		mockStore.On("MarkBackInStockSubscriptionsFulfilled", mock.Anything, []int32{1, 2}).Return(nil)
+		mockStore.On("MarkBackInStockSubscriptionsFulfilled", mock.Anything, []int32{5}).Return(nil)
+
+		service := NewBackInStockService(mockStore, 2, 0, 0)
+
+		var sentTo []string
+		sent, err := service.NotifySubscribers(context.Background(), event, func(n notifications.Notification) error {
+			assert.Equal(t, "TEST-001", n.SKU)
+			sentTo = append(sentTo, n.Email)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, sent)
+		assert.Equal(t, []string{"a@example.com", "b@example.com", "c@example.com"}, sentTo)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("error - failed sends are put off for a retry", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ClaimBackInStockSubscribers", mock.Anything, mock.Anything).Return([]db.ClaimBackInStockSubscribersRow{
+			{ID: 1, UserID: 10, Email: "a@example.com"},
+			{ID: 2, UserID: 11, Email: "bounce@example.com"},
+		}, nil).Once()
+		mockStore.On("MarkBackInStockSubscriptionsFulfilled", mock.Anything, []int32{1}).Return(nil)
+		mockStore.On("RecordBackInStockFailures", mock.Anything, db.RecordBackInStockFailuresParams{
+			RetryDelay: pgtype.Interval{Microseconds: 30_000_000, Valid: true},
+			Ids:        []int32{2},
+		}).Return(nil)
+
+		service := NewBackInStockService(mockStore, 10, 30*time.Second, 0)
+
+		sent, err := service.NotifySubscribers(context.Background(), event, func(n notifications.Notification) error {
+			if n.Email == "bounce@example.com" {
+				return errors.New("mailbox unavailable")
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrBackInStockRetrying)
+		assert.Equal(t, 1, sent)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("success - sent emails are recorded after the caller gives up", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ClaimBackInStockSubscribers", mock.Anything, mock.Anything).Return([]db.ClaimBackInStockSubscribersRow{
+			{ID: 1, UserID: 10, Email: "a@example.com"},
+		}, nil).Once()
+		mockStore.On("MarkBackInStockSubscriptionsFulfilled", mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Err() == nil
+		}), []int32{1}).Return(nil)
+
+		service := NewBackInStockService(mockStore, 10, 0, 0)
+
+		sent, err := service.NotifySubscribers(ctx, event, func(notifications.Notification) error {
+			cancel()
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		mockStore.AssertExpectations(t)
+	})
+}
+
+func TestBackInStockService_RetryFailed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - resends the due emails", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("TryJobLock", mock.Anything, "back_in_stock_retry").Return(true, nil)
+		mockStore.On("ListBackInStockRetryProducts", mock.Anything, int32(3)).Return([]db.ListBackInStockRetryProductsRow{
+			{ID: 1, Sku: "TEST-001", Name: "Test Product"},
+		}, nil)
+		mockStore.On("ClaimBackInStockSubscribers", mock.Anything, db.ClaimBackInStockSubscribersParams{
+			Lease:     pgtype.Interval{Microseconds: backInStockClaimLease.Microseconds(), Valid: true},
+			ProductID: 1, MaxAttempts: 3, Limit: 10,
+		}).Return([]db.ClaimBackInStockSubscribersRow{
+			{ID: 2, UserID: 11, Email: "b@example.com", FirstName: "Bo"},
+		}, nil)
+		mockStore.On("MarkBackInStockSubscriptionsFulfilled", mock.Anything, []int32{2}).Return(nil)
+
+		service := NewBackInStockService(mockStore, 10, 0, 3)
+
+		var got []notifications.Notification
+		sent, err := service.RetryFailed(context.Background(), func(n notifications.Notification) error {
+			got = append(got, n)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		require.Len(t, got, 1)
+		assert.Equal(t, notifications.NotificationTypeBackInStock, got[0].Type)
+		assert.Equal(t, "TEST-001", got[0].SKU)
+		assert.Equal(t, "Test Product", got[0].ProductName)
+		assert.Equal(t, "b@example.com", got[0].Email)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("success - another replica is retrying", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("TryJobLock", mock.Anything, "back_in_stock_retry").Return(false, nil)
+
+		service := NewBackInStockService(mockStore, 10, 0, 0)
+
+		sent, err := service.RetryFailed(context.Background(), func(notifications.Notification) error {
+			t.Fatal("send called without the lock")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		mockStore.AssertNotCalled(t, "ListBackInStockRetryProducts", mock.Anything, mock.Anything)
+		mockStore.AssertExpectations(t)
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 )
 
 var (
@@ -18,11 +20,14 @@ var (
 )
 
 type CartService struct {
-	store db.Store
+	store      db.Store
+	currencies *CurrencyConverter
 }
 
-func NewCartService(store db.Store) *CartService {
-	return &CartService{store: store}
+// NewCartService creates a CartService. A nil converter shows carts in
+// money.DefaultCurrency only.
+func NewCartService(store db.Store, currencies *CurrencyConverter) *CartService {
+	return &CartService{store: store, currencies: currencies}
 }
 
 // GetOrCreateCart gets the user's cart or creates a new one if it doesn't exist
@@ -43,17 +48,27 @@ func (s *CartService) GetOrCreateCart(ctx context.Context, userID int32) (*db.Ca
 }
 
 // GetCart returns the user's cart with all items and product details
-func (s *CartService) GetCart(ctx context.Context, userID int32) (*dto.CartResponse, error) {
+func (s *CartService) GetCart(ctx context.Context, userID int32, currency string) (*dto.CartResponse, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	cart, err := s.GetOrCreateCart(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.buildCartResponse(ctx, cart)
+	return s.buildCartResponse(ctx, cart, quote)
 }
 
 // AddToCart adds a product to the user's cart
-func (s *CartService) AddToCart(ctx context.Context, userID int32, req dto.AddToCartRequest) (*dto.CartResponse, error) {
+func (s *CartService) AddToCart(ctx context.Context, userID int32, req dto.AddToCartRequest, currency string) (*dto.CartResponse, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get or create cart
 	cart, err := s.GetOrCreateCart(ctx, userID)
 	if err != nil {
@@ -69,7 +84,14 @@ func (s *CartService) AddToCart(ctx context.Context, userID int32, req dto.AddTo
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	if int(product.Stock.Int32) < req.Quantity {
+	// Stock held by other customers' checkouts is not available
+	reserved, err := reservedStock(ctx, s.store, []int32{product.ID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	available := availableStock(product.Stock, reserved[product.ID])
+
+	if available < req.Quantity {
 		return nil, ErrInsufficientStock
 	}
 
@@ -85,7 +107,7 @@ func (s *CartService) AddToCart(ctx context.Context, userID int32, req dto.AddTo
 	if err == nil {
 		// Update existing active item quantity
 		newQuantity := existingItem.Quantity + int32(req.Quantity) //#nosec G115 -- quantity is validated
-		if int(product.Stock.Int32) < int(newQuantity) {
+		if available < int(newQuantity) {
 			return nil, ErrInsufficientStock
 		}
 		_, err = s.store.UpdateCartItemQuantity(ctx, db.UpdateCartItemQuantityParams{
@@ -127,11 +149,16 @@ func (s *CartService) AddToCart(ctx context.Context, userID int32, req dto.AddTo
 		return nil, fmt.Errorf("failed to update cart timestamp: %w", err)
 	}
 
-	return s.buildCartResponse(ctx, cart)
+	return s.buildCartResponse(ctx, cart, quote)
 }
 
 // UpdateCartItem updates the quantity of a cart item
-func (s *CartService) UpdateCartItem(ctx context.Context, userID int32, itemID int32, req dto.UpdateCartItemRequest) (*dto.CartResponse, error) {
+func (s *CartService) UpdateCartItem(ctx context.Context, userID int32, itemID int32, req dto.UpdateCartItemRequest, currency string) (*dto.CartResponse, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get cart
 	cart, err := s.store.GetCartByUserID(ctx, userID)
 	if err != nil {
@@ -160,7 +187,12 @@ func (s *CartService) UpdateCartItem(ctx context.Context, userID int32, itemID i
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	if int(product.Stock.Int32) < req.Quantity {
+	reserved, err := reservedStock(ctx, s.store, []int32{product.ID}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if availableStock(product.Stock, reserved[product.ID]) < req.Quantity {
 		return nil, ErrInsufficientStock
 	}
 
@@ -179,11 +211,16 @@ func (s *CartService) UpdateCartItem(ctx context.Context, userID int32, itemID i
 		return nil, fmt.Errorf("failed to update cart timestamp: %w", err)
 	}
 
-	return s.buildCartResponse(ctx, &cart)
+	return s.buildCartResponse(ctx, &cart, quote)
 }
 
 // RemoveCartItem removes an item from the cart
-func (s *CartService) RemoveCartItem(ctx context.Context, userID int32, itemID int32) (*dto.CartResponse, error) {
+func (s *CartService) RemoveCartItem(ctx context.Context, userID int32, itemID int32, currency string) (*dto.CartResponse, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get cart
 	cart, err := s.store.GetCartByUserID(ctx, userID)
 	if err != nil {
@@ -218,7 +255,7 @@ func (s *CartService) RemoveCartItem(ctx context.Context, userID int32, itemID i
 		return nil, fmt.Errorf("failed to update cart timestamp: %w", err)
 	}
 
-	return s.buildCartResponse(ctx, &cart)
+	return s.buildCartResponse(ctx, &cart, quote)
 }
 
 // ClearCart removes all items from the cart
@@ -236,11 +273,18 @@ func (s *CartService) ClearCart(ctx context.Context, userID int32) error {
 		return fmt.Errorf("failed to clear cart items: %w", err)
 	}
 
+	// Nothing left to check out, so stop holding stock
+	err = s.store.DeleteStockReservationsByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
 	return nil
 }
 
-// buildCartResponse builds a complete cart response with product details
-func (s *CartService) buildCartResponse(ctx context.Context, cart *db.Cart) (*dto.CartResponse, error) {
+// buildCartResponse builds a complete cart response with product details,
+// priced in the quoted currency
+func (s *CartService) buildCartResponse(ctx context.Context, cart *db.Cart, quote Quote) (*dto.CartResponse, error) {
 	items, err := s.store.ListCartItems(ctx, cart.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list cart items: %w", err)
@@ -288,28 +332,37 @@ func (s *CartService) buildCartResponse(ctx context.Context, cart *db.Cart) (*dt
 		}
 	}
 
+	// The owner's own holds are still available to them
+	reserved, err := reservedStock(ctx, s.store, productIDs, cart.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Price items at what they would sell for right now
+	prices, err := resolvePrices(ctx, s.store, productIDs, time.Now(), quote)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build response
-	var totalPrice float64
+	var totalPrice money.Money
 	cartItems := make([]dto.CartItemResponse, len(items))
 
 	for i, item := range items {
 		product := productMap[item.ProductID]
 		category := categoryMap[product.CategoryID]
-		priceFloat, _ := product.Price.Float64Value()
-		subtotal := priceFloat.Float64 * float64(item.Quantity)
-		totalPrice += subtotal
 
 		cartItems[i] = dto.CartItemResponse{
 			ID: uint(item.ID), //#nosec G115 -- DB ID is always positive
 			Product: dto.ProductResponse{
-				ID:          uint(product.ID), //#nosec G115 -- DB ID is always positive
-				Name:        product.Name,
-				Description: product.Description.String,
-				Price:       priceFloat.Float64,
-				Stock:       int(product.Stock.Int32),
-				CategoryID:  uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
-				SKU:         product.Sku,
-				IsActive:    product.IsActive.Bool,
+				ID:             uint(product.ID), //#nosec G115 -- DB ID is always positive
+				Name:           product.Name,
+				Description:    product.Description.String,
+				Stock:          int(product.Stock.Int32),
+				AvailableStock: availableStock(product.Stock, reserved[product.ID]),
+				CategoryID:     uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
+				SKU:            product.Sku,
+				IsActive:       product.IsActive.Bool,
 				Category: dto.CategoryResponse{
 					ID:          int64(category.ID),
 					Name:        category.Name,
@@ -318,7 +371,18 @@ func (s *CartService) buildCartResponse(ctx context.Context, cart *db.Cart) (*dt
 				},
 			},
 			Quantity: int(item.Quantity),
-			Subtotal: subtotal,
+		}
+		if err := prices.apply(&cartItems[i].Product, product); err != nil {
+			return nil, err
+		}
+
+		subtotal, err := cartItems[i].Product.Price.Mul(int64(item.Quantity))
+		if err != nil {
+			return nil, fmt.Errorf("failed to total cart item %d: %w", item.ID, err)
+		}
+		cartItems[i].Subtotal = subtotal
+		if totalPrice, err = totalPrice.Add(subtotal); err != nil {
+			return nil, fmt.Errorf("failed to total cart: %w", err)
 		}
 	}
 
@@ -327,5 +391,6 @@ func (s *CartService) buildCartResponse(ctx context.Context, cart *db.Cart) (*dt
 		UserID:    uint(cart.UserID), //#nosec G115 -- DB ID is always positive
 		CartItems: cartItems,
 		Total:     totalPrice,
+		Currency:  quote.Currency,
 	}, nil
 }
@@ -17,6 +17,7 @@ import (
 // MockCartStore provides mocked store methods for CartService testing
 type MockCartStore struct {
 	mock.Mock
+	noopStore
 }
 
 // Cart methods
@@ -80,9 +81,14 @@ func (m *MockCartStore) ListProductImagesByProductIDs(ctx context.Context, ids [
 	return args.Get(0).([]db.ProductImage), args.Error(1)
 }
 
-func (m *MockCartStore) ExecTx(ctx context.Context, fn func(*db.Queries) error) error {
+// ExecTx records the transaction and, unless it is mocked to fail, runs fn
+// against the mock, so the queries made inside it are checked too
+func (m *MockCartStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
 	args := m.Called(ctx, fn)
-	return args.Error(0)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
 }
 
 // Helper to create test cart
@@ -156,7 +162,7 @@ func TestCartService_GetCart(t *testing.T) {
 
 			service := &CartService{store: createCartStoreWrapper(mockStore)}
 
-			resp, err := service.GetCart(context.Background(), tt.userID)
+			resp, err := service.GetCart(context.Background(), tt.userID, "")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -252,7 +258,7 @@ func TestCartService_AddToCart(t *testing.T) {
 
 			service := &CartService{store: createCartStoreWrapper(mockStore)}
 
-			resp, err := service.AddToCart(context.Background(), tt.userID, tt.req)
+			resp, err := service.AddToCart(context.Background(), tt.userID, tt.req, "")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -349,7 +355,7 @@ func TestCartService_UpdateCartItem(t *testing.T) {
 
 			service := &CartService{store: createCartStoreWrapper(mockStore)}
 
-			resp, err := service.UpdateCartItem(context.Background(), tt.userID, tt.itemID, tt.req)
+			resp, err := service.UpdateCartItem(context.Background(), tt.userID, tt.itemID, tt.req, "")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -412,7 +418,7 @@ func TestCartService_RemoveCartItem(t *testing.T) {
 
 			service := &CartService{store: createCartStoreWrapper(mockStore)}
 
-			resp, err := service.RemoveCartItem(context.Background(), tt.userID, tt.itemID)
+			resp, err := service.RemoveCartItem(context.Background(), tt.userID, tt.itemID, "")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -478,7 +484,7 @@ func TestCartService_ClearCart(t *testing.T) {
 	}
 }
 
-// cartStoreWrapper wraps MockCartStore to implement db.Store interface
+// cartStoreWrapper wraps MockCartStore as a db.Store; queries it doesn't mock fall through to noopStore
 type cartStoreWrapper struct {
 	*MockCartStore
 }
@@ -486,208 +492,3 @@ type cartStoreWrapper struct {
 func createCartStoreWrapper(m *MockCartStore) db.Store {
 	return &cartStoreWrapper{MockCartStore: m}
 }
-
-// Implement remaining db.Store methods as stubs
-func (s *cartStoreWrapper) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *cartStoreWrapper) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *cartStoreWrapper) GetUserByID(ctx context.Context, id int32) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *cartStoreWrapper) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *cartStoreWrapper) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) error {
-	return nil
-}
-func (s *cartStoreWrapper) UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *cartStoreWrapper) UpdateUserStatus(ctx context.Context, arg db.UpdateUserStatusParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *cartStoreWrapper) SoftDeleteUser(ctx context.Context, id int32) error { return nil }
-func (s *cartStoreWrapper) ListUsers(ctx context.Context, arg db.ListUsersParams) ([]db.User, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) CountUsers(ctx context.Context) (int64, error) { return 0, nil }
-func (s *cartStoreWrapper) CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) (db.RefreshToken, error) {
-	return db.RefreshToken{}, nil
-}
-func (s *cartStoreWrapper) GetRefreshToken(ctx context.Context, token string) (db.RefreshToken, error) {
-	return db.RefreshToken{}, nil
-}
-func (s *cartStoreWrapper) DeleteRefreshToken(ctx context.Context, token string) error { return nil }
-func (s *cartStoreWrapper) DeleteRefreshTokensByUserID(ctx context.Context, userID int32) error {
-	return nil
-}
-func (s *cartStoreWrapper) DeleteExpiredRefreshTokens(ctx context.Context) error { return nil }
-func (s *cartStoreWrapper) GetRefreshTokensByUserID(ctx context.Context, userID int32) ([]db.RefreshToken, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) GetCartByID(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *cartStoreWrapper) UpdateCartTimestamp(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *cartStoreWrapper) SoftDeleteCart(ctx context.Context, id int32) error { return nil }
-func (s *cartStoreWrapper) SoftDeleteCartByUserID(ctx context.Context, userID int32) error {
-	return nil
-}
-func (s *cartStoreWrapper) CreateCartItem(ctx context.Context, arg db.CreateCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *cartStoreWrapper) GetCartItem(ctx context.Context, arg db.GetCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *cartStoreWrapper) RestoreCartItem(ctx context.Context, arg db.RestoreCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *cartStoreWrapper) CountCartItems(ctx context.Context, cartID int32) (int64, error) {
-	return 0, nil
-}
-func (s *cartStoreWrapper) CreateCategory(ctx context.Context, arg db.CreateCategoryParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *cartStoreWrapper) GetCategoryByID(ctx context.Context, id int32) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *cartStoreWrapper) ListCategories(ctx context.Context, arg db.ListCategoriesParams) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) ListActiveCategories(ctx context.Context) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) UpdateCategory(ctx context.Context, arg db.UpdateCategoryParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *cartStoreWrapper) UpdateCategoryStatus(ctx context.Context, arg db.UpdateCategoryStatusParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *cartStoreWrapper) SoftDeleteCategory(ctx context.Context, id int32) error { return nil }
-func (s *cartStoreWrapper) CountCategories(ctx context.Context) (int64, error)     { return 0, nil }
-func (s *cartStoreWrapper) CreateProduct(ctx context.Context, arg db.CreateProductParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *cartStoreWrapper) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *cartStoreWrapper) GetProductBySKU(ctx context.Context, sku string) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *cartStoreWrapper) GetProductsByIDsForUpdate(ctx context.Context, ids []int32) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) ListProducts(ctx context.Context, arg db.ListProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) ListActiveProducts(ctx context.Context, arg db.ListActiveProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) ListProductsByCategory(ctx context.Context, arg db.ListProductsByCategoryParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) UpdateProduct(ctx context.Context, arg db.UpdateProductParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *cartStoreWrapper) UpdateProductStatus(ctx context.Context, arg db.UpdateProductStatusParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *cartStoreWrapper) UpdateProductStock(ctx context.Context, arg db.UpdateProductStockParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *cartStoreWrapper) SoftDeleteProduct(ctx context.Context, id int32) error  { return nil }
-func (s *cartStoreWrapper) CountProducts(ctx context.Context) (int64, error)       { return 0, nil }
-func (s *cartStoreWrapper) CountActiveProducts(ctx context.Context) (int64, error) { return 0, nil }
-func (s *cartStoreWrapper) CountProductsByCategory(ctx context.Context, categoryID int32) (int64, error) {
-	return 0, nil
-}
-func (s *cartStoreWrapper) CreateProductImage(ctx context.Context, arg db.CreateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *cartStoreWrapper) GetProductImageByID(ctx context.Context, id int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *cartStoreWrapper) GetPrimaryProductImage(ctx context.Context, productID int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *cartStoreWrapper) ListProductImages(ctx context.Context, productID int32) ([]db.ProductImage, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) UpdateProductImage(ctx context.Context, arg db.UpdateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *cartStoreWrapper) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) error {
-	return nil
-}
-func (s *cartStoreWrapper) SoftDeleteProductImage(ctx context.Context, id int32) error { return nil }
-func (s *cartStoreWrapper) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) error {
-	return nil
-}
-func (s *cartStoreWrapper) CreateOrder(ctx context.Context, arg db.CreateOrderParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *cartStoreWrapper) GetOrderByID(ctx context.Context, id int32) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *cartStoreWrapper) ListOrders(ctx context.Context, arg db.ListOrdersParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) ListOrdersByUserID(ctx context.Context, arg db.ListOrdersByUserIDParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) ListOrdersByStatus(ctx context.Context, arg db.ListOrdersByStatusParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) UpdateOrderStatus(ctx context.Context, arg db.UpdateOrderStatusParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *cartStoreWrapper) UpdateOrderTotal(ctx context.Context, arg db.UpdateOrderTotalParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *cartStoreWrapper) SoftDeleteOrder(ctx context.Context, id int32) error { return nil }
-func (s *cartStoreWrapper) CountOrders(ctx context.Context) (int64, error)      { return 0, nil }
-func (s *cartStoreWrapper) CountOrdersByUserID(ctx context.Context, userID int32) (int64, error) {
-	return 0, nil
-}
-func (s *cartStoreWrapper) CountOrdersByStatus(ctx context.Context, status db.NullOrderStatus) (int64, error) {
-	return 0, nil
-}
-func (s *cartStoreWrapper) GetOrderTotal(ctx context.Context, orderID int32) (pgtype.Numeric, error) {
-	return pgtype.Numeric{}, nil
-}
-func (s *cartStoreWrapper) CreateOrderItem(ctx context.Context, arg db.CreateOrderItemParams) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *cartStoreWrapper) GetOrderItemByID(ctx context.Context, id int32) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *cartStoreWrapper) ListOrderItems(ctx context.Context, orderID int32) ([]db.OrderItem, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) SoftDeleteOrderItem(ctx context.Context, id int32) error { return nil }
-func (s *cartStoreWrapper) SoftDeleteOrderItemsByOrderID(ctx context.Context, orderID int32) error {
-	return nil
-}
-func (s *cartStoreWrapper) CountOrderItems(ctx context.Context, orderID int32) (int64, error) {
-	return 0, nil
-}
-func (s *cartStoreWrapper) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *cartStoreWrapper) GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *cartStoreWrapper) UpdateIdempotencyKeyOrderID(ctx context.Context, arg db.UpdateIdempotencyKeyOrderIDParams) error {
-	return nil
-}
-func (s *cartStoreWrapper) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
-	return nil, nil
-}
-func (s *cartStoreWrapper) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
-	return 0, nil
-}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const (
+	defaultCategorySuggestions = 3
+	defaultCategoryMismatches  = 50
+	defaultMismatchConfidence  = 0.7
+)
+
+var ErrCategorizerUntrained = errors.New("at least two categories need products before categories can be suggested")
+
+// CategorizationService suggests categories for products from what the
+// products already in each category are called. Its CategoryClassifier is
+// trained on every categorized product and retrained by Train, which
+// RunCategoryClassifierTrainer calls on a schedule.
+type CategorizationService struct {
+	store         db.Store
+	minConfidence float64 // default confidence a mismatch needs to be flagged
+
+	mu         sync.RWMutex
+	classifier *CategoryClassifier
+}
+
+func NewCategorizationService(store db.Store, minConfidence float64) *CategorizationService {
+	if minConfidence <= 0 || minConfidence > 1 {
+		minConfidence = defaultMismatchConfidence
+	}
+	return &CategorizationService{
+		store:         store,
+		minConfidence: minConfidence,
+	}
+}
+
+// Train rebuilds the classifier from the catalog and returns how many
+// products it learned from
+func (s *CategorizationService) Train(ctx context.Context) (int, error) {
+	products, err := s.store.ListCategoryTrainingProducts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list categorized products: %w", err)
+	}
+	s.setClassifier(trainCategoryClassifier(products))
+	return len(products), nil
+}
+
+// SuggestCategory ranks the categories a new product could go in, most
+// likely first
+func (s *CategorizationService) SuggestCategory(ctx context.Context, req dto.SuggestCategoryRequest) ([]dto.CategorySuggestion, error) {
+	classifier, err := s.trainedClassifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultCategorySuggestions
+	}
+
+	scores := classifier.Classify(productWords(req.Name, req.Description))
+	suggestions := make([]dto.CategorySuggestion, 0, min(limit, len(scores)))
+	for _, score := range scores[:min(limit, len(scores))] {
+		suggestions = append(suggestions, toCategorySuggestion(score))
+	}
+	return suggestions, nil
+}
+
+// FindCategoryMismatches lists products the classifier would file under
+// another category with at least the minimum confidence, most confident
+// first. Each product is judged by a model that hasn't seen it, so its own
+// words don't vouch for the category it is in.
+func (s *CategorizationService) FindCategoryMismatches(ctx context.Context, req dto.CategoryMismatchRequest) ([]dto.CategoryMismatch, error) {
+	products, err := s.store.ListCategoryTrainingProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categorized products: %w", err)
+	}
+	classifier := trainCategoryClassifier(products)
+	s.setClassifier(classifier)
+	if classifier.Categories() < 2 {
+		return nil, ErrCategorizerUntrained
+	}
+
+	minConfidence := req.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = s.minConfidence
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultCategoryMismatches
+	}
+
+	mismatches := []dto.CategoryMismatch{}
+	for _, product := range products {
+		scores := classifier.ClassifyExcluding(productWords(product.Name, product.Description.String), product.CategoryID)
+		if len(scores) == 0 {
+			continue
+		}
+		top := scores[0]
+		if top.CategoryID == product.CategoryID || top.Probability < minConfidence {
+			continue
+		}
+
+		current := CategoryScore{CategoryID: product.CategoryID, CategoryName: product.CategoryName}
+		for _, score := range scores {
+			if score.CategoryID == product.CategoryID {
+				current = score
+				break
+			}
+		}
+		mismatches = append(mismatches, dto.CategoryMismatch{
+			ProductID:   uint(product.ID), //#nosec G115 -- DB ID is always positive
+			ProductName: product.Name,
+			SKU:         product.Sku,
+			Current:     toCategorySuggestion(current),
+			Suggested:   toCategorySuggestion(top),
+		})
+	}
+
+	sort.SliceStable(mismatches, func(i, j int) bool {
+		return mismatches[i].Suggested.Confidence > mismatches[j].Suggested.Confidence
+	})
+	if len(mismatches) > limit {
+		mismatches = mismatches[:limit]
+	}
+	return mismatches, nil
+}
+
+// trainedClassifier returns the current classifier, training the first one
+// if the scheduled trainer hasn't yet
+func (s *CategorizationService) trainedClassifier(ctx context.Context) (*CategoryClassifier, error) {
+	s.mu.RLock()
+	classifier := s.classifier
+	s.mu.RUnlock()
+
+	if classifier == nil {
+		if _, err := s.Train(ctx); err != nil {
+			return nil, err
+		}
+		s.mu.RLock()
+		classifier = s.classifier
+		s.mu.RUnlock()
+	}
+	if classifier.Categories() < 2 {
+		return nil, ErrCategorizerUntrained
+	}
+	return classifier, nil
+}
+
+func (s *CategorizationService) setClassifier(classifier *CategoryClassifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.classifier = classifier
+}
+
+func trainCategoryClassifier(products []db.ListCategoryTrainingProductsRow) *CategoryClassifier {
+	classifier := NewCategoryClassifier()
+	for _, product := range products {
+		classifier.Add(product.CategoryID, product.CategoryName, productWords(product.Name, product.Description.String))
+	}
+	return classifier
+}
+
+func toCategorySuggestion(score CategoryScore) dto.CategorySuggestion {
+	return dto.CategorySuggestion{
+		CategoryID:   uint(score.CategoryID), //#nosec G115 -- DB ID is always positive
+		CategoryName: score.CategoryName,
+		Confidence:   score.Probability,
+	}
+}
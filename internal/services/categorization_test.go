@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+// categorizationStoreWrapper serves a fixed catalog to train on and counts
+// how often it was read
+type categorizationStoreWrapper struct {
+	*productStoreWrapper
+	products []db.ListCategoryTrainingProductsRow
+	loads    int
+}
+
+func (s *categorizationStoreWrapper) ListCategoryTrainingProducts(ctx context.Context) ([]db.ListCategoryTrainingProductsRow, error) {
+	s.loads++
+	return s.products, nil
+}
+
+func trainingProduct(id int32, name, description string, categoryID int32, categoryName string) db.ListCategoryTrainingProductsRow {
+	return db.ListCategoryTrainingProductsRow{
+		ID:           id,
+		Name:         name,
+		Description:  pgtype.Text{String: description, Valid: description != ""},
+		Sku:          "SKU-" + name,
+		CategoryID:   categoryID,
+		CategoryName: categoryName,
+	}
+}
+
+func newCategorizationStore(products ...db.ListCategoryTrainingProductsRow) *categorizationStoreWrapper {
+	return &categorizationStoreWrapper{
+		productStoreWrapper: &productStoreWrapper{MockProductStore: new(MockProductStore)},
+		products:            products,
+	}
+}
+
+var testCatalog = []db.ListCategoryTrainingProductsRow{
+	trainingProduct(1, "Trail Running Shoes", "Lightweight shoes with a grippy sole", 1, "Shoes"),
+	trainingProduct(2, "Leather Hiking Boots", "Waterproof boots with a sturdy sole", 1, "Shoes"),
+	trainingProduct(3, "Canvas Sneakers", "Everyday shoes with a rubber sole", 1, "Shoes"),
+	trainingProduct(4, "Chef Knife", "Stainless steel kitchen knife", 2, "Kitchen"),
+	trainingProduct(5, "Cast Iron Skillet", "Pre-seasoned pan for the kitchen", 2, "Kitchen"),
+	trainingProduct(6, "Nonstick Frying Pan", "Kitchen pan with a glass lid", 2, "Kitchen"),
+	trainingProduct(7, "Paring Knife", "Small steel kitchen knife", 1, "Shoes"), // filed in the wrong category
+	trainingProduct(8, "Bread Knife", "Serrated steel knife", 2, "Kitchen"),
+	trainingProduct(9, "Santoku Knife", "Japanese kitchen knife", 2, "Kitchen"),
+}
+
+func TestCategoryClassifier_Classify(t *testing.T) {
+	t.Parallel()
+
+	classifier := NewCategoryClassifier()
+	classifier.Add(1, "A", map[string]int{"boot": 2})
+	classifier.Add(2, "B", map[string]int{"knife": 2})
+
+	// Equal priors; P(boot|A) = (2+1)/(2+2) and P(boot|B) = (0+1)/(2+2)
+	scores := classifier.Classify(map[string]int{"boot": 1, "unseen": 1})
+	require.Len(t, scores, 2)
+	assert.Equal(t, int32(1), scores[0].CategoryID)
+	assert.InDelta(t, 0.75, scores[0].Probability, 1e-9)
+	assert.InDelta(t, 0.25, scores[1].Probability, 1e-9)
+
+	// With its only product left out, A has nothing to score with
+	scores = classifier.ClassifyExcluding(map[string]int{"boot": 2}, 1)
+	require.Len(t, scores, 1)
+	assert.Equal(t, int32(2), scores[0].CategoryID)
+	assert.InDelta(t, 1.0, scores[0].Probability, 1e-9)
+}
+
+func TestProductWords(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, map[string]int{"hiking": 2, "boot": 3, "glass": 1, "waterproof": 1},
+		productWords("The Hiking Boots", "Waterproof boots, with glass."))
+}
+
+func TestCategorizationService_SuggestCategory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("categories ranked by confidence", func(t *testing.T) {
+		t.Parallel()
+
+		store := newCategorizationStore(testCatalog[:6]...)
+		service := NewCategorizationService(store, 0)
+
+		suggestions, err := service.SuggestCategory(context.Background(), dto.SuggestCategoryRequest{
+			Name:        "Running Sneakers",
+			Description: "Breathable shoes with a cushioned sole",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, suggestions, 2)
+		assert.Equal(t, "Shoes", suggestions[0].CategoryName)
+		assert.Equal(t, uint(1), suggestions[0].CategoryID)
+		assert.Greater(t, suggestions[0].Confidence, 0.95)
+		assert.InDelta(t, 1.0, suggestions[0].Confidence+suggestions[1].Confidence, 1e-9)
+
+		suggestions, err = service.SuggestCategory(context.Background(), dto.SuggestCategoryRequest{Name: "Bread Knife", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, "Kitchen", suggestions[0].CategoryName)
+
+		// Trained once, on first use
+		assert.Equal(t, 1, store.loads)
+	})
+
+	t.Run("one category is not enough", func(t *testing.T) {
+		t.Parallel()
+
+		service := NewCategorizationService(newCategorizationStore(testCatalog[:3]...), 0)
+
+		_, err := service.SuggestCategory(context.Background(), dto.SuggestCategoryRequest{Name: "Sandals"})
+		assert.True(t, errors.Is(err, ErrCategorizerUntrained))
+	})
+}
+
+func TestCategorizationService_FindCategoryMismatches(t *testing.T) {
+	t.Parallel()
+
+	service := NewCategorizationService(newCategorizationStore(testCatalog...), 0.7)
+
+	mismatches, err := service.FindCategoryMismatches(context.Background(), dto.CategoryMismatchRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, mismatches, 1)
+	mismatch := mismatches[0]
+	assert.Equal(t, uint(7), mismatch.ProductID)
+	assert.Equal(t, "Paring Knife", mismatch.ProductName)
+	assert.Equal(t, uint(1), mismatch.Current.CategoryID)
+	assert.Equal(t, "Kitchen", mismatch.Suggested.CategoryName)
+	assert.GreaterOrEqual(t, mismatch.Suggested.Confidence, 0.7)
+	assert.InDelta(t, 1.0, mismatch.Current.Confidence+mismatch.Suggested.Confidence, 1e-9)
+
+	// A higher bar than the product reaches flags nothing
+	mismatches, err = service.FindCategoryMismatches(context.Background(), dto.CategoryMismatchRequest{MinConfidence: 0.999999})
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// nameWeight is how many times a product's name words count against its
+// description words; names say more about what a product is
+const nameWeight = 2
+
+// classifierStopWords are words too common in product copy to tell
+// categories apart
+var classifierStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "its": true, "new": true, "of": true,
+	"on": true, "or": true, "our": true, "the": true, "this": true, "to": true, "with": true, "you": true,
+	"your": true,
+}
+
+// categoryClass is what the classifier learned about one category
+type categoryClass struct {
+	id       int32
+	name     string
+	products int            // training products filed under the category
+	words    map[string]int // word counts over those products
+	total    int            // sum of words
+}
+
+// CategoryClassifier is a multinomial naive Bayes model of which words the
+// products of each category use. It scores a product by how likely its
+// words are under each category, with Laplace smoothing so one unseen word
+// doesn't rule a category out.
+type CategoryClassifier struct {
+	classes    []*categoryClass
+	byID       map[int32]*categoryClass
+	vocabulary map[string]bool
+	products   int
+}
+
+// CategoryScore is a category and how likely a product belongs to it
+type CategoryScore struct {
+	CategoryID   int32
+	CategoryName string
+	Probability  float64
+}
+
+func NewCategoryClassifier() *CategoryClassifier {
+	return &CategoryClassifier{
+		byID:       map[int32]*categoryClass{},
+		vocabulary: map[string]bool{},
+	}
+}
+
+// Add learns from one product filed under a category
+func (c *CategoryClassifier) Add(categoryID int32, categoryName string, words map[string]int) {
+	class, ok := c.byID[categoryID]
+	if !ok {
+		class = &categoryClass{id: categoryID, name: categoryName, words: map[string]int{}}
+		c.byID[categoryID] = class
+		c.classes = append(c.classes, class)
+	}
+	class.products++
+	c.products++
+	for word, n := range words {
+		class.words[word] += n
+		class.total += n
+		c.vocabulary[word] = true
+	}
+}
+
+// Categories is how many categories the classifier has products for
+func (c *CategoryClassifier) Categories() int {
+	return len(c.classes)
+}
+
+// Classify returns every category's probability for a product's words,
+// most likely first. Ties go to the lower category ID.
+func (c *CategoryClassifier) Classify(words map[string]int) []CategoryScore {
+	return c.classify(words, 0)
+}
+
+// ClassifyExcluding classifies words that were added for a product in
+// excludeCategory as if that product had never been added, so a product's
+// own words don't vouch for the category it is already in
+func (c *CategoryClassifier) ClassifyExcluding(words map[string]int, excludeCategory int32) []CategoryScore {
+	return c.classify(words, excludeCategory)
+}
+
+// classify scores words against every category, leaving out one product of
+// the exclude category when it isn't 0
+func (c *CategoryClassifier) classify(words map[string]int, exclude int32) []CategoryScore {
+	if len(c.classes) == 0 {
+		return nil
+	}
+
+	vocabulary := float64(len(c.vocabulary))
+	products := c.products
+	if exclude != 0 {
+		products--
+	}
+
+	logScores := make([]float64, len(c.classes))
+	for i, class := range c.classes {
+		classProducts, classTotal := class.products, class.total
+		if class.id == exclude {
+			classProducts--
+			for _, n := range words {
+				classTotal -= n
+			}
+		}
+		if classProducts <= 0 {
+			logScores[i] = math.Inf(-1)
+			continue
+		}
+
+		score := math.Log(float64(classProducts) / float64(products))
+		for word, n := range words {
+			if !c.vocabulary[word] {
+				continue
+			}
+			count := class.words[word]
+			if class.id == exclude {
+				count -= n
+			}
+			score += float64(n) * math.Log((float64(count)+1)/(float64(classTotal)+vocabulary))
+		}
+		logScores[i] = score
+	}
+
+	// Softmax turns the log scores into probabilities that add up to 1.
+	// Categories left with no products have no score.
+	top := math.Inf(-1)
+	for _, score := range logScores {
+		top = math.Max(top, score)
+	}
+	if math.IsInf(top, -1) {
+		return nil
+	}
+	likelihoods := make([]float64, len(logScores))
+	var sum float64
+	for i, score := range logScores {
+		likelihoods[i] = math.Exp(score - top)
+		sum += likelihoods[i]
+	}
+
+	scores := make([]CategoryScore, 0, len(c.classes))
+	for i, class := range c.classes {
+		if math.IsInf(logScores[i], -1) {
+			continue
+		}
+		scores = append(scores, CategoryScore{
+			CategoryID:   class.id,
+			CategoryName: class.name,
+			Probability:  likelihoods[i] / sum,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Probability != scores[j].Probability {
+			return scores[i].Probability > scores[j].Probability
+		}
+		return scores[i].CategoryID < scores[j].CategoryID
+	})
+	return scores
+}
+
+// productWords counts the words of a product's name and description that
+// the classifier learns from, with name words counting nameWeight times.
+// Plurals are folded into the singular, so "boots" and "boot" are one word.
+func productWords(name, description string) map[string]int {
+	words := map[string]int{}
+	for _, word := range classifierWords(name) {
+		words[word] += nameWeight
+	}
+	for _, word := range classifierWords(description) {
+		words[word]++
+	}
+	return words
+}
+
+func classifierWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len(field) < 2 || classifierStopWords[field] {
+			continue
+		}
+		words = append(words, singular(field))
+	}
+	return words
+}
+
+// singular strips a plural "s" from words long enough to have one, leaving
+// words like "glass" and "dress" alone
+func singular(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return word[:len(word)-1]
+	}
+	return word
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+var (
+	ErrContentDraftNotFound = errors.New("content draft not found")
+	ErrContentDraftReviewed = errors.New("content draft has already been reviewed")
+)
+
+// ContentService drafts product descriptions, SEO titles and tags with a
+// ContentGenerator. Drafts are stored for an admin to review; accepting one
+// applies it to the product through ProductService.UpdateProductByID.
+type ContentService struct {
+	store     db.Store
+	products  *ProductService
+	generator interfaces.ContentGenerator
+}
+
+func NewContentService(store db.Store, products *ProductService, generator interfaces.ContentGenerator) *ContentService {
+	return &ContentService{
+		store:     store,
+		products:  products,
+		generator: generator,
+	}
+}
+
+// GenerateDraft writes a draft from the product's name, category, attributes
+// and image alt text. The product itself is left as it is.
+func (s *ContentService) GenerateDraft(ctx context.Context, actorID int32, productID uint) (*dto.ContentDraftResponse, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.store.GetCategoryByID(ctx, product.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	attributes, err := productAttributes(ctx, s.store, []int32{product.ID})
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.store.ListProductImages(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product images: %w", err)
+	}
+	altTexts := make([]string, 0, len(images))
+	for _, image := range images {
+		if image.AltText.String != "" {
+			altTexts = append(altTexts, image.AltText.String)
+		}
+	}
+
+	content, err := s.generator.Generate(ctx, dto.ContentInput{
+		Name:          product.Name,
+		Category:      category.Name,
+		Attributes:    attributes[product.ID],
+		ImageAltTexts: altTexts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate product content: %w", err)
+	}
+
+	draft, err := s.store.CreateProductContentDraft(ctx, db.CreateProductContentDraftParams{
+		ProductID:   product.ID,
+		Description: content.Description,
+		SeoTitle:    content.SEOTitle,
+		Tags:        normalizeTags(content.Tags),
+		Generator:   s.generator.Name(),
+		CreatedBy:   pgtype.Int4{Int32: actorID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create content draft: %w", err)
+	}
+	return toContentDraftResponse(draft), nil
+}
+
+// ListDrafts returns a product's drafts, newest first
+func (s *ContentService) ListDrafts(ctx context.Context, productID uint) ([]dto.ContentDraftResponse, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	drafts, err := s.store.ListProductContentDrafts(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content drafts: %w", err)
+	}
+
+	responses := make([]dto.ContentDraftResponse, len(drafts))
+	for i, draft := range drafts {
+		responses[i] = *toContentDraftResponse(draft)
+	}
+	return responses, nil
+}
+
+// AcceptDraft applies a pending draft's description, SEO title and tags to
+// its product, keeping everything else about the product as it is, and
+// returns the updated product
+func (s *ContentService) AcceptDraft(ctx context.Context, actorID int32, productID, draftID uint) (*dto.ProductResponse, error) {
+	draft, err := s.getPendingDraft(ctx, productID, draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	price, err := money.FromNumeric(product.Price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read product price: %w", err)
+	}
+
+	updated, err := s.products.UpdateProductByID(ctx, actorID, productID, &dto.UpdateProductRequest{
+		Name:        product.Name,
+		Description: draft.Description,
+		Price:       price,
+		CategoryID:  int64(product.CategoryID),
+		SEOTitle:    &draft.SeoTitle,
+		Tags:        draft.Tags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply content draft: %w", err)
+	}
+
+	if _, err := s.review(ctx, actorID, draft.ID, db.ContentDraftStatusAccepted); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RejectDraft marks a pending draft rejected, leaving the product alone
+func (s *ContentService) RejectDraft(ctx context.Context, actorID int32, productID, draftID uint) (*dto.ContentDraftResponse, error) {
+	draft, err := s.getPendingDraft(ctx, productID, draftID)
+	if err != nil {
+		return nil, err
+	}
+	return s.review(ctx, actorID, draft.ID, db.ContentDraftStatusRejected)
+}
+
+func (s *ContentService) getProduct(ctx context.Context, productID uint) (db.Product, error) {
+	product, err := s.store.GetProductByID(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Product{}, ErrProductNotFound
+		}
+		return db.Product{}, fmt.Errorf("failed to get product: %w", err)
+	}
+	return product, nil
+}
+
+// getPendingDraft returns a draft of the product that hasn't been reviewed
+func (s *ContentService) getPendingDraft(ctx context.Context, productID, draftID uint) (db.ProductContentDraft, error) {
+	draft, err := s.store.GetProductContentDraft(ctx, int32(draftID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.ProductContentDraft{}, ErrContentDraftNotFound
+		}
+		return db.ProductContentDraft{}, fmt.Errorf("failed to get content draft: %w", err)
+	}
+	if uint(draft.ProductID) != productID { //#nosec G115 -- DB ID is always positive
+		return db.ProductContentDraft{}, ErrContentDraftNotFound
+	}
+	if draft.Status != db.ContentDraftStatusPending {
+		return db.ProductContentDraft{}, ErrContentDraftReviewed
+	}
+	return draft, nil
+}
+
+func (s *ContentService) review(ctx context.Context, actorID, draftID int32, status db.ContentDraftStatus) (*dto.ContentDraftResponse, error) {
+	draft, err := s.store.ReviewProductContentDraft(ctx, db.ReviewProductContentDraftParams{
+		ID:         draftID,
+		Status:     status,
+		ReviewedBy: pgtype.Int4{Int32: actorID, Valid: true},
+	})
+	if err != nil {
+		// Another admin reviewed it first
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrContentDraftReviewed
+		}
+		return nil, fmt.Errorf("failed to review content draft: %w", err)
+	}
+	return toContentDraftResponse(draft), nil
+}
+
+func toContentDraftResponse(draft db.ProductContentDraft) *dto.ContentDraftResponse {
+	resp := &dto.ContentDraftResponse{
+		ID:          uint(draft.ID),        //#nosec G115 -- DB ID is always positive
+		ProductID:   uint(draft.ProductID), //#nosec G115 -- DB ID is always positive
+		Description: draft.Description,
+		SEOTitle:    draft.SeoTitle,
+		Tags:        draft.Tags,
+		Generator:   draft.Generator,
+		Status:      string(draft.Status),
+		CreatedAt:   draft.CreatedAt.Time,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if draft.CreatedBy.Valid {
+		createdBy := uint(draft.CreatedBy.Int32) //#nosec G115 -- DB ID is always positive
+		resp.CreatedBy = &createdBy
+	}
+	if draft.ReviewedBy.Valid {
+		reviewedBy := uint(draft.ReviewedBy.Int32) //#nosec G115 -- DB ID is always positive
+		resp.ReviewedBy = &reviewedBy
+	}
+	if draft.ReviewedAt.Valid {
+		resp.ReviewedAt = &draft.ReviewedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+)
+
+// contentStoreWrapper keeps content drafts in memory and records product
+// content updates
+type contentStoreWrapper struct {
+	*productStoreWrapper
+	attributes     []db.ProductAttribute
+	drafts         map[int32]db.ProductContentDraft
+	created        []db.CreateProductContentDraftParams
+	reviews        []db.ReviewProductContentDraftParams
+	contentUpdates []db.UpdateProductContentParams
+}
+
+func newContentStoreWrapper(mockStore *MockProductStore) *contentStoreWrapper {
+	return &contentStoreWrapper{
+		productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore},
+		drafts:              map[int32]db.ProductContentDraft{},
+	}
+}
+
+func (s *contentStoreWrapper) ListProductAttributesByProductIDs(ctx context.Context, productIds []int32) ([]db.ProductAttribute, error) {
+	return s.attributes, nil
+}
+
+func (s *contentStoreWrapper) CreateProductContentDraft(ctx context.Context, arg db.CreateProductContentDraftParams) (db.ProductContentDraft, error) {
+	s.created = append(s.created, arg)
+	return db.ProductContentDraft{
+		ID:          int32(len(s.created)), //#nosec G115 -- test IDs are small
+		ProductID:   arg.ProductID,
+		Description: arg.Description,
+		SeoTitle:    arg.SeoTitle,
+		Tags:        arg.Tags,
+		Generator:   arg.Generator,
+		Status:      db.ContentDraftStatusPending,
+		CreatedBy:   arg.CreatedBy,
+	}, nil
+}
+
+func (s *contentStoreWrapper) GetProductContentDraft(ctx context.Context, id int32) (db.ProductContentDraft, error) {
+	draft, ok := s.drafts[id]
+	if !ok {
+		return db.ProductContentDraft{}, pgx.ErrNoRows
+	}
+	return draft, nil
+}
+
+func (s *contentStoreWrapper) ReviewProductContentDraft(ctx context.Context, arg db.ReviewProductContentDraftParams) (db.ProductContentDraft, error) {
+	s.reviews = append(s.reviews, arg)
+	draft := s.drafts[arg.ID]
+	draft.Status = arg.Status
+	draft.ReviewedBy = arg.ReviewedBy
+	return draft, nil
+}
+
+// ExecTx runs fn against the wrapper, so product updates made in a
+// transaction are recorded too
+func (s *contentStoreWrapper) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
+	return fn(s)
+}
+
+func (s *contentStoreWrapper) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
+	return s.GetProductByID(ctx, id)
+}
+
+func (s *contentStoreWrapper) UpdateProductContent(ctx context.Context, arg db.UpdateProductContentParams) error {
+	s.contentUpdates = append(s.contentUpdates, arg)
+	return nil
+}
+
+func TestContentService_GenerateDraft(t *testing.T) {
+	t.Parallel()
+
+	t.Run("draft stored without changing the product", func(t *testing.T) {
+		t.Parallel()
+
+		product := createTestProduct()
+		product.Name = "Trail Runner Shoe"
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(product, nil)
+		mockStore.On("GetCategoryByID", mock.Anything, int32(1)).Return(db.Category{ID: 1, Name: "Shoes"}, nil)
+		mockStore.On("ListProductImages", mock.Anything, int32(1)).Return([]db.ProductImage{
+			{ID: 1, ProductID: 1, AltText: pgtype.Text{String: "Side view of the red shoe", Valid: true}},
+			{ID: 2, ProductID: 1},
+		}, nil)
+
+		store := newContentStoreWrapper(mockStore)
+		store.attributes = []db.ProductAttribute{
+			{ProductID: 1, Name: "color", Value: "Red"},
+			{ProductID: 1, Name: "color", Value: "Blue"},
+			{ProductID: 1, Name: "size", Value: "10"},
+		}
+		service := NewContentService(store, &ProductService{store: store}, providers.NewTemplateContentGenerator())
+
+		draft, err := service.GenerateDraft(context.Background(), 9, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, "The Trail Runner Shoe is part of our Shoes range. Available in color Red or Blue and size 10. Pictured: Side view of the red shoe.", draft.Description)
+		assert.Equal(t, "Trail Runner Shoe | Shoes", draft.SEOTitle)
+		assert.Equal(t, []string{"shoes", "red", "blue", "10", "trail", "runner", "shoe"}, draft.Tags)
+		assert.Equal(t, "template", draft.Generator)
+		assert.Equal(t, "pending", draft.Status)
+		require.NotNil(t, draft.CreatedBy)
+		assert.Equal(t, uint(9), *draft.CreatedBy)
+
+		require.Len(t, store.created, 1)
+		assert.Empty(t, store.contentUpdates)
+		mockStore.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.Anything)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(999)).Return(db.Product{}, pgx.ErrNoRows)
+		store := newContentStoreWrapper(mockStore)
+		service := NewContentService(store, &ProductService{store: store}, providers.NewTemplateContentGenerator())
+
+		_, err := service.GenerateDraft(context.Background(), 9, 999)
+		assert.True(t, errors.Is(err, ErrProductNotFound))
+		assert.Empty(t, store.created)
+	})
+}
+
+func TestContentService_AcceptDraft(t *testing.T) {
+	t.Parallel()
+
+	pendingDraft := db.ProductContentDraft{
+		ID:          5,
+		ProductID:   1,
+		Description: "A generated description",
+		SeoTitle:    "Test Product | Electronics",
+		Tags:        []string{"electronics", "test"},
+		Generator:   "template",
+		Status:      db.ContentDraftStatusPending,
+	}
+
+	t.Run("draft applied through the product update", func(t *testing.T) {
+		t.Parallel()
+
+		product := createTestProduct()
+		product.Price = money.FromCents(1999).Numeric()
+		updated := product
+		updated.Description = pgtype.Text{String: pendingDraft.Description, Valid: true}
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(product, nil)
+		mockStore.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(arg db.UpdateProductParams) bool {
+			return arg.ID == 1 && arg.Name == product.Name && arg.Description.String == pendingDraft.Description &&
+				arg.Sku == product.Sku
+		})).Return(updated, nil)
+		mockStore.On("ListProductImages", mock.Anything, int32(1)).Return([]db.ProductImage{}, nil)
+
+		store := newContentStoreWrapper(mockStore)
+		store.drafts[5] = pendingDraft
+		service := NewContentService(store, &ProductService{store: store}, providers.NewTemplateContentGenerator())
+
+		resp, err := service.AcceptDraft(context.Background(), 9, 1, 5)
+		require.NoError(t, err)
+
+		assert.Equal(t, pendingDraft.Description, resp.Description)
+		assert.Equal(t, pendingDraft.SeoTitle, resp.SEOTitle)
+		assert.Equal(t, pendingDraft.Tags, resp.Tags)
+		assert.Equal(t, []db.UpdateProductContentParams{{
+			ID:       1,
+			SeoTitle: pgtype.Text{String: pendingDraft.SeoTitle, Valid: true},
+			Tags:     pendingDraft.Tags,
+		}}, store.contentUpdates)
+		assert.Equal(t, []db.ReviewProductContentDraftParams{{
+			ID:         5,
+			Status:     db.ContentDraftStatusAccepted,
+			ReviewedBy: pgtype.Int4{Int32: 9, Valid: true},
+		}}, store.reviews)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("reviewed drafts can't be accepted again", func(t *testing.T) {
+		t.Parallel()
+
+		store := newContentStoreWrapper(new(MockProductStore))
+		rejected := pendingDraft
+		rejected.Status = db.ContentDraftStatusRejected
+		store.drafts[5] = rejected
+		service := NewContentService(store, &ProductService{store: store}, providers.NewTemplateContentGenerator())
+
+		_, err := service.AcceptDraft(context.Background(), 9, 1, 5)
+		assert.True(t, errors.Is(err, ErrContentDraftReviewed))
+		assert.Empty(t, store.contentUpdates)
+	})
+
+	t.Run("draft of another product", func(t *testing.T) {
+		t.Parallel()
+
+		store := newContentStoreWrapper(new(MockProductStore))
+		store.drafts[5] = pendingDraft
+		service := NewContentService(store, &ProductService{store: store}, providers.NewTemplateContentGenerator())
+
+		_, err := service.AcceptDraft(context.Background(), 9, 2, 5)
+		assert.True(t, errors.Is(err, ErrContentDraftNotFound))
+	})
+}
+
+func TestContentService_RejectDraft(t *testing.T) {
+	t.Parallel()
+
+	store := newContentStoreWrapper(new(MockProductStore))
+	store.drafts[5] = db.ProductContentDraft{ID: 5, ProductID: 1, Status: db.ContentDraftStatusPending}
+	service := NewContentService(store, &ProductService{store: store}, providers.NewTemplateContentGenerator())
+
+	draft, err := service.RejectDraft(context.Background(), 9, 1, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "rejected", draft.Status)
+	require.NotNil(t, draft.ReviewedBy)
+	assert.Equal(t, uint(9), *draft.ReviewedBy)
+	assert.Empty(t, store.contentUpdates)
+
+	_, err = service.RejectDraft(context.Background(), 9, 1, 6)
+	assert.True(t, errors.Is(err, ErrContentDraftNotFound))
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Quote is a currency prices are shown or charged in, with the rate from the
+// base currency
+type Quote struct {
+	Base     string
+	Currency string
+	Rate     *big.Rat // units of Currency one unit of Base buys
+}
+
+// baseQuote prices in the base currency itself
+func baseQuote(base string) Quote {
+	return Quote{Base: base, Currency: base, Rate: big.NewRat(1, 1)}
+}
+
+// CurrencyConverter quotes the currencies the store sells in against the base
+// currency catalog prices are kept in
+type CurrencyConverter struct {
+	base      string
+	supported map[string]bool
+	rates     interfaces.ExchangeRates
+}
+
+// NewCurrencyConverter creates a CurrencyConverter. The base currency is
+// always supported.
+func NewCurrencyConverter(base string, supported []string, rates interfaces.ExchangeRates) (*CurrencyConverter, error) {
+	base, err := money.NormalizeCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CurrencyConverter{
+		base:      base,
+		supported: map[string]bool{base: true},
+		rates:     rates,
+	}
+	for _, code := range supported {
+		code, err := money.NormalizeCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		c.supported[code] = true
+	}
+	return c, nil
+}
+
+// Base returns the currency catalog prices are kept in. A nil converter
+// prices everything in money.DefaultCurrency.
+func (c *CurrencyConverter) Base() string {
+	if c == nil {
+		return money.DefaultCurrency
+	}
+	return c.base
+}
+
+// Supported normalizes a currency code and checks the store sells in it.
+// An empty code means the base currency.
+func (c *CurrencyConverter) Supported(currency string) (string, error) {
+	if currency == "" {
+		return c.Base(), nil
+	}
+
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	if code == c.Base() {
+		return code, nil
+	}
+	if c == nil || !c.supported[code] {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedCurrency, code)
+	}
+	return code, nil
+}
+
+// Quote returns the current rate into a currency. The rate is rounded to
+// money.RateScale places so an order can store it and replay it exactly.
+func (c *CurrencyConverter) Quote(ctx context.Context, currency string) (Quote, error) {
+	code, err := c.Supported(currency)
+	if err != nil {
+		return Quote{}, err
+	}
+	if code == c.Base() {
+		return baseQuote(code), nil
+	}
+
+	rate, err := c.rates.Rate(ctx, c.base, code)
+	if err != nil {
+		return Quote{}, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	// A zero rate, or one too small for RateScale, can't be inverted
+	rounded := money.RoundRate(rate)
+	if rounded.Sign() <= 0 {
+		return Quote{}, fmt.Errorf("%w: %s into %s", money.ErrInvalidRate, c.base, code)
+	}
+	return Quote{Base: c.base, Currency: code, Rate: rounded}, nil
+}
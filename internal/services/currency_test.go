@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+)
+
+func TestCurrencyConverter_Quote(t *testing.T) {
+	t.Parallel()
+
+	rates, err := providers.NewStaticExchangeRates("USD", map[string]string{"EUR": "0.92", "GBP": "0.79"})
+	require.NoError(t, err)
+	converter, err := NewCurrencyConverter("usd", []string{"eur"}, rates)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		currency string
+		want     Quote
+		wantErr  error
+	}{
+		{name: "empty means base", currency: "", want: Quote{Base: "USD", Currency: "USD", Rate: big.NewRat(1, 1)}},
+		{name: "base", currency: "USD", want: Quote{Base: "USD", Currency: "USD", Rate: big.NewRat(1, 1)}},
+		{name: "supported", currency: "eur", want: Quote{Base: "USD", Currency: "EUR", Rate: big.NewRat(92, 100)}},
+		{name: "rate known but not sold in", currency: "GBP", wantErr: ErrUnsupportedCurrency},
+		{name: "malformed", currency: "euro", wantErr: ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quote, err := converter.Quote(context.Background(), tt.currency)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Base, quote.Base)
+			assert.Equal(t, tt.want.Currency, quote.Currency)
+			assert.Equal(t, 0, tt.want.Rate.Cmp(quote.Rate))
+		})
+	}
+}
+
+// tinyRates quotes a rate that rounds to zero at money.RateScale
+type tinyRates struct{}
+
+func (tinyRates) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	return big.NewRat(1, 1_000_000_000), nil
+}
+
+func TestCurrencyConverter_Quote_RoundsToZero(t *testing.T) {
+	t.Parallel()
+
+	converter, err := NewCurrencyConverter("USD", []string{"EUR"}, tinyRates{})
+	require.NoError(t, err)
+
+	_, err = converter.Quote(context.Background(), "EUR")
+	assert.ErrorIs(t, err, money.ErrInvalidRate)
+}
+
+func TestCurrencyConverter_Nil(t *testing.T) {
+	t.Parallel()
+
+	var converter *CurrencyConverter
+
+	quote, err := converter.Quote(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", quote.Currency)
+
+	_, err = converter.Quote(context.Background(), "EUR")
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+}
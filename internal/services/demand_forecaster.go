@@ -0,0 +1,220 @@
+package services
+
+import (
+	"math"
+)
+
+// Forecast methods, from the most history needed to the least
+const (
+	ForecastMethodHoltWinters          = "holt_winters"
+	ForecastMethodExponentialSmoothing = "exponential_smoothing"
+	ForecastMethodNoSales              = "no_sales"
+)
+
+// DemandForecaster forecasts daily demand from a product's daily sales.
+// With at least two seasons of history it uses additive Holt-Winters
+// exponential smoothing, which follows the level of demand, its trend and
+// a repeating seasonal pattern such as busier weekends. With less it
+// smooths the level alone.
+type DemandForecaster struct {
+	LevelSmoothing    float64 // alpha: how fast the level follows new sales, 0-1
+	TrendSmoothing    float64 // beta: how fast the trend follows changes in level, 0-1
+	SeasonalSmoothing float64 // gamma: how fast the seasonal pattern follows new sales, 0-1
+	SeasonLength      int     // days before the pattern repeats, 7 for weekly
+	MovingAverageDays int     // days the plain moving average covers
+}
+
+// DemandForecast is what a DemandForecaster expects a product to sell
+type DemandForecast struct {
+	Daily         []float64 // expected units for each day ahead
+	DailyDemand   float64   // mean of Daily
+	MovingAverage float64   // mean daily sales over the last MovingAverageDays
+	Error         float64   // RMS error of the model's one-day-ahead forecasts over the history
+	Method        string
+}
+
+// Forecast forecasts the next horizon days from sales, one entry per day,
+// oldest first and ending yesterday
+func (f DemandForecaster) Forecast(sales []float64, horizon int) DemandForecast {
+	forecast := DemandForecast{
+		Daily:         make([]float64, horizon),
+		MovingAverage: movingAverage(sales, f.MovingAverageDays),
+		Method:        ForecastMethodNoSales,
+	}
+	if !anyPositive(sales) {
+		return forecast
+	}
+
+	var residuals []float64
+	if f.SeasonLength > 1 && len(sales) >= 2*f.SeasonLength {
+		residuals = f.holtWinters(sales, forecast.Daily)
+		forecast.Method = ForecastMethodHoltWinters
+	} else {
+		residuals = f.exponentialSmoothing(sales, forecast.Daily)
+		forecast.Method = ForecastMethodExponentialSmoothing
+	}
+
+	for i, demand := range forecast.Daily {
+		// Sales can't go negative, however steep the trend
+		forecast.Daily[i] = math.Max(demand, 0)
+		forecast.DailyDemand += forecast.Daily[i]
+	}
+	if horizon > 0 {
+		forecast.DailyDemand /= float64(horizon)
+	}
+	forecast.Error = rootMeanSquare(residuals)
+	return forecast
+}
+
+// holtWinters fits level, trend and season to sales, fills daily with the
+// forecast and returns the one-day-ahead errors made along the way. The
+// first season only sets the starting values.
+func (f DemandForecaster) holtWinters(sales []float64, daily []float64) []float64 {
+	m := f.SeasonLength
+	alpha, beta, gamma := f.LevelSmoothing, f.TrendSmoothing, f.SeasonalSmoothing
+
+	// Start the level at the first season's mean, the trend at the change
+	// between the first two seasons' means, and each season slot at how far
+	// that day sits from its season's mean, averaged over complete seasons
+	first, second := mean(sales[:m]), mean(sales[m:2*m])
+	level := first
+	trend := (second - first) / float64(m)
+	seasons := len(sales) / m
+	season := make([]float64, m)
+	for s := 0; s < seasons; s++ {
+		seasonMean := mean(sales[s*m : (s+1)*m])
+		for i := 0; i < m; i++ {
+			season[i] += (sales[s*m+i] - seasonMean) / float64(seasons)
+		}
+	}
+
+	residuals := make([]float64, 0, len(sales)-m)
+	for t := m; t < len(sales); t++ {
+		slot := t % m
+		residuals = append(residuals, sales[t]-(level+trend+season[slot]))
+
+		previous := level
+		level = alpha*(sales[t]-season[slot]) + (1-alpha)*(level+trend)
+		trend = beta*(level-previous) + (1-beta)*trend
+		season[slot] = gamma*(sales[t]-level) + (1-gamma)*season[slot]
+	}
+
+	for h := range daily {
+		daily[h] = level + float64(h+1)*trend + season[(len(sales)+h)%m]
+	}
+	return residuals
+}
+
+// exponentialSmoothing fits a level to sales and forecasts it flat
+func (f DemandForecaster) exponentialSmoothing(sales []float64, daily []float64) []float64 {
+	level := sales[0]
+	residuals := make([]float64, 0, len(sales)-1)
+	for _, sold := range sales[1:] {
+		residuals = append(residuals, sold-level)
+		level = f.LevelSmoothing*sold + (1-f.LevelSmoothing)*level
+	}
+	for h := range daily {
+		daily[h] = level
+	}
+	return residuals
+}
+
+// ReorderPlan is when to restock a product and by how much
+type ReorderPlan struct {
+	DaysOfCover     float64 // days current stock lasts; +Inf when nothing is expected to sell
+	ReorderPoint    int     // stock at which to reorder: lead time demand plus safety stock
+	ReorderQuantity int     // units to order now; 0 while stock is above the reorder point
+}
+
+// ReorderPolicy sets how restocks are planned. A restock takes LeadTimeDays
+// to arrive and should last CoverDays after that. Safety stock of
+// SafetyFactor forecast errors over the lead time covers demand the
+// forecast misses; 1.65 runs out about one lead time in twenty.
+type ReorderPolicy struct {
+	LeadTimeDays int
+	CoverDays    int
+	SafetyFactor float64
+}
+
+// Plan recommends a restock for a product with stock on hand, given its
+// forecast
+func (p ReorderPolicy) Plan(forecast DemandForecast, stock int) ReorderPlan {
+	plan := ReorderPlan{DaysOfCover: math.Inf(1)}
+	if forecast.DailyDemand > 0 {
+		plan.DaysOfCover = math.Max(float64(stock), 0) / forecast.DailyDemand
+	}
+
+	safetyStock := p.SafetyFactor * forecast.Error * math.Sqrt(float64(p.LeadTimeDays))
+	leadTimeDemand := sumDays(forecast.Daily, 0, p.LeadTimeDays)
+	plan.ReorderPoint = int(math.Ceil(leadTimeDemand + safetyStock))
+	if forecast.DailyDemand == 0 || stock > plan.ReorderPoint {
+		return plan
+	}
+
+	// Order enough to reach the stock needed to last the lead time and then
+	// the cover period
+	orderUpTo := leadTimeDemand + sumDays(forecast.Daily, p.LeadTimeDays, p.LeadTimeDays+p.CoverDays) + safetyStock
+	plan.ReorderQuantity = int(math.Max(math.Ceil(orderUpTo-float64(stock)), 0))
+	return plan
+}
+
+// Horizon is how many days ahead a plan needs forecast
+func (p ReorderPolicy) Horizon() int {
+	return p.LeadTimeDays + p.CoverDays
+}
+
+// sumDays adds up the forecast from day from to day to, repeating the last
+// forecast day when the forecast is shorter than that
+func sumDays(daily []float64, from, to int) float64 {
+	var total float64
+	for d := from; d < to; d++ {
+		switch {
+		case d < len(daily):
+			total += daily[d]
+		case len(daily) > 0:
+			total += daily[len(daily)-1]
+		}
+	}
+	return total
+}
+
+func movingAverage(sales []float64, days int) float64 {
+	if days <= 0 || days > len(sales) {
+		days = len(sales)
+	}
+	if days == 0 {
+		return 0
+	}
+	return mean(sales[len(sales)-days:])
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func rootMeanSquare(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func anyPositive(values []float64) bool {
+	for _, v := range values {
+		if v > 0 {
+			return true
+		}
+	}
+	return false
+}
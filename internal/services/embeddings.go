@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+const (
+	defaultEmbeddingBatchSize = 64
+
+	// semanticMinSimilarity is the cosine similarity a product's embedding
+	// needs to the query's to count as a semantic match
+	semanticMinSimilarity = 0.2
+
+	// hybridTextWeight is the share of a hybrid search score that comes from
+	// full-text rank; the rest comes from semantic similarity
+	hybridTextWeight = 0.5
+)
+
+var ErrSemanticSearchUnavailable = errors.New("semantic search is not configured")
+
+// EmbeddingIndexer keeps product embeddings in step with the catalog. It
+// embeds products that have no embedding from the current model or whose
+// text changed since theirs was made.
+type EmbeddingIndexer struct {
+	store     db.Store
+	embedder  interfaces.Embedder
+	batchSize int
+	notify    chan struct{}
+}
+
+func NewEmbeddingIndexer(store db.Store, embedder interfaces.Embedder, batchSize int) *EmbeddingIndexer {
+	if batchSize <= 0 {
+		batchSize = defaultEmbeddingBatchSize
+	}
+	return &EmbeddingIndexer{
+		store:     store,
+		embedder:  embedder,
+		batchSize: batchSize,
+		notify:    make(chan struct{}, 1),
+	}
+}
+
+// Notify asks for stale embeddings to be refreshed now rather than on the
+// next tick. It never blocks, and is a no-op on a nil indexer.
+func (i *EmbeddingIndexer) Notify() {
+	if i == nil {
+		return
+	}
+	select {
+	case i.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Notified receives after Notify is called
+func (i *EmbeddingIndexer) Notified() <-chan struct{} {
+	return i.notify
+}
+
+// IndexStale embeds every stale product, a batch at a time, and returns how
+// many it embedded
+func (i *EmbeddingIndexer) IndexStale(ctx context.Context) (int, error) {
+	indexed := 0
+	for {
+		products, err := i.store.ListProductsNeedingEmbedding(ctx, db.ListProductsNeedingEmbeddingParams{
+			Model: i.embedder.Model(),
+			Limit: int32(i.batchSize), //#nosec G115 -- batch size is small
+		})
+		if err != nil {
+			return indexed, fmt.Errorf("failed to list products needing embeddings: %w", err)
+		}
+		if len(products) == 0 {
+			return indexed, nil
+		}
+
+		texts := make([]string, len(products))
+		for j, product := range products {
+			texts[j] = productEmbeddingText(product)
+		}
+		vectors, err := i.embed(ctx, texts)
+		if err != nil {
+			return indexed, err
+		}
+
+		for j, product := range products {
+			err := i.store.UpsertProductEmbedding(ctx, db.UpsertProductEmbeddingParams{
+				ProductID:   product.ID,
+				Model:       i.embedder.Model(),
+				Embedding:   vectors[j],
+				ContentHash: product.ContentHash,
+			})
+			if err != nil {
+				return indexed, fmt.Errorf("failed to save embedding of product %d: %w", product.ID, err)
+			}
+			indexed++
+		}
+	}
+}
+
+// embed embeds texts as unit vectors, so their dot product is their cosine
+// similarity
+func (i *EmbeddingIndexer) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := i.embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed text: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("failed to embed text: got %d embeddings for %d texts", len(vectors), len(texts))
+	}
+	for _, vector := range vectors {
+		normalize(vector)
+	}
+	return vectors, nil
+}
+
+// productEmbeddingText is the text a product is embedded from
+func productEmbeddingText(p db.ListProductsNeedingEmbeddingRow) string {
+	parts := []string{p.Name, p.CategoryName}
+	if p.Description != "" {
+		parts = append(parts, p.Description)
+	}
+	if p.Attributes != "" {
+		parts = append(parts, p.Attributes)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// normalize scales a vector to unit length in place; a zero vector is left as is
+func normalize(vector []float32) {
+	var norm float64
+	for _, x := range vector {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+}
+
+// SemanticSearch ranks products by how close their meaning is to the query.
+// In hybrid mode the score blends that with the full-text rank, and products
+// matching either way are included.
+func (s *ProductService) SemanticSearch(ctx context.Context, req dto.SemanticSearchRequest, currency string) ([]dto.ProductSearchResult, *utils.PaginationMeta, error) {
+	if s.embeddings == nil {
+		return nil, nil, ErrSemanticSearchUnavailable
+	}
+
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := req.Page
+	limit := req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	vectors, err := s.embeddings.embed(ctx, []string{req.Query})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var textWeight float32
+	if req.Mode == dto.SemanticSearchHybrid {
+		textWeight = hybridTextWeight
+	}
+	model := s.embeddings.embedder.Model()
+
+	// Get total count for pagination
+	totalCount, err := s.store.CountSemanticSearchProducts(ctx, db.CountSemanticSearchProductsParams{
+		Model:         model,
+		Embedding:     vectors[0],
+		TextWeight:    textWeight,
+		Query:         req.Query,
+		MinSimilarity: semanticMinSimilarity,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Calculate total pages
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit > 0 {
+		totalPages++
+	}
+
+	paginationMeta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: int(totalCount),
+		TotalPages: totalPages,
+	}
+
+	products, err := s.store.SemanticSearchProducts(ctx, db.SemanticSearchProductsParams{
+		TextWeight:    textWeight,
+		Model:         model,
+		Embedding:     vectors[0],
+		Query:         req.Query,
+		MinSimilarity: semanticMinSimilarity,
+		Limit:         int32(limit),              //#nosec G115 -- pagination values are bounded
+		Offset:        int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(products) == 0 {
+		return []dto.ProductSearchResult{}, paginationMeta, nil
+	}
+
+	rows := make([]db.Product, len(products))
+	for i, product := range products {
+		rows[i] = product.Product
+	}
+	productResponses, err := s.productResponses(ctx, rows, quote)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]dto.ProductSearchResult, len(products))
+	for i, product := range products {
+		results[i] = dto.ProductSearchResult{ProductResponse: productResponses[i], Rank: product.Score}
+	}
+	return results, paginationMeta, nil
+}
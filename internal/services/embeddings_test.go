@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+)
+
+// embeddingStoreWrapper serves stale products in batches and records saved embeddings
+type embeddingStoreWrapper struct {
+	*productStoreWrapper
+	stale    []db.ListProductsNeedingEmbeddingRow
+	saved    []db.UpsertProductEmbeddingParams
+	counts   []db.CountSemanticSearchProductsParams
+	searches []db.SemanticSearchProductsParams
+	results  []db.SemanticSearchProductsRow
+}
+
+func (s *embeddingStoreWrapper) ListProductsNeedingEmbedding(ctx context.Context, arg db.ListProductsNeedingEmbeddingParams) ([]db.ListProductsNeedingEmbeddingRow, error) {
+	n := min(int(arg.Limit), len(s.stale))
+	batch := s.stale[:n]
+	s.stale = s.stale[n:]
+	return batch, nil
+}
+
+func (s *embeddingStoreWrapper) UpsertProductEmbedding(ctx context.Context, arg db.UpsertProductEmbeddingParams) error {
+	s.saved = append(s.saved, arg)
+	return nil
+}
+
+func (s *embeddingStoreWrapper) CountSemanticSearchProducts(ctx context.Context, arg db.CountSemanticSearchProductsParams) (int64, error) {
+	s.counts = append(s.counts, arg)
+	return int64(len(s.results)), nil
+}
+
+func (s *embeddingStoreWrapper) SemanticSearchProducts(ctx context.Context, arg db.SemanticSearchProductsParams) ([]db.SemanticSearchProductsRow, error) {
+	s.searches = append(s.searches, arg)
+	return s.results, nil
+}
+
+func newEmbeddingStore(mockStore *MockProductStore) *embeddingStoreWrapper {
+	return &embeddingStoreWrapper{productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore}}
+}
+
+func vectorNorm(vector []float32) float64 {
+	var norm float64
+	for _, x := range vector {
+		norm += float64(x) * float64(x)
+	}
+	return math.Sqrt(norm)
+}
+
+func TestEmbeddingIndexer_IndexStale(t *testing.T) {
+	t.Parallel()
+
+	store := newEmbeddingStore(new(MockProductStore))
+	store.stale = []db.ListProductsNeedingEmbeddingRow{
+		{ID: 1, Name: "Trail Running Shoes", CategoryName: "Footwear", ContentHash: "h1"},
+		{ID: 2, Name: "Waterproof Jacket", CategoryName: "Outerwear", Description: "Keeps rain out", ContentHash: "h2"},
+		{ID: 3, Name: "Wool Socks", CategoryName: "Footwear", Attributes: "material: wool", ContentHash: "h3"},
+	}
+	embedder := providers.NewHashEmbedder(64)
+	indexer := NewEmbeddingIndexer(store, embedder, 2)
+
+	indexed, err := indexer.IndexStale(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, indexed)
+	require.Len(t, store.saved, 3)
+	for i, saved := range store.saved {
+		assert.Equal(t, int32(i+1), saved.ProductID)
+		assert.Equal(t, embedder.Model(), saved.Model)
+		assert.Len(t, saved.Embedding, 64)
+		assert.InDelta(t, 1, vectorNorm(saved.Embedding), 1e-5)
+	}
+	assert.Equal(t, "h2", store.saved[1].ContentHash)
+}
+
+func TestEmbeddingIndexer_Notify(t *testing.T) {
+	t.Parallel()
+
+	indexer := NewEmbeddingIndexer(nil, providers.NewHashEmbedder(0), 0)
+
+	// Repeated notifications collapse into one wake-up
+	indexer.Notify()
+	indexer.Notify()
+	<-indexer.Notified()
+	select {
+	case <-indexer.Notified():
+		t.Fatal("expected a single pending notification")
+	default:
+	}
+
+	var nilIndexer *EmbeddingIndexer
+	assert.NotPanics(t, nilIndexer.Notify)
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	vector := []float32{3, 4}
+	normalize(vector)
+	assert.InDelta(t, 0.6, vector[0], 1e-6)
+	assert.InDelta(t, 0.8, vector[1], 1e-6)
+
+	zero := []float32{0, 0}
+	normalize(zero)
+	assert.Equal(t, []float32{0, 0}, zero)
+}
+
+func TestHashEmbedder_SimilarTextsAreCloser(t *testing.T) {
+	t.Parallel()
+
+	indexer := NewEmbeddingIndexer(nil, providers.NewHashEmbedder(256), 0)
+	vectors, err := indexer.embed(context.Background(), []string{
+		"red running shoes",
+		"running shoe in red",
+		"stainless steel kettle",
+	})
+	require.NoError(t, err)
+
+	dot := func(a, b []float32) float32 {
+		var sum float32
+		for i := range a {
+			sum += a[i] * b[i]
+		}
+		return sum
+	}
+	assert.Greater(t, dot(vectors[0], vectors[1]), dot(vectors[0], vectors[2]))
+
+	again, err := indexer.embed(context.Background(), []string{"red running shoes"})
+	require.NoError(t, err)
+	assert.Equal(t, vectors[0], again[0])
+}
+
+func TestProductService_SemanticSearch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		mode           string
+		wantTextWeight float32
+	}{
+		{name: "semantic", mode: dto.SemanticSearchSemantic, wantTextWeight: 0},
+		{name: "default is semantic", mode: "", wantTextWeight: 0},
+		{name: "hybrid", mode: dto.SemanticSearchHybrid, wantTextWeight: hybridTextWeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(MockProductStore)
+			mockStore.On("GetCategoriesByIDs", mock.Anything, []int32{1}).Return([]db.Category{createTestCategory()}, nil)
+			mockStore.On("ListProductImagesByProductIDs", mock.Anything, []int32{4}).Return([]db.ProductImage{}, nil)
+
+			store := newEmbeddingStore(mockStore)
+			store.results = []db.SemanticSearchProductsRow{{
+				Product: db.Product{
+					ID: 4, CategoryID: 1, Name: "Trail Running Shoes", Price: money.FromCents(12000).Numeric(),
+					SeoTitle: pgtype.Text{String: "Trail Running Shoes | Running", Valid: true},
+					Tags:     []string{"running", "trail"},
+				},
+				Score: 0.82,
+			}}
+			embedder := providers.NewHashEmbedder(64)
+			service := &ProductService{store: store, embeddings: NewEmbeddingIndexer(store, embedder, 0)}
+
+			results, meta, err := service.SemanticSearch(context.Background(), dto.SemanticSearchRequest{
+				Query: "sneakers for jogging",
+				Mode:  tt.mode,
+				Page:  2,
+				Limit: 5,
+			}, "")
+			require.NoError(t, err)
+
+			require.Len(t, store.searches, 1)
+			search := store.searches[0]
+			assert.Equal(t, tt.wantTextWeight, search.TextWeight)
+			assert.Equal(t, tt.wantTextWeight, store.counts[0].TextWeight)
+			assert.Equal(t, embedder.Model(), search.Model)
+			assert.Equal(t, "sneakers for jogging", search.Query)
+			assert.InDelta(t, 1, vectorNorm(search.Embedding), 1e-5)
+			assert.Equal(t, int32(5), search.Limit)
+			assert.Equal(t, int32(5), search.Offset)
+
+			assert.Equal(t, 1, meta.TotalCount)
+			require.Len(t, results, 1)
+			assert.Equal(t, "Trail Running Shoes", results[0].Name)
+			assert.Equal(t, "Trail Running Shoes | Running", results[0].SEOTitle)
+			assert.Equal(t, []string{"running", "trail"}, results[0].Tags)
+			assert.InDelta(t, 0.82, results[0].Rank, 1e-6)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductService_SemanticSearch_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	service := &ProductService{store: newEmbeddingStore(new(MockProductStore))}
+
+	_, _, err := service.SemanticSearch(context.Background(), dto.SemanticSearchRequest{Query: "shoes"}, "")
+	assert.ErrorIs(t, err, ErrSemanticSearchUnavailable)
+}
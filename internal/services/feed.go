@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const (
+	defaultFeedItems = 20
+	maxFeedItems     = 50
+
+	// feedCandidatePool caps how many best-selling products, overall and in
+	// the user's categories, are ranked for a feed
+	feedCandidatePool = 500
+
+	defaultFeedWindow   = 30 * 24 * time.Hour
+	defaultFeedHalfLife = 30 * 24 * time.Hour
+)
+
+// How much each kind of activity counts towards a user's affinity for a
+// category: buying says more than carting, carting more than looking
+const (
+	orderAffinityWeight = 3
+	cartAffinityWeight  = 2
+	viewAffinityWeight  = 1
+)
+
+// FeedWeights sets how much each signal counts in a feed score. Each signal
+// is scaled to 0..1 before weighting.
+type FeedWeights struct {
+	Popularity float64 // orders in the window, relative to the best-selling candidate
+	Affinity   float64 // the user's activity in the category, relative to their favourite
+	Recency    float64 // halves every half-life of the product's age
+}
+
+// DefaultFeedWeights favour what a user shops over what sells, with a
+// nudge towards new products
+var DefaultFeedWeights = FeedWeights{Popularity: 0.3, Affinity: 0.5, Recency: 0.2}
+
+// FeedService builds each user's personalized "for you" product feed from
+// their orders, cart and product views
+type FeedService struct {
+	store    db.Store
+	products *ProductService
+	weights  FeedWeights
+	window   time.Duration
+	halfLife time.Duration
+	now      func() time.Time
+}
+
+// NewFeedService creates a FeedService. Popularity and views count over the
+// last window; recency halves every halfLife. Zero weights fall back to
+// DefaultFeedWeights.
+func NewFeedService(store db.Store, products *ProductService, weights FeedWeights, window, halfLife time.Duration) *FeedService {
+	if weights == (FeedWeights{}) {
+		weights = DefaultFeedWeights
+	}
+	if window <= 0 {
+		window = defaultFeedWindow
+	}
+	if halfLife <= 0 {
+		halfLife = defaultFeedHalfLife
+	}
+	return &FeedService{
+		store:    store,
+		products: products,
+		weights:  weights,
+		window:   window,
+		halfLife: halfLife,
+		now:      time.Now,
+	}
+}
+
+// RecordView notes that a user looked at a product
+func (s *FeedService) RecordView(ctx context.Context, userID, productID int32) error {
+	if _, err := s.store.GetProductByID(ctx, productID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFound
+		}
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err := s.store.CreateProductView(ctx, db.CreateProductViewParams{
+		UserID:    userID,
+		ProductID: productID,
+	}); err != nil {
+		return fmt.Errorf("failed to record product view: %w", err)
+	}
+	return nil
+}
+
+// GetFeed ranks products for a user, leaving out ones they have bought or
+// have in their cart. Users with no history get the best-sellers.
+func (s *FeedService) GetFeed(ctx context.Context, userID int32, req dto.FeedRequest, currency string) (*dto.FeedResponse, error) {
+	quote, err := s.products.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	since := pgtype.Timestamptz{Time: now.Add(-s.window), Valid: true}
+	limit := feedLimit(req)
+
+	activity, err := s.store.ListUserCategoryActivity(ctx, db.ListUserCategoryActivityParams{
+		UserID:     userID,
+		ViewsSince: since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category activity: %w", err)
+	}
+	affinity := categoryAffinity(activity)
+
+	var ranked []feedScore
+	personalized := len(affinity) > 0
+	if personalized {
+		ranked, err = s.rankForUser(ctx, userID, since, affinity, now)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		candidates, err := s.store.ListFeedCandidates(ctx, db.ListFeedCandidatesParams{
+			Since:       since,
+			CategoryIds: []int32{},
+			Limit:       limit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list best-sellers: %w", err)
+		}
+		ranked = bestSellers(feedCandidates(candidates))
+	}
+	if len(ranked) > int(limit) {
+		ranked = ranked[:limit]
+	}
+
+	items, err := s.feedItems(ctx, ranked, quote)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FeedResponse{Personalized: personalized, Items: items}, nil
+}
+
+// rankForUser ranks the best-sellers overall and in the user's categories,
+// less what they already own
+func (s *FeedService) rankForUser(ctx context.Context, userID int32, since pgtype.Timestamptz, affinity map[int32]float64, now time.Time) ([]feedScore, error) {
+	categoryIDs := make([]int32, 0, len(affinity))
+	for id := range affinity {
+		categoryIDs = append(categoryIDs, id)
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	owned, err := s.store.ListUserOwnedProductIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list owned products: %w", err)
+	}
+	skip := make(map[int32]bool, len(owned))
+	for _, id := range owned {
+		skip[id] = true
+	}
+
+	var candidates []feedCandidate
+	for _, ids := range [][]int32{{}, categoryIDs} {
+		rows, err := s.store.ListFeedCandidates(ctx, db.ListFeedCandidatesParams{
+			Since:       since,
+			CategoryIds: ids,
+			Limit:       feedCandidatePool,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list feed candidates: %w", err)
+		}
+		for _, candidate := range feedCandidates(rows) {
+			if !skip[candidate.productID] {
+				skip[candidate.productID] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return rankFeed(candidates, affinity, s.weights, s.halfLife, now), nil
+}
+
+func (s *FeedService) feedItems(ctx context.Context, ranked []feedScore, quote Quote) ([]dto.FeedItem, error) {
+	if len(ranked) == 0 {
+		return []dto.FeedItem{}, nil
+	}
+
+	ids := make([]int32, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.productID
+	}
+	rows, err := s.store.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed products: %w", err)
+	}
+	byID := make(map[int32]db.Product, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+
+	// Keep the ranked order, dropping any product removed since it was ranked
+	products := make([]db.Product, 0, len(ranked))
+	scores := make([]float64, 0, len(ranked))
+	for _, r := range ranked {
+		if product, ok := byID[r.productID]; ok {
+			products = append(products, product)
+			scores = append(scores, r.score)
+		}
+	}
+
+	responses, err := s.products.productResponses(ctx, products, quote)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.FeedItem, len(responses))
+	for i, response := range responses {
+		items[i] = dto.FeedItem{ProductResponse: response, Score: scores[i]}
+	}
+	return items, nil
+}
+
+// feedCandidate is a product that may go in a feed, with the facts it is
+// ranked on
+type feedCandidate struct {
+	productID  int32
+	categoryID int32
+	createdAt  time.Time
+	orders     int32
+}
+
+type feedScore struct {
+	productID int32
+	score     float64
+}
+
+func feedCandidates(rows []db.ListFeedCandidatesRow) []feedCandidate {
+	candidates := make([]feedCandidate, len(rows))
+	for i, row := range rows {
+		candidates[i] = feedCandidate{
+			productID:  row.ID,
+			categoryID: row.CategoryID,
+			createdAt:  row.CreatedAt.Time,
+			orders:     row.Orders,
+		}
+	}
+	return candidates
+}
+
+// categoryAffinity weighs a user's activity in each category and scales it
+// so their favourite category is 1
+func categoryAffinity(activity []db.ListUserCategoryActivityRow) map[int32]float64 {
+	affinity := make(map[int32]float64, len(activity))
+	var top float64
+	for _, a := range activity {
+		weight := float64(orderAffinityWeight*a.Orders + cartAffinityWeight*a.CartItems + viewAffinityWeight*a.Views)
+		if weight <= 0 {
+			continue
+		}
+		affinity[a.CategoryID] = weight
+		top = max(top, weight)
+	}
+	for id := range affinity {
+		affinity[id] /= top
+	}
+	return affinity
+}
+
+// rankFeed scores candidates by weights and returns them best first, ties
+// going to the lower product ID. It depends only on its arguments, so the
+// same history always gives the same feed.
+func rankFeed(candidates []feedCandidate, affinity map[int32]float64, weights FeedWeights, halfLife time.Duration, now time.Time) []feedScore {
+	var topOrders int32
+	for _, c := range candidates {
+		topOrders = max(topOrders, c.orders)
+	}
+
+	ranked := make([]feedScore, len(candidates))
+	for i, c := range candidates {
+		var popularity float64
+		if topOrders > 0 {
+			popularity = float64(c.orders) / float64(topOrders)
+		}
+		age := max(now.Sub(c.createdAt), 0)
+		recency := math.Pow(0.5, float64(age)/float64(halfLife))
+
+		ranked[i] = feedScore{
+			productID: c.productID,
+			score:     weights.Popularity*popularity + weights.Affinity*affinity[c.categoryID] + weights.Recency*recency,
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].productID < ranked[j].productID
+	})
+	return ranked
+}
+
+// bestSellers keeps the candidates' best-seller order, scoring each by its
+// orders relative to the top seller
+func bestSellers(candidates []feedCandidate) []feedScore {
+	var topOrders int32
+	for _, c := range candidates {
+		topOrders = max(topOrders, c.orders)
+	}
+
+	ranked := make([]feedScore, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = feedScore{productID: c.productID}
+		if topOrders > 0 {
+			ranked[i].score = float64(c.orders) / float64(topOrders)
+		}
+	}
+	return ranked
+}
+
+// feedLimit returns defaultFeedItems unless asked for another number, and
+// never more than maxFeedItems
+func feedLimit(req dto.FeedRequest) int32 {
+	if req.Limit < 1 {
+		return defaultFeedItems
+	}
+	return int32(min(req.Limit, maxFeedItems)) //#nosec G115 -- capped above
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+var feedNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+const feedDay = 24 * time.Hour
+
+func TestRankFeed(t *testing.T) {
+	t.Parallel()
+
+	candidates := []feedCandidate{
+		{productID: 1, categoryID: 1, createdAt: feedNow, orders: 4},
+		{productID: 2, categoryID: 2, createdAt: feedNow.Add(-10 * feedDay), orders: 2},
+		{productID: 3, categoryID: 2, createdAt: feedNow.Add(-20 * feedDay), orders: 0},
+		{productID: 5, categoryID: 1, createdAt: feedNow.Add(-10 * feedDay), orders: 2},
+		{productID: 4, categoryID: 1, createdAt: feedNow.Add(-10 * feedDay), orders: 2},
+		{productID: 6, categoryID: 3, createdAt: feedNow.Add(feedDay), orders: 0},
+	}
+	affinity := map[int32]float64{1: 0.5, 2: 1}
+	weights := FeedWeights{Popularity: 0.5, Affinity: 0.25, Recency: 0.25}
+
+	got := rankFeed(candidates, affinity, weights, 10*feedDay, feedNow)
+
+	assert.Equal(t, []feedScore{
+		{productID: 1, score: 0.875},
+		{productID: 2, score: 0.625},
+		{productID: 4, score: 0.5}, // ties with 5, lower ID first
+		{productID: 5, score: 0.5},
+		{productID: 3, score: 0.3125},
+		{productID: 6, score: 0.25}, // created in the future counts as new
+	}, got)
+}
+
+func TestCategoryAffinity(t *testing.T) {
+	t.Parallel()
+
+	got := categoryAffinity([]db.ListUserCategoryActivityRow{
+		{CategoryID: 1, Orders: 1},
+		{CategoryID: 2, CartItems: 1, Views: 4},
+		{CategoryID: 3},
+	})
+
+	assert.Equal(t, map[int32]float64{1: 0.5, 2: 1}, got)
+}
+
+// feedStoreWrapper serves a fixed history and catalog, and records the
+// candidate queries
+type feedStoreWrapper struct {
+	*productStoreWrapper
+	activity       []db.ListUserCategoryActivityRow
+	owned          []int32
+	candidates     []db.ListFeedCandidatesRow
+	candidateCalls []db.ListFeedCandidatesParams
+	views          []db.CreateProductViewParams
+}
+
+func (s *feedStoreWrapper) ListUserCategoryActivity(ctx context.Context, arg db.ListUserCategoryActivityParams) ([]db.ListUserCategoryActivityRow, error) {
+	return s.activity, nil
+}
+
+func (s *feedStoreWrapper) ListUserOwnedProductIDs(ctx context.Context, userID int32) ([]int32, error) {
+	return s.owned, nil
+}
+
+func (s *feedStoreWrapper) ListFeedCandidates(ctx context.Context, arg db.ListFeedCandidatesParams) ([]db.ListFeedCandidatesRow, error) {
+	s.candidateCalls = append(s.candidateCalls, arg)
+	rows := []db.ListFeedCandidatesRow{}
+	for _, row := range s.candidates {
+		if len(rows) == int(arg.Limit) {
+			break
+		}
+		if len(arg.CategoryIds) == 0 || row.CategoryID == arg.CategoryIds[0] {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (s *feedStoreWrapper) GetProductsByIDs(ctx context.Context, ids []int32) ([]db.Product, error) {
+	products := make([]db.Product, 0, len(ids))
+	for _, row := range s.candidates {
+		for _, id := range ids {
+			if row.ID == id {
+				products = append(products, db.Product{ID: row.ID, CategoryID: 1, Name: "Product", Price: money.FromCents(1000).Numeric(), CreatedAt: row.CreatedAt})
+			}
+		}
+	}
+	return products, nil
+}
+
+func (s *feedStoreWrapper) CreateProductView(ctx context.Context, arg db.CreateProductViewParams) error {
+	s.views = append(s.views, arg)
+	return nil
+}
+
+func feedTestCandidates() []db.ListFeedCandidatesRow {
+	at := func(age time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: feedNow.Add(-age), Valid: true}
+	}
+	return []db.ListFeedCandidatesRow{
+		{ID: 1, CategoryID: 1, CreatedAt: at(0), Orders: 4},
+		{ID: 2, CategoryID: 2, CreatedAt: at(10 * feedDay), Orders: 2},
+		{ID: 3, CategoryID: 2, CreatedAt: at(20 * feedDay), Orders: 0},
+	}
+}
+
+func newFeedTestService(store *feedStoreWrapper) *FeedService {
+	service := NewFeedService(store, &ProductService{store: store}, FeedWeights{Popularity: 0.5, Affinity: 0.25, Recency: 0.25}, 30*feedDay, 10*feedDay)
+	service.now = func() time.Time { return feedNow }
+	return service
+}
+
+func TestFeedService_GetFeed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("personalized feed leaves out owned products", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetCategoriesByIDs", mock.Anything, []int32{1}).Return([]db.Category{createTestCategory()}, nil)
+		mockStore.On("ListProductImagesByProductIDs", mock.Anything, []int32{2, 3}).Return([]db.ProductImage{}, nil)
+
+		store := &feedStoreWrapper{
+			productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore},
+			activity:            []db.ListUserCategoryActivityRow{{CategoryID: 2, Views: 3}},
+			owned:               []int32{1},
+			candidates:          feedTestCandidates(),
+		}
+
+		feed, err := newFeedTestService(store).GetFeed(context.Background(), 7, dto.FeedRequest{}, "")
+		require.NoError(t, err)
+
+		assert.True(t, feed.Personalized)
+		require.Len(t, feed.Items, 2)
+		// With product 1 left out, product 2 is the best-selling candidate
+		assert.Equal(t, uint(2), feed.Items[0].ID)
+		assert.Equal(t, 0.875, feed.Items[0].Score)
+		assert.Equal(t, uint(3), feed.Items[1].ID)
+		assert.Equal(t, 0.3125, feed.Items[1].Score)
+
+		require.Len(t, store.candidateCalls, 2)
+		assert.Equal(t, []int32{}, store.candidateCalls[0].CategoryIds)
+		assert.Equal(t, []int32{2}, store.candidateCalls[1].CategoryIds)
+		assert.Equal(t, feedNow.Add(-30*feedDay), store.candidateCalls[0].Since.Time)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("new users get best-sellers", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetCategoriesByIDs", mock.Anything, []int32{1}).Return([]db.Category{createTestCategory()}, nil)
+		mockStore.On("ListProductImagesByProductIDs", mock.Anything, []int32{1, 2}).Return([]db.ProductImage{}, nil)
+
+		store := &feedStoreWrapper{
+			productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore},
+			candidates:          feedTestCandidates(),
+		}
+
+		feed, err := newFeedTestService(store).GetFeed(context.Background(), 7, dto.FeedRequest{Limit: 2}, "")
+		require.NoError(t, err)
+
+		assert.False(t, feed.Personalized)
+		require.Len(t, feed.Items, 2)
+		assert.Equal(t, uint(1), feed.Items[0].ID)
+		assert.Equal(t, 1.0, feed.Items[0].Score)
+		assert.Equal(t, uint(2), feed.Items[1].ID)
+		assert.Equal(t, 0.5, feed.Items[1].Score)
+
+		require.Len(t, store.candidateCalls, 1)
+		assert.Equal(t, int32(2), store.candidateCalls[0].Limit)
+		mockStore.AssertExpectations(t)
+	})
+}
+
+func TestFeedService_RecordView(t *testing.T) {
+	t.Parallel()
+
+	t.Run("view recorded", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+		store := &feedStoreWrapper{productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore}}
+
+		err := newFeedTestService(store).RecordView(context.Background(), 7, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []db.CreateProductViewParams{{UserID: 7, ProductID: 1}}, store.views)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(999)).Return(db.Product{}, pgx.ErrNoRows)
+		store := &feedStoreWrapper{productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore}}
+
+		err := newFeedTestService(store).RecordView(context.Background(), 7, 999)
+		assert.True(t, errors.Is(err, ErrProductNotFound))
+		assert.Empty(t, store.views)
+	})
+}
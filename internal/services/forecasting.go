@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+const (
+	defaultForecastHistoryDays = 180
+	forecastDay                = 24 * time.Hour
+)
+
+// DefaultDemandForecaster smooths gently enough that a single busy day
+// doesn't swing the forecast, with a weekly season
+var DefaultDemandForecaster = DemandForecaster{
+	LevelSmoothing:    0.3,
+	TrendSmoothing:    0.05,
+	SeasonalSmoothing: 0.2,
+	SeasonLength:      7,
+	MovingAverageDays: 28,
+}
+
+// DefaultReorderPolicy restocks for a month, a week ahead
+var DefaultReorderPolicy = ReorderPolicy{LeadTimeDays: 7, CoverDays: 30, SafetyFactor: 1.65}
+
+// forecastCSVHeader is the first row of ExportCSV
+var forecastCSVHeader = []string{
+	"product_id", "sku", "name", "stock", "daily_demand", "moving_average", "forecast_error",
+	"days_of_cover", "reorder_point", "reorder_quantity", "method", "history_days", "computed_at",
+}
+
+// ForecastService forecasts each product's daily demand from its sales in
+// order_items, and recommends when and how much to restock against its
+// current stock
+type ForecastService struct {
+	store       db.Store
+	forecaster  DemandForecaster
+	policy      ReorderPolicy
+	historyDays int
+	now         func() time.Time
+}
+
+// NewForecastService creates a ForecastService that forecasts from the last
+// historyDays of sales. A zero forecaster or policy falls back to
+// DefaultDemandForecaster or DefaultReorderPolicy.
+func NewForecastService(store db.Store, forecaster DemandForecaster, policy ReorderPolicy, historyDays int) *ForecastService {
+	if forecaster == (DemandForecaster{}) {
+		forecaster = DefaultDemandForecaster
+	}
+	if policy == (ReorderPolicy{}) {
+		policy = DefaultReorderPolicy
+	}
+	if historyDays <= 0 {
+		historyDays = defaultForecastHistoryDays
+	}
+	return &ForecastService{
+		store:       store,
+		forecaster:  forecaster,
+		policy:      policy,
+		historyDays: historyDays,
+		now:         time.Now,
+	}
+}
+
+// Refresh forecasts every active product from its sales up to the end of
+// yesterday, UTC, and returns how many it forecast. The old forecasts are
+// replaced in one transaction.
+func (s *ForecastService) Refresh(ctx context.Context) (int, error) {
+	today := s.now().UTC().Truncate(forecastDay)
+
+	products, err := s.store.ListForecastProducts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list products: %w", err)
+	}
+
+	rows, err := s.store.ListDailyProductSales(ctx, db.ListDailyProductSalesParams{
+		Since: pgtype.Timestamptz{Time: today.AddDate(0, 0, -s.historyDays), Valid: true},
+		Until: pgtype.Timestamptz{Time: today, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list product sales: %w", err)
+	}
+	sales := dailySales(rows, today)
+
+	params := db.InsertDemandForecastsParams{}
+	for _, product := range products {
+		history := sales[product.ID]
+		forecast := s.forecaster.Forecast(history, s.policy.Horizon())
+		stock := int(product.Stock.Int32)
+		plan := s.policy.Plan(forecast, stock)
+
+		params.ProductIds = append(params.ProductIds, product.ID)
+		params.Stocks = append(params.Stocks, product.Stock.Int32)
+		params.DailyDemands = append(params.DailyDemands, forecast.DailyDemand)
+		params.MovingAverages = append(params.MovingAverages, forecast.MovingAverage)
+		params.ForecastErrors = append(params.ForecastErrors, forecast.Error)
+		params.ReorderPoints = append(params.ReorderPoints, int32(plan.ReorderPoint))            //#nosec G115 -- bounded by forecast stock levels
+		params.ReorderQuantities = append(params.ReorderQuantities, int32(plan.ReorderQuantity)) //#nosec G115 -- bounded by forecast stock levels
+		params.Methods = append(params.Methods, forecast.Method)
+		params.HistoryDays = append(params.HistoryDays, int32(len(history))) //#nosec G115 -- at most historyDays
+	}
+
+	err = s.store.ExecTx(ctx, func(q db.Querier) error {
+		if err := q.DeleteDemandForecasts(ctx); err != nil {
+			return fmt.Errorf("failed to clear demand forecasts: %w", err)
+		}
+		if len(params.ProductIds) == 0 {
+			return nil
+		}
+		if _, err := q.InsertDemandForecasts(ctx, params); err != nil {
+			return fmt.Errorf("failed to save demand forecasts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(products), nil
+}
+
+// ListForecasts returns the latest forecasts, products that run out
+// soonest first
+func (s *ForecastService) ListForecasts(ctx context.Context, page, limit int) ([]dto.DemandForecastResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	totalCount, err := s.store.CountDemandForecasts(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count demand forecasts: %w", err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit > 0 {
+		totalPages++
+	}
+
+	rows, err := s.store.ListDemandForecasts(ctx, db.ListDemandForecastsParams{
+		Limit:  int32(limit),              //#nosec G115 -- pagination values are bounded
+		Offset: int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list demand forecasts: %w", err)
+	}
+
+	forecasts := make([]dto.DemandForecastResponse, len(rows))
+	for i, row := range rows {
+		forecasts[i] = toDemandForecastResponse(row)
+	}
+
+	return forecasts, &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: int(totalCount),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ExportCSV writes every latest forecast to w as CSV, in the order
+// ListForecasts returns them, under a header row
+func (s *ForecastService) ExportCSV(ctx context.Context, w io.Writer) error {
+	totalCount, err := s.store.CountDemandForecasts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count demand forecasts: %w", err)
+	}
+
+	rows, err := s.store.ListDemandForecasts(ctx, db.ListDemandForecastsParams{
+		Limit: int32(totalCount), //#nosec G115 -- one row per product
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list demand forecasts: %w", err)
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(forecastCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		daysOfCover := ""
+		if row.DaysOfCover.Valid {
+			daysOfCover = formatForecastFloat(row.DaysOfCover.Float64)
+		}
+		record := []string{
+			strconv.Itoa(int(row.ProductID)),
+			row.Sku,
+			row.Name,
+			strconv.Itoa(int(row.Stock)),
+			formatForecastFloat(row.DailyDemand),
+			formatForecastFloat(row.MovingAverage),
+			formatForecastFloat(row.ForecastError),
+			daysOfCover,
+			strconv.Itoa(int(row.ReorderPoint)),
+			strconv.Itoa(int(row.ReorderQuantity)),
+			row.Method,
+			strconv.Itoa(int(row.HistoryDays)),
+			row.ComputedAt.Time.UTC().Format(time.RFC3339),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// dailySales lays each product's sales out one entry per day, from its
+// first sale in rows to the day before until, with days without sales as 0
+func dailySales(rows []db.ListDailyProductSalesRow, until time.Time) map[int32][]float64 {
+	first := make(map[int32]time.Time)
+	for _, row := range rows {
+		if day, ok := first[row.ProductID]; !ok || row.Day.Time.Before(day) {
+			first[row.ProductID] = row.Day.Time
+		}
+	}
+
+	sales := make(map[int32][]float64, len(first))
+	for productID, day := range first {
+		days := int(until.Sub(day) / forecastDay)
+		if days > 0 {
+			sales[productID] = make([]float64, days)
+		}
+	}
+	for _, row := range rows {
+		series := sales[row.ProductID]
+		if d := int(row.Day.Time.Sub(first[row.ProductID]) / forecastDay); d < len(series) {
+			series[d] += float64(row.Quantity)
+		}
+	}
+	return sales
+}
+
+func toDemandForecastResponse(row db.ListDemandForecastsRow) dto.DemandForecastResponse {
+	forecast := dto.DemandForecastResponse{
+		ProductID:       uint(row.ProductID), //#nosec G115 -- DB ID is always positive
+		SKU:             row.Sku,
+		Name:            row.Name,
+		Stock:           int(row.Stock),
+		DailyDemand:     roundForecast(row.DailyDemand),
+		MovingAverage:   roundForecast(row.MovingAverage),
+		ForecastError:   roundForecast(row.ForecastError),
+		ReorderPoint:    int(row.ReorderPoint),
+		ReorderQuantity: int(row.ReorderQuantity),
+		Method:          row.Method,
+		HistoryDays:     int(row.HistoryDays),
+		ComputedAt:      row.ComputedAt.Time,
+	}
+	if row.DaysOfCover.Valid {
+		daysOfCover := roundForecast(row.DaysOfCover.Float64)
+		forecast.DaysOfCover = &daysOfCover
+	}
+	return forecast
+}
+
+// roundForecast keeps two decimals; forecasts aren't more precise than that
+func roundForecast(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func formatForecastFloat(v float64) string {
+	return strconv.FormatFloat(roundForecast(v), 'f', 2, 64)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+)
+
+var forecastNow = time.Date(2026, 3, 2, 15, 30, 0, 0, time.UTC) // a Monday
+
+// syntheticSales builds days of sales from a demand curve, with noise of up
+// to noise units either way drawn from a fixed seed
+func syntheticSales(days int, noise float64, demand func(day int) float64) []float64 {
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 -- deterministic test fixture
+	sales := make([]float64, days)
+	for d := range sales {
+		sales[d] = math.Max(math.Round(demand(d)+(rng.Float64()*2-1)*noise), 0)
+	}
+	return sales
+}
+
+// weekendPeak sells 8 a day on weekdays and 20 on Saturdays and Sundays,
+// for series that start on a Monday
+func weekendPeak(day int) float64 {
+	if day%7 >= 5 {
+		return 20
+	}
+	return 8
+}
+
+// meanAbsolutePercentageError compares a forecast with the demand curve
+// behind the sales that followed it, from day from on
+func meanAbsolutePercentageError(forecast []float64, from int, demand func(day int) float64) float64 {
+	var total float64
+	for i, f := range forecast {
+		actual := demand(from + i)
+		total += math.Abs(f-actual) / actual
+	}
+	return total / float64(len(forecast))
+}
+
+func TestDemandForecaster_Forecast(t *testing.T) {
+	t.Parallel()
+
+	forecaster := DefaultDemandForecaster
+
+	t.Run("steady demand", func(t *testing.T) {
+		t.Parallel()
+
+		sales := syntheticSales(56, 0, func(int) float64 { return 10 })
+		forecast := forecaster.Forecast(sales, 14)
+
+		assert.Equal(t, ForecastMethodHoltWinters, forecast.Method)
+		assert.InDelta(t, 10, forecast.DailyDemand, 1e-9)
+		assert.InDelta(t, 10, forecast.MovingAverage, 1e-9)
+		assert.InDelta(t, 0, forecast.Error, 1e-9)
+	})
+
+	t.Run("weekly season held out for two weeks", func(t *testing.T) {
+		t.Parallel()
+
+		// Fit ten noisy weeks, then forecast the two weeks after them
+		sales := syntheticSales(70, 2, weekendPeak)
+		forecast := forecaster.Forecast(sales, 14)
+
+		assert.Equal(t, ForecastMethodHoltWinters, forecast.Method)
+		assert.Less(t, meanAbsolutePercentageError(forecast.Daily, 70, weekendPeak), 0.1)
+
+		// Weekends stand out from weekdays in the forecast as they did in sales
+		assert.Greater(t, forecast.Daily[5], 1.8*forecast.Daily[2]) // Saturday over Wednesday
+		assert.InEpsilon(t, (5*8+2*20)/7.0, forecast.DailyDemand, 0.1)
+	})
+
+	t.Run("growing demand held out for a week", func(t *testing.T) {
+		t.Parallel()
+
+		growing := func(day int) float64 { return 5 + 0.2*float64(day) }
+		sales := syntheticSales(84, 1, growing)
+		forecast := forecaster.Forecast(sales, 7)
+
+		assert.Less(t, meanAbsolutePercentageError(forecast.Daily, 84, growing), 0.03)
+		// The trend carries the forecast above recent sales
+		assert.Greater(t, forecast.DailyDemand, forecast.MovingAverage)
+	})
+
+	t.Run("too little history for a season", func(t *testing.T) {
+		t.Parallel()
+
+		forecast := forecaster.Forecast([]float64{4, 6, 5, 5, 6}, 7)
+
+		assert.Equal(t, ForecastMethodExponentialSmoothing, forecast.Method)
+		assert.InDelta(t, 5.3, forecast.DailyDemand, 0.2)
+		assert.InDelta(t, 5.2, forecast.MovingAverage, 1e-9)
+	})
+
+	t.Run("no sales", func(t *testing.T) {
+		t.Parallel()
+
+		forecast := forecaster.Forecast(nil, 7)
+
+		assert.Equal(t, ForecastMethodNoSales, forecast.Method)
+		assert.Zero(t, forecast.DailyDemand)
+		assert.Equal(t, make([]float64, 7), forecast.Daily)
+	})
+
+	t.Run("falling demand never forecasts below zero", func(t *testing.T) {
+		t.Parallel()
+
+		sales := syntheticSales(28, 0, func(day int) float64 { return math.Max(27-float64(day), 0) })
+		forecast := forecaster.Forecast(sales, 37)
+
+		for _, demand := range forecast.Daily {
+			assert.GreaterOrEqual(t, demand, 0.0)
+		}
+	})
+}
+
+func TestReorderPolicy_Plan(t *testing.T) {
+	t.Parallel()
+
+	policy := ReorderPolicy{LeadTimeDays: 7, CoverDays: 30, SafetyFactor: 2}
+	steady := DemandForecast{Daily: []float64{10, 10, 10}, DailyDemand: 10, Error: 1}
+	safetyStock := 2 * math.Sqrt(7)
+
+	tests := []struct {
+		name  string
+		stock int
+		want  ReorderPlan
+	}{
+		{
+			name:  "below the reorder point orders up to lead time and cover",
+			stock: 50,
+			want: ReorderPlan{
+				DaysOfCover:     5,
+				ReorderPoint:    int(math.Ceil(70 + safetyStock)),
+				ReorderQuantity: int(math.Ceil(370 + safetyStock - 50)),
+			},
+		},
+		{
+			name:  "above the reorder point orders nothing",
+			stock: 100,
+			want:  ReorderPlan{DaysOfCover: 10, ReorderPoint: int(math.Ceil(70 + safetyStock))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, policy.Plan(steady, tt.stock))
+		})
+	}
+
+	t.Run("nothing expected to sell", func(t *testing.T) {
+		t.Parallel()
+
+		plan := policy.Plan(DemandForecast{Daily: make([]float64, 37)}, 0)
+		assert.True(t, math.IsInf(plan.DaysOfCover, 1))
+		assert.Zero(t, plan.ReorderQuantity)
+	})
+}
+
+func TestDailySales(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, 2, 20+d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	until := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+
+	got := dailySales([]db.ListDailyProductSalesRow{
+		{ProductID: 1, Day: day(1), Quantity: 3},
+		{ProductID: 1, Day: day(4), Quantity: 2},
+		{ProductID: 2, Day: day(0), Quantity: 5},
+	}, until)
+
+	assert.Equal(t, map[int32][]float64{
+		1: {3, 0, 0, 2},
+		2: {5, 0, 0, 0, 0},
+	}, got)
+}
+
+func TestForecastService_Refresh(t *testing.T) {
+	t.Parallel()
+
+	today := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("ListForecastProducts", mock.Anything).Return([]db.ListForecastProductsRow{
+		{ID: 1, Stock: pgtype.Int4{Int32: 40, Valid: true}},
+		{ID: 2, Stock: pgtype.Int4{Int32: 5, Valid: true}},
+	}, nil)
+	mockStore.On("ListDailyProductSales", mock.Anything, db.ListDailyProductSalesParams{
+		Since: pgtype.Timestamptz{Time: today.AddDate(0, 0, -90), Valid: true},
+		Until: pgtype.Timestamptz{Time: today, Valid: true},
+	}).Return([]db.ListDailyProductSalesRow{
+		{ProductID: 1, Day: pgtype.Date{Time: today.AddDate(0, 0, -3), Valid: true}, Quantity: 4},
+	}, nil)
+	mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("DeleteDemandForecasts", mock.Anything).Return(nil)
+	mockStore.On("InsertDemandForecasts", mock.Anything, mock.MatchedBy(func(arg db.InsertDemandForecastsParams) bool {
+		return assert.ObjectsAreEqual([]int32{1, 2}, arg.ProductIds)
+	})).Return(int64(2), nil)
+
+	service := NewForecastService(mockStore, DemandForecaster{}, ReorderPolicy{}, 90)
+	service.now = func() time.Time { return forecastNow }
+
+	forecast, err := service.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, forecast)
+	mockStore.AssertExpectations(t)
+}
+
+func TestForecastService_ExportCSV(t *testing.T) {
+	t.Parallel()
+
+	computedAt := pgtype.Timestamptz{Time: forecastNow, Valid: true}
+	mockStore := new(mocks.MockStore)
+	mockStore.On("CountDemandForecasts", mock.Anything).Return(int64(2), nil)
+	mockStore.On("ListDemandForecasts", mock.Anything, db.ListDemandForecastsParams{Limit: 2}).Return([]db.ListDemandForecastsRow{
+		{
+			ProductID: 1, Sku: "MUG-1", Name: "Mug, large", Stock: 12, DailyDemand: 4.004, MovingAverage: 3.5,
+			ForecastError: 1.25, DaysOfCover: pgtype.Float8{Float64: 2.997, Valid: true}, ReorderPoint: 40,
+			ReorderQuantity: 176, Method: ForecastMethodHoltWinters, HistoryDays: 120, ComputedAt: computedAt,
+		},
+		{
+			ProductID: 2, Sku: "LAMP-1", Name: "Lamp", Stock: 3, Method: ForecastMethodNoSales, ComputedAt: computedAt,
+		},
+	}, nil)
+
+	service := NewForecastService(mockStore, DemandForecaster{}, ReorderPolicy{}, 0)
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportCSV(context.Background(), &buf))
+
+	assert.Equal(t, "product_id,sku,name,stock,daily_demand,moving_average,forecast_error,days_of_cover,reorder_point,reorder_quantity,method,history_days,computed_at\n"+
+		"1,MUG-1,\"Mug, large\",12,4.00,3.50,1.25,3.00,40,176,holt_winters,120,2026-03-02T15:30:00Z\n"+
+		"2,LAMP-1,Lamp,3,0.00,0.00,0.00,,0,0,no_sales,0,2026-03-02T15:30:00Z\n", buf.String())
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+)
+
+// imageUploadReclaimAfter is how long an upload may be processing before
+// it is taken to be abandoned by a request that crashed and may be claimed
+// again. Processing takes seconds.
+const imageUploadReclaimAfter = 10 * time.Minute
+
+var (
+	ErrImageUploadNotFound   = errors.New("image upload not found")
+	ErrImageUploadNotPending = errors.New("image upload is no longer pending")
+	ErrImageNotUploaded      = errors.New("image has not been uploaded yet")
+)
+
+// ImageUploadService lets clients upload product images straight to
+// storage. Creating an upload presigns a request that stores the raw file
+// under incoming/; completing it processes the file like an image uploaded
+// through the API, adds it to the product's images and deletes the raw file.
+type ImageUploadService struct {
+	store    db.Store
+	provider interfaces.Upload
+	uploads  *UploadService
+	images   interfaces.ProductImageServicer
+	ttl      time.Duration
+}
+
+func NewImageUploadService(store db.Store, provider interfaces.Upload, uploads *UploadService, images interfaces.ProductImageServicer, ttl time.Duration) *ImageUploadService {
+	return &ImageUploadService{
+		store:    store,
+		provider: provider,
+		uploads:  uploads,
+		images:   images,
+		ttl:      ttl,
+	}
+}
+
+// CreateUpload records a pending upload of an image for a product and
+// returns the presigned request to upload it with
+func (s *ImageUploadService) CreateUpload(ctx context.Context, actorID int32, productID uint, req dto.CreateImageUploadRequest) (*dto.ImageUploadResponse, error) {
+	if req.Size > s.uploads.limits.MaxBytes {
+		return nil, ErrImageTooLarge
+	}
+	if _, err := s.store.GetProductByID(ctx, int32(productID)); err != nil { //#nosec G115 -- id from validated request
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	method := req.Method
+	if method == "" {
+		method = dto.UploadMethodPut
+	}
+	path := fmt.Sprintf("incoming/products/%d/%s", productID, uuid.New().String())
+
+	presigned, err := s.provider.PresignUpload(ctx, method, path, req.ContentType, req.Size, s.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := s.store.CreateProductImageUpload(ctx, db.CreateProductImageUploadParams{
+		ProductID:   int32(productID), //#nosec G115 -- id from validated request
+		StoragePath: path,
+		ContentType: req.ContentType,
+		MaxBytes:    req.Size,
+		AltText:     pgtype.Text{String: req.AltText, Valid: req.AltText != ""},
+		CreatedBy:   pgtype.Int4{Int32: actorID, Valid: actorID != 0},
+		ExpiresAt:   pgtype.Timestamptz{Time: presigned.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image upload: %w", err)
+	}
+
+	resp := toImageUploadResponse(upload)
+	resp.Upload = presigned
+	return resp, nil
+}
+
+// CompleteUpload processes an uploaded image into the product's images.
+// An upload that is not a valid image fails, and has to be started again.
+// Any other error leaves it pending, with its file, to be completed again.
+func (s *ImageUploadService) CompleteUpload(ctx context.Context, productID, uploadID uint) (*dto.ImageUploadResponse, error) {
+	upload, err := s.store.GetProductImageUpload(ctx, int32(uploadID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrImageUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to get image upload: %w", err)
+	}
+	if upload.ProductID != int32(productID) { //#nosec G115 -- id from validated request
+		return nil, ErrImageUploadNotFound
+	}
+	if upload.Status != db.ImageUploadStatusPending && upload.Status != db.ImageUploadStatusProcessing {
+		return nil, ErrImageUploadNotPending
+	}
+
+	// Check the file is there before claiming the upload, so a client that
+	// completes too early can try again
+	size, err := s.uploadedSize(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err = s.store.ClaimProductImageUpload(ctx, db.ClaimProductImageUploadParams{
+		ID:            upload.ID,
+		ProductID:     upload.ProductID,
+		ReclaimBefore: pgtype.Timestamptz{Time: time.Now().Add(-imageUploadReclaimAfter), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrImageUploadNotPending
+		}
+		return nil, fmt.Errorf("failed to claim image upload: %w", err)
+	}
+
+	image, err := s.addImage(ctx, upload, size)
+	if err != nil {
+		return nil, s.abandon(ctx, upload, err)
+	}
+
+	// Linking the image completes the upload, so it never shows completed
+	// without one
+	upload, err = s.store.CompleteProductImageUpload(ctx, db.CompleteProductImageUploadParams{
+		ID:        upload.ID,
+		ImageID:   pgtype.Int4{Int32: int32(image.ID), Valid: true}, //#nosec G115 -- id from the database
+		ClaimedAt: upload.ClaimedAt,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrImageUploadNotPending
+		}
+		return nil, fmt.Errorf("failed to complete image upload: %w", err)
+	}
+	s.deleteIncoming(ctx, upload)
+
+	resp := toImageUploadResponse(upload)
+	resp.Image = image
+	return resp, nil
+}
+
+// ExpireUploads expires uploads left pending for longer than their URL
+// worked, and deletes any file uploaded for them. Uploads get that long
+// again to be completed after their URL expires, and as long again to be
+// completed once more after a request processing them crashed.
+func (s *ImageUploadService) ExpireUploads(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.ttl)
+	expired, err := s.store.ExpireProductImageUploads(ctx, db.ExpireProductImageUploadsParams{
+		Cutoff:          pgtype.Timestamptz{Time: cutoff, Valid: true},
+		AbandonedBefore: pgtype.Timestamptz{Time: cutoff.Add(-imageUploadReclaimAfter), Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire image uploads: %w", err)
+	}
+	for _, upload := range expired {
+		s.deleteIncoming(ctx, upload)
+	}
+	return len(expired), nil
+}
+
+// uploadedSize returns the size of an upload's file, or ErrImageNotUploaded
+// when it isn't stored yet
+func (s *ImageUploadService) uploadedSize(ctx context.Context, upload db.ProductImageUpload) (int64, error) {
+	info, err := s.provider.Stat(ctx, upload.StoragePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, ErrImageNotUploaded
+		}
+		return 0, fmt.Errorf("failed to stat uploaded image: %w", err)
+	}
+	return info.Size, nil
+}
+
+// addImage processes a claimed upload's file into the product's images.
+// Presigned URLs are bound to the upload's size, so a larger file means the
+// storage didn't enforce it; the upload fails like one that isn't an image.
+func (s *ImageUploadService) addImage(ctx context.Context, upload db.ProductImageUpload, size int64) (*dto.ProductImageResponse, error) {
+	if size > upload.MaxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	file, err := s.provider.Get(ctx, upload.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded image: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	stored, err := s.uploads.StoreProductImage(ctx, io.LimitReader(file, upload.MaxBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	// When the image can't be added, the garbage collector deletes its files
+	productID := uint(upload.ProductID) //#nosec G115 -- id from the database
+	return s.images.AddImage(ctx, productID, *stored, upload.AltText.String)
+}
+
+// abandon gives up a claimed upload after processing failed with err, which
+// it returns. A file that isn't an acceptable image, or a product deleted
+// meanwhile, fails the upload and its file is deleted; after any other error
+// it goes back to pending so completing it can be retried. Both happen even
+// when the request was cancelled.
+func (s *ImageUploadService) abandon(ctx context.Context, upload db.ProductImageUpload, err error) error {
+	ctx = context.WithoutCancel(ctx)
+
+	if !errors.Is(err, ErrUnsupportedImage) && !errors.Is(err, ErrImageTooLarge) &&
+		!errors.Is(err, ErrImageDimensions) && !errors.Is(err, ErrProductNotFound) {
+		if releaseErr := s.store.ReleaseProductImageUpload(ctx, db.ReleaseProductImageUploadParams{
+			ID:        upload.ID,
+			ClaimedAt: upload.ClaimedAt,
+		}); releaseErr != nil {
+			return fmt.Errorf("failed to release image upload: %w", releaseErr)
+		}
+		return err
+	}
+
+	if failErr := s.store.FailProductImageUpload(ctx, db.FailProductImageUploadParams{
+		ID:        upload.ID,
+		ClaimedAt: upload.ClaimedAt,
+	}); failErr != nil {
+		return fmt.Errorf("failed to mark image upload failed: %w", failErr)
+	}
+	s.deleteIncoming(ctx, upload)
+	return err
+}
+
+// deleteIncoming deletes an upload's raw file, which is no longer needed
+// once the upload is processed or abandoned
+func (s *ImageUploadService) deleteIncoming(ctx context.Context, upload db.ProductImageUpload) {
+	_ = s.provider.Delete(context.WithoutCancel(ctx), upload.StoragePath)
+}
+
+func toImageUploadResponse(upload db.ProductImageUpload) *dto.ImageUploadResponse {
+	return &dto.ImageUploadResponse{
+		ID:        uint(upload.ID),        //#nosec G115 -- id from the database
+		ProductID: uint(upload.ProductID), //#nosec G115 -- id from the database
+		Status:    string(upload.Status),
+		ExpiresAt: upload.ExpiresAt.Time,
+		CreatedAt: upload.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+)
+
+const incomingPath = "incoming/products/1/upload"
+
+func newImageUploadService(store db.Store, provider interfaces.Upload) *ImageUploadService {
+	return NewImageUploadService(store, provider, NewUploadService(store, provider, testImageLimits),
+		NewProductImageService(store, nil), 15*time.Minute)
+}
+
+func pendingUpload() db.ProductImageUpload {
+	return db.ProductImageUpload{
+		ID:          7,
+		ProductID:   1,
+		StoragePath: incomingPath,
+		ContentType: "image/png",
+		MaxBytes:    1 << 16,
+		AltText:     pgtype.Text{String: "Front", Valid: true},
+		Status:      db.ImageUploadStatusPending,
+	}
+}
+
+// claimedUpload returns pendingUpload as claimed for processing
+func claimedUpload() db.ProductImageUpload {
+	upload := pendingUpload()
+	upload.Status = db.ImageUploadStatusProcessing
+	upload.ClaimedAt = pgtype.Timestamptz{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true}
+	return upload
+}
+
+// uploadedProvider returns a provider with data uploaded for pendingUpload
+func uploadedProvider(t *testing.T, data []byte) *providers.MemoryUploadProvider {
+	t.Helper()
+
+	provider := providers.NewMemoryUploadProvider()
+	_, err := provider.Put(context.Background(), incomingPath, bytes.NewReader(data), dto.FileMetadata{ContentType: "image/png", Size: int64(len(data))})
+	require.NoError(t, err)
+	return provider
+}
+
+// assertNoProductFiles asserts that no processed image was left stored
+func assertNoProductFiles(t *testing.T, provider *providers.MemoryUploadProvider) {
+	t.Helper()
+
+	files, err := provider.List(context.Background(), "images/")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestImageUploadService_CreateUpload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("presigns an upload under incoming", func(t *testing.T) {
+		t.Parallel()
+
+		expiresAt := time.Now().Add(15 * time.Minute)
+		presigned := &dto.PresignedUpload{Method: dto.UploadMethodPost, URL: "http://localhost:4566/ecommerce-uploads", ExpiresAt: expiresAt}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(db.Product{ID: 1}, nil)
+		mockStore.On("CreateProductImageUpload", mock.Anything, mock.MatchedBy(func(arg db.CreateProductImageUploadParams) bool {
+			return arg.ProductID == 1 && arg.ContentType == "image/png" && arg.MaxBytes == 5000 &&
+				arg.AltText.String == "Front" && arg.CreatedBy.Int32 == 9 && arg.ExpiresAt.Time.Equal(expiresAt)
+		})).Return(db.ProductImageUpload{ID: 7, ProductID: 1, Status: db.ImageUploadStatusPending}, nil)
+
+		mockProvider := new(MockUploadProvider)
+		mockProvider.On("PresignUpload", mock.Anything, dto.UploadMethodPost, mock.MatchedBy(func(path string) bool {
+			return assert.Regexp(t, `^incoming/products/1/[0-9a-f-]{36}$`, path)
+		}), "image/png", int64(5000), 15*time.Minute).Return(presigned, nil)
+
+		service := newImageUploadService(mockStore, mockProvider)
+		upload, err := service.CreateUpload(context.Background(), 9, 1, dto.CreateImageUploadRequest{
+			ContentType: "image/png",
+			Size:        5000,
+			Method:      dto.UploadMethodPost,
+			AltText:     "Front",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(7), upload.ID)
+		assert.Equal(t, "pending", upload.Status)
+		assert.Same(t, presigned, upload.Upload)
+	})
+
+	t.Run("defaults to PUT", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(db.Product{ID: 1}, nil)
+		mockStore.On("CreateProductImageUpload", mock.Anything, mock.Anything).Return(db.ProductImageUpload{ID: 7}, nil)
+
+		mockProvider := new(MockUploadProvider)
+		mockProvider.On("PresignUpload", mock.Anything, dto.UploadMethodPut, mock.Anything, "image/jpeg", int64(100), mock.Anything).
+			Return(&dto.PresignedUpload{Method: dto.UploadMethodPut}, nil)
+
+		service := newImageUploadService(mockStore, mockProvider)
+		_, err := service.CreateUpload(context.Background(), 9, 1, dto.CreateImageUploadRequest{ContentType: "image/jpeg", Size: 100})
+		require.NoError(t, err)
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("rejects files over the size limit", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockProvider := new(MockUploadProvider)
+
+		service := newImageUploadService(mockStore, mockProvider)
+		_, err := service.CreateUpload(context.Background(), 9, 1, dto.CreateImageUploadRequest{
+			ContentType: "image/png",
+			Size:        testImageLimits.MaxBytes + 1,
+		})
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+		mockProvider.AssertNotCalled(t, "PresignUpload", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(db.Product{}, pgx.ErrNoRows)
+
+		service := newImageUploadService(mockStore, new(MockUploadProvider))
+		_, err := service.CreateUpload(context.Background(), 9, 1, dto.CreateImageUploadRequest{ContentType: "image/png", Size: 100})
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
+
+func TestImageUploadService_CompleteUpload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("processes the upload into a product image", func(t *testing.T) {
+		t.Parallel()
+
+		claimed := claimedUpload()
+		completed := claimed
+		completed.Status = db.ImageUploadStatusCompleted
+		completed.ImageID = pgtype.Int4{Int32: 3, Valid: true}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+		mockStore.On("ClaimProductImageUpload", mock.Anything, mock.MatchedBy(func(arg db.ClaimProductImageUploadParams) bool {
+			// Claims older than the reclaim timeout were abandoned
+			reclaimAfter := time.Since(arg.ReclaimBefore.Time)
+			return arg.ID == 7 && arg.ProductID == 1 && reclaimAfter >= imageUploadReclaimAfter && reclaimAfter < imageUploadReclaimAfter+time.Minute
+		})).Return(claimed, nil)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(db.Product{ID: 1}, nil)
+		mockStore.On("ListImageBlobVariants", mock.Anything, mock.Anything).Return([]db.ProductImageVariant{}, nil)
+		mockStore.On("CreateProductImage", mock.Anything, mock.MatchedBy(func(arg db.CreateProductImageParams) bool {
+			return arg.AltText.String == "Front" && len(arg.VariantNames) == 7 && arg.ContentHash.Valid
+		})).Return(db.ProductImage{ID: 3, ProductID: 1, Url: "/uploads/images/ab/ab.png"}, nil)
+		mockStore.On("CompleteProductImageUpload", mock.Anything, db.CompleteProductImageUploadParams{
+			ID:        7,
+			ImageID:   pgtype.Int4{Int32: 3, Valid: true},
+			ClaimedAt: claimed.ClaimedAt,
+		}).Return(completed, nil)
+
+		provider := uploadedProvider(t, encodePNG(t, 100, 100))
+
+		service := newImageUploadService(mockStore, provider)
+		response, err := service.CompleteUpload(context.Background(), 1, 7)
+		require.NoError(t, err)
+
+		assert.Equal(t, "completed", response.Status)
+		require.NotNil(t, response.Image)
+		assert.Equal(t, uint(3), response.Image.ID)
+		_, err = provider.Stat(context.Background(), incomingPath)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		files, err := provider.List(context.Background(), "images/")
+		require.NoError(t, err)
+		assert.Len(t, files, 7)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("file not uploaded yet", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+
+		service := newImageUploadService(mockStore, providers.NewMemoryUploadProvider())
+		_, err := service.CompleteUpload(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrImageNotUploaded)
+		// It can be completed once the file is there
+		mockStore.AssertNotCalled(t, "ClaimProductImageUpload", mock.Anything, mock.Anything)
+	})
+
+	t.Run("file larger than declared", func(t *testing.T) {
+		t.Parallel()
+
+		upload := claimedUpload()
+		upload.MaxBytes = 10
+		failed := db.FailProductImageUploadParams{ID: 7, ClaimedAt: upload.ClaimedAt}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+		mockStore.On("ClaimProductImageUpload", mock.Anything, mock.Anything).Return(upload, nil)
+		mockStore.On("FailProductImageUpload", mock.Anything, failed).Return(nil)
+
+		provider := uploadedProvider(t, encodePNG(t, 100, 100))
+
+		service := newImageUploadService(mockStore, provider)
+		_, err := service.CompleteUpload(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+		mockStore.AssertCalled(t, "FailProductImageUpload", mock.Anything, failed)
+		_, err = provider.Stat(context.Background(), incomingPath)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assertNoProductFiles(t, provider)
+	})
+
+	t.Run("fails uploads that are not images", func(t *testing.T) {
+		t.Parallel()
+
+		failed := db.FailProductImageUploadParams{ID: 7, ClaimedAt: claimedUpload().ClaimedAt}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+		mockStore.On("ClaimProductImageUpload", mock.Anything, mock.Anything).Return(claimedUpload(), nil)
+		mockStore.On("FailProductImageUpload", mock.Anything, failed).Return(nil)
+
+		provider := uploadedProvider(t, []byte("%PDF-1.7\n"))
+
+		service := newImageUploadService(mockStore, provider)
+		_, err := service.CompleteUpload(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrUnsupportedImage)
+		mockStore.AssertCalled(t, "FailProductImageUpload", mock.Anything, failed)
+		mockStore.AssertNotCalled(t, "ReleaseProductImageUpload", mock.Anything, mock.Anything)
+		_, err = provider.Stat(context.Background(), incomingPath)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assertNoProductFiles(t, provider)
+	})
+
+	t.Run("returns the upload to pending after other errors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+		mockStore.On("ClaimProductImageUpload", mock.Anything, mock.Anything).Return(claimedUpload(), nil)
+		mockStore.On("ListImageBlobVariants", mock.Anything, mock.Anything).Return([]db.ProductImageVariant{}, nil)
+		// The client goes away while the image is added
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(context.Canceled).Run(func(mock.Arguments) { cancel() })
+		mockStore.On("ReleaseProductImageUpload", mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Err() == nil
+		}), db.ReleaseProductImageUploadParams{ID: 7, ClaimedAt: claimedUpload().ClaimedAt}).Return(nil)
+
+		provider := uploadedProvider(t, encodePNG(t, 100, 100))
+
+		service := newImageUploadService(mockStore, provider)
+		_, err := service.CompleteUpload(ctx, 1, 7)
+		assert.ErrorIs(t, err, context.Canceled)
+		mockStore.AssertNotCalled(t, "FailProductImageUpload", mock.Anything, mock.Anything)
+		mockStore.AssertExpectations(t)
+		// The file is kept so completing it can be retried
+		_, err = provider.Stat(context.Background(), incomingPath)
+		require.NoError(t, err)
+	})
+
+	t.Run("another product's upload", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+
+		service := newImageUploadService(mockStore, new(MockUploadProvider))
+		_, err := service.CompleteUpload(context.Background(), 2, 7)
+		assert.ErrorIs(t, err, ErrImageUploadNotFound)
+	})
+
+	t.Run("already completed", func(t *testing.T) {
+		t.Parallel()
+
+		upload := pendingUpload()
+		upload.Status = db.ImageUploadStatusCompleted
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(upload, nil)
+
+		service := newImageUploadService(mockStore, new(MockUploadProvider))
+		_, err := service.CompleteUpload(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrImageUploadNotPending)
+	})
+
+	t.Run("being processed by another request", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(claimedUpload(), nil)
+		mockStore.On("ClaimProductImageUpload", mock.Anything, mock.Anything).Return(db.ProductImageUpload{}, pgx.ErrNoRows)
+
+		service := newImageUploadService(mockStore, uploadedProvider(t, encodePNG(t, 100, 100)))
+		_, err := service.CompleteUpload(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrImageUploadNotPending)
+	})
+
+	t.Run("completed concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductImageUpload", mock.Anything, int32(7)).Return(pendingUpload(), nil)
+		mockStore.On("ClaimProductImageUpload", mock.Anything, mock.Anything).Return(db.ProductImageUpload{}, pgx.ErrNoRows)
+
+		provider := uploadedProvider(t, encodePNG(t, 100, 100))
+
+		service := newImageUploadService(mockStore, provider)
+		_, err := service.CompleteUpload(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrImageUploadNotPending)
+		assertNoProductFiles(t, provider)
+	})
+}
+
+func TestImageUploadService_ExpireUploads(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("ExpireProductImageUploads", mock.Anything, mock.MatchedBy(func(arg db.ExpireProductImageUploadsParams) bool {
+		// Uploads get as long again as their URL worked to be completed,
+		// and as long as a claim lasts again once processing is abandoned
+		return time.Until(arg.Cutoff.Time) < -14*time.Minute &&
+			arg.Cutoff.Time.Sub(arg.AbandonedBefore.Time) == imageUploadReclaimAfter
+	})).Return([]db.ProductImageUpload{
+		{ID: 1, StoragePath: "incoming/products/1/a"},
+		{ID: 2, StoragePath: "incoming/products/2/b"},
+	}, nil)
+
+	mockProvider := new(MockUploadProvider)
+	mockProvider.On("Delete", mock.Anything, "incoming/products/1/a").Return(nil)
+	mockProvider.On("Delete", mock.Anything, "incoming/products/2/b").Return(fmt.Errorf("remove: %w", fs.ErrNotExist))
+
+	service := newImageUploadService(mockStore, mockProvider)
+	expired, err := service.ExpireUploads(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, expired)
+	mockProvider.AssertExpectations(t)
+}
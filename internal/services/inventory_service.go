@@ -0,0 +1,490 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+var (
+	ErrInvalidStockAdjustment = errors.New("stock adjustment must be non-zero and not drive stock below zero")
+	ErrInvalidMovementReason  = errors.New("invalid inventory movement reason")
+	ErrWarehouseNotFound      = errors.New("warehouse not found")
+	ErrWarehouseExists        = errors.New("warehouse code already exists")
+	ErrCategoryNotFound       = errors.New("category not found")
+)
+
+type InventoryService struct {
+	store   db.Store
+	alerter *StockAlerter
+}
+
+// NewInventoryService creates an InventoryService. A nil alerter disables
+// low-stock alerts.
+func NewInventoryService(store db.Store, alerter *StockAlerter) *InventoryService {
+	return &InventoryService{
+		store:   store,
+		alerter: alerter,
+	}
+}
+
+// stockMovement describes a single change to a product's on-hand stock
+type stockMovement struct {
+	ProductID   int32
+	WarehouseID int32 // 0 when the change is not tied to a warehouse
+	Delta       int32
+	Reason      db.InventoryMovementReason
+	ReferenceID string
+	ActorID     int32 // 0 for system-initiated changes
+	Note        string
+}
+
+// applyStockMovement adjusts a product's stock (and its warehouse's stock, when
+// set) by the movement delta and records the change in the inventory ledger.
+// Callers should run it inside a transaction so the stock columns and the
+// ledger never drift apart.
+func applyStockMovement(ctx context.Context, q db.Querier, m stockMovement) (db.InventoryMovement, error) {
+	product, err := q.AdjustProductStock(ctx, db.AdjustProductStockParams{
+		Delta: m.Delta,
+		ID:    m.ProductID,
+	})
+	if err != nil {
+		return db.InventoryMovement{}, err
+	}
+
+	if m.WarehouseID != 0 {
+		_, err = q.AdjustWarehouseStock(ctx, db.AdjustWarehouseStockParams{
+			WarehouseID: m.WarehouseID,
+			ProductID:   m.ProductID,
+			Delta:       m.Delta,
+		})
+		if err != nil {
+			return db.InventoryMovement{}, fmt.Errorf("failed to adjust warehouse stock: %w", err)
+		}
+	}
+
+	return recordStockMovement(ctx, q, m, product.Stock.Int32)
+}
+
+// applyWarehouseStockMovement applies m against its warehouse, or the default
+// warehouse when none is set, rejecting changes that would take the warehouse
+// below zero. It must run inside a transaction.
+func applyWarehouseStockMovement(ctx context.Context, q db.Querier, m stockMovement) (db.InventoryMovement, error) {
+	if m.WarehouseID == 0 {
+		warehouse, err := q.GetDefaultWarehouse(ctx)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return db.InventoryMovement{}, ErrWarehouseNotFound
+			}
+			return db.InventoryMovement{}, fmt.Errorf("failed to get default warehouse: %w", err)
+		}
+		m.WarehouseID = warehouse.ID
+	}
+
+	if m.Delta < 0 {
+		stock, err := q.GetWarehouseStockForUpdate(ctx, db.GetWarehouseStockForUpdateParams{
+			WarehouseID: m.WarehouseID,
+			ProductID:   m.ProductID,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return db.InventoryMovement{}, fmt.Errorf("failed to lock warehouse stock: %w", err)
+		}
+		if stock.Quantity+m.Delta < 0 {
+			return db.InventoryMovement{}, ErrInvalidStockAdjustment
+		}
+	}
+
+	return applyStockMovement(ctx, q, m)
+}
+
+// recordStockMovement appends a ledger entry for a stock change that has already been applied
+func recordStockMovement(ctx context.Context, q db.Querier, m stockMovement, stockAfter int32) (db.InventoryMovement, error) {
+	movement, err := q.CreateInventoryMovement(ctx, db.CreateInventoryMovementParams{
+		ProductID:      m.ProductID,
+		Reason:         m.Reason,
+		ReferenceID:    pgtype.Text{String: m.ReferenceID, Valid: m.ReferenceID != ""},
+		ActorID:        pgtype.Int4{Int32: m.ActorID, Valid: m.ActorID != 0},
+		QuantityChange: m.Delta,
+		StockAfter:     stockAfter,
+		Note:           pgtype.Text{String: m.Note, Valid: m.Note != ""},
+		WarehouseID:    pgtype.Int4{Int32: m.WarehouseID, Valid: m.WarehouseID != 0},
+	})
+	if err != nil {
+		return db.InventoryMovement{}, fmt.Errorf("failed to record inventory movement: %w", err)
+	}
+	return movement, nil
+}
+
+// GetMovementsBySKU returns the ledger history for a product, newest first
+func (s *InventoryService) GetMovementsBySKU(ctx context.Context, sku string, page, limit int) ([]dto.InventoryMovementResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	product, err := s.store.GetProductBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrProductNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	totalCount, err := s.store.CountInventoryMovementsByProduct(ctx, product.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count inventory movements: %w", err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit > 0 {
+		totalPages++
+	}
+
+	movements, err := s.store.ListInventoryMovementsByProduct(ctx, db.ListInventoryMovementsByProductParams{
+		ProductID: product.ID,
+		Limit:     int32(limit),              //#nosec G115 -- pagination values are bounded
+		Offset:    int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list inventory movements: %w", err)
+	}
+
+	responses := make([]dto.InventoryMovementResponse, len(movements))
+	for i, m := range movements {
+		responses[i] = toInventoryMovementResponse(m, product.Sku)
+	}
+
+	return responses, &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: int(totalCount),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// AdjustStock applies a manual stock correction to the product with the given SKU
+func (s *InventoryService) AdjustStock(ctx context.Context, actorID int32, sku string, req dto.StockAdjustmentRequest) (*dto.InventoryMovementResponse, error) {
+	reason := db.InventoryMovementReasonManualAdjustment
+	if req.Reason != "" {
+		reason = db.InventoryMovementReason(req.Reason)
+	}
+	if !isManualMovementReason(reason) {
+		return nil, ErrInvalidMovementReason
+	}
+	if req.QuantityChange == 0 {
+		return nil, ErrInvalidStockAdjustment
+	}
+
+	var movement db.InventoryMovement
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		product, err := q.GetProductBySKU(ctx, sku)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrProductNotFound
+			}
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		var warehouseID int32
+		if req.WarehouseCode != "" {
+			warehouse, err := q.GetWarehouseByCode(ctx, req.WarehouseCode)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrWarehouseNotFound
+				}
+				return fmt.Errorf("failed to get warehouse: %w", err)
+			}
+			warehouseID = warehouse.ID
+		}
+
+		movement, err = applyWarehouseStockMovement(ctx, q, stockMovement{
+			ProductID:   product.ID,
+			WarehouseID: warehouseID,
+			Delta:       req.QuantityChange,
+			Reason:      reason,
+			ReferenceID: req.ReferenceID,
+			ActorID:     actorID,
+			Note:        req.Note,
+		})
+		if err != nil {
+			if errors.Is(err, ErrInvalidStockAdjustment) || errors.Is(err, ErrWarehouseNotFound) {
+				return err
+			}
+			return fmt.Errorf("failed to adjust product stock: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.alerter.Notify(ctx, movement)
+
+	resp := toInventoryMovementResponse(movement, sku)
+	return &resp, nil
+}
+
+// Reconcile lists products whose stock column disagrees with the sum of their
+// ledger entries or with the stock held across their warehouses
+func (s *InventoryService) Reconcile(ctx context.Context) ([]dto.StockDiscrepancyResponse, error) {
+	rows, err := s.store.ListStockDiscrepancies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock discrepancies: %w", err)
+	}
+
+	discrepancies := make([]dto.StockDiscrepancyResponse, len(rows))
+	for i, row := range rows {
+		discrepancies[i] = dto.StockDiscrepancyResponse{
+			ProductID:      uint(row.ID), //#nosec G115 -- DB ID is always positive
+			SKU:            row.Sku,
+			Stock:          int(row.Stock),
+			LedgerStock:    int(row.LedgerStock),
+			WarehouseStock: int(row.WarehouseStock),
+			Difference:     int(row.Stock - row.LedgerStock),
+		}
+	}
+
+	return discrepancies, nil
+}
+
+// ListWarehouses returns every warehouse in fulfilment priority order
+func (s *InventoryService) ListWarehouses(ctx context.Context) ([]dto.WarehouseResponse, error) {
+	warehouses, err := s.store.ListWarehouses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouses: %w", err)
+	}
+
+	responses := make([]dto.WarehouseResponse, len(warehouses))
+	for i, w := range warehouses {
+		responses[i] = toWarehouseResponse(w)
+	}
+	return responses, nil
+}
+
+// CreateWarehouse registers a new fulfilment location
+func (s *InventoryService) CreateWarehouse(ctx context.Context, req dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error) {
+	if _, err := s.store.GetWarehouseByCode(ctx, req.Code); err == nil {
+		return nil, ErrWarehouseExists
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check warehouse code: %w", err)
+	}
+
+	warehouse, err := s.store.CreateWarehouse(ctx, db.CreateWarehouseParams{
+		Code:       req.Code,
+		Name:       req.Name,
+		Country:    strings.ToUpper(req.Country),
+		PostalCode: pgtype.Text{String: req.PostalCode, Valid: req.PostalCode != ""},
+		Latitude:   optionalFloat8(req.Latitude),
+		Longitude:  optionalFloat8(req.Longitude),
+		Priority:   req.Priority,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create warehouse: %w", err)
+	}
+
+	resp := toWarehouseResponse(warehouse)
+	return &resp, nil
+}
+
+// GetWarehouseStockBySKU returns how a product's stock is spread across warehouses
+func (s *InventoryService) GetWarehouseStockBySKU(ctx context.Context, sku string) ([]dto.WarehouseStockResponse, error) {
+	product, err := s.store.GetProductBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	rows, err := s.store.ListWarehouseStockByProduct(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouse stock: %w", err)
+	}
+
+	responses := make([]dto.WarehouseStockResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = dto.WarehouseStockResponse{
+			WarehouseID:   uint(row.WarehouseID), //#nosec G115 -- DB ID is always positive
+			WarehouseCode: row.Code,
+			WarehouseName: row.Name,
+			Quantity:      int(row.Quantity),
+			UpdatedAt:     row.UpdatedAt.Time,
+		}
+	}
+	return responses, nil
+}
+
+// ListLowStock returns every product below its reorder threshold, and every
+// product out of stock, emptiest first
+func (s *InventoryService) ListLowStock(ctx context.Context) ([]dto.LowStockResponse, error) {
+	rows, err := s.store.ListProductsBelowThreshold(ctx, s.defaultThreshold())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list low stock products: %w", err)
+	}
+
+	responses := make([]dto.LowStockResponse, len(rows))
+	for i, row := range rows {
+		status := notifications.NotificationTypeStockLow
+		if row.Stock <= 0 {
+			status = notifications.NotificationTypeStockOut
+		}
+		responses[i] = dto.LowStockResponse{
+			ProductID: uint(row.ID), //#nosec G115 -- DB ID is always positive
+			SKU:       row.Sku,
+			Name:      row.Name,
+			Stock:     int(row.Stock),
+			Threshold: int(row.Threshold),
+			Status:    string(status),
+		}
+	}
+	return responses, nil
+}
+
+// SetProductReorderThreshold overrides the reorder threshold for one product,
+// or removes the override when req.Threshold is nil
+func (s *InventoryService) SetProductReorderThreshold(ctx context.Context, sku string, req dto.ReorderThresholdRequest) (*dto.ReorderThresholdResponse, error) {
+	product, err := s.store.GetProductBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	productID := pgtype.Int4{Int32: product.ID, Valid: true}
+	responseID := uint(product.ID) //#nosec G115 -- DB ID is always positive
+	resp := &dto.ReorderThresholdResponse{ProductID: &responseID}
+
+	if req.Threshold == nil {
+		if err := s.store.DeleteProductReorderThreshold(ctx, productID); err != nil {
+			return nil, fmt.Errorf("failed to remove reorder threshold: %w", err)
+		}
+		return resp, nil
+	}
+
+	threshold, err := s.store.UpsertProductReorderThreshold(ctx, db.UpsertProductReorderThresholdParams{
+		ProductID: productID,
+		Threshold: *req.Threshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set reorder threshold: %w", err)
+	}
+
+	value := int(threshold.Threshold)
+	resp.Threshold = &value
+	return resp, nil
+}
+
+// SetCategoryReorderThreshold sets the reorder threshold for every product in
+// a category without its own override, or removes it when req.Threshold is nil
+func (s *InventoryService) SetCategoryReorderThreshold(ctx context.Context, categoryID int32, req dto.ReorderThresholdRequest) (*dto.ReorderThresholdResponse, error) {
+	if _, err := s.store.GetCategoryByID(ctx, categoryID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	id := pgtype.Int4{Int32: categoryID, Valid: true}
+	responseID := uint(categoryID) //#nosec G115 -- DB ID is always positive
+	resp := &dto.ReorderThresholdResponse{CategoryID: &responseID}
+
+	if req.Threshold == nil {
+		if err := s.store.DeleteCategoryReorderThreshold(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to remove reorder threshold: %w", err)
+		}
+		return resp, nil
+	}
+
+	threshold, err := s.store.UpsertCategoryReorderThreshold(ctx, db.UpsertCategoryReorderThresholdParams{
+		CategoryID: id,
+		Threshold:  *req.Threshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set reorder threshold: %w", err)
+	}
+
+	value := int(threshold.Threshold)
+	resp.Threshold = &value
+	return resp, nil
+}
+
+// defaultThreshold is the threshold for products with no product or category override
+func (s *InventoryService) defaultThreshold() int32 {
+	if s.alerter == nil {
+		return 0
+	}
+	return s.alerter.defaultThreshold
+}
+
+func toWarehouseResponse(w db.Warehouse) dto.WarehouseResponse {
+	resp := dto.WarehouseResponse{
+		ID:         uint(w.ID), //#nosec G115 -- DB ID is always positive
+		Code:       w.Code,
+		Name:       w.Name,
+		Country:    w.Country,
+		PostalCode: w.PostalCode.String,
+		Priority:   int(w.Priority),
+		IsActive:   w.IsActive,
+		CreatedAt:  w.CreatedAt.Time,
+	}
+	if w.Latitude.Valid {
+		resp.Latitude = &w.Latitude.Float64
+	}
+	if w.Longitude.Valid {
+		resp.Longitude = &w.Longitude.Float64
+	}
+	return resp
+}
+
+func optionalFloat8(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
+
+func toInventoryMovementResponse(m db.InventoryMovement, sku string) dto.InventoryMovementResponse {
+	resp := dto.InventoryMovementResponse{
+		ID:             uint(m.ID),        //#nosec G115 -- DB ID is always positive
+		ProductID:      uint(m.ProductID), //#nosec G115 -- DB ID is always positive
+		SKU:            sku,
+		Reason:         string(m.Reason),
+		ReferenceID:    m.ReferenceID.String,
+		QuantityChange: int(m.QuantityChange),
+		StockAfter:     int(m.StockAfter),
+		Note:           m.Note.String,
+		CreatedAt:      m.CreatedAt.Time,
+	}
+	if m.WarehouseID.Valid {
+		warehouseID := uint(m.WarehouseID.Int32) //#nosec G115 -- DB ID is always positive
+		resp.WarehouseID = &warehouseID
+	}
+	if m.ActorID.Valid {
+		actorID := uint(m.ActorID.Int32) //#nosec G115 -- DB ID is always positive
+		resp.ActorID = &actorID
+	}
+	return resp
+}
+
+// isManualMovementReason reports whether an admin may record the reason directly;
+// order and cancellation movements are only written by the order flow
+func isManualMovementReason(reason db.InventoryMovementReason) bool {
+	switch reason {
+	case db.InventoryMovementReasonManualAdjustment,
+		db.InventoryMovementReasonImport,
+		db.InventoryMovementReasonReturn:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+func TestInventoryService_GetMovementsBySKU(t *testing.T) {
+	t.Parallel()
+
+	testProduct := createTestProduct()
+	movements := []db.InventoryMovement{
+		{
+			ID:             2,
+			ProductID:      1,
+			Reason:         db.InventoryMovementReasonOrder,
+			ReferenceID:    pgtype.Text{String: "42", Valid: true},
+			ActorID:        pgtype.Int4{Int32: 7, Valid: true},
+			QuantityChange: -2,
+			StockAfter:     8,
+		},
+		{
+			ID:             1,
+			ProductID:      1,
+			Reason:         db.InventoryMovementReasonImport,
+			QuantityChange: 10,
+			StockAfter:     10,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		sku       string
+		setupMock func(m *mocks.MockStore)
+		wantLen   int
+		wantErr   error
+	}{
+		{
+			name: "success - returns movements",
+			sku:  "TEST-001",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductBySKU", mock.Anything, "TEST-001").Return(testProduct, nil)
+				m.On("CountInventoryMovementsByProduct", mock.Anything, int32(1)).Return(int64(2), nil)
+				m.On("ListInventoryMovementsByProduct", mock.Anything, db.ListInventoryMovementsByProductParams{
+					ProductID: 1,
+					Limit:     20,
+					Offset:    0,
+				}).Return(movements, nil)
+			},
+			wantLen: 2,
+		},
+		{
+			name: "error - unknown sku",
+			sku:  "MISSING",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductBySKU", mock.Anything, "MISSING").Return(db.Product{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewInventoryService(mockStore, nil)
+
+			resp, meta, err := service.GetMovementsBySKU(context.Background(), tt.sku, 1, 0)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, resp, tt.wantLen)
+			assert.Equal(t, 1, meta.TotalPages)
+			assert.Equal(t, "order", resp[0].Reason)
+			require.NotNil(t, resp[0].ActorID)
+			assert.Equal(t, uint(7), *resp[0].ActorID)
+			assert.Nil(t, resp[1].ActorID)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryService_AdjustStock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		req       dto.StockAdjustmentRequest
+		setupMock func(m *mocks.MockStore)
+		wantErr   error
+	}{
+		{
+			name: "success - adjustment committed",
+			req:  dto.StockAdjustmentRequest{QuantityChange: 5, Note: "recount"},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetProductBySKU", mock.Anything, "TEST-001").Return(db.Product{ID: 1, Sku: "TEST-001"}, nil)
+				m.On("GetDefaultWarehouse", mock.Anything).Return(db.Warehouse{ID: 3}, nil)
+				m.On("AdjustProductStock", mock.Anything, db.AdjustProductStockParams{Delta: 5, ID: 1}).
+					Return(db.Product{ID: 1, Stock: pgtype.Int4{Int32: 15, Valid: true}}, nil)
+				m.On("AdjustWarehouseStock", mock.Anything, db.AdjustWarehouseStockParams{WarehouseID: 3, ProductID: 1, Delta: 5}).
+					Return(db.WarehouseStock{}, nil)
+				m.On("CreateInventoryMovement", mock.Anything, mock.MatchedBy(func(arg db.CreateInventoryMovementParams) bool {
+					return arg.QuantityChange == 5 && arg.StockAfter == 15 && arg.WarehouseID.Int32 == 3 &&
+						arg.Reason == db.InventoryMovementReasonManualAdjustment
+				})).Return(db.InventoryMovement{ID: 1, ProductID: 1, QuantityChange: 5, StockAfter: 15}, nil)
+			},
+		},
+		{
+			name: "error - warehouse would go negative",
+			req:  dto.StockAdjustmentRequest{QuantityChange: -3},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetProductBySKU", mock.Anything, "TEST-001").Return(db.Product{ID: 1, Sku: "TEST-001"}, nil)
+				m.On("GetDefaultWarehouse", mock.Anything).Return(db.Warehouse{ID: 3}, nil)
+				m.On("GetWarehouseStockForUpdate", mock.Anything, db.GetWarehouseStockForUpdateParams{WarehouseID: 3, ProductID: 1}).
+					Return(db.WarehouseStock{Quantity: 2}, nil)
+			},
+			wantErr: ErrInvalidStockAdjustment,
+		},
+		{
+			name:      "error - zero quantity",
+			req:       dto.StockAdjustmentRequest{QuantityChange: 0},
+			setupMock: func(m *mocks.MockStore) {},
+			wantErr:   ErrInvalidStockAdjustment,
+		},
+		{
+			name:      "error - system-only reason",
+			req:       dto.StockAdjustmentRequest{QuantityChange: 1, Reason: "order"},
+			setupMock: func(m *mocks.MockStore) {},
+			wantErr:   ErrInvalidMovementReason,
+		},
+		{
+			name: "error - transaction fails",
+			req:  dto.StockAdjustmentRequest{QuantityChange: -3},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(ErrInvalidStockAdjustment)
+			},
+			wantErr: ErrInvalidStockAdjustment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewInventoryService(mockStore, nil)
+
+			resp, err := service.AdjustStock(context.Background(), 1, "TEST-001", tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertExpectations(t)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, resp)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryService_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - reports drift", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListStockDiscrepancies", mock.Anything).Return([]db.ListStockDiscrepanciesRow{
+			{ID: 1, Sku: "TEST-001", Stock: 12, LedgerStock: 10},
+		}, nil)
+
+		service := NewInventoryService(mockStore, nil)
+
+		resp, err := service.Reconcile(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, resp, 1)
+		assert.Equal(t, 2, resp[0].Difference)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("error - database error", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListStockDiscrepancies", mock.Anything).Return([]db.ListStockDiscrepanciesRow(nil), errors.New("db error"))
+
+		service := NewInventoryService(mockStore, nil)
+
+		_, err := service.Reconcile(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestInventoryService_CreateWarehouse(t *testing.T) {
+	t.Parallel()
+
+	req := dto.CreateWarehouseRequest{Code: "WEST", Name: "West Coast", Country: "us", PostalCode: "90001", Priority: 1}
+
+	tests := []struct {
+		name      string
+		setupMock func(m *mocks.MockStore)
+		wantErr   error
+	}{
+		{
+			name: "success - warehouse created",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetWarehouseByCode", mock.Anything, "WEST").Return(db.Warehouse{}, pgx.ErrNoRows)
+				m.On("CreateWarehouse", mock.Anything, mock.MatchedBy(func(arg db.CreateWarehouseParams) bool {
+					return arg.Country == "US" && arg.PostalCode.String == "90001" && !arg.Latitude.Valid
+				})).Return(db.Warehouse{ID: 2, Code: "WEST", Name: "West Coast", Country: "US", Priority: 1, IsActive: true}, nil)
+			},
+		},
+		{
+			name: "error - duplicate code",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetWarehouseByCode", mock.Anything, "WEST").Return(db.Warehouse{ID: 2, Code: "WEST"}, nil)
+			},
+			wantErr: ErrWarehouseExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewInventoryService(mockStore, nil)
+
+			resp, err := service.CreateWarehouse(context.Background(), req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "WEST", resp.Code)
+			assert.Nil(t, resp.Latitude)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryService_SetProductReorderThreshold(t *testing.T) {
+	t.Parallel()
+
+	testProduct := createTestProduct()
+	threshold := int32(5)
+
+	tests := []struct {
+		name          string
+		req           dto.ReorderThresholdRequest
+		setupMock     func(m *mocks.MockStore)
+		wantThreshold *int
+		wantErr       error
+	}{
+		{
+			name: "success - override set",
+			req:  dto.ReorderThresholdRequest{Threshold: &threshold},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductBySKU", mock.Anything, "TEST-001").Return(testProduct, nil)
+				m.On("UpsertProductReorderThreshold", mock.Anything, db.UpsertProductReorderThresholdParams{
+					ProductID: pgtype.Int4{Int32: 1, Valid: true},
+					Threshold: 5,
+				}).Return(db.ReorderThreshold{ID: 1, ProductID: pgtype.Int4{Int32: 1, Valid: true}, Threshold: 5}, nil)
+			},
+			wantThreshold: func() *int { v := 5; return &v }(),
+		},
+		{
+			name: "success - override removed",
+			req:  dto.ReorderThresholdRequest{},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductBySKU", mock.Anything, "TEST-001").Return(testProduct, nil)
+				m.On("DeleteProductReorderThreshold", mock.Anything, pgtype.Int4{Int32: 1, Valid: true}).Return(nil)
+			},
+		},
+		{
+			name: "error - unknown sku",
+			req:  dto.ReorderThresholdRequest{Threshold: &threshold},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductBySKU", mock.Anything, "TEST-001").Return(db.Product{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewInventoryService(mockStore, nil)
+
+			resp, err := service.SetProductReorderThreshold(context.Background(), "TEST-001", tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantThreshold, resp.Threshold)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryService_ListLowStock(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("ListProductsBelowThreshold", mock.Anything, int32(3)).Return([]db.ListProductsBelowThresholdRow{
+		{ID: 2, Sku: "TEST-002", Name: "Sold Out", Stock: 0, Threshold: 3},
+		{ID: 1, Sku: "TEST-001", Name: "Running Low", Stock: 2, Threshold: 3},
+	}, nil)
+
+	service := NewInventoryService(mockStore, NewStockAlerter(mockStore, nil, 3))
+
+	resp, err := service.ListLowStock(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	assert.Equal(t, "stock_out", resp[0].Status)
+	assert.Equal(t, "stock_low", resp[1].Status)
+	mockStore.AssertExpectations(t)
+}
@@ -0,0 +1,586 @@
+package services
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+)
+
+// noopStore answers every query with zero values and no error. The hand
+// written store mocks embed it, so they only need to define the queries
+// their tests set expectations on.
+type noopStore struct{}
+
+func (noopStore) AddOrderRiskSignals(ctx context.Context, arg db.AddOrderRiskSignalsParams) error {
+	return nil
+}
+func (noopStore) AddProductAttributes(ctx context.Context, arg db.AddProductAttributesParams) error {
+	return nil
+}
+func (noopStore) AdjustProductStock(ctx context.Context, arg db.AdjustProductStockParams) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) AdjustWarehouseStock(ctx context.Context, arg db.AdjustWarehouseStockParams) (db.WarehouseStock, error) {
+	return db.WarehouseStock{}, nil
+}
+func (noopStore) ClaimAssistantConversation(ctx context.Context, arg db.ClaimAssistantConversationParams) (db.AssistantConversation, error) {
+	return db.AssistantConversation{}, nil
+}
+func (noopStore) ClaimBackInStockSubscribers(ctx context.Context, arg db.ClaimBackInStockSubscribersParams) ([]db.ClaimBackInStockSubscribersRow, error) {
+	return nil, nil
+}
+func (noopStore) ClaimProductImageUpload(ctx context.Context, arg db.ClaimProductImageUploadParams) (db.ProductImageUpload, error) {
+	return db.ProductImageUpload{}, nil
+}
+func (noopStore) ClearPrimaryProductImage(ctx context.Context, productID int32) error { return nil }
+func (noopStore) CompleteProductImageUpload(ctx context.Context, arg db.CompleteProductImageUploadParams) (db.ProductImageUpload, error) {
+	return db.ProductImageUpload{}, nil
+}
+func (noopStore) CountActiveProducts(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountCartItems(ctx context.Context, cartID int32) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountCategories(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountDemandForecasts(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountInventoryMovementsByProduct(ctx context.Context, productID int32) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountOrderItems(ctx context.Context, orderID int32) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountOrders(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountOrdersByStatus(ctx context.Context, status db.NullOrderStatus) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountOrdersByUserID(ctx context.Context, userID int32) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountProductReviews(ctx context.Context, productID int32) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountProducts(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountProductsByCategory(ctx context.Context, categoryID int32) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountRecentFailedLogins(ctx context.Context, arg db.CountRecentFailedLoginsParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountRecentOrdersByIP(ctx context.Context, arg db.CountRecentOrdersByIPParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountRecentOrdersByUser(ctx context.Context, arg db.CountRecentOrdersByUserParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountSemanticSearchProducts(ctx context.Context, arg db.CountSemanticSearchProductsParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CountUsers(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) CreateBackInStockSubscription(ctx context.Context, arg db.CreateBackInStockSubscriptionParams) (db.BackInStockSubscription, error) {
+	return db.BackInStockSubscription{}, nil
+}
+func (noopStore) CreateCart(ctx context.Context, userID int32) (db.Cart, error) {
+	return db.Cart{}, nil
+}
+func (noopStore) CreateCartItem(ctx context.Context, arg db.CreateCartItemParams) (db.CartItem, error) {
+	return db.CartItem{}, nil
+}
+func (noopStore) CreateCategory(ctx context.Context, arg db.CreateCategoryParams) (db.Category, error) {
+	return db.Category{}, nil
+}
+func (noopStore) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
+	return db.OrderIdempotencyKey{}, nil
+}
+func (noopStore) CreateInventoryMovement(ctx context.Context, arg db.CreateInventoryMovementParams) (db.InventoryMovement, error) {
+	return db.InventoryMovement{}, nil
+}
+func (noopStore) CreateOrder(ctx context.Context, arg db.CreateOrderParams) (db.Order, error) {
+	return db.Order{}, nil
+}
+func (noopStore) CreateOrderItem(ctx context.Context, arg db.CreateOrderItemParams) (db.OrderItem, error) {
+	return db.OrderItem{}, nil
+}
+func (noopStore) CreateOrderItemAllocation(ctx context.Context, arg db.CreateOrderItemAllocationParams) (db.OrderItemAllocation, error) {
+	return db.OrderItemAllocation{}, nil
+}
+func (noopStore) CreateOrderRiskAssessment(ctx context.Context, arg db.CreateOrderRiskAssessmentParams) (db.OrderRiskAssessment, error) {
+	return db.OrderRiskAssessment{}, nil
+}
+func (noopStore) CreateProduct(ctx context.Context, arg db.CreateProductParams) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) CreateProductContentDraft(ctx context.Context, arg db.CreateProductContentDraftParams) (db.ProductContentDraft, error) {
+	return db.ProductContentDraft{}, nil
+}
+func (noopStore) CreateProductImage(ctx context.Context, arg db.CreateProductImageParams) (db.ProductImage, error) {
+	return db.ProductImage{}, nil
+}
+func (noopStore) CreateProductImageUpload(ctx context.Context, arg db.CreateProductImageUploadParams) (db.ProductImageUpload, error) {
+	return db.ProductImageUpload{}, nil
+}
+func (noopStore) CreateProductPrice(ctx context.Context, arg db.CreateProductPriceParams) (db.ProductPrice, error) {
+	return db.ProductPrice{}, nil
+}
+func (noopStore) CreateProductReview(ctx context.Context, arg db.CreateProductReviewParams) (db.ProductReview, error) {
+	return db.ProductReview{}, nil
+}
+func (noopStore) CreateProductView(ctx context.Context, arg db.CreateProductViewParams) error {
+	return nil
+}
+func (noopStore) CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) (db.RefreshToken, error) {
+	return db.RefreshToken{}, nil
+}
+func (noopStore) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	return db.User{}, nil
+}
+func (noopStore) CreateWarehouse(ctx context.Context, arg db.CreateWarehouseParams) (db.Warehouse, error) {
+	return db.Warehouse{}, nil
+}
+func (noopStore) DeleteCategoryReorderThreshold(ctx context.Context, categoryID pgtype.Int4) error {
+	return nil
+}
+func (noopStore) DeleteDemandForecasts(ctx context.Context) error      { return nil }
+func (noopStore) DeleteExpiredRefreshTokens(ctx context.Context) error { return nil }
+func (noopStore) DeleteExpiredStockReservations(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (noopStore) DeletePendingBackInStockSubscription(ctx context.Context, arg db.DeletePendingBackInStockSubscriptionParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) DeleteProductAttributes(ctx context.Context, productID int32) error { return nil }
+func (noopStore) DeleteProductRecommendations(ctx context.Context) error             { return nil }
+func (noopStore) DeleteProductReorderThreshold(ctx context.Context, productID pgtype.Int4) error {
+	return nil
+}
+func (noopStore) DeleteQuietAssistantConversations(ctx context.Context, arg db.DeleteQuietAssistantConversationsParams) error {
+	return nil
+}
+func (noopStore) DeleteRefreshToken(ctx context.Context, token string) error          { return nil }
+func (noopStore) DeleteRefreshTokensByUserID(ctx context.Context, userID int32) error { return nil }
+func (noopStore) DeleteReleasedImageBlobs(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	return 0, nil
+}
+func (noopStore) DeleteSentStockAlerts(ctx context.Context, arg db.DeleteSentStockAlertsParams) error {
+	return nil
+}
+func (noopStore) DeleteStockReservationsByUser(ctx context.Context, userID int32) error { return nil }
+func (noopStore) EndProductSalePrice(ctx context.Context, arg db.EndProductSalePriceParams) (db.ProductPrice, error) {
+	return db.ProductPrice{}, nil
+}
+func (noopStore) ExpireProductImageUploads(ctx context.Context, arg db.ExpireProductImageUploadsParams) ([]db.ProductImageUpload, error) {
+	return nil, nil
+}
+func (noopStore) FailProductImageUpload(ctx context.Context, arg db.FailProductImageUploadParams) error {
+	return nil
+}
+func (noopStore) GetCartByID(ctx context.Context, id int32) (db.Cart, error) {
+	return db.Cart{}, nil
+}
+func (noopStore) GetCartByUserID(ctx context.Context, userID int32) (db.Cart, error) {
+	return db.Cart{}, nil
+}
+func (noopStore) GetCartItem(ctx context.Context, arg db.GetCartItemParams) (db.CartItem, error) {
+	return db.CartItem{}, nil
+}
+func (noopStore) GetCartItemByID(ctx context.Context, id int32) (db.CartItem, error) {
+	return db.CartItem{}, nil
+}
+func (noopStore) GetCategoriesByIDs(ctx context.Context, dollar_1 []int32) ([]db.Category, error) {
+	return nil, nil
+}
+func (noopStore) GetCategoryByID(ctx context.Context, id int32) (db.Category, error) {
+	return db.Category{}, nil
+}
+func (noopStore) GetDefaultWarehouse(ctx context.Context) (db.Warehouse, error) {
+	return db.Warehouse{}, nil
+}
+func (noopStore) GetEffectivePrices(ctx context.Context, arg db.GetEffectivePricesParams) ([]db.GetEffectivePricesRow, error) {
+	return nil, nil
+}
+func (noopStore) GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
+	return db.OrderIdempotencyKey{}, nil
+}
+func (noopStore) GetImageBlobForUpdate(ctx context.Context, contentHash string) (db.ImageBlob, error) {
+	return db.ImageBlob{}, nil
+}
+func (noopStore) GetOrderByID(ctx context.Context, id int32) (db.Order, error) {
+	return db.Order{}, nil
+}
+func (noopStore) GetOrderByIDForUpdate(ctx context.Context, id int32) (db.Order, error) {
+	return db.Order{}, nil
+}
+func (noopStore) GetOrderItemByID(ctx context.Context, id int32) (db.OrderItem, error) {
+	return db.OrderItem{}, nil
+}
+func (noopStore) GetOrderRiskAssessment(ctx context.Context, orderID int32) (db.OrderRiskAssessment, error) {
+	return db.OrderRiskAssessment{}, nil
+}
+func (noopStore) GetOrderTotal(ctx context.Context, orderID int32) (pgtype.Numeric, error) {
+	return pgtype.Numeric{}, nil
+}
+func (noopStore) GetOrdersByIDs(ctx context.Context, ids []int32) ([]db.Order, error) {
+	return nil, nil
+}
+func (noopStore) GetPendingBackInStockSubscription(ctx context.Context, arg db.GetPendingBackInStockSubscriptionParams) (db.BackInStockSubscription, error) {
+	return db.BackInStockSubscription{}, nil
+}
+func (noopStore) GetPrimaryProductImage(ctx context.Context, productID int32) (db.ProductImage, error) {
+	return db.ProductImage{}, nil
+}
+func (noopStore) GetProductByID(ctx context.Context, id int32) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) GetProductBySKU(ctx context.Context, sku string) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) GetProductContentDraft(ctx context.Context, id int32) (db.ProductContentDraft, error) {
+	return db.ProductContentDraft{}, nil
+}
+func (noopStore) GetProductImageByID(ctx context.Context, id int32) (db.ProductImage, error) {
+	return db.ProductImage{}, nil
+}
+func (noopStore) GetProductImageUpload(ctx context.Context, id int32) (db.ProductImageUpload, error) {
+	return db.ProductImageUpload{}, nil
+}
+func (noopStore) GetProductReviewStats(ctx context.Context, productID int32) (db.GetProductReviewStatsRow, error) {
+	return db.GetProductReviewStatsRow{}, nil
+}
+func (noopStore) GetProductReviewSummary(ctx context.Context, productID int32) (db.ProductReviewSummary, error) {
+	return db.ProductReviewSummary{}, nil
+}
+func (noopStore) GetProductsByIDs(ctx context.Context, dollar_1 []int32) ([]db.Product, error) {
+	return nil, nil
+}
+func (noopStore) GetProductsByIDsForUpdate(ctx context.Context, dollar_1 []int32) ([]db.Product, error) {
+	return nil, nil
+}
+func (noopStore) GetRefreshToken(ctx context.Context, token string) (db.RefreshToken, error) {
+	return db.RefreshToken{}, nil
+}
+func (noopStore) GetRefreshTokensByUserID(ctx context.Context, userID int32) ([]db.RefreshToken, error) {
+	return nil, nil
+}
+func (noopStore) GetReorderThresholdsByProductIDs(ctx context.Context, arg db.GetReorderThresholdsByProductIDsParams) ([]db.GetReorderThresholdsByProductIDsRow, error) {
+	return nil, nil
+}
+func (noopStore) GetReservedStockByProductIDs(ctx context.Context, arg db.GetReservedStockByProductIDsParams) ([]db.GetReservedStockByProductIDsRow, error) {
+	return nil, nil
+}
+func (noopStore) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	return db.User{}, nil
+}
+func (noopStore) GetUserByID(ctx context.Context, id int32) (db.User, error) {
+	return db.User{}, nil
+}
+func (noopStore) GetWarehouseByCode(ctx context.Context, code string) (db.Warehouse, error) {
+	return db.Warehouse{}, nil
+}
+func (noopStore) GetWarehouseStockForUpdate(ctx context.Context, arg db.GetWarehouseStockForUpdateParams) (db.WarehouseStock, error) {
+	return db.WarehouseStock{}, nil
+}
+func (noopStore) InsertDemandForecasts(ctx context.Context, arg db.InsertDemandForecastsParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) InsertProductRecommendations(ctx context.Context, arg db.InsertProductRecommendationsParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) ListActiveCategories(ctx context.Context) ([]db.Category, error) {
+	return nil, nil
+}
+func (noopStore) ListActiveProducts(ctx context.Context, arg db.ListActiveProductsParams) ([]db.Product, error) {
+	return nil, nil
+}
+func (noopStore) ListActiveStockReservationsByUser(ctx context.Context, userID int32) ([]db.StockReservation, error) {
+	return nil, nil
+}
+func (noopStore) ListAllocatableStockForUpdate(ctx context.Context, dollar_1 []int32) ([]db.ListAllocatableStockForUpdateRow, error) {
+	return nil, nil
+}
+func (noopStore) ListAttributeValues(ctx context.Context) ([]db.ListAttributeValuesRow, error) {
+	return nil, nil
+}
+func (noopStore) ListBackInStockRetryProducts(ctx context.Context, maxAttempts int32) ([]db.ListBackInStockRetryProductsRow, error) {
+	return nil, nil
+}
+func (noopStore) ListCartItems(ctx context.Context, cartID int32) ([]db.CartItem, error) {
+	return nil, nil
+}
+func (noopStore) ListCartRecommendations(ctx context.Context, arg db.ListCartRecommendationsParams) ([]db.ListCartRecommendationsRow, error) {
+	return nil, nil
+}
+func (noopStore) ListCategories(ctx context.Context, arg db.ListCategoriesParams) ([]db.Category, error) {
+	return nil, nil
+}
+func (noopStore) ListCategoryNames(ctx context.Context) ([]db.ListCategoryNamesRow, error) {
+	return nil, nil
+}
+func (noopStore) ListCategoryTrainingProducts(ctx context.Context) ([]db.ListCategoryTrainingProductsRow, error) {
+	return nil, nil
+}
+func (noopStore) ListDailyProductSales(ctx context.Context, arg db.ListDailyProductSalesParams) ([]db.ListDailyProductSalesRow, error) {
+	return nil, nil
+}
+func (noopStore) ListDemandForecasts(ctx context.Context, arg db.ListDemandForecastsParams) ([]db.ListDemandForecastsRow, error) {
+	return nil, nil
+}
+func (noopStore) ListFeedCandidates(ctx context.Context, arg db.ListFeedCandidatesParams) ([]db.ListFeedCandidatesRow, error) {
+	return nil, nil
+}
+func (noopStore) ListForecastProducts(ctx context.Context) ([]db.ListForecastProductsRow, error) {
+	return nil, nil
+}
+func (noopStore) ListHeldOrderRiskAssessments(ctx context.Context, arg db.ListHeldOrderRiskAssessmentsParams) ([]db.OrderRiskAssessment, error) {
+	return nil, nil
+}
+func (noopStore) ListImageBlobVariants(ctx context.Context, contentHash string) ([]db.ProductImageVariant, error) {
+	return nil, nil
+}
+func (noopStore) ListInventoryMovementsByProduct(ctx context.Context, arg db.ListInventoryMovementsByProductParams) ([]db.InventoryMovement, error) {
+	return nil, nil
+}
+func (noopStore) ListOrderItemAllocationsByOrderID(ctx context.Context, orderID int32) ([]db.ListOrderItemAllocationsByOrderIDRow, error) {
+	return nil, nil
+}
+func (noopStore) ListOrderItems(ctx context.Context, orderID int32) ([]db.OrderItem, error) {
+	return nil, nil
+}
+func (noopStore) ListOrderItemsByOrderIDs(ctx context.Context, orderIds []int32) ([]db.OrderItem, error) {
+	return nil, nil
+}
+func (noopStore) ListOrderRiskSignalsByOrderIDs(ctx context.Context, orderIds []int32) ([]db.OrderRiskSignal, error) {
+	return nil, nil
+}
+func (noopStore) ListOrders(ctx context.Context, arg db.ListOrdersParams) ([]db.Order, error) {
+	return nil, nil
+}
+func (noopStore) ListOrdersByStatus(ctx context.Context, arg db.ListOrdersByStatusParams) ([]db.Order, error) {
+	return nil, nil
+}
+func (noopStore) ListOrdersByUserID(ctx context.Context, arg db.ListOrdersByUserIDParams) ([]db.Order, error) {
+	return nil, nil
+}
+func (noopStore) ListProductAttributesByProductIDs(ctx context.Context, productIds []int32) ([]db.ProductAttribute, error) {
+	return nil, nil
+}
+func (noopStore) ListProductContentDrafts(ctx context.Context, productID int32) ([]db.ProductContentDraft, error) {
+	return nil, nil
+}
+func (noopStore) ListProductImageVariantsByImageIDs(ctx context.Context, imageIds []int32) ([]db.ProductImageVariant, error) {
+	return nil, nil
+}
+func (noopStore) ListProductImages(ctx context.Context, productID int32) ([]db.ProductImage, error) {
+	return nil, nil
+}
+func (noopStore) ListProductImagesByProductIDs(ctx context.Context, dollar_1 []int32) ([]db.ProductImage, error) {
+	return nil, nil
+}
+func (noopStore) ListProductPricesByProduct(ctx context.Context, productID int32) ([]db.ProductPrice, error) {
+	return nil, nil
+}
+func (noopStore) ListProductRecommendations(ctx context.Context, arg db.ListProductRecommendationsParams) ([]db.ListProductRecommendationsRow, error) {
+	return nil, nil
+}
+func (noopStore) ListProductReviewSummariesByProductIDs(ctx context.Context, productIds []int32) ([]db.ProductReviewSummary, error) {
+	return nil, nil
+}
+func (noopStore) ListProductReviews(ctx context.Context, arg db.ListProductReviewsParams) ([]db.ProductReview, error) {
+	return nil, nil
+}
+func (noopStore) ListProducts(ctx context.Context, arg db.ListProductsParams) ([]db.Product, error) {
+	return nil, nil
+}
+func (noopStore) ListProductsBelowThreshold(ctx context.Context, defaultThreshold int32) ([]db.ListProductsBelowThresholdRow, error) {
+	return nil, nil
+}
+func (noopStore) ListProductsByCategory(ctx context.Context, arg db.ListProductsByCategoryParams) ([]db.Product, error) {
+	return nil, nil
+}
+func (noopStore) ListProductsNeedingEmbedding(ctx context.Context, arg db.ListProductsNeedingEmbeddingParams) ([]db.ListProductsNeedingEmbeddingRow, error) {
+	return nil, nil
+}
+func (noopStore) ListReferencedStoragePaths(ctx context.Context, cutoff pgtype.Timestamptz) ([]string, error) {
+	return nil, nil
+}
+func (noopStore) ListSearchTermWords(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+func (noopStore) ListStockAlerts(ctx context.Context) ([]db.StockAlert, error) {
+	return nil, nil
+}
+func (noopStore) ListStockDiscrepancies(ctx context.Context) ([]db.ListStockDiscrepanciesRow, error) {
+	return nil, nil
+}
+func (noopStore) ListUserCategoryActivity(ctx context.Context, arg db.ListUserCategoryActivityParams) ([]db.ListUserCategoryActivityRow, error) {
+	return nil, nil
+}
+func (noopStore) ListUserOwnedProductIDs(ctx context.Context, userID int32) ([]int32, error) {
+	return nil, nil
+}
+func (noopStore) ListUsers(ctx context.Context, arg db.ListUsersParams) ([]db.User, error) {
+	return nil, nil
+}
+func (noopStore) ListWarehouseStockByProduct(ctx context.Context, productID int32) ([]db.ListWarehouseStockByProductRow, error) {
+	return nil, nil
+}
+func (noopStore) ListWarehouses(ctx context.Context) ([]db.Warehouse, error) {
+	return nil, nil
+}
+func (noopStore) MarkBackInStockSubscriptionsFulfilled(ctx context.Context, ids []int32) error {
+	return nil
+}
+func (noopStore) QueueStockAlert(ctx context.Context, arg db.QueueStockAlertParams) error {
+	return nil
+}
+func (noopStore) RecordBackInStockFailures(ctx context.Context, arg db.RecordBackInStockFailuresParams) error {
+	return nil
+}
+func (noopStore) RecordFailedLogin(ctx context.Context, userID int32) error { return nil }
+func (noopStore) RecordOrderRiskReview(ctx context.Context, arg db.RecordOrderRiskReviewParams) (db.OrderRiskAssessment, error) {
+	return db.OrderRiskAssessment{}, nil
+}
+func (noopStore) ReleaseAssistantConversation(ctx context.Context, arg db.ReleaseAssistantConversationParams) error {
+	return nil
+}
+func (noopStore) ReleaseProductImageUpload(ctx context.Context, arg db.ReleaseProductImageUploadParams) error {
+	return nil
+}
+func (noopStore) RestoreCartItem(ctx context.Context, arg db.RestoreCartItemParams) (db.CartItem, error) {
+	return db.CartItem{}, nil
+}
+func (noopStore) ReviewProductContentDraft(ctx context.Context, arg db.ReviewProductContentDraftParams) (db.ProductContentDraft, error) {
+	return db.ProductContentDraft{}, nil
+}
+func (noopStore) SaveAssistantConversation(ctx context.Context, arg db.SaveAssistantConversationParams) error {
+	return nil
+}
+func (noopStore) SearchAttributeFacets(ctx context.Context, arg db.SearchAttributeFacetsParams) ([]db.SearchAttributeFacetsRow, error) {
+	return nil, nil
+}
+func (noopStore) SearchCategoryFacets(ctx context.Context, arg db.SearchCategoryFacetsParams) ([]db.SearchCategoryFacetsRow, error) {
+	return nil, nil
+}
+func (noopStore) SearchPriceFacets(ctx context.Context, arg db.SearchPriceFacetsParams) ([]db.SearchPriceFacetsRow, error) {
+	return nil, nil
+}
+func (noopStore) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
+	return nil, nil
+}
+func (noopStore) SearchStockFacets(ctx context.Context, arg db.SearchStockFacetsParams) (db.SearchStockFacetsRow, error) {
+	return db.SearchStockFacetsRow{}, nil
+}
+func (noopStore) SemanticSearchProducts(ctx context.Context, arg db.SemanticSearchProductsParams) ([]db.SemanticSearchProductsRow, error) {
+	return nil, nil
+}
+func (noopStore) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) SetProductImagePositions(ctx context.Context, arg db.SetProductImagePositionsParams) (int64, error) {
+	return 0, nil
+}
+func (noopStore) SoftDeleteCart(ctx context.Context, id int32) error                     { return nil }
+func (noopStore) SoftDeleteCartByUserID(ctx context.Context, userID int32) error         { return nil }
+func (noopStore) SoftDeleteCartItem(ctx context.Context, id int32) error                 { return nil }
+func (noopStore) SoftDeleteCartItemsByCartID(ctx context.Context, cartID int32) error    { return nil }
+func (noopStore) SoftDeleteCategory(ctx context.Context, id int32) error                 { return nil }
+func (noopStore) SoftDeleteOrder(ctx context.Context, id int32) error                    { return nil }
+func (noopStore) SoftDeleteOrderItem(ctx context.Context, id int32) error                { return nil }
+func (noopStore) SoftDeleteOrderItemsByOrderID(ctx context.Context, orderID int32) error { return nil }
+func (noopStore) SoftDeleteProduct(ctx context.Context, id int32) error                  { return nil }
+func (noopStore) SoftDeleteProductImage(ctx context.Context, id int32) ([]string, error) {
+	return nil, nil
+}
+func (noopStore) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) ([]string, error) {
+	return nil, nil
+}
+func (noopStore) SoftDeleteUser(ctx context.Context, id int32) error { return nil }
+func (noopStore) SuggestProducts(ctx context.Context, arg db.SuggestProductsParams) ([]db.SuggestProductsRow, error) {
+	return nil, nil
+}
+func (noopStore) TryJobLock(ctx context.Context, name string) (bool, error) { return false, nil }
+func (noopStore) UpdateCartItemQuantity(ctx context.Context, arg db.UpdateCartItemQuantityParams) (db.CartItem, error) {
+	return db.CartItem{}, nil
+}
+func (noopStore) UpdateCartTimestamp(ctx context.Context, id int32) (db.Cart, error) {
+	return db.Cart{}, nil
+}
+func (noopStore) UpdateCategory(ctx context.Context, arg db.UpdateCategoryParams) (db.Category, error) {
+	return db.Category{}, nil
+}
+func (noopStore) UpdateCategoryStatus(ctx context.Context, arg db.UpdateCategoryStatusParams) (db.Category, error) {
+	return db.Category{}, nil
+}
+func (noopStore) UpdateIdempotencyKeyOrderID(ctx context.Context, arg db.UpdateIdempotencyKeyOrderIDParams) error {
+	return nil
+}
+func (noopStore) UpdateOrderStatus(ctx context.Context, arg db.UpdateOrderStatusParams) (db.Order, error) {
+	return db.Order{}, nil
+}
+func (noopStore) UpdateOrderTotal(ctx context.Context, arg db.UpdateOrderTotalParams) (db.Order, error) {
+	return db.Order{}, nil
+}
+func (noopStore) UpdateProduct(ctx context.Context, arg db.UpdateProductParams) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) UpdateProductContent(ctx context.Context, arg db.UpdateProductContentParams) error {
+	return nil
+}
+func (noopStore) UpdateProductImage(ctx context.Context, arg db.UpdateProductImageParams) (db.ProductImage, error) {
+	return db.ProductImage{}, nil
+}
+func (noopStore) UpdateProductImageAltText(ctx context.Context, arg db.UpdateProductImageAltTextParams) (db.ProductImage, error) {
+	return db.ProductImage{}, nil
+}
+func (noopStore) UpdateProductStatus(ctx context.Context, arg db.UpdateProductStatusParams) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) UpdateProductStock(ctx context.Context, arg db.UpdateProductStockParams) (db.Product, error) {
+	return db.Product{}, nil
+}
+func (noopStore) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
+	return db.User{}, nil
+}
+func (noopStore) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) error {
+	return nil
+}
+func (noopStore) UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error) {
+	return db.User{}, nil
+}
+func (noopStore) UpdateUserStatus(ctx context.Context, arg db.UpdateUserStatusParams) (db.User, error) {
+	return db.User{}, nil
+}
+func (noopStore) UpsertCartItem(ctx context.Context, arg db.UpsertCartItemParams) (db.CartItem, error) {
+	return db.CartItem{}, nil
+}
+func (noopStore) UpsertCategoryReorderThreshold(ctx context.Context, arg db.UpsertCategoryReorderThresholdParams) (db.ReorderThreshold, error) {
+	return db.ReorderThreshold{}, nil
+}
+func (noopStore) UpsertProductEmbedding(ctx context.Context, arg db.UpsertProductEmbeddingParams) error {
+	return nil
+}
+func (noopStore) UpsertProductReorderThreshold(ctx context.Context, arg db.UpsertProductReorderThresholdParams) (db.ReorderThreshold, error) {
+	return db.ReorderThreshold{}, nil
+}
+func (noopStore) UpsertProductReviewSummary(ctx context.Context, arg db.UpsertProductReviewSummaryParams) (db.ProductReviewSummary, error) {
+	return db.ProductReviewSummary{}, nil
+}
+func (noopStore) UpsertStockReservation(ctx context.Context, arg db.UpsertStockReservationParams) (db.StockReservation, error) {
+	return db.StockReservation{}, nil
+}
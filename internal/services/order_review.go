@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+var (
+	ErrOrderNotHeld          = errors.New("order is not held for review")
+	ErrOrderHeldByReview     = errors.New("orders are held and released only by fraud review")
+	ErrInvalidReviewDecision = errors.New("review decision must be approve or reject")
+)
+
+// ListHeldOrders returns the orders waiting for fraud review, oldest first
+func (s *OrderService) ListHeldOrders(ctx context.Context, page, limit int) ([]dto.HeldOrderResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	totalCount, err := s.store.CountOrdersByStatus(ctx, db.NullOrderStatus{OrderStatus: db.OrderStatusOnHold, Valid: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count held orders: %w", err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit > 0 {
+		totalPages++
+	}
+
+	paginationMeta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: int(totalCount),
+		TotalPages: totalPages,
+	}
+
+	assessments, err := s.store.ListHeldOrderRiskAssessments(ctx, db.ListHeldOrderRiskAssessmentsParams{
+		Limit:  int32(limit),              //#nosec G115 -- pagination values are bounded
+		Offset: int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list held orders: %w", err)
+	}
+
+	if len(assessments) == 0 {
+		return []dto.HeldOrderResponse{}, paginationMeta, nil
+	}
+
+	orderIDs := make([]int32, len(assessments))
+	for i, assessment := range assessments {
+		orderIDs[i] = assessment.OrderID
+	}
+
+	signals, err := s.store.ListOrderRiskSignalsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list risk signals: %w", err)
+	}
+
+	signalsByOrder := make(map[int32][]dto.RiskSignal, len(assessments))
+	for _, signal := range signals {
+		signalsByOrder[signal.OrderID] = append(signalsByOrder[signal.OrderID], dto.RiskSignal{
+			Rule:   signal.Rule,
+			Weight: signal.Weight,
+			Detail: signal.Detail,
+		})
+	}
+
+	orders, err := s.store.GetOrdersByIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get held orders: %w", err)
+	}
+	orderResponses, err := s.buildOrderResponses(ctx, orders)
+	if err != nil {
+		return nil, nil, err
+	}
+	ordersByID := make(map[int32]dto.OrderResponse, len(orderResponses))
+	for _, order := range orderResponses {
+		ordersByID[int32(order.ID)] = order //#nosec G115 -- id from the database
+	}
+
+	responses := make([]dto.HeldOrderResponse, len(assessments))
+	for i, assessment := range assessments {
+		orderSignals := signalsByOrder[assessment.OrderID]
+		if orderSignals == nil {
+			orderSignals = []dto.RiskSignal{}
+		}
+		responses[i] = dto.HeldOrderResponse{
+			Order:      ordersByID[assessment.OrderID],
+			RiskScore:  assessment.Score,
+			Signals:    orderSignals,
+			IPAddress:  assessment.IpAddress.String,
+			AssessedAt: assessment.AssessedAt.Time,
+		}
+	}
+
+	return responses, paginationMeta, nil
+}
+
+// ReviewHeldOrder settles an order held for fraud review. Approving it
+// returns it to pending; rejecting it cancels it and puts its stock back.
+func (s *OrderService) ReviewHeldOrder(ctx context.Context, adminID, orderID int32, decision string) (*dto.OrderResponse, error) {
+	var status db.OrderStatus
+	var riskDecision db.OrderRiskDecision
+	switch decision {
+	case "approve":
+		status, riskDecision = db.OrderStatusPending, db.OrderRiskDecisionApproved
+	case "reject":
+		status, riskDecision = db.OrderStatusCancelled, db.OrderRiskDecisionRejected
+	default:
+		return nil, ErrInvalidReviewDecision
+	}
+
+	var order db.Order
+	var movements []db.InventoryMovement
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		// Lock the order so a concurrent cancel or second review waits and
+		// then sees it is no longer on hold
+		held, err := q.GetOrderByIDForUpdate(ctx, orderID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrOrderNotFound
+			}
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if held.Status.OrderStatus != db.OrderStatusOnHold {
+			return ErrOrderNotHeld
+		}
+
+		order, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+			ID:     orderID,
+			Status: db.NullOrderStatus{OrderStatus: status, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		_, err = q.RecordOrderRiskReview(ctx, db.RecordOrderRiskReviewParams{
+			OrderID:    orderID,
+			ReviewedBy: pgtype.Int4{Int32: adminID, Valid: true},
+			Decision:   db.NullOrderRiskDecision{OrderRiskDecision: riskDecision, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record review: %w", err)
+		}
+
+		if status != db.OrderStatusCancelled {
+			return nil
+		}
+		allocations, err := q.ListOrderItemAllocationsByOrderID(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("failed to list order item allocations: %w", err)
+		}
+		movements, err = restoreOrderStock(ctx, q, orderID, adminID, allocations)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.alerter.Notify(ctx, movements...)
+
+	return s.buildOrderResponse(ctx, order)
+}
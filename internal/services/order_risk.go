@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+// RiskOrder is what risk rules know about an order being placed
+type RiskOrder struct {
+	UserID          int32
+	IPAddress       string
+	Total           money.Money // in the base currency
+	Lines           []RiskLine
+	ShippingAddress *dto.ShippingAddress
+	BillingAddress  *dto.ShippingAddress
+	PlacedAt        time.Time
+}
+
+// RiskLine is one product and quantity of an order being placed
+type RiskLine struct {
+	ProductID int32
+	Quantity  int32
+}
+
+// RiskRule is one sign that an order may be fraudulent. Check returns why
+// the rule fired for an order, or "" when it didn't. It runs inside the
+// order's transaction, so it reads through q.
+type RiskRule interface {
+	Name() string
+	Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error)
+}
+
+// RiskAssessment is an order's risk score and the rules behind it
+type RiskAssessment struct {
+	Score   float64
+	Hold    bool // the score reached the hold score, so the order waits for review
+	Signals []dto.RiskSignal
+}
+
+type weightedRule struct {
+	rule   RiskRule
+	weight float64
+}
+
+// RiskScorer adds up the weights of the rules an order trips. Orders that
+// reach the hold score are held for manual review.
+type RiskScorer struct {
+	rules     []weightedRule
+	holdScore float64
+}
+
+// NewRiskScorer scores orders with the given rules, each weighted by the
+// entry for its name in weights. Rules without a positive weight are left
+// out, so a weight of 0 turns a rule off.
+func NewRiskScorer(holdScore float64, weights map[string]float64, rules ...RiskRule) *RiskScorer {
+	scorer := &RiskScorer{holdScore: holdScore}
+	for _, rule := range rules {
+		if weight := weights[rule.Name()]; weight > 0 {
+			scorer.rules = append(scorer.rules, weightedRule{rule: rule, weight: weight})
+		}
+	}
+	return scorer
+}
+
+// Assess runs every rule against an order. A nil scorer assesses nothing
+// and returns nil.
+func (s *RiskScorer) Assess(ctx context.Context, q db.Querier, order RiskOrder) (*RiskAssessment, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	assessment := &RiskAssessment{Signals: []dto.RiskSignal{}}
+	for _, r := range s.rules {
+		detail, err := r.rule.Check(ctx, q, order)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s risk: %w", r.rule.Name(), err)
+		}
+		if detail == "" {
+			continue
+		}
+		assessment.Score += r.weight
+		assessment.Signals = append(assessment.Signals, dto.RiskSignal{
+			Rule:   r.rule.Name(),
+			Weight: r.weight,
+			Detail: detail,
+		})
+	}
+	assessment.Hold = s.holdScore > 0 && assessment.Score >= s.holdScore
+	return assessment, nil
+}
+
+// ParseRiskWeights parses rule weights written as
+// "user_velocity=30,address_mismatch=20"
+func ParseRiskWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		rule, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid risk weight %q, expected rule=weight", pair)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid risk weight %q: %w", pair, err)
+		}
+		weights[strings.TrimSpace(rule)] = weight
+	}
+	return weights, nil
+}
+
+// UserVelocityRule fires when the user has already placed MaxOrders
+// orders within Window
+type UserVelocityRule struct {
+	Window    time.Duration
+	MaxOrders int
+}
+
+func (r UserVelocityRule) Name() string { return "user_velocity" }
+
+func (r UserVelocityRule) Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error) {
+	count, err := q.CountRecentOrdersByUser(ctx, db.CountRecentOrdersByUserParams{
+		UserID: order.UserID,
+		Since:  pgtype.Timestamptz{Time: order.PlacedAt.Add(-r.Window), Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	if count < int64(r.MaxOrders) {
+		return "", nil
+	}
+	return fmt.Sprintf("%d orders from this account in the last %s", count, shortDuration(r.Window)), nil
+}
+
+// IPVelocityRule fires when MaxOrders orders have already come from the
+// order's IP address within Window
+type IPVelocityRule struct {
+	Window    time.Duration
+	MaxOrders int
+}
+
+func (r IPVelocityRule) Name() string { return "ip_velocity" }
+
+func (r IPVelocityRule) Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error) {
+	if order.IPAddress == "" {
+		return "", nil
+	}
+	count, err := q.CountRecentOrdersByIP(ctx, db.CountRecentOrdersByIPParams{
+		IpAddress: pgtype.Text{String: order.IPAddress, Valid: true},
+		Since:     pgtype.Timestamptz{Time: order.PlacedAt.Add(-r.Window), Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	if count < int64(r.MaxOrders) {
+		return "", nil
+	}
+	return fmt.Sprintf("%d orders from %s in the last %s", count, order.IPAddress, shortDuration(r.Window)), nil
+}
+
+// FirstOrderHighValueRule fires when a user's first order is worth at
+// least Threshold in the base currency
+type FirstOrderHighValueRule struct {
+	Threshold money.Money
+}
+
+func (r FirstOrderHighValueRule) Name() string { return "first_order_high_value" }
+
+func (r FirstOrderHighValueRule) Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error) {
+	if order.Total.Cents() < r.Threshold.Cents() {
+		return "", nil
+	}
+	count, err := q.CountOrdersByUserID(ctx, order.UserID)
+	if err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("first order is worth %s", order.Total), nil
+}
+
+// AddressMismatchRule fires when the billing address is not the shipping
+// address. Addresses are compared ignoring case, spacing and punctuation,
+// and a part left out of either address is not compared.
+type AddressMismatchRule struct{}
+
+func (r AddressMismatchRule) Name() string { return "address_mismatch" }
+
+func (r AddressMismatchRule) Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error) {
+	if order.BillingAddress == nil || order.ShippingAddress == nil {
+		return "", nil
+	}
+	billing, shipping := order.BillingAddress, order.ShippingAddress
+	if !strings.EqualFold(billing.Country, shipping.Country) {
+		return fmt.Sprintf("billing country %s, shipping country %s", strings.ToUpper(billing.Country), strings.ToUpper(shipping.Country)), nil
+	}
+
+	parts := []struct {
+		name              string
+		billing, shipping string
+	}{
+		{"postal code", billing.PostalCode, shipping.PostalCode},
+		{"city", billing.City, shipping.City},
+		{"street address", billing.Line1, shipping.Line1},
+	}
+	for _, part := range parts {
+		billed, shipped := normalizeAddressPart(part.billing), normalizeAddressPart(part.shipping)
+		if billed != "" && shipped != "" && billed != shipped {
+			return fmt.Sprintf("billing %s differs from shipping %s", part.name, part.name), nil
+		}
+	}
+	return "", nil
+}
+
+// normalizeAddressPart lowercases part and drops everything but its
+// letters and digits, so "10 Main St." and "10 main st" compare equal
+func normalizeAddressPart(part string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, part)
+}
+
+// FailedLoginsRule fires when MaxAttempts wrong passwords were given for
+// the account within Window
+type FailedLoginsRule struct {
+	Window      time.Duration
+	MaxAttempts int
+}
+
+func (r FailedLoginsRule) Name() string { return "failed_logins" }
+
+func (r FailedLoginsRule) Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error) {
+	count, err := q.CountRecentFailedLogins(ctx, db.CountRecentFailedLoginsParams{
+		UserID: order.UserID,
+		Since:  pgtype.Timestamptz{Time: order.PlacedAt.Add(-r.Window), Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	if count < int64(r.MaxAttempts) {
+		return "", nil
+	}
+	return fmt.Sprintf("%d failed logins in the last %s", count, shortDuration(r.Window)), nil
+}
+
+// UnusualQuantityRule fires when any line of the order is for more than
+// MaxQuantity of a product
+type UnusualQuantityRule struct {
+	MaxQuantity int32
+}
+
+func (r UnusualQuantityRule) Name() string { return "unusual_quantity" }
+
+func (r UnusualQuantityRule) Check(ctx context.Context, q db.Querier, order RiskOrder) (string, error) {
+	for _, line := range order.Lines {
+		if line.Quantity > r.MaxQuantity {
+			return fmt.Sprintf("%d of product %d", line.Quantity, line.ProductID), nil
+		}
+	}
+	return "", nil
+}
+
+// recordRiskAssessment stores an order's risk score and the rules that
+// fired, so IP velocity can count the order and reviewers can see why it
+// was held
+func recordRiskAssessment(ctx context.Context, q db.Querier, orderID int32, ipAddress string, assessment *RiskAssessment) error {
+	_, err := q.CreateOrderRiskAssessment(ctx, db.CreateOrderRiskAssessmentParams{
+		OrderID:   orderID,
+		Score:     assessment.Score,
+		Held:      assessment.Hold,
+		IpAddress: pgtype.Text{String: ipAddress, Valid: ipAddress != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record risk assessment: %w", err)
+	}
+	if len(assessment.Signals) == 0 {
+		return nil
+	}
+
+	rules := make([]string, len(assessment.Signals))
+	weights := make([]float64, len(assessment.Signals))
+	details := make([]string, len(assessment.Signals))
+	for i, signal := range assessment.Signals {
+		rules[i], weights[i], details[i] = signal.Rule, signal.Weight, signal.Detail
+	}
+	err = q.AddOrderRiskSignals(ctx, db.AddOrderRiskSignalsParams{
+		OrderID: orderID,
+		Rules:   rules,
+		Weights: weights,
+		Details: details,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record risk signals: %w", err)
+	}
+	return nil
+}
+
+// shortDuration writes whole hours and minutes without trailing zero units,
+// so an hour reads "1h" rather than "1h0m0s"
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
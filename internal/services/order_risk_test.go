@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+func TestRiskScorer_Assess(t *testing.T) {
+	t.Parallel()
+
+	placedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	order := RiskOrder{
+		UserID:          7,
+		IPAddress:       "203.0.113.9",
+		Total:           money.FromCents(80000),
+		Lines:           []RiskLine{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 40}},
+		ShippingAddress: &dto.ShippingAddress{Country: "US"},
+		BillingAddress:  &dto.ShippingAddress{Country: "ng"},
+		PlacedAt:        placedAt,
+	}
+	rules := []RiskRule{
+		UserVelocityRule{Window: time.Hour, MaxOrders: 3},
+		IPVelocityRule{Window: time.Hour, MaxOrders: 5},
+		FirstOrderHighValueRule{Threshold: money.FromCents(50000)},
+		AddressMismatchRule{},
+		FailedLoginsRule{Window: 24 * time.Hour, MaxAttempts: 5},
+		UnusualQuantityRule{MaxQuantity: 10},
+	}
+	weights := map[string]float64{
+		"user_velocity":          30,
+		"ip_velocity":            30,
+		"first_order_high_value": 35,
+		"address_mismatch":       20,
+		"failed_logins":          25,
+		"unusual_quantity":       20,
+	}
+
+	setupQuiet := func(m *mocks.MockStore) {
+		m.On("CountRecentOrdersByUser", mock.Anything, db.CountRecentOrdersByUserParams{
+			UserID: 7,
+			Since:  pgtype.Timestamptz{Time: placedAt.Add(-time.Hour), Valid: true},
+		}).Return(int64(1), nil)
+		m.On("CountRecentOrdersByIP", mock.Anything, db.CountRecentOrdersByIPParams{
+			IpAddress: pgtype.Text{String: "203.0.113.9", Valid: true},
+			Since:     pgtype.Timestamptz{Time: placedAt.Add(-time.Hour), Valid: true},
+		}).Return(int64(6), nil)
+		m.On("CountOrdersByUserID", mock.Anything, int32(7)).Return(int64(4), nil)
+		m.On("CountRecentFailedLogins", mock.Anything, db.CountRecentFailedLoginsParams{
+			UserID: 7,
+			Since:  pgtype.Timestamptz{Time: placedAt.Add(-24 * time.Hour), Valid: true},
+		}).Return(int64(0), nil)
+	}
+
+	t.Run("weights of the rules that fire add up to a hold", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		setupQuiet(mockStore)
+
+		scorer := NewRiskScorer(50, weights, rules...)
+		assessment, err := scorer.Assess(context.Background(), mockStore, order)
+		require.NoError(t, err)
+
+		assert.InDelta(t, 70, assessment.Score, 1e-9)
+		assert.True(t, assessment.Hold)
+		assert.Equal(t, []dto.RiskSignal{
+			{Rule: "ip_velocity", Weight: 30, Detail: "6 orders from 203.0.113.9 in the last 1h"},
+			{Rule: "address_mismatch", Weight: 20, Detail: "billing country NG, shipping country US"},
+			{Rule: "unusual_quantity", Weight: 20, Detail: "40 of product 2"},
+		}, assessment.Signals)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("rules without a weight are left out", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		setupQuiet(mockStore)
+
+		scorer := NewRiskScorer(50, map[string]float64{
+			"user_velocity":          30,
+			"ip_velocity":            0,
+			"first_order_high_value": 35,
+			"address_mismatch":       20,
+			"failed_logins":          25,
+		}, rules...)
+		assessment, err := scorer.Assess(context.Background(), mockStore, order)
+		require.NoError(t, err)
+
+		assert.InDelta(t, 20, assessment.Score, 1e-9)
+		assert.False(t, assessment.Hold)
+		require.Len(t, assessment.Signals, 1)
+		assert.Equal(t, "address_mismatch", assessment.Signals[0].Rule)
+		mockStore.AssertNotCalled(t, "CountRecentOrdersByIP", mock.Anything, mock.Anything)
+	})
+
+	t.Run("first order over the threshold", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("CountOrdersByUserID", mock.Anything, int32(7)).Return(int64(0), nil)
+
+		scorer := NewRiskScorer(30, weights, FirstOrderHighValueRule{Threshold: money.FromCents(50000)})
+		assessment, err := scorer.Assess(context.Background(), mockStore, order)
+		require.NoError(t, err)
+
+		assert.True(t, assessment.Hold)
+		assert.Equal(t, []dto.RiskSignal{
+			{Rule: "first_order_high_value", Weight: 35, Detail: "first order is worth 800.00"},
+		}, assessment.Signals)
+	})
+
+	t.Run("rule errors fail the assessment", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("CountRecentFailedLogins", mock.Anything, mock.Anything).Return(int64(0), errors.New("db down"))
+
+		scorer := NewRiskScorer(50, weights, FailedLoginsRule{Window: time.Hour, MaxAttempts: 5})
+		_, err := scorer.Assess(context.Background(), mockStore, order)
+		assert.ErrorContains(t, err, "failed to check failed_logins risk")
+	})
+
+	t.Run("nil scorer assesses nothing", func(t *testing.T) {
+		t.Parallel()
+
+		var scorer *RiskScorer
+		assessment, err := scorer.Assess(context.Background(), new(mocks.MockStore), order)
+		require.NoError(t, err)
+		assert.Nil(t, assessment)
+	})
+}
+
+func TestAddressMismatchRule(t *testing.T) {
+	t.Parallel()
+
+	shipping := &dto.ShippingAddress{Line1: "10 Main St.", City: "Springfield", PostalCode: "62701", Country: "US"}
+
+	tests := []struct {
+		name    string
+		billing *dto.ShippingAddress
+		want    string
+	}{
+		{
+			name:    "same address written differently",
+			billing: &dto.ShippingAddress{Line1: "10 main st", City: "SPRINGFIELD", PostalCode: "62 701", Country: "us"},
+		},
+		{
+			name:    "billing address with only a country",
+			billing: &dto.ShippingAddress{Country: "US"},
+		},
+		{
+			name:    "no billing address",
+			billing: nil,
+		},
+		{
+			name:    "another country",
+			billing: &dto.ShippingAddress{Line1: "10 Main St.", City: "Springfield", PostalCode: "62701", Country: "ca"},
+			want:    "billing country CA, shipping country US",
+		},
+		{
+			name:    "another street in the same country",
+			billing: &dto.ShippingAddress{Line1: "99 Elm Rd", City: "Springfield", PostalCode: "62701", Country: "US"},
+			want:    "billing street address differs from shipping street address",
+		},
+		{
+			name:    "another city in the same country",
+			billing: &dto.ShippingAddress{Line1: "10 Main St.", City: "Chicago", Country: "US"},
+			want:    "billing city differs from shipping city",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			detail, err := AddressMismatchRule{}.Check(context.Background(), nil, RiskOrder{
+				ShippingAddress: shipping,
+				BillingAddress:  tt.billing,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, detail)
+		})
+	}
+}
+
+func TestParseRiskWeights(t *testing.T) {
+	t.Parallel()
+
+	weights, err := ParseRiskWeights(" user_velocity=30, address_mismatch = 12.5,,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"user_velocity": 30, "address_mismatch": 12.5}, weights)
+
+	_, err = ParseRiskWeights("user_velocity")
+	assert.Error(t, err)
+
+	_, err = ParseRiskWeights("user_velocity=high")
+	assert.Error(t, err)
+}
+
+func TestOrderService_ListHeldOrders(t *testing.T) {
+	t.Parallel()
+
+	heldOrder := createTestOrder()
+	heldOrder.ID = 4
+	heldOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusOnHold, Valid: true}
+	assessedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("CountOrdersByStatus", mock.Anything, db.NullOrderStatus{OrderStatus: db.OrderStatusOnHold, Valid: true}).Return(int64(1), nil)
+	mockStore.On("ListHeldOrderRiskAssessments", mock.Anything, db.ListHeldOrderRiskAssessmentsParams{Limit: 10, Offset: 0}).Return([]db.OrderRiskAssessment{{
+		OrderID:    4,
+		Score:      55,
+		Held:       true,
+		IpAddress:  pgtype.Text{String: "203.0.113.9", Valid: true},
+		AssessedAt: pgtype.Timestamptz{Time: assessedAt, Valid: true},
+	}}, nil)
+	mockStore.On("ListOrderRiskSignalsByOrderIDs", mock.Anything, []int32{4}).Return([]db.OrderRiskSignal{
+		{OrderID: 4, Rule: "first_order_high_value", Weight: 35, Detail: "first order is worth 800.00"},
+		{OrderID: 4, Rule: "address_mismatch", Weight: 20, Detail: "billing country NG, shipping country US"},
+	}, nil)
+	mockStore.On("GetOrdersByIDs", mock.Anything, []int32{4}).Return([]db.Order{heldOrder}, nil)
+	mockStore.On("ListOrderItemsByOrderIDs", mock.Anything, []int32{4}).Return([]db.OrderItem{}, nil)
+
+	service := &OrderService{store: mockStore}
+	orders, pagination, err := service.ListHeldOrders(context.Background(), 1, 10)
+	require.NoError(t, err)
+
+	require.Len(t, orders, 1)
+	assert.Equal(t, uint(4), orders[0].Order.ID)
+	assert.Equal(t, "on_hold", orders[0].Order.Status)
+	assert.InDelta(t, 55, orders[0].RiskScore, 1e-9)
+	assert.Equal(t, "203.0.113.9", orders[0].IPAddress)
+	assert.Equal(t, assessedAt, orders[0].AssessedAt)
+	require.Len(t, orders[0].Signals, 2)
+	assert.Equal(t, "first_order_high_value", orders[0].Signals[0].Rule)
+	assert.Equal(t, 1, pagination.TotalCount)
+	mockStore.AssertExpectations(t)
+}
+
+func TestOrderService_ReviewHeldOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		decision  string
+		setupMock func(m *mocks.MockStore)
+		wantErr   error
+	}{
+		{
+			name:     "error - unknown decision",
+			decision: "escalate",
+			setupMock: func(m *mocks.MockStore) {
+			},
+			wantErr: ErrInvalidReviewDecision,
+		},
+		{
+			// A concurrent review released the order first; the lock makes this one see it
+			name:     "error - order not held",
+			decision: "approve",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(4)).Return(db.Order{
+					ID:     4,
+					Status: db.NullOrderStatus{OrderStatus: db.OrderStatusPending, Valid: true},
+				}, nil)
+			},
+			wantErr: ErrOrderNotHeld,
+		},
+		{
+			name:     "error - order not found",
+			decision: "reject",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(4)).Return(db.Order{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := &OrderService{store: mockStore}
+			order, err := service.ReviewHeldOrder(context.Background(), 1, 4, tt.decision)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, order)
+			mockStore.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
 )
 
@@ -23,18 +27,34 @@ var (
 type OrderService struct {
 	store       db.Store
 	cartService *CartService
+	allocator   AllocationStrategy
+	alerter     *StockAlerter
+	currencies  *CurrencyConverter
+	risk        *RiskScorer
 }
 
-func NewOrderService(store db.Store, cartService *CartService) *OrderService {
+// NewOrderService creates an OrderService. A nil allocator ships from the
+// highest-priority warehouse that can fulfil the whole order; a nil alerter
+// disables low-stock alerts; a nil converter takes orders in
+// money.DefaultCurrency only; a nil risk scorer places every order without
+// a fraud check.
+func NewOrderService(store db.Store, cartService *CartService, allocator AllocationStrategy, alerter *StockAlerter, currencies *CurrencyConverter, risk *RiskScorer) *OrderService {
+	if allocator == nil {
+		allocator = SingleWarehouseFirstStrategy{}
+	}
 	return &OrderService{
 		store:       store,
 		cartService: cartService,
+		allocator:   allocator,
+		alerter:     alerter,
+		currencies:  currencies,
+		risk:        risk,
 	}
 }
 
 // CreateOrderWithIdempotency creates a new order with idempotency key support
 // If the same idempotency key is provided twice, returns the existing order
-func (s *OrderService) CreateOrderWithIdempotency(ctx context.Context, userID int32, idempotencyKey string) (*dto.OrderResponse, error) {
+func (s *OrderService) CreateOrderWithIdempotency(ctx context.Context, userID int32, idempotencyKey string, req dto.CreateOrderRequest) (*dto.OrderResponse, error) {
 	// Check if order already exists for this idempotency key
 	if idempotencyKey != "" {
 		existingKey, err := s.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
@@ -53,7 +73,7 @@ func (s *OrderService) CreateOrderWithIdempotency(ctx context.Context, userID in
 	}
 
 	// Create the order using the existing logic
-	orderResponse, err := s.CreateOrderFromCart(ctx, userID)
+	orderResponse, err := s.CreateOrderFromCart(ctx, userID, req)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +94,16 @@ func (s *OrderService) CreateOrderWithIdempotency(ctx context.Context, userID in
 }
 
 // CreateOrderFromCart creates a new order from the user's cart
-// Uses database transaction with row-level locking to prevent race conditions
-func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int32) (*dto.OrderResponse, error) {
+// Uses database transaction with row-level locking to prevent race conditions.
+// Each line is allocated to one or more warehouses by the configured strategy.
+// The order is charged in req.Currency, and the exchange rate used is locked
+// on the order. Orders the risk scorer flags are placed on_hold for review.
+func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int32, req dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+	quote, err := s.currencies.Quote(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the user's cart (outside transaction - read-only)
 	cart, err := s.store.GetCartByUserID(ctx, userID)
 	if err != nil {
@@ -101,10 +129,13 @@ func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int32) (*
 		productIDs[i] = item.ProductID
 	}
 
-	var order db.Order
+	var (
+		order     db.Order
+		movements []db.InventoryMovement
+	)
 
 	// Execute order creation within a transaction with row locking
-	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+	err = s.store.ExecTx(ctx, func(q db.Querier) error {
 		// Lock product rows with FOR UPDATE to prevent concurrent modifications
 		products, err := q.GetProductsByIDsForUpdate(ctx, productIDs)
 		if err != nil {
@@ -120,74 +151,140 @@ func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int32) (*
 			productMap[p.ID] = p
 		}
 
+		// Stock held by other customers' checkouts is off limits; the
+		// customer's own hold is consumed by this order
+		reserved, err := reservedStock(ctx, q, productIDs, userID)
+		if err != nil {
+			return err
+		}
+
+		// Charge the price in effect when the order is placed, sale or not
+		prices, err := resolvePrices(ctx, q, productIDs, time.Now(), quote)
+		if err != nil {
+			return err
+		}
+
 		// Validate stock and calculate total (under lock)
-		var totalAmount float64
 		for _, item := range cartItems {
 			product, ok := productMap[item.ProductID]
 			if !ok {
 				return ErrProductNotFound
 			}
-			if int(product.Stock.Int32) < int(item.Quantity) {
+			if availableStock(product.Stock, reserved[product.ID]) < int(item.Quantity) {
 				return ErrInsufficientStock
 			}
-			priceFloat, _ := product.Price.Float64Value()
-			totalAmount += priceFloat.Float64 * float64(item.Quantity)
+		}
+		unitPrices, totalAmount, err := priceLines(cartItems, productMap, prices)
+		if err != nil {
+			return err
 		}
 
-		// Create order
-		var totalNumeric pgtype.Numeric
-		if err := totalNumeric.Scan(fmt.Sprintf("%.2f", totalAmount)); err != nil {
-			return fmt.Errorf("failed to parse total amount: %w", err)
+		// Decide which warehouses fulfil each line (under lock)
+		allocations, err := s.allocate(ctx, q, cartItems, productIDs, req.ShippingAddress)
+		if err != nil {
+			return err
 		}
 
+		// Score the order for fraud against the orders placed before it, in
+		// the base currency the thresholds are set in
+		baseTotal, err := totalAmount.Convert(new(big.Rat).Inv(quote.Rate))
+		if err != nil {
+			return fmt.Errorf("failed to convert order total: %w", err)
+		}
+		lines := make([]RiskLine, len(cartItems))
+		for i, item := range cartItems {
+			lines[i] = RiskLine{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+		assessment, err := s.risk.Assess(ctx, q, RiskOrder{
+			UserID:          userID,
+			IPAddress:       req.IPAddress,
+			Total:           baseTotal,
+			Lines:           lines,
+			ShippingAddress: req.ShippingAddress,
+			BillingAddress:  req.BillingAddress,
+			PlacedAt:        time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Create order
 		order, err = q.CreateOrder(ctx, db.CreateOrderParams{
-			UserID:      userID,
-			TotalAmount: totalNumeric,
+			UserID:       userID,
+			TotalAmount:  totalAmount.Numeric(),
+			Currency:     quote.Currency,
+			ExchangeRate: money.RateNumeric(quote.Rate),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create order: %w", err)
 		}
 
-		// Create order items and update stock
-		for _, item := range cartItems {
-			product := productMap[item.ProductID]
-			priceFloat, _ := product.Price.Float64Value()
-
-			var priceNumeric pgtype.Numeric
-			if err := priceNumeric.Scan(fmt.Sprintf("%.2f", priceFloat.Float64)); err != nil {
-				return fmt.Errorf("failed to parse price: %w", err)
+		if assessment != nil {
+			if err := recordRiskAssessment(ctx, q, order.ID, req.IPAddress, assessment); err != nil {
+				return err
+			}
+			// The stock stays allocated to a held order until it is reviewed
+			if assessment.Hold {
+				order, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+					ID:     order.ID,
+					Status: db.NullOrderStatus{OrderStatus: db.OrderStatusOnHold, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to hold order: %w", err)
+				}
 			}
+		}
 
-			_, err := q.CreateOrderItem(ctx, db.CreateOrderItemParams{
+		// Create order items and update stock
+		for i, item := range cartItems {
+			orderItem, err := q.CreateOrderItem(ctx, db.CreateOrderItemParams{
 				OrderID:   order.ID,
 				ProductID: item.ProductID,
 				Quantity:  item.Quantity,
-				Price:     priceNumeric,
+				Price:     unitPrices[i].Numeric(),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create order item: %w", err)
 			}
 
-			// Update product stock (still under lock)
-			newStock := product.Stock.Int32 - item.Quantity
-			_, err = q.UpdateProductStock(ctx, db.UpdateProductStockParams{
-				ID: product.ID,
-				Stock: pgtype.Int4{
-					Int32: newStock,
-					Valid: true,
-				},
-			})
-			if err != nil {
-				return fmt.Errorf("failed to update product stock: %w", err)
+			// Record where the line ships from and take the stock out of that
+			// warehouse and the ledger (still under lock)
+			for _, a := range allocations[item.ProductID] {
+				_, err = q.CreateOrderItemAllocation(ctx, db.CreateOrderItemAllocationParams{
+					OrderItemID: orderItem.ID,
+					WarehouseID: a.WarehouseID,
+					Quantity:    a.Quantity,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create order item allocation: %w", err)
+				}
+
+				movement, err := applyStockMovement(ctx, q, stockMovement{
+					ProductID:   item.ProductID,
+					WarehouseID: a.WarehouseID,
+					Delta:       -a.Quantity,
+					Reason:      db.InventoryMovementReasonOrder,
+					ReferenceID: strconv.Itoa(int(order.ID)),
+					ActorID:     userID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to update product stock: %w", err)
+				}
+				movements = append(movements, movement)
 			}
 		}
 
-		// Clear cart items within transaction
+		// Clear cart items and release the checkout hold within transaction
 		err = q.SoftDeleteCartItemsByCartID(ctx, cart.ID)
 		if err != nil {
 			return fmt.Errorf("failed to clear cart: %w", err)
 		}
 
+		err = q.DeleteStockReservationsByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to release reservation: %w", err)
+		}
+
 		return nil
 	})
 
@@ -195,10 +292,74 @@ func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int32) (*
 		return nil, err
 	}
 
+	_ = s.alerter.Notify(ctx, movements...)
+
 	// Return the order response
 	return s.buildOrderResponse(ctx, order)
 }
 
+// allocate locks the warehouse stock for the cart's products and asks the
+// allocation strategy where to fulfil each line, keyed by product ID
+func (s *OrderService) allocate(ctx context.Context, q db.Querier, cartItems []db.CartItem, productIDs []int32, shipTo *dto.ShippingAddress) (map[int32][]Allocation, error) {
+	rows, err := q.ListAllocatableStockForUpdate(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock warehouse stock: %w", err)
+	}
+
+	var warehouses []WarehouseCandidate
+	index := make(map[int32]int)
+	for _, row := range rows {
+		i, ok := index[row.WarehouseID]
+		if !ok {
+			candidate := WarehouseCandidate{
+				WarehouseID: row.WarehouseID,
+				Code:        row.Code,
+				Priority:    row.Priority,
+				Address: Address{
+					Country:    row.Country,
+					PostalCode: row.PostalCode.String,
+				},
+				Stock: make(map[int32]int32),
+			}
+			if row.Latitude.Valid && row.Longitude.Valid {
+				candidate.Address.Latitude = &row.Latitude.Float64
+				candidate.Address.Longitude = &row.Longitude.Float64
+			}
+			i = len(warehouses)
+			index[row.WarehouseID] = i
+			warehouses = append(warehouses, candidate)
+		}
+		warehouses[i].Stock[row.ProductID] = row.Quantity
+	}
+
+	req := AllocationRequest{
+		Lines:      make([]AllocationLine, len(cartItems)),
+		Warehouses: warehouses,
+	}
+	for i, item := range cartItems {
+		req.Lines[i] = AllocationLine{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	if shipTo != nil {
+		req.Destination = &Address{
+			Country:    shipTo.Country,
+			PostalCode: shipTo.PostalCode,
+			Latitude:   shipTo.Latitude,
+			Longitude:  shipTo.Longitude,
+		}
+	}
+
+	allocations, err := s.allocator.Allocate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[int32][]Allocation, len(cartItems))
+	for _, a := range allocations {
+		byProduct[a.ProductID] = append(byProduct[a.ProductID], a)
+	}
+	return byProduct, nil
+}
+
 // GetOrderByID retrieves an order by ID (validates ownership for non-admin users)
 func (s *OrderService) GetOrderByID(ctx context.Context, userID int32, orderID int32, isAdmin bool) (*dto.OrderResponse, error) {
 	order, err := s.store.GetOrderByID(ctx, orderID)
@@ -258,102 +419,164 @@ func (s *OrderService) GetUserOrders(ctx context.Context, userID int32, page, li
 		return []dto.OrderResponse{}, paginationMeta, nil
 	}
 
-	// Build responses for each order
-	orderResponses := make([]dto.OrderResponse, len(orders))
-	for i, order := range orders {
-		response, err := s.buildOrderResponse(ctx, order)
-		if err != nil {
-			return nil, nil, err
-		}
-		orderResponses[i] = *response
+	orderResponses, err := s.buildOrderResponses(ctx, orders)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	return orderResponses, paginationMeta, nil
 }
 
-// UpdateOrderStatus updates the status of an order (admin function)
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID int32, status string) (*dto.OrderResponse, error) {
+// UpdateOrderStatus updates the status of an order (admin function).
+// Cancelling puts the order's stock back like a customer cancel. Orders are
+// only held, and released, by fraud review.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, adminID, orderID int32, status string) (*dto.OrderResponse, error) {
 	// Validate status
 	orderStatus := db.OrderStatus(status)
 	if !isValidOrderStatus(orderStatus) {
 		return nil, fmt.Errorf("invalid order status: %s", status)
 	}
+	if orderStatus == db.OrderStatusOnHold {
+		return nil, ErrOrderHeldByReview
+	}
 
-	order, err := s.store.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
-		ID: orderID,
-		Status: db.NullOrderStatus{
-			OrderStatus: orderStatus,
-			Valid:       true,
-		},
+	var (
+		order     db.Order
+		movements []db.InventoryMovement
+	)
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		// Lock the order so a concurrent review or cancel waits for this
+		current, err := q.GetOrderByIDForUpdate(ctx, orderID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrOrderNotFound
+			}
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if current.Status.OrderStatus == db.OrderStatusOnHold {
+			return ErrOrderHeldByReview
+		}
+
+		if orderStatus == db.OrderStatusCancelled {
+			order, movements, err = cancelLockedOrder(ctx, q, current, adminID)
+			return err
+		}
+
+		order, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+			ID: orderID,
+			Status: db.NullOrderStatus{
+				OrderStatus: orderStatus,
+				Valid:       true,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrOrderNotFound
-		}
-		return nil, fmt.Errorf("failed to update order status: %w", err)
+		return nil, err
 	}
 
+	_ = s.alerter.Notify(ctx, movements...)
+
 	return s.buildOrderResponse(ctx, order)
 }
 
 // CancelOrder cancels an order (only pending orders can be cancelled)
 func (s *OrderService) CancelOrder(ctx context.Context, userID int32, orderID int32) (*dto.OrderResponse, error) {
-	order, err := s.store.GetOrderByID(ctx, orderID)
+	var (
+		order     db.Order
+		movements []db.InventoryMovement
+	)
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		order, movements, err = cancelOrder(ctx, q, userID, orderID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.alerter.Notify(ctx, movements...)
+
+	return s.buildOrderResponse(ctx, order)
+}
+
+// cancelOrder cancels a customer's order and puts its stock back. The order
+// is locked first, so of two cancels at once only one gets past the status
+// check and restores the stock. It must run inside a transaction.
+func cancelOrder(ctx context.Context, q db.Querier, userID, orderID int32) (db.Order, []db.InventoryMovement, error) {
+	order, err := q.GetOrderByIDForUpdate(ctx, orderID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrOrderNotFound
+			return db.Order{}, nil, ErrOrderNotFound
 		}
-		return nil, fmt.Errorf("failed to get order: %w", err)
+		return db.Order{}, nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
 	// Check ownership
 	if order.UserID != userID {
-		return nil, ErrUnauthorizedOrder
+		return db.Order{}, nil, ErrUnauthorizedOrder
 	}
 
-	// Only pending orders can be cancelled
-	if order.Status.OrderStatus != db.OrderStatusPending {
-		return nil, ErrOrderNotCancellable
+	return cancelLockedOrder(ctx, q, order, userID)
+}
+
+// cancelLockedOrder cancels an order locked by the caller and puts its stock
+// back, recording actorID as who moved it
+func cancelLockedOrder(ctx context.Context, q db.Querier, order db.Order, actorID int32) (db.Order, []db.InventoryMovement, error) {
+	// Only orders that haven't been confirmed can be cancelled
+	if order.Status.OrderStatus != db.OrderStatusPending && order.Status.OrderStatus != db.OrderStatusOnHold {
+		return db.Order{}, nil, ErrOrderNotCancellable
 	}
 
 	// Update order status to cancelled
-	updatedOrder, err := s.store.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
-		ID: orderID,
+	cancelled, err := q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+		ID: order.ID,
 		Status: db.NullOrderStatus{
 			OrderStatus: db.OrderStatusCancelled,
 			Valid:       true,
 		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel order: %w", err)
+		return db.Order{}, nil, fmt.Errorf("failed to cancel order: %w", err)
 	}
 
-	// Restore product stock
-	orderItems, err := s.store.ListOrderItems(ctx, orderID)
+	// Restore product stock to the warehouses it was allocated from
+	allocations, err := q.ListOrderItemAllocationsByOrderID(ctx, order.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list order items: %w", err)
+		return db.Order{}, nil, fmt.Errorf("failed to list order item allocations: %w", err)
 	}
+	movements, err := restoreOrderStock(ctx, q, order.ID, actorID, allocations)
+	if err != nil {
+		return db.Order{}, nil, err
+	}
+	return cancelled, movements, nil
+}
 
-	for _, item := range orderItems {
-		product, err := s.store.GetProductByID(ctx, item.ProductID)
-		if err != nil {
-			continue // Skip if product not found
-		}
-
-		newStock := product.Stock.Int32 + item.Quantity
-		_, err = s.store.UpdateProductStock(ctx, db.UpdateProductStockParams{
-			ID: product.ID,
-			Stock: pgtype.Int4{
-				Int32: newStock,
-				Valid: true,
-			},
+// restoreOrderStock returns a cancelled order's stock to the warehouses it
+// was allocated from
+func restoreOrderStock(ctx context.Context, q db.Querier, orderID, actorID int32, allocations []db.ListOrderItemAllocationsByOrderIDRow) ([]db.InventoryMovement, error) {
+	var movements []db.InventoryMovement
+	for _, a := range allocations {
+		movement, err := applyStockMovement(ctx, q, stockMovement{
+			ProductID:   a.ProductID,
+			WarehouseID: a.WarehouseID,
+			Delta:       a.Quantity,
+			Reason:      db.InventoryMovementReasonCancellation,
+			ReferenceID: strconv.Itoa(int(orderID)),
+			ActorID:     actorID,
 		})
 		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue // Skip if product not found
+			}
 			return nil, fmt.Errorf("failed to restore product stock: %w", err)
 		}
+		movements = append(movements, movement)
 	}
-
-	return s.buildOrderResponse(ctx, updatedOrder)
+	return movements, nil
 }
 
 // buildOrderResponse builds an OrderResponse with order items and product details
@@ -363,10 +586,46 @@ func (s *OrderService) buildOrderResponse(ctx context.Context, order db.Order) (
 		return nil, fmt.Errorf("failed to list order items: %w", err)
 	}
 
-	// Collect product IDs
-	productIDs := make([]int32, len(orderItems))
-	for i, item := range orderItems {
-		productIDs[i] = item.ProductID
+	responses, err := s.orderResponses(ctx, []db.Order{order}, orderItems)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// buildOrderResponses builds OrderResponses for a page of orders, fetching
+// their items and products in batches rather than order by order
+func (s *OrderService) buildOrderResponses(ctx context.Context, orders []db.Order) ([]dto.OrderResponse, error) {
+	if len(orders) == 0 {
+		return []dto.OrderResponse{}, nil
+	}
+
+	orderIDs := make([]int32, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.ID
+	}
+
+	orderItems, err := s.store.ListOrderItemsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order items: %w", err)
+	}
+
+	return s.orderResponses(ctx, orders, orderItems)
+}
+
+// orderResponses builds OrderResponses for orders from all of their items
+func (s *OrderService) orderResponses(ctx context.Context, orders []db.Order, orderItems []db.OrderItem) ([]dto.OrderResponse, error) {
+	// Collect unique product IDs
+	itemsByOrder := make(map[int32][]db.OrderItem, len(orders))
+	productIDSet := make(map[int32]struct{})
+	for _, item := range orderItems {
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+		productIDSet[item.ProductID] = struct{}{}
+	}
+
+	productIDs := make([]int32, 0, len(productIDSet))
+	for id := range productIDSet {
+		productIDs = append(productIDs, id)
 	}
 
 	// Fetch products in batch
@@ -404,54 +663,94 @@ func (s *OrderService) buildOrderResponse(ctx context.Context, order db.Order) (
 		}
 	}
 
-	// Build order item responses
-	orderItemResponses := make([]dto.OrderItemResponse, len(orderItems))
-	for i, item := range orderItems {
-		product := productMap[item.ProductID]
-		category := categoryMap[product.CategoryID]
-		priceFloat, _ := item.Price.Float64Value()
-		productPriceFloat, _ := product.Price.Float64Value()
-
-		orderItemResponses[i] = dto.OrderItemResponse{
-			ID: uint(item.ID), //#nosec G115 -- DB ID is always positive
-			Product: dto.ProductResponse{
-				ID:          uint(product.ID), //#nosec G115 -- DB ID is always positive
-				Name:        product.Name,
-				Description: product.Description.String,
-				Price:       productPriceFloat.Float64,
-				Stock:       int(product.Stock.Int32),
-				CategoryID:  uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
-				SKU:         product.Sku,
-				IsActive:    product.IsActive.Bool,
-				Category: dto.CategoryResponse{
-					ID:          int64(category.ID),
-					Name:        category.Name,
-					Description: category.Description.String,
-					IsActive:    category.IsActive.Bool,
+	reserved, err := reservedStock(ctx, s.store, productIDs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders locked at the same currency and rate share their prices
+	pricesByQuote := make(map[string]priceList)
+
+	responses := make([]dto.OrderResponse, len(orders))
+	for i, order := range orders {
+		// Products show today's price in the order's currency at its locked
+		// rate; the line keeps the price that was charged
+		rate, err := money.RateFromNumeric(order.ExchangeRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read exchange rate of order %d: %w", order.ID, err)
+		}
+		quoteKey := order.Currency + " " + rate.RatString()
+		prices, ok := pricesByQuote[quoteKey]
+		if !ok {
+			quote := Quote{Base: s.currencies.Base(), Currency: order.Currency, Rate: rate}
+			if prices, err = resolvePrices(ctx, s.store, productIDs, time.Now(), quote); err != nil {
+				return nil, err
+			}
+			pricesByQuote[quoteKey] = prices
+		}
+
+		// Build order item responses
+		items := itemsByOrder[order.ID]
+		orderItemResponses := make([]dto.OrderItemResponse, len(items))
+		for j, item := range items {
+			product := productMap[item.ProductID]
+			category := categoryMap[product.CategoryID]
+			price, err := money.FromNumeric(item.Price)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read price of order item %d: %w", item.ID, err)
+			}
+
+			orderItemResponses[j] = dto.OrderItemResponse{
+				ID: uint(item.ID), //#nosec G115 -- DB ID is always positive
+				Product: dto.ProductResponse{
+					ID:             uint(product.ID), //#nosec G115 -- DB ID is always positive
+					Name:           product.Name,
+					Description:    product.Description.String,
+					Stock:          int(product.Stock.Int32),
+					AvailableStock: availableStock(product.Stock, reserved[product.ID]),
+					CategoryID:     uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
+					SKU:            product.Sku,
+					IsActive:       product.IsActive.Bool,
+					Category: dto.CategoryResponse{
+						ID:          int64(category.ID),
+						Name:        category.Name,
+						Description: category.Description.String,
+						IsActive:    category.IsActive.Bool,
+					},
+					Images: []dto.ProductImageResponse{},
 				},
-				Images: []dto.ProductImageResponse{},
-			},
-			Quantity: int(item.Quantity),
-			Price:    priceFloat.Float64,
+				Quantity: int(item.Quantity),
+				Price:    price,
+			}
+			if err := prices.apply(&orderItemResponses[j].Product, product); err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	totalFloat, _ := order.TotalAmount.Float64Value()
+		totalAmount, err := money.FromNumeric(order.TotalAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read total of order %d: %w", order.ID, err)
+		}
+
+		responses[i] = dto.OrderResponse{
+			ID:           uint(order.ID),     //#nosec G115 -- DB ID is always positive
+			UserID:       uint(order.UserID), //#nosec G115 -- DB ID is always positive
+			Status:       string(order.Status.OrderStatus),
+			TotalAmount:  totalAmount,
+			Currency:     order.Currency,
+			ExchangeRate: rate.FloatString(money.RateScale),
+			OrderItems:   orderItemResponses,
+			CreatedAt:    order.CreatedAt.Time,
+			UpdatedAt:    order.UpdatedAt.Time,
+		}
+	}
 
-	return &dto.OrderResponse{
-		ID:          uint(order.ID),     //#nosec G115 -- DB ID is always positive
-		UserID:      uint(order.UserID), //#nosec G115 -- DB ID is always positive
-		Status:      string(order.Status.OrderStatus),
-		TotalAmount: totalFloat.Float64,
-		OrderItems:  orderItemResponses,
-		CreatedAt:   order.CreatedAt.Time,
-		UpdatedAt:   order.UpdatedAt.Time,
-	}, nil
+	return responses, nil
 }
 
 func isValidOrderStatus(status db.OrderStatus) bool {
 	switch status {
-	case db.OrderStatusPending, db.OrderStatusConfirmed, db.OrderStatusShipped, db.OrderStatusDelivered, db.OrderStatusCancelled:
+	case db.OrderStatusPending, db.OrderStatusConfirmed, db.OrderStatusShipped, db.OrderStatusDelivered, db.OrderStatusCancelled, db.OrderStatusOnHold:
 		return true
 	default:
 		return false
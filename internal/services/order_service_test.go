@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/jackc/pgx/v5"
@@ -10,12 +11,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
 	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 )
 
 // MockOrderStore provides mocked store methods for OrderService testing
 type MockOrderStore struct {
 	mock.Mock
+	noopStore
 }
 
 // Order methods
@@ -24,6 +28,11 @@ func (m *MockOrderStore) GetOrderByID(ctx context.Context, id int32) (db.Order,
 	return args.Get(0).(db.Order), args.Error(1)
 }
 
+func (m *MockOrderStore) GetOrderByIDForUpdate(ctx context.Context, id int32) (db.Order, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Order), args.Error(1)
+}
+
 func (m *MockOrderStore) ListOrdersByUserID(ctx context.Context, arg db.ListOrdersByUserIDParams) ([]db.Order, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).([]db.Order), args.Error(1)
@@ -44,6 +53,11 @@ func (m *MockOrderStore) ListOrderItems(ctx context.Context, orderID int32) ([]d
 	return args.Get(0).([]db.OrderItem), args.Error(1)
 }
 
+func (m *MockOrderStore) ListOrderItemsByOrderIDs(ctx context.Context, orderIDs []int32) ([]db.OrderItem, error) {
+	args := m.Called(ctx, orderIDs)
+	return args.Get(0).([]db.OrderItem), args.Error(1)
+}
+
 func (m *MockOrderStore) GetProductsByIDs(ctx context.Context, ids []int32) ([]db.Product, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]db.Product), args.Error(1)
@@ -59,20 +73,32 @@ func (m *MockOrderStore) ListProductImagesByProductIDs(ctx context.Context, ids
 	return args.Get(0).([]db.ProductImage), args.Error(1)
 }
 
-func (m *MockOrderStore) ExecTx(ctx context.Context, fn func(*db.Queries) error) error {
+func (m *MockOrderStore) ListOrderItemAllocationsByOrderID(ctx context.Context, orderID int32) ([]db.ListOrderItemAllocationsByOrderIDRow, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).([]db.ListOrderItemAllocationsByOrderIDRow), args.Error(1)
+}
+
+// ExecTx records the transaction and, unless it is mocked to fail, runs fn
+// against the mock, so the queries made inside it are checked too
+func (m *MockOrderStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
 	args := m.Called(ctx, fn)
-	return args.Error(0)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
 }
 
 // Helper to create test order
 func createTestOrder() db.Order {
 	return db.Order{
-		ID:          1,
-		UserID:      1,
-		Status:      db.NullOrderStatus{OrderStatus: db.OrderStatusPending, Valid: true},
-		TotalAmount: pgtype.Numeric{Valid: true},
-		CreatedAt:   pgtype.Timestamptz{Valid: true},
-		UpdatedAt:   pgtype.Timestamptz{Valid: true},
+		ID:           1,
+		UserID:       1,
+		Status:       db.NullOrderStatus{OrderStatus: db.OrderStatusPending, Valid: true},
+		TotalAmount:  money.FromCents(1999).Numeric(),
+		Currency:     "USD",
+		ExchangeRate: money.RateNumeric(big.NewRat(1, 1)),
+		CreatedAt:    pgtype.Timestamptz{Valid: true},
+		UpdatedAt:    pgtype.Timestamptz{Valid: true},
 	}
 }
 
@@ -123,6 +149,20 @@ func TestOrderService_GetOrderByID(t *testing.T) {
 			wantErr: true,
 			errMsg:  "order not found",
 		},
+		{
+			name:    "error - stored total too large for cents",
+			userID:  1,
+			orderID: 1,
+			isAdmin: false,
+			setupMock: func(m *MockOrderStore) {
+				huge := testOrder
+				huge.TotalAmount = pgtype.Numeric{Int: new(big.Int).Lsh(big.NewInt(1), 70), Valid: true}
+				m.On("GetOrderByID", mock.Anything, int32(1)).Return(huge, nil)
+				m.On("ListOrderItems", mock.Anything, int32(1)).Return([]db.OrderItem{}, nil)
+			},
+			wantErr: true,
+			errMsg:  "failed to read total of order 1",
+		},
 		{
 			name:    "error - unauthorized access",
 			userID:  2, // Different user
@@ -166,13 +206,15 @@ func TestOrderService_UpdateOrderStatus(t *testing.T) {
 
 	updatedOrder := createTestOrder()
 	updatedOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusConfirmed, Valid: true}
+	heldOrder := createTestOrder()
+	heldOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusOnHold, Valid: true}
 
 	tests := []struct {
 		name      string
 		orderID   int32
 		status    string
 		setupMock func(m *MockOrderStore)
-		wantErr   bool
+		wantErr   error
 		errMsg    string
 	}{
 		{
@@ -180,12 +222,13 @@ func TestOrderService_UpdateOrderStatus(t *testing.T) {
 			orderID: 1,
 			status:  "confirmed",
 			setupMock: func(m *MockOrderStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(createTestOrder(), nil)
 				m.On("UpdateOrderStatus", mock.Anything, mock.MatchedBy(func(arg db.UpdateOrderStatusParams) bool {
 					return arg.ID == 1 && arg.Status.OrderStatus == db.OrderStatusConfirmed
 				})).Return(updatedOrder, nil)
 				m.On("ListOrderItems", mock.Anything, int32(1)).Return([]db.OrderItem{}, nil)
 			},
-			wantErr: false,
 		},
 		{
 			name:    "success - status updated to shipped",
@@ -194,10 +237,66 @@ func TestOrderService_UpdateOrderStatus(t *testing.T) {
 			setupMock: func(m *MockOrderStore) {
 				shippedOrder := createTestOrder()
 				shippedOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusShipped, Valid: true}
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(updatedOrder, nil)
 				m.On("UpdateOrderStatus", mock.Anything, mock.Anything).Return(shippedOrder, nil)
 				m.On("ListOrderItems", mock.Anything, int32(1)).Return([]db.OrderItem{}, nil)
 			},
-			wantErr: false,
+		},
+		{
+			name:    "success - cancelling puts the stock back",
+			orderID: 1,
+			status:  "cancelled",
+			setupMock: func(m *MockOrderStore) {
+				cancelled := createTestOrder()
+				cancelled.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusCancelled, Valid: true}
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(createTestOrder(), nil)
+				m.On("UpdateOrderStatus", mock.Anything, mock.Anything).Return(cancelled, nil)
+				m.On("ListOrderItemAllocationsByOrderID", mock.Anything, int32(1)).Return([]db.ListOrderItemAllocationsByOrderIDRow{}, nil)
+				m.On("ListOrderItems", mock.Anything, int32(1)).Return([]db.OrderItem{}, nil)
+			},
+		},
+		{
+			name:    "error - cancelling a shipped order",
+			orderID: 1,
+			status:  "cancelled",
+			setupMock: func(m *MockOrderStore) {
+				shippedOrder := createTestOrder()
+				shippedOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusShipped, Valid: true}
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(shippedOrder, nil)
+			},
+			wantErr: ErrOrderNotCancellable,
+		},
+		{
+			name:    "error - holding an order",
+			orderID: 1,
+			status:  "on_hold",
+			setupMock: func(m *MockOrderStore) {
+				// No mock needed - only the risk scorer holds orders
+			},
+			wantErr: ErrOrderHeldByReview,
+		},
+		{
+			name:    "error - releasing a held order",
+			orderID: 1,
+			status:  "pending",
+			setupMock: func(m *MockOrderStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(heldOrder, nil)
+			},
+			wantErr: ErrOrderHeldByReview,
+		},
+		{
+			name:    "error - cancelling a held order",
+			orderID: 1,
+			status:  "cancelled",
+			setupMock: func(m *MockOrderStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(heldOrder, nil)
+			},
+			wantErr: ErrOrderHeldByReview,
 		},
 		{
 			name:    "error - invalid status",
@@ -206,18 +305,17 @@ func TestOrderService_UpdateOrderStatus(t *testing.T) {
 			setupMock: func(m *MockOrderStore) {
 				// No mock needed - validation fails first
 			},
-			wantErr: true,
-			errMsg:  "invalid order status",
+			errMsg: "invalid order status",
 		},
 		{
 			name:    "error - order not found",
 			orderID: 999,
 			status:  "confirmed",
 			setupMock: func(m *MockOrderStore) {
-				m.On("UpdateOrderStatus", mock.Anything, mock.Anything).Return(db.Order{}, pgx.ErrNoRows)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(999)).Return(db.Order{}, pgx.ErrNoRows)
 			},
-			wantErr: true,
-			errMsg:  "order not found",
+			wantErr: ErrOrderNotFound,
 		},
 	}
 
@@ -230,18 +328,23 @@ func TestOrderService_UpdateOrderStatus(t *testing.T) {
 
 			service := &OrderService{store: createOrderStoreWrapper(mockStore)}
 
-			resp, err := service.UpdateOrderStatus(context.Background(), tt.orderID, tt.status)
+			resp, err := service.UpdateOrderStatus(context.Background(), 9, tt.orderID, tt.status)
 
-			if tt.wantErr {
+			if tt.wantErr != nil || tt.errMsg != "" {
 				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
 				if tt.errMsg != "" {
 					assert.Contains(t, err.Error(), tt.errMsg)
 				}
+				mockStore.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything)
 				return
 			}
 
 			require.NoError(t, err)
 			assert.NotNil(t, resp)
+			mockStore.AssertExpectations(t)
 		})
 	}
 }
@@ -249,60 +352,124 @@ func TestOrderService_UpdateOrderStatus(t *testing.T) {
 func TestOrderService_CancelOrder(t *testing.T) {
 	t.Parallel()
 
+	t.Run("success - cancelled in a transaction", func(t *testing.T) {
+		t.Parallel()
+
+		cancelled := createTestOrder()
+		cancelled.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusCancelled, Valid: true}
+
+		mockStore := new(MockOrderStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(createTestOrder(), nil)
+		mockStore.On("UpdateOrderStatus", mock.Anything, mock.Anything).Return(cancelled, nil)
+		mockStore.On("ListOrderItemAllocationsByOrderID", mock.Anything, int32(1)).Return([]db.ListOrderItemAllocationsByOrderIDRow{}, nil)
+		mockStore.On("ListOrderItems", mock.Anything, mock.Anything).Return([]db.OrderItem{}, nil)
+
+		service := &OrderService{store: createOrderStoreWrapper(mockStore)}
+		resp, err := service.CancelOrder(context.Background(), 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, string(db.OrderStatusCancelled), resp.Status)
+		mockStore.AssertNotCalled(t, "GetOrderByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - transaction fails", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockOrderStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(ErrOrderNotCancellable)
+
+		service := &OrderService{store: createOrderStoreWrapper(mockStore)}
+		_, err := service.CancelOrder(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrOrderNotCancellable)
+		mockStore.AssertNotCalled(t, "ListOrderItems", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	t.Parallel()
+
 	pendingOrder := createTestOrder()
+	heldOrder := createTestOrder()
+	heldOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusOnHold, Valid: true}
 	confirmedOrder := createTestOrder()
 	confirmedOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusConfirmed, Valid: true}
+	cancelledOrder := createTestOrder()
+	cancelledOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusCancelled, Valid: true}
+
+	restoresStock := func(m *mocks.MockStore) {
+		m.On("UpdateOrderStatus", mock.Anything, db.UpdateOrderStatusParams{
+			ID:     1,
+			Status: db.NullOrderStatus{OrderStatus: db.OrderStatusCancelled, Valid: true},
+		}).Return(cancelledOrder, nil)
+		m.On("ListOrderItemAllocationsByOrderID", mock.Anything, int32(1)).Return([]db.ListOrderItemAllocationsByOrderIDRow{
+			{ID: 1, OrderItemID: 1, WarehouseID: 2, Quantity: 3, ProductID: 5},
+		}, nil)
+		m.On("AdjustProductStock", mock.Anything, db.AdjustProductStockParams{Delta: 3, ID: 5}).
+			Return(db.Product{ID: 5, Stock: pgtype.Int4{Int32: 8, Valid: true}}, nil)
+		m.On("AdjustWarehouseStock", mock.Anything, db.AdjustWarehouseStockParams{WarehouseID: 2, ProductID: 5, Delta: 3}).
+			Return(db.WarehouseStock{}, nil)
+		m.On("CreateInventoryMovement", mock.Anything, mock.MatchedBy(func(arg db.CreateInventoryMovementParams) bool {
+			return arg.Reason == db.InventoryMovementReasonCancellation && arg.ReferenceID.String == "1" && arg.QuantityChange == 3
+		})).Return(db.InventoryMovement{ProductID: 5, QuantityChange: 3, StockAfter: 8}, nil)
+	}
 
 	tests := []struct {
-		name      string
-		userID    int32
-		orderID   int32
-		setupMock func(m *MockOrderStore)
-		wantErr   bool
-		errMsg    string
+		name          string
+		userID        int32
+		setupMock     func(m *mocks.MockStore)
+		wantErr       error
+		wantMovements int
 	}{
 		{
-			name:    "success - pending order cancelled",
-			userID:  1,
-			orderID: 1,
-			setupMock: func(m *MockOrderStore) {
-				m.On("GetOrderByID", mock.Anything, int32(1)).Return(pendingOrder, nil)
-				cancelledOrder := pendingOrder
-				cancelledOrder.Status = db.NullOrderStatus{OrderStatus: db.OrderStatusCancelled, Valid: true}
-				m.On("UpdateOrderStatus", mock.Anything, mock.Anything).Return(cancelledOrder, nil)
-				m.On("ListOrderItems", mock.Anything, int32(1)).Return([]db.OrderItem{}, nil)
+			name:   "pending order cancelled and its stock restored",
+			userID: 1,
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(pendingOrder, nil)
+				restoresStock(m)
 			},
-			wantErr: false,
+			wantMovements: 1,
 		},
 		{
-			name:    "error - order not found",
-			userID:  1,
-			orderID: 999,
-			setupMock: func(m *MockOrderStore) {
-				m.On("GetOrderByID", mock.Anything, int32(999)).Return(db.Order{}, pgx.ErrNoRows)
+			name:   "held order cancelled",
+			userID: 1,
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(heldOrder, nil)
+				restoresStock(m)
 			},
-			wantErr: true,
-			errMsg:  "order not found",
+			wantMovements: 1,
 		},
 		{
-			name:    "error - not owner",
-			userID:  2, // Different user
-			orderID: 1,
-			setupMock: func(m *MockOrderStore) {
-				m.On("GetOrderByID", mock.Anything, int32(1)).Return(pendingOrder, nil)
+			name:   "order not found",
+			userID: 1,
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(db.Order{}, pgx.ErrNoRows)
 			},
-			wantErr: true,
-			errMsg:  "unauthorized",
+			wantErr: ErrOrderNotFound,
 		},
 		{
-			name:    "error - order not pending",
-			userID:  1,
-			orderID: 1,
-			setupMock: func(m *MockOrderStore) {
-				m.On("GetOrderByID", mock.Anything, int32(1)).Return(confirmedOrder, nil)
+			name:   "not owner",
+			userID: 2,
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(pendingOrder, nil)
 			},
-			wantErr: true,
-			errMsg:  "cannot be cancelled",
+			wantErr: ErrUnauthorizedOrder,
+		},
+		{
+			name:   "order not pending",
+			userID: 1,
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(confirmedOrder, nil)
+			},
+			wantErr: ErrOrderNotCancellable,
+		},
+		{
+			// A concurrent cancel committed first; the lock makes this one see it
+			name:   "already cancelled",
+			userID: 1,
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetOrderByIDForUpdate", mock.Anything, int32(1)).Return(cancelledOrder, nil)
+			},
+			wantErr: ErrOrderNotCancellable,
 		},
 	}
 
@@ -310,23 +477,22 @@ func TestOrderService_CancelOrder(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockStore := new(MockOrderStore)
+			mockStore := new(mocks.MockStore)
 			tt.setupMock(mockStore)
 
-			service := &OrderService{store: createOrderStoreWrapper(mockStore)}
-
-			resp, err := service.CancelOrder(context.Background(), tt.userID, tt.orderID)
+			order, movements, err := cancelOrder(context.Background(), mockStore, tt.userID, 1)
 
-			if tt.wantErr {
-				assert.Error(t, err)
-				if tt.errMsg != "" {
-					assert.Contains(t, err.Error(), tt.errMsg)
-				}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything)
+				mockStore.AssertNotCalled(t, "AdjustProductStock", mock.Anything, mock.Anything)
 				return
 			}
 
 			require.NoError(t, err)
-			assert.NotNil(t, resp)
+			assert.Equal(t, db.OrderStatusCancelled, order.Status.OrderStatus)
+			assert.Len(t, movements, tt.wantMovements)
+			mockStore.AssertExpectations(t)
 		})
 	}
 }
@@ -351,9 +517,18 @@ func TestOrderService_GetUserOrders(t *testing.T) {
 			page:   1,
 			limit:  10,
 			setupMock: func(m *MockOrderStore) {
+				secondOrder := createTestOrder()
+				secondOrder.ID = 2
 				m.On("CountOrdersByUserID", mock.Anything, int32(1)).Return(int64(2), nil)
-				m.On("ListOrdersByUserID", mock.Anything, mock.Anything).Return([]db.Order{testOrder, testOrder}, nil)
-				m.On("ListOrderItems", mock.Anything, mock.Anything).Return([]db.OrderItem{}, nil)
+				m.On("ListOrdersByUserID", mock.Anything, mock.Anything).Return([]db.Order{testOrder, secondOrder}, nil)
+				// Items of the whole page are fetched at once
+				m.On("ListOrderItemsByOrderIDs", mock.Anything, []int32{1, 2}).Return([]db.OrderItem{
+					{ID: 1, OrderID: 1, ProductID: 1, Quantity: 1, Price: money.FromCents(1999).Numeric()},
+					{ID: 2, OrderID: 2, ProductID: 1, Quantity: 2, Price: money.FromCents(1999).Numeric()},
+					{ID: 3, OrderID: 2, ProductID: 1, Quantity: 1, Price: money.FromCents(1999).Numeric()},
+				}, nil)
+				m.On("GetProductsByIDs", mock.Anything, []int32{1}).Return([]db.Product{{ID: 1, Name: "Test Product", Price: money.FromCents(1999).Numeric()}}, nil)
+				m.On("GetCategoriesByIDs", mock.Anything, mock.Anything).Return([]db.Category{}, nil)
 			},
 			wantLen: 2,
 			wantErr: false,
@@ -402,12 +577,15 @@ func TestOrderService_GetUserOrders(t *testing.T) {
 			assert.Len(t, resp, tt.wantLen)
 			if tt.wantLen > 0 {
 				assert.NotNil(t, meta)
+				assert.Len(t, resp[0].OrderItems, 1)
+				assert.Len(t, resp[1].OrderItems, 2)
 			}
+			mockStore.AssertNotCalled(t, "ListOrderItems", mock.Anything, mock.Anything)
 		})
 	}
 }
 
-// orderStoreWrapper wraps MockOrderStore to implement db.Store interface
+// orderStoreWrapper wraps MockOrderStore as a db.Store; queries it doesn't mock fall through to noopStore
 type orderStoreWrapper struct {
 	*MockOrderStore
 }
@@ -415,218 +593,3 @@ type orderStoreWrapper struct {
 func createOrderStoreWrapper(m *MockOrderStore) db.Store {
 	return &orderStoreWrapper{MockOrderStore: m}
 }
-
-// Implement remaining db.Store methods as stubs
-func (s *orderStoreWrapper) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *orderStoreWrapper) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *orderStoreWrapper) GetUserByID(ctx context.Context, id int32) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *orderStoreWrapper) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *orderStoreWrapper) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) error {
-	return nil
-}
-func (s *orderStoreWrapper) UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *orderStoreWrapper) UpdateUserStatus(ctx context.Context, arg db.UpdateUserStatusParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteUser(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) ListUsers(ctx context.Context, arg db.ListUsersParams) ([]db.User, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) CountUsers(ctx context.Context) (int64, error) { return 0, nil }
-func (s *orderStoreWrapper) CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) (db.RefreshToken, error) {
-	return db.RefreshToken{}, nil
-}
-func (s *orderStoreWrapper) GetRefreshToken(ctx context.Context, token string) (db.RefreshToken, error) {
-	return db.RefreshToken{}, nil
-}
-func (s *orderStoreWrapper) DeleteRefreshToken(ctx context.Context, token string) error { return nil }
-func (s *orderStoreWrapper) DeleteRefreshTokensByUserID(ctx context.Context, userID int32) error {
-	return nil
-}
-func (s *orderStoreWrapper) DeleteExpiredRefreshTokens(ctx context.Context) error { return nil }
-func (s *orderStoreWrapper) GetRefreshTokensByUserID(ctx context.Context, userID int32) ([]db.RefreshToken, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) CreateCart(ctx context.Context, userID int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *orderStoreWrapper) GetCartByUserID(ctx context.Context, userID int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *orderStoreWrapper) GetCartByID(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *orderStoreWrapper) UpdateCartTimestamp(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteCart(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) SoftDeleteCartByUserID(ctx context.Context, userID int32) error {
-	return nil
-}
-func (s *orderStoreWrapper) CreateCartItem(ctx context.Context, arg db.CreateCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *orderStoreWrapper) GetCartItem(ctx context.Context, arg db.GetCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *orderStoreWrapper) GetCartItemByID(ctx context.Context, id int32) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *orderStoreWrapper) ListCartItems(ctx context.Context, cartID int32) ([]db.CartItem, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) UpdateCartItemQuantity(ctx context.Context, arg db.UpdateCartItemQuantityParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *orderStoreWrapper) UpsertCartItem(ctx context.Context, arg db.UpsertCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *orderStoreWrapper) RestoreCartItem(ctx context.Context, arg db.RestoreCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteCartItem(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) SoftDeleteCartItemsByCartID(ctx context.Context, cartID int32) error {
-	return nil
-}
-func (s *orderStoreWrapper) CountCartItems(ctx context.Context, cartID int32) (int64, error) {
-	return 0, nil
-}
-func (s *orderStoreWrapper) CreateCategory(ctx context.Context, arg db.CreateCategoryParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *orderStoreWrapper) GetCategoryByID(ctx context.Context, id int32) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *orderStoreWrapper) ListCategories(ctx context.Context, arg db.ListCategoriesParams) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) ListActiveCategories(ctx context.Context) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) UpdateCategory(ctx context.Context, arg db.UpdateCategoryParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *orderStoreWrapper) UpdateCategoryStatus(ctx context.Context, arg db.UpdateCategoryStatusParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteCategory(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) CountCategories(ctx context.Context) (int64, error)     { return 0, nil }
-func (s *orderStoreWrapper) CreateProduct(ctx context.Context, arg db.CreateProductParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) GetProductByID(ctx context.Context, id int32) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) GetProductBySKU(ctx context.Context, sku string) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) GetProductsByIDsForUpdate(ctx context.Context, ids []int32) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) ListProducts(ctx context.Context, arg db.ListProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) ListActiveProducts(ctx context.Context, arg db.ListActiveProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) ListProductsByCategory(ctx context.Context, arg db.ListProductsByCategoryParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) UpdateProduct(ctx context.Context, arg db.UpdateProductParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) UpdateProductStatus(ctx context.Context, arg db.UpdateProductStatusParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) UpdateProductStock(ctx context.Context, arg db.UpdateProductStockParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteProduct(ctx context.Context, id int32) error  { return nil }
-func (s *orderStoreWrapper) CountProducts(ctx context.Context) (int64, error)       { return 0, nil }
-func (s *orderStoreWrapper) CountActiveProducts(ctx context.Context) (int64, error) { return 0, nil }
-func (s *orderStoreWrapper) CountProductsByCategory(ctx context.Context, categoryID int32) (int64, error) {
-	return 0, nil
-}
-func (s *orderStoreWrapper) CreateProductImage(ctx context.Context, arg db.CreateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *orderStoreWrapper) GetProductImageByID(ctx context.Context, id int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *orderStoreWrapper) GetPrimaryProductImage(ctx context.Context, productID int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *orderStoreWrapper) ListProductImages(ctx context.Context, productID int32) ([]db.ProductImage, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) UpdateProductImage(ctx context.Context, arg db.UpdateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *orderStoreWrapper) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) error {
-	return nil
-}
-func (s *orderStoreWrapper) SoftDeleteProductImage(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) error {
-	return nil
-}
-func (s *orderStoreWrapper) CreateOrder(ctx context.Context, arg db.CreateOrderParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *orderStoreWrapper) ListOrders(ctx context.Context, arg db.ListOrdersParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) ListOrdersByStatus(ctx context.Context, arg db.ListOrdersByStatusParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) UpdateOrderTotal(ctx context.Context, arg db.UpdateOrderTotalParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteOrder(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) CountOrders(ctx context.Context) (int64, error)      { return 0, nil }
-func (s *orderStoreWrapper) CountOrdersByStatus(ctx context.Context, status db.NullOrderStatus) (int64, error) {
-	return 0, nil
-}
-func (s *orderStoreWrapper) GetOrderTotal(ctx context.Context, orderID int32) (pgtype.Numeric, error) {
-	return pgtype.Numeric{}, nil
-}
-func (s *orderStoreWrapper) CreateOrderItem(ctx context.Context, arg db.CreateOrderItemParams) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *orderStoreWrapper) GetOrderItemByID(ctx context.Context, id int32) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *orderStoreWrapper) SoftDeleteOrderItem(ctx context.Context, id int32) error { return nil }
-func (s *orderStoreWrapper) SoftDeleteOrderItemsByOrderID(ctx context.Context, orderID int32) error {
-	return nil
-}
-func (s *orderStoreWrapper) CountOrderItems(ctx context.Context, orderID int32) (int64, error) {
-	return 0, nil
-}
-func (s *orderStoreWrapper) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *orderStoreWrapper) GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *orderStoreWrapper) UpdateIdempotencyKeyOrderID(ctx context.Context, arg db.UpdateIdempotencyKeyOrderIDParams) error {
-	return nil
-}
-func (s *orderStoreWrapper) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
-	return nil, nil
-}
-func (s *orderStoreWrapper) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
-	return 0, nil
-}
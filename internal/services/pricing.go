@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+// effectivePrice is what a product sells for at a point in time
+type effectivePrice struct {
+	Price     money.Money  // the sale price while a sale runs, otherwise the list price
+	CompareAt *money.Money // the list price while a sale runs; nil otherwise
+}
+
+// priceList holds what a set of products sell for in one currency
+type priceList struct {
+	quote  Quote
+	prices map[int32]effectivePrice
+}
+
+// pricePoints are a product's list and sale price in one currency at one time
+type pricePoints struct {
+	list *money.Money
+	sale *money.Money
+}
+
+// resolvePrices returns the effective price of each product at the given
+// time in the quoted currency: the newest sale running then, else the newest
+// list price in force then. Prices come from the currency's own price list
+// where it has them and are otherwise converted from the base currency at
+// the quoted rate. Products with no price history are left out; prices.of
+// falls back to their current list price.
+func resolvePrices(ctx context.Context, q db.Querier, productIDs []int32, at time.Time, quote Quote) (priceList, error) {
+	prices := priceList{quote: quote, prices: make(map[int32]effectivePrice, len(productIDs))}
+	if len(productIDs) == 0 {
+		return prices, nil
+	}
+
+	base, err := fetchPricePoints(ctx, q, productIDs, at, quote.Base, true)
+	if err != nil {
+		return priceList{}, err
+	}
+
+	local := base
+	if quote.Currency != quote.Base {
+		if local, err = fetchPricePoints(ctx, q, productIDs, at, quote.Currency, false); err != nil {
+			return priceList{}, err
+		}
+	}
+
+	for productID, points := range base {
+		if points.list == nil {
+			continue
+		}
+		listPrice, err := points.list.Convert(quote.Rate)
+		if err != nil {
+			return priceList{}, fmt.Errorf("failed to convert list price of product %d: %w", productID, err)
+		}
+		if p := local[productID].list; p != nil {
+			listPrice = *p
+		}
+
+		var salePrice *money.Money
+		switch {
+		case local[productID].sale != nil:
+			salePrice = local[productID].sale
+		case points.sale != nil:
+			converted, err := points.sale.Convert(quote.Rate)
+			if err != nil {
+				return priceList{}, fmt.Errorf("failed to convert sale price of product %d: %w", productID, err)
+			}
+			salePrice = &converted
+		}
+
+		if salePrice == nil {
+			prices.prices[productID] = effectivePrice{Price: listPrice}
+			continue
+		}
+		prices.prices[productID] = effectivePrice{Price: *salePrice, CompareAt: &listPrice}
+	}
+	return prices, nil
+}
+
+// fetchPricePoints loads list and sale prices in one currency. In the base
+// currency a product always has a list price, its current one if the history
+// has nothing older.
+func fetchPricePoints(ctx context.Context, q db.Querier, productIDs []int32, at time.Time, currency string, isBase bool) (map[int32]pricePoints, error) {
+	rows, err := q.GetEffectivePrices(ctx, db.GetEffectivePricesParams{
+		Currency:   currency,
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+		ProductIds: productIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve prices: %w", err)
+	}
+
+	points := make(map[int32]pricePoints, len(rows))
+	for _, row := range rows {
+		var p pricePoints
+
+		listPrice := row.ListPrice
+		if !listPrice.Valid && isBase {
+			listPrice = row.CurrentPrice
+		}
+		if listPrice.Valid {
+			price, err := money.FromNumeric(listPrice)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read list price of product %d: %w", row.ProductID, err)
+			}
+			p.list = &price
+		}
+
+		if row.SalePrice.Valid {
+			price, err := money.FromNumeric(row.SalePrice)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read sale price of product %d: %w", row.ProductID, err)
+			}
+			p.sale = &price
+		}
+
+		points[row.ProductID] = p
+	}
+	return points, nil
+}
+
+// of looks up a product's resolved price, falling back to its current list
+// price converted at the quoted rate
+func (l priceList) of(product db.Product) (effectivePrice, error) {
+	if price, ok := l.prices[product.ID]; ok {
+		return price, nil
+	}
+	listPrice, err := money.FromNumeric(product.Price)
+	if err != nil {
+		return effectivePrice{}, fmt.Errorf("failed to read price of product %d: %w", product.ID, err)
+	}
+	converted, err := listPrice.Convert(l.quote.Rate)
+	if err != nil {
+		return effectivePrice{}, fmt.Errorf("failed to convert price of product %d: %w", product.ID, err)
+	}
+	return effectivePrice{Price: converted}, nil
+}
+
+// apply fills a product response's price fields
+func (l priceList) apply(resp *dto.ProductResponse, product db.Product) error {
+	price, err := l.of(product)
+	if err != nil {
+		return err
+	}
+	resp.Price = price.Price
+	resp.CompareAtPrice = price.CompareAt
+	resp.Currency = l.quote.Currency
+	return nil
+}
+
+// priceLines prices each cart line at its product's effective price. The
+// total is the exact sum of the line totals, so an order's total always
+// matches its items.
+func priceLines(items []db.CartItem, products map[int32]db.Product, prices priceList) (unitPrices []money.Money, total money.Money, err error) {
+	unitPrices = make([]money.Money, len(items))
+	for i, item := range items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return nil, 0, ErrProductNotFound
+		}
+		price, err := prices.of(product)
+		if err != nil {
+			return nil, 0, err
+		}
+		unitPrices[i] = price.Price
+		lineTotal, err := unitPrices[i].Mul(int64(item.Quantity))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to total cart item %d: %w", item.ID, err)
+		}
+		if total, err = total.Add(lineTotal); err != nil {
+			return nil, 0, fmt.Errorf("failed to total cart: %w", err)
+		}
+	}
+	return unitPrices, total, nil
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+func testNumeric(t *testing.T, value string) pgtype.Numeric {
+	t.Helper()
+	var n pgtype.Numeric
+	require.NoError(t, n.Scan(value))
+	return n
+}
+
+func TestResolvePrices(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("GetEffectivePrices", mock.Anything, db.GetEffectivePricesParams{
+		Currency:   "USD",
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+		ProductIds: []int32{1, 2, 3},
+	}).Return([]db.GetEffectivePricesRow{
+		{ProductID: 1, ListPrice: testNumeric(t, "100.00"), SalePrice: testNumeric(t, "79.99")},
+		{ProductID: 2, ListPrice: testNumeric(t, "25.00")},
+	}, nil)
+
+	prices, err := resolvePrices(context.Background(), mockStore, []int32{1, 2, 3}, at, baseQuote("USD"))
+	require.NoError(t, err)
+
+	var onSale, listed, untracked dto.ProductResponse
+	prices.apply(&onSale, db.Product{ID: 1})
+	prices.apply(&listed, db.Product{ID: 2})
+	prices.apply(&untracked, db.Product{ID: 3, Price: testNumeric(t, "9.50")})
+
+	assert.Equal(t, money.FromCents(7999), onSale.Price)
+	require.NotNil(t, onSale.CompareAtPrice)
+	assert.Equal(t, money.FromCents(10000), *onSale.CompareAtPrice)
+
+	assert.Equal(t, money.FromCents(2500), listed.Price)
+	assert.Nil(t, listed.CompareAtPrice)
+
+	assert.Equal(t, money.FromCents(950), untracked.Price)
+	assert.Nil(t, untracked.CompareAtPrice)
+	assert.Equal(t, "USD", untracked.Currency)
+	mockStore.AssertExpectations(t)
+}
+
+func TestResolvePrices_OtherCurrency(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	quote := Quote{Base: "USD", Currency: "EUR", Rate: big.NewRat(92, 100)}
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("GetEffectivePrices", mock.Anything, db.GetEffectivePricesParams{
+		Currency:   "USD",
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+		ProductIds: []int32{1, 2, 3},
+	}).Return([]db.GetEffectivePricesRow{
+		{ProductID: 1, CurrentPrice: testNumeric(t, "10.00"), ListPrice: testNumeric(t, "10.00")},
+		{ProductID: 2, CurrentPrice: testNumeric(t, "20.00"), ListPrice: testNumeric(t, "20.00"), SalePrice: testNumeric(t, "15.55")},
+		{ProductID: 3, CurrentPrice: testNumeric(t, "30.00"), ListPrice: testNumeric(t, "30.00")},
+	}, nil)
+	mockStore.On("GetEffectivePrices", mock.Anything, db.GetEffectivePricesParams{
+		Currency:   "EUR",
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+		ProductIds: []int32{1, 2, 3},
+	}).Return([]db.GetEffectivePricesRow{
+		{ProductID: 1, CurrentPrice: testNumeric(t, "10.00")},
+		{ProductID: 2, CurrentPrice: testNumeric(t, "20.00")},
+		{ProductID: 3, CurrentPrice: testNumeric(t, "30.00"), ListPrice: testNumeric(t, "25.00"), SalePrice: testNumeric(t, "19.99")},
+	}, nil)
+
+	prices, err := resolvePrices(context.Background(), mockStore, []int32{1, 2, 3}, at, quote)
+	require.NoError(t, err)
+
+	var converted, convertedSale, local, untracked dto.ProductResponse
+	prices.apply(&converted, db.Product{ID: 1})
+	prices.apply(&convertedSale, db.Product{ID: 2})
+	prices.apply(&local, db.Product{ID: 3})
+	prices.apply(&untracked, db.Product{ID: 4, Price: testNumeric(t, "9.99")})
+
+	// No EUR price list entry: the USD list price at the quoted rate
+	assert.Equal(t, money.FromCents(920), converted.Price)
+	assert.Nil(t, converted.CompareAtPrice)
+
+	// A USD sale converts too; 15.55 * 0.92 = 14.306 rounds to 14.31
+	assert.Equal(t, money.FromCents(1431), convertedSale.Price)
+	require.NotNil(t, convertedSale.CompareAtPrice)
+	assert.Equal(t, money.FromCents(1840), *convertedSale.CompareAtPrice)
+
+	// EUR prices win over conversion
+	assert.Equal(t, money.FromCents(1999), local.Price)
+	require.NotNil(t, local.CompareAtPrice)
+	assert.Equal(t, money.FromCents(2500), *local.CompareAtPrice)
+
+	// 9.99 * 0.92 = 9.1908 rounds to 9.19
+	assert.Equal(t, money.FromCents(919), untracked.Price)
+	assert.Equal(t, "EUR", untracked.Currency)
+	mockStore.AssertExpectations(t)
+}
+
+func TestResolvePrices_NoProducts(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+
+	prices, err := resolvePrices(context.Background(), mockStore, nil, time.Now(), baseQuote("USD"))
+
+	require.NoError(t, err)
+	assert.Empty(t, prices.prices)
+	mockStore.AssertNotCalled(t, "GetEffectivePrices", mock.Anything, mock.Anything)
+}
+
+func TestPriceList_Of_InvalidPrice(t *testing.T) {
+	t.Parallel()
+
+	prices := priceList{quote: baseQuote("USD"), prices: map[int32]effectivePrice{}}
+
+	_, err := prices.of(db.Product{ID: 1, Price: pgtype.Numeric{Valid: true}})
+
+	assert.ErrorIs(t, err, money.ErrInvalidAmount)
+}
+
+func TestProductService_ScheduleSalePrice(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(72 * time.Hour)
+
+	tests := []struct {
+		name      string
+		req       dto.CreateSalePriceRequest
+		setupMock func(m *mocks.MockStore)
+		wantErr   error
+	}{
+		{
+			name: "success - scheduled sale",
+			req:  dto.CreateSalePriceRequest{Price: money.FromCents(7999), StartsAt: &startsAt, EndsAt: &endsAt},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				m.On("GetEffectivePrices", mock.Anything, mock.MatchedBy(func(arg db.GetEffectivePricesParams) bool {
+					return arg.Currency == "USD" && arg.At.Time.Equal(startsAt)
+				})).Return([]db.GetEffectivePricesRow{}, nil)
+				m.On("CreateProductPrice", mock.Anything, mock.MatchedBy(func(arg db.CreateProductPriceParams) bool {
+					return arg.ProductID == 1 &&
+						arg.Currency == "USD" &&
+						arg.Kind == db.ProductPriceKindSale &&
+						arg.StartsAt.Time.Equal(startsAt) &&
+						arg.EndsAt.Valid && arg.EndsAt.Time.Equal(endsAt) &&
+						arg.CreatedBy == pgtype.Int4{Int32: 7, Valid: true}
+				})).Return(db.ProductPrice{
+					ID:        4,
+					ProductID: 1,
+					Kind:      db.ProductPriceKindSale,
+					Price:     testNumeric(t, "79.99"),
+					StartsAt:  pgtype.Timestamptz{Time: startsAt, Valid: true},
+					EndsAt:    pgtype.Timestamptz{Time: endsAt, Valid: true},
+					CreatedBy: pgtype.Int4{Int32: 7, Valid: true},
+				}, nil)
+			},
+		},
+		{
+			name:      "error - ends before it starts",
+			req:       dto.CreateSalePriceRequest{Price: money.FromCents(7999), StartsAt: &endsAt, EndsAt: &startsAt},
+			setupMock: func(m *mocks.MockStore) {},
+			wantErr:   ErrInvalidSalePeriod,
+		},
+		{
+			name: "error - not below the list price when it starts",
+			req:  dto.CreateSalePriceRequest{Price: money.FromCents(7999), StartsAt: &startsAt, EndsAt: &endsAt},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				// The list price drops to 79.99 before the sale starts
+				m.On("GetEffectivePrices", mock.Anything, mock.Anything).Return([]db.GetEffectivePricesRow{
+					{ProductID: 1, ListPrice: testNumeric(t, "79.99"), CurrentPrice: testNumeric(t, "99.99")},
+				}, nil)
+			},
+			wantErr: ErrSaleNotBelowList,
+		},
+		{
+			name: "error - not below the list price under a running sale",
+			req:  dto.CreateSalePriceRequest{Price: money.FromCents(9999), StartsAt: &startsAt},
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				m.On("GetEffectivePrices", mock.Anything, mock.Anything).Return([]db.GetEffectivePricesRow{
+					{ProductID: 1, ListPrice: testNumeric(t, "99.99"), SalePrice: testNumeric(t, "89.99")},
+				}, nil)
+			},
+			wantErr: ErrSaleNotBelowList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewProductService(mockStore, nil, nil, nil, nil, nil, nil)
+
+			resp, err := service.ScheduleSalePrice(context.Background(), 7, 1, tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertExpectations(t)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint(4), resp.ID)
+			assert.Equal(t, "sale", resp.Kind)
+			assert.Equal(t, money.FromCents(7999), resp.Price)
+			require.NotNil(t, resp.EndsAt)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+// pricedLine is a random cart line for the totals property tests
+type pricedLine struct {
+	ListCents uint32
+	SaleCents uint32 // zero when the product is not on sale
+	Quantity  uint8
+}
+
+// Cart and order totals always equal the exact sum of their lines, and the
+// cart shows the same total the order will charge
+func TestTotalsMatchLinesProperty(t *testing.T) {
+	t.Parallel()
+
+	totalsMatch := func(lines []pricedLine) bool {
+		items := make([]db.CartItem, len(lines))
+		products := make([]db.Product, len(lines))
+		productMap := make(map[int32]db.Product, len(lines))
+		productIDs := make([]int32, len(lines))
+		priceRows := make([]db.GetEffectivePricesRow, 0, len(lines))
+		for i, line := range lines {
+			id := int32(i + 1) //#nosec G115 -- small test slice
+			quantity := int32(line.Quantity) + 1
+			items[i] = db.CartItem{ID: id, CartID: 1, ProductID: id, Quantity: quantity}
+			products[i] = db.Product{ID: id, CategoryID: 1, Price: money.FromCents(int64(line.ListCents)).Numeric()}
+			productMap[id] = products[i]
+			productIDs[i] = id
+			if line.SaleCents > 0 {
+				priceRows = append(priceRows, db.GetEffectivePricesRow{
+					ProductID: id,
+					ListPrice: products[i].Price,
+					SalePrice: money.FromCents(int64(line.SaleCents)).Numeric(),
+				})
+			}
+		}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListCartItems", mock.Anything, int32(1)).Return(items, nil)
+		mockStore.On("GetProductsByIDs", mock.Anything, productIDs).Return(products, nil)
+		mockStore.On("GetCategoriesByIDs", mock.Anything, mock.Anything).Return([]db.Category{{ID: 1}}, nil)
+		mockStore.On("GetReservedStockByProductIDs", mock.Anything, mock.Anything).Return([]db.GetReservedStockByProductIDsRow{}, nil)
+		mockStore.On("GetEffectivePrices", mock.Anything, mock.Anything).Return(priceRows, nil)
+
+		service := &CartService{store: mockStore}
+		cart, err := service.buildCartResponse(context.Background(), &db.Cart{ID: 1, UserID: 1}, baseQuote("USD"))
+		if err != nil {
+			return false
+		}
+
+		// What a float64 total would drift from: the sum in exact rationals
+		want := new(big.Rat)
+		var lineSum money.Money
+		for i, item := range cart.CartItems {
+			subtotal, err := item.Product.Price.Mul(int64(item.Quantity))
+			if err != nil || item.Subtotal != subtotal {
+				return false
+			}
+			lineSum += item.Subtotal
+
+			unit, _ := new(big.Rat).SetString(item.Product.Price.String())
+			want.Add(want, unit.Mul(unit, big.NewRat(int64(items[i].Quantity), 1)))
+		}
+		got, _ := new(big.Rat).SetString(cart.Total.String())
+		if cart.Total != lineSum || got.Cmp(want) != 0 {
+			return false
+		}
+
+		prices, err := resolvePrices(context.Background(), mockStore, productIDs, time.Now(), baseQuote("USD"))
+		if err != nil {
+			return false
+		}
+		unitPrices, orderTotal, err := priceLines(items, productMap, prices)
+		if err != nil {
+			return false
+		}
+		var orderLineSum money.Money
+		for i, item := range items {
+			lineTotal, err := unitPrices[i].Mul(int64(item.Quantity))
+			if err != nil {
+				return false
+			}
+			orderLineSum += lineTotal
+		}
+		return orderTotal == orderLineSum && orderTotal == cart.Total
+	}
+
+	require.NoError(t, quick.Check(totalsMatch, nil))
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+var (
+	ErrProductImageNotFound = errors.New("product image not found")
+	ErrInvalidImageOrder    = errors.New("image order must list each of the product's images once")
+)
+
+// ProductImageService manages a product's images: their order, which one is
+// primary, their alt text, and removing them. Files no image uses any more
+// are deleted when their last image is removed, and otherwise by the storage
+// garbage collector.
+type ProductImageService struct {
+	store db.Store
+	files *StorageGCService
+}
+
+// NewProductImageService creates a ProductImageService. A nil files leaves
+// the files of deleted images to the storage garbage collector.
+func NewProductImageService(store db.Store, files *StorageGCService) *ProductImageService {
+	return &ProductImageService{
+		store: store,
+		files: files,
+	}
+}
+
+// ListImages returns a product's images in display order
+func (s *ProductImageService) ListImages(ctx context.Context, productID uint) ([]dto.ProductImageResponse, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.listImages(ctx, productID)
+}
+
+// AddImage adds an image after the product's other images, along with its
+// variants. An image hosted elsewhere has no storage path, content hash or
+// variants. The product's first image becomes its primary image.
+func (s *ProductImageService) AddImage(ctx context.Context, productID uint, upload dto.UploadedImage, altText string) (*dto.ProductImageResponse, error) {
+	params := db.CreateProductImageParams{
+		ProductID:   int32(productID), //#nosec G115 -- id from validated request
+		Url:         upload.URL,
+		AltText:     pgtype.Text{String: altText, Valid: altText != ""},
+		StoragePath: pgtype.Text{String: upload.StoragePath, Valid: upload.StoragePath != ""},
+		ContentHash: pgtype.Text{String: upload.ContentHash, Valid: upload.ContentHash != ""},
+	}
+	variants := make([]dto.ProductImageVariant, len(upload.Variants))
+	for i, v := range upload.Variants {
+		variants[i] = v.ProductImageVariant
+		params.VariantNames = append(params.VariantNames, v.Name)
+		params.VariantFormats = append(params.VariantFormats, v.Format)
+		params.VariantWidths = append(params.VariantWidths, int32(v.Width))    //#nosec G115 -- bounded by the upload's dimension limit
+		params.VariantHeights = append(params.VariantHeights, int32(v.Height)) //#nosec G115 -- bounded by the upload's dimension limit
+		params.VariantUrls = append(params.VariantUrls, v.URL)
+		params.VariantStoragePaths = append(params.VariantStoragePaths, v.StoragePath)
+	}
+
+	// Images added at the same time would otherwise take the same position,
+	// and both become primary when the product has none
+	var image db.ProductImage
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		if err := lockProduct(ctx, q, params.ProductID); err != nil {
+			return err
+		}
+		var err error
+		image, err = q.CreateProductImage(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create product image: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toProductImageResponse(image, variants)
+	return &resp, nil
+}
+
+// ReorderImages puts a product's images in the order of imageIDs, which
+// must list every one of them
+func (s *ProductImageService) ReorderImages(ctx context.Context, productID uint, imageIDs []uint) ([]dto.ProductImageResponse, error) {
+	images, err := s.store.ListProductImages(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product images: %w", err)
+	}
+	if len(imageIDs) != len(images) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	current := make(map[int32]bool, len(images))
+	for _, image := range images {
+		current[image.ID] = true
+	}
+	ids := make([]int32, len(imageIDs))
+	for i, id := range imageIDs {
+		ids[i] = int32(id) //#nosec G115 -- id from validated request
+		if !current[ids[i]] {
+			return nil, ErrInvalidImageOrder
+		}
+		delete(current, ids[i])
+	}
+
+	updated, err := s.store.SetProductImagePositions(ctx, db.SetProductImagePositionsParams{
+		ImageIds:  ids,
+		ProductID: int32(productID), //#nosec G115 -- id from validated request
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder product images: %w", err)
+	}
+	// An image was deleted since they were listed
+	if updated != int64(len(ids)) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	return s.listImages(ctx, productID)
+}
+
+// SetPrimaryImage makes an image its product's primary image, in place of
+// the current one
+func (s *ProductImageService) SetPrimaryImage(ctx context.Context, productID, imageID uint) ([]dto.ProductImageResponse, error) {
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		if err := lockProduct(ctx, q, int32(productID)); err != nil { //#nosec G115 -- id from validated request
+			return err
+		}
+		if err := q.ClearPrimaryProductImage(ctx, int32(productID)); err != nil { //#nosec G115 -- id from validated request
+			return fmt.Errorf("failed to clear primary image: %w", err)
+		}
+		updated, err := q.SetPrimaryProductImage(ctx, db.SetPrimaryProductImageParams{
+			ProductID: int32(productID), //#nosec G115 -- id from validated request
+			ID:        int32(imageID),   //#nosec G115 -- id from validated request
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set primary image: %w", err)
+		}
+		if updated == 0 {
+			return ErrProductImageNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listImages(ctx, productID)
+}
+
+// UpdateImage changes an image's alt text
+func (s *ProductImageService) UpdateImage(ctx context.Context, productID, imageID uint, req dto.UpdateProductImageRequest) (*dto.ProductImageResponse, error) {
+	image, err := s.store.UpdateProductImageAltText(ctx, db.UpdateProductImageAltTextParams{
+		ProductID: int32(productID), //#nosec G115 -- id from validated request
+		ID:        int32(imageID),   //#nosec G115 -- id from validated request
+		AltText:   pgtype.Text{String: req.AltText, Valid: req.AltText != ""},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductImageNotFound
+		}
+		return nil, fmt.Errorf("failed to update product image: %w", err)
+	}
+
+	variants, err := imageVariants(ctx, s.store, []int32{image.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toProductImageResponse(image, variants[image.ID])
+	return &resp, nil
+}
+
+// DeleteImage removes an image. When it was the primary image, the next
+// image in order takes its place. Its files are deleted too, unless another
+// image has the same content.
+func (s *ProductImageService) DeleteImage(ctx context.Context, productID, imageID uint) error {
+	image, err := s.store.GetProductImageByID(ctx, int32(imageID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductImageNotFound
+		}
+		return fmt.Errorf("failed to get product image: %w", err)
+	}
+	if image.ProductID != int32(productID) { //#nosec G115 -- id from validated request
+		return ErrProductImageNotFound
+	}
+
+	var released []string
+	err = s.store.ExecTx(ctx, func(q db.Querier) error {
+		// An image added meanwhile must see whether a primary is left
+		if err := lockProduct(ctx, q, image.ProductID); err != nil {
+			return err
+		}
+		var err error
+		released, err = q.SoftDeleteProductImage(ctx, image.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete product image: %w", err)
+		}
+		if !image.IsPrimary.Bool {
+			return nil
+		}
+
+		remaining, err := q.ListProductImages(ctx, image.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to list product images: %w", err)
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		_, err = q.SetPrimaryProductImage(ctx, db.SetPrimaryProductImageParams{
+			ProductID: image.ProductID,
+			ID:        remaining[0].ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set primary image: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteReleasedFiles(ctx, released)
+	return nil
+}
+
+// deleteReleasedFiles deletes the files of content hashes no image uses any
+// more. The image is already gone, so a failure is not reported; the storage
+// garbage collector deletes what is left.
+func (s *ProductImageService) deleteReleasedFiles(ctx context.Context, contentHashes []string) {
+	if s.files == nil || len(contentHashes) == 0 {
+		return
+	}
+	_ = s.files.DeleteReleasedFiles(ctx, contentHashes)
+}
+
+func (s *ProductImageService) requireProduct(ctx context.Context, productID uint) error {
+	_, err := s.store.GetProductByID(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFound
+		}
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	return nil
+}
+
+// lockProduct locks a product row until the transaction ends, so changes to
+// which of its images is primary and where new ones go happen one at a time
+func lockProduct(ctx context.Context, q db.Querier, productID int32) error {
+	_, err := q.GetProductByIDForUpdate(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFound
+		}
+		return fmt.Errorf("failed to lock product: %w", err)
+	}
+	return nil
+}
+
+func (s *ProductImageService) listImages(ctx context.Context, productID uint) ([]dto.ProductImageResponse, error) {
+	images, err := s.store.ListProductImages(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product images: %w", err)
+	}
+	return toProductImageResponses(ctx, s.store, images)
+}
+
+// toProductImageResponses converts images along with their variants
+func toProductImageResponses(ctx context.Context, q db.Querier, images []db.ProductImage) ([]dto.ProductImageResponse, error) {
+	ids := make([]int32, len(images))
+	for i, image := range images {
+		ids[i] = image.ID
+	}
+	variants, err := imageVariants(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ProductImageResponse, len(images))
+	for i, image := range images {
+		responses[i] = toProductImageResponse(image, variants[image.ID])
+	}
+	return responses, nil
+}
+
+// imageVariants batch loads the variants of a set of images; images hosted
+// elsewhere have none
+func imageVariants(ctx context.Context, q db.Querier, imageIDs []int32) (map[int32][]dto.ProductImageVariant, error) {
+	if len(imageIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.ListProductImageVariantsByImageIDs(ctx, imageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list image variants: %w", err)
+	}
+
+	variants := make(map[int32][]dto.ProductImageVariant)
+	for _, row := range rows {
+		variants[row.ImageID] = append(variants[row.ImageID], dto.ProductImageVariant{
+			Name:   row.Name,
+			Format: row.Format,
+			URL:    row.Url,
+			Width:  int(row.Width),
+			Height: int(row.Height),
+		})
+	}
+	return variants, nil
+}
+
+func toProductImageResponse(image db.ProductImage, variants []dto.ProductImageVariant) dto.ProductImageResponse {
+	if variants == nil {
+		variants = []dto.ProductImageVariant{}
+	}
+	return dto.ProductImageResponse{
+		ID:        uint(image.ID), //#nosec G115 -- DB ID is always positive
+		URL:       image.Url,
+		AltText:   image.AltText.String,
+		IsPrimary: image.IsPrimary.Bool,
+		Position:  int(image.Position),
+		Variants:  variants,
+		CreatedAt: image.CreatedAt.Time,
+	}
+}
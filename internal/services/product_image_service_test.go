@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+func productImages(ids ...int32) []db.ProductImage {
+	images := make([]db.ProductImage, len(ids))
+	for i, id := range ids {
+		images[i] = db.ProductImage{
+			ID:        id,
+			ProductID: 1,
+			Url:       "/uploads/products/1/image.jpg",
+			IsPrimary: pgtype.Bool{Bool: i == 0, Valid: true},
+			Position:  int32(i), //#nosec G115 -- small test fixture
+		}
+	}
+	return images
+}
+
+func TestProductImageService_AddImage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stores the upload path, content hash and variants", func(t *testing.T) {
+		t.Parallel()
+
+		thumbnail := dto.ProductImageVariant{Name: "thumbnail", Format: "webp", URL: "/uploads/products/1/a_thumbnail.webp", Width: 150, Height: 100}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(db.Product{ID: 1}, nil)
+		mockStore.On("CreateProductImage", mock.Anything, db.CreateProductImageParams{
+			ProductID:           1,
+			Url:                 "/uploads/products/1/a.jpg",
+			AltText:             pgtype.Text{String: "Front", Valid: true},
+			StoragePath:         pgtype.Text{String: "products/1/a.jpg", Valid: true},
+			ContentHash:         pgtype.Text{String: "abc", Valid: true},
+			VariantNames:        []string{"thumbnail"},
+			VariantFormats:      []string{"webp"},
+			VariantWidths:       []int32{150},
+			VariantHeights:      []int32{100},
+			VariantUrls:         []string{"/uploads/products/1/a_thumbnail.webp"},
+			VariantStoragePaths: []string{"products/1/a_thumbnail.webp"},
+		}).Return(db.ProductImage{
+			ID: 3, ProductID: 1, Url: "/uploads/products/1/a.jpg", Position: 2,
+			AltText: pgtype.Text{String: "Front", Valid: true},
+		}, nil)
+
+		service := NewProductImageService(mockStore, nil)
+		image, err := service.AddImage(context.Background(), 1, dto.UploadedImage{
+			URL:         "/uploads/products/1/a.jpg",
+			StoragePath: "products/1/a.jpg",
+			ContentHash: "abc",
+			Variants:    []dto.UploadedImageVariant{{ProductImageVariant: thumbnail, StoragePath: "products/1/a_thumbnail.webp"}},
+		}, "Front")
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(3), image.ID)
+		assert.Equal(t, 2, image.Position)
+		assert.False(t, image.IsPrimary)
+		assert.Equal(t, []dto.ProductImageVariant{thumbnail}, image.Variants)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("error - product not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(9)).Return(db.Product{}, pgx.ErrNoRows)
+
+		service := NewProductImageService(mockStore, nil)
+		_, err := service.AddImage(context.Background(), 9, dto.UploadedImage{URL: "https://cdn.example.com/a.jpg"}, "")
+		assert.ErrorIs(t, err, ErrProductNotFound)
+		mockStore.AssertNotCalled(t, "CreateProductImage", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductImageService_ReorderImages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		imageIDs []uint
+		wantErr  error
+	}{
+		{name: "success", imageIDs: []uint{12, 10, 11}},
+		{name: "error - image left out", imageIDs: []uint{12, 10}, wantErr: ErrInvalidImageOrder},
+		{name: "error - image listed twice", imageIDs: []uint{12, 10, 12}, wantErr: ErrInvalidImageOrder},
+		{name: "error - another product's image", imageIDs: []uint{12, 10, 99}, wantErr: ErrInvalidImageOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			mockStore.On("ListProductImages", mock.Anything, int32(1)).Return(productImages(10, 11, 12), nil)
+			mockStore.On("SetProductImagePositions", mock.Anything, db.SetProductImagePositionsParams{
+				ImageIds:  []int32{12, 10, 11},
+				ProductID: 1,
+			}).Return(int64(3), nil)
+			mockStore.On("ListProductImageVariantsByImageIDs", mock.Anything, []int32{10, 11, 12}).Return([]db.ProductImageVariant{}, nil)
+
+			service := NewProductImageService(mockStore, nil)
+			images, err := service.ReorderImages(context.Background(), 1, tt.imageIDs)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertNotCalled(t, "SetProductImagePositions", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, images, 3)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductImageService_SetPrimaryImage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		imageID uint
+		updated int64
+		wantErr error
+	}{
+		{name: "success", imageID: 11, updated: 1},
+		{name: "error - image not found", imageID: 99, wantErr: ErrProductImageNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// The primary is cleared and set in one transaction, under the product lock
+			var calls []string
+
+			mockStore := new(mocks.MockStore)
+			mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+			mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(db.Product{ID: 1}, nil).
+				Run(func(mock.Arguments) { calls = append(calls, "lock") })
+			mockStore.On("ClearPrimaryProductImage", mock.Anything, int32(1)).Return(nil).
+				Run(func(mock.Arguments) { calls = append(calls, "clear") })
+			mockStore.On("SetPrimaryProductImage", mock.Anything, db.SetPrimaryProductImageParams{
+				ProductID: 1,
+				ID:        int32(tt.imageID), //#nosec G115 -- small test fixture
+			}).Return(tt.updated, nil).
+				Run(func(mock.Arguments) { calls = append(calls, "set") })
+			mockStore.On("ListProductImages", mock.Anything, int32(1)).Return(productImages(11, 10), nil)
+			mockStore.On("ListProductImageVariantsByImageIDs", mock.Anything, []int32{11, 10}).Return([]db.ProductImageVariant{}, nil)
+
+			service := NewProductImageService(mockStore, nil)
+			images, err := service.SetPrimaryImage(context.Background(), 1, tt.imageID)
+
+			assert.Equal(t, []string{"lock", "clear", "set"}, calls)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, images)
+				mockStore.AssertNotCalled(t, "ListProductImages", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, images, 2)
+			assert.Equal(t, uint(11), images[0].ID)
+			assert.True(t, images[0].IsPrimary)
+			assert.False(t, images[1].IsPrimary)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductImageService_UpdateImage(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("UpdateProductImageAltText", mock.Anything, db.UpdateProductImageAltTextParams{
+		ProductID: 1,
+		ID:        99,
+		AltText:   pgtype.Text{String: "Side view", Valid: true},
+	}).Return(db.ProductImage{}, pgx.ErrNoRows)
+
+	service := NewProductImageService(mockStore, nil)
+	_, err := service.UpdateImage(context.Background(), 1, 99, dto.UpdateProductImageRequest{AltText: "Side view"})
+
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+}
+
+func TestProductImageService_DeleteImage(t *testing.T) {
+	t.Parallel()
+
+	uploaded := db.ProductImage{
+		ID:          10,
+		ProductID:   1,
+		Url:         "/uploads/images/ab/abc.jpg",
+		StoragePath: pgtype.Text{String: "images/ab/abc.jpg", Valid: true},
+		ContentHash: pgtype.Text{String: "abc", Valid: true},
+	}
+
+	// Released after the test's files were stored, so they may be deleted
+	releasedAt := pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+
+	tests := []struct {
+		name      string
+		productID uint
+		image     db.ProductImage
+		getErr    error
+		txErr     error
+		released  []string
+		blob      db.ImageBlob
+		wantErr   error
+		wantFiles []string
+	}{
+		{
+			name:      "deletes the image and its files once no image uses them",
+			productID: 1,
+			image:     uploaded,
+			released:  []string{"abc"},
+			blob:      db.ImageBlob{ContentHash: "abc", RefCount: 0, UpdatedAt: releasedAt},
+			wantFiles: []string{"images/cd/cde.jpg"},
+		},
+		{
+			name:      "keeps files another image uses",
+			productID: 1,
+			image:     uploaded,
+			released:  []string{},
+			wantFiles: []string{"images/ab/abc.jpg", "images/ab/abc_thumbnail.jpg", "images/cd/cde.jpg"},
+		},
+		{
+			// An upload of the same file took a reference after the delete committed
+			name:      "keeps files used again since",
+			productID: 1,
+			image:     uploaded,
+			released:  []string{"abc"},
+			blob:      db.ImageBlob{ContentHash: "abc", RefCount: 1, UpdatedAt: releasedAt},
+			wantFiles: []string{"images/ab/abc.jpg", "images/ab/abc_thumbnail.jpg", "images/cd/cde.jpg"},
+		},
+		{
+			name:      "image hosted elsewhere",
+			productID: 1,
+			image:     db.ProductImage{ID: 10, ProductID: 1, Url: "https://cdn.example.com/a.jpg"},
+			released:  []string{},
+			wantFiles: []string{"images/ab/abc.jpg", "images/ab/abc_thumbnail.jpg", "images/cd/cde.jpg"},
+		},
+		{
+			name:      "error - image belongs to another product",
+			productID: 2,
+			image:     uploaded,
+			wantErr:   ErrProductImageNotFound,
+		},
+		{
+			name:      "error - image not found",
+			productID: 1,
+			getErr:    pgx.ErrNoRows,
+			wantErr:   ErrProductImageNotFound,
+		},
+		{
+			name:      "error - delete fails",
+			productID: 1,
+			image:     uploaded,
+			txErr:     errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := storedProvider(t, "images/ab/abc.jpg", "images/ab/abc_thumbnail.jpg", "images/cd/cde.jpg")
+
+			mockStore := new(mocks.MockStore)
+			mockStore.On("GetProductImageByID", mock.Anything, int32(10)).Return(tt.image, tt.getErr)
+			mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(tt.txErr)
+			mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(db.Product{ID: 1}, nil)
+			mockStore.On("SoftDeleteProductImage", mock.Anything, int32(10)).Return(tt.released, nil)
+			mockStore.On("GetImageBlobForUpdate", mock.Anything, "abc").Return(tt.blob, nil)
+
+			service := NewProductImageService(mockStore, NewStorageGCService(mockStore, provider, 0))
+			err := service.DeleteImage(context.Background(), tt.productID, 10)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertNotCalled(t, "ExecTx", mock.Anything, mock.Anything)
+			case tt.txErr != nil:
+				assert.ErrorIs(t, err, tt.txErr)
+				assert.Len(t, storedPaths(t, provider), 3)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantFiles, storedPaths(t, provider))
+			}
+		})
+	}
+}
@@ -2,20 +2,53 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
 )
 
+var (
+	ErrInvalidSalePeriod = errors.New("sale must end after it starts")
+	ErrSaleNotBelowList  = errors.New("sale price must be below the list price")
+	ErrSalePriceNotFound = errors.New("sale price not found or already ended")
+	ErrBaseCurrencyPrice = errors.New("the base currency list price is the product's price")
+)
+
 type ProductService struct {
-	store db.Store
+	store      db.Store
+	alerter    *StockAlerter
+	currencies *CurrencyConverter
+	embeddings *EmbeddingIndexer
+	parser     interfaces.QueryParser
+	terms      *SearchTermIndex
+	files      *StorageGCService
 }
 
-func NewProductService(store db.Store) *ProductService {
-	return &ProductService{store: store}
+// NewProductService creates a ProductService. A nil alerter disables
+// low-stock alerts; a nil converter prices the catalog in
+// money.DefaultCurrency only; a nil indexer disables semantic search; a nil
+// parser reads search queries with the RuleQueryParser; nil terms disable
+// "did you mean" corrections; a nil files leaves the files of deleted
+// products' images to the storage garbage collector.
+func NewProductService(store db.Store, alerter *StockAlerter, currencies *CurrencyConverter, embeddings *EmbeddingIndexer, parser interfaces.QueryParser, terms *SearchTermIndex, files *StorageGCService) *ProductService {
+	return &ProductService{
+		store:      store,
+		alerter:    alerter,
+		currencies: currencies,
+		embeddings: embeddings,
+		parser:     parser,
+		terms:      terms,
+		files:      files,
+	}
 }
 
 func (s *ProductService) CreateCategory(ctx context.Context, req dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
@@ -109,57 +142,66 @@ func (s *ProductService) DeleteCategory(ctx context.Context, id uint) error {
 	return s.store.SoftDeleteCategory(ctx, int32(id)) //#nosec G115 -- id from validated request
 }
 
-func (s *ProductService) CreateProduct(ctx context.Context, req dto.CreateProductRequest) (*dto.ProductResponse, error) {
-	var price pgtype.Numeric
-	if err := price.Scan(fmt.Sprintf("%f", req.Price)); err != nil {
-		return nil, err
-	}
-
-	product, err := s.store.CreateProduct(ctx, db.CreateProductParams{
-		Name: req.Name,
-		Description: pgtype.Text{
-			String: req.Description,
-			Valid:  true,
-		},
-		Price: price,
-		Stock: pgtype.Int4{
-			Valid: true,
-			Int32: int32(req.Stock), //#nosec G115 -- stock is validated
-		},
-		CategoryID: int32(req.CategoryID), //#nosec G115 -- category ID from validated request
-		Sku:        req.SKU,
+func (s *ProductService) CreateProduct(ctx context.Context, actorID int32, req dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	var (
+		product  db.Product
+		movement *db.InventoryMovement
+	)
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		product, movement, err = s.createProduct(ctx, q, actorID, req)
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if movement != nil {
+		_ = s.alerter.Notify(ctx, *movement)
+	}
+
+	// Embed the new product for semantic search
+	s.embeddings.Notify()
+
 	// Fetch the category to include in response
 	category, err := s.store.GetCategoryByID(ctx, product.CategoryID)
 	if err != nil {
 		return nil, err
 	}
 
-	priceFloat, _ := product.Price.Float64Value()
+	attributes, err := productAttributes(ctx, s.store, []int32{product.ID})
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.ProductResponse{
-		ID:          uint(product.ID), //#nosec G115 -- DB ID is always positive
-		Name:        product.Name,
-		Description: product.Description.String,
-		Price:       priceFloat.Float64,
-		Stock:       int(product.Stock.Int32),
-		CategoryID:  uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
-		SKU:         product.Sku,
-		IsActive:    product.IsActive.Bool,
+		ID:             uint(product.ID), //#nosec G115 -- DB ID is always positive
+		Name:           product.Name,
+		Description:    product.Description.String,
+		Price:          req.Price,
+		Currency:       s.currencies.Base(),
+		Stock:          int(product.Stock.Int32),
+		AvailableStock: int(product.Stock.Int32), // a new product has no reservations
+		CategoryID:     uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
+		SKU:            product.Sku,
+		IsActive:       product.IsActive.Bool,
 		Category: dto.CategoryResponse{
 			ID:          int64(category.ID),
 			Name:        category.Name,
 			Description: category.Description.String,
 			IsActive:    category.IsActive.Bool,
 		},
-		Images: []dto.ProductImageResponse{},
+		Images:     []dto.ProductImageResponse{},
+		Attributes: attributes[product.ID],
 	}, nil
 }
 
-func (s *ProductService) GetProducts(ctx context.Context, page, limit int) ([]dto.ProductResponse, *utils.PaginationMeta, error) {
+func (s *ProductService) GetProducts(ctx context.Context, page, limit int, currency string) ([]dto.ProductResponse, *utils.PaginationMeta, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if page < 1 {
 		page = 1
 	}
@@ -198,6 +240,17 @@ func (s *ProductService) GetProducts(ctx context.Context, page, limit int) ([]dt
 		return []dto.ProductResponse{}, paginationMeta, nil
 	}
 
+	productResponses, err := s.productResponses(ctx, products, quote)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return productResponses, paginationMeta, nil
+}
+
+// productResponses builds the responses for a page of products, batch loading
+// their categories, images, reservations, prices and attributes
+func (s *ProductService) productResponses(ctx context.Context, products []db.Product, quote Quote) ([]dto.ProductResponse, error) {
 	// Collect unique category IDs and all product IDs
 	categoryIDSet := make(map[int32]struct{})
 	productIDs := make([]int32, len(products))
@@ -215,7 +268,7 @@ func (s *ProductService) GetProducts(ctx context.Context, page, limit int) ([]dt
 	// Batch fetch categories
 	categories, err := s.store.GetCategoriesByIDs(ctx, categoryIDs)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Create category lookup map
@@ -227,13 +280,45 @@ func (s *ProductService) GetProducts(ctx context.Context, page, limit int) ([]dt
 	// Batch fetch images
 	images, err := s.store.ListProductImagesByProductIDs(ctx, productIDs)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Group images by product ID
 	imageMap := make(map[int32][]db.ProductImage)
-	for _, img := range images {
+	imageIDs := make([]int32, len(images))
+	for i, img := range images {
 		imageMap[img.ProductID] = append(imageMap[img.ProductID], img)
+		imageIDs[i] = img.ID
+	}
+
+	// Batch fetch image variants
+	variants, err := imageVariants(ctx, s.store, imageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Batch fetch active reservations
+	reserved, err := reservedStock(ctx, s.store, productIDs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Batch resolve current prices
+	prices, err := resolvePrices(ctx, s.store, productIDs, time.Now(), quote)
+	if err != nil {
+		return nil, err
+	}
+
+	// Batch fetch attributes
+	attributes, err := productAttributes(ctx, s.store, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Batch fetch review summaries
+	summaries, err := reviewSummaries(ctx, s.store, productIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build response
@@ -246,41 +331,45 @@ func (s *ProductService) GetProducts(ctx context.Context, page, limit int) ([]dt
 		productImages := imageMap[product.ID]
 		imageResponses := make([]dto.ProductImageResponse, len(productImages))
 		for j, img := range productImages {
-			imageResponses[j] = dto.ProductImageResponse{
-				ID:        uint(img.ID), //#nosec G115 -- DB ID is always positive
-				URL:       img.Url,
-				AltText:   img.AltText.String,
-				IsPrimary: img.IsPrimary.Bool,
-			}
+			imageResponses[j] = toProductImageResponse(img, variants[img.ID])
 		}
 
-		priceFloat, _ := product.Price.Float64Value()
 		productResponses[i] = dto.ProductResponse{
-			ID:          uint(product.ID), //#nosec G115 -- DB ID is always positive
-			Name:        product.Name,
-			Description: product.Description.String,
-			Price:       priceFloat.Float64,
-			Stock:       int(product.Stock.Int32),
-			CategoryID:  uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
-			SKU:         product.Sku,
-			IsActive:    product.IsActive.Bool,
+			ID:             uint(product.ID), //#nosec G115 -- DB ID is always positive
+			Name:           product.Name,
+			Description:    product.Description.String,
+			Stock:          int(product.Stock.Int32),
+			AvailableStock: availableStock(product.Stock, reserved[product.ID]),
+			CategoryID:     uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
+			SKU:            product.Sku,
+			IsActive:       product.IsActive.Bool,
 			Category: dto.CategoryResponse{
 				ID:          int64(category.ID),
 				Name:        category.Name,
 				Description: category.Description.String,
 				IsActive:    category.IsActive.Bool,
 			},
-			Images: imageResponses,
+			Images:        imageResponses,
+			Attributes:    attributes[product.ID],
+			SEOTitle:      product.SeoTitle.String,
+			Tags:          product.Tags,
+			ReviewSummary: summaries[product.ID],
+		}
+		if err := prices.apply(&productResponses[i], product); err != nil {
+			return nil, err
 		}
 	}
 
-	return productResponses, paginationMeta, nil
+	return productResponses, nil
 }
 
-// SearchProducts performs full-text search on products by name, sku, and description
-func (s *ProductService) SearchProducts(ctx context.Context, req dto.SearchProductsRequest) ([]dto.ProductSearchResult, *utils.PaginationMeta, error) {
-	if req.Query == "" {
-		return []dto.ProductSearchResult{}, &utils.PaginationMeta{Page: req.Page, Limit: req.Limit}, nil
+// SearchProducts performs full-text search on products by name, sku, and
+// description, with facet counts over every match. An empty query browses
+// the catalog by the filters alone.
+func (s *ProductService) SearchProducts(ctx context.Context, req dto.SearchProductsRequest, currency string) (*dto.ProductSearchResponse, *utils.PaginationMeta, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	page := req.Page
@@ -292,33 +381,39 @@ func (s *ProductService) SearchProducts(ctx context.Context, req dto.SearchProdu
 		limit = 10
 	}
 
-	// Build optional filter params
-	var categoryID pgtype.Int4
-	if req.CategoryID != nil {
-		categoryID = pgtype.Int4{Int32: int32(*req.CategoryID), Valid: true} //#nosec G115 -- category ID is bounded
-	}
-
-	var minPrice, maxPrice pgtype.Numeric
-	if req.MinPrice != nil {
-		_ = minPrice.Scan(*req.MinPrice)
-	}
-	if req.MaxPrice != nil {
-		_ = maxPrice.Scan(*req.MaxPrice)
+	var parsed *dto.ParsedQuery
+	if req.Parse {
+		if req, parsed, err = s.parseQuery(ctx, req); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	countParams := db.CountSearchProductsParams{
-		PlaintoTsquery: req.Query,
-		CategoryID:     categoryID,
-		MinPrice:       minPrice,
-		MaxPrice:       maxPrice,
+	filters, err := newSearchFilters(req, quote)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Get total count for pagination
-	totalCount, err := s.store.CountSearchProducts(ctx, countParams)
+	totalCount, err := s.store.CountSearchProducts(ctx, db.CountSearchProductsParams(filters))
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// When full-text search finds nothing, suggest a corrected query and fall
+	// back to matching product names by similarity, which tolerates typos and
+	// partial words
+	var didYouMean string
+	if totalCount == 0 && req.Query != "" {
+		if didYouMean, err = s.correctQuery(ctx, req.Query); err != nil {
+			return nil, nil, err
+		}
+
+		filters.Fuzzy = true
+		if totalCount, err = s.store.CountSearchProducts(ctx, db.CountSearchProductsParams(filters)); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Calculate total pages
 	totalPages := int(totalCount) / limit
 	if int(totalCount)%limit > 0 {
@@ -332,134 +427,184 @@ func (s *ProductService) SearchProducts(ctx context.Context, req dto.SearchProdu
 		TotalPages: totalPages,
 	}
 
-	searchParams := db.SearchProductsParams{
-		PlaintoTsquery: req.Query,
-		Limit:          int32(limit),              //#nosec G115 -- pagination values are bounded
-		Offset:         int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
-		CategoryID:     categoryID,
-		MinPrice:       minPrice,
-		MaxPrice:       maxPrice,
+	// Facets cover every match, and other selections, so compute them even
+	// when this page is empty
+	facets, err := s.searchFacets(ctx, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort := req.Sort
+	if sort == "" {
+		sort = dto.SearchSortRank
 	}
 
-	products, err := s.store.SearchProducts(ctx, searchParams)
+	products, err := s.store.SearchProducts(ctx, db.SearchProductsParams{
+		Query:           filters.Query,
+		Fuzzy:           filters.Fuzzy,
+		CategoryIds:     filters.CategoryIds,
+		MinPrice:        filters.MinPrice,
+		MaxPrice:        filters.MaxPrice,
+		InStock:         filters.InStock,
+		AttributeNames:  filters.AttributeNames,
+		AttributeValues: filters.AttributeValues,
+		BaseCurrency:    filters.BaseCurrency,
+		Currency:        filters.Currency,
+		Rate:            filters.Rate,
+		Sort:            sort,
+		Limit:           int32(limit),              //#nosec G115 -- pagination values are bounded
+		Offset:          int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if len(products) == 0 {
-		return []dto.ProductSearchResult{}, paginationMeta, nil
+		return &dto.ProductSearchResponse{
+			Results:    []dto.ProductSearchResult{},
+			Facets:     facets,
+			DidYouMean: didYouMean,
+			Fuzzy:      filters.Fuzzy,
+			Parsed:     parsed,
+		}, paginationMeta, nil
 	}
 
-	// Collect unique category IDs and all product IDs
-	categoryIDSet := make(map[int32]struct{})
-	productIDs := make([]int32, len(products))
+	rows := make([]db.Product, len(products))
 	for i, product := range products {
-		categoryIDSet[product.CategoryID] = struct{}{}
-		productIDs[i] = product.ID
+		rows[i] = product.Product
+	}
+	productResponses, err := s.productResponses(ctx, rows, quote)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Convert category ID set to slice
-	categoryIDs := make([]int32, 0, len(categoryIDSet))
-	for id := range categoryIDSet {
-		categoryIDs = append(categoryIDs, id)
+	// Build response with rank
+	productResults := make([]dto.ProductSearchResult, len(products))
+	for i, product := range products {
+		productResults[i] = dto.ProductSearchResult{ProductResponse: productResponses[i], Rank: product.Rank}
 	}
 
-	// Batch fetch categories
-	categories, err := s.store.GetCategoriesByIDs(ctx, categoryIDs)
+	return &dto.ProductSearchResponse{
+		Results:    productResults,
+		Facets:     facets,
+		DidYouMean: didYouMean,
+		Fuzzy:      filters.Fuzzy,
+		Parsed:     parsed,
+	}, paginationMeta, nil
+}
+
+func (s *ProductService) GetProductByID(ctx context.Context, id uint, currency string) (*dto.ProductResponse, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// Create category lookup map
-	categoryMap := make(map[int32]db.Category, len(categories))
-	for _, cat := range categories {
-		categoryMap[cat.ID] = cat
+	product, err := s.store.GetProductByID(ctx, int32(id)) //#nosec G115 -- id from validated request
+	if err != nil {
+		return nil, err
 	}
 
-	// Batch fetch images
-	images, err := s.store.ListProductImagesByProductIDs(ctx, productIDs)
+	images, err := s.store.ListProductImages(ctx, int32(id)) //#nosec G115 -- id from validated request
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	imageResponses, err := toProductImageResponses(ctx, s.store, images)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create image lookup map
-	imageMap := make(map[int32][]db.ProductImage)
-	for _, img := range images {
-		imageMap[img.ProductID] = append(imageMap[img.ProductID], img)
+	reserved, err := reservedStock(ctx, s.store, []int32{product.ID}, 0)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build response with rank
-	productResults := make([]dto.ProductSearchResult, len(products))
-	for i, product := range products {
-		category := categoryMap[product.CategoryID]
-		productImages := imageMap[product.ID]
+	prices, err := resolvePrices(ctx, s.store, []int32{product.ID}, time.Now(), quote)
+	if err != nil {
+		return nil, err
+	}
 
-		imageResponses := make([]dto.ProductImageResponse, len(productImages))
-		for j, img := range productImages {
-			imageResponses[j] = dto.ProductImageResponse{
-				ID:        uint(img.ID), //#nosec G115 -- DB ID is always positive
-				URL:       img.Url,
-				AltText:   img.AltText.String,
-				IsPrimary: img.IsPrimary.Bool,
-			}
-		}
+	attributes, err := productAttributes(ctx, s.store, []int32{product.ID})
+	if err != nil {
+		return nil, err
+	}
 
-		priceFloat, _ := product.Price.Float64Value()
-		productResults[i] = dto.ProductSearchResult{
-			ProductResponse: dto.ProductResponse{
-				ID:          uint(product.ID), //#nosec G115 -- DB ID is always positive
-				Name:        product.Name,
-				Description: product.Description.String,
-				Price:       priceFloat.Float64,
-				Stock:       int(product.Stock.Int32),
-				CategoryID:  uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
-				SKU:         product.Sku,
-				IsActive:    product.IsActive.Bool,
-				Category: dto.CategoryResponse{
-					ID:          int64(category.ID),
-					Name:        category.Name,
-					Description: category.Description.String,
-					IsActive:    category.IsActive.Bool,
-				},
-				Images: imageResponses,
-			},
-			Rank: product.Rank,
-		}
+	summaries, err := reviewSummaries(ctx, s.store, []int32{product.ID})
+	if err != nil {
+		return nil, err
 	}
 
-	return productResults, paginationMeta, nil
+	resp, err := s.convertProductToProductResponse(product, imageResponses, reserved[product.ID], prices, attributes[product.ID])
+	if err != nil {
+		return nil, err
+	}
+	resp.ReviewSummary = summaries[product.ID]
+	return resp, nil
 }
 
-func (s *ProductService) GetProductByID(ctx context.Context, id uint) (*dto.ProductResponse, error) {
-	product, err := s.store.GetProductByID(ctx, int32(id)) //#nosec G115 -- id from validated request
+func (s *ProductService) UpdateProductByID(ctx context.Context, actorID int32, id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+	var (
+		product  db.Product
+		movement *db.InventoryMovement
+	)
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		product, movement, err = s.updateProduct(ctx, q, actorID, int32(id), req) //#nosec G115 -- id from validated request
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if movement != nil {
+		_ = s.alerter.Notify(ctx, *movement)
+	}
+
+	// Re-embed the product if its text changed
+	s.embeddings.Notify()
+
 	images, err := s.store.ListProductImages(ctx, int32(id)) //#nosec G115 -- id from validated request
 	if err != nil {
 		return nil, err
 	}
+	imageResponses, err := toProductImageResponses(ctx, s.store, images)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.convertProductToProductResponse(product, images), nil
-}
+	reserved, err := reservedStock(ctx, s.store, []int32{product.ID}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := resolvePrices(ctx, s.store, []int32{product.ID}, time.Now(), baseQuote(s.currencies.Base()))
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := productAttributes(ctx, s.store, []int32{product.ID})
+	if err != nil {
+		return nil, err
+	}
 
-func (s *ProductService) UpdateProductByID(ctx context.Context, id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
-	// First, fetch the existing product
-	existing, err := s.store.GetProductByID(ctx, int32(id)) //#nosec G115 -- id from validated request
+	summaries, err := reviewSummaries(ctx, s.store, []int32{product.ID})
 	if err != nil {
 		return nil, err
 	}
 
-	// Prepare price from request
-	var price pgtype.Numeric
-	if err := price.Scan(fmt.Sprintf("%f", req.Price)); err != nil {
+	resp, err := s.convertProductToProductResponse(product, imageResponses, reserved[product.ID], prices, attributes[product.ID])
+	if err != nil {
 		return nil, err
 	}
+	resp.ReviewSummary = summaries[product.ID]
+	return resp, nil
+}
+
+// createProduct adds a product with its launch price, initial stock and
+// attributes. It must run inside a transaction.
+func (s *ProductService) createProduct(ctx context.Context, q db.Querier, actorID int32, req dto.CreateProductRequest) (db.Product, *db.InventoryMovement, error) {
+	price := req.Price.Numeric()
 
-	// Update the product with values from the request, using existing values as fallbacks
-	product, err := s.store.UpdateProduct(ctx, db.UpdateProductParams{
-		ID:   int32(id), //#nosec G115 -- id from validated request
+	product, err := q.CreateProduct(ctx, db.CreateProductParams{
 		Name: req.Name,
 		Description: pgtype.Text{
 			String: req.Description,
@@ -468,78 +613,458 @@ func (s *ProductService) UpdateProductByID(ctx context.Context, id uint, req *dt
 		Price: price,
 		Stock: pgtype.Int4{
 			Valid: true,
-			Int32: int32(req.Stock), //#nosec G115 -- stock is validated
+			Int32: 0, // initial stock is booked into the default warehouse below
 		},
 		CategoryID: int32(req.CategoryID), //#nosec G115 -- category ID from validated request
+		Sku:        req.SKU,
+	})
+	if err != nil {
+		return db.Product{}, nil, err
+	}
+
+	// Open the price history with the launch price
+	if err := recordListPrice(ctx, q, actorID, product.ID, s.currencies.Base(), price); err != nil {
+		return db.Product{}, nil, err
+	}
+
+	// Receive the initial stock into the default warehouse and open the ledger
+	movement, err := setStock(ctx, q, &product, int32(req.Stock), stockMovement{ //#nosec G115 -- stock is validated
+		Reason:  db.InventoryMovementReasonImport,
+		ActorID: actorID,
+		Note:    "initial stock",
+	})
+	if err != nil {
+		return db.Product{}, nil, err
+	}
+
+	if len(req.Attributes) > 0 {
+		if err := addProductAttributes(ctx, q, product.ID, req.Attributes); err != nil {
+			return db.Product{}, nil, err
+		}
+	}
+	return product, movement, nil
+}
+
+// updateProduct applies req to a product, locking it first so stock moved by
+// orders while it runs is not overwritten. It must run inside a transaction.
+func (s *ProductService) updateProduct(ctx context.Context, q db.Querier, actorID, id int32, req *dto.UpdateProductRequest) (db.Product, *db.InventoryMovement, error) {
+	existing, err := q.GetProductByIDForUpdate(ctx, id)
+	if err != nil {
+		return db.Product{}, nil, err
+	}
+
+	product, err := q.UpdateProduct(ctx, db.UpdateProductParams{
+		ID:   id,
+		Name: req.Name,
+		Description: pgtype.Text{
+			String: req.Description,
+			Valid:  true,
+		},
+		Price:      req.Price.Numeric(),
+		CategoryID: int32(req.CategoryID), //#nosec G115 -- category ID from validated request
 		Sku:        existing.Sku,          // Preserve existing SKU since it's not in UpdateProductRequest
 	})
 	if err != nil {
-		return nil, err
+		return db.Product{}, nil, err
+	}
+
+	// Append the new list price so earlier prices stay in the history
+	existingPrice, err := money.FromNumeric(existing.Price)
+	if err != nil {
+		return db.Product{}, nil, fmt.Errorf("failed to read price of product %d: %w", existing.ID, err)
+	}
+	if existingPrice != req.Price {
+		if err := recordListPrice(ctx, q, actorID, product.ID, s.currencies.Base(), product.Price); err != nil {
+			return db.Product{}, nil, err
+		}
+	}
+
+	// Book any stock change against the default warehouse as a manual
+	// adjustment, from the stock locked above
+	var movement *db.InventoryMovement
+	if req.Stock != nil {
+		movement, err = setStock(ctx, q, &product, int32(*req.Stock), stockMovement{ //#nosec G115 -- stock is validated
+			Reason:  db.InventoryMovementReasonManualAdjustment,
+			ActorID: actorID,
+			Note:    "product update",
+		})
+		if err != nil {
+			return db.Product{}, nil, err
+		}
+	}
+
+	// Replace the attributes when the request sets them
+	if req.Attributes != nil {
+		if err := q.DeleteProductAttributes(ctx, product.ID); err != nil {
+			return db.Product{}, nil, fmt.Errorf("failed to clear product attributes: %w", err)
+		}
+		if err := addProductAttributes(ctx, q, product.ID, req.Attributes); err != nil {
+			return db.Product{}, nil, err
+		}
+	}
+
+	// Replace the SEO title and tags when the request sets them
+	if req.SEOTitle != nil || req.Tags != nil {
+		params := db.UpdateProductContentParams{ID: product.ID, SeoTitle: product.SeoTitle, Tags: product.Tags}
+		if req.SEOTitle != nil {
+			params.SeoTitle = pgtype.Text{String: *req.SEOTitle, Valid: *req.SEOTitle != ""}
+		}
+		if req.Tags != nil {
+			params.Tags = normalizeTags(req.Tags)
+		}
+		if params.Tags == nil {
+			params.Tags = []string{}
+		}
+		if err := q.UpdateProductContent(ctx, params); err != nil {
+			return db.Product{}, nil, fmt.Errorf("failed to update product content: %w", err)
+		}
+		product.SeoTitle, product.Tags = params.SeoTitle, params.Tags
 	}
 
 	// Handle optional IsActive update
 	if req.IsActive != nil {
-		product, err = s.store.UpdateProductStatus(ctx, db.UpdateProductStatusParams{
-			ID: int32(id), //#nosec G115 -- id from validated request
+		product, err = q.UpdateProductStatus(ctx, db.UpdateProductStatusParams{
+			ID: id,
 			IsActive: pgtype.Bool{
 				Bool:  *req.IsActive,
 				Valid: true,
 			},
 		})
+		if err != nil {
+			return db.Product{}, nil, err
+		}
+	}
+	return product, movement, nil
+}
+
+// setStock brings a locked product's stock to target by moving the
+// difference in or out of the default warehouse, recording it in the ledger
+// as m describes. It returns the movement, or nil when the stock is already
+// at target. It must run inside the transaction that locked the product.
+func setStock(ctx context.Context, q db.Querier, product *db.Product, target int32, m stockMovement) (*db.InventoryMovement, error) {
+	m.ProductID = product.ID
+	m.Delta = target - product.Stock.Int32
+	if m.Delta == 0 {
+		return nil, nil
+	}
+
+	movement, err := applyWarehouseStockMovement(ctx, q, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stock: %w", err)
+	}
+	product.Stock = pgtype.Int4{Int32: movement.StockAfter, Valid: true}
+	return &movement, nil
+}
+
+// GetPriceHistory returns a product's full price history in every currency,
+// newest first, with the price it sold for in the given currency at the
+// given time
+func (s *ProductService) GetPriceHistory(ctx context.Context, productID uint, at time.Time, currency string) (*dto.PriceHistoryResponse, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.store.GetProductByID(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	prices, err := resolvePrices(ctx, s.store, []int32{product.ID}, at, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.store.ListProductPricesByProduct(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product prices: %w", err)
+	}
+
+	effective, err := prices.of(product)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.PriceHistoryResponse{
+		ProductID:      uint(product.ID), //#nosec G115 -- DB ID is always positive
+		At:             at,
+		Currency:       quote.Currency,
+		Price:          effective.Price,
+		CompareAtPrice: effective.CompareAt,
+		History:        make([]dto.ProductPriceResponse, len(history)),
+	}
+	for i, p := range history {
+		price, err := toProductPriceResponse(p)
 		if err != nil {
 			return nil, err
 		}
+		resp.History[i] = *price
 	}
+	return resp, nil
+}
 
-	images, err := s.store.ListProductImages(ctx, int32(id)) //#nosec G115 -- id from validated request
+// SetListPrice sets a product's list price in another currency's price
+// list. Shoppers in that currency see it instead of the converted base price.
+func (s *ProductService) SetListPrice(ctx context.Context, actorID int32, productID uint, currency string, req dto.SetListPriceRequest) (*dto.ProductPriceResponse, error) {
+	code, err := s.currencies.Supported(currency)
 	if err != nil {
 		return nil, err
 	}
+	if code == s.currencies.Base() {
+		return nil, ErrBaseCurrencyPrice
+	}
+
+	product, err := s.store.GetProductByID(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	listPrice, err := s.store.CreateProductPrice(ctx, db.CreateProductPriceParams{
+		ProductID: product.ID,
+		Currency:  code,
+		Kind:      db.ProductPriceKindList,
+		Price:     req.Price.Numeric(),
+		StartsAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		CreatedBy: pgtype.Int4{Int32: actorID, Valid: actorID != 0},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set list price: %w", err)
+	}
 
-	return s.convertProductToProductResponse(product, images), nil
+	return toProductPriceResponse(listPrice)
 }
 
-func (s *ProductService) DeleteProductByID(ctx context.Context, id uint) error {
-	return s.store.SoftDeleteProduct(ctx, int32(id)) //#nosec G115 -- id from validated request
+// ScheduleSalePrice sets a sale price for a product between req.StartsAt
+// (now if unset) and req.EndsAt (open-ended if unset). The sale must be below
+// the list price in its currency when it starts. The newest sale wins where
+// sales overlap. A sale in another currency applies to that currency's
+// shoppers only; a base currency sale is converted for everyone else.
+func (s *ProductService) ScheduleSalePrice(ctx context.Context, actorID int32, productID uint, req dto.CreateSalePriceRequest) (*dto.ProductPriceResponse, error) {
+	quote, err := s.currencies.Quote(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	currency := quote.Currency
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(startsAt) {
+		return nil, ErrInvalidSalePeriod
+	}
+
+	product, err := s.store.GetProductByID(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	prices, err := resolvePrices(ctx, s.store, []int32{product.ID}, startsAt, quote)
+	if err != nil {
+		return nil, err
+	}
+	listPrice, err := prices.of(product)
+	if err != nil {
+		return nil, err
+	}
+	if listPrice.CompareAt != nil {
+		listPrice.Price = *listPrice.CompareAt
+	}
+	if req.Price >= listPrice.Price {
+		return nil, ErrSaleNotBelowList
+	}
+
+	var endsAt pgtype.Timestamptz
+	if req.EndsAt != nil {
+		endsAt = pgtype.Timestamptz{Time: *req.EndsAt, Valid: true}
+	}
+
+	sale, err := s.store.CreateProductPrice(ctx, db.CreateProductPriceParams{
+		ProductID: product.ID,
+		Currency:  currency,
+		Kind:      db.ProductPriceKindSale,
+		Price:     req.Price.Numeric(),
+		StartsAt:  pgtype.Timestamptz{Time: startsAt, Valid: true},
+		EndsAt:    endsAt,
+		CreatedBy: pgtype.Int4{Int32: actorID, Valid: actorID != 0},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sale price: %w", err)
+	}
+
+	return toProductPriceResponse(sale)
 }
 
-// UpdateProductImage creates a new product image record for the given product
-func (s *ProductService) UpdateProductImage(ctx context.Context, productID uint, imageURL string, altText string) error {
-	_, err := s.store.CreateProductImage(ctx, db.CreateProductImageParams{
+// EndSalePrice ends a running sale now, or cancels one that has not started.
+// The row is kept so the history stays complete.
+func (s *ProductService) EndSalePrice(ctx context.Context, productID, priceID uint) (*dto.ProductPriceResponse, error) {
+	sale, err := s.store.EndProductSalePrice(ctx, db.EndProductSalePriceParams{
+		ID:        int32(priceID),   //#nosec G115 -- id from validated request
 		ProductID: int32(productID), //#nosec G115 -- id from validated request
-		Url:       imageURL,
-		AltText: pgtype.Text{
-			String: altText,
-			Valid:  true,
-		},
-		IsPrimary: pgtype.Bool{
-			Bool:  true, // Set as primary by default when uploading
-			Valid: true,
-		},
 	})
-	return err
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSalePriceNotFound
+		}
+		return nil, fmt.Errorf("failed to end sale price: %w", err)
+	}
+
+	return toProductPriceResponse(sale)
 }
 
-func (s *ProductService) convertProductToProductResponse(product db.Product, images []db.ProductImage) *dto.ProductResponse {
-	// Convert []db.ProductImage to []dto.ProductImageResponse
-	imageResponses := make([]dto.ProductImageResponse, len(images))
-	for i, img := range images {
-		imageResponses[i] = dto.ProductImageResponse{
-			ID:        uint(img.ID), //#nosec G115 -- DB ID is always positive
-			URL:       img.Url,
-			AltText:   img.AltText.String,
-			IsPrimary: img.IsPrimary.Bool,
+// recordListPrice appends a list price to the product's price history
+func recordListPrice(ctx context.Context, q db.Querier, actorID, productID int32, currency string, price pgtype.Numeric) error {
+	_, err := q.CreateProductPrice(ctx, db.CreateProductPriceParams{
+		ProductID: productID,
+		Currency:  currency,
+		Kind:      db.ProductPriceKindList,
+		Price:     price,
+		StartsAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		CreatedBy: pgtype.Int4{Int32: actorID, Valid: actorID != 0},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+// DeleteProductByID deletes a product along with its images, and the files
+// of those images no other image has the same content as
+func (s *ProductService) DeleteProductByID(ctx context.Context, id uint) error {
+	productID := int32(id) //#nosec G115 -- id from validated request
+	var released []string
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		if err := q.SoftDeleteProduct(ctx, productID); err != nil {
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
+		var err error
+		released, err = q.SoftDeleteProductImagesByProductID(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("failed to delete product images: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	priceFloat, _ := product.Price.Float64Value()
-	return &dto.ProductResponse{
-		ID:          uint(product.ID), //#nosec G115 -- DB ID is always positive
-		Name:        product.Name,
-		Description: product.Description.String,
-		Price:       priceFloat.Float64,
-		Stock:       int(product.Stock.Int32),
-		CategoryID:  uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
-		Images:      imageResponses,
+	// The product is already gone; the storage garbage collector deletes
+	// whatever files are left
+	if s.files != nil && len(released) > 0 {
+		_ = s.files.DeleteReleasedFiles(ctx, released)
+	}
+	return nil
+}
+
+func (s *ProductService) convertProductToProductResponse(product db.Product, imageResponses []dto.ProductImageResponse, reserved int32, prices priceList, attributes []dto.ProductAttribute) (*dto.ProductResponse, error) {
+	resp := &dto.ProductResponse{
+		ID:             uint(product.ID), //#nosec G115 -- DB ID is always positive
+		Name:           product.Name,
+		Description:    product.Description.String,
+		Stock:          int(product.Stock.Int32),
+		AvailableStock: availableStock(product.Stock, reserved),
+		CategoryID:     uint(product.CategoryID), //#nosec G115 -- DB ID is always positive
+		Images:         imageResponses,
+		Attributes:     attributes,
+		SEOTitle:       product.SeoTitle.String,
+		Tags:           product.Tags,
+	}
+	if err := prices.apply(resp, product); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// productAttributes batch loads the attributes of a set of products
+func productAttributes(ctx context.Context, q db.Querier, productIDs []int32) (map[int32][]dto.ProductAttribute, error) {
+	rows, err := q.ListProductAttributesByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product attributes: %w", err)
+	}
+
+	attributes := make(map[int32][]dto.ProductAttribute)
+	for _, row := range rows {
+		attributes[row.ProductID] = append(attributes[row.ProductID], dto.ProductAttribute{Name: row.Name, Value: row.Value})
+	}
+	return attributes, nil
+}
+
+// reviewSummaries batch loads the review summaries of a set of products;
+// products nobody has reviewed have none
+func reviewSummaries(ctx context.Context, q db.Querier, productIDs []int32) (map[int32]*dto.ReviewSummary, error) {
+	rows, err := q.ListProductReviewSummariesByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review summaries: %w", err)
+	}
+
+	summaries := make(map[int32]*dto.ReviewSummary, len(rows))
+	for _, row := range rows {
+		summaries[row.ProductID] = toReviewSummary(row)
+	}
+	return summaries, nil
+}
+
+// normalizeTags lowercases and trims tags, dropping empty and repeated ones
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// addProductAttributes adds attributes to a product; ones it already has are skipped
+func addProductAttributes(ctx context.Context, q db.Querier, productID int32, attributes []dto.ProductAttribute) error {
+	names := make([]string, len(attributes))
+	values := make([]string, len(attributes))
+	for i, attr := range attributes {
+		names[i] = strings.TrimSpace(attr.Name)
+		values[i] = strings.TrimSpace(attr.Value)
+	}
+
+	err := q.AddProductAttributes(ctx, db.AddProductAttributesParams{
+		ProductID: productID,
+		Names:     names,
+		Values:    values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add product attributes: %w", err)
+	}
+	return nil
+}
+
+func toProductPriceResponse(p db.ProductPrice) (*dto.ProductPriceResponse, error) {
+	price, err := money.FromNumeric(p.Price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price %d: %w", p.ID, err)
+	}
+	resp := &dto.ProductPriceResponse{
+		ID:        uint(p.ID), //#nosec G115 -- DB ID is always positive
+		Kind:      string(p.Kind),
+		Currency:  p.Currency,
+		Price:     price,
+		StartsAt:  p.StartsAt.Time,
+		CreatedAt: p.CreatedAt.Time,
+	}
+	if p.EndsAt.Valid {
+		resp.EndsAt = &p.EndsAt.Time
+	}
+	if p.CreatedBy.Valid {
+		createdBy := uint(p.CreatedBy.Int32) //#nosec G115 -- DB ID is always positive
+		resp.CreatedBy = &createdBy
 	}
+	return resp, nil
 }
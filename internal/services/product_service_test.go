@@ -10,13 +10,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
 	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
 	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
 )
 
 // MockProductStore provides mocked store methods for ProductService testing
 type MockProductStore struct {
 	mock.Mock
+	noopStore
 }
 
 // Category methods
@@ -56,6 +59,11 @@ func (m *MockProductStore) GetProductByID(ctx context.Context, id int32) (db.Pro
 	return args.Get(0).(db.Product), args.Error(1)
 }
 
+func (m *MockProductStore) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Product), args.Error(1)
+}
+
 func (m *MockProductStore) GetProductBySKU(ctx context.Context, sku string) (db.Product, error) {
 	args := m.Called(ctx, sku)
 	return args.Get(0).(db.Product), args.Error(1)
@@ -106,9 +114,14 @@ func (m *MockProductStore) GetCategoriesByIDs(ctx context.Context, ids []int32)
 	return args.Get(0).([]db.Category), args.Error(1)
 }
 
-func (m *MockProductStore) ExecTx(ctx context.Context, fn func(*db.Queries) error) error {
+// ExecTx records the transaction and, unless it is mocked to fail, runs fn
+// against the mock, so the queries made inside it are checked too
+func (m *MockProductStore) ExecTx(ctx context.Context, fn func(db.Querier) error) error {
 	args := m.Called(ctx, fn)
-	return args.Error(0)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
 }
 
 // Helper to create test category
@@ -129,7 +142,7 @@ func createTestProduct() db.Product {
 		ID:          1,
 		Name:        "Test Product",
 		Description: pgtype.Text{String: "A test product", Valid: true},
-		Price:       pgtype.Numeric{Valid: true},
+		Price:       money.FromCents(9999).Numeric(),
 		Stock:       pgtype.Int4{Int32: 10, Valid: true},
 		CategoryID:  1,
 		Sku:         "TEST-001",
@@ -408,7 +421,7 @@ func TestProductService_GetProductByID(t *testing.T) {
 
 			service := &ProductService{store: createProductStoreWrapper(mockStore)}
 
-			resp, err := service.GetProductByID(context.Background(), tt.id)
+			resp, err := service.GetProductByID(context.Background(), tt.id, "")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -436,6 +449,7 @@ func TestProductService_DeleteProductByID(t *testing.T) {
 			name: "success - product deleted",
 			id:   1,
 			setupMock: func(m *MockProductStore) {
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
 				m.On("SoftDeleteProduct", mock.Anything, int32(1)).Return(nil)
 			},
 			wantErr: false,
@@ -444,7 +458,7 @@ func TestProductService_DeleteProductByID(t *testing.T) {
 			name: "error - product not found",
 			id:   999,
 			setupMock: func(m *MockProductStore) {
-				m.On("SoftDeleteProduct", mock.Anything, int32(999)).Return(pgx.ErrNoRows)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
 			},
 			wantErr: true,
 		},
@@ -475,43 +489,36 @@ func TestProductService_DeleteProductByID(t *testing.T) {
 func TestProductService_CreateProduct(t *testing.T) {
 	t.Parallel()
 
-	testProduct := createTestProduct()
 	testCategory := createTestCategory()
+	req := dto.CreateProductRequest{
+		Name:        "Test Product",
+		Description: "A test product",
+		Price:       money.FromCents(9999),
+		Stock:       10,
+		CategoryID:  1,
+		SKU:         "TEST-001",
+	}
 
 	tests := []struct {
 		name      string
-		req       dto.CreateProductRequest
 		setupMock func(m *MockProductStore)
 		wantErr   bool
 	}{
 		{
 			name: "success - product created",
-			req: dto.CreateProductRequest{
-				Name:        "Test Product",
-				Description: "A test product",
-				Price:       99.99,
-				Stock:       10,
-				CategoryID:  1,
-				SKU:         "TEST-001",
-			},
 			setupMock: func(m *MockProductStore) {
-				m.On("CreateProduct", mock.Anything, mock.Anything).Return(testProduct, nil)
-				m.On("GetCategoryByID", mock.Anything, int32(1)).Return(testCategory, nil)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("CreateProduct", mock.Anything, mock.MatchedBy(func(arg db.CreateProductParams) bool {
+					return arg.Sku == "TEST-001"
+				})).Return(createTestProduct(), nil)
+				m.On("GetCategoryByID", mock.Anything, mock.Anything).Return(testCategory, nil)
 			},
 			wantErr: false,
 		},
 		{
-			name: "error - database error",
-			req: dto.CreateProductRequest{
-				Name:        "Test Product",
-				Description: "A test product",
-				Price:       99.99,
-				Stock:       10,
-				CategoryID:  1,
-				SKU:         "TEST-001",
-			},
+			name: "error - transaction fails",
 			setupMock: func(m *MockProductStore) {
-				m.On("CreateProduct", mock.Anything, mock.Anything).Return(db.Product{}, errors.New("db error"))
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(errors.New("db error"))
 			},
 			wantErr: true,
 		},
@@ -526,20 +533,98 @@ func TestProductService_CreateProduct(t *testing.T) {
 
 			service := &ProductService{store: createProductStoreWrapper(mockStore)}
 
-			resp, err := service.CreateProduct(context.Background(), tt.req)
+			resp, err := service.CreateProduct(context.Background(), 1, req)
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				mockStore.AssertNotCalled(t, "GetCategoryByID", mock.Anything, mock.Anything)
 				return
 			}
 
 			require.NoError(t, err)
 			assert.NotNil(t, resp)
-			assert.Equal(t, testProduct.Name, resp.Name)
+			assert.Equal(t, testCategory.Name, resp.Category.Name)
 		})
 	}
 }
 
+func TestProductService_createProduct(t *testing.T) {
+	t.Parallel()
+
+	req := dto.CreateProductRequest{
+		Name:        "Test Product",
+		Description: "A test product",
+		Price:       money.FromCents(9999),
+		Stock:       10,
+		CategoryID:  1,
+		SKU:         "TEST-001",
+		Attributes:  []dto.ProductAttribute{{Name: "color", Value: "red"}},
+	}
+	created := createTestProduct()
+	created.Stock = pgtype.Int4{Int32: 0, Valid: true}
+
+	t.Run("books the initial stock into the default warehouse", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("CreateProduct", mock.Anything, mock.MatchedBy(func(arg db.CreateProductParams) bool {
+			return arg.Stock.Int32 == 0 && arg.Sku == "TEST-001"
+		})).Return(created, nil)
+		mockStore.On("CreateProductPrice", mock.Anything, mock.MatchedBy(func(arg db.CreateProductPriceParams) bool {
+			return arg.ProductID == 1 && arg.Kind == db.ProductPriceKindList
+		})).Return(db.ProductPrice{}, nil)
+		mockStore.On("GetDefaultWarehouse", mock.Anything).Return(db.Warehouse{ID: 3}, nil)
+		mockStore.On("AdjustProductStock", mock.Anything, db.AdjustProductStockParams{Delta: 10, ID: 1}).
+			Return(db.Product{ID: 1, Stock: pgtype.Int4{Int32: 10, Valid: true}}, nil)
+		mockStore.On("AdjustWarehouseStock", mock.Anything, db.AdjustWarehouseStockParams{WarehouseID: 3, ProductID: 1, Delta: 10}).
+			Return(db.WarehouseStock{}, nil)
+		mockStore.On("CreateInventoryMovement", mock.Anything, mock.MatchedBy(func(arg db.CreateInventoryMovementParams) bool {
+			return arg.Reason == db.InventoryMovementReasonImport && arg.QuantityChange == 10 && arg.StockAfter == 10
+		})).Return(db.InventoryMovement{ProductID: 1, QuantityChange: 10, StockAfter: 10}, nil)
+		mockStore.On("AddProductAttributes", mock.Anything, db.AddProductAttributesParams{
+			ProductID: 1, Names: []string{"color"}, Values: []string{"red"},
+		}).Return(nil)
+
+		service := &ProductService{}
+		product, movement, err := service.createProduct(context.Background(), mockStore, 1, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(10), product.Stock.Int32)
+		require.NotNil(t, movement)
+		assert.Equal(t, int32(10), movement.StockAfter)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("no stock opens no ledger", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("CreateProduct", mock.Anything, mock.Anything).Return(created, nil)
+		mockStore.On("CreateProductPrice", mock.Anything, mock.Anything).Return(db.ProductPrice{}, nil)
+
+		service := &ProductService{}
+		empty := req
+		empty.Stock, empty.Attributes = 0, nil
+		_, movement, err := service.createProduct(context.Background(), mockStore, 1, empty)
+		require.NoError(t, err)
+
+		assert.Nil(t, movement)
+		mockStore.AssertNotCalled(t, "AdjustProductStock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("insert fails", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("CreateProduct", mock.Anything, mock.Anything).Return(db.Product{}, errors.New("db error"))
+
+		service := &ProductService{}
+		_, _, err := service.createProduct(context.Background(), mockStore, 1, req)
+		assert.Error(t, err)
+		mockStore.AssertNotCalled(t, "CreateProductPrice", mock.Anything, mock.Anything)
+	})
+}
+
 func TestProductService_UpdateCategory(t *testing.T) {
 	t.Parallel()
 
@@ -623,6 +708,11 @@ func boolPtr(b bool) *bool {
 	return &b
 }
 
+// Helper for int pointer
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestProductService_GetProducts(t *testing.T) {
 	t.Parallel()
 
@@ -692,7 +782,7 @@ func TestProductService_GetProducts(t *testing.T) {
 
 			service := &ProductService{store: createProductStoreWrapper(mockStore)}
 
-			resp, meta, err := service.GetProducts(context.Background(), tt.page, tt.limit)
+			resp, meta, err := service.GetProducts(context.Background(), tt.page, tt.limit, "")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -709,83 +799,35 @@ func TestProductService_GetProducts(t *testing.T) {
 func TestProductService_UpdateProductByID(t *testing.T) {
 	t.Parallel()
 
-	testProduct := createTestProduct()
+	req := &dto.UpdateProductRequest{
+		Name:        "Updated Product",
+		Description: "Updated description",
+		Price:       money.FromCents(19999),
+		Stock:       intPtr(20),
+		CategoryID:  1,
+	}
 
 	tests := []struct {
 		name      string
-		id        uint
-		req       *dto.UpdateProductRequest
 		setupMock func(m *MockProductStore)
 		wantErr   bool
 	}{
 		{
 			name: "success - product updated",
-			id:   1,
-			req: &dto.UpdateProductRequest{
-				Name:        "Updated Product",
-				Description: "Updated description",
-				Price:       199.99,
-				Stock:       20,
-				CategoryID:  1,
-			},
 			setupMock: func(m *MockProductStore) {
-				m.On("GetProductByID", mock.Anything, int32(1)).Return(testProduct, nil)
-				updatedProduct := testProduct
-				updatedProduct.Name = "Updated Product"
-				m.On("UpdateProduct", mock.Anything, mock.Anything).Return(updatedProduct, nil)
-				m.On("ListProductImages", mock.Anything, int32(1)).Return([]db.ProductImage{}, nil)
-			},
-			wantErr: false,
-		},
-		{
-			name: "success - product updated with IsActive",
-			id:   1,
-			req: &dto.UpdateProductRequest{
-				Name:        "Updated Product",
-				Description: "Updated description",
-				Price:       199.99,
-				Stock:       20,
-				CategoryID:  1,
-				IsActive:    boolPtr(false),
-			},
-			setupMock: func(m *MockProductStore) {
-				m.On("GetProductByID", mock.Anything, int32(1)).Return(testProduct, nil)
-				updatedProduct := testProduct
-				updatedProduct.Name = "Updated Product"
-				m.On("UpdateProduct", mock.Anything, mock.Anything).Return(updatedProduct, nil)
-				m.On("UpdateProductStatus", mock.Anything, mock.Anything).Return(updatedProduct, nil)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				m.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(arg db.UpdateProductParams) bool {
+					return arg.ID == 1 && arg.Name == "Updated Product"
+				})).Return(createTestProduct(), nil)
 				m.On("ListProductImages", mock.Anything, int32(1)).Return([]db.ProductImage{}, nil)
 			},
 			wantErr: false,
 		},
 		{
 			name: "error - product not found",
-			id:   999,
-			req: &dto.UpdateProductRequest{
-				Name:        "Updated Product",
-				Description: "Updated description",
-				Price:       199.99,
-				Stock:       20,
-				CategoryID:  1,
-			},
 			setupMock: func(m *MockProductStore) {
-				m.On("GetProductByID", mock.Anything, int32(999)).Return(db.Product{}, pgx.ErrNoRows)
-			},
-			wantErr: true,
-		},
-		{
-			name: "error - update fails",
-			id:   1,
-			req: &dto.UpdateProductRequest{
-				Name:        "Updated Product",
-				Description: "Updated description",
-				Price:       199.99,
-				Stock:       20,
-				CategoryID:  1,
-			},
-			setupMock: func(m *MockProductStore) {
-				m.On("GetProductByID", mock.Anything, int32(1)).Return(testProduct, nil)
-				m.On("UpdateProduct", mock.Anything, mock.Anything).Return(db.Product{}, errors.New("db error"))
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
 			},
 			wantErr: true,
 		},
@@ -800,10 +842,11 @@ func TestProductService_UpdateProductByID(t *testing.T) {
 
 			service := &ProductService{store: createProductStoreWrapper(mockStore)}
 
-			resp, err := service.UpdateProductByID(context.Background(), tt.id, tt.req)
+			resp, err := service.UpdateProductByID(context.Background(), 1, 1, req)
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				mockStore.AssertNotCalled(t, "ListProductImages", mock.Anything, mock.Anything)
 				return
 			}
 
@@ -813,7 +856,112 @@ func TestProductService_UpdateProductByID(t *testing.T) {
 	}
 }
 
-// productStoreWrapper wraps MockProductStore to implement db.Store interface
+func TestProductService_updateProduct(t *testing.T) {
+	t.Parallel()
+
+	// An order took the stock from 10 to 7 after the admin loaded the product
+	locked := createTestProduct()
+	locked.Stock = pgtype.Int4{Int32: 7, Valid: true}
+	locked.Price = money.FromCents(9999).Numeric()
+
+	req := &dto.UpdateProductRequest{
+		Name:        "Updated Product",
+		Description: "Updated description",
+		Price:       money.FromCents(9999),
+		Stock:       intPtr(20),
+		CategoryID:  1,
+	}
+
+	updated := locked
+	updated.Name = "Updated Product"
+
+	t.Run("moves stock from the locked level without writing it", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(locked, nil)
+		mockStore.On("UpdateProduct", mock.Anything, db.UpdateProductParams{
+			ID:          1,
+			CategoryID:  1,
+			Name:        "Updated Product",
+			Description: pgtype.Text{String: "Updated description", Valid: true},
+			Price:       money.FromCents(9999).Numeric(),
+			Sku:         "TEST-001",
+		}).Return(updated, nil)
+		mockStore.On("GetDefaultWarehouse", mock.Anything).Return(db.Warehouse{ID: 3}, nil)
+		mockStore.On("AdjustProductStock", mock.Anything, db.AdjustProductStockParams{Delta: 13, ID: 1}).
+			Return(db.Product{ID: 1, Stock: pgtype.Int4{Int32: 20, Valid: true}}, nil)
+		mockStore.On("AdjustWarehouseStock", mock.Anything, db.AdjustWarehouseStockParams{WarehouseID: 3, ProductID: 1, Delta: 13}).
+			Return(db.WarehouseStock{}, nil)
+		mockStore.On("CreateInventoryMovement", mock.Anything, mock.MatchedBy(func(arg db.CreateInventoryMovementParams) bool {
+			return arg.Reason == db.InventoryMovementReasonManualAdjustment && arg.QuantityChange == 13
+		})).Return(db.InventoryMovement{ProductID: 1, QuantityChange: 13, StockAfter: 20}, nil)
+
+		service := &ProductService{}
+		product, movement, err := service.updateProduct(context.Background(), mockStore, 1, 1, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(20), product.Stock.Int32)
+		require.NotNil(t, movement)
+		assert.Equal(t, int32(13), movement.QuantityChange)
+		// The price did not change, so no price history is added
+		mockStore.AssertNotCalled(t, "CreateProductPrice", mock.Anything, mock.Anything)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("updates content and status in the same transaction", func(t *testing.T) {
+		t.Parallel()
+
+		seoTitle := "Updated | Store"
+		contentReq := *req
+		contentReq.Stock = nil
+		contentReq.Price = money.FromCents(19999)
+		contentReq.SEOTitle = &seoTitle
+		contentReq.Tags = []string{"New"}
+		contentReq.Attributes = []dto.ProductAttribute{{Name: "color", Value: "blue"}}
+		contentReq.IsActive = boolPtr(false)
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(locked, nil)
+		mockStore.On("UpdateProduct", mock.Anything, mock.Anything).Return(updated, nil)
+		mockStore.On("CreateProductPrice", mock.Anything, mock.MatchedBy(func(arg db.CreateProductPriceParams) bool {
+			return arg.Kind == db.ProductPriceKindList
+		})).Return(db.ProductPrice{}, nil)
+		mockStore.On("DeleteProductAttributes", mock.Anything, int32(1)).Return(nil)
+		mockStore.On("AddProductAttributes", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("UpdateProductContent", mock.Anything, db.UpdateProductContentParams{
+			ID:       1,
+			SeoTitle: pgtype.Text{String: seoTitle, Valid: true},
+			Tags:     []string{"new"},
+		}).Return(nil)
+		inactive := updated
+		inactive.IsActive = pgtype.Bool{Bool: false, Valid: true}
+		mockStore.On("UpdateProductStatus", mock.Anything, mock.Anything).Return(inactive, nil)
+
+		service := &ProductService{}
+		product, movement, err := service.updateProduct(context.Background(), mockStore, 1, 1, &contentReq)
+		require.NoError(t, err)
+
+		assert.Nil(t, movement)
+		assert.False(t, product.IsActive.Bool)
+		mockStore.AssertNotCalled(t, "AdjustProductStock", mock.Anything, mock.Anything)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductByIDForUpdate", mock.Anything, int32(1)).Return(db.Product{}, pgx.ErrNoRows)
+
+		service := &ProductService{}
+		_, _, err := service.updateProduct(context.Background(), mockStore, 1, 1, req)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		mockStore.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.Anything)
+	})
+}
+
+// productStoreWrapper wraps MockProductStore as a db.Store; queries it doesn't mock fall through to noopStore
 type productStoreWrapper struct {
 	*MockProductStore
 }
@@ -821,200 +969,3 @@ type productStoreWrapper struct {
 func createProductStoreWrapper(m *MockProductStore) db.Store {
 	return &productStoreWrapper{MockProductStore: m}
 }
-
-// Implement remaining db.Store methods as stubs
-func (s *productStoreWrapper) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *productStoreWrapper) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *productStoreWrapper) GetUserByID(ctx context.Context, id int32) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *productStoreWrapper) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *productStoreWrapper) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) error {
-	return nil
-}
-func (s *productStoreWrapper) UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *productStoreWrapper) UpdateUserStatus(ctx context.Context, arg db.UpdateUserStatusParams) (db.User, error) {
-	return db.User{}, nil
-}
-func (s *productStoreWrapper) SoftDeleteUser(ctx context.Context, id int32) error { return nil }
-func (s *productStoreWrapper) ListUsers(ctx context.Context, arg db.ListUsersParams) ([]db.User, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) CountUsers(ctx context.Context) (int64, error) { return 0, nil }
-func (s *productStoreWrapper) CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) (db.RefreshToken, error) {
-	return db.RefreshToken{}, nil
-}
-func (s *productStoreWrapper) GetRefreshToken(ctx context.Context, token string) (db.RefreshToken, error) {
-	return db.RefreshToken{}, nil
-}
-func (s *productStoreWrapper) DeleteRefreshToken(ctx context.Context, token string) error { return nil }
-func (s *productStoreWrapper) DeleteRefreshTokensByUserID(ctx context.Context, userID int32) error {
-	return nil
-}
-func (s *productStoreWrapper) DeleteExpiredRefreshTokens(ctx context.Context) error { return nil }
-func (s *productStoreWrapper) GetRefreshTokensByUserID(ctx context.Context, userID int32) ([]db.RefreshToken, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) CreateCart(ctx context.Context, userID int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *productStoreWrapper) GetCartByUserID(ctx context.Context, userID int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *productStoreWrapper) GetCartByID(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *productStoreWrapper) UpdateCartTimestamp(ctx context.Context, id int32) (db.Cart, error) {
-	return db.Cart{}, nil
-}
-func (s *productStoreWrapper) SoftDeleteCart(ctx context.Context, id int32) error { return nil }
-func (s *productStoreWrapper) SoftDeleteCartByUserID(ctx context.Context, userID int32) error {
-	return nil
-}
-func (s *productStoreWrapper) CreateCartItem(ctx context.Context, arg db.CreateCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *productStoreWrapper) GetCartItem(ctx context.Context, arg db.GetCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *productStoreWrapper) GetCartItemByID(ctx context.Context, id int32) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *productStoreWrapper) ListCartItems(ctx context.Context, cartID int32) ([]db.CartItem, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) UpdateCartItemQuantity(ctx context.Context, arg db.UpdateCartItemQuantityParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *productStoreWrapper) UpsertCartItem(ctx context.Context, arg db.UpsertCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *productStoreWrapper) RestoreCartItem(ctx context.Context, arg db.RestoreCartItemParams) (db.CartItem, error) {
-	return db.CartItem{}, nil
-}
-func (s *productStoreWrapper) SoftDeleteCartItem(ctx context.Context, id int32) error { return nil }
-func (s *productStoreWrapper) SoftDeleteCartItemsByCartID(ctx context.Context, cartID int32) error {
-	return nil
-}
-func (s *productStoreWrapper) CountCartItems(ctx context.Context, cartID int32) (int64, error) {
-	return 0, nil
-}
-func (s *productStoreWrapper) ListCategories(ctx context.Context, arg db.ListCategoriesParams) ([]db.Category, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) UpdateCategoryStatus(ctx context.Context, arg db.UpdateCategoryStatusParams) (db.Category, error) {
-	return db.Category{}, nil
-}
-func (s *productStoreWrapper) CountCategories(ctx context.Context) (int64, error) { return 0, nil }
-func (s *productStoreWrapper) GetProductByIDForUpdate(ctx context.Context, id int32) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *productStoreWrapper) GetProductsByIDs(ctx context.Context, ids []int32) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) GetProductsByIDsForUpdate(ctx context.Context, ids []int32) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) ListProducts(ctx context.Context, arg db.ListProductsParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) ListProductsByCategory(ctx context.Context, arg db.ListProductsByCategoryParams) ([]db.Product, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) UpdateProductStatus(ctx context.Context, arg db.UpdateProductStatusParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *productStoreWrapper) UpdateProductStock(ctx context.Context, arg db.UpdateProductStockParams) (db.Product, error) {
-	return db.Product{}, nil
-}
-func (s *productStoreWrapper) CountProducts(ctx context.Context) (int64, error) { return 0, nil }
-func (s *productStoreWrapper) CountProductsByCategory(ctx context.Context, categoryID int32) (int64, error) {
-	return 0, nil
-}
-func (s *productStoreWrapper) GetProductImageByID(ctx context.Context, id int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *productStoreWrapper) GetPrimaryProductImage(ctx context.Context, productID int32) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *productStoreWrapper) UpdateProductImage(ctx context.Context, arg db.UpdateProductImageParams) (db.ProductImage, error) {
-	return db.ProductImage{}, nil
-}
-func (s *productStoreWrapper) SetPrimaryProductImage(ctx context.Context, arg db.SetPrimaryProductImageParams) error {
-	return nil
-}
-func (s *productStoreWrapper) SoftDeleteProductImage(ctx context.Context, id int32) error { return nil }
-func (s *productStoreWrapper) SoftDeleteProductImagesByProductID(ctx context.Context, productID int32) error {
-	return nil
-}
-func (s *productStoreWrapper) CreateOrder(ctx context.Context, arg db.CreateOrderParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *productStoreWrapper) GetOrderByID(ctx context.Context, id int32) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *productStoreWrapper) ListOrders(ctx context.Context, arg db.ListOrdersParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) ListOrdersByUserID(ctx context.Context, arg db.ListOrdersByUserIDParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) ListOrdersByStatus(ctx context.Context, arg db.ListOrdersByStatusParams) ([]db.Order, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) UpdateOrderStatus(ctx context.Context, arg db.UpdateOrderStatusParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *productStoreWrapper) UpdateOrderTotal(ctx context.Context, arg db.UpdateOrderTotalParams) (db.Order, error) {
-	return db.Order{}, nil
-}
-func (s *productStoreWrapper) SoftDeleteOrder(ctx context.Context, id int32) error { return nil }
-func (s *productStoreWrapper) CountOrders(ctx context.Context) (int64, error)      { return 0, nil }
-func (s *productStoreWrapper) CountOrdersByUserID(ctx context.Context, userID int32) (int64, error) {
-	return 0, nil
-}
-func (s *productStoreWrapper) CountOrdersByStatus(ctx context.Context, status db.NullOrderStatus) (int64, error) {
-	return 0, nil
-}
-func (s *productStoreWrapper) GetOrderTotal(ctx context.Context, orderID int32) (pgtype.Numeric, error) {
-	return pgtype.Numeric{}, nil
-}
-func (s *productStoreWrapper) CreateOrderItem(ctx context.Context, arg db.CreateOrderItemParams) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *productStoreWrapper) GetOrderItemByID(ctx context.Context, id int32) (db.OrderItem, error) {
-	return db.OrderItem{}, nil
-}
-func (s *productStoreWrapper) ListOrderItems(ctx context.Context, orderID int32) ([]db.OrderItem, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) SoftDeleteOrderItem(ctx context.Context, id int32) error { return nil }
-func (s *productStoreWrapper) SoftDeleteOrderItemsByOrderID(ctx context.Context, orderID int32) error {
-	return nil
-}
-func (s *productStoreWrapper) CountOrderItems(ctx context.Context, orderID int32) (int64, error) {
-	return 0, nil
-}
-func (s *productStoreWrapper) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *productStoreWrapper) GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.OrderIdempotencyKey, error) {
-	return db.OrderIdempotencyKey{}, nil
-}
-func (s *productStoreWrapper) UpdateIdempotencyKeyOrderID(ctx context.Context, arg db.UpdateIdempotencyKeyOrderIDParams) error {
-	return nil
-}
-func (s *productStoreWrapper) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
-	return nil, nil
-}
-func (s *productStoreWrapper) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
-	return 0, nil
-}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+// amountPattern matches a price such as "$80", "80.50" or "80 dollars"
+const amountPattern = `\$?\s*(\d+(?:\.\d{1,2})?)\b(?:\s*(?:dollars|bucks))?`
+
+var (
+	priceRangePattern = regexp.MustCompile(`(?i)\b(?:between|from)\s+` + amountPattern + `\s+(?:and|to)\s+` + amountPattern)
+	priceSpanPattern  = regexp.MustCompile(`\$(\d+(?:\.\d{1,2})?)\s*-\s*\$?(\d+(?:\.\d{1,2})?)\b`)
+	maxPricePattern   = regexp.MustCompile(`(?i)\b(?:under|below|less than|cheaper than|up to|at most|max)\s+` + amountPattern)
+	minPricePattern   = regexp.MustCompile(`(?i)\b(?:over|above|more than|at least|min)\s+` + amountPattern)
+)
+
+// sortPatterns are the phrases that ask for a sort order, checked in order
+var sortPatterns = []struct {
+	pattern *regexp.Regexp
+	sort    string
+}{
+	{regexp.MustCompile(`(?i)\b(?:most expensive|priciest|highest price[sd]?|high to low)\b`), dto.SearchSortPriceDesc},
+	{regexp.MustCompile(`(?i)\b(?:cheapest|lowest price[sd]?|low to high)\b`), dto.SearchSortPriceAsc},
+	{regexp.MustCompile(`(?i)\b(?:newest|latest|new arrivals|most recent)\b`), dto.SearchSortNewest},
+}
+
+// RuleQueryParser reads prices and sort order with fixed patterns, and
+// categories and attributes by matching the catalog's own names for them.
+// It needs nothing outside the process.
+type RuleQueryParser struct{}
+
+func NewRuleQueryParser() *RuleQueryParser {
+	return &RuleQueryParser{}
+}
+
+// vocabularyTerm is a category or attribute value as a run of words
+type vocabularyTerm struct {
+	words     []string
+	category  *dto.ParsedCategory
+	attribute *dto.ProductAttribute
+}
+
+func (p *RuleQueryParser) Parse(ctx context.Context, query string, vocab dto.SearchVocabulary) (*dto.ParsedQuery, error) {
+	parsed := &dto.ParsedQuery{
+		Categories: []dto.ParsedCategory{},
+		Attributes: []dto.ProductAttribute{},
+	}
+
+	rest := query
+	if m := priceRangePattern.FindStringSubmatchIndex(rest); m != nil {
+		parsed.MinPrice = parseAmount(rest[m[2]:m[3]])
+		parsed.MaxPrice = parseAmount(rest[m[4]:m[5]])
+		rest = removeMatch(rest, m)
+	} else if m := priceSpanPattern.FindStringSubmatchIndex(rest); m != nil {
+		parsed.MinPrice = parseAmount(rest[m[2]:m[3]])
+		parsed.MaxPrice = parseAmount(rest[m[4]:m[5]])
+		rest = removeMatch(rest, m)
+	} else {
+		if m := maxPricePattern.FindStringSubmatchIndex(rest); m != nil {
+			parsed.MaxPrice = parseAmount(rest[m[2]:m[3]])
+			rest = removeMatch(rest, m)
+		}
+		if m := minPricePattern.FindStringSubmatchIndex(rest); m != nil {
+			parsed.MinPrice = parseAmount(rest[m[2]:m[3]])
+			rest = removeMatch(rest, m)
+		}
+	}
+
+	for _, sp := range sortPatterns {
+		if m := sp.pattern.FindStringIndex(rest); m != nil {
+			parsed.Sort = sp.sort
+			rest = removeMatch(rest, m)
+			break
+		}
+	}
+
+	words := queryWords(rest)
+	used := make([]bool, len(words))
+	terms := vocabularyTerms(vocab)
+	seenCategories := map[uint]bool{}
+	seenAttributes := map[dto.ProductAttribute]bool{}
+
+	for i := 0; i < len(words); i++ {
+		if used[i] {
+			continue
+		}
+		for _, term := range terms {
+			if !matchWords(words[i:], term.words) {
+				continue
+			}
+			switch {
+			case term.category != nil && !seenCategories[term.category.ID]:
+				seenCategories[term.category.ID] = true
+				parsed.Categories = append(parsed.Categories, *term.category)
+			case term.attribute != nil && !seenAttributes[*term.attribute]:
+				seenAttributes[*term.attribute] = true
+				parsed.Attributes = append(parsed.Attributes, *term.attribute)
+			}
+			for j := range term.words {
+				used[i+j] = true
+			}
+			// "in red", "in size 10"
+			if term.attribute != nil && i > 0 && words[i-1] == "in" {
+				used[i-1] = true
+			}
+			i += len(term.words) - 1
+			break
+		}
+	}
+
+	var keywords []string
+	for i, word := range words {
+		if !used[i] {
+			keywords = append(keywords, word)
+		}
+	}
+	parsed.Keywords = strings.Join(keywords, " ")
+	return parsed, nil
+}
+
+// vocabularyTerms lists the ways a category or attribute value can be
+// written, longest first so "navy blue" wins over "blue". An attribute value
+// is recognised after its name ("size 10"), and also alone when it is a word
+// no other attribute uses ("red").
+func vocabularyTerms(vocab dto.SearchVocabulary) []vocabularyTerm {
+	var terms []vocabularyTerm
+	for i := range vocab.Categories {
+		terms = append(terms, vocabularyTerm{
+			words:    queryWords(vocab.Categories[i].Name),
+			category: &vocab.Categories[i],
+		})
+	}
+
+	attributesByValue := map[string]int{}
+	for _, attr := range vocab.Attributes {
+		attributesByValue[strings.ToLower(attr.Value)]++
+	}
+	for i := range vocab.Attributes {
+		attr := &vocab.Attributes[i]
+		name, value := queryWords(attr.Name), queryWords(attr.Value)
+		terms = append(terms, vocabularyTerm{words: append(name, value...), attribute: attr})
+		if attributesByValue[strings.ToLower(attr.Value)] == 1 && standaloneValue(attr.Value) {
+			terms = append(terms, vocabularyTerm{words: value, attribute: attr})
+		}
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i].words) > len(terms[j].words)
+	})
+	return terms
+}
+
+// standaloneValue reports whether an attribute value is distinctive enough
+// to recognise without its name: not a number, and not a size like "M"
+func standaloneValue(value string) bool {
+	if len(value) < 3 {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && r != '.' {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWords reports whether words starts with term, letting plurals match
+// their singulars
+func matchWords(words, term []string) bool {
+	if len(term) == 0 || len(words) < len(term) {
+		return false
+	}
+	for i, t := range term {
+		if !sameWord(words[i], t) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameWord(a, b string) bool {
+	return a == b ||
+		a+"s" == b || b+"s" == a ||
+		a+"es" == b || b+"es" == a
+}
+
+// queryWords lowercases text and splits it into words, dropping the
+// punctuation around them
+func queryWords(text string) []string {
+	fields := strings.Fields(strings.ToLower(text))
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if word := strings.Trim(field, `,;:!?"'()`); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// removeMatch removes the match at m[0]:m[1] from s
+func removeMatch(s string, m []int) string {
+	return s[:m[0]] + " " + s[m[1]:]
+}
+
+func parseAmount(s string) *money.Money {
+	amount, err := money.Parse(s)
+	if err != nil {
+		return nil
+	}
+	return &amount
+}
+
+// parseQuery reads the filters a shopper typed into req.Query and merges
+// them into req, leaving the other words as the query. Filters set in the
+// request itself win over parsed ones, and are left out of the parsed query
+// returned, so it holds only what parsing added.
+func (s *ProductService) parseQuery(ctx context.Context, req dto.SearchProductsRequest) (dto.SearchProductsRequest, *dto.ParsedQuery, error) {
+	vocab, err := s.searchVocabulary(ctx)
+	if err != nil {
+		return req, nil, err
+	}
+
+	var parsed *dto.ParsedQuery
+	if s.parser != nil {
+		parsed, err = s.parser.Parse(ctx, req.Query, vocab)
+	}
+	// Without a parser, or when a model-backed one fails, parse by rule
+	// rather than fail the search
+	if s.parser == nil || err != nil {
+		if parsed, err = NewRuleQueryParser().Parse(ctx, req.Query, vocab); err != nil {
+			return req, nil, fmt.Errorf("failed to parse search query: %w", err)
+		}
+	}
+
+	req.Query = parsed.Keywords
+	if len(req.CategoryIDs) == 0 {
+		for _, category := range parsed.Categories {
+			req.CategoryIDs = append(req.CategoryIDs, category.ID)
+		}
+	} else {
+		parsed.Categories = []dto.ParsedCategory{}
+	}
+	if req.MinPrice == nil {
+		req.MinPrice = parsed.MinPrice
+	} else {
+		parsed.MinPrice = nil
+	}
+	if req.MaxPrice == nil {
+		req.MaxPrice = parsed.MaxPrice
+	} else {
+		parsed.MaxPrice = nil
+	}
+	if req.Sort == "" {
+		req.Sort = parsed.Sort
+	} else {
+		parsed.Sort = ""
+	}
+
+	filtered := map[string]bool{}
+	for _, attr := range req.Attributes {
+		name, _, _ := strings.Cut(attr, ":")
+		filtered[strings.TrimSpace(name)] = true
+	}
+	attributes := []dto.ProductAttribute{}
+	for _, attr := range parsed.Attributes {
+		if !filtered[attr.Name] {
+			req.Attributes = append(req.Attributes, attr.Name+":"+attr.Value)
+			attributes = append(attributes, attr)
+		}
+	}
+	parsed.Attributes = attributes
+
+	return req, parsed, nil
+}
+
+// searchVocabulary lists the categories and attribute values a query parser
+// can recognise
+func (s *ProductService) searchVocabulary(ctx context.Context) (dto.SearchVocabulary, error) {
+	categories, err := s.store.ListCategoryNames(ctx)
+	if err != nil {
+		return dto.SearchVocabulary{}, fmt.Errorf("failed to list categories: %w", err)
+	}
+	attributes, err := s.store.ListAttributeValues(ctx)
+	if err != nil {
+		return dto.SearchVocabulary{}, fmt.Errorf("failed to list attribute values: %w", err)
+	}
+
+	vocab := dto.SearchVocabulary{
+		Categories: make([]dto.ParsedCategory, len(categories)),
+		Attributes: make([]dto.ProductAttribute, len(attributes)),
+	}
+	for i, category := range categories {
+		vocab.Categories[i] = dto.ParsedCategory{ID: uint(category.ID), Name: category.Name} //#nosec G115 -- IDs are positive
+	}
+	for i, attr := range attributes {
+		vocab.Attributes[i] = dto.ProductAttribute{Name: attr.Name, Value: attr.Value}
+	}
+	return vocab, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+func testVocabulary() dto.SearchVocabulary {
+	return dto.SearchVocabulary{
+		Categories: []dto.ParsedCategory{
+			{ID: 1, Name: "Shoes"},
+			{ID: 2, Name: "Rain Jackets"},
+		},
+		Attributes: []dto.ProductAttribute{
+			{Name: "color", Value: "navy blue"},
+			{Name: "color", Value: "red"},
+			{Name: "size", Value: "10"},
+			{Name: "size", Value: "M"},
+		},
+	}
+}
+
+func moneyPtr(cents int64) *money.Money {
+	m := money.FromCents(cents)
+	return &m
+}
+
+func TestRuleQueryParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  dto.ParsedQuery
+	}{
+		{
+			name:  "price, category and attributes",
+			query: "red running shoes under $80 in size 10",
+			want: dto.ParsedQuery{
+				Keywords:   "running",
+				Categories: []dto.ParsedCategory{{ID: 1, Name: "Shoes"}},
+				MaxPrice:   moneyPtr(8000),
+				Attributes: []dto.ProductAttribute{{Name: "color", Value: "red"}, {Name: "size", Value: "10"}},
+			},
+		},
+		{
+			name:  "price range and multi-word names",
+			query: "navy blue rain jacket between 40 and 120.50 dollars",
+			want: dto.ParsedQuery{
+				Keywords:   "",
+				Categories: []dto.ParsedCategory{{ID: 2, Name: "Rain Jackets"}},
+				MinPrice:   moneyPtr(4000),
+				MaxPrice:   moneyPtr(12050),
+				Attributes: []dto.ProductAttribute{{Name: "color", Value: "navy blue"}},
+			},
+		},
+		{
+			name:  "price span and sort",
+			query: "cheapest leather boots $20-$50",
+			want: dto.ParsedQuery{
+				Keywords:   "leather boots",
+				Categories: []dto.ParsedCategory{},
+				MinPrice:   moneyPtr(2000),
+				MaxPrice:   moneyPtr(5000),
+				Attributes: []dto.ProductAttribute{},
+				Sort:       dto.SearchSortPriceAsc,
+			},
+		},
+		{
+			name:  "minimum price and newest",
+			query: "newest Shoes over $100",
+			want: dto.ParsedQuery{
+				Keywords:   "",
+				Categories: []dto.ParsedCategory{{ID: 1, Name: "Shoes"}},
+				MinPrice:   moneyPtr(10000),
+				Attributes: []dto.ProductAttribute{},
+				Sort:       dto.SearchSortNewest,
+			},
+		},
+		{
+			name:  "short and numeric values need their attribute name",
+			query: "m 10 shirt size M",
+			want: dto.ParsedQuery{
+				Keywords:   "m 10 shirt",
+				Categories: []dto.ParsedCategory{},
+				Attributes: []dto.ProductAttribute{{Name: "size", Value: "M"}},
+			},
+		},
+		{
+			name:  "nothing to parse",
+			query: "wireless headphones",
+			want: dto.ParsedQuery{
+				Keywords:   "wireless headphones",
+				Categories: []dto.ParsedCategory{},
+				Attributes: []dto.ProductAttribute{},
+			},
+		},
+	}
+
+	parser := NewRuleQueryParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parser.Parse(context.Background(), tt.query, testVocabulary())
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, got)
+		})
+	}
+}
+
+// vocabularyStoreWrapper serves a fixed search vocabulary
+type vocabularyStoreWrapper struct {
+	*productStoreWrapper
+	vocab dto.SearchVocabulary
+}
+
+func (s *vocabularyStoreWrapper) ListCategoryNames(ctx context.Context) ([]db.ListCategoryNamesRow, error) {
+	rows := make([]db.ListCategoryNamesRow, len(s.vocab.Categories))
+	for i, c := range s.vocab.Categories {
+		rows[i] = db.ListCategoryNamesRow{ID: int32(c.ID), Name: c.Name} //#nosec G115 -- test IDs are small
+	}
+	return rows, nil
+}
+
+func (s *vocabularyStoreWrapper) ListAttributeValues(ctx context.Context) ([]db.ListAttributeValuesRow, error) {
+	rows := make([]db.ListAttributeValuesRow, len(s.vocab.Attributes))
+	for i, a := range s.vocab.Attributes {
+		rows[i] = db.ListAttributeValuesRow{Name: a.Name, Value: a.Value}
+	}
+	return rows, nil
+}
+
+// failingQueryParser stands in for a model that can't be reached
+type failingQueryParser struct{}
+
+func (failingQueryParser) Parse(ctx context.Context, query string, vocab dto.SearchVocabulary) (*dto.ParsedQuery, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestProductService_ParseQuery(t *testing.T) {
+	t.Parallel()
+
+	store := &vocabularyStoreWrapper{
+		productStoreWrapper: &productStoreWrapper{MockProductStore: new(MockProductStore)},
+		vocab:               testVocabulary(),
+	}
+
+	t.Run("parsed filters fill the request", func(t *testing.T) {
+		t.Parallel()
+
+		service := &ProductService{store: store}
+		req, parsed, err := service.parseQuery(context.Background(), dto.SearchProductsRequest{
+			Query: "red running shoes under $80 in size 10",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "running", req.Query)
+		assert.Equal(t, []uint{1}, req.CategoryIDs)
+		assert.Equal(t, moneyPtr(8000), req.MaxPrice)
+		assert.Equal(t, []string{"color:red", "size:10"}, req.Attributes)
+		assert.Equal(t, "running", parsed.Keywords)
+		assert.Len(t, parsed.Attributes, 2)
+	})
+
+	t.Run("request filters win over parsed ones", func(t *testing.T) {
+		t.Parallel()
+
+		service := &ProductService{store: store, parser: NewRuleQueryParser()}
+		req, parsed, err := service.parseQuery(context.Background(), dto.SearchProductsRequest{
+			Query:       "cheapest red shoes under $80 in size 10",
+			CategoryIDs: []uint{2},
+			MaxPrice:    moneyPtr(5000),
+			Attributes:  []string{"size:M"},
+			Sort:        dto.SearchSortNewest,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []uint{2}, req.CategoryIDs)
+		assert.Equal(t, moneyPtr(5000), req.MaxPrice)
+		assert.Equal(t, []string{"size:M", "color:red"}, req.Attributes)
+		assert.Equal(t, dto.SearchSortNewest, req.Sort)
+
+		assert.Empty(t, parsed.Categories)
+		assert.Nil(t, parsed.MaxPrice)
+		assert.Equal(t, []dto.ProductAttribute{{Name: "color", Value: "red"}}, parsed.Attributes)
+		assert.Empty(t, parsed.Sort)
+	})
+
+	t.Run("failing parser falls back to rules", func(t *testing.T) {
+		t.Parallel()
+
+		service := &ProductService{store: store, parser: failingQueryParser{}}
+		req, parsed, err := service.parseQuery(context.Background(), dto.SearchProductsRequest{Query: "shoes under 50"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "", req.Query)
+		assert.Equal(t, []uint{1}, req.CategoryIDs)
+		assert.Equal(t, moneyPtr(5000), parsed.MaxPrice)
+	})
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const (
+	defaultRecommendationNeighbours = 20
+	defaultRecommendations          = 10
+	maxRecommendations              = 50
+)
+
+// RecommendationService computes "customers also bought" recommendations:
+// for each product, the products most often bought in the same orders
+type RecommendationService struct {
+	store          db.Store
+	topN           int
+	minCoPurchases int
+}
+
+// NewRecommendationService creates a RecommendationService that keeps the
+// topN most similar products for each product, counting only pairs bought
+// together in at least minCoPurchases orders
+func NewRecommendationService(store db.Store, topN, minCoPurchases int) *RecommendationService {
+	if topN <= 0 {
+		topN = defaultRecommendationNeighbours
+	}
+	if minCoPurchases <= 0 {
+		minCoPurchases = 1
+	}
+	return &RecommendationService{
+		store:          store,
+		topN:           topN,
+		minCoPurchases: minCoPurchases,
+	}
+}
+
+// Refresh recomputes every product's recommendations from order history and
+// returns how many it stored. The old set is replaced in one transaction, so
+// readers never see it half built.
+func (s *RecommendationService) Refresh(ctx context.Context) (int64, error) {
+	var stored int64
+	err := s.store.ExecTx(ctx, func(q db.Querier) error {
+		if err := q.DeleteProductRecommendations(ctx); err != nil {
+			return fmt.Errorf("failed to clear product recommendations: %w", err)
+		}
+
+		var err error
+		stored, err = q.InsertProductRecommendations(ctx, db.InsertProductRecommendationsParams{
+			MinCoPurchases: int32(s.minCoPurchases), //#nosec G115 -- small config value
+			TopN:           int32(s.topN),           //#nosec G115 -- small config value
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compute product recommendations: %w", err)
+		}
+		return nil
+	})
+	return stored, err
+}
+
+// GetProductRecommendations returns the products most often bought together
+// with a product, best first
+func (s *ProductService) GetProductRecommendations(ctx context.Context, productID uint, req dto.RecommendationsRequest, currency string) ([]dto.ProductRecommendation, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.store.GetProductByID(ctx, int32(productID)) //#nosec G115 -- id from validated request
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	rows, err := s.store.ListProductRecommendations(ctx, db.ListProductRecommendationsParams{
+		ProductID: product.ID,
+		Limit:     recommendationLimit(req),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product recommendations: %w", err)
+	}
+
+	products := make([]db.Product, len(rows))
+	scores := make([]float32, len(rows))
+	for i, row := range rows {
+		products[i] = db.Product{
+			ID:          row.ID,
+			CategoryID:  row.CategoryID,
+			Name:        row.Name,
+			Description: row.Description,
+			Price:       row.Price,
+			Stock:       row.Stock,
+			Sku:         row.Sku,
+			IsActive:    row.IsActive,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		}
+		scores[i] = row.Score
+	}
+	return s.recommendations(ctx, products, scores, quote)
+}
+
+// GetCartRecommendations recommends products to go with everything in the
+// user's cart: the neighbours of each cart product, scored by how similar
+// they are to the cart as a whole. Products already in the cart are left out.
+func (s *ProductService) GetCartRecommendations(ctx context.Context, userID int32, req dto.RecommendationsRequest, currency string) ([]dto.ProductRecommendation, error) {
+	quote, err := s.currencies.Quote(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.ListCartRecommendations(ctx, db.ListCartRecommendationsParams{
+		UserID: userID,
+		Limit:  recommendationLimit(req),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart recommendations: %w", err)
+	}
+
+	products := make([]db.Product, len(rows))
+	scores := make([]float32, len(rows))
+	for i, row := range rows {
+		products[i] = db.Product{
+			ID:          row.ID,
+			CategoryID:  row.CategoryID,
+			Name:        row.Name,
+			Description: row.Description,
+			Price:       row.Price,
+			Stock:       row.Stock,
+			Sku:         row.Sku,
+			IsActive:    row.IsActive,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		}
+		scores[i] = row.Score
+	}
+	return s.recommendations(ctx, products, scores, quote)
+}
+
+func (s *ProductService) recommendations(ctx context.Context, products []db.Product, scores []float32, quote Quote) ([]dto.ProductRecommendation, error) {
+	if len(products) == 0 {
+		return []dto.ProductRecommendation{}, nil
+	}
+
+	responses, err := s.productResponses(ctx, products, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]dto.ProductRecommendation, len(responses))
+	for i, response := range responses {
+		recommendations[i] = dto.ProductRecommendation{ProductResponse: response, Score: scores[i]}
+	}
+	return recommendations, nil
+}
+
+// recommendationLimit returns defaultRecommendations unless asked for
+// another number, and never more than maxRecommendations
+func recommendationLimit(req dto.RecommendationsRequest) int32 {
+	if req.Limit < 1 {
+		return defaultRecommendations
+	}
+	return int32(min(req.Limit, maxRecommendations)) //#nosec G115 -- capped above
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+// recommendationStoreWrapper serves fixed recommendations and records what was asked for
+type recommendationStoreWrapper struct {
+	*productStoreWrapper
+	productCalls []db.ListProductRecommendationsParams
+	cartCalls    []db.ListCartRecommendationsParams
+	rows         []db.ListProductRecommendationsRow
+}
+
+func (s *recommendationStoreWrapper) ListProductRecommendations(ctx context.Context, arg db.ListProductRecommendationsParams) ([]db.ListProductRecommendationsRow, error) {
+	s.productCalls = append(s.productCalls, arg)
+	return s.rows, nil
+}
+
+func (s *recommendationStoreWrapper) ListCartRecommendations(ctx context.Context, arg db.ListCartRecommendationsParams) ([]db.ListCartRecommendationsRow, error) {
+	s.cartCalls = append(s.cartCalls, arg)
+	rows := make([]db.ListCartRecommendationsRow, len(s.rows))
+	for i, row := range s.rows {
+		rows[i] = db.ListCartRecommendationsRow(row)
+	}
+	return rows, nil
+}
+
+func TestProductService_GetProductRecommendations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("recommendations in score order", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+		mockStore.On("GetCategoriesByIDs", mock.Anything, []int32{1}).Return([]db.Category{createTestCategory()}, nil)
+		mockStore.On("ListProductImagesByProductIDs", mock.Anything, []int32{5, 3}).Return([]db.ProductImage{}, nil)
+
+		store := &recommendationStoreWrapper{
+			productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore},
+			rows: []db.ListProductRecommendationsRow{
+				{ID: 5, CategoryID: 1, Name: "Phone Case", Price: money.FromCents(1999).Numeric(), Score: 0.8},
+				{ID: 3, CategoryID: 1, Name: "Charger", Price: money.FromCents(2499).Numeric(), Score: 0.4},
+			},
+		}
+		service := &ProductService{store: store}
+
+		recommendations, err := service.GetProductRecommendations(context.Background(), 1, dto.RecommendationsRequest{}, "")
+		require.NoError(t, err)
+
+		require.Len(t, store.productCalls, 1)
+		assert.Equal(t, db.ListProductRecommendationsParams{ProductID: 1, Limit: defaultRecommendations}, store.productCalls[0])
+		require.Len(t, recommendations, 2)
+		assert.Equal(t, "Phone Case", recommendations[0].Name)
+		assert.InDelta(t, 0.8, recommendations[0].Score, 1e-6)
+		assert.Equal(t, "Charger", recommendations[1].Name)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(MockProductStore)
+		mockStore.On("GetProductByID", mock.Anything, int32(999)).Return(db.Product{}, pgx.ErrNoRows)
+		service := &ProductService{store: &recommendationStoreWrapper{productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore}}}
+
+		_, err := service.GetProductRecommendations(context.Background(), 999, dto.RecommendationsRequest{}, "")
+		assert.True(t, errors.Is(err, ErrProductNotFound))
+	})
+}
+
+func TestProductService_GetCartRecommendations(t *testing.T) {
+	t.Parallel()
+
+	store := &recommendationStoreWrapper{productStoreWrapper: &productStoreWrapper{MockProductStore: new(MockProductStore)}}
+	service := &ProductService{store: store}
+
+	recommendations, err := service.GetCartRecommendations(context.Background(), 7, dto.RecommendationsRequest{Limit: 500}, "")
+	require.NoError(t, err)
+
+	assert.Empty(t, recommendations)
+	assert.NotNil(t, recommendations)
+	require.Len(t, store.cartCalls, 1)
+	assert.Equal(t, db.ListCartRecommendationsParams{UserID: 7, Limit: maxRecommendations}, store.cartCalls[0])
+}
+
+func TestRecommendationService_Refresh(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(MockProductStore)
+	mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+	service := NewRecommendationService(&productStoreWrapper{MockProductStore: mockStore}, 0, 0)
+
+	assert.Equal(t, defaultRecommendationNeighbours, service.topN)
+	assert.Equal(t, 1, service.minCoPurchases)
+
+	_, err := service.Refresh(context.Background())
+	assert.EqualError(t, err, "connection reset")
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+)
+
+const defaultReservationTTL = 15 * time.Minute
+
+var ErrReservationNotFound = errors.New("no active stock reservation")
+
+// ReservationService holds stock for a customer while they check out
+type ReservationService struct {
+	store db.Store
+	ttl   time.Duration
+}
+
+func NewReservationService(store db.Store, ttl time.Duration) *ReservationService {
+	if ttl <= 0 {
+		ttl = defaultReservationTTL
+	}
+	return &ReservationService{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// ReserveCart holds stock for every item in the user's cart until the TTL
+// elapses, replacing any earlier hold. Stock held by other customers is not
+// available, so this fails with ErrInsufficientStock when they got there first.
+func (s *ReservationService) ReserveCart(ctx context.Context, userID int32) (*dto.ReservationResponse, error) {
+	cart, err := s.store.GetCartByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEmptyCart
+		}
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	cartItems, err := s.store.ListCartItems(ctx, cart.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart items: %w", err)
+	}
+
+	if len(cartItems) == 0 {
+		return nil, ErrEmptyCart
+	}
+
+	productIDs := make([]int32, len(cartItems))
+	for i, item := range cartItems {
+		productIDs[i] = item.ProductID
+	}
+
+	expiresAt := pgtype.Timestamptz{Time: time.Now().Add(s.ttl), Valid: true}
+	reservations := make([]db.StockReservation, 0, len(cartItems))
+
+	err = s.store.ExecTx(ctx, func(q db.Querier) error {
+		// Lock the products so concurrent checkouts see each other's holds
+		products, err := q.GetProductsByIDsForUpdate(ctx, productIDs)
+		if err != nil {
+			return fmt.Errorf("failed to lock products: %w", err)
+		}
+
+		if len(products) != len(productIDs) {
+			return ErrProductNotFound
+		}
+
+		reserved, err := reservedStock(ctx, q, productIDs, userID)
+		if err != nil {
+			return err
+		}
+
+		productMap := make(map[int32]db.Product, len(products))
+		for _, p := range products {
+			productMap[p.ID] = p
+		}
+
+		for _, item := range cartItems {
+			if availableStock(productMap[item.ProductID].Stock, reserved[item.ProductID]) < int(item.Quantity) {
+				return ErrInsufficientStock
+			}
+		}
+
+		// Replace the previous hold so removed cart items are released
+		if err := q.DeleteStockReservationsByUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to release previous reservation: %w", err)
+		}
+
+		for _, item := range cartItems {
+			reservation, err := q.UpsertStockReservation(ctx, db.UpsertStockReservationParams{
+				UserID:    userID,
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				ExpiresAt: expiresAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to reserve stock: %w", err)
+			}
+			reservations = append(reservations, reservation)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservations, expiresAt.Time), nil
+}
+
+// GetReservation returns the user's active stock hold
+func (s *ReservationService) GetReservation(ctx context.Context, userID int32) (*dto.ReservationResponse, error) {
+	reservations, err := s.store.ListActiveStockReservationsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+
+	if len(reservations) == 0 {
+		return nil, ErrReservationNotFound
+	}
+
+	return toReservationResponse(reservations, reservations[0].ExpiresAt.Time), nil
+}
+
+// ReleaseReservation drops the user's stock hold, if any
+func (s *ReservationService) ReleaseReservation(ctx context.Context, userID int32) error {
+	if err := s.store.DeleteStockReservationsByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+	return nil
+}
+
+// ReleaseExpired deletes every reservation past its expiry and reports how many were released
+func (s *ReservationService) ReleaseExpired(ctx context.Context) (int64, error) {
+	released, err := s.store.DeleteExpiredStockReservations(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release expired reservations: %w", err)
+	}
+	return released, nil
+}
+
+// reservedStock returns the quantity of each product held by unexpired
+// reservations, ignoring holds owned by excludeUserID (0 counts every hold)
+func reservedStock(ctx context.Context, q db.Querier, productIDs []int32, excludeUserID int32) (map[int32]int32, error) {
+	reserved := make(map[int32]int32, len(productIDs))
+	if len(productIDs) == 0 {
+		return reserved, nil
+	}
+
+	rows, err := q.GetReservedStockByProductIDs(ctx, db.GetReservedStockByProductIDsParams{
+		ProductIds:    productIDs,
+		ExcludeUserID: excludeUserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved stock: %w", err)
+	}
+
+	for _, row := range rows {
+		reserved[row.ProductID] = row.Reserved
+	}
+	return reserved, nil
+}
+
+// availableStock is on-hand stock minus active reservations, never below zero
+func availableStock(stock pgtype.Int4, reserved int32) int {
+	return max(int(stock.Int32)-int(reserved), 0)
+}
+
+func toReservationResponse(reservations []db.StockReservation, expiresAt time.Time) *dto.ReservationResponse {
+	items := make([]dto.ReservationItemResponse, len(reservations))
+	for i, r := range reservations {
+		items[i] = dto.ReservationItemResponse{
+			ProductID: uint(r.ProductID), //#nosec G115 -- DB ID is always positive
+			Quantity:  int(r.Quantity),
+		}
+	}
+
+	return &dto.ReservationResponse{
+		ExpiresAt: expiresAt,
+		Items:     items,
+	}
+}
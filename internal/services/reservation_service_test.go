@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+)
+
+func TestReservationService_ReserveCart(t *testing.T) {
+	t.Parallel()
+
+	testCart := createTestCart()
+
+	tests := []struct {
+		name      string
+		setupMock func(m *mocks.MockStore)
+		wantErr   error
+	}{
+		{
+			name: "success - cart reserved",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetCartByUserID", mock.Anything, int32(1)).Return(testCart, nil)
+				m.On("ListCartItems", mock.Anything, int32(1)).Return([]db.CartItem{createTestCartItem()}, nil)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetProductsByIDsForUpdate", mock.Anything, []int32{1}).Return([]db.Product{createTestProduct()}, nil)
+				m.On("GetReservedStockByProductIDs", mock.Anything, db.GetReservedStockByProductIDsParams{
+					ProductIds:    []int32{1},
+					ExcludeUserID: 1,
+				}).Return([]db.GetReservedStockByProductIDsRow{{ProductID: 1, Reserved: 8}}, nil)
+				m.On("DeleteStockReservationsByUser", mock.Anything, int32(1)).Return(nil)
+				m.On("UpsertStockReservation", mock.Anything, mock.MatchedBy(func(arg db.UpsertStockReservationParams) bool {
+					return arg.UserID == 1 && arg.ProductID == 1 && arg.Quantity == 2
+				})).Return(db.StockReservation{ID: 1, UserID: 1, ProductID: 1, Quantity: 2}, nil)
+			},
+		},
+		{
+			name: "error - no cart",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetCartByUserID", mock.Anything, int32(1)).Return(db.Cart{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrEmptyCart,
+		},
+		{
+			name: "error - empty cart",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetCartByUserID", mock.Anything, int32(1)).Return(testCart, nil)
+				m.On("ListCartItems", mock.Anything, int32(1)).Return([]db.CartItem{}, nil)
+			},
+			wantErr: ErrEmptyCart,
+		},
+		{
+			name: "error - stock held by other customers",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetCartByUserID", mock.Anything, int32(1)).Return(testCart, nil)
+				m.On("ListCartItems", mock.Anything, int32(1)).Return([]db.CartItem{createTestCartItem()}, nil)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetProductsByIDsForUpdate", mock.Anything, []int32{1}).Return([]db.Product{createTestProduct()}, nil)
+				m.On("GetReservedStockByProductIDs", mock.Anything, mock.Anything).
+					Return([]db.GetReservedStockByProductIDsRow{{ProductID: 1, Reserved: 9}}, nil)
+			},
+			wantErr: ErrInsufficientStock,
+		},
+		{
+			name: "error - product gone",
+			setupMock: func(m *mocks.MockStore) {
+				m.On("GetCartByUserID", mock.Anything, int32(1)).Return(testCart, nil)
+				m.On("ListCartItems", mock.Anything, int32(1)).Return([]db.CartItem{createTestCartItem()}, nil)
+				m.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetProductsByIDsForUpdate", mock.Anything, []int32{1}).Return([]db.Product{}, nil)
+			},
+			wantErr: ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			tt.setupMock(mockStore)
+
+			service := NewReservationService(mockStore, 10*time.Minute)
+
+			before := time.Now()
+			resp, err := service.ReserveCart(context.Background(), 1)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockStore.AssertExpectations(t)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.WithinDuration(t, before.Add(10*time.Minute), resp.ExpiresAt, time.Second)
+			require.Len(t, resp.Items, 1)
+			assert.Equal(t, uint(1), resp.Items[0].ProductID)
+			assert.Equal(t, 2, resp.Items[0].Quantity)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReservationService_GetReservation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - returns active hold", func(t *testing.T) {
+		t.Parallel()
+
+		expiresAt := time.Now().Add(5 * time.Minute)
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListActiveStockReservationsByUser", mock.Anything, int32(1)).Return([]db.StockReservation{
+			{ID: 1, UserID: 1, ProductID: 3, Quantity: 2, ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true}},
+		}, nil)
+
+		service := NewReservationService(mockStore, 0)
+
+		resp, err := service.GetReservation(context.Background(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, expiresAt, resp.ExpiresAt)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, uint(3), resp.Items[0].ProductID)
+		assert.Equal(t, 2, resp.Items[0].Quantity)
+	})
+
+	t.Run("error - nothing reserved", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListActiveStockReservationsByUser", mock.Anything, int32(1)).Return([]db.StockReservation{}, nil)
+
+		service := NewReservationService(mockStore, 0)
+
+		_, err := service.GetReservation(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrReservationNotFound)
+	})
+}
+
+func TestReservationService_ReleaseExpired(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - reports released holds", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("DeleteExpiredStockReservations", mock.Anything).Return(int64(3), nil)
+
+		service := NewReservationService(mockStore, 0)
+
+		released, err := service.ReleaseExpired(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), released)
+	})
+
+	t.Run("error - database error", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("DeleteExpiredStockReservations", mock.Anything).Return(int64(0), errors.New("db error"))
+
+		service := NewReservationService(mockStore, 0)
+
+		_, err := service.ReleaseExpired(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestReservedStock(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("GetReservedStockByProductIDs", mock.Anything, db.GetReservedStockByProductIDsParams{
+		ProductIds:    []int32{1, 2},
+		ExcludeUserID: 7,
+	}).Return([]db.GetReservedStockByProductIDsRow{{ProductID: 1, Reserved: 4}}, nil)
+
+	reserved, err := reservedStock(context.Background(), mockStore, []int32{1, 2}, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), reserved[1])
+	assert.Equal(t, int32(0), reserved[2])
+
+	stock := pgtype.Int4{Int32: 10, Valid: true}
+	assert.Equal(t, 6, availableStock(stock, reserved[1]))
+	assert.Equal(t, 10, availableStock(stock, reserved[2]))
+	assert.Equal(t, 0, availableStock(stock, 12))
+}
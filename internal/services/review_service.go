@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/events"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+	"github.com/trenchesdeveloper/go-ai-store/internal/utils"
+)
+
+// summaryReviewLimit is how many of a product's latest reviews are
+// summarized; the count and average rating still cover all of them
+const summaryReviewLimit = 500
+
+var (
+	ErrReviewExists          = errors.New("you have already reviewed this product")
+	ErrReviewSummaryNotFound = errors.New("product has no review summary yet")
+)
+
+// ReviewService stores product reviews and the summaries made of them. A
+// new review publishes a review_created event, and the notifier refreshes
+// the product's summary when it arrives, so writing a review never waits
+// on summarizing. Without a publisher, summaries are refreshed inline.
+type ReviewService struct {
+	store      db.Store
+	pub        events.EventPublisher
+	summarizer interfaces.Summarizer
+}
+
+func NewReviewService(store db.Store, pub events.EventPublisher, summarizer interfaces.Summarizer) *ReviewService {
+	return &ReviewService{
+		store:      store,
+		pub:        pub,
+		summarizer: summarizer,
+	}
+}
+
+// CreateReview records a user's review of a product. Each user reviews a
+// product once.
+func (s *ReviewService) CreateReview(ctx context.Context, userID, productID int32, req dto.CreateReviewRequest) (*dto.ReviewResponse, error) {
+	if _, err := s.store.GetProductByID(ctx, productID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	review, err := s.store.CreateProductReview(ctx, db.CreateProductReviewParams{
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    int32(req.Rating), //#nosec G115 -- rating is validated to 1-5
+		Body:      req.Body,
+	})
+	if err != nil {
+		// The insert does nothing when the user already has a review
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReviewExists
+		}
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	// The review is saved either way; when the event can't be published the
+	// summary is refreshed here instead
+	if err := s.publishReviewCreated(ctx, userID, productID); err != nil {
+		_ = s.RefreshSummary(ctx, productID)
+	}
+
+	response := toReviewResponse(review)
+	return &response, nil
+}
+
+// ListReviews returns a product's reviews, newest first
+func (s *ReviewService) ListReviews(ctx context.Context, productID int32, page, limit int) ([]dto.ReviewResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	if _, err := s.store.GetProductByID(ctx, productID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrProductNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	totalCount, err := s.store.CountProductReviews(ctx, productID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	totalPages := int(totalCount) / limit
+	if int(totalCount)%limit > 0 {
+		totalPages++
+	}
+
+	reviews, err := s.store.ListProductReviews(ctx, db.ListProductReviewsParams{
+		ProductID: productID,
+		Limit:     int32(limit),              //#nosec G115 -- pagination values are bounded
+		Offset:    int32((page - 1) * limit), //#nosec G115 -- pagination values are bounded
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	responses := make([]dto.ReviewResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = toReviewResponse(review)
+	}
+
+	return responses, &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: int(totalCount),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetSummary returns a product's latest review summary
+func (s *ReviewService) GetSummary(ctx context.Context, productID int32) (*dto.ReviewSummary, error) {
+	summary, err := s.store.GetProductReviewSummary(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReviewSummaryNotFound
+		}
+		return nil, fmt.Errorf("failed to get review summary: %w", err)
+	}
+	return toReviewSummary(summary), nil
+}
+
+// RefreshSummary summarizes a product's reviews again and stores the
+// result. A product without reviews is left without a summary.
+func (s *ReviewService) RefreshSummary(ctx context.Context, productID int32) error {
+	stats, err := s.store.GetProductReviewStats(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get review stats: %w", err)
+	}
+	if stats.ReviewCount == 0 {
+		return nil
+	}
+
+	reviews, err := s.store.ListProductReviews(ctx, db.ListProductReviewsParams{
+		ProductID: productID,
+		Limit:     summaryReviewLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	texts := make([]dto.ReviewText, len(reviews))
+	for i, review := range reviews {
+		texts[i] = dto.ReviewText{Rating: int(review.Rating), Body: review.Body}
+	}
+
+	generated, err := s.summarizer.Summarize(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to summarize reviews: %w", err)
+	}
+
+	_, err = s.store.UpsertProductReviewSummary(ctx, db.UpsertProductReviewSummaryParams{
+		ProductID:     productID,
+		ReviewCount:   stats.ReviewCount,
+		AverageRating: stats.AverageRating,
+		Sentiment:     generated.Sentiment,
+		Pros:          generated.Pros,
+		Cons:          generated.Cons,
+		Quotes:        generated.Quotes,
+		Summarizer:    s.summarizer.Name(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save review summary: %w", err)
+	}
+	return nil
+}
+
+func (s *ReviewService) publishReviewCreated(ctx context.Context, userID, productID int32) error {
+	if s.pub == nil {
+		return errors.New("no event publisher")
+	}
+	return s.pub.Publish(ctx, string(notifications.NotificationTypeReviewCreated), notifications.Notification{
+		Type:      notifications.NotificationTypeReviewCreated,
+		UserID:    int64(userID),
+		ProductID: int64(productID),
+	}, map[string]string{"product_id": strconv.Itoa(int(productID))})
+}
+
+func toReviewResponse(review db.ProductReview) dto.ReviewResponse {
+	return dto.ReviewResponse{
+		ID:        uint(review.ID),        //#nosec G115 -- DB ID is always positive
+		ProductID: uint(review.ProductID), //#nosec G115 -- DB ID is always positive
+		UserID:    uint(review.UserID),    //#nosec G115 -- DB ID is always positive
+		Rating:    int(review.Rating),
+		Body:      review.Body,
+		CreatedAt: review.CreatedAt.Time,
+	}
+}
+
+func toReviewSummary(summary db.ProductReviewSummary) *dto.ReviewSummary {
+	return &dto.ReviewSummary{
+		ReviewCount:   int(summary.ReviewCount),
+		AverageRating: summary.AverageRating,
+		Sentiment:     summary.Sentiment,
+		Pros:          nonNilStrings(summary.Pros),
+		Cons:          nonNilStrings(summary.Cons),
+		Quotes:        nonNilStrings(summary.Quotes),
+		UpdatedAt:     summary.UpdatedAt.Time,
+	}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+)
+
+func TestReviewService_CreateReview(t *testing.T) {
+	t.Parallel()
+
+	req := dto.CreateReviewRequest{Rating: 4, Body: "Comfortable and sturdy."}
+	params := db.CreateProductReviewParams{ProductID: 1, UserID: 7, Rating: 4, Body: req.Body}
+	event := notifications.Notification{Type: notifications.NotificationTypeReviewCreated, UserID: 7, ProductID: 1}
+
+	tests := []struct {
+		name          string
+		setupMock     func(m *mocks.MockStore, pub *MockEventPublisher)
+		wantErr       error
+		wantRefreshed bool
+	}{
+		{
+			name: "success - summary refreshed from the event",
+			setupMock: func(m *mocks.MockStore, pub *MockEventPublisher) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				m.On("CreateProductReview", mock.Anything, params).Return(db.ProductReview{ID: 3, ProductID: 1, UserID: 7, Rating: 4, Body: req.Body}, nil)
+				pub.On("Publish", mock.Anything, "review_created", event, map[string]string{"product_id": "1"}).Return(nil)
+			},
+		},
+		{
+			name: "success - summary refreshed inline when publishing fails",
+			setupMock: func(m *mocks.MockStore, pub *MockEventPublisher) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				m.On("CreateProductReview", mock.Anything, params).Return(db.ProductReview{ID: 3, ProductID: 1, UserID: 7, Rating: 4, Body: req.Body}, nil)
+				pub.On("Publish", mock.Anything, "review_created", event, mock.Anything).Return(errors.New("queue down"))
+				m.On("GetProductReviewStats", mock.Anything, int32(1)).Return(db.GetProductReviewStatsRow{}, nil)
+			},
+			wantRefreshed: true,
+		},
+		{
+			name: "error - already reviewed",
+			setupMock: func(m *mocks.MockStore, pub *MockEventPublisher) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(createTestProduct(), nil)
+				m.On("CreateProductReview", mock.Anything, params).Return(db.ProductReview{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrReviewExists,
+		},
+		{
+			name: "error - product not found",
+			setupMock: func(m *mocks.MockStore, pub *MockEventPublisher) {
+				m.On("GetProductByID", mock.Anything, int32(1)).Return(db.Product{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStore := new(mocks.MockStore)
+			mockPublisher := new(MockEventPublisher)
+			tt.setupMock(mockStore, mockPublisher)
+
+			service := NewReviewService(mockStore, mockPublisher, providers.NewLexiconSummarizer())
+			review, err := service.CreateReview(context.Background(), 7, 1, req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, review)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, uint(3), review.ID)
+				assert.Equal(t, 4, review.Rating)
+			}
+			if !tt.wantRefreshed {
+				mockStore.AssertNotCalled(t, "GetProductReviewStats", mock.Anything, mock.Anything)
+			}
+			mockStore.AssertExpectations(t)
+			mockPublisher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReviewService_RefreshSummary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reviews summarized into pros, cons and quotes", func(t *testing.T) {
+		t.Parallel()
+
+		reviews := []db.ProductReview{
+			{Rating: 5, Body: "Love these boots! The sole is really comfortable.\nSizing runs a bit small though."},
+			{Rating: 4, Body: "Comfortable sole and sturdy laces. The zipper broke after a week."},
+			{Rating: 2, Body: "The zipper is flimsy and broke. Not comfortable at all."},
+			{Rating: 3, Body: "Arrived on time."},
+		}
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductReviewStats", mock.Anything, int32(1)).Return(db.GetProductReviewStatsRow{ReviewCount: 4, AverageRating: 3.5}, nil)
+		mockStore.On("ListProductReviews", mock.Anything, db.ListProductReviewsParams{ProductID: 1, Limit: summaryReviewLimit}).Return(reviews, nil)
+
+		var saved db.UpsertProductReviewSummaryParams
+		mockStore.On("UpsertProductReviewSummary", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { saved = args.Get(1).(db.UpsertProductReviewSummaryParams) }).
+			Return(db.ProductReviewSummary{}, nil)
+
+		service := NewReviewService(mockStore, nil, providers.NewLexiconSummarizer())
+		require.NoError(t, service.RefreshSummary(context.Background(), 1))
+
+		assert.Equal(t, int32(4), saved.ReviewCount)
+		assert.InDelta(t, 3.5, saved.AverageRating, 1e-9)
+		assert.Equal(t, "lexicon", saved.Summarizer)
+
+		// A happy review, a mixed one, an unhappy one and one with no opinion
+		// add up to slightly positive
+		assert.InDelta(t, 0.05, saved.Sentiment, 1e-9)
+		assert.Equal(t, []string{"sole", "boot", "lace"}, saved.Pros)
+		assert.Equal(t, []string{"zipper"}, saved.Cons)
+
+		// Most positive, most negative, then closest to the overall sentiment,
+		// where "not" has turned "comfortable" around
+		assert.Equal(t, []string{
+			"Comfortable sole and sturdy laces.",
+			"The zipper is flimsy and broke.",
+			"Not comfortable at all.",
+		}, saved.Quotes)
+	})
+
+	t.Run("no reviews leaves the product without a summary", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetProductReviewStats", mock.Anything, int32(1)).Return(db.GetProductReviewStatsRow{}, nil)
+
+		service := NewReviewService(mockStore, nil, providers.NewLexiconSummarizer())
+		require.NoError(t, service.RefreshSummary(context.Background(), 1))
+
+		mockStore.AssertNotCalled(t, "UpsertProductReviewSummary", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReviewService_GetSummary(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("GetProductReviewSummary", mock.Anything, int32(1)).Return(db.ProductReviewSummary{}, pgx.ErrNoRows)
+
+	service := NewReviewService(mockStore, nil, providers.NewLexiconSummarizer())
+	_, err := service.GetSummary(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrReviewSummaryNotFound)
+}
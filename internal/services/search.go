@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+var ErrInvalidAttributeFilter = errors.New("attribute filters must be written name:value")
+
+// SuggestProducts returns defaultSuggestions suggestions unless asked for
+// another number, and never more than maxSuggestions
+const (
+	defaultSuggestions = 5
+	maxSuggestions     = 20
+)
+
+// priceFacetBounds are the edges of the price facet buckets, in whole units
+// of the display currency
+var priceFacetBounds = []int64{10, 25, 50, 100, 250, 500}
+
+// searchFilters are a search's filters in the form every search query takes.
+// The queries price products as they sell now in the quoted currency, so
+// price bounds are in that currency.
+type searchFilters db.CountSearchProductsParams
+
+// newSearchFilters reads a search request's filters
+func newSearchFilters(req dto.SearchProductsRequest, quote Quote) (searchFilters, error) {
+	f := searchFilters{
+		Query:           req.Query,
+		CategoryIds:     make([]int32, len(req.CategoryIDs)),
+		AttributeNames:  make([]string, len(req.Attributes)),
+		AttributeValues: make([]string, len(req.Attributes)),
+		BaseCurrency:    quote.Base,
+		Currency:        quote.Currency,
+		Rate:            money.RateNumeric(quote.Rate),
+	}
+	for i, id := range req.CategoryIDs {
+		f.CategoryIds[i] = int32(id) //#nosec G115 -- category ID is bounded
+	}
+
+	if req.MinPrice != nil {
+		f.MinPrice = req.MinPrice.Numeric()
+	}
+	if req.MaxPrice != nil {
+		f.MaxPrice = req.MaxPrice.Numeric()
+	}
+	if req.InStock != nil {
+		f.InStock = pgtype.Bool{Bool: *req.InStock, Valid: true}
+	}
+
+	for i, attr := range req.Attributes {
+		name, value, ok := strings.Cut(attr, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return searchFilters{}, fmt.Errorf("%w: %q", ErrInvalidAttributeFilter, attr)
+		}
+		f.AttributeNames[i] = name
+		f.AttributeValues[i] = value
+	}
+	return f, nil
+}
+
+// withoutAttribute drops the filters on one attribute
+func (f searchFilters) withoutAttribute(name string) searchFilters {
+	names, values := f.AttributeNames, f.AttributeValues
+	f.AttributeNames, f.AttributeValues = []string{}, []string{}
+	for i, n := range names {
+		if n != name {
+			f.AttributeNames = append(f.AttributeNames, n)
+			f.AttributeValues = append(f.AttributeValues, values[i])
+		}
+	}
+	return f
+}
+
+// hasAttribute reports whether the search filters on an attribute value
+func (f searchFilters) hasAttribute(name, value string) bool {
+	for i, n := range f.AttributeNames {
+		if n == name && f.AttributeValues[i] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// selectedAttributes returns the filtered attribute names, in request order
+func (f searchFilters) selectedAttributes() []string {
+	var names []string
+	for _, name := range f.AttributeNames {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// searchFacets counts the products a search matches by category, price,
+// stock and attribute
+func (s *ProductService) searchFacets(ctx context.Context, f searchFilters) (dto.SearchFacets, error) {
+	facets := dto.SearchFacets{
+		Categories: []dto.CategoryFacet{},
+		Prices:     []dto.PriceFacet{},
+		Attributes: []dto.AttributeFacet{},
+	}
+
+	categories, err := s.categoryFacets(ctx, f)
+	if err != nil {
+		return dto.SearchFacets{}, err
+	}
+	facets.Categories = categories
+
+	prices, err := s.priceFacets(ctx, f)
+	if err != nil {
+		return dto.SearchFacets{}, err
+	}
+	facets.Prices = prices
+
+	withoutStock := f
+	withoutStock.InStock = pgtype.Bool{}
+	stock, err := s.store.SearchStockFacets(ctx, db.SearchStockFacetsParams(withoutStock))
+	if err != nil {
+		return dto.SearchFacets{}, fmt.Errorf("failed to count stock facets: %w", err)
+	}
+	facets.Stock = dto.StockFacet{InStock: int(stock.InStock), OutOfStock: int(stock.OutOfStock)}
+
+	attributes, err := s.attributeFacets(ctx, f)
+	if err != nil {
+		return dto.SearchFacets{}, err
+	}
+	facets.Attributes = attributes
+
+	return facets, nil
+}
+
+func (s *ProductService) categoryFacets(ctx context.Context, f searchFilters) ([]dto.CategoryFacet, error) {
+	selected := f.CategoryIds
+	f.CategoryIds = []int32{}
+	rows, err := s.store.SearchCategoryFacets(ctx, db.SearchCategoryFacetsParams(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count category facets: %w", err)
+	}
+	if len(rows) == 0 {
+		return []dto.CategoryFacet{}, nil
+	}
+
+	categoryIDs := make([]int32, len(rows))
+	for i, row := range rows {
+		categoryIDs[i] = row.CategoryID
+	}
+	categories, err := s.store.GetCategoriesByIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get facet categories: %w", err)
+	}
+	names := make(map[int32]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+
+	facets := make([]dto.CategoryFacet, len(rows))
+	for i, row := range rows {
+		facets[i] = dto.CategoryFacet{
+			ID:       uint(row.CategoryID), //#nosec G115 -- DB ID is always positive
+			Name:     names[row.CategoryID],
+			Count:    int(row.Count),
+			Selected: slices.Contains(selected, row.CategoryID),
+		}
+	}
+	return facets, nil
+}
+
+// priceFacets buckets the prices products sell for now at priceFacetBounds
+// in the quoted currency, leaving out empty buckets
+func (s *ProductService) priceFacets(ctx context.Context, f searchFilters) ([]dto.PriceFacet, error) {
+	bounds := make([]money.Money, len(priceFacetBounds))
+	numericBounds := make([]pgtype.Numeric, len(priceFacetBounds))
+	for i, units := range priceFacetBounds {
+		bounds[i] = money.FromCents(units * 100)
+		numericBounds[i] = bounds[i].Numeric()
+	}
+
+	rows, err := s.store.SearchPriceFacets(ctx, db.SearchPriceFacetsParams{
+		Bounds:          numericBounds,
+		Query:           f.Query,
+		Fuzzy:           f.Fuzzy,
+		CategoryIds:     f.CategoryIds,
+		InStock:         f.InStock,
+		AttributeNames:  f.AttributeNames,
+		AttributeValues: f.AttributeValues,
+		BaseCurrency:    f.BaseCurrency,
+		Currency:        f.Currency,
+		Rate:            f.Rate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count price facets: %w", err)
+	}
+
+	// Bucket i holds prices from bounds[i-1] up to bounds[i]
+	facets := make([]dto.PriceFacet, len(rows))
+	for i, row := range rows {
+		facets[i].Count = int(row.Count)
+		if row.Bucket > 0 {
+			facets[i].Min = &bounds[row.Bucket-1]
+		}
+		if int(row.Bucket) < len(bounds) {
+			facets[i].Max = &bounds[row.Bucket]
+		}
+	}
+	return facets, nil
+}
+
+// attributeFacets counts attribute values. Attributes the search filters on
+// are counted without their own filter, one query each; the rest are
+// counted together.
+func (s *ProductService) attributeFacets(ctx context.Context, f searchFilters) ([]dto.AttributeFacet, error) {
+	selected := f.selectedAttributes()
+
+	rows, err := s.store.SearchAttributeFacets(ctx, db.SearchAttributeFacetsParams{
+		Query:           f.Query,
+		Fuzzy:           f.Fuzzy,
+		CategoryIds:     f.CategoryIds,
+		MinPrice:        f.MinPrice,
+		MaxPrice:        f.MaxPrice,
+		InStock:         f.InStock,
+		AttributeNames:  f.AttributeNames,
+		AttributeValues: f.AttributeValues,
+		BaseCurrency:    f.BaseCurrency,
+		Currency:        f.Currency,
+		Rate:            f.Rate,
+		ExcludeNames:    append([]string{}, selected...),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attribute facets: %w", err)
+	}
+
+	for _, name := range selected {
+		without := f.withoutAttribute(name)
+		selectedRows, err := s.store.SearchAttributeFacets(ctx, db.SearchAttributeFacetsParams{
+			Query:           without.Query,
+			Fuzzy:           without.Fuzzy,
+			CategoryIds:     without.CategoryIds,
+			MinPrice:        without.MinPrice,
+			MaxPrice:        without.MaxPrice,
+			InStock:         without.InStock,
+			AttributeNames:  without.AttributeNames,
+			AttributeValues: without.AttributeValues,
+			BaseCurrency:    without.BaseCurrency,
+			Currency:        without.Currency,
+			Rate:            without.Rate,
+			FacetName:       pgtype.Text{String: name, Valid: true},
+			ExcludeNames:    []string{},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count attribute facets: %w", err)
+		}
+		rows = append(rows, selectedRows...)
+	}
+
+	// Rows come back grouped by name within each query
+	var facets []dto.AttributeFacet
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.Name]
+		if !ok {
+			i = len(facets)
+			index[row.Name] = i
+			facets = append(facets, dto.AttributeFacet{Name: row.Name})
+		}
+		facets[i].Values = append(facets[i].Values, dto.AttributeValueFacet{
+			Value:    row.Value,
+			Count:    int(row.Count),
+			Selected: f.hasAttribute(row.Name, row.Value),
+		})
+	}
+	if facets == nil {
+		return []dto.AttributeFacet{}, nil
+	}
+	slices.SortFunc(facets, func(a, b dto.AttributeFacet) int { return strings.Compare(a.Name, b.Name) })
+	return facets, nil
+}
+
+// SuggestProducts returns products to offer as a shopper types: names that
+// start with the query, then names with a word that does, then names that
+// are only similar to it
+func (s *ProductService) SuggestProducts(ctx context.Context, req dto.SuggestProductsRequest) ([]dto.ProductSuggestion, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return []dto.ProductSuggestion{}, nil
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = defaultSuggestions
+	}
+	limit = min(limit, maxSuggestions)
+
+	rows, err := s.store.SuggestProducts(ctx, db.SuggestProductsParams{
+		Pattern: escapeLike(query),
+		Prefix:  query,
+		Limit:   int32(limit), //#nosec G115 -- limit is validated
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest products: %w", err)
+	}
+
+	suggestions := make([]dto.ProductSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = dto.ProductSuggestion{
+			ID:   uint(row.ID), //#nosec G115 -- DB ID is always positive
+			Name: row.Name,
+			SKU:  row.Sku,
+		}
+	}
+	return suggestions, nil
+}
+
+// correctQuery replaces each word of a query with the most similar word in
+// the catalog's product names. It returns an empty string when no word
+// changes.
+func (s *ProductService) correctQuery(ctx context.Context, query string) (string, error) {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 || s.terms == nil {
+		return "", nil
+	}
+
+	corrections, err := s.terms.Correct(ctx, terms)
+	if err != nil {
+		return "", err
+	}
+
+	changed := false
+	for i, term := range terms {
+		if correction, ok := corrections[term]; ok && correction != term {
+			terms[i] = correction
+			changed = true
+		}
+	}
+	if !changed {
+		return "", nil
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
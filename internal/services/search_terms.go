@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+)
+
+// searchTermMinSimilarity is the trigram similarity a word in the catalog
+// needs to a search term to be offered as its correction; pg_trgm's default
+// threshold
+const searchTermMinSimilarity = 0.3
+
+// SearchTermIndex holds the words of active product names that misspelt
+// search terms are corrected to. It is built from the catalog on first use
+// and rebuilt by Refresh, so correcting a query doesn't read every product
+// name. Each replica keeps its own in memory.
+type SearchTermIndex struct {
+	store db.Store
+
+	mu    sync.RWMutex
+	terms *termDictionary
+}
+
+func NewSearchTermIndex(store db.Store) *SearchTermIndex {
+	return &SearchTermIndex{store: store}
+}
+
+// Refresh rebuilds the index from the catalog and returns how many words it
+// holds
+func (i *SearchTermIndex) Refresh(ctx context.Context) (int, error) {
+	words, err := i.store.ListSearchTermWords(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list search term words: %w", err)
+	}
+	terms := newTermDictionary(words)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.terms = terms
+	return len(words), nil
+}
+
+// Correct returns the most similar catalog word to each term, by term.
+// Terms with no similar word are left out.
+func (i *SearchTermIndex) Correct(ctx context.Context, terms []string) (map[string]string, error) {
+	i.mu.RLock()
+	dictionary := i.terms
+	i.mu.RUnlock()
+
+	// Build the first index if the scheduled refresher hasn't yet
+	if dictionary == nil {
+		if _, err := i.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		i.mu.RLock()
+		dictionary = i.terms
+		i.mu.RUnlock()
+	}
+
+	corrections := make(map[string]string, len(terms))
+	for _, term := range terms {
+		if word, ok := dictionary.closest(term); ok {
+			corrections[term] = word
+		}
+	}
+	return corrections, nil
+}
+
+// termDictionary finds the closest of a set of words to a search term the
+// way pg_trgm would: words whose word similarity to the term reaches
+// searchTermMinSimilarity, ranked by their similarity to it
+type termDictionary struct {
+	words     []string
+	trigrams  [][]string       // each word's trigrams, in order
+	byTrigram map[string][]int // indexes of the words containing each trigram
+}
+
+func newTermDictionary(words []string) *termDictionary {
+	d := &termDictionary{
+		words:     words,
+		trigrams:  make([][]string, len(words)),
+		byTrigram: make(map[string][]int),
+	}
+	for i, word := range words {
+		d.trigrams[i] = wordTrigrams(word)
+		for gram := range distinctTrigrams(d.trigrams[i]) {
+			d.byTrigram[gram] = append(d.byTrigram[gram], i)
+		}
+	}
+	return d
+}
+
+// closest returns the word most similar to term, the first in alphabetical
+// order on a tie, if any is similar enough
+func (d *termDictionary) closest(term string) (string, bool) {
+	grams := distinctTrigrams(wordTrigrams(term))
+	candidates := make(map[int]struct{})
+	for gram := range grams {
+		for _, i := range d.byTrigram[gram] {
+			candidates[i] = struct{}{}
+		}
+	}
+
+	best, bestSimilarity := -1, 0.0
+	for i := range candidates {
+		if wordSimilarity(grams, d.trigrams[i]) < searchTermMinSimilarity {
+			continue
+		}
+		similarity := trigramSimilarity(grams, distinctTrigrams(d.trigrams[i]))
+		if best < 0 || similarity > bestSimilarity ||
+			(similarity == bestSimilarity && d.words[i] < d.words[best]) {
+			best, bestSimilarity = i, similarity
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return d.words[best], true
+}
+
+// trigramSimilarity is pg_trgm's similarity: the trigrams two sets share
+// over all the trigrams either has
+func trigramSimilarity(a, b map[string]struct{}) float64 {
+	shared := 0
+	for gram := range a {
+		if _, ok := b[gram]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// wordSimilarity is pg_trgm's word_similarity: the greatest similarity
+// between the term's trigrams and any run of consecutive trigrams of the
+// word, so a term matching part of a word scores well
+func wordSimilarity(term map[string]struct{}, word []string) float64 {
+	best := 0.0
+	for start := range word {
+		extent := make(map[string]struct{})
+		for _, gram := range word[start:] {
+			extent[gram] = struct{}{}
+			best = max(best, trigramSimilarity(term, extent))
+		}
+	}
+	return best
+}
+
+// wordTrigrams returns the trigrams of a lower-cased word padded with two
+// spaces in front and one behind, in order, as pg_trgm splits words
+func wordTrigrams(word string) []string {
+	runes := []rune("  " + strings.ToLower(word) + " ")
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+func distinctTrigrams(grams []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(grams))
+	for _, gram := range grams {
+		set[gram] = struct{}{}
+	}
+	return set
+}
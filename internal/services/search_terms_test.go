@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	t.Parallel()
+
+	word := distinctTrigrams(wordTrigrams("word"))
+
+	// The values pg_trgm gives
+	assert.InDelta(t, 0.571429, trigramSimilarity(word, distinctTrigrams(wordTrigrams("words"))), 1e-6)
+	assert.InDelta(t, 1, trigramSimilarity(word, distinctTrigrams(wordTrigrams("WORD"))), 1e-6)
+	assert.InDelta(t, 0.8, wordSimilarity(distinctTrigrams(wordTrigrams("head")), wordTrigrams("headphones")), 1e-6)
+	assert.Zero(t, wordSimilarity(word, wordTrigrams("zzz")))
+}
+
+func TestSearchTermIndex_Correct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds the index once", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListSearchTermWords", mock.Anything).Return([]string{"case", "cases", "iphone"}, nil).Once()
+
+		index := NewSearchTermIndex(mockStore)
+		corrections, err := index.Correct(context.Background(), []string{"iphnoe", "casse", "zzz"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"iphnoe": "iphone", "casse": "case"}, corrections)
+
+		corrections, err = index.Correct(context.Background(), []string{"iphnoe"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"iphnoe": "iphone"}, corrections)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("refresh picks up new words", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListSearchTermWords", mock.Anything).Return([]string{"case"}, nil).Once()
+		mockStore.On("ListSearchTermWords", mock.Anything).Return([]string{"case", "iphone"}, nil).Once()
+
+		index := NewSearchTermIndex(mockStore)
+		count, err := index.Refresh(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		corrections, err := index.Correct(context.Background(), []string{"iphnoe"})
+		require.NoError(t, err)
+		assert.Empty(t, corrections)
+
+		count, err = index.Refresh(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		corrections, err = index.Correct(context.Background(), []string{"iphnoe"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"iphnoe": "iphone"}, corrections)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListSearchTermWords", mock.Anything).Return([]string(nil), errors.New("db down"))
+
+		_, err := NewSearchTermIndex(mockStore).Correct(context.Background(), []string{"iphnoe"})
+		assert.ErrorContains(t, err, "db down")
+	})
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/money"
+)
+
+func TestNewSearchFilters(t *testing.T) {
+	t.Parallel()
+
+	minPrice, maxPrice := money.FromCents(920), money.FromCents(4600)
+	inStock := true
+
+	tests := []struct {
+		name    string
+		req     dto.SearchProductsRequest
+		quote   Quote
+		want    searchFilters
+		wantErr error
+	}{
+		{
+			name:  "no filters",
+			req:   dto.SearchProductsRequest{Query: "shirt"},
+			quote: baseQuote("USD"),
+			want: searchFilters{
+				Query:           "shirt",
+				CategoryIds:     []int32{},
+				AttributeNames:  []string{},
+				AttributeValues: []string{},
+				BaseCurrency:    "USD",
+				Currency:        "USD",
+				Rate:            money.RateNumeric(big.NewRat(1, 1)),
+			},
+		},
+		{
+			name: "every filter, prices in the quoted currency",
+			req: dto.SearchProductsRequest{
+				Query:       "shirt",
+				CategoryIDs: []uint{1, 2},
+				MinPrice:    &minPrice,
+				MaxPrice:    &maxPrice,
+				InStock:     &inStock,
+				Attributes:  []string{"color:red", " size : M "},
+			},
+			quote: Quote{Base: "USD", Currency: "EUR", Rate: big.NewRat(92, 100)},
+			want: searchFilters{
+				Query:           "shirt",
+				CategoryIds:     []int32{1, 2},
+				MinPrice:        money.FromCents(920).Numeric(),
+				MaxPrice:        money.FromCents(4600).Numeric(),
+				InStock:         pgtype.Bool{Bool: true, Valid: true},
+				AttributeNames:  []string{"color", "size"},
+				AttributeValues: []string{"red", "M"},
+				BaseCurrency:    "USD",
+				Currency:        "EUR",
+				Rate:            money.RateNumeric(big.NewRat(92, 100)),
+			},
+		},
+		{
+			name:    "attribute without value",
+			req:     dto.SearchProductsRequest{Query: "shirt", Attributes: []string{"color"}},
+			quote:   baseQuote("USD"),
+			wantErr: ErrInvalidAttributeFilter,
+		},
+		{
+			name:    "attribute with empty name",
+			req:     dto.SearchProductsRequest{Query: "shirt", Attributes: []string{":red"}},
+			quote:   baseQuote("USD"),
+			wantErr: ErrInvalidAttributeFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := newSearchFilters(tt.req, tt.quote)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f)
+		})
+	}
+}
+
+// facetStoreWrapper answers attribute facet queries and records what they filtered on
+type facetStoreWrapper struct {
+	*productStoreWrapper
+	calls []db.SearchAttributeFacetsParams
+	rows  map[string][]db.SearchAttributeFacetsRow // by facet name; "" for the unselected attributes
+}
+
+func (s *facetStoreWrapper) SearchAttributeFacets(ctx context.Context, arg db.SearchAttributeFacetsParams) ([]db.SearchAttributeFacetsRow, error) {
+	s.calls = append(s.calls, arg)
+	return s.rows[arg.FacetName.String], nil
+}
+
+func TestProductService_AttributeFacets(t *testing.T) {
+	t.Parallel()
+
+	store := &facetStoreWrapper{
+		productStoreWrapper: &productStoreWrapper{MockProductStore: new(MockProductStore)},
+		rows: map[string][]db.SearchAttributeFacetsRow{
+			"": {
+				{Name: "size", Value: "M", Count: 4},
+				{Name: "size", Value: "L", Count: 2},
+			},
+			"color": {
+				{Name: "color", Value: "blue", Count: 3},
+				{Name: "color", Value: "red", Count: 5},
+			},
+		},
+	}
+	service := &ProductService{store: store}
+
+	f, err := newSearchFilters(dto.SearchProductsRequest{
+		Query:      "shirt",
+		Attributes: []string{"color:red", "color:blue", "material:cotton"},
+	}, baseQuote("USD"))
+	require.NoError(t, err)
+
+	facets, err := service.attributeFacets(context.Background(), f)
+	require.NoError(t, err)
+
+	// One query for the unselected attributes, then one per selected attribute
+	// without its own filter
+	require.Len(t, store.calls, 3)
+	assert.Equal(t, []string{"color", "material"}, store.calls[0].ExcludeNames)
+	assert.Equal(t, []string{"color", "color", "material"}, store.calls[0].AttributeNames)
+	assert.Equal(t, "color", store.calls[1].FacetName.String)
+	assert.Equal(t, []string{"material"}, store.calls[1].AttributeNames)
+	assert.Equal(t, []string{"cotton"}, store.calls[1].AttributeValues)
+	assert.Equal(t, "material", store.calls[2].FacetName.String)
+	assert.Equal(t, []string{"color", "color"}, store.calls[2].AttributeNames)
+
+	assert.Equal(t, []dto.AttributeFacet{
+		{Name: "color", Values: []dto.AttributeValueFacet{
+			{Value: "blue", Count: 3, Selected: true},
+			{Value: "red", Count: 5, Selected: true},
+		}},
+		{Name: "size", Values: []dto.AttributeValueFacet{
+			{Value: "M", Count: 4},
+			{Value: "L", Count: 2},
+		}},
+	}, facets)
+}
+
+// correctionStoreWrapper lists a fixed vocabulary of product name words
+type correctionStoreWrapper struct {
+	*productStoreWrapper
+	words []string
+}
+
+func (s *correctionStoreWrapper) ListSearchTermWords(ctx context.Context) ([]string, error) {
+	return s.words, nil
+}
+
+func TestProductService_CorrectQuery(t *testing.T) {
+	t.Parallel()
+
+	store := &correctionStoreWrapper{
+		productStoreWrapper: &productStoreWrapper{MockProductStore: new(MockProductStore)},
+		words:               []string{"case", "headphones", "iphone"},
+	}
+	service := &ProductService{store: store, terms: NewSearchTermIndex(store)}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "typo corrected", query: "iPhnoe case", want: "iphone case"},
+		{name: "partial word completed", query: "head", want: "headphones"},
+		{name: "unknown words kept", query: "iphnoe zzz", want: "iphone zzz"},
+		{name: "nothing to correct", query: "case", want: ""},
+		{name: "no words", query: "!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := service.correctQuery(context.Background(), tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "head", escapeLike("head"))
+	assert.Equal(t, `50\% off`, escapeLike("50% off"))
+	assert.Equal(t, `a\_b\\c`, escapeLike(`a_b\c`))
+}
+
+// fallbackStoreWrapper matches products only by name similarity
+type fallbackStoreWrapper struct {
+	*correctionStoreWrapper
+	products []db.SearchProductsRow
+}
+
+func (s *fallbackStoreWrapper) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
+	if !arg.Fuzzy {
+		return 0, nil
+	}
+	return int64(len(s.products)), nil
+}
+
+func (s *fallbackStoreWrapper) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
+	if !arg.Fuzzy {
+		return nil, nil
+	}
+	return s.products, nil
+}
+
+func TestProductService_SearchProducts_FuzzyFallback(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(MockProductStore)
+	mockStore.On("GetCategoriesByIDs", mock.Anything, []int32{1}).Return([]db.Category{createTestCategory()}, nil)
+	mockStore.On("ListProductImagesByProductIDs", mock.Anything, []int32{7}).Return([]db.ProductImage{}, nil)
+
+	store := &fallbackStoreWrapper{
+		correctionStoreWrapper: &correctionStoreWrapper{
+			productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore},
+			words:               []string{"iphone"},
+		},
+		products: []db.SearchProductsRow{{
+			Product: db.Product{
+				ID: 7, CategoryID: 1, Name: "iPhone 15", Price: money.FromCents(79900).Numeric(),
+				SeoTitle: pgtype.Text{String: "iPhone 15 | Phones", Valid: true},
+				Tags:     []string{"phone", "apple"},
+			},
+			Rank: 0.43,
+		}},
+	}
+	service := &ProductService{store: store, terms: NewSearchTermIndex(store)}
+
+	result, meta, err := service.SearchProducts(context.Background(), dto.SearchProductsRequest{Query: "iphnoe"}, "")
+	require.NoError(t, err)
+
+	assert.True(t, result.Fuzzy)
+	assert.Equal(t, "iphone", result.DidYouMean)
+	assert.Equal(t, 1, meta.TotalCount)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, "iPhone 15", result.Results[0].Name)
+	assert.Equal(t, "iPhone 15 | Phones", result.Results[0].SEOTitle)
+	assert.Equal(t, []string{"phone", "apple"}, result.Results[0].Tags)
+	mockStore.AssertExpectations(t)
+}
+
+// browseStoreWrapper matches every product and records the searches it got
+type browseStoreWrapper struct {
+	*productStoreWrapper
+	products []db.SearchProductsRow
+	searches []db.SearchProductsParams
+}
+
+func (s *browseStoreWrapper) CountSearchProducts(ctx context.Context, arg db.CountSearchProductsParams) (int64, error) {
+	return int64(len(s.products)), nil
+}
+
+func (s *browseStoreWrapper) SearchProducts(ctx context.Context, arg db.SearchProductsParams) ([]db.SearchProductsRow, error) {
+	s.searches = append(s.searches, arg)
+	return s.products, nil
+}
+
+func TestProductService_SearchProducts_BrowseByFilters(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(MockProductStore)
+	mockStore.On("GetCategoriesByIDs", mock.Anything, []int32{1}).Return([]db.Category{createTestCategory()}, nil)
+	mockStore.On("ListProductImagesByProductIDs", mock.Anything, []int32{7}).Return([]db.ProductImage{}, nil)
+
+	store := &browseStoreWrapper{
+		productStoreWrapper: &productStoreWrapper{MockProductStore: mockStore},
+		products:            []db.SearchProductsRow{{Product: db.Product{ID: 7, CategoryID: 1, Name: "iPhone 15", Price: money.FromCents(79900).Numeric()}}},
+	}
+	service := &ProductService{store: store}
+
+	inStock := true
+	result, meta, err := service.SearchProducts(context.Background(), dto.SearchProductsRequest{
+		CategoryIDs: []uint{1},
+		InStock:     &inStock,
+	}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, meta.TotalCount)
+	require.Len(t, result.Results, 1)
+	assert.False(t, result.Fuzzy)
+	assert.Empty(t, result.DidYouMean)
+	require.Len(t, store.searches, 1)
+	assert.Empty(t, store.searches[0].Query)
+	assert.Equal(t, []int32{1}, store.searches[0].CategoryIds)
+	assert.Equal(t, pgtype.Bool{Bool: true, Valid: true}, store.searches[0].InStock)
+}
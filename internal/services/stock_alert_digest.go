@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+)
+
+// stockAlertDigestJob locks the digest so only one notifier replica sends it
+const stockAlertDigestJob = "stock_alert_digest"
+
+// StockAlertDigest collects stock alerts so catalog admins get one email per
+// interval instead of one per stock change. Alerts wait in the database, so
+// a notifier restart doesn't lose them. A newer alert for a product replaces
+// the older one, so the digest always shows the latest state.
+type StockAlertDigest struct {
+	store db.Store
+}
+
+func NewStockAlertDigest(store db.Store) *StockAlertDigest {
+	return &StockAlertDigest{store: store}
+}
+
+// Queue stores a stock_low or stock_out alert for the next digest
+func (d *StockAlertDigest) Queue(ctx context.Context, alert notifications.Notification) error {
+	err := d.store.QueueStockAlert(ctx, db.QueueStockAlertParams{
+		ProductID:   int32(alert.ProductID), //#nosec G115 -- product IDs come from an int32 column
+		Type:        string(alert.Type),
+		Sku:         alert.SKU,
+		ProductName: alert.ProductName,
+		Stock:       int32(alert.Stock),     //#nosec G115 -- stock comes from an int32 column
+		Threshold:   int32(alert.Threshold), //#nosec G115 -- thresholds come from an int32 column
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue stock alert: %w", err)
+	}
+	return nil
+}
+
+// Flush passes the queued alerts to send and deletes them once it succeeds.
+// If send fails the alerts are kept for the next flush. When another replica
+// is flushing, Flush leaves the alerts to it. It returns how many alerts
+// were sent.
+func (d *StockAlertDigest) Flush(ctx context.Context, send func(alerts []notifications.Notification) error) (int, error) {
+	var sent int
+	err := d.store.ExecTx(ctx, func(q db.Querier) error {
+		locked, err := q.TryJobLock(ctx, stockAlertDigestJob)
+		if err != nil {
+			return fmt.Errorf("failed to lock stock alert digest: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		queued, err := q.ListStockAlerts(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list stock alerts: %w", err)
+		}
+		if len(queued) == 0 {
+			return nil
+		}
+
+		alerts := make([]notifications.Notification, len(queued))
+		productIDs := make([]int32, len(queued))
+		queuedAts := make([]pgtype.Timestamptz, len(queued))
+		for i, a := range queued {
+			alerts[i] = notifications.Notification{
+				Type:        notifications.NotificationType(a.Type),
+				ProductID:   int64(a.ProductID),
+				SKU:         a.Sku,
+				ProductName: a.ProductName,
+				Stock:       int(a.Stock),
+				Threshold:   int(a.Threshold),
+			}
+			productIDs[i] = a.ProductID
+			queuedAts[i] = a.QueuedAt
+		}
+
+		if err := send(alerts); err != nil {
+			return fmt.Errorf("failed to send stock alert digest: %w", err)
+		}
+
+		// The digest has gone out, so a failure from here only repeats it
+		err = q.DeleteSentStockAlerts(ctx, db.DeleteSentStockAlertsParams{
+			ProductIds: productIDs,
+			QueuedAts:  queuedAts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete sent stock alerts: %w", err)
+		}
+		sent = len(alerts)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+)
+
+func TestStockAlertDigest_Queue(t *testing.T) {
+	t.Parallel()
+
+	mockStore := new(mocks.MockStore)
+	mockStore.On("QueueStockAlert", mock.Anything, db.QueueStockAlertParams{
+		ProductID:   1,
+		Type:        "stock_low",
+		Sku:         "TEST-001",
+		ProductName: "Test Product",
+		Stock:       3,
+		Threshold:   5,
+	}).Return(nil)
+
+	digest := NewStockAlertDigest(mockStore)
+
+	err := digest.Queue(context.Background(), notifications.Notification{
+		Type:        notifications.NotificationTypeStockLow,
+		ProductID:   1,
+		SKU:         "TEST-001",
+		ProductName: "Test Product",
+		Stock:       3,
+		Threshold:   5,
+	})
+
+	require.NoError(t, err)
+	mockStore.AssertExpectations(t)
+}
+
+func TestStockAlertDigest_Flush(t *testing.T) {
+	t.Parallel()
+
+	queuedAt := pgtype.Timestamptz{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true}
+	queued := []db.StockAlert{
+		{ProductID: 1, Type: "stock_low", Sku: "TEST-001", ProductName: "Lamp", Stock: 3, Threshold: 5, QueuedAt: queuedAt},
+		{ProductID: 2, Type: "stock_out", Sku: "TEST-002", ProductName: "Desk", Stock: 0, Threshold: 5, QueuedAt: queuedAt},
+	}
+
+	t.Run("success - deletes the sent alerts", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("TryJobLock", mock.Anything, "stock_alert_digest").Return(true, nil)
+		mockStore.On("ListStockAlerts", mock.Anything).Return(queued, nil)
+		mockStore.On("DeleteSentStockAlerts", mock.Anything, db.DeleteSentStockAlertsParams{
+			ProductIds: []int32{1, 2},
+			QueuedAts:  []pgtype.Timestamptz{queuedAt, queuedAt},
+		}).Return(nil)
+
+		digest := NewStockAlertDigest(mockStore)
+
+		var got []notifications.Notification
+		sent, err := digest.Flush(context.Background(), func(alerts []notifications.Notification) error {
+			got = alerts
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		require.Len(t, got, 2)
+		assert.Equal(t, notifications.NotificationTypeStockLow, got[0].Type)
+		assert.Equal(t, "TEST-001", got[0].SKU)
+		assert.Equal(t, notifications.NotificationTypeStockOut, got[1].Type)
+		assert.Equal(t, int64(2), got[1].ProductID)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("error - failed send keeps the alerts", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("TryJobLock", mock.Anything, "stock_alert_digest").Return(true, nil)
+		mockStore.On("ListStockAlerts", mock.Anything).Return(queued, nil)
+
+		digest := NewStockAlertDigest(mockStore)
+
+		sent, err := digest.Flush(context.Background(), func([]notifications.Notification) error {
+			return errors.New("smtp unavailable")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, sent)
+		mockStore.AssertNotCalled(t, "DeleteSentStockAlerts", mock.Anything, mock.Anything)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("success - nothing queued", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("TryJobLock", mock.Anything, "stock_alert_digest").Return(true, nil)
+		mockStore.On("ListStockAlerts", mock.Anything).Return([]db.StockAlert{}, nil)
+
+		digest := NewStockAlertDigest(mockStore)
+
+		sent, err := digest.Flush(context.Background(), func([]notifications.Notification) error {
+			t.Fatal("send called with no alerts")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("success - another replica is flushing", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("TryJobLock", mock.Anything, "stock_alert_digest").Return(false, nil)
+
+		digest := NewStockAlertDigest(mockStore)
+
+		sent, err := digest.Flush(context.Background(), func([]notifications.Notification) error {
+			t.Fatal("send called without the lock")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		mockStore.AssertNotCalled(t, "ListStockAlerts", mock.Anything)
+		mockStore.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/events"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+)
+
+// StockAlerter publishes stock_low and stock_out events when committed stock
+// movements take a product across its reorder threshold, and back_in_stock
+// when they bring an out-of-stock product back
+type StockAlerter struct {
+	store            db.Store
+	pub              events.EventPublisher
+	defaultThreshold int32
+}
+
+func NewStockAlerter(store db.Store, pub events.EventPublisher, defaultThreshold int32) *StockAlerter {
+	return &StockAlerter{
+		store:            store,
+		pub:              pub,
+		defaultThreshold: defaultThreshold,
+	}
+}
+
+// Notify publishes an event for every movement that crossed a threshold.
+// Call it after the movements are committed; the stock has already changed,
+// so a failure here should not fail the caller. A nil alerter does nothing.
+func (a *StockAlerter) Notify(ctx context.Context, movements ...db.InventoryMovement) error {
+	if a == nil || a.pub == nil {
+		return nil
+	}
+
+	var productIDs []int32
+	seen := make(map[int32]struct{})
+	for _, m := range movements {
+		if _, ok := seen[m.ProductID]; mayAlert(m) && !ok {
+			seen[m.ProductID] = struct{}{}
+			productIDs = append(productIDs, m.ProductID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	rows, err := a.store.GetReorderThresholdsByProductIDs(ctx, db.GetReorderThresholdsByProductIDsParams{
+		DefaultThreshold: a.defaultThreshold,
+		ProductIds:       productIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get reorder thresholds: %w", err)
+	}
+
+	thresholds := make(map[int32]db.GetReorderThresholdsByProductIDsRow, len(rows))
+	for _, row := range rows {
+		thresholds[row.ID] = row
+	}
+
+	var errs []error
+	for _, m := range movements {
+		product, ok := thresholds[m.ProductID]
+		if !ok || !mayAlert(m) {
+			continue
+		}
+
+		alert := stockAlertType(m.StockAfter-m.QuantityChange, m.StockAfter, product.Threshold)
+		if alert == "" {
+			continue
+		}
+
+		err := a.pub.Publish(ctx, string(alert), notifications.Notification{
+			Type:        alert,
+			ProductID:   int64(product.ID),
+			SKU:         product.Sku,
+			ProductName: product.Name,
+			Stock:       int(m.StockAfter),
+			Threshold:   int(product.Threshold),
+		}, map[string]string{"product_id": strconv.Itoa(int(product.ID))})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to publish %s for product %d: %w", alert, product.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// mayAlert reports whether a movement can raise an alert: any decrease, or an
+// increase that takes the product from zero back into stock
+func mayAlert(m db.InventoryMovement) bool {
+	return m.QuantityChange < 0 || (m.StockAfter-m.QuantityChange <= 0 && m.StockAfter > 0)
+}
+
+// stockAlertType reports which alert, if any, a stock change from before to
+// after raises. Only the change that crosses the line alerts, so a product
+// already below its threshold does not alert again on every sale.
+func stockAlertType(before, after, threshold int32) notifications.NotificationType {
+	switch {
+	case before <= 0 && after > 0:
+		return notifications.NotificationTypeBackInStock
+	case after <= 0 && before > 0:
+		return notifications.NotificationTypeStockOut
+	case after < threshold && before >= threshold:
+		return notifications.NotificationTypeStockLow
+	default:
+		return ""
+	}
+}
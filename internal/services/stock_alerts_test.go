@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/notifications"
+)
+
+func TestStockAlertType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		before    int32
+		after     int32
+		threshold int32
+		want      notifications.NotificationType
+	}{
+		{name: "crosses threshold", before: 10, after: 4, threshold: 5, want: notifications.NotificationTypeStockLow},
+		{name: "lands on threshold", before: 10, after: 5, threshold: 5, want: ""},
+		{name: "already below threshold", before: 4, after: 3, threshold: 5, want: ""},
+		{name: "sells out", before: 3, after: 0, threshold: 5, want: notifications.NotificationTypeStockOut},
+		{name: "sells out past threshold", before: 10, after: 0, threshold: 5, want: notifications.NotificationTypeStockOut},
+		{name: "sells out with no threshold", before: 1, after: 0, threshold: 0, want: notifications.NotificationTypeStockOut},
+		{name: "stays above threshold", before: 10, after: 8, threshold: 5, want: ""},
+		{name: "already out", before: 0, after: 0, threshold: 5, want: ""},
+		{name: "restocked from zero", before: 0, after: 3, threshold: 5, want: notifications.NotificationTypeBackInStock},
+		{name: "restocked while in stock", before: 2, after: 6, threshold: 5, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, stockAlertType(tt.before, tt.after, tt.threshold))
+		})
+	}
+}
+
+func TestStockAlerter_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - publishes crossings only", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetReorderThresholdsByProductIDs", mock.Anything, db.GetReorderThresholdsByProductIDsParams{
+			DefaultThreshold: 2,
+			ProductIds:       []int32{1, 2},
+		}).Return([]db.GetReorderThresholdsByProductIDsRow{
+			{ID: 1, Sku: "TEST-001", Name: "Test Product", Threshold: 5},
+			{ID: 2, Sku: "TEST-002", Name: "Other Product", Threshold: 2},
+		}, nil)
+
+		pub := new(MockEventPublisher)
+		pub.On("Publish", mock.Anything, "stock_low", notifications.Notification{
+			Type:        notifications.NotificationTypeStockLow,
+			ProductID:   1,
+			SKU:         "TEST-001",
+			ProductName: "Test Product",
+			Stock:       4,
+			Threshold:   5,
+		}, map[string]string{"product_id": "1"}).Return(nil)
+
+		alerter := NewStockAlerter(mockStore, pub, 2)
+
+		err := alerter.Notify(context.Background(),
+			db.InventoryMovement{ProductID: 1, QuantityChange: -3, StockAfter: 4},
+			db.InventoryMovement{ProductID: 2, QuantityChange: -1, StockAfter: 8},
+			db.InventoryMovement{ProductID: 3, QuantityChange: 5, StockAfter: 9},
+		)
+
+		require.NoError(t, err)
+		mockStore.AssertExpectations(t)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("success - publishes back in stock", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetReorderThresholdsByProductIDs", mock.Anything, db.GetReorderThresholdsByProductIDsParams{
+			DefaultThreshold: 0,
+			ProductIds:       []int32{1},
+		}).Return([]db.GetReorderThresholdsByProductIDsRow{
+			{ID: 1, Sku: "TEST-001", Name: "Test Product", Threshold: 5},
+		}, nil)
+
+		pub := new(MockEventPublisher)
+		pub.On("Publish", mock.Anything, "back_in_stock", mock.MatchedBy(func(n notifications.Notification) bool {
+			return n.Type == notifications.NotificationTypeBackInStock && n.ProductID == 1 && n.Stock == 3
+		}), mock.Anything).Return(nil)
+
+		alerter := NewStockAlerter(mockStore, pub, 0)
+
+		err := alerter.Notify(context.Background(), db.InventoryMovement{ProductID: 1, QuantityChange: 3, StockAfter: 3})
+
+		require.NoError(t, err)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("success - restocks of in-stock products are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		pub := new(MockEventPublisher)
+
+		alerter := NewStockAlerter(mockStore, pub, 0)
+
+		err := alerter.Notify(context.Background(), db.InventoryMovement{ProductID: 1, QuantityChange: 5, StockAfter: 7})
+
+		require.NoError(t, err)
+		mockStore.AssertNotCalled(t, "GetReorderThresholdsByProductIDs", mock.Anything, mock.Anything)
+		pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - publish failure is reported", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("GetReorderThresholdsByProductIDs", mock.Anything, mock.Anything).Return([]db.GetReorderThresholdsByProductIDsRow{
+			{ID: 1, Sku: "TEST-001", Name: "Test Product", Threshold: 5},
+		}, nil)
+
+		pub := new(MockEventPublisher)
+		pub.On("Publish", mock.Anything, "stock_out", mock.Anything, mock.Anything).Return(errors.New("queue unavailable"))
+
+		alerter := NewStockAlerter(mockStore, pub, 0)
+
+		err := alerter.Notify(context.Background(), db.InventoryMovement{ProductID: 1, QuantityChange: -2, StockAfter: 0})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("success - nil alerter does nothing", func(t *testing.T) {
+		t.Parallel()
+
+		var alerter *StockAlerter
+
+		assert.NoError(t, alerter.Notify(context.Background(), db.InventoryMovement{ProductID: 1, QuantityChange: -1}))
+	})
+}
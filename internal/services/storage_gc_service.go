@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
+)
+
+// StorageGCService deletes stored files that no product image or pending
+// upload uses, such as those of deleted images and products, or of uploads
+// that failed part way. A file is stored before the image that uses it is
+// saved, and images with the same content share files, so a file is only
+// deleted once it, and any image that used it, is older than the grace
+// period.
+type StorageGCService struct {
+	store    db.Store
+	provider interfaces.Upload
+	grace    time.Duration
+	now      func() time.Time
+}
+
+// storageGCPrefixes are where image files are stored: content addressed
+// images, presigned uploads, and images stored by product before that. The
+// bucket may hold other files, which are left alone.
+var storageGCPrefixes = []string{"images/", "incoming/", "products/"}
+
+// defaultStorageGCGrace is used when no grace period is set, since without
+// one files could be deleted before the image that uses them is saved
+const defaultStorageGCGrace = 72 * time.Hour
+
+func NewStorageGCService(store db.Store, provider interfaces.Upload, grace time.Duration) *StorageGCService {
+	if grace <= 0 {
+		grace = defaultStorageGCGrace
+	}
+	return &StorageGCService{
+		store:    store,
+		provider: provider,
+		grace:    grace,
+		now:      time.Now,
+	}
+}
+
+// CollectGarbage compares the stored files against the images and uploads
+// that use them, and reports the orphans. Unless dryRun, it deletes them,
+// and forgets the content hashes no image has used since the grace period
+// began.
+func (s *StorageGCService) CollectGarbage(ctx context.Context, dryRun bool) (*dto.StorageGCReport, error) {
+	cutoff := s.now().Add(-s.grace)
+	report := &dto.StorageGCReport{
+		DryRun:  dryRun,
+		Cutoff:  cutoff,
+		Orphans: []dto.OrphanedFile{},
+	}
+
+	// List the files before what uses them, so a file stored in between is
+	// either left out or seen as used
+	var files []dto.FileInfo
+	for _, prefix := range storageGCPrefixes {
+		listed, err := s.provider.List(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stored files: %w", err)
+		}
+		files = append(files, listed...)
+	}
+	paths, err := s.store.ListReferencedStoragePaths(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referenced files: %w", err)
+	}
+	referenced := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		referenced[path] = true
+	}
+
+	report.Scanned = len(files)
+	for _, file := range files {
+		switch {
+		case referenced[file.Path]:
+			report.Referenced++
+		case !file.ModTime.Before(cutoff):
+			report.InGrace++
+		default:
+			report.Orphans = append(report.Orphans, dto.OrphanedFile{Path: file.Path, Size: file.Size, ModTime: file.ModTime})
+			report.OrphanBytes += file.Size
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, orphan := range report.Orphans {
+		if err := s.provider.Delete(ctx, orphan.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to delete orphaned file %s: %w", orphan.Path, err)
+		}
+		report.Deleted++
+	}
+
+	forgotten, err := s.store.DeleteReleasedImageBlobs(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to forget unused content hashes: %w", err)
+	}
+	report.ForgottenBlobs = forgotten
+	return report, nil
+}
+
+// DeleteReleasedFiles deletes the files of content hashes whose last image
+// was just deleted, instead of leaving them for the next collection. Each
+// hash is locked and checked again first, so one an image has started using
+// since keeps its files, as do files stored again after it was released.
+func (s *StorageGCService) DeleteReleasedFiles(ctx context.Context, contentHashes []string) error {
+	for _, hash := range contentHashes {
+		err := s.store.ExecTx(ctx, func(q db.Querier) error {
+			blob, err := q.GetImageBlobForUpdate(ctx, hash)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil
+				}
+				return fmt.Errorf("failed to lock content hash: %w", err)
+			}
+			if blob.RefCount > 0 {
+				return nil
+			}
+
+			files, err := s.provider.List(ctx, imageStoragePath(hash))
+			if err != nil {
+				return fmt.Errorf("failed to list stored files: %w", err)
+			}
+			for _, file := range files {
+				if file.ModTime.After(blob.UpdatedAt.Time) {
+					continue
+				}
+				if err := s.provider.Delete(ctx, file.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("failed to delete released file %s: %w", file.Path, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
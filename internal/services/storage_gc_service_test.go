@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
+)
+
+// storedProvider returns a provider holding a small file at each path
+func storedProvider(t *testing.T, paths ...string) *providers.MemoryUploadProvider {
+	t.Helper()
+
+	provider := providers.NewMemoryUploadProvider()
+	for _, path := range paths {
+		_, err := provider.Put(context.Background(), path, bytes.NewReader([]byte("data")), dto.FileMetadata{ContentType: "image/png", Size: 4})
+		require.NoError(t, err)
+	}
+	return provider
+}
+
+func storedPaths(t *testing.T, provider *providers.MemoryUploadProvider) []string {
+	t.Helper()
+
+	files, err := provider.List(context.Background(), "")
+	require.NoError(t, err)
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func TestStorageGCService_CollectGarbage(t *testing.T) {
+	t.Parallel()
+
+	// Far enough ahead that every file stored by the test is past the grace period
+	later := time.Now().Add(100 * time.Hour)
+
+	t.Run("deletes unreferenced files and forgets released hashes", func(t *testing.T) {
+		t.Parallel()
+
+		provider := storedProvider(t, "images/ab/abc.png", "images/ab/abc_thumbnail.png", "images/cd/cde.png")
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListReferencedStoragePaths", mock.Anything, mock.Anything).Return([]string{"images/ab/abc.png", "images/ab/abc_thumbnail.png"}, nil)
+		mockStore.On("DeleteReleasedImageBlobs", mock.Anything, mock.Anything).Return(int64(1), nil)
+
+		service := NewStorageGCService(mockStore, provider, 72*time.Hour)
+		service.now = func() time.Time { return later }
+		report, err := service.CollectGarbage(context.Background(), false)
+		require.NoError(t, err)
+
+		assert.False(t, report.DryRun)
+		assert.Equal(t, later.Add(-72*time.Hour), report.Cutoff)
+		assert.Equal(t, 3, report.Scanned)
+		assert.Equal(t, 2, report.Referenced)
+		require.Len(t, report.Orphans, 1)
+		assert.Equal(t, "images/cd/cde.png", report.Orphans[0].Path)
+		assert.Equal(t, int64(4), report.OrphanBytes)
+		assert.Equal(t, 1, report.Deleted)
+		assert.Equal(t, int64(1), report.ForgottenBlobs)
+		assert.Equal(t, []string{"images/ab/abc.png", "images/ab/abc_thumbnail.png"}, storedPaths(t, provider))
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("only looks at image files", func(t *testing.T) {
+		t.Parallel()
+
+		provider := storedProvider(t, "images/ab/abc.png", "incoming/products/1/u1", "products/1/old.png", "backups/db.sql", "robots.txt")
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListReferencedStoragePaths", mock.Anything, mock.Anything).Return([]string{}, nil)
+		mockStore.On("DeleteReleasedImageBlobs", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		service := NewStorageGCService(mockStore, provider, 72*time.Hour)
+		service.now = func() time.Time { return later }
+		report, err := service.CollectGarbage(context.Background(), false)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, report.Scanned)
+		assert.Equal(t, 3, report.Deleted)
+		assert.Equal(t, []string{"backups/db.sql", "robots.txt"}, storedPaths(t, provider))
+	})
+
+	t.Run("dry run only reports", func(t *testing.T) {
+		t.Parallel()
+
+		provider := storedProvider(t, "images/ab/abc.png", "images/cd/cde.png")
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListReferencedStoragePaths", mock.Anything, mock.Anything).Return([]string{}, nil)
+
+		service := NewStorageGCService(mockStore, provider, 72*time.Hour)
+		service.now = func() time.Time { return later }
+		report, err := service.CollectGarbage(context.Background(), true)
+		require.NoError(t, err)
+
+		assert.True(t, report.DryRun)
+		assert.Len(t, report.Orphans, 2)
+		assert.Equal(t, int64(8), report.OrphanBytes)
+		assert.Zero(t, report.Deleted)
+		assert.Len(t, storedPaths(t, provider), 2)
+		mockStore.AssertNotCalled(t, "DeleteReleasedImageBlobs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("keeps files stored within the grace period", func(t *testing.T) {
+		t.Parallel()
+
+		provider := storedProvider(t, "images/ab/abc.png")
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListReferencedStoragePaths", mock.Anything, mock.Anything).Return([]string{}, nil)
+		mockStore.On("DeleteReleasedImageBlobs", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		service := NewStorageGCService(mockStore, provider, 0)
+		report, err := service.CollectGarbage(context.Background(), false)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, report.InGrace)
+		assert.Empty(t, report.Orphans)
+		assert.Len(t, storedPaths(t, provider), 1)
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		t.Parallel()
+
+		mockProvider := new(MockUploadProvider)
+		mockProvider.On("List", mock.Anything, "images/").Return([]dto.FileInfo{{Path: "images/ab/abc.png", Size: 4}}, nil)
+		mockProvider.On("List", mock.Anything, mock.Anything).Return([]dto.FileInfo{}, nil)
+		mockProvider.On("Delete", mock.Anything, "images/ab/abc.png").Return(errors.New("access denied"))
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListReferencedStoragePaths", mock.Anything, mock.Anything).Return([]string{}, nil)
+
+		service := NewStorageGCService(mockStore, mockProvider, 72*time.Hour)
+		_, err := service.CollectGarbage(context.Background(), false)
+		assert.ErrorContains(t, err, "access denied")
+		mockStore.AssertNotCalled(t, "DeleteReleasedImageBlobs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListReferencedStoragePaths", mock.Anything, mock.Anything).Return([]string(nil), errors.New("db down"))
+
+		service := NewStorageGCService(mockStore, providers.NewMemoryUploadProvider(), 72*time.Hour)
+		_, err := service.CollectGarbage(context.Background(), true)
+		assert.ErrorContains(t, err, "db down")
+	})
+}
+
+func TestStorageGCService_DeleteReleasedFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keeps files stored again after the release", func(t *testing.T) {
+		t.Parallel()
+
+		provider := storedProvider(t, "images/ab/abc.png", "images/ab/abc_thumbnail.png")
+
+		// Released before the files above were stored by a new upload
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("GetImageBlobForUpdate", mock.Anything, "abc").Return(db.ImageBlob{
+			ContentHash: "abc",
+			UpdatedAt:   pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		}, nil)
+
+		service := NewStorageGCService(mockStore, provider, 0)
+		require.NoError(t, service.DeleteReleasedFiles(context.Background(), []string{"abc"}))
+		assert.Len(t, storedPaths(t, provider), 2)
+	})
+
+	t.Run("forgotten hash", func(t *testing.T) {
+		t.Parallel()
+
+		provider := storedProvider(t, "images/ab/abc.png")
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ExecTx", mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("GetImageBlobForUpdate", mock.Anything, "abc").Return(db.ImageBlob{}, pgx.ErrNoRows)
+
+		service := NewStorageGCService(mockStore, provider, 0)
+		require.NoError(t, service.DeleteReleasedFiles(context.Background(), []string{"abc"}))
+		assert.Len(t, storedPaths(t, provider), 1)
+	})
+}
@@ -1,41 +1,282 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"mime/multipart"
-	"path/filepath"
-	"slices"
-	"strings"
+	"net/http"
 
-	"github.com/google/uuid"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/imaging"
 	"github.com/trenchesdeveloper/go-ai-store/internal/interfaces"
 )
 
+var (
+	ErrUnsupportedImage = errors.New("file is not a JPEG, PNG, GIF or WebP image")
+	ErrImageTooLarge    = errors.New("image file is too large")
+	ErrImageDimensions  = errors.New("image dimensions are out of range")
+)
+
+// imageFormats maps sniffed content types to the format names image.Decode
+// reports, and the extension each is stored with
+var imageFormats = map[string]struct{ format, ext string }{
+	"image/jpeg": {"jpeg", ".jpg"},
+	"image/png":  {"png", ".png"},
+	"image/gif":  {"gif", ".gif"},
+	"image/webp": {"webp", ".webp"},
+}
+
+// imageVariantWidths are the widths uploaded images are resized to. Images
+// narrower than a width keep their own.
+var imageVariantWidths = []struct {
+	name  string
+	width int
+}{
+	{"thumbnail", 150},
+	{"medium", 600},
+	{"large", 1200},
+}
+
+const jpegQuality = 85
+
+// ImageLimits bounds the images StoreProductImage accepts
+type ImageLimits struct {
+	MaxBytes     int64
+	MinDimension int // shortest side, in pixels
+	MaxDimension int // longest side, in pixels
+}
+
+// UploadService stores uploaded images under keys made from the SHA-256 of
+// the file, so a file uploaded again reuses what was stored for it while
+// an image still uses it
 type UploadService struct {
+	store    db.Store
 	provider interfaces.Upload
+	limits   ImageLimits
 }
 
-func NewUploadService(provider interfaces.Upload) *UploadService {
+func NewUploadService(store db.Store, provider interfaces.Upload, limits ImageLimits) *UploadService {
 	return &UploadService{
+		store:    store,
 		provider: provider,
+		limits:   limits,
+	}
+}
+
+// encodedImage is one file to store for an uploaded image
+type encodedImage struct {
+	variant     dto.ProductImageVariant
+	data        []byte
+	contentType string
+	ext         string
+}
+
+// UploadProductImage checks that a file uploaded in a multipart form really
+// is an image of an accepted size, then stores it like StoreProductImage
+func (s *UploadService) UploadProductImage(ctx context.Context, file *multipart.FileHeader) (*dto.UploadedImage, error) {
+	if file.Size > s.limits.MaxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	source, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
+	defer func() { _ = source.Close() }()
+
+	return s.StoreProductImage(ctx, source)
 }
 
-func (s *UploadService) UploadProductImage(productID uint, file *multipart.FileHeader) (string, error) {
-	ext := strings.ToLower(filepath.Ext(file.Filename))
+// StoreProductImage checks that what r reads really is an image of an
+// accepted size, then stores it without its metadata along with resized
+// variants, each in the original format and as WebP. JPEGs are turned
+// upright according to their EXIF orientation first. A file an image
+// already uses is not stored again.
+func (s *UploadService) StoreProductImage(ctx context.Context, r io.Reader) (*dto.UploadedImage, error) {
+	// Decoding needs the whole image, so read it, up to the size limit
+	data, err := io.ReadAll(io.LimitReader(r, s.limits.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > s.limits.MaxBytes {
+		return nil, ErrImageTooLarge
+	}
 
-	if !isValidImageFormat(ext) {
-		return "", fmt.Errorf("invalid image format: %s", ext)
+	contentType := http.DetectContentType(data)
+	kind, ok := imageFormats[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: found %s", ErrUnsupportedImage, contentType)
 	}
 
-	newFileName := uuid.New().String()
+	// Check the dimensions before decoding, which allocates for every pixel
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != kind.format {
+		return nil, ErrUnsupportedImage
+	}
+	if min(config.Width, config.Height) < s.limits.MinDimension || max(config.Width, config.Height) > s.limits.MaxDimension {
+		return nil, fmt.Errorf("%w: %dx%d, sides must be between %d and %d pixels",
+			ErrImageDimensions, config.Width, config.Height, s.limits.MinDimension, s.limits.MaxDimension)
+	}
 
-	path := fmt.Sprintf("products/%d/%s%s", productID, newFileName, ext)
+	// An image with the same content is already stored, so skip the
+	// decoding and encoding and reuse its files
+	sum := sha256.Sum256(data)
+	contentHash := hex.EncodeToString(sum[:])
+	stored, err := s.store.ListImageBlobVariants(ctx, contentHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up stored image: %w", err)
+	}
+	if len(stored) > 0 {
+		return storedImage(contentHash, stored), nil
+	}
 
-	return s.provider.UploadFile(file, path)
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedImage, err)
+	}
+
+	files, err := encodeImageVariants(data, img, kind.format, contentType, kind.ext)
+	if err != nil {
+		return nil, err
+	}
+
+	base := imageStoragePath(contentHash)
+	uploaded := &dto.UploadedImage{ContentHash: contentHash}
+	for _, f := range files {
+		path := base + f.ext
+		if f.variant.Name != "original" {
+			path = fmt.Sprintf("%s_%s%s", base, f.variant.Name, f.ext)
+		}
+
+		url, err := s.provider.Put(ctx, path, bytes.NewReader(f.data), dto.FileMetadata{
+			ContentType: f.contentType,
+			Size:        int64(len(f.data)),
+		})
+		if err != nil {
+			// Files stored so far are left to the storage garbage
+			// collector, since an image uploaded at the same time may
+			// use them
+			return nil, err
+		}
+
+		f.variant.URL = url
+		uploaded.Variants = append(uploaded.Variants, dto.UploadedImageVariant{ProductImageVariant: f.variant, StoragePath: path})
+		if f.variant.Name == "original" {
+			uploaded.URL = url
+			uploaded.StoragePath = path
+		}
+	}
+	return uploaded, nil
 }
 
-func isValidImageFormat(ext string) bool {
-	validFormats := []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}
-	return slices.Contains(validFormats, ext)
+// imageStoragePath returns the path an image's files are stored under,
+// before their variant name and extension. Spreading them over directories
+// by the first byte of their hash keeps directories small on disk.
+func imageStoragePath(contentHash string) string {
+	return fmt.Sprintf("images/%s/%s", contentHash[:2], contentHash)
+}
+
+// storedImage rebuilds an image already stored from its variants
+func storedImage(contentHash string, variants []db.ProductImageVariant) *dto.UploadedImage {
+	uploaded := &dto.UploadedImage{ContentHash: contentHash}
+	for _, v := range variants {
+		variant := dto.ProductImageVariant{
+			Name:   v.Name,
+			Format: v.Format,
+			URL:    v.Url,
+			Width:  int(v.Width),
+			Height: int(v.Height),
+		}
+		uploaded.Variants = append(uploaded.Variants, dto.UploadedImageVariant{ProductImageVariant: variant, StoragePath: v.StoragePath})
+		if v.Name == "original" {
+			uploaded.URL = v.Url
+			uploaded.StoragePath = v.StoragePath
+		}
+	}
+	return uploaded
+}
+
+// encodeImageVariants returns the files to store for an image: the original,
+// then each resized variant in the original format and as WebP. Resized
+// JPEGs stay JPEGs; everything else is resized to PNG to keep transparency.
+// The WebP is lossless, so it is left out when it isn't smaller than the
+// JPEG or PNG beside it, as it usually isn't for photos.
+func encodeImageVariants(data []byte, img image.Image, format, contentType, ext string) ([]encodedImage, error) {
+	original := data
+	if format == "jpeg" {
+		if orientation := imaging.JPEGOrientation(data); orientation != 1 {
+			img = imaging.Orient(img, orientation)
+			// Stripping the orientation would show the original sideways
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+				return nil, fmt.Errorf("failed to encode image: %w", err)
+			}
+			original = buf.Bytes()
+		}
+	}
+	original, err := imaging.StripMetadata(original, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedImage, err)
+	}
+
+	bounds := img.Bounds()
+	files := []encodedImage{{
+		variant: dto.ProductImageVariant{
+			Name:   "original",
+			Format: format,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		},
+		data:        original,
+		contentType: contentType,
+		ext:         ext,
+	}}
+
+	resizedFormat, resizedType, resizedExt := "png", "image/png", ".png"
+	if format == "jpeg" {
+		resizedFormat, resizedType, resizedExt = format, contentType, ext
+	}
+
+	for _, size := range imageVariantWidths {
+		resized := imaging.Resize(img, size.width)
+		variant := dto.ProductImageVariant{
+			Name:   size.name,
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+		}
+
+		var buf bytes.Buffer
+		if err := encodeResized(&buf, resized, resizedFormat); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		variant.Format = resizedFormat
+		files = append(files, encodedImage{variant: variant, data: buf.Bytes(), contentType: resizedType, ext: resizedExt})
+
+		var webp bytes.Buffer
+		if err := imaging.EncodeWebP(&webp, resized); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		if webp.Len() >= buf.Len() {
+			continue
+		}
+		variant.Format = "webp"
+		files = append(files, encodedImage{variant: variant, data: webp.Bytes(), contentType: "image/webp", ext: ".webp"})
+	}
+	return files, nil
+}
+
+func encodeResized(w io.Writer, img image.Image, format string) error {
+	if format == "jpeg" {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	return png.Encode(w, img)
 }
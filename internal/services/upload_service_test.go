@@ -1,11 +1,30 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"mime/multipart"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trenchesdeveloper/go-ai-store/db/mocks"
+	db "github.com/trenchesdeveloper/go-ai-store/db/sqlc"
+	"github.com/trenchesdeveloper/go-ai-store/internal/dto"
+	"github.com/trenchesdeveloper/go-ai-store/internal/imaging"
+	"github.com/trenchesdeveloper/go-ai-store/internal/providers"
 )
 
 // MockUploadProvider mocks the Upload interface
@@ -13,69 +32,312 @@ type MockUploadProvider struct {
 	mock.Mock
 }
 
-func (m *MockUploadProvider) UploadFile(file *multipart.FileHeader, path string) (string, error) {
-	args := m.Called(file, path)
+func (m *MockUploadProvider) Put(ctx context.Context, path string, r io.Reader, meta dto.FileMetadata) (string, error) {
+	args := m.Called(ctx, path, r, meta)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockUploadProvider) DeleteFile(path string) error {
-	args := m.Called(path)
+func (m *MockUploadProvider) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	args := m.Called(ctx, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockUploadProvider) Stat(ctx context.Context, path string) (*dto.FileInfo, error) {
+	args := m.Called(ctx, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FileInfo), args.Error(1)
+}
+
+func (m *MockUploadProvider) List(ctx context.Context, prefix string) ([]dto.FileInfo, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.FileInfo), args.Error(1)
+}
+
+func (m *MockUploadProvider) Delete(ctx context.Context, path string) error {
+	args := m.Called(ctx, path)
 	return args.Error(0)
 }
 
+func (m *MockUploadProvider) PresignUpload(ctx context.Context, method, path, contentType string, maxBytes int64, expires time.Duration) (*dto.PresignedUpload, error) {
+	args := m.Called(ctx, method, path, contentType, maxBytes, expires)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PresignedUpload), args.Error(1)
+}
+
+var testImageLimits = ImageLimits{MaxBytes: 1 << 20, MinDimension: 50, MaxDimension: 1000}
+
+// formFile returns content as a file uploaded in a multipart form
+func formFile(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["image"][0]
+}
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 90, A: 0xff}) //#nosec G115 -- wraps on purpose
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment with an orientation into a JPEG
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x12, 0x01, 3, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint16(tiff[18:], orientation)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2)) //#nosec G115 -- small test fixture
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+// unusedContentStore returns a store in which no image uses any content
+// hash yet
+func unusedContentStore() *mocks.MockStore {
+	m := new(mocks.MockStore)
+	m.On("ListImageBlobVariants", mock.Anything, mock.Anything).Return([]db.ProductImageVariant{}, nil)
+	return m
+}
+
+// storedData returns the file stored under path
+func storedData(t *testing.T, provider *providers.MemoryUploadProvider, path string) []byte {
+	t.Helper()
+
+	file, err := provider.Get(context.Background(), path)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return data
+}
+
 func TestUploadService_UploadProductImage(t *testing.T) {
 	t.Parallel()
 
+	t.Run("stores the original and its variants under its content hash", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodeJPEG(t, 800, 400)
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, testImageLimits)
+		uploaded, err := service.UploadProductImage(context.Background(), formFile(t, "photo.jpg", data))
+		require.NoError(t, err)
+
+		assert.Equal(t, hash, uploaded.ContentHash)
+		assert.Equal(t, "images/"+hash[:2]+"/"+hash+".jpg", uploaded.StoragePath)
+		assert.Equal(t, "memory://"+uploaded.StoragePath, uploaded.URL)
+
+		type variant struct {
+			name, format  string
+			width, height int
+		}
+		var got []variant
+		for _, v := range uploaded.Variants {
+			got = append(got, variant{v.Name, v.Format, v.Width, v.Height})
+			assert.NotEmpty(t, v.URL)
+		}
+		// A lossless WebP of a photo is larger than its JPEG, so none is stored
+		assert.Equal(t, []variant{
+			{"original", "jpeg", 800, 400},
+			{"thumbnail", "jpeg", 150, 75},
+			{"medium", "jpeg", 600, 300},
+			{"large", "jpeg", 800, 400},
+		}, got)
+
+		files, err := provider.List(context.Background(), "images/")
+		require.NoError(t, err)
+		require.Len(t, files, 4)
+	})
+
+	t.Run("stores WebP variants smaller than the PNG", func(t *testing.T) {
+		t.Parallel()
+
+		// Grey noise: PNG stores each channel, WebP's subtract green transform
+		// leaves only one to store
+		img := image.NewNRGBA(image.Rect(0, 0, 800, 400))
+		seed := uint32(1)
+		for i := 0; i < len(img.Pix); i += 4 {
+			seed = seed*1664525 + 1013904223
+			v := uint8(seed >> 24)
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = v, v, v, 0xff
+		}
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+		sum := sha256.Sum256(buf.Bytes())
+		hash := hex.EncodeToString(sum[:])
+
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, testImageLimits)
+		uploaded, err := service.UploadProductImage(context.Background(), formFile(t, "photo.png", buf.Bytes()))
+		require.NoError(t, err)
+
+		var formats []string
+		for _, v := range uploaded.Variants {
+			formats = append(formats, v.Name+"."+v.Format)
+		}
+		assert.Equal(t, []string{
+			"original.png",
+			"thumbnail.png", "thumbnail.webp",
+			"medium.png", "medium.webp",
+			"large.png", "large.webp",
+		}, formats)
+
+		webp := uploaded.Variants[2]
+		assert.Equal(t, "images/"+hash[:2]+"/"+hash+"_thumbnail.webp", webp.StoragePath)
+		decoded, format, err := image.Decode(bytes.NewReader(storedData(t, provider, webp.StoragePath)))
+		require.NoError(t, err)
+		assert.Equal(t, "webp", format)
+		assert.Equal(t, image.Pt(150, 75), decoded.Bounds().Size())
+		info, err := provider.Stat(context.Background(), webp.StoragePath)
+		require.NoError(t, err)
+		assert.Equal(t, "image/webp", info.ContentType)
+		assert.Less(t, len(storedData(t, provider, webp.StoragePath)), len(storedData(t, provider, uploaded.Variants[1].StoragePath)))
+	})
+
+	t.Run("trusts the content over the extension", func(t *testing.T) {
+		t.Parallel()
+
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, testImageLimits)
+		uploaded, err := service.UploadProductImage(context.Background(), formFile(t, "photo.jpg", encodePNG(t, 100, 100)))
+		require.NoError(t, err)
+
+		assert.Regexp(t, `\.png$`, uploaded.StoragePath)
+		assert.Equal(t, "png", uploaded.Variants[0].Format)
+		info, err := provider.Stat(context.Background(), uploaded.StoragePath)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", info.ContentType)
+		// Resized PNGs stay PNG to keep transparency
+		assert.Equal(t, "png", uploaded.Variants[1].Format)
+	})
+
+	t.Run("strips EXIF and turns the image upright", func(t *testing.T) {
+		t.Parallel()
+
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, testImageLimits)
+		data := withOrientation(encodeJPEG(t, 120, 60), 6)
+		uploaded, err := service.UploadProductImage(context.Background(), formFile(t, "photo.jpg", data))
+		require.NoError(t, err)
+
+		original := storedData(t, provider, uploaded.StoragePath)
+		assert.NotContains(t, string(original), "Exif")
+		assert.Equal(t, 1, imaging.JPEGOrientation(original))
+
+		config, _, err := image.DecodeConfig(bytes.NewReader(original))
+		require.NoError(t, err)
+		assert.Equal(t, 60, config.Width)
+		assert.Equal(t, 120, config.Height)
+		assert.Equal(t, 60, uploaded.Variants[0].Width)
+	})
+
+	t.Run("leaves stored files to the collector when an upload fails", func(t *testing.T) {
+		t.Parallel()
+
+		mockProvider := new(MockUploadProvider)
+		mockProvider.On("Put", mock.Anything, mock.MatchedBy(func(path string) bool {
+			return !strings.Contains(path, "_medium")
+		}), mock.Anything, mock.Anything).Return("https://cdn.example.com/image", nil)
+		mockProvider.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("s3 unavailable"))
+
+		service := NewUploadService(unusedContentStore(), mockProvider, testImageLimits)
+		_, err := service.UploadProductImage(context.Background(), formFile(t, "photo.jpg", encodeJPEG(t, 800, 400)))
+		require.Error(t, err)
+
+		// The original and the thumbnails were stored before the failure,
+		// and may be shared with an image uploaded at the same time
+		mockProvider.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUploadService_UploadProductImage_Rejected(t *testing.T) {
+	t.Parallel()
+
 	tests := []struct {
-		name      string
-		productID uint
-		filename  string
-		setupMock func(m *MockUploadProvider)
-		wantErr   bool
-		errMsg    string
+		name     string
+		filename string
+		content  func(t *testing.T) []byte
+		limits   ImageLimits
+		wantErr  error
 	}{
 		{
-			name:      "success - jpg image",
-			productID: 1,
-			filename:  "test.jpg",
-			setupMock: func(m *MockUploadProvider) {
-				m.On("UploadFile", mock.Anything, mock.Anything).Return("https://cdn.example.com/products/1/test.jpg", nil)
-			},
-			wantErr: false,
+			name:     "text with an image extension",
+			filename: "photo.jpg",
+			content:  func(*testing.T) []byte { return []byte("definitely not an image") },
+			wantErr:  ErrUnsupportedImage,
+		},
+		{
+			name:     "pdf",
+			filename: "document.png",
+			content:  func(*testing.T) []byte { return []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n") },
+			wantErr:  ErrUnsupportedImage,
 		},
 		{
-			name:      "success - png image",
-			productID: 1,
-			filename:  "image.png",
-			setupMock: func(m *MockUploadProvider) {
-				m.On("UploadFile", mock.Anything, mock.Anything).Return("https://cdn.example.com/products/1/image.png", nil)
-			},
-			wantErr: false,
+			name:     "truncated image",
+			filename: "photo.jpg",
+			content:  func(t *testing.T) []byte { return encodeJPEG(t, 100, 100)[:40] },
+			wantErr:  ErrUnsupportedImage,
 		},
 		{
-			name:      "success - webp image",
-			productID: 1,
-			filename:  "photo.webp",
-			setupMock: func(m *MockUploadProvider) {
-				m.On("UploadFile", mock.Anything, mock.Anything).Return("https://cdn.example.com/products/1/photo.webp", nil)
-			},
-			wantErr: false,
+			name:     "too small",
+			filename: "icon.png",
+			content:  func(t *testing.T) []byte { return encodePNG(t, 200, 20) },
+			wantErr:  ErrImageDimensions,
 		},
 		{
-			name:      "error - invalid format txt",
-			productID: 1,
-			filename:  "document.txt",
-			setupMock: func(m *MockUploadProvider) {},
-			wantErr:   true,
-			errMsg:    "invalid image format",
+			name:     "too large",
+			filename: "poster.png",
+			content:  func(t *testing.T) []byte { return encodePNG(t, 1001, 100) },
+			wantErr:  ErrImageDimensions,
 		},
 		{
-			name:      "error - invalid format pdf",
-			productID: 1,
-			filename:  "document.pdf",
-			setupMock: func(m *MockUploadProvider) {},
-			wantErr:   true,
-			errMsg:    "invalid image format",
+			name:     "too many bytes",
+			filename: "photo.jpg",
+			content:  func(t *testing.T) []byte { return encodeJPEG(t, 100, 100) },
+			limits:   ImageLimits{MaxBytes: 100, MinDimension: 1, MaxDimension: 1000},
+			wantErr:  ErrImageTooLarge,
 		},
 	}
 
@@ -83,54 +345,86 @@ func TestUploadService_UploadProductImage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockProvider := new(MockUploadProvider)
-			tt.setupMock(mockProvider)
-
-			service := NewUploadService(mockProvider)
-
-			file := &multipart.FileHeader{
-				Filename: tt.filename,
+			limits := tt.limits
+			if limits == (ImageLimits{}) {
+				limits = testImageLimits
 			}
 
-			url, err := service.UploadProductImage(tt.productID, file)
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				if tt.errMsg != "" {
-					assert.Contains(t, err.Error(), tt.errMsg)
-				}
-				return
-			}
+			mockProvider := new(MockUploadProvider)
+			service := NewUploadService(unusedContentStore(), mockProvider, limits)
 
-			assert.NoError(t, err)
-			assert.NotEmpty(t, url)
+			_, err := service.UploadProductImage(context.Background(), formFile(t, tt.filename, tt.content(t)))
+			assert.ErrorIs(t, err, tt.wantErr)
+			mockProvider.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 		})
 	}
 }
 
-func TestIsValidImageFormat(t *testing.T) {
+func TestUploadService_StoreProductImage(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		ext   string
-		valid bool
-	}{
-		{".jpg", true},
-		{".jpeg", true},
-		{".png", true},
-		{".gif", true},
-		{".webp", true},
-		{".txt", false},
-		{".pdf", false},
-		{".bmp", false},
-		{".doc", false},
-	}
+	t.Run("stores an image read from a stream", func(t *testing.T) {
+		t.Parallel()
 
-	for _, tt := range tests {
-		t.Run(tt.ext, func(t *testing.T) {
-			t.Parallel()
-			result := isValidImageFormat(tt.ext)
-			assert.Equal(t, tt.valid, result)
-		})
-	}
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, testImageLimits)
+		uploaded, err := service.StoreProductImage(context.Background(), bytes.NewReader(encodePNG(t, 100, 100)))
+		require.NoError(t, err)
+
+		files, err := provider.List(context.Background(), "images/")
+		require.NoError(t, err)
+		assert.Len(t, files, len(uploaded.Variants))
+	})
+
+	t.Run("reuses the files of an image with the same content", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodePNG(t, 100, 100)
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		base := "images/" + hash[:2] + "/" + hash
+
+		mockStore := new(mocks.MockStore)
+		mockStore.On("ListImageBlobVariants", mock.Anything, hash).Return([]db.ProductImageVariant{
+			{ImageID: 4, Name: "thumbnail", Format: "png", Width: 100, Height: 100, Url: "/uploads/" + base + "_thumbnail.png", StoragePath: base + "_thumbnail.png"},
+			{ImageID: 4, Name: "original", Format: "png", Width: 100, Height: 100, Url: "/uploads/" + base + ".png", StoragePath: base + ".png"},
+		}, nil)
+		mockProvider := new(MockUploadProvider)
+
+		service := NewUploadService(mockStore, mockProvider, testImageLimits)
+		uploaded, err := service.StoreProductImage(context.Background(), bytes.NewReader(data))
+		require.NoError(t, err)
+
+		assert.Equal(t, hash, uploaded.ContentHash)
+		assert.Equal(t, base+".png", uploaded.StoragePath)
+		assert.Equal(t, "/uploads/"+base+".png", uploaded.URL)
+		require.Len(t, uploaded.Variants, 2)
+		assert.Equal(t, base+"_thumbnail.png", uploaded.Variants[0].StoragePath)
+		mockProvider.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a stream over the size limit", func(t *testing.T) {
+		t.Parallel()
+
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, ImageLimits{MaxBytes: 100, MinDimension: 1, MaxDimension: 1000})
+		_, err := service.StoreProductImage(context.Background(), bytes.NewReader(encodeJPEG(t, 100, 100)))
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+
+		files, err := provider.List(context.Background(), "")
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		provider := providers.NewMemoryUploadProvider()
+		service := NewUploadService(unusedContentStore(), provider, testImageLimits)
+		_, err := service.StoreProductImage(ctx, bytes.NewReader(encodePNG(t, 100, 100)))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
 }
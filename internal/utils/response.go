@@ -84,3 +84,28 @@ func PaginatedSuccessResponse(c *gin.Context, message string, data interface{},
 		Meta: meta,
 	})
 }
+
+type SearchResponse struct {
+	PaginatedResponse
+	Facets     interface{} `json:"facets"`
+	DidYouMean string      `json:"did_you_mean,omitempty"`
+	Fuzzy      bool        `json:"fuzzy"`
+	Parsed     interface{} `json:"parsed"`
+}
+
+func SearchSuccessResponse(c *gin.Context, message string, data interface{}, meta PaginationMeta, facets interface{}, didYouMean string, fuzzy bool, parsed interface{}) {
+	c.JSON(http.StatusOK, SearchResponse{
+		PaginatedResponse: PaginatedResponse{
+			Response: Response{
+				Data:    data,
+				Success: true,
+				Message: message,
+			},
+			Meta: meta,
+		},
+		Facets:     facets,
+		DidYouMean: didYouMean,
+		Fuzzy:      fuzzy,
+		Parsed:     parsed,
+	})
+}